@@ -0,0 +1,65 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// admin invite controller
+type AdminInviteController struct {
+	inviteUseCase usecases.AdminInviteUseCase        // invite usecase for creating additional admins
+}
+
+// new admin invite controller
+func NewAdminInviteController(uc usecases.AdminInviteUseCase) *AdminInviteController {
+	return &AdminInviteController{inviteUseCase: uc}        // return new admin invite controller instance
+}
+
+func (inviteContr *AdminInviteController) CreateInvite(c *gin.Context) {
+
+	var req domain.CreateAdminInviteRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create invite through usecase layer
+	invite, plain, err := inviteContr.inviteUseCase.CreateInvite(c.Request.Context(), c.GetString("userID"), req.Email)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	// plain token is only ever shown once
+	c.JSON(http.StatusCreated, gin.H{"token": plain, "details": invite})
+}
+
+func (inviteContr *AdminInviteController) AcceptInvite(c *gin.Context) {
+
+	var req domain.AcceptAdminInviteRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create admin through usecase layer
+	admin := domain.User{Username: req.Username, Password: req.Password, Email: req.Email}
+	if err := inviteContr.inviteUseCase.AcceptInvite(c.Request.Context(), req.Token, &admin); err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrInvalidAdminInvite:
+			apierror.Respond(c, http.StatusForbidden, err)
+		case domain.ErrUserExists:
+			apierror.Respond(c, http.StatusConflict, err)
+		default:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "admin user created"})       // success response
+}
@@ -0,0 +1,74 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// api usage controller
+type APIUsageController struct {
+	usageUseCase usecases.APIUsageUseCase        // api usage usecase for per-token counters
+}
+
+// new api usage controller
+func NewAPIUsageController(uc usecases.APIUsageUseCase) *APIUsageController {
+	return &APIUsageController{usageUseCase: uc}        // return new api usage controller instance
+}
+
+func (usageContr *APIUsageController) TokenUsage(c *gin.Context) {
+
+	// read usage through usecase layer
+	usage, err := usageContr.usageUseCase.GetTokenUsage(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		switch err {
+		case domain.ErrTokenNotFound:
+			apierror.Respond(c, http.StatusNotFound, err)
+		case domain.ErrInvalidTokenID:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		default:
+			apierror.Abort(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)       // calls per endpoint, busiest first
+}
+
+func (usageContr *APIUsageController) UsageRollup(c *gin.Context) {
+
+	// parse ?limit=...
+	var limit int64
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.ParseInt(raw, 10, 64); err != nil || limit <= 0 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	// read rollup through usecase layer
+	rollup, err := usageContr.usageUseCase.GetUsageRollup(c.Request.Context(), limit)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rollup)       // calls per user, token and client, busiest first
+}
+
+func (usageContr *APIUsageController) DeprecatedUsage(c *gin.Context) {
+
+	// read usage through usecase layer
+	report, err := usageContr.usageUseCase.GetDeprecatedUsage(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)       // deprecated endpoints with their callers, most recent first
+}
@@ -0,0 +1,60 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// audit log controller
+type AuditLogController struct {
+	auditLogUseCase usecases.AuditLogUseCase        // audit log usecase for querying changes
+}
+
+// new audit log controller
+func NewAuditLogController(uc usecases.AuditLogUseCase) *AuditLogController {
+	return &AuditLogController{auditLogUseCase: uc}        // return new audit log controller instance
+}
+
+func (auditLogContr *AuditLogController) GetEntries(c *gin.Context) {
+
+	// parse filters from query parameters (?user_id=...&from=...&to=...&limit=...)
+	filter := domain.AuditLogFilter{ActorID: c.Query("user_id")}
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if filter.From, err = time.Parse(time.RFC3339, raw); err != nil {
+			apierror.Message(c, http.StatusBadRequest, "from must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if filter.To, err = time.Parse(time.RFC3339, raw); err != nil {
+			apierror.Message(c, http.StatusBadRequest, "to must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+			return
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if filter.Limit, err = strconv.ParseInt(raw, 10, 64); err != nil || filter.Limit <= 0 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	// query audit log through usecase layer
+	entries, err := auditLogContr.auditLogUseCase.GetEntries(c.Request.Context(), filter)
+	if err != nil {
+		if err == domain.ErrInvalidAuditFilter {
+			apierror.Respond(c, http.StatusBadRequest, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)       // return matching entries, newest first
+}
@@ -0,0 +1,28 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// cache controller
+type CacheController struct {
+	metrics domain.CacheMetrics        // task cache counters (nil when caching is off)
+}
+
+// new cache controller
+func NewCacheController(metrics domain.CacheMetrics) *CacheController {
+	return &CacheController{metrics: metrics}        // return new cache controller instance
+}
+
+func (cacheContr *CacheController) Stats(c *gin.Context) {
+
+	if cacheContr.metrics == nil {
+		c.JSON(http.StatusOK, domain.CacheStats{})       // caching disabled
+		return
+	}
+
+	c.JSON(http.StatusOK, cacheContr.metrics.CacheStats())       // hit and miss counters since startup
+}
@@ -0,0 +1,128 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"strconv";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// checklist controller
+type ChecklistController struct {
+	checklistUseCase usecases.ChecklistUseCase        // checklist usecase for checklist operations
+	workflow         domain.TaskWorkflow              // transitions linked from returned tasks
+}
+
+// new checklist controller
+func NewChecklistController(uc usecases.ChecklistUseCase, workflow domain.TaskWorkflow) *ChecklistController {
+	return &ChecklistController{checklistUseCase: uc, workflow: workflow}        // return new checklist controller instance
+}
+
+func (checklistContr *ChecklistController) AddItem(c *gin.Context) {
+
+	var input domain.ChecklistItemInput
+	if !bindJSON(c, &input) {       // parse and validate request body
+		return
+	}
+
+	// add item through usecase layer
+	task, err := checklistContr.checklistUseCase.AddChecklistItem(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	checklistContr.respond(c, http.StatusCreated, task)       // return task with the new item
+}
+
+func (checklistContr *ChecklistController) ToggleItem(c *gin.Context) {
+
+	index, ok := checklistIndex(c)
+	if !ok {
+		return
+	}
+
+	// toggle item through usecase layer
+	task, err := checklistContr.checklistUseCase.ToggleChecklistItem(c.Request.Context(), c.Param("id"), index)
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	checklistContr.respond(c, http.StatusOK, task)       // return task with its progress
+}
+
+func (checklistContr *ChecklistController) ReorderItems(c *gin.Context) {
+
+	var req domain.ChecklistOrderRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// reorder items through usecase layer
+	task, err := checklistContr.checklistUseCase.ReorderChecklist(c.Request.Context(), c.Param("id"), req.Order)
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	checklistContr.respond(c, http.StatusOK, task)       // return task with the new order
+}
+
+func (checklistContr *ChecklistController) DeleteItem(c *gin.Context) {
+
+	index, ok := checklistIndex(c)
+	if !ok {
+		return
+	}
+
+	// delete item through usecase layer
+	task, err := checklistContr.checklistUseCase.DeleteChecklistItem(c.Request.Context(), c.Param("id"), index)
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	checklistContr.respond(c, http.StatusOK, task)       // return task without the item
+}
+
+// answer with the changed task and its version
+func (checklistContr *ChecklistController) respond(c *gin.Context, status int, task *domain.Task) {
+	c.Header("ETag", taskETag(task))
+	c.JSON(status, taskResource(c, *task, checklistContr.workflow))
+}
+
+// item index from the path (false after answering a malformed one)
+func checklistIndex(c *gin.Context) (int, bool) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		apierror.Message(c, http.StatusBadRequest, "checklist item index must be a number from 0")
+		return 0, false
+	}
+	return index, true
+}
+
+// map checklist errors to responses
+func checklistError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch {
+	case err == domain.ErrTaskNotFound, err == domain.ErrChecklistItemNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case err == domain.ErrInvalidTaskID, err == domain.ErrInvalidChecklistOrder:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case err == domain.ErrProjectAccessDenied:
+		apierror.Respond(c, http.StatusForbidden, err)
+	case errors.Is(err, domain.ErrTaskLocked):
+		apierror.Respond(c, http.StatusLocked, err)
+	case err == domain.ErrTaskModified:
+		apierror.Respond(c, http.StatusPreconditionFailed, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -2,10 +2,17 @@ package controllers
 
 // imports
 import (
+	"crypto/sha256";
+	"encoding/hex";
+	"errors";
 	"net/http";
+	"strconv";
 	"strings";
+	"time";
 	"github.com/gin-gonic/gin";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
 	"go.mongodb.org/mongo-driver/bson/primitive";
 )
@@ -13,6 +20,7 @@ import (
 // task controller
 type TaskController struct {
 	taskUseCase usecases.TaskUseCase        // task usecase for task operations
+	workflow    domain.TaskWorkflow         // status changes offered as transition links
 }
 
 // user controller
@@ -21,8 +29,8 @@ type UserController struct {
 }
 
 // new task controller
-func NewTaskController(uc usecases.TaskUseCase) *TaskController {
-	return &TaskController{taskUseCase: uc}        // return new task controller instance
+func NewTaskController(uc usecases.TaskUseCase, workflow domain.TaskWorkflow) *TaskController {
+	return &TaskController{taskUseCase: uc, workflow: workflow}        // return new task controller instance
 }
 
 // new user controller
@@ -32,31 +40,30 @@ func NewUserController(uc usecases.UserUseCase) *UserController {
 
 func (taskContr *TaskController) CreateTask(c *gin.Context) {
 	
-	var task domain.Task
-	err := c.ShouldBindJSON(&task)      // parse request body into task struct
-	if err != nil {
-		// handle specific date format error case
-		if strings.Contains(err.Error(), "numeric literal") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid date format. Use ISO 8601 format like '2023-12-31T00:00:00Z'",
-				"example": gin.H{
-					"due_date": "2025-07-22T00:00:00Z",
-				},
-			})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req domain.CreateTaskRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
 		return
 	}
 
 	// create task through usecase layer
-	createdTask, err := taskContr.taskUseCase.CreateTask(&task)
+	createdTask, created, err := taskContr.taskUseCase.CreateTask(c.Request.Context(), req.ToTask())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if validationFailed(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrRejectedByExtension) || err == domain.ErrProjectAccessDenied {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdTask)        // return created task with 201 status
+	if !created {
+		c.JSON(http.StatusOK, taskResource(c, *createdTask, taskContr.workflow))        // retried client id, return the task created first
+		return
+	}
+	c.JSON(http.StatusCreated, taskResource(c, *createdTask, taskContr.workflow))        // return created task with 201 status
 }
 
 func (taskContr *TaskController) DeleteTask(c *gin.Context) {
@@ -65,18 +72,28 @@ func (taskContr *TaskController) DeleteTask(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		apierror.Message(c, http.StatusBadRequest, "Invalid task ID format")
 		return
 	}
 
+	cascade := c.Query("cascade") == "true"       // also delete subtasks
+
 	// delete task through usecase layer
-	err = taskContr.taskUseCase.DeleteTask(id)
+	err = taskContr.taskUseCase.DeleteTask(c.Request.Context(), id, cascade)
 	if err != nil {
 		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrTaskHasSubtasks {
+			apierror.Respond(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err == domain.ErrProjectAccessDenied {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		apierror.Abort(c, err)
 		return
 	}
 
@@ -85,19 +102,141 @@ func (taskContr *TaskController) DeleteTask(c *gin.Context) {
 
 func (taskContr *TaskController) GetAllTasks(c *gin.Context) {
 	
+	query, ok := parseTaskQuery(c)
+	if !ok {
+		return
+	}
+
 	// get all tasks through usecase layer
-	tasks, err := taskContr.taskUseCase.GetAllTasks()
+	tasks, err := taskContr.taskUseCase.GetAllTasks(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch err {
+		case domain.ErrInvalidSortField:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		case domain.ErrProjectNotFound:
+			apierror.Respond(c, http.StatusNotFound, err)
+		case domain.ErrProjectAccessDenied:
+			apierror.Respond(c, http.StatusForbidden, err)
+		case domain.ErrPartialResult:
+			// return what was read in time so clients can narrow the query or retry
+			apierror.RespondWith(c, http.StatusGatewayTimeout, err, gin.H{
+				"partial":  true,
+				"count":    len(tasks),
+				"tasks":    taskResources(c, tasks, taskContr.workflow),
+				"hint":     "results are incomplete, narrow the query or retry",
+			})
+		default:
+			apierror.Abort(c, err)
+		}
+		return
+	}
+
+	// polling clients get an empty answer while nothing in the list changed
+	if notModified(c, taskListETag(tasks), time.Time{}) {
 		return
 	}
 
-	if len(tasks) == 0 {
-		c.JSON(http.StatusOK, []domain.Task{})
+	c.JSON(http.StatusOK, taskResources(c, tasks, taskContr.workflow))       // return all tasks
+}
+
+func (taskContr *TaskController) SearchTasks(c *gin.Context) {
+
+	// parse words, language and page size (e.g. ?q=quarterly report&language=en&limit=10)
+	query := domain.TaskSearchQuery{Text: c.Query("q"), Language: c.Query("language")}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		query.Limit = limit
+	}
+
+	// search tasks through usecase layer
+	results, err := taskContr.taskUseCase.SearchTasks(c.Request.Context(), query)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, tasks)       // return all tasks
+	resources := make([]TaskSearchResultResource, 0, len(results))
+	for _, result := range results {
+		resources = append(resources, TaskSearchResultResource{Task: taskResource(c, result.Task, taskContr.workflow), Score: result.Score, Highlights: result.Highlights})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": resources})       // most relevant tasks first
+}
+
+// task list filters shared by listing and export (false when an error response was sent)
+func parseTaskQuery(c *gin.Context) (domain.TaskQuery, bool) {
+
+	// parse query options (e.g. ?sort=priority,-due_date&overdue=true)
+	query := domain.TaskQuery{Sort: parseSort(c.Query("sort"))}
+	if raw := c.Query("overdue"); raw != "" {
+		overdue, err := strconv.ParseBool(raw)
+		if err != nil {
+			apierror.Message(c, http.StatusBadRequest, "overdue must be true or false")
+			return query, false
+		}
+		query.Overdue = &overdue
+	}
+	// label filter (e.g. ?labels=bug,backend&labels_match=all), any label matches by default
+	for _, label := range strings.Split(c.Query("labels"), ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			query.Labels = append(query.Labels, label)
+		}
+	}
+	switch c.DefaultQuery("labels_match", "any") {
+	case "any":
+	case "all":
+		query.MatchAllLabels = true
+	default:
+		apierror.Message(c, http.StatusBadRequest, "labels_match must be any or all")
+		return query, false
+	}
+	// project filter (e.g. ?project_id=...), members only
+	if raw := c.Query("project_id"); raw != "" {
+		projectID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, domain.ErrInvalidProjectID)
+			return query, false
+		}
+		query.ProjectID = &projectID
+	}
+	// changes since the last sync (e.g. ?updated_since=2025-01-02T15:04:05Z)
+	if raw := c.Query("updated_since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			apierror.Message(c, http.StatusBadRequest, "updated_since must be an RFC3339 time")
+			return query, false
+		}
+		query.UpdatedSince = &since
+	}
+	// language filter and search (e.g. ?q=rapport mensuel&language=fr), words are stemmed in the language given
+	query.Search = strings.TrimSpace(c.Query("q"))
+	if query.Language = c.Query("language"); query.Language != "" && !domain.IsTaskLanguage(query.Language) {
+		apierror.Respond(c, http.StatusBadRequest, domain.ErrInvalidTaskLanguage)
+		return query, false
+	}
+
+	return query, true
+}
+
+// parse comma separated sort fields, a leading "-" means descending
+func parseSort(raw string) []domain.SortField {
+	var fields []domain.SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := domain.SortField{Field: strings.TrimPrefix(part, "-"), Descending: strings.HasPrefix(part, "-")}
+		fields = append(fields, field)
+	}
+	return fields
 }
 
 func (taskContr *TaskController) GetTaskByID(c *gin.Context) {
@@ -106,22 +245,131 @@ func (taskContr *TaskController) GetTaskByID(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)      // validate it is a valid ObjectID
 	if err != nil {      
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		apierror.Message(c, http.StatusBadRequest, "Invalid task ID format")
 		return
 	}
 
 	// get specific task through usecase layer
-	task, err := taskContr.taskUseCase.GetTaskByID(id)
+	task, err := taskContr.taskUseCase.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
 		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apierror.Respond(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
+		return
+	}
+
+	// unchanged since the client's copy
+	if notModified(c, taskETag(task), task.UpdatedAt) {
 		return
 	}
 
-	c.JSON(http.StatusOK, task)       // return found task 
+	c.JSON(http.StatusOK, taskResource(c, *task, taskContr.workflow))       // return found task 
+}
+
+// handle fetching many tasks by id in one request
+func (taskContr *TaskController) BatchGetTasks(c *gin.Context) {
+
+	var input domain.TaskBatchInput
+	if !bindJSON(c, &input) {       // bind and validate incoming json
+		return
+	}
+
+	// get tasks through usecase layer, unknown ids don't fail the request
+	batch, err := taskContr.taskUseCase.GetTasksByIDs(c.Request.Context(), input.IDs)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TaskBatchResource{Tasks: taskResources(c, batch.Tasks, taskContr.workflow), NotFound: batch.NotFound})
+}
+
+// version tag of a task, changes whenever the task is stored
+func taskETag(task *domain.Task) string {
+	return `"` + strconv.FormatInt(task.UpdatedAt.UnixMilli(), 36) + `"`
+}
+
+// last change time of the version a client holds (nil when any version may be changed)
+func parseTaskETag(raw string) (*time.Time, bool) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "W/")
+	if raw == "" || raw == "*" {
+		return nil, true
+	}
+	millis, err := strconv.ParseInt(strings.Trim(raw, `"`), 36, 64)
+	if err != nil {
+		return nil, false
+	}
+	updatedAt := time.UnixMilli(millis).UTC()
+	return &updatedAt, true
+}
+
+// version tag of a task list, a hash of the tasks' versions (changes when a task is added, removed or stored)
+func taskListETag(tasks []domain.Task) string {
+	hash := sha256.New()
+	for _, task := range tasks {
+		hash.Write([]byte(task.ID.Hex() + ":" + strconv.FormatInt(task.UpdatedAt.UnixMilli(), 36) + ";"))
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:12]) + `"`
+}
+
+// set the validators of a read and answer 304 when the client's copy is still current
+// If-None-Match wins over If-Modified-Since, lists send no Last-Modified (removed tasks would not move it)
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	current := false
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")        // weak comparison (compressed copies are the same version)
+			if tag == "*" || tag == etag {
+				current = true
+				break
+			}
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		current = !lastModified.Truncate(time.Second).After(since)        // header times have whole seconds
+	}
+	if current {
+		c.Status(http.StatusNotModified)
+	}
+	return current
+}
+
+func (taskContr *TaskController) GetSubtasks(c *gin.Context) {
+	
+	id := c.Param("id")        // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)      // validate it is a valid ObjectID
+	if err != nil {      
+		apierror.Message(c, http.StatusBadRequest, "Invalid task ID format")
+		return
+	}
+
+	// get subtasks through usecase layer
+	subtasks, err := taskContr.taskUseCase.GetSubtasks(c.Request.Context(), id)
+	if err != nil {
+		if err == domain.ErrTaskNotFound {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	if notModified(c, taskListETag(subtasks), time.Time{}) {
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResources(c, subtasks, taskContr.workflow))       // return subtasks
 }
 
 func (taskContr *TaskController) UpdateTask(c *gin.Context) {
@@ -130,57 +378,83 @@ func (taskContr *TaskController) UpdateTask(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)        // validate it is a valid ObjectID
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		apierror.Message(c, http.StatusBadRequest, "Invalid task ID format")
 		return
 	}
 
 	var task domain.Task
-	err = c.ShouldBindJSON(&task)       // parse request body into task struct
-	if err != nil {
-		// handle specific date format error case
-		if strings.Contains(err.Error(), "numeric literal") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid date format. Use ISO 8601 format like '2025-7-16T00:00:00Z'",
-				"example": gin.H{
-					"due_date": "2025-07-22T00:00:00Z",
-				},
-			})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &task) {       // parse and validate request body
+		return
+	}
+	// only change the version the client read (e.g. If-Match: "m1abc2de")
+	expected, ok := parseTaskETag(c.GetHeader("If-Match"))
+	if !ok {
+		apierror.Message(c, http.StatusBadRequest, "If-Match must be an ETag returned for the task")
 		return
 	}
+	task.ExpectedUpdatedAt = expected
 
 	// update task through usecase layer
-	updatedTask, err := taskContr.taskUseCase.UpdateTask(id, &task)
+	updatedTask, changes, err := taskContr.taskUseCase.UpdateTask(c.Request.Context(), id, domain.NewTaskUpdate(&task))
 	if err != nil {
-		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})       
+		taskUpdateError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{ "message":"task updated successfully", "updated_task":updatedTask})       // success response
+	c.Header("ETag", taskETag(updatedTask))
+	c.JSON(http.StatusOK, gin.H{ "message":"task updated successfully", "updated_task":taskResource(c, *updatedTask, taskContr.workflow), "changes":changes})       // success response
+}
+
+// map task update errors to responses (PUT and PATCH)
+func taskUpdateError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	if err == domain.ErrTaskNotFound {
+		apierror.Respond(c, http.StatusNotFound, err)
+		return
+	}
+	if err == domain.ErrProjectAccessDenied {
+		apierror.Respond(c, http.StatusForbidden, err)
+		return
+	}
+	if errors.Is(err, domain.ErrTaskLocked) {
+		apierror.Respond(c, http.StatusLocked, err)
+		return
+	}
+	if errors.Is(err, domain.ErrTaskBlocked) {
+		apierror.Respond(c, http.StatusConflict, err)
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		apierror.Respond(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err == domain.ErrTaskModified {
+		apierror.Respond(c, http.StatusPreconditionFailed, err)
+		return
+	}
+	apierror.Respond(c, http.StatusBadRequest, err)
 }
 
 func (uc *UserController) Register(c *gin.Context) {
 	
-	var user domain.User
-	err := c.ShouldBindJSON(&user)       // parse request body into user struct
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req domain.RegisterRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
 		return
 	}
 
 	// create user through usecase layer
-	if err := uc.userUseCase.Register(&user); err != nil {
-		if err == domain.ErrUserExists {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	user := domain.User{Username: req.Username, Password: req.Password, Email: req.Email}
+	if err := uc.userUseCase.Register(c.Request.Context(), &user); err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		if err == domain.ErrUserExists || err == domain.ErrSetupRequired {
+			apierror.Respond(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -190,20 +464,33 @@ func (uc *UserController) Register(c *gin.Context) {
 func (uc *UserController) Login(c *gin.Context) {
 	
 	var creds domain.Credentials
-	err := c.ShouldBindJSON(&creds)        // parse request body into user struct
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &creds) {       // parse and validate request body
 		return
 	}
 
 	// authenticate user through usecase layer
-	token, user, err := uc.userUseCase.Login(&creds)
+	token, user, err := uc.userUseCase.Login(c.Request.Context(), &creds)
 	if err != nil {
+		if twoFactorRequired(c, err) {       // password was right, the code comes next
+			return
+		}
 		if err == domain.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			apierror.Respond(c, http.StatusUnauthorized, err)
+			return
+		}
+		if err == domain.ErrAccountLocked {
+			apierror.Respond(c, http.StatusLocked, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err == domain.ErrAccountDeactivated || err == domain.ErrEmailNotVerified {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, domain.ErrRejectedByExtension) {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		apierror.Abort(c, err)
 		return
 	}
 
@@ -224,20 +511,263 @@ func (uc *UserController) PromoteToAdmin(c *gin.Context) {
 	 
 	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		apierror.Message(c, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	// promote user through usecase layer
-	err = uc.userUseCase.PromoteToAdmin(userID) 
+	err = uc.userUseCase.PromoteToAdmin(c.Request.Context(), userID) 
 	if err != nil {
 		if err == domain.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apierror.Respond(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "user promoted to admin successfully"})       // success response
-}
\ No newline at end of file
+}
+
+func (uc *UserController) UnlockUser(c *gin.Context) {
+	
+	userID := c.Param("id")       // get user id from request parameter
+	 
+	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
+	if err != nil {
+		apierror.Message(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	// unlock user through usecase layer
+	err = uc.userUseCase.UnlockUser(c.Request.Context(), userID) 
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked successfully"})       // success response
+}
+
+func (uc *UserController) ListUsers(c *gin.Context) {
+
+	// parse filters, order and page (e.g. ?q=ali&role=admin&status=active&sort=-created_at&page=2&limit=50)
+	query := domain.UserQuery{Search: strings.TrimSpace(c.Query("q")), Role: c.Query("role"), Status: c.Query("status")}
+	if order := parseSort(c.Query("sort")); len(order) > 1 {
+		apierror.Message(c, http.StatusBadRequest, "sort takes one field")
+		return
+	} else if len(order) == 1 {
+		query.Sort = order[0]
+	}
+	for name, target := range map[string]*int{"page": &query.Page, "limit": &query.Limit} {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 1 {
+				apierror.Message(c, http.StatusBadRequest, name + " must be a positive number")
+				return
+			}
+			*target = value
+		}
+	}
+
+	// list users through usecase layer
+	page, err := uc.userUseCase.ListUsers(c.Request.Context(), query)
+	if err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)       // return page of users
+}
+
+func (uc *UserController) GetUser(c *gin.Context) {
+
+	// get user through usecase layer
+	user, err := uc.userUseCase.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)       // return account and project memberships
+}
+
+func (uc *UserController) DeactivateUser(c *gin.Context) {
+
+	// deactivate user through usecase layer
+	if err := uc.userUseCase.DeactivateUser(c.Request.Context(), c.Param("id")); err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deactivated successfully"})       // success response
+}
+
+func (uc *UserController) ReactivateUser(c *gin.Context) {
+
+	// reactivate user through usecase layer
+	if err := uc.userUseCase.ReactivateUser(c.Request.Context(), c.Param("id")); err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user reactivated successfully"})       // success response
+}
+
+func (uc *UserController) DemoteAdmin(c *gin.Context) {
+
+	// demote admin through usecase layer
+	if err := uc.userUseCase.DemoteAdmin(c.Request.Context(), c.Param("id")); err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "admin demoted to user successfully"})       // success response
+}
+
+func (uc *UserController) DeleteUser(c *gin.Context) {
+
+	// delete user through usecase layer (?reassign_to=<user id> keeps the projects they own alone)
+	deletion, err := uc.userUseCase.DeleteUser(c.Request.Context(), c.Param("id"), c.Query("reassign_to"))
+	if err != nil {
+		userAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully", "deletion": deletion})       // success response with what happened to their projects
+}
+
+// map user management errors to responses
+func userAdminError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrUserNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrLastAdmin, domain.ErrUserNotAdmin:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrManageSelf:
+		apierror.Respond(c, http.StatusForbidden, err)
+	case domain.ErrInvalidUserID, domain.ErrInvalidUserQuery:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	default:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	}
+}
+
+func (uc *UserController) GetProfile(c *gin.Context) {
+
+	// get own profile through usecase layer
+	user, err := uc.userUseCase.GetProfile(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profileResponse(c, user))       // return profile (excluding sensitive data)
+}
+
+func (uc *UserController) UpdateProfile(c *gin.Context) {
+
+	var req domain.UpdateProfileRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// update own profile through usecase layer
+	user, err := uc.userUseCase.UpdateProfile(c.Request.Context(), c.GetString("userID"), &req)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		if err == domain.ErrUserNotFound {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrUserExists {
+			apierror.Respond(c, http.StatusConflict, err)
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profileResponse(c, user))       // return updated profile
+}
+
+func (uc *UserController) ChangePassword(c *gin.Context) {
+
+	var req domain.ChangePasswordRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// change own password through usecase layer
+	err := uc.userUseCase.ChangePassword(c.Request.Context(), c.GetString("userID"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrWrongPassword:
+			apierror.Respond(c, http.StatusForbidden, err)
+		case domain.ErrUserNotFound:
+			apierror.Respond(c, http.StatusNotFound, err)
+		default:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password changed successfully"})       // success response
+}
+
+// profile fields returned to the user
+func profileResponse(c *gin.Context, user *domain.User) gin.H {
+	profile := gin.H{
+		"id":           user.ID,
+		"username":     user.Username,
+		"email":        user.Email,
+		"display_name": user.DisplayName,
+		"role":         user.Role,
+		"created_at":   user.CreatedAt,
+		"updated_at":   user.UpdatedAt,
+		"_links":       profileLinks(c),
+	}
+	if user.EmailVerificationPending {
+		profile["email_verification_pending"] = true
+	}
+	if user.EmailVerifiedAt != nil {
+		profile["email_verified_at"] = user.EmailVerifiedAt
+	}
+	if user.TwoFactor.IsEnabled() {
+		profile["two_factor_enabled"] = true
+	}
+	return profile
+}
+
+// respond with the field violations when a usecase rejected the data (false for other errors)
+func validationFailed(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	apierror.RespondWith(c, http.StatusUnprocessableEntity, domain.ErrValidation, gin.H{"errors": validationErr.Violations})
+	return true
+}
+
+// bind json body and respond with field level errors on failure
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	err := c.ShouldBindJSON(obj)
+	if err != nil {
+		apierror.Invalid(c, infrastructure.BindingErrors(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// day plan controller
+type DayPlanController struct {
+	dayPlanUseCase usecases.DayPlanUseCase        // day plan usecase for planning and my day
+	workflow       domain.TaskWorkflow            // status changes offered as transition links
+}
+
+// new day plan controller
+func NewDayPlanController(uc usecases.DayPlanUseCase, workflow domain.TaskWorkflow) *DayPlanController {
+	return &DayPlanController{dayPlanUseCase: uc, workflow: workflow}        // return new day plan controller instance
+}
+
+func (dayPlanContr *DayPlanController) PlanDay(c *gin.Context) {
+
+	// body is optional, server defaults apply without one
+	var req domain.PlanDayRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	// plan through usecase layer
+	plan, err := dayPlanContr.dayPlanUseCase.PlanDay(c.Request.Context(), req)
+	if err != nil {
+		dayPlanError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)       // proposal only, accept it with PUT /me/day
+}
+
+func (dayPlanContr *DayPlanController) SetMyDay(c *gin.Context) {
+
+	var req domain.SetMyDayRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// save order through usecase layer
+	day, err := dayPlanContr.dayPlanUseCase.SetMyDay(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		dayPlanError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dayPlanContr.myDayResponse(c, day))       // today's tasks in the chosen order
+}
+
+func (dayPlanContr *DayPlanController) GetMyDay(c *gin.Context) {
+
+	// get order through usecase layer
+	day, err := dayPlanContr.dayPlanUseCase.GetMyDay(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		dayPlanError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dayPlanContr.myDayResponse(c, day))       // today's tasks in the chosen order
+}
+
+// my day with task links
+func (dayPlanContr *DayPlanController) myDayResponse(c *gin.Context, day *domain.MyDayTasks) gin.H {
+	return gin.H{
+		"date":      day.Date,
+		"timezone":  day.Timezone,
+		"tasks":     taskResources(c, day.Tasks, dayPlanContr.workflow),
+	}
+}
+
+// map day plan errors to status codes
+func dayPlanError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrInvalidWorkingHours, domain.ErrInvalidTimezone, domain.ErrDuplicateDayTask, domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,40 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// due date controller
+type DueDateController struct {
+	dueDateUseCase usecases.DueDateUseCase        // due date usecase for suggestions
+}
+
+// new due date controller
+func NewDueDateController(uc usecases.DueDateUseCase) *DueDateController {
+	return &DueDateController{dueDateUseCase: uc}        // return new due date controller instance
+}
+
+func (dueDateContr *DueDateController) SuggestDueDate(c *gin.Context) {
+
+	title := c.Query("title")
+	if title == "" {
+		apierror.Message(c, http.StatusBadRequest, "title is required")
+		return
+	}
+
+	// suggest through usecase layer
+	suggestion, err := dueDateContr.dueDateUseCase.SuggestDueDate(c.Request.Context(), c.GetString("userID"), title)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)       // due date with confidence and reasons
+}
@@ -0,0 +1,54 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// email verification controller
+type EmailVerificationController struct {
+	verificationUseCase usecases.EmailVerificationUseCase        // email verification usecase for verify/resend flows
+}
+
+// new email verification controller
+func NewEmailVerificationController(uc usecases.EmailVerificationUseCase) *EmailVerificationController {
+	return &EmailVerificationController{verificationUseCase: uc}        // return new email verification controller instance
+}
+
+func (verifyContr *EmailVerificationController) VerifyEmail(c *gin.Context) {
+
+	token := c.Query("token")        // get token from the emailed link
+
+	// verify email through usecase layer
+	if err := verifyContr.verificationUseCase.VerifyEmail(c.Request.Context(), token); err != nil {
+		if err == domain.ErrInvalidVerificationToken {
+			apierror.Respond(c, http.StatusBadRequest, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified successfully"})       // success response
+}
+
+func (verifyContr *EmailVerificationController) ResendVerification(c *gin.Context) {
+
+	var req domain.ResendVerificationRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// request verification email through usecase layer
+	if err := verifyContr.verificationUseCase.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		apierror.Message(c, http.StatusInternalServerError, "could not send verification email")
+		return
+	}
+
+	// same answer whether the address is known, verified or not
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email belongs to an unverified account, a verification link has been sent"})
+}
@@ -0,0 +1,56 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// export controller
+type ExportController struct {
+	exportUseCase usecases.ExportUseCase        // export usecase for task downloads
+}
+
+// new export controller
+func NewExportController(uc usecases.ExportUseCase) *ExportController {
+	return &ExportController{exportUseCase: uc}        // return new export controller instance
+}
+
+func (exportContr *ExportController) ExportTasks(c *gin.Context) {
+
+	// same filters as the task list (e.g. ?format=xlsx&labels=bug&sort=-due_date)
+	query, ok := parseTaskQuery(c)
+	if !ok {
+		return
+	}
+	writer := newExportWriter(c, c.DefaultQuery("format", domain.ExportFormatCSV), "tasks")
+	if writer == nil {
+		apierror.Message(c, http.StatusBadRequest, "format must be csv or xlsx")
+		return
+	}
+
+	// stream rows through usecase layer
+	err := exportContr.exportUseCase.ExportTasks(c.Request.Context(), query, writer)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if writer.Started() {
+			c.Error(err)        // file is cut short, the status is already sent
+			return
+		}
+		switch err {
+		case domain.ErrInvalidSortField:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		case domain.ErrProjectNotFound:
+			apierror.Respond(c, http.StatusNotFound, err)
+		case domain.ErrProjectAccessDenied:
+			apierror.Respond(c, http.StatusForbidden, err)
+		default:
+			apierror.Abort(c, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package controllers
+
+// imports
+import (
+	"archive/zip";
+	"encoding/csv";
+	"encoding/xml";
+	"fmt";
+	"io";
+	"net/http";
+	"strconv";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const exportFlushEvery = 200        // rows written between flushes to the client
+
+// row writer that streams a downloadable file to the client
+type exportWriter interface {
+	domain.RowWriter
+	Started() bool        // response headers sent (errors can't be answered with json anymore)
+	Close() error         // finish the file
+}
+
+// streaming writer for an export format (nil when unsupported)
+func newExportWriter(c *gin.Context, format string, name string) exportWriter {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format)
+	switch format {
+	case domain.ExportFormatCSV:
+		return &csvRowWriter{exportStream: exportStream{c: c, contentType: "text/csv; charset=utf-8", filename: filename}}
+	case domain.ExportFormatXLSX:
+		return &xlsxRowWriter{exportStream: exportStream{c: c, contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", filename: filename}}
+	}
+	return nil
+}
+
+// download response whose headers are sent with the first row
+type exportStream struct {
+	c            *gin.Context
+	contentType  string
+	filename     string
+	started      bool
+	rows         int
+}
+
+func (stream *exportStream) Started() bool {
+	return stream.started
+}
+
+// send headers once
+func (stream *exportStream) begin() {
+	if stream.started {
+		return
+	}
+	stream.started = true
+	stream.c.Header("Content-Type", stream.contentType)
+	stream.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", stream.filename))
+	stream.c.Status(http.StatusOK)
+}
+
+// count row and tell whether buffered data should go out now
+func (stream *exportStream) rowDone() bool {
+	stream.rows++
+	return stream.rows%exportFlushEvery == 0
+}
+
+// csv export (cells that spreadsheets would run as formulas are prefixed with ')
+type csvRowWriter struct {
+	exportStream
+	csv *csv.Writer
+}
+
+func (writer *csvRowWriter) WriteRow(cells []string) error {
+
+	if writer.csv == nil {
+		writer.begin()
+		writer.csv = csv.NewWriter(writer.c.Writer)
+	}
+
+	safe := make([]string, len(cells))
+	for i, cell := range cells {
+		safe[i] = csvSafe(cell)
+	}
+	if err := writer.csv.Write(safe); err != nil {
+		return err
+	}
+
+	if writer.rowDone() {
+		writer.csv.Flush()
+		writer.c.Writer.Flush()
+	}
+	return writer.csv.Error()
+}
+
+func (writer *csvRowWriter) Close() error {
+	if writer.csv == nil {
+		return nil
+	}
+	writer.csv.Flush()
+	return writer.csv.Error()
+}
+
+// guard against formula injection when the file is opened in a spreadsheet
+func csvSafe(cell string) string {
+	if cell != "" && (cell[0] == '=' || cell[0] == '+' || cell[0] == '-' || cell[0] == '@' || cell[0] == '\t' || cell[0] == '\r') {
+		return "'" + cell
+	}
+	return cell
+}
+
+// fixed parts of a single sheet workbook
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Tasks" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// xlsx export (zip of spreadsheetml parts, the sheet is written row by row with inline strings)
+type xlsxRowWriter struct {
+	exportStream
+	zip    *zip.Writer
+	sheet  io.Writer
+}
+
+func (writer *xlsxRowWriter) WriteRow(cells []string) error {
+
+	if writer.sheet == nil {
+		if err := writer.open(); err != nil {
+			return err
+		}
+	}
+
+	row := strconv.Itoa(writer.rows + 1)
+	if _, err := io.WriteString(writer.sheet, `<row r="`+row+`">`); err != nil {
+		return err
+	}
+	for i, cell := range cells {
+		if _, err := io.WriteString(writer.sheet, `<c r="`+xlsxColumn(i)+row+`" t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(writer.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(writer.sheet, `</row>`); err != nil {
+		return err
+	}
+
+	if writer.rowDone() {
+		if err := writer.zip.Flush(); err != nil {
+			return err
+		}
+		writer.c.Writer.Flush()
+	}
+	return nil
+}
+
+// send headers, fixed parts and the start of the sheet
+func (writer *xlsxRowWriter) open() error {
+
+	writer.begin()
+	writer.zip = zip.NewWriter(writer.c.Writer)
+	for _, part := range xlsxParts {
+		entry, err := writer.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := writer.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	writer.sheet = sheet
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+func (writer *xlsxRowWriter) Close() error {
+	if writer.sheet == nil {
+		return nil
+	}
+	if _, err := io.WriteString(writer.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return writer.zip.Close()
+}
+
+// spreadsheet column name of a zero based index (A, B, ..., Z, AA, ...)
+func xlsxColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
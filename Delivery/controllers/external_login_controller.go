@@ -0,0 +1,142 @@
+package controllers
+
+// imports
+import (
+	"crypto/rand";
+	"crypto/subtle";
+	"encoding/hex";
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// cookie holding the state of a sign-in in progress (one per provider)
+const oauthStateCookie = "oauth_state_"
+
+// how long a started sign-in can take, in seconds
+const oauthStateMaxAge = 600
+
+// external login controller
+type ExternalLoginController struct {
+	userUseCase   usecases.UserUseCase        // user usecase linking and signing in users
+	oauthService  domain.OAuthService         // google and github oauth client
+	readOnly      bool                        // sign-ins may create users, not on read-only instances
+}
+
+// new external login controller
+func NewExternalLoginController(uc usecases.UserUseCase, oauthService domain.OAuthService, readOnly bool) *ExternalLoginController {
+	return &ExternalLoginController{userUseCase: uc, oauthService: oauthService, readOnly: readOnly}        // return new external login controller instance
+}
+
+func (loginContr *ExternalLoginController) StartLogin(c *gin.Context) {
+
+	if loginContr.readOnly {
+		apierror.Message(c, http.StatusServiceUnavailable, "instance is in read-only mode, writes are disabled")
+		return
+	}
+
+	// random state ties the callback to this browser
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	state := hex.EncodeToString(bytes)
+
+	provider := c.Param("provider")
+	authURL, err := loginContr.oauthService.AuthCodeURL(provider, state)
+	if err != nil {
+		if err == domain.ErrUnknownProvider {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	// lax, the cookie has to come along when the provider redirects back
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie+provider, state, oauthStateMaxAge, "/", "", isHTTPS(c), true)
+	c.Redirect(http.StatusFound, authURL)        // send the user to the provider's sign-in page
+}
+
+func (loginContr *ExternalLoginController) Callback(c *gin.Context) {
+
+	if loginContr.readOnly {
+		apierror.Message(c, http.StatusServiceUnavailable, "instance is in read-only mode, writes are disabled")
+		return
+	}
+
+	// the state is single use
+	provider := c.Param("provider")
+	expected, _ := c.Cookie(oauthStateCookie + provider)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie+provider, "", -1, "/", "", isHTTPS(c), true)
+
+	// the user cancelled or the provider refused
+	if reason := c.Query("error"); reason != "" {
+		apierror.Message(c, http.StatusUnauthorized, "sign-in not completed at the provider: " + reason)
+		return
+	}
+	state := c.Query("state")
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(state)) != 1 {
+		apierror.Respond(c, http.StatusBadRequest, domain.ErrInvalidOAuthState)
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		apierror.Message(c, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	// verify the identity with the provider
+	identity, err := loginContr.oauthService.Exchange(c.Request.Context(), provider, code, state)
+	if err != nil {
+		if err == domain.ErrUnknownProvider {
+			apierror.Respond(c, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, domain.ErrExternalLoginFailed) {
+			apierror.Respond(c, http.StatusUnauthorized, domain.ErrExternalLoginFailed)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	// sign in, linking or creating the user on first use
+	token, user, err := loginContr.userUseCase.ExternalLogin(c.Request.Context(), identity)
+	if err != nil {
+		if twoFactorRequired(c, err) {       // the provider replaces the password, not the code
+			return
+		}
+		if err == domain.ErrUserExists || err == domain.ErrSetupRequired || err == domain.ErrIdentityLinkRequired {
+			apierror.Respond(c, http.StatusConflict, err)
+			return
+		}
+		if err == domain.ErrAccountDeactivated || err == domain.ErrEmailNotVerified || errors.Is(err, domain.ErrRejectedByExtension) {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	// same answer as a password login
+	c.JSON(http.StatusOK, gin.H{
+		"token": token,
+		"user": gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"role":     user.Role,
+		},
+	})
+}
+
+// check if the client reached us over https (directly or through a proxy)
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,39 @@
+package controllers
+
+// imports
+import (
+	"context";
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+)
+
+// health controller
+type HealthController struct {
+	readOnly  bool                                  // instance serves reads only
+	dbPing    func(ctx context.Context) error       // checks database connectivity
+}
+
+// new health controller
+func NewHealthController(readOnly bool, dbPing func(ctx context.Context) error) *HealthController {
+	return &HealthController{readOnly: readOnly, dbPing: dbPing}        // return new health controller instance
+}
+
+func (healthContr *HealthController) Health(c *gin.Context) {
+
+	contx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)      // set timeout
+	defer cancel()
+
+	mode := "read-write"
+	if healthContr.readOnly {
+		mode = "read-only"
+	}
+
+	// report unhealthy if database can't be reached
+	if err := healthContr.dbPing(contx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "mode": mode, "read_only": healthContr.readOnly, "database": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "mode": mode, "read_only": healthContr.readOnly, "database": "ok"})
+}
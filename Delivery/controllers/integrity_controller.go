@@ -0,0 +1,41 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// integrity controller
+type IntegrityController struct {
+	integrityUseCase usecases.IntegrityUseCase        // integrity usecase for checks and repairs
+}
+
+// new integrity controller
+func NewIntegrityController(uc usecases.IntegrityUseCase) *IntegrityController {
+	return &IntegrityController{integrityUseCase: uc}        // return new integrity controller instance
+}
+
+func (integrityContr *IntegrityController) CheckIntegrity(c *gin.Context) {
+
+	// every check as a dry run when there is no body
+	var req domain.IntegrityRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// run checks through usecase layer
+	report, err := integrityContr.integrityUseCase.CheckIntegrity(c.Request.Context(), req)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)       // return what was found and repaired
+}
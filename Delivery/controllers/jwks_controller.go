@@ -0,0 +1,33 @@
+package controllers
+
+// imports
+import (
+	"encoding/json";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// jwks controller
+type JWKSController struct {
+	jwtService domain.JWTService        // jwt service holding the signing keys
+}
+
+// new jwks controller
+func NewJWKSController(jwtService domain.JWTService) *JWKSController {
+	return &JWKSController{jwtService: jwtService}        // return new jwks controller instance
+}
+
+func (jwksContr *JWKSController) GetKeys(c *gin.Context) {
+
+	// jwk set media type, so the response format handler leaves the standard layout alone
+	body, err := json.Marshal(jwksContr.jwtService.JWKS())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")        // verifiers refetch after a rotation within minutes
+	c.Data(http.StatusOK, "application/jwk-set+json", body)        // return public signing keys
+}
@@ -0,0 +1,143 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// label controller
+type LabelController struct {
+	labelUseCase usecases.LabelUseCase        // label usecase for label operations
+}
+
+// new label controller
+func NewLabelController(uc usecases.LabelUseCase) *LabelController {
+	return &LabelController{labelUseCase: uc}        // return new label controller instance
+}
+
+func (labelContr *LabelController) CreateLabel(c *gin.Context) {
+
+	var label domain.Label
+	if !bindJSON(c, &label) {       // parse and validate request body
+		return
+	}
+
+	// create label through usecase layer
+	created, err := labelContr.labelUseCase.CreateLabel(c.Request.Context(), &label)
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)        // return created label with 201 status
+}
+
+func (labelContr *LabelController) GetLabels(c *gin.Context) {
+
+	// get labels through usecase layer
+	labels, err := labelContr.labelUseCase.GetLabels(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, labels)       // return all labels
+}
+
+func (labelContr *LabelController) GetLabelByID(c *gin.Context) {
+
+	// get label through usecase layer
+	label, err := labelContr.labelUseCase.GetLabelByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, label)       // return found label
+}
+
+func (labelContr *LabelController) UpdateLabel(c *gin.Context) {
+
+	var label domain.Label
+	if !bindJSON(c, &label) {       // parse and validate request body
+		return
+	}
+
+	// update label through usecase layer
+	updated, err := labelContr.labelUseCase.UpdateLabel(c.Request.Context(), c.Param("id"), &label)
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)       // return updated label
+}
+
+func (labelContr *LabelController) DeleteLabel(c *gin.Context) {
+
+	// delete label through usecase layer
+	err := labelContr.labelUseCase.DeleteLabel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "label deleted successfully"})       // success response
+}
+
+func (labelContr *LabelController) AttachLabel(c *gin.Context) {
+
+	// attach label through usecase layer
+	task, err := labelContr.labelUseCase.AttachLabel(c.Request.Context(), c.Param("id"), c.Param("labelId"))
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)       // return task with its labels
+}
+
+func (labelContr *LabelController) DetachLabel(c *gin.Context) {
+
+	// detach label through usecase layer
+	task, err := labelContr.labelUseCase.DetachLabel(c.Request.Context(), c.Param("id"), c.Param("labelId"))
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)       // return task with its labels
+}
+
+func (labelContr *LabelController) BudgetReport(c *gin.Context) {
+
+	// build report through usecase layer
+	report, err := labelContr.labelUseCase.GetBudgetReport(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)       // return budgets and cost totals
+}
+
+// map label errors to responses
+func labelError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrLabelNotFound, domain.ErrTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrLabelExists:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrInvalidLabelID, domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	default:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+// imports
+import (
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+)
+
+// hypermedia link (only added when the caller may follow it)
+type Link struct {
+	Href    string    `json:"href"`                 // path under the version the request was made to
+	Method  string    `json:"method"`               // http method to use
+	Status  string    `json:"status,omitempty"`     // status the task moves to (transitions only)
+}
+
+// task with the actions available to the caller
+type TaskResource struct {
+	domain.Task
+	Links  map[string]interface{}  `json:"_links"`       // self, update, delete, subtasks, history, parent, transitions
+}
+
+// add links to one task
+func taskResource(c *gin.Context, task domain.Task, workflow domain.TaskWorkflow) TaskResource {
+
+	prefix := infrastructure.APIPrefix(c)        // links stay on the version the caller uses
+	self := prefix + "/tasks/" + task.ID.Hex()
+	links := map[string]interface{}{
+		"self":      Link{Href: self, Method: "GET"},
+		"subtasks":  Link{Href: self + "/subtasks", Method: "GET"},
+		"history":   Link{Href: self + "/history", Method: "GET"},
+	}
+	if task.ParentID != nil {
+		links["parent"] = Link{Href: prefix + "/tasks/" + task.ParentID.Hex(), Method: "GET"}
+	}
+
+	// write actions only for callers allowed to perform them
+	if infrastructure.HasAccess(c, domain.PermissionTaskWrite, domain.ScopeWriteTasks) {
+		links["update"] = Link{Href: self, Method: "PUT"}
+		links["delete"] = Link{Href: self, Method: "DELETE"}
+		transitions := []Link{}
+		for _, status := range workflow.Next(task.Status) {
+			transitions = append(transitions, Link{Href: self, Method: "PUT", Status: status})
+		}
+		links["transitions"] = transitions
+	}
+
+	return TaskResource{Task: task, Links: links}
+}
+
+// task found by a search with its links
+type TaskSearchResultResource struct {
+	Task        TaskResource           `json:"task"`
+	Score       float64                `json:"score"`                   // relevance, only comparable within one search
+	Highlights  map[string][]string    `json:"highlights,omitempty"`    // field -> fragments with the matched words in <em></em>
+}
+
+// tasks fetched by id with their links and the ids that weren't found
+type TaskBatchResource struct {
+	Tasks     []TaskResource   `json:"tasks"`         // requested order
+	NotFound  []string         `json:"not_found"`     // invalid, missing or hidden ids
+}
+
+// add links to every task of a list
+func taskResources(c *gin.Context, tasks []domain.Task, workflow domain.TaskWorkflow) []TaskResource {
+	resources := make([]TaskResource, 0, len(tasks))
+	for _, task := range tasks {
+		resources = append(resources, taskResource(c, task, workflow))
+	}
+	return resources
+}
+
+// links of the caller's own profile
+func profileLinks(c *gin.Context) map[string]interface{} {
+	me := infrastructure.APIPrefix(c) + "/me"
+	return map[string]interface{}{
+		"self":      Link{Href: me, Method: "GET"},
+		"update":    Link{Href: me, Method: "PUT"},
+		"password":  Link{Href: me + "/password", Method: "PUT"},
+		"tokens":    Link{Href: me + "/tokens", Method: "GET"},
+	}
+}
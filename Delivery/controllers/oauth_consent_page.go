@@ -0,0 +1,124 @@
+package controllers
+
+// imports
+import (
+	"bytes";
+	"html/template";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// what each scope lets a client do, as shown on the consent page
+var scopeDescriptions = map[string]string{
+	domain.ScopeReadTasks:  "See your tasks",
+	domain.ScopeWriteTasks: "Create, change and delete your tasks",
+}
+
+// steps of the consent page
+const (
+	consentStepSignIn     = "sign_in"        // username and password
+	consentStepTwoFactor  = "two_factor"     // app or backup code of accounts with two-factor authentication
+	consentStepConsent    = "consent"        // approve or deny the client
+	consentStepError      = "error"          // request can't be sent back to the client
+)
+
+// data of the consent page
+type consentPage struct {
+	Step            string
+	Request         domain.OAuthAuthorizeRequest       // authorize request, carried along in hidden fields
+	ClientName      string
+	Scopes          []string                           // descriptions of the requested scopes
+	Username        string                             // signed-in user (consent step)
+	ChallengeToken  string                             // two-factor step
+	CSRFToken       string
+	Error           string
+}
+
+var consentTemplate = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Authorize application</title>
+  <style>
+    body { font-family: sans-serif; max-width: 28rem; margin: 3rem auto; padding: 0 1rem; }
+    label, input, button { display: block; width: 100%; box-sizing: border-box; margin-top: .5rem; }
+    input, button { padding: .5rem; }
+    .error { color: #b00020; }
+    .actions { display: flex; gap: .5rem; }
+  </style>
+</head>
+<body>
+{{- define "request"}}
+  <input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+  <input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+  <input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+  <input type="hidden" name="scope" value="{{.Request.Scope}}">
+  <input type="hidden" name="state" value="{{.Request.State}}">
+  <input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+  <input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+  <input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+{{- end}}
+{{- if eq .Step "error"}}
+  <h1>Authorization failed</h1>
+  <p class="error">{{.Error}}</p>
+{{- else}}
+  <h1>{{.ClientName}}</h1>
+  {{- if .Error}}<p class="error">{{.Error}}</p>{{end}}
+  <form method="post">
+  {{- template "request" .}}
+  {{- if eq .Step "sign_in"}}
+    <p>Sign in to let {{.ClientName}} access your account.</p>
+    <label>Username <input name="username" autocomplete="username" required></label>
+    <label>Password <input name="password" type="password" autocomplete="current-password" required></label>
+    <button name="action" value="sign_in">Sign in</button>
+  {{- else if eq .Step "two_factor"}}
+    <p>Enter a code from your authenticator app or a backup code.</p>
+    <input type="hidden" name="challenge_token" value="{{.ChallengeToken}}">
+    <label>Code <input name="code" autocomplete="one-time-code" required></label>
+    <button name="action" value="two_factor">Continue</button>
+  {{- else}}
+    <p>Signed in as <strong>{{.Username}}</strong>. {{.ClientName}} is asking to:</p>
+    <ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>
+    <p>You will be sent back to {{.Request.RedirectURI}}</p>
+    <div class="actions">
+      <button name="action" value="approve">Allow</button>
+      <button name="action" value="deny">Deny</button>
+    </div>
+    <button name="action" value="sign_out" formnovalidate>Use another account</button>
+  {{- end}}
+  </form>
+{{- end}}
+</body>
+</html>
+`))
+
+// render the consent page (never cached or framed, it holds a csrf token and approves access)
+func renderConsentPage(c *gin.Context, status int, page consentPage) {
+
+	descriptions := make([]string, len(page.Scopes))
+	for i, scope := range page.Scopes {
+		descriptions[i] = scope
+		if description, ok := scopeDescriptions[scope]; ok {
+			descriptions[i] = description
+		}
+	}
+	page.Scopes = descriptions
+
+	var body bytes.Buffer
+	if err := consentTemplate.Execute(&body, page); err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	c.Data(status, "text/html; charset=utf-8", body.Bytes())
+}
+
+// page for requests that can't be answered with a redirect (unknown client or redirect uri)
+func renderConsentError(c *gin.Context, status int, message string) {
+	renderConsentPage(c, status, consentPage{Step: consentStepError, Error: message})
+}
@@ -0,0 +1,301 @@
+package controllers
+
+// imports
+import (
+	"crypto/rand";
+	"crypto/subtle";
+	"encoding/hex";
+	"errors";
+	"net/http";
+	"net/url";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// oauth controller
+type OAuthController struct {
+	oauthUseCase    usecases.OAuthUseCase                 // oauth usecase for authorization server operations
+	userUseCase     usecases.UserUseCase                  // signs users in on the consent page
+	authenticator   *infrastructure.AuthMiddleWare        // checks the consent page session
+}
+
+// new oauth controller
+func NewOAuthController(uc usecases.OAuthUseCase, userUsc usecases.UserUseCase, authenticator *infrastructure.AuthMiddleWare) *OAuthController {
+	return &OAuthController{oauthUseCase: uc, userUseCase: userUsc, authenticator: authenticator}        // return new oauth controller instance
+}
+
+// cookies of the consent page, scoped to its path
+const (
+	consentSessionCookie = "oauth_session"        // login token of the user signed in on the consent page
+	consentCSRFCookie    = "oauth_csrf"           // double-submit token of the consent forms
+)
+
+// client registration request body
+type registerClientRequest struct {
+	Name          string      `json:"name" binding:"required"`
+	RedirectURIs  []string    `json:"redirect_uris" binding:"required"`
+	Scopes        []string    `json:"scopes" binding:"required"`
+}
+
+// consent page form, the authorize request travels along in hidden fields
+type consentForm struct {
+	domain.OAuthAuthorizeRequest
+	Action          string      `form:"action"`              // sign_in, two_factor, sign_out, approve or deny
+	Username        string      `form:"username"`
+	Password        string      `form:"password"`
+	ChallengeToken  string      `form:"challenge_token"`
+	Code            string      `form:"code"`
+	CSRFToken       string      `form:"csrf_token"`
+}
+
+func (oauthContr *OAuthController) RegisterClient(c *gin.Context) {
+
+	var req registerClientRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// register client through usecase layer
+	client, secret, err := oauthContr.oauthUseCase.RegisterClient(c.Request.Context(), c.GetString("userID"), req.Name, req.RedirectURIs, req.Scopes)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// secret is only ever shown once
+	c.JSON(http.StatusCreated, gin.H{"client": client, "client_secret": secret})
+}
+
+// consent page, signs the user in with a cookie of its own (third-party sites can't attach bearer tokens)
+func (oauthContr *OAuthController) Authorize(c *gin.Context) {
+
+	var req domain.OAuthAuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		renderConsentError(c, http.StatusBadRequest, "The authorization request is missing response_type, client_id, redirect_uri or scope.")
+		return
+	}
+
+	consent, ok := oauthContr.validate(c, &req)
+	if !ok {
+		return
+	}
+
+	page := consentPage{Step: consentStepSignIn, Request: req, ClientName: consent.Client.Name, Scopes: consent.Scopes, CSRFToken: oauthContr.csrfToken(c)}
+	if page.CSRFToken == "" {
+		return
+	}
+	if principal := oauthContr.signedIn(c); principal != nil {
+		page.Step, page.Username = consentStepConsent, principal.Username
+	}
+	renderConsentPage(c, http.StatusOK, page)
+}
+
+// consent page form posts: sign in, second factor, sign out, approve or deny
+func (oauthContr *OAuthController) Consent(c *gin.Context) {
+
+	var form consentForm
+	if err := c.ShouldBind(&form); err != nil {
+		renderConsentError(c, http.StatusBadRequest, "The authorization request is missing response_type, client_id, redirect_uri or scope.")
+		return
+	}
+
+	// double-submit check, another site can post the form but can't read the cookie
+	expected, _ := c.Cookie(consentCSRFCookie)
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(form.CSRFToken)) != 1 {
+		renderConsentError(c, http.StatusForbidden, "The form has expired, start the authorization again from the application.")
+		return
+	}
+
+	// re-validate the request, the hidden fields may have been tampered with
+	req := form.OAuthAuthorizeRequest
+	consent, ok := oauthContr.validate(c, &req)
+	if !ok {
+		return
+	}
+	page := consentPage{Step: consentStepSignIn, Request: req, ClientName: consent.Client.Name, Scopes: consent.Scopes, CSRFToken: form.CSRFToken}
+	ctx := c.Request.Context()
+
+	switch form.Action {
+	case "sign_in":
+		if form.Username == "" || form.Password == "" {
+			page.Error = "Enter your username and password."
+			renderConsentPage(c, http.StatusBadRequest, page)
+			return
+		}
+		token, user, err := oauthContr.userUseCase.Login(ctx, &domain.Credentials{Username: form.Username, Password: form.Password})
+		oauthContr.signIn(c, page, token, user, err)
+
+	case "two_factor":
+		token, user, err := oauthContr.userUseCase.CompleteTwoFactorLogin(ctx, form.ChallengeToken, form.Code)
+		if err == domain.ErrInvalidTwoFactorCode {
+			page.Step, page.ChallengeToken, page.Error = consentStepTwoFactor, form.ChallengeToken, err.Error()
+			renderConsentPage(c, http.StatusUnauthorized, page)
+			return
+		}
+		oauthContr.signIn(c, page, token, user, err)
+
+	case "sign_out":
+		oauthContr.setSessionCookie(c, "", -1)
+		renderConsentPage(c, http.StatusOK, page)
+
+	case "deny":
+		redirectWithError(c, &req, "access_denied")        // tell the client per rfc 6749
+
+	case "approve":
+		principal := oauthContr.signedIn(c)
+		if principal == nil {
+			page.Error = "Your session has ended, sign in again."
+			renderConsentPage(c, http.StatusUnauthorized, page)
+			return
+		}
+
+		// issue authorization code through usecase layer
+		ctx = domain.ContextWithActor(ctx, domain.Actor{ID: principal.UserID, Username: principal.Username, Role: principal.Role, TenantID: principal.TenantID, SessionID: principal.SessionID})
+		redirect, err := oauthContr.oauthUseCase.Approve(ctx, principal.UserID, &req)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		c.Redirect(http.StatusFound, redirect)        // client gets the code at its redirect uri
+
+	default:
+		renderConsentError(c, http.StatusBadRequest, "Unknown action.")
+	}
+}
+
+// validate an authorize request, errors are rendered when the redirect uri can't be trusted and sent to the client otherwise
+func (oauthContr *OAuthController) validate(c *gin.Context, req *domain.OAuthAuthorizeRequest) (*usecases.OAuthConsent, bool) {
+
+	consent, err := oauthContr.oauthUseCase.Authorize(c.Request.Context(), req)
+	switch err {
+	case nil:
+		return consent, true
+	case domain.ErrOAuthClientNotFound:
+		renderConsentError(c, http.StatusNotFound, "The application asking for access is not registered.")
+	case domain.ErrInvalidRedirectURI:
+		renderConsentError(c, http.StatusBadRequest, "The application asked to be sent back to an address it did not register.")
+	case domain.ErrInvalidScope:
+		redirectWithError(c, req, "invalid_scope")
+	case domain.ErrUnsupportedGrant:
+		redirectWithError(c, req, "unsupported_response_type")
+	case domain.ErrInvalidCodeChallenge:
+		redirectWithError(c, req, "invalid_request")
+	default:
+		apierror.Abort(c, err)
+	}
+	return nil, false
+}
+
+// finish a sign-in step: ask for the second factor, keep the session or show why it failed
+func (oauthContr *OAuthController) signIn(c *gin.Context, page consentPage, token string, user *domain.User, err error) {
+
+	var required *domain.TwoFactorRequiredError
+	switch {
+	case errors.As(err, &required):
+		page.Step, page.ChallengeToken = consentStepTwoFactor, required.ChallengeToken
+		renderConsentPage(c, http.StatusOK, page)
+	case err == nil:
+		oauthContr.setSessionCookie(c, token, int(domain.SessionTTL.Seconds()))
+		page.Step, page.Username = consentStepConsent, user.Username
+		renderConsentPage(c, http.StatusOK, page)
+	case errors.Is(err, domain.ErrInvalidCredentials), errors.Is(err, domain.ErrAccountLocked), errors.Is(err, domain.ErrAccountDeactivated), errors.Is(err, domain.ErrInvalidTwoFactorChallenge):
+		page.Error = err.Error()
+		renderConsentPage(c, http.StatusUnauthorized, page)
+	default:
+		apierror.Abort(c, err)
+	}
+}
+
+// user signed in on the consent page, nil without a valid session (scoped tokens can't approve clients)
+func (oauthContr *OAuthController) signedIn(c *gin.Context) *infrastructure.Principal {
+
+	token, _ := c.Cookie(consentSessionCookie)
+	if token == "" {
+		return nil
+	}
+	principal, err := oauthContr.authenticator.Authenticate(c.Request.Context(), token, c.FullPath())
+	if err != nil || principal.Scoped {
+		oauthContr.setSessionCookie(c, "", -1)
+		return nil
+	}
+	return principal
+}
+
+// csrf token of the browser, issued on its first visit (empty after answering an error)
+func (oauthContr *OAuthController) csrfToken(c *gin.Context) string {
+
+	if token, _ := c.Cookie(consentCSRFCookie); token != "" {
+		return token
+	}
+
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		apierror.Abort(c, err)
+		return ""
+	}
+	token := hex.EncodeToString(bytes)
+
+	// strict, the form is only ever posted from the page itself
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(consentCSRFCookie, token, 0, c.FullPath(), "", isHTTPS(c), true)
+	return token
+}
+
+// lax, the session has to come along when a client sends the user to the consent page
+func (oauthContr *OAuthController) setSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(consentSessionCookie, token, maxAge, c.FullPath(), "", isHTTPS(c), true)
+}
+
+// send an authorize error back to the client's (verified) redirect uri
+func redirectWithError(c *gin.Context, req *domain.OAuthAuthorizeRequest, code string) {
+
+	redirect, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	query := redirect.Query()
+	query.Set("error", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, redirect.String())
+}
+
+// errors of the token endpoint keep the oauth 2.0 shape (rfc 6749 section 5.2) clients expect
+func (oauthContr *OAuthController) Token(c *gin.Context) {
+
+	var req domain.OAuthTokenRequest
+	err := c.ShouldBind(&req)       // token endpoint accepts form or json
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	// exchange code through usecase layer
+	token, err := oauthContr.oauthUseCase.ExchangeCode(c.Request.Context(), &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidClient:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": err.Error()})
+		case domain.ErrInvalidGrant:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": err.Error()})
+		case domain.ErrUnsupportedGrant:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type", "error_description": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)       // return access token
+}
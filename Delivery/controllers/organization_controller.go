@@ -0,0 +1,129 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// organization controller
+type OrganizationController struct {
+	orgUseCase usecases.OrganizationUseCase        // organization usecase for tenants and their members
+}
+
+// new organization controller
+func NewOrganizationController(uc usecases.OrganizationUseCase) *OrganizationController {
+	return &OrganizationController{orgUseCase: uc}        // return new organization controller instance
+}
+
+func (orgContr *OrganizationController) CreateOrganization(c *gin.Context) {
+
+	var req domain.CreateOrganizationRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create organization through usecase layer
+	org, err := orgContr.orgUseCase.CreateOrganization(c.Request.Context(), req)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)       // return created organization
+}
+
+func (orgContr *OrganizationController) GetOrganization(c *gin.Context) {
+
+	// get organization through usecase layer
+	org, err := orgContr.orgUseCase.GetOrganization(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)       // return organization
+}
+
+func (orgContr *OrganizationController) ListMembers(c *gin.Context) {
+
+	// parse page (e.g. ?page=2&limit=50)
+	var page, limit int
+	for name, target := range map[string]*int{"page": &page, "limit": &limit} {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 1 {
+				apierror.Message(c, http.StatusBadRequest, name + " must be a positive number")
+				return
+			}
+			*target = value
+		}
+	}
+
+	// list members through usecase layer
+	members, err := orgContr.orgUseCase.ListMembers(c.Request.Context(), c.Param("id"), page, limit)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)       // return page of members
+}
+
+func (orgContr *OrganizationController) CreateInvite(c *gin.Context) {
+
+	var req domain.CreateOrganizationInviteRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create invite through usecase layer
+	invite, plain, err := orgContr.orgUseCase.CreateInvite(c.Request.Context(), c.Param("id"), req.Email)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	// plain token is only ever shown once
+	c.JSON(http.StatusCreated, gin.H{"token": plain, "details": invite})
+}
+
+func (orgContr *OrganizationController) AcceptInvite(c *gin.Context) {
+
+	var req domain.AcceptOrganizationInviteRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// join through usecase layer
+	org, err := orgContr.orgUseCase.AcceptInvite(c.Request.Context(), req.Token)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	// the old token still names the default workspace, its session has ended
+	c.JSON(http.StatusOK, gin.H{"message": "joined organization, log in again to continue", "organization": org})
+}
+
+func organizationError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrOrganizationNotFound, domain.ErrUserNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrNotOrganizationOwner, domain.ErrInvalidOrgInvite:
+		apierror.Respond(c, http.StatusForbidden, err)
+	case domain.ErrAlreadyInOrganization, domain.ErrAdminCannotJoin:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	}
+}
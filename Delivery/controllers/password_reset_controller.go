@@ -0,0 +1,60 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// password reset controller
+type PasswordResetController struct {
+	passwordResetUseCase usecases.PasswordResetUseCase        // password reset usecase for forgot/reset flows
+}
+
+// new password reset controller
+func NewPasswordResetController(uc usecases.PasswordResetUseCase) *PasswordResetController {
+	return &PasswordResetController{passwordResetUseCase: uc}        // return new password reset controller instance
+}
+
+func (resetContr *PasswordResetController) ForgotPassword(c *gin.Context) {
+
+	var req domain.ForgotPasswordRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// request reset email through usecase layer
+	if err := resetContr.passwordResetUseCase.RequestReset(c.Request.Context(), req.Email); err != nil {
+		apierror.Message(c, http.StatusInternalServerError, "could not send password reset email")
+		return
+	}
+
+	// same answer whether the address is known or not
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email belongs to an account, a reset link has been sent"})
+}
+
+func (resetContr *PasswordResetController) ResetPassword(c *gin.Context) {
+
+	var req domain.ResetPasswordRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// reset password through usecase layer
+	if err := resetContr.passwordResetUseCase.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		if err == domain.ErrInvalidResetToken {
+			apierror.Respond(c, http.StatusBadRequest, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})       // success response
+}
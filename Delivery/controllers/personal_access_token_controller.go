@@ -0,0 +1,74 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// personal access token controller
+type PersonalAccessTokenController struct {
+	patUseCase usecases.PersonalAccessTokenUseCase        // token usecase for personal access token operations
+}
+
+// new personal access token controller
+func NewPersonalAccessTokenController(uc usecases.PersonalAccessTokenUseCase) *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{patUseCase: uc}        // return new token controller instance
+}
+
+func (patContr *PersonalAccessTokenController) CreateToken(c *gin.Context) {
+
+	var req domain.CreatePersonalAccessTokenRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// mint token through usecase layer
+	token, plain, err := patContr.patUseCase.CreateToken(c.Request.Context(), c.GetString("userID"), &req)
+	if err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// plain token is only ever shown once
+	c.JSON(http.StatusCreated, gin.H{"token": plain, "details": token})
+}
+
+func (patContr *PersonalAccessTokenController) ListTokens(c *gin.Context) {
+
+	// list tokens through usecase layer
+	tokens, err := patContr.patUseCase.ListTokens(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)       // return user's tokens
+}
+
+func (patContr *PersonalAccessTokenController) RevokeToken(c *gin.Context) {
+
+	tokenID := c.Param("id")       // get token id from request parameter
+
+	// revoke token through usecase layer
+	err := patContr.patUseCase.RevokeToken(c.Request.Context(), c.GetString("userID"), tokenID)
+	if err != nil {
+		switch err {
+		case domain.ErrTokenNotFound:
+			apierror.Respond(c, http.StatusNotFound, err)
+		case domain.ErrInvalidTokenID:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		default:
+			apierror.Abort(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "token revoked successfully"})       // success response
+}
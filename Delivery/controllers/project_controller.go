@@ -0,0 +1,166 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// project controller
+type ProjectController struct {
+	projectUseCase usecases.ProjectUseCase        // project usecase for project and membership operations
+}
+
+// new project controller
+func NewProjectController(uc usecases.ProjectUseCase) *ProjectController {
+	return &ProjectController{projectUseCase: uc}        // return new project controller instance
+}
+
+func (projectContr *ProjectController) CreateProject(c *gin.Context) {
+
+	var project domain.Project
+	if !bindJSON(c, &project) {       // parse and validate request body
+		return
+	}
+
+	// create project through usecase layer
+	created, err := projectContr.projectUseCase.CreateProject(c.Request.Context(), &project)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)        // return created project with 201 status
+}
+
+func (projectContr *ProjectController) GetProjects(c *gin.Context) {
+
+	// get projects through usecase layer
+	projects, err := projectContr.projectUseCase.GetProjects(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)       // return the caller's projects
+}
+
+func (projectContr *ProjectController) GetProject(c *gin.Context) {
+
+	// get project through usecase layer
+	project, err := projectContr.projectUseCase.GetProject(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)       // return found project
+}
+
+func (projectContr *ProjectController) UpdateProject(c *gin.Context) {
+
+	var project domain.Project
+	if !bindJSON(c, &project) {       // parse and validate request body
+		return
+	}
+
+	// update project through usecase layer
+	updated, err := projectContr.projectUseCase.UpdateProject(c.Request.Context(), c.Param("id"), &project)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)       // return updated project
+}
+
+func (projectContr *ProjectController) DeleteProject(c *gin.Context) {
+
+	// delete project through usecase layer
+	err := projectContr.projectUseCase.DeleteProject(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "project deleted successfully"})       // success response
+}
+
+func (projectContr *ProjectController) SetMember(c *gin.Context) {
+
+	var member domain.ProjectMember
+	if !bindJSON(c, &member) {       // parse and validate request body (role only, the user comes from the path)
+		return
+	}
+
+	// add or change member through usecase layer
+	member.UserID = c.Param("userId")
+	project, err := projectContr.projectUseCase.SetMember(c.Request.Context(), c.Param("id"), member)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)       // return project with its members
+}
+
+func (projectContr *ProjectController) RemoveMember(c *gin.Context) {
+
+	// remove member through usecase layer
+	project, err := projectContr.projectUseCase.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)       // return project with its remaining members
+}
+
+// map project errors to responses
+func (projectContr *ProjectController) CloneProject(c *gin.Context) {
+
+	var req domain.ProjectCloneRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+	// copying tasks creates tasks, the project itself only needs task:read like creating one
+	if req.IncludeTasks && !infrastructure.HasAccess(c, domain.PermissionTaskWrite, domain.ScopeWriteTasks) {
+		apierror.MessageWith(c, http.StatusForbidden, "permission denied", gin.H{"required_permission": domain.PermissionTaskWrite})
+		return
+	}
+
+	// clone project through usecase layer
+	clone, err := projectContr.projectUseCase.CloneProject(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err == domain.ErrTooManyTasksToClone {
+			apierror.RespondWith(c, http.StatusConflict, err, gin.H{"max_tasks": domain.MaxClonedTasks})
+			return
+		}
+		projectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, clone)        // return new project and copied task count with 201 status
+}
+
+func projectError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrProjectNotFound, domain.ErrProjectMemberNotFound, domain.ErrUserNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrProjectAccessDenied:
+		apierror.Respond(c, http.StatusForbidden, err)
+	case domain.ErrProjectHasTasks, domain.ErrLastProjectOwner:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// query log controller
+type QueryLogController struct {
+	queryLog domain.QueryLogSwitch        // switch of the temporary database query log
+}
+
+// new query log controller
+func NewQueryLogController(queryLog domain.QueryLogSwitch) *QueryLogController {
+	return &QueryLogController{queryLog: queryLog}        // return new query log controller instance
+}
+
+func (queryLogContr *QueryLogController) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, queryLogContr.queryLog.QueryLogStatus())       // whether queries are logged and until when
+}
+
+func (queryLogContr *QueryLogController) Enable(c *gin.Context) {
+
+	var req domain.QueryLogRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// open the window (it closes itself)
+	status, err := queryLogContr.queryLog.EnableQueryLog(time.Duration(req.Minutes) * time.Minute)
+	if err != nil {
+		apierror.RespondWith(c, http.StatusBadRequest, err, gin.H{"max_minutes": status.MaxMinutes})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)       // return new state
+}
+
+func (queryLogContr *QueryLogController) Disable(c *gin.Context) {
+	c.JSON(http.StatusOK, queryLogContr.queryLog.DisableQueryLog())       // return new state
+}
@@ -0,0 +1,86 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// recurrence controller
+type RecurrenceController struct {
+	recurrenceUseCase usecases.RecurrenceUseCase        // recurrence usecase for repeating tasks
+	workflow          domain.TaskWorkflow               // status changes offered as transition links
+}
+
+// new recurrence controller
+func NewRecurrenceController(uc usecases.RecurrenceUseCase, workflow domain.TaskWorkflow) *RecurrenceController {
+	return &RecurrenceController{recurrenceUseCase: uc, workflow: workflow}        // return new recurrence controller instance
+}
+
+func (recurrenceContr *RecurrenceController) SetRecurrence(c *gin.Context) {
+
+	var rule domain.Recurrence
+	if !bindJSON(c, &rule) {       // parse and validate request body
+		return
+	}
+
+	// start or replace series through usecase layer
+	task, err := recurrenceContr.recurrenceUseCase.SetRecurrence(c.Request.Context(), c.Param("id"), &rule)
+	if err != nil {
+		recurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, recurrenceContr.workflow))       // return task with its rule
+}
+
+func (recurrenceContr *RecurrenceController) PauseRecurrence(c *gin.Context) {
+
+	task, err := recurrenceContr.recurrenceUseCase.PauseRecurrence(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		recurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, recurrenceContr.workflow))       // return task with paused rule
+}
+
+func (recurrenceContr *RecurrenceController) ResumeRecurrence(c *gin.Context) {
+
+	task, err := recurrenceContr.recurrenceUseCase.ResumeRecurrence(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		recurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, recurrenceContr.workflow))       // return task with resumed rule
+}
+
+func (recurrenceContr *RecurrenceController) EndRecurrence(c *gin.Context) {
+
+	task, err := recurrenceContr.recurrenceUseCase.EndRecurrence(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		recurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, recurrenceContr.workflow))       // return task without rule
+}
+
+// map recurrence errors to status codes
+func recurrenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		apierror.Respond(c, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrRecurrenceNotFound):
+		apierror.Respond(c, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrInvalidTaskID), errors.Is(err, domain.ErrInvalidRecurrence):
+		apierror.Respond(c, http.StatusBadRequest, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
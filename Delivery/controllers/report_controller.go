@@ -0,0 +1,82 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// report controller
+type ReportController struct {
+	reportUseCase usecases.ReportUseCase        // report usecase for summary and productivity reports
+}
+
+// new report controller
+func NewReportController(uc usecases.ReportUseCase) *ReportController {
+	return &ReportController{reportUseCase: uc}        // return new report controller instance
+}
+
+func (reportContr *ReportController) Summary(c *gin.Context) {
+
+	// build summary through usecase layer
+	report, err := reportContr.reportUseCase.Summary(c.Request.Context())
+	if err != nil {
+		reportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)       // return task counts
+}
+
+func (reportContr *ReportController) Productivity(c *gin.Context) {
+
+	// parse range from query parameters (?from=2025-07-01&to=2025-07-31&user_id=...)
+	query := domain.ProductivityQuery{UserID: c.Query("user_id")}
+	if raw := c.Query("from"); raw != "" {
+		from, err := parseImportDate(raw)
+		if err != nil {
+			apierror.Message(c, http.StatusBadRequest, "from must be a date like 2025-07-01 or 2025-07-01T00:00:00Z")
+			return
+		}
+		query.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := parseImportDate(raw)
+		if err != nil {
+			apierror.Message(c, http.StatusBadRequest, "to must be a date like 2025-07-31 or 2025-07-31T00:00:00Z")
+			return
+		}
+		if len(raw) == len("2006-01-02") {
+			to = to.Add(24 * time.Hour)        // plain dates include the whole day
+		}
+		query.To = &to
+	}
+
+	// build productivity report through usecase layer
+	report, err := reportContr.reportUseCase.Productivity(c.Request.Context(), query)
+	if err != nil {
+		reportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)       // return work done per day
+}
+
+// map report errors to responses
+func reportError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrReportForbidden:
+		apierror.Respond(c, http.StatusForbidden, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,49 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// security controller
+type SecurityController struct {
+	sessionUseCase usecases.SessionUseCase        // session usecase for workspace security settings
+}
+
+// new security controller
+func NewSecurityController(uc usecases.SessionUseCase) *SecurityController {
+	return &SecurityController{sessionUseCase: uc}        // return new security controller instance
+}
+
+func (securityContr *SecurityController) GetSettings(c *gin.Context) {
+
+	// read settings through usecase layer
+	settings, err := securityContr.sessionUseCase.GetSecuritySettings(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)       // saved settings or environment defaults
+}
+
+func (securityContr *SecurityController) UpdateSettings(c *gin.Context) {
+
+	var req domain.SecuritySettings
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// save settings through usecase layer
+	settings, err := securityContr.sessionUseCase.UpdateSecuritySettings(c.Request.Context(), &req)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)       // applies from the next login
+}
@@ -0,0 +1,53 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// setup controller
+type SetupController struct {
+	setupUseCase usecases.SetupUseCase        // setup usecase for first run
+}
+
+// new setup controller
+func NewSetupController(uc usecases.SetupUseCase) *SetupController {
+	return &SetupController{setupUseCase: uc}        // return new setup controller instance
+}
+
+func (setupContr *SetupController) CompleteSetup(c *gin.Context) {
+
+	var req domain.SetupRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create first admin through usecase layer
+	admin := domain.User{Username: req.Username, Password: req.Password, Email: req.Email}
+	settings := domain.InstanceSettings{WorkspaceName: req.WorkspaceName, SMTP: req.SMTP}
+	if err := setupContr.setupUseCase.CompleteSetup(c.Request.Context(), req.Token, &admin, &settings); err != nil {
+		if validationFailed(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrSMTPTestFailed) {
+			apierror.Respond(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		switch err {
+		case domain.ErrSetupCompleted, domain.ErrUserExists:
+			apierror.Respond(c, http.StatusConflict, err)
+		case domain.ErrInvalidSetupToken:
+			apierror.Respond(c, http.StatusForbidden, err)
+		default:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "setup completed, admin user created"})       // success response
+}
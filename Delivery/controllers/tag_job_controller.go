@@ -0,0 +1,80 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// tag job controller
+type TagJobController struct {
+	tagJobUseCase usecases.TagJobUseCase        // tag job usecase for bulk tag renames and merges
+}
+
+// new tag job controller
+func NewTagJobController(uc usecases.TagJobUseCase) *TagJobController {
+	return &TagJobController{tagJobUseCase: uc}        // return new tag job controller instance
+}
+
+func (tagJobContr *TagJobController) RenameTag(c *gin.Context) {
+
+	var req domain.RenameTagRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// start rename through usecase layer
+	job, err := tagJobContr.tagJobUseCase.RenameTag(c.Request.Context(), req)
+	if err != nil {
+		tagJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)       // tasks are retagged in the background
+}
+
+func (tagJobContr *TagJobController) MergeTags(c *gin.Context) {
+
+	var req domain.MergeTagsRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// start merge through usecase layer
+	job, err := tagJobContr.tagJobUseCase.MergeTags(c.Request.Context(), req)
+	if err != nil {
+		tagJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)       // tasks are retagged in the background
+}
+
+func (tagJobContr *TagJobController) GetJob(c *gin.Context) {
+
+	// get job through usecase layer
+	job, err := tagJobContr.tagJobUseCase.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		tagJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)       // job with its progress
+}
+
+// map tag job errors to responses
+func tagJobError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrLabelNotFound, domain.ErrTagJobNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrLabelExists:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrInvalidTagJobID, domain.ErrSameTag:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	default:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	}
+}
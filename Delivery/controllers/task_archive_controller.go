@@ -0,0 +1,78 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// task archive controller
+type TaskArchiveController struct {
+	taskArchiveUseCase usecases.TaskArchiveUseCase        // task archive usecase for archive listing and restore
+	workflow           domain.TaskWorkflow                // allowed status changes advertised in restored task links
+}
+
+// new task archive controller
+func NewTaskArchiveController(uc usecases.TaskArchiveUseCase, workflow domain.TaskWorkflow) *TaskArchiveController {
+	return &TaskArchiveController{taskArchiveUseCase: uc, workflow: workflow}        // return new task archive controller instance
+}
+
+func (archiveContr *TaskArchiveController) GetArchivedTasks(c *gin.Context) {
+
+	// parse paging from query parameters (?before=...&limit=...)
+	var before time.Time
+	var limit int64
+	var err error
+	if raw := c.Query("before"); raw != "" {
+		if before, err = time.Parse(time.RFC3339, raw); err != nil {
+			apierror.Message(c, http.StatusBadRequest, "before must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+			return
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.ParseInt(raw, 10, 64); err != nil || limit <= 0 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	// get archived tasks through usecase layer
+	tasks, err := archiveContr.taskArchiveUseCase.GetArchivedTasks(c.Request.Context(), before, limit)
+	if err != nil {
+		taskArchiveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)       // return archived tasks, newest first
+}
+
+func (archiveContr *TaskArchiveController) UnarchiveTask(c *gin.Context) {
+
+	// restore task through usecase layer
+	task, err := archiveContr.taskArchiveUseCase.UnarchiveTask(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskArchiveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, archiveContr.workflow))       // return the restored task
+}
+
+// map task archive errors to responses
+func taskArchiveError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrArchivedTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,79 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// task dependency controller
+type TaskDependencyController struct {
+	dependencyUseCase usecases.TaskDependencyUseCase        // task dependency usecase for blocker links
+}
+
+// new task dependency controller
+func NewTaskDependencyController(uc usecases.TaskDependencyUseCase) *TaskDependencyController {
+	return &TaskDependencyController{dependencyUseCase: uc}        // return new task dependency controller instance
+}
+
+func (dependencyContr *TaskDependencyController) AddDependency(c *gin.Context) {
+
+	var input domain.TaskDependencyInput
+	if !bindJSON(c, &input) {       // parse and validate request body
+		return
+	}
+
+	// link tasks through usecase layer
+	dependency, err := dependencyContr.dependencyUseCase.AddDependency(c.Request.Context(), c.Param("id"), input.BlockerID)
+	if err != nil {
+		taskDependencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dependency)       // return the new dependency
+}
+
+func (dependencyContr *TaskDependencyController) RemoveDependency(c *gin.Context) {
+
+	// unlink tasks through usecase layer
+	if err := dependencyContr.dependencyUseCase.RemoveDependency(c.Request.Context(), c.Param("id"), c.Param("blockerId")); err != nil {
+		taskDependencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "dependency removed"})       // success response
+}
+
+func (dependencyContr *TaskDependencyController) GetDependencies(c *gin.Context) {
+
+	// get dependencies through usecase layer
+	dependencies, err := dependencyContr.dependencyUseCase.GetDependencies(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskDependencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)       // return blockers and blocked tasks
+}
+
+// map task dependency errors to responses
+func taskDependencyError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrTaskNotFound, domain.ErrDependencyNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrDependencyExists, domain.ErrDependencyCycle:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
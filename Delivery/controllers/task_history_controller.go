@@ -0,0 +1,78 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// task history controller
+type TaskHistoryController struct {
+	taskHistoryUseCase usecases.TaskHistoryUseCase        // task history usecase for point in time views
+}
+
+// new task history controller
+func NewTaskHistoryController(uc usecases.TaskHistoryUseCase) *TaskHistoryController {
+	return &TaskHistoryController{taskHistoryUseCase: uc}        // return new task history controller instance
+}
+
+func (historyContr *TaskHistoryController) GetTaskAsOf(c *gin.Context) {
+
+	// parse point in time from query parameter (?at=...)
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		apierror.Message(c, http.StatusBadRequest, "at must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+		return
+	}
+
+	// rebuild task through usecase layer
+	task, err := historyContr.taskHistoryUseCase.GetTaskAsOf(c.Request.Context(), c.Param("id"), at)
+	if err != nil {
+		taskHistoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)       // return task as it was at the given time
+}
+
+func (historyContr *TaskHistoryController) GetTaskHistory(c *gin.Context) {
+
+	// get stored changes through usecase layer
+	events, err := historyContr.taskHistoryUseCase.GetTaskHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskHistoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)       // return snapshots, oldest first
+}
+
+func (historyContr *TaskHistoryController) GetTaskChanges(c *gin.Context) {
+
+	// get field changes through usecase layer
+	changes, err := historyContr.taskHistoryUseCase.GetTaskChanges(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskHistoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)       // return changes, oldest first
+}
+
+// map task history errors to status codes
+func taskHistoryError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrTaskHistoryDisabled:
+		apierror.Respond(c, http.StatusNotImplemented, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
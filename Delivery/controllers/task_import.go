@@ -0,0 +1,240 @@
+package controllers
+
+// imports
+import (
+	"encoding/csv";
+	"encoding/json";
+	"errors";
+	"fmt";
+	"io";
+	"net/http";
+	"path/filepath";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/gin-gonic/gin/binding";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+const maxImportSize = 5 << 20        // largest accepted import file (5 MB)
+
+func (taskContr *TaskController) ImportTasks(c *gin.Context) {
+
+	// file from a multipart upload (field "file") or the raw request body
+	data, format, err := readImportFile(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var rows []domain.TaskImportRow
+	switch format {
+	case domain.ImportFormatCSV:
+		rows, err = parseCSVImport(data)
+	case domain.ImportFormatJSON:
+		rows, err = parseJSONImport(data)
+	default:
+		err = errors.New("format must be csv or json")
+	}
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// validate and create through usecase layer
+	report, err := taskContr.taskUseCase.ImportTasks(c.Request.Context(), rows)
+	if err != nil {
+		switch err {
+		case domain.ErrTooManyImportRows:
+			apierror.RespondWith(c, http.StatusRequestEntityTooLarge, err, gin.H{"max_rows": domain.MaxImportRows})
+		default:
+			apierror.Abort(c, err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)       // outcome of every row
+}
+
+// read the uploaded file and its format (?format=, file extension or content type)
+func readImportFile(c *gin.Context) ([]byte, string, error) {
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	format := strings.ToLower(c.Query("format"))
+	var reader io.Reader = c.Request.Body
+	contentType := c.ContentType()
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("file is required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		reader = file
+		contentType = header.Header.Get("Content-Type")
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+	}
+	if format == "" {
+		switch {
+		case strings.Contains(contentType, "csv"):
+			format = domain.ImportFormatCSV
+		case strings.Contains(contentType, "json"):
+			format = domain.ImportFormatJSON
+		}
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, "", fmt.Errorf("file is larger than %d bytes", maxImportSize)
+		}
+		return nil, "", err
+	}
+
+	return data, format, nil
+}
+
+// rows of a csv file with a header line (columns as in the export, unknown ones are ignored)
+func parseCSVImport(data []byte) ([]domain.TaskImportRow, error) {
+
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(data), "\ufeff")))       // spreadsheets may add a byte order mark
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("csv file needs a header line")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("csv header needs a title column")
+	}
+
+	rows := []domain.TaskImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, domain.TaskImportRow{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return csvUnescape(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+		req := domain.CreateTaskRequest{Title: cell("title"), Description: cell("description"), Status: cell("status"), Priority: cell("priority")}
+		row := domain.TaskImportRow{Row: line}
+		if raw := cell("due_date"); raw != "" {
+			if req.DueDate, err = parseImportDate(raw); err != nil {
+				row.Error = "due_date: must be a date like 2025-07-22 or 2025-07-22T18:00:00Z"
+			}
+		}
+		if raw := cell("parent_id"); raw != "" && row.Error == "" {
+			parentID, err := primitive.ObjectIDFromHex(raw)
+			if err != nil {
+				row.Error = "parent_id: " + domain.ErrInvalidTaskID.Error()
+			}
+			req.ParentID = &parentID
+		}
+		if raw := cell("estimate"); raw != "" && row.Error == "" {
+			if req.Estimate, err = strconv.Atoi(raw); err != nil {
+				row.Error = "estimate: must be a number of minutes"
+			}
+		}
+		for _, tag := range strings.Split(cell("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+
+		rows = append(rows, importRow(row, &req))
+	}
+
+	return rows, nil
+}
+
+// rows of a json array of task creation payloads
+func parseJSONImport(data []byte) ([]domain.TaskImportRow, error) {
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, errors.New("json file must be an array of tasks")
+	}
+
+	rows := make([]domain.TaskImportRow, 0, len(items))
+	for i, item := range items {
+		var req domain.CreateTaskRequest
+		row := domain.TaskImportRow{Row: i + 1}
+		if err := json.Unmarshal(item, &req); err != nil {
+			row.Error = fieldErrorText(err)
+		}
+		rows = append(rows, importRow(row, &req))
+	}
+
+	return rows, nil
+}
+
+// validate a parsed payload with the same rules as POST /tasks
+func importRow(row domain.TaskImportRow, req *domain.CreateTaskRequest) domain.TaskImportRow {
+	if row.Error != "" {
+		return row
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		row.Error = fieldErrorText(err)
+		return row
+	}
+	row.Task = req.ToTask()
+	row.Task.ClientID = ""        // client ids are for offline sync, imported files may repeat them
+	return row
+}
+
+// field errors as one line (e.g. "title: required; due_date: required")
+func fieldErrorText(err error) string {
+	parts := []string{}
+	for _, fieldErr := range infrastructure.BindingErrors(err) {
+		parts = append(parts, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// full timestamps or plain dates (midnight UTC)
+func parseImportDate(raw string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, raw); err == nil {
+		return date, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
+// undo the formula guard added by the csv export
+func csvUnescape(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && csvSafe(cell[1:]) != cell[1:] {
+		return cell[1:]
+	}
+	return cell
+}
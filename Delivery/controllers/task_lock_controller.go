@@ -0,0 +1,90 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// task lock controller
+type TaskLockController struct {
+	taskLockUseCase usecases.TaskLockUseCase        // task lock usecase for edit lock operations
+}
+
+// new task lock controller
+func NewTaskLockController(uc usecases.TaskLockUseCase) *TaskLockController {
+	return &TaskLockController{taskLockUseCase: uc}        // return new task lock controller instance
+}
+
+func (lockContr *TaskLockController) AcquireLock(c *gin.Context) {
+
+	// lock task through usecase layer
+	lock, err := lockContr.taskLockUseCase.AcquireLock(c.Request.Context(), c.Param("id"))
+	if err == domain.ErrTaskLocked && lock != nil {
+		apierror.RespondWith(c, http.StatusLocked, err, gin.H{"lock": lock})       // clients show who is editing
+		return
+	}
+	if err != nil {
+		taskLockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lock)       // return the caller's lock
+}
+
+func (lockContr *TaskLockController) Heartbeat(c *gin.Context) {
+
+	// extend lock through usecase layer
+	lock, err := lockContr.taskLockUseCase.Heartbeat(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskLockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lock)       // return the extended lock
+}
+
+func (lockContr *TaskLockController) ReleaseLock(c *gin.Context) {
+
+	// release lock through usecase layer
+	if err := lockContr.taskLockUseCase.ReleaseLock(c.Request.Context(), c.Param("id")); err != nil {
+		taskLockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "task lock released"})       // success response
+}
+
+func (lockContr *TaskLockController) GetLock(c *gin.Context) {
+
+	// get lock through usecase layer
+	lock, err := lockContr.taskLockUseCase.GetLock(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskLockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lock)       // return who is editing the task
+}
+
+// map task lock errors to responses
+func taskLockError(c *gin.Context, err error) {
+	switch {
+	case err == domain.ErrTaskNotFound, err == domain.ErrTaskLockNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrTaskLocked):
+		apierror.Respond(c, http.StatusLocked, err)
+	case err == domain.ErrTaskLockNotHeld:
+		apierror.Respond(c, http.StatusConflict, err)
+	case err == domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case err == domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
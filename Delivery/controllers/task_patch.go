@@ -0,0 +1,516 @@
+package controllers
+
+// imports
+import (
+	"encoding/json";
+	"errors";
+	"io";
+	"net/http";
+	"reflect";
+	"sort";
+	"strconv";
+	"strings";
+	"github.com/gin-gonic/gin";
+	"github.com/gin-gonic/gin/binding";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// patch formats of PATCH /tasks/:id (plain application/json is read as a merge patch)
+const (
+	mergePatchType  = "application/merge-patch+json"       // RFC 7386 JSON Merge Patch
+	jsonPatchType   = "application/json-patch+json"        // RFC 6902 JSON Patch
+)
+
+// task fields a patch may change, the rest is maintained by the server
+var patchableTaskFields = map[string]bool{
+	"title": true, "description": true, "due_date": true, "status": true, "priority": true, "estimate": true,
+	"parent_id": true, "project_id": true, "reminder": true, "cost": true, "tags": true, "language": true,
+}
+
+// custom patch errors
+var errPatchTestFailed = errors.New("patch test operation failed")       // a test operation didn't match the task
+
+// json patch operation
+type jsonPatchOperation struct {
+	Op     string            `json:"op"`       // add, remove, replace, move, copy or test
+	Path   string            `json:"path"`     // json pointer of the changed value (e.g. /tags/0)
+	From   string            `json:"from"`     // source of move and copy
+	Value  json.RawMessage   `json:"value"`    // value of add, replace and test
+}
+
+func (taskContr *TaskController) PatchTask(c *gin.Context) {
+
+	id := c.Param("id")       // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)        // validate it is a valid ObjectID
+	if err != nil {
+		apierror.Message(c, http.StatusBadRequest, "Invalid task ID format")
+		return
+	}
+
+	c.Header("Accept-Patch", mergePatchType+", "+jsonPatchType)
+	contentType := c.ContentType()
+	if contentType != mergePatchType && contentType != jsonPatchType && contentType != "application/json" {
+		apierror.Message(c, http.StatusUnsupportedMediaType, "Content-Type must be " + mergePatchType + " or " + jsonPatchType)
+		return
+	}
+	// only change the version the client read (e.g. If-Match: "m1abc2de")
+	expected, ok := parseTaskETag(c.GetHeader("If-Match"))
+	if !ok {
+		apierror.Message(c, http.StatusBadRequest, "If-Match must be an ETag returned for the task")
+		return
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// the patch is applied to the task as it is now
+	current, err := taskContr.taskUseCase.GetTaskByID(c.Request.Context(), id)
+	if err != nil {
+		taskUpdateError(c, err)
+		return
+	}
+	original, err := patchableTaskDocument(current)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	patched, err := patchableTaskDocument(current)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	var result interface{}
+	if contentType == jsonPatchType {
+		var operations []jsonPatchOperation
+		if err := json.Unmarshal(body, &operations); err != nil {
+			apierror.Invalid(c, infrastructure.BindingErrors(err))
+			return
+		}
+		result, err = applyJSONPatch(patched, operations)
+		if err == errPatchTestFailed {
+			apierror.Respond(c, http.StatusConflict, err)
+			return
+		}
+		if err != nil {
+			apierror.Respond(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+	} else {
+		var patch interface{}
+		if err := json.Unmarshal(body, &patch); err != nil {
+			apierror.Invalid(c, infrastructure.BindingErrors(err))
+			return
+		}
+		result = mergePatch(patched, patch)
+	}
+	document, ok := result.(map[string]interface{})
+	if !ok {
+		apierror.Message(c, http.StatusUnprocessableEntity, "patched task must be a JSON object")
+		return
+	}
+
+	// a patch that leaves the task as it is stores nothing
+	changes := changedTaskFields(original, document)
+	if len(changes) == 0 {
+		c.Header("ETag", taskETag(current))
+		c.JSON(http.StatusOK, gin.H{"message": "task updated successfully", "updated_task": taskResource(c, *current, taskContr.workflow), "changes": []domain.TaskFieldChange{}})
+		return
+	}
+	input, fieldErrs, err := taskUpdateInput(changes)
+	if len(fieldErrs) > 0 {
+		apierror.Invalid(c, fieldErrs)
+		return
+	}
+	if err != nil {
+		taskUpdateError(c, err)
+		return
+	}
+	input.ExpectedUpdatedAt = expected
+	if input.ExpectedUpdatedAt == nil {
+		input.ExpectedUpdatedAt = &current.UpdatedAt        // the version the patch was applied to
+	}
+
+	// update task through usecase layer
+	updatedTask, fieldChanges, err := taskContr.taskUseCase.PatchTask(c.Request.Context(), id, input)
+	if err != nil {
+		taskUpdateError(c, err)
+		return
+	}
+
+	c.Header("ETag", taskETag(updatedTask))
+	c.JSON(http.StatusOK, gin.H{"message": "task updated successfully", "updated_task": taskResource(c, *updatedTask, taskContr.workflow), "changes": fieldChanges})       // success response
+}
+
+// the patchable fields of a task as a json document (tags are always present so they can be appended to)
+func patchableTaskDocument(task *domain.Task) (map[string]interface{}, error) {
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for field := range document {
+		if !patchableTaskFields[field] {
+			delete(document, field)
+		}
+	}
+	if _, ok := document["tags"]; !ok {
+		document["tags"] = []interface{}{}
+	}
+
+	return document, nil
+}
+
+// top-level fields that differ after patching, removed fields map to nil
+func changedTaskFields(original map[string]interface{}, patched map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for field, value := range patched {
+		if before, ok := original[field]; !ok || !reflect.DeepEqual(before, value) {
+			changes[field] = value
+		}
+	}
+	for field := range original {
+		if _, ok := patched[field]; !ok {
+			changes[field] = nil
+		}
+	}
+	return changes
+}
+
+// turn changed fields into a partial update, field errors are payload errors (400), the error is a domain violation (422)
+func taskUpdateInput(changes map[string]interface{}) (*domain.UpdateTaskInput, []infrastructure.FieldError, error) {
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	input := &domain.UpdateTaskInput{}
+	invalid := &domain.ValidationError{}
+	set := map[string]interface{}{}
+	for _, field := range fields {
+		value := changes[field]
+		if !patchableTaskFields[field] {
+			invalid.Add(field, "can't be changed")
+			continue
+		}
+		if value != nil {
+			set[field] = value
+			continue
+		}
+
+		// removed fields are cleared where the task allows it
+		empty, zero := "", 0
+		switch field {
+		case "description":
+			input.Description = &empty
+		case "estimate":
+			input.Estimate = &zero
+		case "parent_id":
+			input.ParentID = &empty
+		case "project_id":
+			input.ProjectID = &empty
+		case "reminder":
+			input.ClearReminder = true
+		case "cost":
+			input.ClearCost = true
+		case "tags":
+			input.Tags = &[]string{}
+		default:
+			invalid.Add(field, "can't be removed")
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// set fields get the same checks as a json body
+	data, err := json.Marshal(set)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(data, input); err != nil {
+		return nil, infrastructure.BindingErrors(err), nil
+	}
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		return nil, infrastructure.BindingErrors(err), nil
+	}
+
+	return input, nil, nil
+}
+
+// RFC 7386: objects are merged, null removes a field, anything else replaces the target
+func mergePatch(target interface{}, patch interface{}) interface{} {
+
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// RFC 6902: apply operations in order, the document is left unusable when one fails
+func applyJSONPatch(document interface{}, operations []jsonPatchOperation) (interface{}, error) {
+
+	for i, operation := range operations {
+		path, err := parseJSONPointer(operation.Path)
+		if err != nil {
+			return nil, patchOperationError(i, err)
+		}
+
+		var value interface{}
+		switch operation.Op {
+		case "add", "replace", "test":
+			if operation.Value == nil {
+				return nil, patchOperationError(i, errors.New("value is required"))
+			}
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return nil, patchOperationError(i, err)
+			}
+		case "move", "copy":
+			from, err := parseJSONPointer(operation.From)
+			if err != nil {
+				return nil, patchOperationError(i, err)
+			}
+			if value, err = jsonPointerValue(document, from); err != nil {
+				return nil, patchOperationError(i, err)
+			}
+			if operation.Op == "copy" {
+				value = cloneJSONValue(value)
+			} else {
+				if strings.HasPrefix(operation.Path+"/", operation.From+"/") && operation.Path != operation.From {
+					return nil, patchOperationError(i, errors.New("can't move a value into itself"))
+				}
+				if document, err = jsonPointerUpdate(document, from, removeJSONValue); err != nil {
+					return nil, patchOperationError(i, err)
+				}
+			}
+		case "remove":
+		default:
+			return nil, patchOperationError(i, errors.New("op must be one of add, remove, replace, move, copy, test"))
+		}
+
+		switch operation.Op {
+		case "add", "move", "copy":
+			document, err = jsonPointerUpdate(document, path, func(container interface{}, token string) (interface{}, error) {
+				return addJSONValue(container, token, value)
+			})
+		case "remove":
+			document, err = jsonPointerUpdate(document, path, removeJSONValue)
+		case "replace":
+			document, err = jsonPointerUpdate(document, path, func(container interface{}, token string) (interface{}, error) {
+				return replaceJSONValue(container, token, value)
+			})
+		case "test":
+			var current interface{}
+			if current, err = jsonPointerValue(document, path); err == nil && !reflect.DeepEqual(current, value) {
+				return nil, errPatchTestFailed
+			}
+		}
+		if err != nil {
+			return nil, patchOperationError(i, err)
+		}
+	}
+
+	return document, nil
+}
+
+// name the failed operation in a patch error
+func patchOperationError(index int, err error) error {
+	return errors.New("operation " + strconv.Itoa(index) + ": " + err.Error())
+}
+
+// split a json pointer into its unescaped tokens ("" is the whole document)
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New("path " + strconv.Quote(pointer) + " must start with /")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// value a json pointer refers to
+func jsonPointerValue(document interface{}, path []string) (interface{}, error) {
+	node := document
+	for _, token := range path {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, errors.New("path /" + token + " not found")
+			}
+			node = value
+		case []interface{}:
+			index, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, errors.New("path /" + token + " not found")
+		}
+	}
+	return node, nil
+}
+
+// change the container holding the last token of a path, returns the changed document
+func jsonPointerUpdate(document interface{}, path []string, change func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+
+	if len(path) == 0 {
+		return change(nil, "")        // the whole document
+	}
+	if len(path) == 1 {
+		return change(document, path[0])
+	}
+
+	switch container := document.(type) {
+	case map[string]interface{}:
+		child, ok := container[path[0]]
+		if !ok {
+			return nil, errors.New("path /" + path[0] + " not found")
+		}
+		updated, err := jsonPointerUpdate(child, path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		container[path[0]] = updated
+		return container, nil
+	case []interface{}:
+		index, err := jsonArrayIndex(path[0], len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := jsonPointerUpdate(container[index], path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	}
+
+	return nil, errors.New("path /" + path[0] + " not found")
+}
+
+// add a member or insert an array element ("-" appends)
+func addJSONValue(container interface{}, token string, value interface{}) (interface{}, error) {
+	switch container := container.(type) {
+	case nil:
+		return value, nil        // replaces the whole document
+	case map[string]interface{}:
+		container[token] = value
+		return container, nil
+	case []interface{}:
+		index := len(container)
+		if token != "-" {
+			var err error
+			if index, err = jsonArrayIndex(token, len(container)); err != nil {
+				return nil, err
+			}
+		}
+		container = append(container, nil)
+		copy(container[index+1:], container[index:])
+		container[index] = value
+		return container, nil
+	}
+	return nil, errors.New("path /" + token + " not found")
+}
+
+// replace an existing member or array element
+func replaceJSONValue(container interface{}, token string, value interface{}) (interface{}, error) {
+	switch container := container.(type) {
+	case nil:
+		return value, nil        // replaces the whole document
+	case map[string]interface{}:
+		if _, ok := container[token]; !ok {
+			return nil, errors.New("path /" + token + " not found")
+		}
+		container[token] = value
+		return container, nil
+	case []interface{}:
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = value
+		return container, nil
+	}
+	return nil, errors.New("path /" + token + " not found")
+}
+
+// remove a member or an array element
+func removeJSONValue(container interface{}, token string) (interface{}, error) {
+	switch container := container.(type) {
+	case nil:
+		return nil, errors.New("the whole task can't be removed")
+	case map[string]interface{}:
+		if _, ok := container[token]; !ok {
+			return nil, errors.New("path /" + token + " not found")
+		}
+		delete(container, token)
+		return container, nil
+	case []interface{}:
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:index], container[index+1:]...), nil
+	}
+	return nil, errors.New("path /" + token + " not found")
+}
+
+// array index of a pointer token, at most max
+func jsonArrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.New("array index " + strconv.Quote(token) + " not found")
+	}
+	return index, nil
+}
+
+// deep copy of a decoded json value
+func cloneJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(value))
+		for key, member := range value {
+			clone[key] = cloneJSONValue(member)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(value))
+		for i, element := range value {
+			clone[i] = cloneJSONValue(element)
+		}
+		return clone
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package controllers
+
+// imports
+import (
+	"encoding/json";
+	"reflect";
+	"testing";
+)
+
+// decode a json literal of a test case
+func decodeJSON(t *testing.T, raw string) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		t.Fatalf("invalid test json %s: %v", raw, err)
+	}
+	return value
+}
+
+func TestMergePatch(t *testing.T) {
+
+	// cases from RFC 7386 appendix A
+	tests := []struct {
+		name    string
+		target  string
+		patch   string
+		want    string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null removes member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"null leaves other members", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"value replaces array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested objects merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are not merged", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"non-object patch replaces target", `{"a":"foo"}`, `["c"]`, `["c"]`},
+		{"null patch replaces target", `{"a":"foo"}`, `null`, `null`},
+		{"object patch replaces scalar target", `"foo"`, `{"a":"b"}`, `{"a":"b"}`},
+		{"null inside new object is dropped", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"task tags are replaced", `{"title":"Write report","tags":["work"]}`, `{"tags":["work","urgent"],"description":null}`, `{"title":"Write report","tags":["work","urgent"]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mergePatch(decodeJSON(t, test.target), decodeJSON(t, test.patch))
+			if want := decodeJSON(t, test.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch(%s, %s) = %v, want %v", test.target, test.patch, got, want)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+
+	// cases follow RFC 6902 appendix A
+	tests := []struct {
+		name      string
+		document  string
+		patch     string
+		want      string          // patched document (unused when an error is expected)
+		wantErr   error           // errPatchTestFailed, or nil with failed set for any other error
+		failed    bool
+	}{
+		{name: "add object member", document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz","value":"qux"}]`, want: `{"baz":"qux","foo":"bar"}`},
+		{name: "add array element", document: `{"foo":["bar","baz"]}`, patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`, want: `{"foo":["bar","qux","baz"]}`},
+		{name: "append array element", document: `{"tags":["work"]}`, patch: `[{"op":"add","path":"/tags/-","value":"urgent"}]`, want: `{"tags":["work","urgent"]}`},
+		{name: "add replaces existing member", document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/foo","value":1}]`, want: `{"foo":1}`},
+		{name: "add nested member", document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, want: `{"foo":"bar","child":{"grandchild":{}}}`},
+		{name: "remove object member", document: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"remove","path":"/baz"}]`, want: `{"foo":"bar"}`},
+		{name: "remove array element", document: `{"foo":["bar","qux","baz"]}`, patch: `[{"op":"remove","path":"/foo/1"}]`, want: `{"foo":["bar","baz"]}`},
+		{name: "replace value", document: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":"boo"}]`, want: `{"baz":"boo","foo":"bar"}`},
+		{name: "move value", document: `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, want: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{name: "move array element", document: `{"foo":["all","grass","cows","eat"]}`, patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, want: `{"foo":["all","cows","eat","grass"]}`},
+		{name: "copy value", document: `{"title":"a","description":"b"}`, patch: `[{"op":"copy","from":"/title","path":"/description"}]`, want: `{"title":"a","description":"a"}`},
+		{name: "copy is independent of its source", document: `{"a":{"b":1}}`, patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, want: `{"a":{"b":1},"c":{"b":2}}`},
+		{name: "test passes", document: `{"baz":"qux","foo":["a",2,"c"]}`, patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, want: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{name: "escaped pointer tokens", document: `{"a/b":1,"m~n":2}`, patch: `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, want: `{"a/b":3}`},
+		{name: "operations apply in order", document: `{"status":"pending"}`, patch: `[{"op":"replace","path":"/status","value":"completed"},{"op":"test","path":"/status","value":"completed"}]`, want: `{"status":"completed"}`},
+		{name: "empty patch", document: `{"foo":"bar"}`, patch: `[]`, want: `{"foo":"bar"}`},
+		{name: "test fails", document: `{"baz":"qux"}`, patch: `[{"op":"test","path":"/baz","value":"bar"}]`, wantErr: errPatchTestFailed},
+		{name: "test compares numbers by value", document: `{"estimate":10}`, patch: `[{"op":"test","path":"/estimate","value":"10"}]`, wantErr: errPatchTestFailed},
+		{name: "add to missing parent", document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`, failed: true},
+		{name: "replace missing member", document: `{"foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":"qux"}]`, failed: true},
+		{name: "remove missing member", document: `{"foo":"bar"}`, patch: `[{"op":"remove","path":"/baz"}]`, failed: true},
+		{name: "array index out of range", document: `{"foo":["bar"]}`, patch: `[{"op":"add","path":"/foo/2","value":"qux"}]`, failed: true},
+		{name: "array index with leading zero", document: `{"foo":["bar","baz"]}`, patch: `[{"op":"remove","path":"/foo/01"}]`, failed: true},
+		{name: "unknown op", document: `{"foo":"bar"}`, patch: `[{"op":"merge","path":"/foo","value":"x"}]`, failed: true},
+		{name: "value required", document: `{"foo":"bar"}`, patch: `[{"op":"replace","path":"/foo"}]`, failed: true},
+		{name: "path must start with slash", document: `{"foo":"bar"}`, patch: `[{"op":"remove","path":"foo"}]`, failed: true},
+		{name: "move into itself", document: `{"a":{"b":{}}}`, patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`, failed: true},
+		{name: "whole document can't be removed", document: `{"foo":"bar"}`, patch: `[{"op":"remove","path":""}]`, failed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var operations []jsonPatchOperation
+			if err := json.Unmarshal([]byte(test.patch), &operations); err != nil {
+				t.Fatalf("invalid test patch %s: %v", test.patch, err)
+			}
+
+			got, err := applyJSONPatch(decodeJSON(t, test.document), operations)
+			switch {
+			case test.wantErr != nil:
+				if err != test.wantErr {
+					t.Fatalf("applyJSONPatch error = %v, want %v", err, test.wantErr)
+				}
+			case test.failed:
+				if err == nil || err == errPatchTestFailed {
+					t.Fatalf("applyJSONPatch error = %v, want an operation error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("applyJSONPatch error = %v", err)
+				}
+				if want := decodeJSON(t, test.want); !reflect.DeepEqual(got, want) {
+					t.Errorf("applyJSONPatch = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
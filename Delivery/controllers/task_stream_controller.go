@@ -0,0 +1,103 @@
+package controllers
+
+// imports
+import (
+	"encoding/json";
+	"fmt";
+	"io";
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// server-sent event stream controller
+type TaskStreamController struct {
+	streamUseCase  usecases.TaskEventStreamUseCase        // logged task events to replay
+	eventBus       domain.TaskEventBus                    // wakes streams up when this instance stores a change
+	pollInterval   time.Duration                          // how often changes stored by other instances are read (and idle streams kept alive)
+}
+
+// new task stream controller
+func NewTaskStreamController(uc usecases.TaskEventStreamUseCase, bus domain.TaskEventBus, pollInterval time.Duration) *TaskStreamController {
+	return &TaskStreamController{streamUseCase: uc, eventBus: bus, pollInterval: pollInterval}        // return new task stream controller instance
+}
+
+func (streamContr *TaskStreamController) StreamTaskEvents(c *gin.Context) {
+
+	// resume after the last event the client saw (browsers send Last-Event-ID when they reconnect)
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID == "" {
+		lastEventID = primitive.NewObjectIDFromTimestamp(time.Now()).Hex()        // only changes from now on
+	}
+
+	// subscribe before the first read so no change falls between replay and live events
+	ctx := c.Request.Context()
+	wake, cancel := streamContr.eventBus.Subscribe(c.GetString("userID"))
+	defer cancel()
+
+	entries, err := streamContr.streamUseCase.GetEventsAfter(ctx, lastEventID)
+	if err != nil {
+		if err == domain.ErrInvalidEventID {
+			apierror.Respond(c, http.StatusBadRequest, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")        // proxies pass events on right away
+	c.Status(http.StatusOK)
+
+	ticker := time.NewTicker(streamContr.pollInterval)
+	defer ticker.Stop()
+	for {
+		for _, entry := range entries {
+			if err := writeTaskEvent(c.Writer, entry); err != nil {
+				return
+			}
+			lastEventID = entry.ID.Hex()
+		}
+		c.Writer.Flush()
+
+		// a full read means more events are waiting to be replayed
+		if len(entries) < domain.TaskEventLogBatch {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-wake:
+				if !ok {
+					return
+				}
+			case <-ticker.C:
+				// comment lines keep idle connections open through proxies
+				if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+		}
+
+		// clients reconnect with the last event id when the stream ends
+		if entries, err = streamContr.streamUseCase.GetEventsAfter(ctx, lastEventID); err != nil {
+			return
+		}
+	}
+}
+
+// write one event in text/event-stream format
+func writeTaskEvent(writer io.Writer, entry domain.TaskEventLogEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "id: %s\nevent: %s\ndata: %s\n\n", entry.ID.Hex(), entry.Type, data)
+	return err
+}
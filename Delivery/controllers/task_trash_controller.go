@@ -0,0 +1,110 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// task trash controller
+type TaskTrashController struct {
+	taskTrashUseCase  usecases.TaskTrashUseCase        // task trash usecase for trash listing, restore and purge
+	workflow          domain.TaskWorkflow              // allowed status changes advertised in restored task links
+}
+
+// new task trash controller
+func NewTaskTrashController(uc usecases.TaskTrashUseCase, workflow domain.TaskWorkflow) *TaskTrashController {
+	return &TaskTrashController{taskTrashUseCase: uc, workflow: workflow}        // return new task trash controller instance
+}
+
+func (trashContr *TaskTrashController) GetDeletedTasks(c *gin.Context) {
+
+	// parse paging from query parameters (?before=...&limit=...)
+	before, ok := trashBefore(c)
+	if !ok {
+		return
+	}
+	var limit int64
+	var err error
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.ParseInt(raw, 10, 64); err != nil || limit <= 0 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	// get deleted tasks through usecase layer
+	tasks, err := trashContr.taskTrashUseCase.GetDeletedTasks(c.Request.Context(), before, limit)
+	if err != nil {
+		taskTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)       // return deleted tasks, newest first
+}
+
+func (trashContr *TaskTrashController) RestoreTask(c *gin.Context) {
+
+	// restore task through usecase layer
+	task, err := trashContr.taskTrashUseCase.RestoreTask(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		taskTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, taskResource(c, *task, trashContr.workflow))       // return the restored task
+}
+
+func (trashContr *TaskTrashController) PurgeTrash(c *gin.Context) {
+
+	// everything in the trash unless ?before= narrows it down
+	before, ok := trashBefore(c)
+	if !ok {
+		return
+	}
+	if before.IsZero() {
+		before = time.Now().UTC()
+	}
+
+	// purge deleted tasks through usecase layer
+	purged, err := trashContr.taskTrashUseCase.PurgeTrash(c.Request.Context(), before)
+	if err != nil {
+		taskTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purged": purged})       // return how many tasks were removed for good
+}
+
+// parse the optional before query parameter (false after answering a bad value)
+func trashBefore(c *gin.Context) (time.Time, bool) {
+	raw := c.Query("before")
+	if raw == "" {
+		return time.Time{}, true
+	}
+	before, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		apierror.Message(c, http.StatusBadRequest, "before must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+		return time.Time{}, false
+	}
+	return before, true
+}
+
+// map task trash errors to responses
+func taskTrashError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrDeletedTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrInvalidTaskID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,32 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// telemetry controller
+type TelemetryController struct {
+	telemetryUseCase  usecases.TelemetryUseCase        // telemetry usecase for building usage reports
+	enabled           bool                             // operator opted in to sending reports
+}
+
+// new telemetry controller
+func NewTelemetryController(uc usecases.TelemetryUseCase, enabled bool) *TelemetryController {
+	return &TelemetryController{telemetryUseCase: uc, enabled: enabled}        // return new telemetry controller instance
+}
+
+func (telemetryContr *TelemetryController) Preview(c *gin.Context) {
+
+	// build report through usecase layer (same report the scheduler sends)
+	report, err := telemetryContr.telemetryUseCase.BuildReport(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enabled": telemetryContr.enabled, "report": report})       // exactly what would be sent
+}
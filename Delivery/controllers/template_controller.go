@@ -0,0 +1,142 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// template controller
+type TemplateController struct {
+	templateUseCase usecases.TemplateUseCase        // template usecase for template operations
+	workflow        domain.TaskWorkflow             // transitions linked from created tasks
+}
+
+// new template controller
+func NewTemplateController(uc usecases.TemplateUseCase, workflow domain.TaskWorkflow) *TemplateController {
+	return &TemplateController{templateUseCase: uc, workflow: workflow}        // return new template controller instance
+}
+
+func (templateContr *TemplateController) CreateTemplate(c *gin.Context) {
+
+	var template domain.TaskTemplate
+	if !bindJSON(c, &template) {       // parse and validate request body
+		return
+	}
+
+	// create template through usecase layer
+	created, err := templateContr.templateUseCase.CreateTemplate(c.Request.Context(), &template)
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)        // return created template with 201 status
+}
+
+func (templateContr *TemplateController) SaveTaskAsTemplate(c *gin.Context) {
+
+	// copy task into a template through usecase layer
+	created, err := templateContr.templateUseCase.SaveTaskAsTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)        // return created template with 201 status
+}
+
+func (templateContr *TemplateController) GetTemplates(c *gin.Context) {
+
+	// get templates through usecase layer
+	templates, err := templateContr.templateUseCase.GetTemplates(c.Request.Context())
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)       // return own templates
+}
+
+func (templateContr *TemplateController) GetTemplateByID(c *gin.Context) {
+
+	// get template through usecase layer
+	template, err := templateContr.templateUseCase.GetTemplateByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)       // return found template
+}
+
+func (templateContr *TemplateController) UpdateTemplate(c *gin.Context) {
+
+	var template domain.TaskTemplate
+	if !bindJSON(c, &template) {       // parse and validate request body
+		return
+	}
+
+	// update template through usecase layer
+	updated, err := templateContr.templateUseCase.UpdateTemplate(c.Request.Context(), c.Param("id"), &template)
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)       // return updated template
+}
+
+func (templateContr *TemplateController) DeleteTemplate(c *gin.Context) {
+
+	// delete template through usecase layer
+	err := templateContr.templateUseCase.DeleteTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted successfully"})       // success response
+}
+
+func (templateContr *TemplateController) CreateTaskFromTemplate(c *gin.Context) {
+
+	var req domain.TaskFromTemplateRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// create task through usecase layer
+	task, err := templateContr.templateUseCase.CreateTaskFromTemplate(c.Request.Context(), c.Param("templateId"), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrRejectedByExtension) || err == domain.ErrProjectAccessDenied {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, taskResource(c, *task, templateContr.workflow))        // return created task with 201 status
+}
+
+// map template errors to responses
+func templateError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrTemplateNotFound, domain.ErrTaskNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrInvalidTemplateID, domain.ErrInvalidTaskID, domain.ErrLabelNotFound, domain.ErrProjectNotFound, domain.ErrInvalidProjectID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+func (uc *UserController) SetupTwoFactor(c *gin.Context) {
+
+	// generate a new secret through usecase layer
+	setup, err := uc.userUseCase.SetupTwoFactor(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		twoFactorFailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)       // success response
+}
+
+func (uc *UserController) EnableTwoFactor(c *gin.Context) {
+
+	var req domain.TwoFactorCodeRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// confirm the secret through usecase layer
+	backup, err := uc.userUseCase.EnableTwoFactor(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		twoFactorFailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backup)       // success response, backup codes are never shown again
+}
+
+func (uc *UserController) DisableTwoFactor(c *gin.Context) {
+
+	var req domain.TwoFactorCodeRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// turn off two-factor logins through usecase layer
+	if err := uc.userUseCase.DisableTwoFactor(c.Request.Context(), c.GetString("userID"), req.Code); err != nil {
+		twoFactorFailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})       // success response
+}
+
+func (uc *UserController) CompleteTwoFactorLogin(c *gin.Context) {
+
+	var req domain.TwoFactorLoginRequest
+	if !bindJSON(c, &req) {       // parse and validate request body
+		return
+	}
+
+	// finish the login through usecase layer
+	token, user, err := uc.userUseCase.CompleteTwoFactorLogin(c.Request.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		if err == domain.ErrInvalidTwoFactorCode || err == domain.ErrInvalidTwoFactorChallenge {
+			apierror.Respond(c, http.StatusUnauthorized, err)
+			return
+		}
+		if err == domain.ErrAccountDeactivated || errors.Is(err, domain.ErrRejectedByExtension) {
+			apierror.Respond(c, http.StatusForbidden, err)
+			return
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	// same answer as a password login
+	c.JSON(http.StatusOK, gin.H{
+		"token": token,
+		"user": gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"role":     user.Role,
+		},
+	})
+}
+
+// respond with the challenge when a login still needs a code (false for other errors)
+func twoFactorRequired(c *gin.Context, err error) bool {
+	var required *domain.TwoFactorRequiredError
+	if !errors.As(err, &required) {
+		return false
+	}
+	c.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge_token": required.ChallengeToken, "expires_at": required.ExpiresAt})
+	return true
+}
+
+// map two-factor settings errors to status codes
+func twoFactorFailed(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrInvalidUserID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUserNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrTwoFactorEnabled, domain.ErrTwoFactorNotSetUp, domain.ErrTwoFactorNotEnabled:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrInvalidTwoFactorCode:
+		apierror.Respond(c, http.StatusUnprocessableEntity, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,149 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"strconv";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// webhook controller
+type WebhookController struct {
+	webhookUseCase usecases.WebhookUseCase        // webhook usecase for webhook and delivery log operations
+}
+
+// new webhook controller
+func NewWebhookController(uc usecases.WebhookUseCase) *WebhookController {
+	return &WebhookController{webhookUseCase: uc}        // return new webhook controller instance
+}
+
+func (webhookContr *WebhookController) CreateWebhook(c *gin.Context) {
+
+	var input domain.WebhookInput
+	if !bindJSON(c, &input) {       // parse and validate request body
+		return
+	}
+
+	// create webhook through usecase layer
+	webhook, err := webhookContr.webhookUseCase.CreateWebhook(c.Request.Context(), &input)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)        // return created webhook with its secret, shown only once
+}
+
+func (webhookContr *WebhookController) GetWebhooks(c *gin.Context) {
+
+	// get webhooks through usecase layer
+	webhooks, err := webhookContr.webhookUseCase.GetWebhooks(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)       // return all webhooks
+}
+
+func (webhookContr *WebhookController) GetWebhook(c *gin.Context) {
+
+	// get webhook through usecase layer
+	webhook, err := webhookContr.webhookUseCase.GetWebhook(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)       // return found webhook
+}
+
+func (webhookContr *WebhookController) UpdateWebhook(c *gin.Context) {
+
+	var input domain.WebhookInput
+	if !bindJSON(c, &input) {       // parse and validate request body
+		return
+	}
+
+	// update webhook through usecase layer
+	webhook, err := webhookContr.webhookUseCase.UpdateWebhook(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)       // return updated webhook
+}
+
+func (webhookContr *WebhookController) DeleteWebhook(c *gin.Context) {
+
+	// delete webhook through usecase layer
+	if err := webhookContr.webhookUseCase.DeleteWebhook(c.Request.Context(), c.Param("id")); err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted successfully"})       // success response
+}
+
+func (webhookContr *WebhookController) GetDeliveries(c *gin.Context) {
+
+	// parse paging from query parameters (?before=...&limit=...)
+	var before time.Time
+	var limit int64
+	var err error
+	if raw := c.Query("before"); raw != "" {
+		if before, err = time.Parse(time.RFC3339, raw); err != nil {
+			apierror.Message(c, http.StatusBadRequest, "before must be an ISO 8601 date like 2025-07-22T00:00:00Z")
+			return
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.ParseInt(raw, 10, 64); err != nil || limit <= 0 {
+			apierror.Message(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	// get delivery log through usecase layer
+	deliveries, err := webhookContr.webhookUseCase.GetDeliveries(c.Request.Context(), c.Param("id"), before, limit)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)       // return deliveries, newest first
+}
+
+func (webhookContr *WebhookController) SendTestEvent(c *gin.Context) {
+
+	// deliver test event through usecase layer
+	delivery, err := webhookContr.webhookUseCase.SendTestEvent(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)       // return the delivery, failed ones too
+}
+
+// map webhook errors to responses
+func webhookError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrWebhookNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrInvalidWebhookID, domain.ErrInvalidWebhook:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,90 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"golang.org/x/net/websocket";
+)
+
+// task event message sent to websocket clients
+type taskEventMessage struct {
+	Type       string          `json:"type"`                // event type (task.created, task.updated, task.deleted, ...)
+	TaskID     string          `json:"task_id"`             // task the event is about
+	Task       *domain.Task    `json:"task,omitempty"`      // task after the change (omitted on delete and archive)
+	Timestamp  time.Time       `json:"timestamp"`           // when the change was stored (UTC)
+}
+
+// websocket controller
+type WebSocketController struct {
+	streamUseCase  usecases.TaskEventStreamUseCase        // logged task events the caller may see
+	eventBus       domain.TaskEventBus                    // wakes connections up when this instance stores a change
+	pollInterval   time.Duration                          // how often changes stored by other instances are read
+}
+
+// new websocket controller
+func NewWebSocketController(uc usecases.TaskEventStreamUseCase, bus domain.TaskEventBus, pollInterval time.Duration) *WebSocketController {
+	return &WebSocketController{streamUseCase: uc, eventBus: bus, pollInterval: pollInterval}        // return new websocket controller instance
+}
+
+func (wsContr *WebSocketController) TaskEvents(c *gin.Context) {
+
+	// events are read through the event log, which only returns changes of the caller's organization and projects
+	ctx := c.Request.Context()
+	lastEventID := primitive.NewObjectIDFromTimestamp(time.Now()).Hex()        // only changes from now on
+
+	// the request is already authenticated, so any origin may connect with a valid token
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+
+			// the bus only says that something changed, the event itself is read with the caller's scope
+			wake, cancel := wsContr.eventBus.Subscribe(c.GetString("userID"))
+			defer cancel()
+
+			// clients only listen, reading just notices when they disconnect
+			go func() {
+				var discard string
+				for websocket.Message.Receive(conn, &discard) == nil {
+				}
+				cancel()
+			}()
+
+			ticker := time.NewTicker(wsContr.pollInterval)
+			defer ticker.Stop()
+			for {
+				entries, err := wsContr.streamUseCase.GetEventsAfter(ctx, lastEventID)
+				if err != nil {
+					return
+				}
+				for _, entry := range entries {
+					message := taskEventMessage{Type: entry.Type, TaskID: entry.TaskID, Task: entry.Task, Timestamp: entry.CreatedAt}
+					if err := websocket.JSON.Send(conn, message); err != nil {
+						return
+					}
+					lastEventID = entry.ID.Hex()
+				}
+
+				// a full read means more events are waiting
+				if len(entries) == domain.TaskEventLogBatch {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-wake:
+					if !ok {
+						return
+					}
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
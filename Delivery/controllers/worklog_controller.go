@@ -0,0 +1,107 @@
+package controllers
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// worklog controller
+type WorklogController struct {
+	worklogUseCase usecases.WorklogUseCase        // worklog usecase for timer and worklog operations
+}
+
+// new worklog controller
+func NewWorklogController(uc usecases.WorklogUseCase) *WorklogController {
+	return &WorklogController{worklogUseCase: uc}        // return new worklog controller instance
+}
+
+func (worklogContr *WorklogController) StartTimer(c *gin.Context) {
+
+	// start timer through usecase layer
+	worklog, err := worklogContr.worklogUseCase.StartTimer(c.Request.Context(), c.Param("id"))
+	if err == domain.ErrTimerRunning && worklog != nil {
+		apierror.RespondWith(c, http.StatusConflict, err, gin.H{"worklog": worklog})       // clients can stop the running timer first
+		return
+	}
+	if err != nil {
+		worklogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, worklog)       // return the running timer
+}
+
+func (worklogContr *WorklogController) StopTimer(c *gin.Context) {
+
+	// stop timer through usecase layer
+	worklog, err := worklogContr.worklogUseCase.StopTimer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		worklogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, worklog)       // return the finished worklog
+}
+
+func (worklogContr *WorklogController) AddWorklog(c *gin.Context) {
+
+	var input domain.WorklogInput
+	if !bindJSON(c, &input) {       // parse and validate request body
+		return
+	}
+
+	// add worklog through usecase layer
+	worklog, err := worklogContr.worklogUseCase.AddWorklog(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		worklogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, worklog)       // return the recorded worklog
+}
+
+func (worklogContr *WorklogController) GetWorklogs(c *gin.Context) {
+
+	// get worklogs through usecase layer
+	worklogs, err := worklogContr.worklogUseCase.GetWorklogs(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		worklogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, worklogs)       // return worklogs with the time spent
+}
+
+func (worklogContr *WorklogController) DeleteWorklog(c *gin.Context) {
+
+	// delete worklog through usecase layer
+	if err := worklogContr.worklogUseCase.DeleteWorklog(c.Request.Context(), c.Param("id"), c.Param("worklogId")); err != nil {
+		worklogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "worklog deleted"})       // success response
+}
+
+// map worklog errors to responses
+func worklogError(c *gin.Context, err error) {
+	if validationFailed(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrTaskNotFound, domain.ErrWorklogNotFound:
+		apierror.Respond(c, http.StatusNotFound, err)
+	case domain.ErrTimerRunning, domain.ErrTimerNotRunning:
+		apierror.Respond(c, http.StatusConflict, err)
+	case domain.ErrInvalidTaskID, domain.ErrInvalidWorklogID:
+		apierror.Respond(c, http.StatusBadRequest, err)
+	case domain.ErrUnauthorized:
+		apierror.Respond(c, http.StatusUnauthorized, err)
+	default:
+		apierror.Abort(c, err)
+	}
+}
@@ -0,0 +1,72 @@
+package controllers
+
+// imports
+import (
+	"encoding/json";
+	"fmt";
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+const maxWorkspaceImportSize = 256 << 20        // largest accepted workspace bundle (256 MB)
+
+// workspace migration controller
+type WorkspaceController struct {
+	workspaceUseCase usecases.WorkspaceUseCase        // workspace usecase for exports and imports
+}
+
+// new workspace controller
+func NewWorkspaceController(uc usecases.WorkspaceUseCase) *WorkspaceController {
+	return &WorkspaceController{workspaceUseCase: uc}        // return new workspace controller instance
+}
+
+func (workspaceContr *WorkspaceController) ExportWorkspace(c *gin.Context) {
+
+	// accounts only on request (?users=true), they carry password hashes
+	opts := domain.WorkspaceExportOptions{IncludeUsers: c.Query("users") == "true"}
+
+	// export through usecase layer
+	bundle, err := workspaceContr.workspaceUseCase.ExportWorkspace(c.Request.Context(), opts)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "workspace-"+bundle.ExportedAt.Format("20060102-150405")+".json"))
+	c.JSON(http.StatusOK, bundle)       // return bundle as download
+}
+
+func (workspaceContr *WorkspaceController) ImportWorkspace(c *gin.Context) {
+
+	// bundle is the raw request body
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWorkspaceImportSize)
+	var bundle domain.WorkspaceBundle
+	if err := json.NewDecoder(c.Request.Body).Decode(&bundle); err != nil {
+		if _, tooLarge := err.(*http.MaxBytesError); tooLarge {
+			apierror.Message(c, http.StatusRequestEntityTooLarge, "workspace bundle is too large")
+			return
+		}
+		apierror.Message(c, http.StatusBadRequest, "invalid workspace bundle: " + err.Error())
+		return
+	}
+
+	// import through usecase layer
+	opts := domain.WorkspaceImportOptions{IncludeUsers: c.Query("users") == "true"}
+	report, err := workspaceContr.workspaceUseCase.ImportWorkspace(c.Request.Context(), &bundle, opts)
+	if err != nil {
+		switch err {
+		case domain.ErrUnsupportedBundle, domain.ErrInvalidPriority:
+			apierror.Respond(c, http.StatusBadRequest, err)
+		case domain.ErrWorkspaceUserConflict, domain.ErrWorkspaceOwnerNeeded:
+			apierror.Respond(c, http.StatusConflict, err)
+		default:
+			apierror.Abort(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)       // counts of created and matched records
+}
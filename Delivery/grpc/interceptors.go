@@ -0,0 +1,129 @@
+package grpc
+
+// imports
+import (
+	"context";
+	"errors";
+	"strings";
+	"time";
+	"google.golang.org/grpc";
+	"google.golang.org/grpc/codes";
+	"google.golang.org/grpc/metadata";
+	"google.golang.org/grpc/status";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+)
+
+type principalKey struct{}
+
+var errNoPrincipal = errors.New("call has no authenticated user")
+
+// authenticated caller of the call (set by AuthInterceptor)
+func principalFromContext(ctx context.Context) (*infrastructure.Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(*infrastructure.Principal)
+	if !ok {
+		return nil, errNoPrincipal
+	}
+	return principal, nil
+}
+
+// logging interceptor (one line per call with status code, latency and user)
+func LoggingInterceptor(logger domain.Logger) UnaryInterceptor {
+	return func(ctx context.Context, call *Call, req interface{}, next grpc.UnaryHandler) (interface{}, error) {
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+
+		callStatus := status.Convert(err)
+		args := []interface{}{
+			"method", call.Method,
+			"code", int(callStatus.Code()),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", call.Peer,
+		}
+		if err != nil {
+			args = append(args, "error", callStatus.Message())
+		}
+
+		switch callStatus.Code() {
+		case codes.OK:
+			logger.Info(ctx, "grpc call", args...)
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DeadlineExceeded:
+			logger.Error(ctx, "grpc call", args...)
+		default:
+			logger.Warn(ctx, "grpc call", args...)
+		}
+		return resp, err
+	}
+}
+
+// auth interceptor, same tokens and rules as the http auth middleware (jwt, personal access tokens, sessions)
+func AuthInterceptor(authMiddleware *infrastructure.AuthMiddleWare) UnaryInterceptor {
+	return func(ctx context.Context, call *Call, req interface{}, next grpc.UnaryHandler) (interface{}, error) {
+
+		if call.Access.Public {
+			return next(ctx, req)
+		}
+
+		// reject if empty
+		tokenStr := bearerToken(call.Metadata)
+		if tokenStr == "" {
+			return nil, status.Errorf(codes.Unauthenticated, "authorization metadata required")
+		}
+
+		principal, err := authMiddleware.Authenticate(ctx, tokenStr, call.Method)
+		switch err {
+		case nil:
+		case domain.ErrUnauthorized:
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+		case domain.ErrSessionRevoked:
+			return nil, status.Errorf(codes.Unauthenticated, "%s", err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "%s", err.Error())
+		}
+
+		// block if the token lacks the method's permission or scope
+		if call.Access.Permission != "" && !domain.HasPermission(principal.Permissions, call.Access.Permission) {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied, requires %s", call.Access.Permission)
+		}
+		if principal.Scoped && call.Access.FirstPartyOnly {
+			return nil, status.Errorf(codes.PermissionDenied, "method not available to third-party tokens")
+		}
+		if principal.Scoped && call.Access.Scope != "" && !hasScope(principal.Scopes, call.Access.Scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s, requires %s", domain.ErrInsufficientScope.Error(), call.Access.Scope)
+		}
+
+		// make the actor available to usecases for auditing
+		ctx = domain.ContextWithActor(ctx, domain.Actor{ID: principal.UserID, Username: principal.Username, Role: principal.Role, TenantID: principal.TenantID, SessionID: principal.SessionID})
+		ctx = context.WithValue(ctx, principalKey{}, principal)
+		return next(ctx, req)
+	}
+}
+
+// read only interceptor (rejects writes when the instance runs in read-only mode)
+func ReadOnlyInterceptor(enabled bool) UnaryInterceptor {
+	return func(ctx context.Context, call *Call, req interface{}, next grpc.UnaryHandler) (interface{}, error) {
+		if enabled && call.Access.Write {
+			return nil, status.Errorf(codes.Unavailable, "instance is in read-only mode, writes are disabled")
+		}
+		return next(ctx, req)
+	}
+}
+
+// bearer or raw token from the authorization metadata
+func bearerToken(md metadata.MD) string {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package grpc
+
+// imports
+import (
+	"context";
+	"errors";
+	"google.golang.org/grpc/codes";
+	"google.golang.org/grpc/status";
+)
+
+// status error of an error returned by a handler (errors without a status are internal)
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err        // nil or already a status
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
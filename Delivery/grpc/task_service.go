@@ -0,0 +1,247 @@
+package grpc
+
+// imports
+import (
+	"context";
+	"encoding/json";
+	"errors";
+	"strings";
+	"time";
+	"github.com/gin-gonic/gin/binding";
+	"google.golang.org/grpc/codes";
+	"google.golang.org/grpc/status";
+	"google.golang.org/protobuf/types/known/timestamppb";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// task service (same usecase and rules as the task controller)
+type taskService struct {
+	UnimplementedTaskServiceServer
+	taskUseCase usecases.TaskUseCase
+}
+
+// register taskmanager.v1.TaskService on the server
+func RegisterTaskService(server *Server, uc usecases.TaskUseCase) {
+
+	read := Access{Permission: domain.PermissionTaskRead, Scope: domain.ScopeReadTasks}
+	write := Access{Permission: domain.PermissionTaskWrite, Scope: domain.ScopeWriteTasks, Write: true}
+
+	server.allow(TaskService_ListTasks_FullMethodName, read)
+	server.allow(TaskService_GetTask_FullMethodName, read)
+	server.allow(TaskService_CreateTask_FullMethodName, write)
+	server.allow(TaskService_UpdateTask_FullMethodName, write)
+	server.allow(TaskService_DeleteTask_FullMethodName, write)
+	RegisterTaskServiceServer(server, &taskService{taskUseCase: uc})
+}
+
+func (taskServ *taskService) ListTasks(ctx context.Context, req *ListTasksRequest) (*ListTasksResponse, error) {
+
+	query := domain.TaskQuery{Overdue: req.Overdue, MatchAllLabels: req.MatchAllLabels}
+	for _, part := range strings.Split(req.Sort, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			query.Sort = append(query.Sort, domain.SortField{Field: strings.TrimPrefix(part, "-"), Descending: strings.HasPrefix(part, "-")})
+		}
+	}
+	for _, label := range req.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			query.Labels = append(query.Labels, label)
+		}
+	}
+
+	// get all tasks through usecase layer
+	tasks, err := taskServ.taskUseCase.GetAllTasks(ctx, query)
+	if err != nil {
+		return nil, taskError(err, codes.Internal)        // partial results are not returned, the call fails with DEADLINE_EXCEEDED
+	}
+
+	resp := &ListTasksResponse{Tasks: make([]*Task, 0, len(tasks))}
+	for i := range tasks {
+		resp.Tasks = append(resp.Tasks, taskMessage(&tasks[i]))
+	}
+	return resp, nil
+}
+
+func (taskServ *taskService) GetTask(ctx context.Context, req *GetTaskRequest) (*Task, error) {
+
+	if _, err := primitive.ObjectIDFromHex(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid task ID format")
+	}
+
+	// get specific task through usecase layer
+	task, err := taskServ.taskUseCase.GetTaskByID(ctx, req.Id)
+	if err != nil {
+		return nil, taskError(err, codes.Internal)
+	}
+	return taskMessage(task), nil
+}
+
+func (taskServ *taskService) CreateTask(ctx context.Context, req *CreateTaskRequest) (*Task, error) {
+
+	create := domain.CreateTaskRequest{
+		Title:        req.Title,
+		Description:  req.Description,
+		DueDate:      timeOf(req.DueDate),
+		Status:       req.Status,
+		Priority:     req.Priority,
+		Estimate:     int(req.Estimate),
+		Tags:         req.Tags,
+		ClientID:     req.ClientId,
+	}
+	parentID, err := parseParentID(req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	create.ParentID = parentID
+	if err := validate(&create); err != nil {       // same rules as the json payload
+		return nil, err
+	}
+
+	// create task through usecase layer
+	created, _, err := taskServ.taskUseCase.CreateTask(ctx, create.ToTask())       // a retried client id returns the task created first
+	if err != nil {
+		return nil, taskError(err, codes.InvalidArgument)
+	}
+	return taskMessage(created), nil
+}
+
+func (taskServ *taskService) UpdateTask(ctx context.Context, req *UpdateTaskRequest) (*UpdateTaskResponse, error) {
+
+	if _, err := primitive.ObjectIDFromHex(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid task ID format")
+	}
+
+	task := domain.Task{
+		Title:        req.Title,
+		Description:  req.Description,
+		DueDate:      timeOf(req.DueDate),
+		Status:       req.Status,
+		Priority:     req.Priority,
+		Estimate:     int(req.Estimate),
+		Tags:         req.Tags,
+	}
+	if req.ReplaceTags && task.Tags == nil {
+		task.Tags = []string{}       // clear labels
+	}
+	parentID, err := parseParentID(req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	task.ParentID = parentID
+	if err := validate(&task); err != nil {       // same rules as the json payload
+		return nil, err
+	}
+
+	// update task through usecase layer
+	updated, changes, err := taskServ.taskUseCase.UpdateTask(ctx, req.Id, domain.NewTaskUpdate(&task))
+	if err != nil {
+		return nil, taskError(err, codes.InvalidArgument)
+	}
+
+	resp := &UpdateTaskResponse{Task: taskMessage(updated), Changes: make([]*TaskFieldChange, 0, len(changes))}
+	for _, change := range changes {
+		oldValue, _ := json.Marshal(change.OldValue)
+		newValue, _ := json.Marshal(change.NewValue)
+		resp.Changes = append(resp.Changes, &TaskFieldChange{Field: change.Field, OldValue: string(oldValue), NewValue: string(newValue)})
+	}
+	return resp, nil
+}
+
+func (taskServ *taskService) DeleteTask(ctx context.Context, req *DeleteTaskRequest) (*DeleteTaskResponse, error) {
+
+	if _, err := primitive.ObjectIDFromHex(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid task ID format")
+	}
+
+	// delete task through usecase layer
+	if err := taskServ.taskUseCase.DeleteTask(ctx, req.Id, req.Cascade); err != nil {
+		return nil, taskError(err, codes.Internal)
+	}
+	return &DeleteTaskResponse{}, nil
+}
+
+// task as protobuf message
+func taskMessage(task *domain.Task) *Task {
+	msg := &Task{
+		Id:           task.ID.Hex(),
+		Title:        task.Title,
+		Description:  task.Description,
+		DueDate:      timestampOf(task.DueDate),
+		Status:       task.Status,
+		Priority:     task.Priority,
+		Estimate:     int32(task.Estimate),
+		IsOverdue:    task.IsOverdue,
+		Tags:         task.Tags,
+		ClientId:     task.ClientID,
+	}
+	if task.ParentID != nil {
+		msg.ParentId = task.ParentID.Hex()
+	}
+	return msg
+}
+
+// time of an optional timestamp (zero when unset)
+func timeOf(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return timestamp.AsTime()
+}
+
+// timestamp of a time, unset when zero
+func timestampOf(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optional parent task id
+func parseParentID(raw string) (*primitive.ObjectID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parentID, err := primitive.ObjectIDFromHex(raw)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parent_id: must be a task id")
+	}
+	return &parentID, nil
+}
+
+// validate request with the binding rules of the http payloads
+func validate(obj interface{}) error {
+
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+	messages := []string{}
+	for _, fieldErr := range infrastructure.BindingErrors(err) {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return status.Errorf(codes.InvalidArgument, "%s", strings.Join(messages, "; "))
+}
+
+// map task errors to status codes like the task controller, other errors get the fallback code
+func taskError(err error, fallback codes.Code) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return err
+	case err == domain.ErrTaskNotFound, err == domain.ErrProjectNotFound:
+		return status.Errorf(codes.NotFound, "%s", err.Error())
+	case err == domain.ErrInvalidTaskID, err == domain.ErrInvalidSortField:
+		return status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	case errors.Is(err, domain.ErrValidation):
+		return status.Errorf(codes.InvalidArgument, "%s", err.Error())       // same "field: message; ..." text as request validation
+	case err == domain.ErrTaskHasSubtasks, errors.Is(err, domain.ErrTaskLocked), errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrTaskBlocked), err == domain.ErrTaskModified:
+		return status.Errorf(codes.FailedPrecondition, "%s", err.Error())
+	case err == domain.ErrPartialResult:
+		return status.Errorf(codes.DeadlineExceeded, "%s", err.Error())
+	case errors.Is(err, domain.ErrRejectedByExtension), err == domain.ErrProjectAccessDenied:
+		return status.Errorf(codes.PermissionDenied, "%s", err.Error())
+	default:
+		return status.Errorf(fallback, "%s", err.Error())
+	}
+}
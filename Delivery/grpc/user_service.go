@@ -0,0 +1,81 @@
+package grpc
+
+// imports
+import (
+	"context";
+	"errors";
+	"google.golang.org/grpc/codes";
+	"google.golang.org/grpc/status";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+)
+
+// user service (same usecase and rules as the user controller)
+type userService struct {
+	UnimplementedUserServiceServer
+	userUseCase usecases.UserUseCase
+}
+
+// register taskmanager.v1.UserService on the server
+func RegisterUserService(server *Server, uc usecases.UserUseCase) {
+
+	server.allow(UserService_Login_FullMethodName, Access{Public: true})
+	server.allow(UserService_GetProfile_FullMethodName, Access{FirstPartyOnly: true})
+	RegisterUserServiceServer(server, &userService{userUseCase: uc})
+}
+
+func (userServ *userService) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+
+	creds := domain.Credentials{Username: req.Username, Password: req.Password}
+	if err := validate(&creds); err != nil {
+		return nil, err
+	}
+
+	// authenticate user through usecase layer
+	token, user, err := userServ.userUseCase.Login(ctx, &creds)
+	if err != nil {
+		switch {
+		case err == domain.ErrInvalidCredentials:
+			return nil, status.Errorf(codes.Unauthenticated, "%s", err.Error())
+		case err == domain.ErrAccountLocked:
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err.Error())
+		case errors.As(err, new(*domain.TwoFactorRequiredError)):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s, sign in over http", err.Error())        // the second step has no rpc
+		case errors.Is(err, domain.ErrRejectedByExtension):
+			return nil, status.Errorf(codes.PermissionDenied, "%s", err.Error())
+		}
+		return nil, err
+	}
+
+	return &LoginResponse{Token: token, User: userMessage(user)}, nil
+}
+
+func (userServ *userService) GetProfile(ctx context.Context, req *GetProfileRequest) (*User, error) {
+
+	principal, err := principalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get own profile through usecase layer
+	user, err := userServ.userUseCase.GetProfile(ctx, principal.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+		return nil, err
+	}
+
+	return userMessage(user), nil
+}
+
+// user as protobuf message (excluding sensitive data)
+func userMessage(user *domain.User) *User {
+	return &User{
+		Id:           user.ID.Hex(),
+		Username:     user.Username,
+		Email:        user.Email,
+		DisplayName:  user.DisplayName,
+		Role:         user.Role,
+	}
+}
@@ -3,46 +3,312 @@ package main
 // imports
 import (
 	"context";
+	"flag";
 	"log";
+	"os";
+	"os/signal";
+	"syscall";
 	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Delivery/grpc";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Delivery/routers";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Migrations";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
-	"go.mongodb.org/mongo-driver/mongo";
-	"go.mongodb.org/mongo-driver/mongo/options";
+	"go.mongodb.org/mongo-driver/mongo/readpref";
 )
 
+// application version (set at build time with -ldflags "-X main.version=...")
+var version = "dev"
+
 // entry point of the Task Management application
 func main() {
 
-	// setup mongodb
+	config := infrastructure.LoadConfig()        // load configuration from .env or environment
+	flag.StringVar(&config.Storage, "storage", config.Storage, "where data is kept (mongo/memory)")
+	migrateOnly := flag.Bool("migrate", false, "create indexes, apply pending migrations and exit")
+	seed := flag.Bool("seed", false, "create an admin account and sample tasks in an empty database")
+	flag.Parse()
+	logger := infrastructure.NewLogger(config)   // setup structured logger
+	memoryStorage := config.Storage == infrastructure.StorageMemory
+
+	// setup storage
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)       // set timeout
 	defer cancel()
 
-	// connect
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	queryLog := infrastructure.NewQueryLogger(logger, config.QueryLogMaxWindow)
+	var store *storage
+	if memoryStorage {
+		store = newMemoryStorage()       // nothing to connect to
+	} else {
+		var err error
+		if store, err = openMongoStorage(ctx, config, queryLog, logger); err != nil {
+			log.Fatal(err)
+		}
+	}
+	defer store.close(ctx)       // disconnect
+
+	jwtservice, _ := infrastructure.NewJWTService()              // setup jwt service infrastructure
+	passwordService := infrastructure.NewPasswordService(config)       // setup password service infrastructure
+	auditSink := infrastructure.NewAuditSink(config, logger)     // setup audit sink infrastructure
+	errorReporter := infrastructure.NewErrorReporter(config, logger)     // setup error reporter infrastructure
+	eventBus := infrastructure.NewTaskEventBus(logger)                   // setup task event bus infrastructure
+	domainEvents := infrastructure.NewEventBus(config, logger)           // setup domain event bus infrastructure
+	extensions := infrastructure.NewExtensionHooks(config, logger)       // setup extension hooks infrastructure
+
+	taskRepo, taskReader, userRepo := store.taskRepo, store.taskReader, store.userRepo
+	taskHistoryRepo, taskChangeRepo := store.taskHistoryRepo, store.taskChangeRepo
+	eventSourced := config.TaskPersistence == domain.TaskPersistenceEvents && !memoryStorage
+	if eventSourced {
+		taskRepo = repositories.NewEventSourcedTaskRepository(taskRepo, taskHistoryRepo)       // every change is stored as an event, tasks collection is the projection
+	}
+	taskRepo = repositories.NewChangeTrackingTaskRepository(taskRepo, taskChangeRepo)       // field changes with actor for the activity history
+	var cacheMetrics domain.CacheMetrics
+	if config.RedisURL != "" && config.TaskCacheTTL > 0 {
+		cache, err := infrastructure.NewRedisCache(config.RedisURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cachedTasks := repositories.NewCachedTaskRepository(taskRepo, taskReader, cache, config.TaskCacheTTL)       // cache-aside reads, writes invalidate
+		taskRepo, taskReader, cacheMetrics = cachedTasks, cachedTasks, cachedTasks
+	}
+	taskRepo, taskReader = repositories.NewTenantTaskRepository(taskRepo), repositories.NewTenantTaskRepository(taskReader)       // requests only see tasks of their organization
+	userRepo = repositories.NewTenantUserRepository(userRepo)                                                                        // requests only see users of their organization
+	unitOfWork := store.unitOfWork
+	oauthRepo, tokenRepo, auditLogRepo, labelRepo := store.oauthRepo, store.tokenRepo, store.auditLogRepo, store.labelRepo
+	resetRepo, verificationRepo, challengeRepo := store.resetRepo, store.verificationRepo, store.challengeRepo
+	usageRepo, settingsRepo, adminInviteRepo, apiUsageRepo := store.usageRepo, store.settingsRepo, store.adminInviteRepo, store.apiUsageRepo
+	tagJobRepo, myDayRepo, sessionRepo, idempotencyRepo := store.tagJobRepo, store.myDayRepo, store.sessionRepo, store.idempotencyRepo
+	projectRepo := repositories.NewTenantProjectRepository(store.projectRepo)       // limited to the request's organization
+	orgRepo, taskLockRepo, worklogRepo, dependencyRepo, eventLogRepo := store.orgRepo, store.taskLockRepo, store.worklogRepo, store.dependencyRepo, store.eventLogRepo
+	reportRepo, archiveRepo, trashRepo, integrityRepo := store.reportRepo, store.archiveRepo, store.trashRepo, store.integrityRepo
+	webhookRepo, webhookDeliveryRepo, templateRepo, migrationRepo := store.webhookRepo, store.webhookDeliveryRepo, store.templateRepo, store.migrationRepo
+
+	emailService := infrastructure.NewSettingsEmailService(settingsRepo, infrastructure.NewEmailService(config, logger))       // setup email service infrastructure (saved settings first)
+	notifier := infrastructure.NewNotifier(config, emailService, logger)       // setup notifier infrastructure
+
+	var searchService domain.SearchService
+	switch config.SearchBackend {
+	case domain.SearchBackendMongo:
+		searchService = store.textSearch       // text index of the task read model
+		if memoryStorage {
+			searchService = repositories.NewMemorySearchService(taskReader)
+		}
+	case domain.SearchBackendElasticsearch:
+		searchService = infrastructure.NewElasticsearchSearchService(config.ElasticsearchURL, config.ElasticsearchIndex)
+	default:
+		log.Fatal(domain.ErrInvalidSearchBackend)
+	}
+	searchService = repositories.NewTenantSearchService(searchService)       // requests only find tasks of their organization
+
+	taskEventHandlers := []domain.TaskEventHandler{usecases.NewAuditLogTaskEventHandler(auditLogRepo, logger), usecases.NewExtensionTaskEventHandler(extensions),
+		usecases.NewTaskEventLogTaskEventHandler(eventLogRepo, config.TaskEventRetention, logger), eventBus,       // logged before live streams are woken up
+		usecases.NewDomainEventTaskEventHandler(domainEvents), usecases.NewSearchIndexTaskEventHandler(searchService, logger), usecases.NewWorklogTaskEventHandler(worklogRepo, logger),
+		usecases.NewTaskDependencyTaskEventHandler(dependencyRepo, logger)}       // consumers of task changes
+	recurrenceUC := usecases.NewRecurrenceUseCase(taskRepo, logger, taskEventHandlers...)       // setup recurrence use case
+	taskWorkflow, err := domain.ParseTaskWorkflow(config.TaskTransitions)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer client.Disconnect(ctx)       // disconnect
+	var completionGuard domain.TaskDependencyRepository        // completing blocked tasks is refused only when enforced
+	if config.EnforceTaskDependencies {
+		completionGuard = dependencyRepo
+	}
+	taskCommandUC := usecases.NewTaskCommandUseCase(taskRepo, labelRepo, projectRepo, taskLockRepo, completionGuard, extensions, infrastructure.NewLanguageDetector(), taskWorkflow, trashRepo, unitOfWork,
+		append(taskEventHandlers, usecases.NewRecurrenceTaskEventHandler(recurrenceUC, logger))...)       // setup task command use case
+	taskQueryUC := usecases.NewTaskQueryUseCase(taskReader, projectRepo, searchService)                        // setup task query use case
+	taskUC := usecases.NewTaskUseCase(taskCommandUC, taskQueryUC)                               // setup task use case
+	taskLockUC := usecases.NewTaskLockUseCase(taskLockRepo, taskQueryUC, config.TaskLockTTL)    // setup task lock use case
+	worklogUC := usecases.NewWorklogUseCase(worklogRepo, taskQueryUC)                         // setup worklog use case
+	dependencyUC := usecases.NewTaskDependencyUseCase(dependencyRepo, taskQueryUC)            // setup task dependency use case
+	templateUC := usecases.NewTemplateUseCase(templateRepo, labelRepo, taskCommandUC, taskQueryUC)       // setup task template use case
+	checklistUC := usecases.NewChecklistUseCase(taskCommandUC, taskQueryUC)                   // setup checklist use case
+	taskStreamUC := usecases.NewTaskEventStreamUseCase(eventLogRepo, projectRepo)             // setup task event stream use case
+	reportUC := usecases.NewReportUseCase(reportRepo, projectRepo)                             // setup report use case
+	taskArchiveUC := usecases.NewTaskArchiveUseCase(archiveRepo, projectRepo, unitOfWork, logger, config.ArchiveAfterDays, taskEventHandlers...)       // setup task archive use case
+	taskTrashUC := usecases.NewTaskTrashUseCase(trashRepo, taskRepo, projectRepo, unitOfWork, logger, taskEventHandlers...)       // setup task trash use case
+	taskHistoryUC := usecases.NewTaskHistoryUseCase(taskHistoryRepo, taskChangeRepo, taskQueryUC, trashRepo, archiveRepo, projectRepo, eventSourced)              // setup task history use case
+	sessionUC := usecases.NewSessionUseCase(sessionRepo, settingsRepo, emailService, auditSink, logger, config.MaxSessions, config.ReadOnly)       // setup session use case
+	emailVerificationUC := usecases.NewEmailVerificationUseCase(userRepo, verificationRepo, emailService, auditSink, logger,
+		config.EmailVerificationTTL, config.EmailVerificationURL)        // setup email verification use case
+	totpService := infrastructure.NewTOTPService(config.TwoFactorIssuer)       // setup one-time password service
+	userUC := usecases.NewUserUseCase(userRepo, projectRepo, taskRepo, trashRepo, jwtservice, sessionUC, emailVerificationUC, totpService, challengeRepo, passwordService, auditSink, auditLogRepo, domainEvents, extensions, unitOfWork, logger,
+		config.MaxFailedLogins, config.LockoutDuration, config.RequireVerifiedEmail, config.TwoFactorChallengeTTL, taskEventHandlers...)       // setup user use case
+	passwordResetUC := usecases.NewPasswordResetUseCase(userRepo, resetRepo, passwordService, sessionUC, emailService, auditSink, logger,
+		config.PasswordResetTTL, config.PasswordResetURL)        // setup password reset use case
+	setupUC := usecases.NewSetupUseCase(userRepo, projectRepo, taskRepo, passwordService, auditSink, settingsRepo, infrastructure.NewSMTPEmailServiceFromSettings, logger)       // setup first run use case
+	adminInviteUC := usecases.NewAdminInviteUseCase(adminInviteRepo, userRepo, passwordService, auditSink, config.AdminInviteTTL)       // setup admin invite use case
+	orgUC := usecases.NewOrganizationUseCase(orgRepo, userRepo, sessionUC, auditSink, auditLogRepo, logger, config.OrgInviteTTL)       // setup organization use case
+	workspaceUC := usecases.NewWorkspaceUseCase(taskRepo, userRepo, labelRepo, projectRepo, auditLogRepo, unitOfWork, logger, taskEventHandlers...)       // setup workspace migration use case
+	integrityUC := usecases.NewIntegrityUseCase(integrityRepo, auditLogRepo, logger, config.IntegrityAutoRepair)       // setup integrity check use case
+	oauthUC := usecases.NewOAuthUseCase(oauthRepo, userRepo, jwtservice, passwordService, auditSink)       // setup oauth use case
+	tokenUC := usecases.NewPersonalAccessTokenUseCase(tokenRepo, userRepo, auditSink)                      // setup personal access token use case
+	auditLogUC := usecases.NewAuditLogUseCase(auditLogRepo)                                                // setup audit log use case
+	labelUC := usecases.NewLabelUseCase(labelRepo, taskRepo, projectRepo, auditLogRepo, logger)           // setup label use case
+	projectUC := usecases.NewProjectUseCase(projectRepo, taskRepo, userRepo, auditLogRepo, unitOfWork, logger, taskEventHandlers...)         // setup project use case
+	webhookUC := usecases.NewWebhookUseCase(webhookRepo, webhookDeliveryRepo, orgRepo, infrastructure.NewWebhookSender(config.WebhookTimeout), auditLogRepo, logger,
+		config.WebhookMaxAttempts, config.WebhookDisableAfterDays, config.WebhookDeliveryRetention)        // setup webhook use case
+	telemetryUC := usecases.NewTelemetryUseCase(usageRepo, infrastructure.NewTelemetrySender(config.TelemetryEndpoint), config.TelemetryEnabled,
+		infrastructure.TelemetryInstanceID(config), version, infrastructure.TelemetryFeatures(config))        // setup telemetry use case
+	deprecatedRoutes, err := domain.ParseDeprecations(config.DeprecatedRoutes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	deprecations := infrastructure.NewDeprecationPolicy(deprecatedRoutes, logger)                            // setup deprecated endpoint policy
+	apiUsageUC := usecases.NewAPIUsageUseCase(apiUsageRepo, tokenRepo, deprecations)                       // setup api usage use case
+	dueDateStrategies, err := usecases.NewDueDateStrategies(config.DueDateStrategies, config.DueDateMaxPerDay)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dueDateUC := usecases.NewDueDateUseCase(taskChangeRepo, taskReader, dueDateStrategies...)                // setup due date suggestion use case
+	exportUC := usecases.NewExportUseCase(taskReader, projectRepo)                                          // setup export use case
+	workingHours, err := domain.ParseWorkingHours(config.WorkDayStart, config.WorkDayEnd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dayPlanUC := usecases.NewDayPlanUseCase(taskReader, myDayRepo, workingHours, config.DefaultTaskEstimate)   // setup day plan use case
+	tagJobUC := usecases.NewTagJobUseCase(tagJobRepo, labelRepo, taskRepo, auditLogRepo, logger)            // setup tag job use case
+	var usageTracker *infrastructure.UsageTracker                                                        // api usage counters (read-only instances don't count)
+	if !config.ReadOnly {
+		usageTracker = infrastructure.NewUsageTracker(apiUsageRepo)
+	}
 
-	db := client.Database("taskmanager")
-	taskCol := db.Collection("tasks")         // initialize task collection
-	userCol := db.Collection("users")         // initialize user collection
+	reminderUC := usecases.NewReminderUseCase(taskRepo, projectRepo, userRepo, notifier, config.ReminderWindow, logger)        // setup reminder use case
+	overdueUC := usecases.NewOverdueUseCase(taskRepo, logger)                                    // setup overdue use case
 
-	jwtservice, _ := infrastructure.NewJWTService()              // setup jwt service infrastructure
-	passwordService := infrastructure.NewPasswordService()       // setup password service infrastructure
+	if memoryStorage {
+		logger.Warn(ctx, "everything is kept in memory and lost on restart, reports, integrity checks, usage statistics and the archive are unavailable")
+	}
 
-	taskRepo := repositories.NewTaskRepository(taskCol)          // setup task repositorie
-	userRepo := repositories.NewUserRepository(userCol)          // setup user repositorie
+	// background jobs and indexes (read-only instances don't write)
+	scheduler := infrastructure.NewScheduler(logger)
+	if config.ReadOnly && (*migrateOnly || *seed) {
+		log.Fatal("read-only instances don't migrate or seed, run them on a writable instance")
+	}
+	if !config.ReadOnly {
+		indexes := []migrations.IndexEnsurer{taskRepo.EnsureIndexes, userRepo.EnsureIndexes,        // existing duplicate usernames or emails must be fixed first
+			labelRepo.EnsureIndexes, resetRepo.EnsureIndexes, verificationRepo.EnsureIndexes, challengeRepo.EnsureIndexes, adminInviteRepo.EnsureIndexes, orgRepo.EnsureIndexes, apiUsageRepo.EnsureIndexes,
+			sessionRepo.EnsureIndexes, idempotencyRepo.EnsureIndexes, projectRepo.EnsureIndexes, taskLockRepo.EnsureIndexes, worklogRepo.EnsureIndexes, dependencyRepo.EnsureIndexes, archiveRepo.EnsureIndexes, trashRepo.EnsureIndexes, webhookDeliveryRepo.EnsureIndexes, eventLogRepo.EnsureIndexes, templateRepo.EnsureIndexes}       // nothing to create in memory
+		var versioned []domain.Migration
+		if (config.AutoMigrate || *migrateOnly) && !memoryStorage {
+			versioned = migrations.Versioned(taskRepo, userRepo)        // a fresh in-memory store needs no data migrations
+		}
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), config.MigrationTimeout)
+		applied, err := migrations.Run(migrateCtx, migrationRepo, indexes, versioned, logger)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *seed {
+			if err := migrations.Seed(migrateCtx, userRepo, taskRepo, passwordService, bootstrapAdmin(config), logger); err != nil {
+				log.Fatal(err)
+			}
+		}
+		cancelMigrate()
+		if *migrateOnly {
+			logger.Info(ctx, "database up to date", "migrations_applied", len(applied))
+			return
+		}
+		setupToken, err := setupUC.Bootstrap(ctx, bootstrapAdmin(config))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if setupToken != "" {
+			logger.Warn(ctx, "fresh database, create the admin account with POST /setup", "setup_token", setupToken)
+		}
+		if err := overdueUC.RefreshOverdueFlags(ctx); err != nil {
+			logger.Warn(ctx, "initial overdue refresh failed", "error", err)
+		}
+		scheduler.Every("overdue-flags", config.OverdueInterval, overdueUC.RefreshOverdueFlags)
+		scheduler.Every("recurring-tasks", config.RecurrenceInterval, recurrenceUC.CreateDueOccurrences)
+		scheduler.Every("api-usage-flush", config.UsageFlushInterval, usageTracker.Flush)
+		scheduler.Every("webhook-auto-disable", time.Hour, webhookUC.DisableFailingWebhooks)
+		domainEvents.Subscribe(webhookUC.HandleDomainEvent)        // webhook deliveries are written, so only writable instances send them
+		if config.RemindersEnabled {
+			scheduler.Every("due-date-reminders", config.ReminderInterval, reminderUC.SendDueReminders)
+		}
+		if config.ArchiveAfterDays > 0 && !memoryStorage {
+			scheduler.Every("task-archival", time.Hour, taskArchiveUC.ArchiveCompletedTasks)
+		}
+		if config.IntegrityCheckInterval > 0 && !memoryStorage {
+			scheduler.Every("integrity-check", config.IntegrityCheckInterval, integrityUC.RunScheduledCheck)
+		}
+	}
+	if config.TelemetryEnabled && memoryStorage {
+		logger.Warn(ctx, "telemetry needs mongodb for usage statistics, reports are not sent")
+	} else if config.TelemetryEnabled {
+		if config.TelemetryEndpoint == "" {
+			logger.Warn(ctx, "telemetry enabled without TELEMETRY_ENDPOINT, reports are not sent")
+		} else {
+			scheduler.Every("telemetry", config.TelemetryInterval, telemetryUC.SendReport)
+		}
+	}
+	scheduler.Start()
+	defer scheduler.Stop()
+	go reloadKeysOnHangup(jwtservice, logger)        // kill -HUP rotates jwt keys without a restart
 
-	taskUC := usecases.NewTaskUseCase(taskRepo)                                    // setup task use case
-	userUC := usecases.NewUserUseCase(userRepo, jwtservice, passwordService)       // setup user use case
+	router := routers.SetupRouter(taskUC, userUC, passwordResetUC, emailVerificationUC, setupUC, adminInviteUC, oauthUC, tokenUC, labelUC, projectUC, taskLockUC, webhookUC, taskHistoryUC, recurrenceUC, auditLogUC, telemetryUC, apiUsageUC, tagJobUC, exportUC, dueDateUC, dayPlanUC, sessionUC, orgUC, workspaceUC, integrityUC, worklogUC, reportUC, taskArchiveUC, dependencyUC, taskStreamUC, templateUC, checklistUC, taskTrashUC, jwtservice, infrastructure.NewOAuthService(config), auditSink, errorReporter, eventBus, cacheMetrics, queryLog, usageTracker, idempotencyRepo, deprecations, taskWorkflow, extensions, logger, config, store.ping)       // initialize the router with all configured routes
 
-	router := routers.SetupRouter(taskUC, userUC, jwtservice)       // initialize the router with all configured routes
+	// grpc for internal services, same usecases and tokens as the http api
+	if config.GRPCAddr != "" {
+		grpcServer := grpc.NewServer(
+			grpc.LoggingInterceptor(logger),
+			grpc.ReadOnlyInterceptor(config.ReadOnly),
+			grpc.AuthInterceptor(infrastructure.NewAuthMiddleware(jwtservice, tokenUC, sessionUC, userUC, auditSink)),
+		)
+		grpc.RegisterTaskService(grpcServer, taskUC)
+		grpc.RegisterUserService(grpcServer, userUC)
+		go func() {
+			logger.Info(context.Background(), "starting grpc server", "addr", config.GRPCAddr, "tls", config.GRPCTLSCert != "")
+			if err := grpcServer.ListenAndServe(config.GRPCAddr, config.GRPCTLSCert, config.GRPCTLSKey); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
 
 	// start the server on port 8080
-	router.Run(":8080")                        
-	log.Println("Starting server on :8080")
+	logger.Info(context.Background(), "starting server", "addr", ":8080")
+	if err := router.Run(":8080"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// read preference of task queries (primary when unset or unknown)
+func queryReadPreference(config *infrastructure.Config) *readpref.ReadPref {
+	if config.ReadOnly {
+		return readpref.SecondaryPreferred()       // read-only instances read from secondaries
+	}
+	mode, err := readpref.ModeFromString(config.QueryReadPreference)
+	if err != nil {
+		log.Printf("unknown query read preference %q, using primary", config.QueryReadPreference)
+		return readpref.Primary()
+	}
+	pref, err := readpref.New(mode)
+	if err != nil {
+		return readpref.Primary()
+	}
+	return pref
+}
+
+// first admin from configuration (nil when no credentials are configured)
+func bootstrapAdmin(config *infrastructure.Config) *domain.User {
+	if config.AdminUsername == "" || config.AdminPassword == "" {
+		return nil
+	}
+	return &domain.User{Username: config.AdminUsername, Password: config.AdminPassword, Email: config.AdminEmail}
+}
+
+// re-read .env and the jwt key files on every SIGHUP, a broken configuration keeps the previous keys
+func reloadKeysOnHangup(jwtService *infrastructure.JWTService, logger domain.Logger) {
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	for range hangup {
+		ctx := context.Background()
+		if err := jwtService.Reload(); err != nil {
+			logger.Error(ctx, "jwt keys not reloaded, previous keys stay in use", "error", err)
+			continue
+		}
+		logger.Info(ctx, "jwt keys reloaded", "signing_kid", jwtService.SigningKeyID())
+	}
 }
@@ -0,0 +1,475 @@
+package routers
+
+// imports
+import (
+	"net/http";
+	"reflect";
+	"sort";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Delivery/controllers";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// api contract version published in the openapi document
+const apiVersion = "1.0.0"
+
+// documentation of one endpoint (request and response bodies are example values of their types)
+type routeDoc struct {
+	Summary   string
+	Tag       string
+	Public    bool              // no bearer token needed
+	Request   interface{}       // json request body
+	Response  interface{}       // success response body
+	Status    int               // success status (200 when not set)
+}
+
+// plain message response
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+// error response (code and message of every failed request)
+type errorResponse apierror.Response
+
+// task update response
+type taskUpdateResponse struct {
+	Message      string                     `json:"message"`
+	UpdatedTask  controllers.TaskResource   `json:"updated_task"`
+	Changes      []domain.TaskFieldChange   `json:"changes"`
+}
+
+type taskSearchResponse struct {
+	Results  []controllers.TaskSearchResultResource   `json:"results"`
+}
+
+// user deletion response
+type userDeletionResponse struct {
+	Message   string               `json:"message"`
+	Deletion  domain.UserDeletion  `json:"deletion"`
+}
+
+// trash purge response
+type trashPurgeResponse struct {
+	Purged  int64  `json:"purged"`
+}
+
+// validation error response
+type validationErrorResponse struct {
+	Errors []infrastructure.FieldError `json:"errors"`
+}
+
+// documentation of the routes registered in SetupRouter, keyed by "METHOD /route/:param"
+// routes missing here are still published, just without bodies
+var routeDocs = map[string]routeDoc{
+	"GET /healthz":                {Summary: "Health and mode of the instance", Tag: "health", Public: true},
+	"POST /register":              {Summary: "Register a new user", Tag: "users", Public: true, Request: domain.RegisterRequest{}, Response: messageResponse{}, Status: http.StatusCreated},
+	"POST /setup":                 {Summary: "Create the first admin and save instance settings with the setup token printed at startup", Tag: "users", Public: true, Request: domain.SetupRequest{}, Response: messageResponse{}, Status: http.StatusCreated},
+	"POST /login":                 {Summary: "Authenticate a user", Tag: "users", Public: true, Request: domain.Credentials{}},
+	"POST /auth/2fa":              {Summary: "Finish a login with a two-factor code", Tag: "users", Public: true, Request: domain.TwoFactorLoginRequest{}},
+	"POST /auth/forgot-password":  {Summary: "Email a password reset link", Tag: "users", Public: true, Request: domain.ForgotPasswordRequest{}, Response: messageResponse{}, Status: http.StatusAccepted},
+	"POST /auth/reset-password":   {Summary: "Set a new password with a reset token", Tag: "users", Public: true, Request: domain.ResetPasswordRequest{}, Response: messageResponse{}},
+	"GET /auth/verify":            {Summary: "Confirm an email address with the token from the verification email", Tag: "users", Public: true, Response: messageResponse{}},
+	"POST /auth/resend-verification": {Summary: "Email a new verification link to an unverified account", Tag: "users", Public: true, Request: domain.ResendVerificationRequest{}, Response: messageResponse{}, Status: http.StatusAccepted},
+	"GET /auth/oauth/:provider":   {Summary: "Redirect to google or github sign-in", Tag: "users", Public: true, Status: http.StatusFound},
+	"GET /auth/oauth/:provider/callback": {Summary: "Finish provider sign-in and issue a token", Tag: "users", Public: true},
+	"PUT /promote/:id":            {Summary: "Promote a user to admin", Tag: "users", Response: messageResponse{}},
+	"GET /tasks":                  {Summary: "List tasks", Tag: "tasks", Response: []controllers.TaskResource{}},
+	"GET /tasks/export":           {Summary: "Download tasks as csv or xlsx (same filters as the list)", Tag: "tasks"},
+	"GET /tasks/search":           {Summary: "Full-text search of tasks, most relevant first with highlighted matches", Tag: "tasks", Response: taskSearchResponse{}},
+	"GET /tasks/suggest-due-date": {Summary: "Suggest a due date for a task title", Tag: "tasks", Response: domain.DueDateSuggestion{}},
+	"POST /tasks/import":          {Summary: "Create tasks from a csv or json file (per-row report)", Tag: "tasks", Response: domain.TaskImportReport{}, Status: http.StatusCreated},
+	"GET /tasks/:id":              {Summary: "Get a task", Tag: "tasks", Response: controllers.TaskResource{}},
+	"POST /tasks/batch-get":       {Summary: "Get up to 100 tasks by id in one request (ids not found are listed)", Tag: "tasks", Request: domain.TaskBatchInput{}, Response: controllers.TaskBatchResource{}},
+	"GET /tasks/archive":          {Summary: "List archived tasks, newest first (paged with before and limit)", Tag: "tasks", Response: []domain.ArchivedTask{}},
+	"POST /tasks/:id/unarchive":   {Summary: "Move an archived task back to the task list", Tag: "tasks", Response: controllers.TaskResource{}},
+	"GET /tasks/trash":            {Summary: "List deleted tasks, newest first (paged with before and limit)", Tag: "tasks", Response: []domain.DeletedTask{}},
+	"POST /tasks/:id/restore":     {Summary: "Move a deleted task and the subtasks deleted with it back to the task list", Tag: "tasks", Response: controllers.TaskResource{}},
+	"GET /tasks/:id/subtasks":     {Summary: "List direct subtasks of a task", Tag: "tasks", Response: []controllers.TaskResource{}},
+	"GET /ws":                     {Summary: "WebSocket stream of task.created, task.updated and task.deleted events", Tag: "tasks"},
+	"GET /tasks/events":           {Summary: "Server-sent event stream of task changes, resumes after Last-Event-ID", Tag: "tasks"},
+	"GET /tasks/:id/as-of":        {Summary: "Get a task as it was at a point in time (event sourced mode)", Tag: "tasks", Response: domain.Task{}},
+	"GET /tasks/:id/history":      {Summary: "List field changes of a task with actor and time", Tag: "tasks", Response: []domain.TaskChange{}},
+	"GET /tasks/:id/events":       {Summary: "List stored snapshots of a task (event sourced mode)", Tag: "tasks", Response: []domain.TaskHistoryEvent{}},
+	"POST /tasks":                 {Summary: "Create a task", Tag: "tasks", Request: domain.CreateTaskRequest{}, Response: controllers.TaskResource{}, Status: http.StatusCreated},
+	"PUT /tasks/:id":              {Summary: "Update a task", Tag: "tasks", Request: domain.Task{}, Response: taskUpdateResponse{}},
+	"PATCH /tasks/:id":            {Summary: "Change some fields of a task (application/merge-patch+json or application/json-patch+json)", Tag: "tasks", Request: domain.UpdateTaskInput{}, Response: taskUpdateResponse{}},
+	"DELETE /tasks/:id":           {Summary: "Move a task to the trash (cascade=true takes its subtasks along)", Tag: "tasks", Response: messageResponse{}},
+	"GET /tasks/:id/lock":             {Summary: "Who is editing a task", Tag: "tasks", Response: domain.TaskLock{}},
+	"POST /tasks/:id/lock":            {Summary: "Lock a task for editing (423 with the holder's lock when taken)", Tag: "tasks", Response: domain.TaskLock{}},
+	"POST /tasks/:id/lock/heartbeat":  {Summary: "Keep an edit lock fresh", Tag: "tasks", Response: domain.TaskLock{}},
+	"DELETE /tasks/:id/lock":          {Summary: "Release an edit lock", Tag: "tasks", Response: messageResponse{}},
+	"GET /tasks/:id/worklogs":         {Summary: "Worklogs of a task with the time spent in total and per user", Tag: "tasks", Response: domain.TaskWorklogs{}},
+	"POST /tasks/:id/worklogs":        {Summary: "Record time spent on a task afterwards", Tag: "tasks", Request: domain.WorklogInput{}, Response: domain.Worklog{}, Status: http.StatusCreated},
+	"DELETE /tasks/:id/worklogs/:worklogId": {Summary: "Delete own worklog (admins any)", Tag: "tasks", Response: messageResponse{}},
+	"POST /tasks/:id/timer/start":     {Summary: "Start a timer on a task (409 with the running timer when one runs already)", Tag: "tasks", Response: domain.Worklog{}, Status: http.StatusCreated},
+	"POST /tasks/:id/timer/stop":      {Summary: "Stop own timer on a task and log the time", Tag: "tasks", Response: domain.Worklog{}},
+	"GET /tasks/:id/dependencies":     {Summary: "Blockers of a task and the tasks it blocks", Tag: "tasks", Response: domain.TaskDependencies{}},
+	"POST /tasks/:id/dependencies":    {Summary: "Mark a task as blocked by another task (409 when it would create a cycle)", Tag: "tasks", Request: domain.TaskDependencyInput{}, Response: domain.TaskDependency{}, Status: http.StatusCreated},
+	"DELETE /tasks/:id/dependencies/:blockerId": {Summary: "Remove a blocker from a task", Tag: "tasks", Response: messageResponse{}},
+	"GET /labels":                 {Summary: "List labels", Tag: "labels", Response: []domain.Label{}},
+	"GET /labels/budget":          {Summary: "Label budgets against task costs", Tag: "labels", Response: domain.BudgetReport{}},
+	"GET /reports/summary":        {Summary: "Task counts by status and priority, overdue and completed this week (workspace for admins, own tasks otherwise)", Tag: "reports", Response: domain.TaskSummaryReport{}},
+	"GET /reports/productivity":   {Summary: "Tasks created and completed and time logged per day between from and to (admins may pass user_id)", Tag: "reports", Response: domain.ProductivityReport{}},
+	"GET /labels/:id":             {Summary: "Get a label", Tag: "labels", Response: domain.Label{}},
+	"POST /labels":                {Summary: "Create a label", Tag: "labels", Request: domain.Label{}, Response: domain.Label{}, Status: http.StatusCreated},
+	"PUT /labels/:id":             {Summary: "Update a label (renames follow on tasks)", Tag: "labels", Request: domain.Label{}, Response: domain.Label{}},
+	"DELETE /labels/:id":          {Summary: "Delete a label and detach it from tasks", Tag: "labels", Response: messageResponse{}},
+	"GET /templates":              {Summary: "List own task templates", Tag: "templates", Response: []domain.TaskTemplate{}},
+	"GET /templates/:id":          {Summary: "Get an own task template", Tag: "templates", Response: domain.TaskTemplate{}},
+	"POST /templates":             {Summary: "Create a task template", Tag: "templates", Request: domain.TaskTemplate{}, Response: domain.TaskTemplate{}, Status: http.StatusCreated},
+	"PUT /templates/:id":          {Summary: "Update an own task template", Tag: "templates", Request: domain.TaskTemplate{}, Response: domain.TaskTemplate{}},
+	"DELETE /templates/:id":       {Summary: "Delete an own task template", Tag: "templates", Response: messageResponse{}},
+	"POST /tasks/:id/template":    {Summary: "Save a task's title, description, checklist, labels and priority as a template", Tag: "templates", Response: domain.TaskTemplate{}, Status: http.StatusCreated},
+	"POST /tasks/from-template/:templateId": {Summary: "Create a task from an own template", Tag: "templates", Request: domain.TaskFromTemplateRequest{}, Response: controllers.TaskResource{}, Status: http.StatusCreated},
+	"GET /projects":               {Summary: "List the caller's projects (all for admins)", Tag: "projects", Response: []domain.Project{}},
+	"GET /projects/:id":           {Summary: "Get a project", Tag: "projects", Response: domain.Project{}},
+	"POST /projects":              {Summary: "Create a project owned by the caller", Tag: "projects", Request: domain.Project{}, Response: domain.Project{}, Status: http.StatusCreated},
+	"PUT /projects/:id":           {Summary: "Update a project", Tag: "projects", Request: domain.Project{}, Response: domain.Project{}},
+	"DELETE /projects/:id":        {Summary: "Delete a project without tasks", Tag: "projects", Response: messageResponse{}},
+	"POST /projects/:id/clone":    {Summary: "Copy a project, its members and open tasks on request", Tag: "projects", Request: domain.ProjectCloneRequest{}, Response: domain.ProjectClone{}, Status: http.StatusCreated},
+	"PUT /projects/:id/members/:userId":    {Summary: "Add a project member or change their role", Tag: "projects", Request: domain.ProjectMember{}, Response: domain.Project{}},
+	"DELETE /projects/:id/members/:userId": {Summary: "Remove a project member", Tag: "projects", Response: domain.Project{}},
+	"PUT /tasks/:id/recurrence":          {Summary: "Start or replace the recurrence of a task", Tag: "tasks", Request: domain.Recurrence{}, Response: controllers.TaskResource{}},
+	"POST /tasks/:id/recurrence/pause":   {Summary: "Pause a recurrence series", Tag: "tasks", Response: controllers.TaskResource{}},
+	"POST /tasks/:id/recurrence/resume":  {Summary: "Resume a paused recurrence series", Tag: "tasks", Response: controllers.TaskResource{}},
+	"DELETE /tasks/:id/recurrence":       {Summary: "End a recurrence series (the task stays)", Tag: "tasks", Response: controllers.TaskResource{}},
+	"POST /tasks/:id/checklist":              {Summary: "Add a checklist item to a task (at the end unless a position is given)", Tag: "tasks", Request: domain.ChecklistItemInput{}, Response: controllers.TaskResource{}, Status: http.StatusCreated},
+	"POST /tasks/:id/checklist/:index/toggle": {Summary: "Tick off a checklist item or undo it", Tag: "tasks", Response: controllers.TaskResource{}},
+	"PUT /tasks/:id/checklist/order":         {Summary: "Reorder the checklist of a task", Tag: "tasks", Request: domain.ChecklistOrderRequest{}, Response: controllers.TaskResource{}},
+	"DELETE /tasks/:id/checklist/:index":     {Summary: "Delete a checklist item", Tag: "tasks", Response: controllers.TaskResource{}},
+	"PUT /tasks/:id/labels/:labelId":    {Summary: "Attach a label to a task", Tag: "labels", Response: domain.Task{}},
+	"DELETE /tasks/:id/labels/:labelId": {Summary: "Detach a label from a task", Tag: "labels", Response: domain.Task{}},
+	"POST /oauth/token":           {Summary: "Exchange an authorization code for an access token", Tag: "oauth", Public: true, Request: domain.OAuthTokenRequest{}, Response: usecases.OAuthToken{}},
+	"POST /oauth/clients":         {Summary: "Register a third-party client", Tag: "oauth", Status: http.StatusCreated},
+	"GET /oauth/authorize":        {Summary: "HTML consent page of an authorize request (signs the user in with its own cookie)", Tag: "oauth", Public: true},
+	"POST /oauth/authorize":       {Summary: "Sign in, approve or deny on the consent page (form post, redirects to the client)", Tag: "oauth", Public: true, Status: http.StatusFound},
+	"GET /me":                     {Summary: "Get own profile", Tag: "users"},
+	"PUT /me":                     {Summary: "Update own email, display name and notification preferences", Tag: "users", Request: domain.UpdateProfileRequest{}},
+	"PUT /me/password":            {Summary: "Change own password", Tag: "users", Request: domain.ChangePasswordRequest{}, Response: messageResponse{}},
+	"POST /me/2fa/setup":          {Summary: "Generate an authenticator app secret", Tag: "users", Response: domain.TwoFactorSetup{}},
+	"POST /me/2fa/enable":         {Summary: "Enable two-factor authentication", Tag: "users", Request: domain.TwoFactorCodeRequest{}, Response: domain.TwoFactorBackupCodes{}},
+	"DELETE /me/2fa":              {Summary: "Disable two-factor authentication", Tag: "users", Request: domain.TwoFactorCodeRequest{}, Response: messageResponse{}},
+	"POST /me/tokens":             {Summary: "Create a personal access token", Tag: "tokens", Request: domain.CreatePersonalAccessTokenRequest{}, Status: http.StatusCreated},
+	"GET /me/tokens":              {Summary: "List own personal access tokens", Tag: "tokens", Response: []domain.PersonalAccessToken{}},
+	"DELETE /me/tokens/:id":       {Summary: "Revoke a personal access token", Tag: "tokens", Response: messageResponse{}},
+	"GET /me/tokens/:id/usage":    {Summary: "Calls made with a personal access token per endpoint", Tag: "tokens", Response: []domain.APIUsage{}},
+	"POST /me/plan-day":           {Summary: "Propose an order of today's tasks within working hours", Tag: "users", Request: domain.PlanDayRequest{}, Response: domain.DayPlan{}},
+	"GET /me/day":                 {Summary: "Today's tasks in the chosen order", Tag: "users", Response: domain.MyDayTasks{}},
+	"PUT /me/day":                 {Summary: "Set the order of today's tasks (e.g. an accepted plan)", Tag: "users", Request: domain.SetMyDayRequest{}, Response: domain.MyDayTasks{}},
+	"GET /orgs/:id":               {Summary: "Get own organization", Tag: "organizations", Response: domain.Organization{}},
+	"GET /orgs/:id/members":       {Summary: "List members of own organization (paged)", Tag: "organizations", Response: domain.UserPage{}},
+	"POST /orgs/:id/invites":      {Summary: "Create a single-use invite to the organization (owners)", Tag: "organizations", Request: domain.CreateOrganizationInviteRequest{}, Status: http.StatusCreated},
+	"POST /orgs/invites/accept":   {Summary: "Join the organization of an invite, sessions end and the caller logs in again", Tag: "organizations", Request: domain.AcceptOrganizationInviteRequest{}},
+	"GET /admin/audit":            {Summary: "Query the audit log", Tag: "admin", Response: []domain.AuditLogEntry{}},
+	"GET /admin/users":            {Summary: "List users (search with q, filter by role and status, paged)", Tag: "admin", Response: domain.UserPage{}},
+	"GET /admin/users/:id":        {Summary: "Get a user with their project memberships", Tag: "admin", Response: domain.UserDetail{}},
+	"DELETE /admin/users/:id":     {Summary: "Delete a user, projects they own alone go to reassign_to or are deleted with their tasks", Tag: "admin", Response: userDeletionResponse{}},
+	"POST /admin/users/:id/unlock": {Summary: "Unlock a user locked out after failed logins", Tag: "admin", Response: messageResponse{}},
+	"POST /admin/users/:id/deactivate": {Summary: "Deactivate a user and end their sessions", Tag: "admin", Response: messageResponse{}},
+	"POST /admin/users/:id/reactivate": {Summary: "Reactivate a deactivated user", Tag: "admin", Response: messageResponse{}},
+	"POST /admin/users/:id/demote":     {Summary: "Demote an admin to user", Tag: "admin", Response: messageResponse{}},
+	"POST /admin/invites":         {Summary: "Create a single-use invite for a new admin", Tag: "admin", Request: domain.CreateAdminInviteRequest{}, Status: http.StatusCreated},
+	"GET /admin/workspace/export": {Summary: "Export labels, projects, tasks and with users=true accounts as a workspace bundle", Tag: "admin", Response: domain.WorkspaceBundle{}},
+	"POST /admin/workspace/import": {Summary: "Import a workspace bundle under new ids (users=true also creates its accounts)", Tag: "admin", Request: domain.WorkspaceBundle{}, Response: domain.WorkspaceImportReport{}, Status: http.StatusCreated},
+	"POST /admin/integrity":       {Summary: "Find broken references and drifted fields, repair them unless dry_run (default true)", Tag: "admin", Request: domain.IntegrityRequest{}, Response: domain.IntegrityReport{}},
+	"POST /admin/orgs":            {Summary: "Create an organization and move its first owner into it", Tag: "admin", Request: domain.CreateOrganizationRequest{}, Response: domain.Organization{}, Status: http.StatusCreated},
+	"GET /admin/webhooks":         {Summary: "List workspace webhooks", Tag: "admin", Response: []domain.Webhook{}},
+	"POST /admin/webhooks":        {Summary: "Add a webhook receiving domain events, the response carries its signing secret", Tag: "admin", Request: domain.WebhookInput{}, Response: domain.Webhook{}, Status: http.StatusCreated},
+	"GET /admin/webhooks/:id":     {Summary: "Get a webhook with its failing and disabled state", Tag: "admin", Response: domain.Webhook{}},
+	"PATCH /admin/webhooks/:id":   {Summary: "Change a webhook's url, events or organization, enable or disable it", Tag: "admin", Request: domain.WebhookInput{}, Response: domain.Webhook{}},
+	"DELETE /admin/webhooks/:id":  {Summary: "Delete a webhook and its delivery log", Tag: "admin", Response: messageResponse{}},
+	"GET /admin/webhooks/:id/deliveries": {Summary: "Delivery log of a webhook with attempts, status codes and latency (paged with before and limit)", Tag: "admin", Response: []domain.WebhookDelivery{}},
+	"POST /admin/webhooks/:id/test": {Summary: "Send a webhook.test event and return its delivery", Tag: "admin", Response: domain.WebhookDelivery{}},
+	"GET /admin/settings/security": {Summary: "Workspace security settings", Tag: "admin", Response: domain.SecuritySettings{}},
+	"PUT /admin/settings/security": {Summary: "Change workspace security settings (concurrent session limit)", Tag: "admin", Request: domain.SecuritySettings{}, Response: domain.SecuritySettings{}},
+	"POST /admin/invites/accept":  {Summary: "Create an admin account with an invite token", Tag: "users", Public: true, Request: domain.AcceptAdminInviteRequest{}, Response: messageResponse{}, Status: http.StatusCreated},
+	"GET /admin/telemetry/preview": {Summary: "Preview the anonymized usage report", Tag: "admin"},
+	"GET /admin/debug/query-log":    {Summary: "Whether database queries are being logged and until when", Tag: "admin", Response: domain.QueryLogStatus{}},
+	"PUT /admin/debug/query-log":    {Summary: "Log every database query with its sanitized filter and duration for a number of minutes", Tag: "admin", Request: domain.QueryLogRequest{}, Response: domain.QueryLogStatus{}},
+	"DELETE /admin/debug/query-log": {Summary: "Stop logging database queries", Tag: "admin", Response: domain.QueryLogStatus{}},
+	"GET /admin/cache":            {Summary: "Task cache hit and miss counters since startup", Tag: "admin", Response: domain.CacheStats{}},
+	"GET /admin/usage":            {Summary: "API calls per user, token and client", Tag: "admin", Response: []domain.APIUsageRollup{}},
+	"GET /admin/deprecations":     {Summary: "Deprecated endpoints and who still calls them", Tag: "admin", Response: []domain.DeprecatedEndpointUsage{}},
+	"POST /admin/tags/rename":     {Summary: "Rename a label and retag its tasks in the background", Tag: "admin", Request: domain.RenameTagRequest{}, Response: domain.TagJob{}, Status: http.StatusAccepted},
+	"POST /admin/tags/merge":      {Summary: "Merge one label into another in the background", Tag: "admin", Request: domain.MergeTagsRequest{}, Response: domain.TagJob{}, Status: http.StatusAccepted},
+	"GET /admin/tags/jobs/:id":    {Summary: "Progress of a tag rename or merge", Tag: "admin", Response: domain.TagJob{}},
+	"DELETE /admin/tasks/trash":   {Summary: "Remove deleted tasks for good (all, or those deleted before ?before=)", Tag: "admin", Response: trashPurgeResponse{}},
+}
+
+// build openapi 3 document from the registered routes
+func buildOpenAPI(routes gin.RoutesInfo, prefix string, deprecations *infrastructure.DeprecationPolicy) map[string]interface{} {
+
+	schemas := map[string]interface{}{}
+	paths := map[string]interface{}{}
+
+	for _, route := range routes {
+		// only the versioned routes, paths are relative to the server url
+		routePath, versioned := strings.CutPrefix(route.Path, prefix+"/")
+		if !versioned {
+			continue
+		}
+		route.Path = "/" + routePath
+		doc := routeDocs[route.Method+" "+route.Path]
+
+		operation := map[string]interface{}{
+			"summary":    doc.Summary,
+			"responses":  operationResponses(doc, schemas),
+		}
+		if doc.Tag != "" {
+			operation["tags"] = []string{doc.Tag}
+		}
+		if !doc.Public {
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+		}
+		if deprecation, ok := deprecations.Lookup(route.Method + " " + route.Path); ok {
+			operation["deprecated"] = true
+			operation["description"] = deprecationNote(deprecation)
+		}
+		if params := pathParameters(route.Path); len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if doc.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(doc.Request), schemas)}},
+			}
+		}
+
+		path := openAPIPath(route.Path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":    "Task Management API",
+			"version":  apiVersion,
+		},
+		"servers": []map[string]string{{"url": prefix}},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+// success and common error responses of an operation
+func operationResponses(doc routeDoc, schemas map[string]interface{}) map[string]interface{} {
+
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	if doc.Response != nil {
+		success["content"] = map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(doc.Response), schemas)}}
+	}
+
+	errorContent := map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(errorResponse{}), schemas)}}
+	responses := map[string]interface{}{
+		strconv.Itoa(status): success,
+		"500":                map[string]interface{}{"description": http.StatusText(http.StatusInternalServerError), "content": errorContent},
+	}
+	if doc.Request != nil {
+		responses["400"] = map[string]interface{}{
+			"description": http.StatusText(http.StatusBadRequest),
+			"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(validationErrorResponse{}), schemas)}},
+		}
+	}
+	if !doc.Public {
+		responses["401"] = map[string]interface{}{"description": http.StatusText(http.StatusUnauthorized), "content": errorContent}
+		responses["403"] = map[string]interface{}{"description": http.StatusText(http.StatusForbidden), "content": errorContent}
+	}
+
+	return responses
+}
+
+// /tasks/:id -> /tasks/{id}
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// path parameters of a gin route
+func pathParameters(path string) []map[string]interface{} {
+	var params []map[string]interface{}
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, map[string]interface{}{
+				"name":      part[1:],
+				"in":        "path",
+				"required":  true,
+				"schema":    map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	return params
+}
+
+// json schema of a go type, structs are registered as named components
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(primitive.ObjectID{}):
+		return map[string]interface{}{"type": "string", "example": "6878d8c9bab227206acc35e3"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = map[string]interface{}{}       // placeholder stops recursion on self references
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// object schema of a struct from its json and binding tags
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+
+	properties := map[string]interface{}{}
+	var required []string
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			// embedded structs contribute their fields
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				collect(field.Type)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			schema := schemaFor(field.Type, schemas)
+			for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+				key, value, _ := strings.Cut(rule, "=")
+				switch key {
+				case "required":
+					required = append(required, name)
+				case "oneof":
+					schema["enum"] = strings.Fields(value)
+				case "max", "min":
+					if limit, err := strconv.Atoi(value); err == nil {
+						schema[limitKeyword(key, field.Type)] = limit
+					}
+				}
+			}
+			properties[name] = schema
+		}
+	}
+	collect(t)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// openapi keyword for a min/max binding rule on a field type
+func limitKeyword(rule string, t reflect.Type) string {
+	suffix := "Length"
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		suffix = "imum"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	}
+	return rule + suffix
+}
+
+// swagger ui page loading the served document
+const swaggerPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Task Management API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });</script>
+</body>
+</html>`
+
+// serve openapi document and swagger ui (call after all api routes are registered)
+func registerOpenAPI(router *gin.Engine, prefix string, deprecations *infrastructure.DeprecationPolicy) {
+
+	spec := buildOpenAPI(router.Routes(), prefix, deprecations)        // built once from the routes registered so far
+
+	serveSpec := func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	}
+	router.GET("/openapi.json", serveSpec)
+	router.GET(prefix+"/openapi.json", serveSpec)
+	router.GET("/swagger/*any", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+	})
+}
+
+// description of a deprecated operation
+func deprecationNote(deprecation domain.Deprecation) string {
+	note := "Deprecated."
+	if deprecation.Successor != "" {
+		note += " Use " + deprecation.Successor + " instead."
+	}
+	if deprecation.Sunset != nil {
+		note += " Removed after " + deprecation.Sunset.Format("2006-01-02") + "."
+	}
+	return note
+}
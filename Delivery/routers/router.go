@@ -2,46 +2,300 @@ package routers
 
 // imports
 import (
+	"context";
+	"log";
+	"net/http";
+	"time";
 	"github.com/gin-gonic/gin";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Delivery/controllers";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
 )
 
+// endpoints deprecated in code, keyed like routeDocs (DEPRECATED_ROUTES adds more and overrides these)
+// e.g. {Endpoint: "GET /tasks/:id/events", Successor: "/tasks/:id/history"}
+var deprecatedRoutes = []domain.Deprecation{}
+
 // setup router
-func SetupRouter( taskUsc usecases.TaskUseCase, userUsc usecases.UserUseCase, jwtServ domain.JWTService) *gin.Engine {
+func SetupRouter( taskUsc usecases.TaskUseCase, userUsc usecases.UserUseCase, passwordResetUsc usecases.PasswordResetUseCase, emailVerificationUsc usecases.EmailVerificationUseCase, setupUsc usecases.SetupUseCase, adminInviteUsc usecases.AdminInviteUseCase, oauthUsc usecases.OAuthUseCase, patUsc usecases.PersonalAccessTokenUseCase, labelUsc usecases.LabelUseCase, projectUsc usecases.ProjectUseCase, taskLockUsc usecases.TaskLockUseCase, webhookUsc usecases.WebhookUseCase, taskHistoryUsc usecases.TaskHistoryUseCase, recurrenceUsc usecases.RecurrenceUseCase, auditLogUsc usecases.AuditLogUseCase, telemetryUsc usecases.TelemetryUseCase, apiUsageUsc usecases.APIUsageUseCase, tagJobUsc usecases.TagJobUseCase, exportUsc usecases.ExportUseCase, dueDateUsc usecases.DueDateUseCase, dayPlanUsc usecases.DayPlanUseCase, sessionUsc usecases.SessionUseCase, orgUsc usecases.OrganizationUseCase, workspaceUsc usecases.WorkspaceUseCase, integrityUsc usecases.IntegrityUseCase, worklogUsc usecases.WorklogUseCase, reportUsc usecases.ReportUseCase, taskArchiveUsc usecases.TaskArchiveUseCase, dependencyUsc usecases.TaskDependencyUseCase, taskStreamUsc usecases.TaskEventStreamUseCase, templateUsc usecases.TemplateUseCase, checklistUsc usecases.ChecklistUseCase, taskTrashUsc usecases.TaskTrashUseCase, jwtServ domain.JWTService, oauthServ domain.OAuthService, auditSink domain.AuditSink, errorReporter domain.ErrorReporter, eventBus domain.TaskEventBus, cacheMetrics domain.CacheMetrics, queryLog domain.QueryLogSwitch, usageTracker *infrastructure.UsageTracker, idempotencyRepo domain.IdempotencyRepository, deprecations *infrastructure.DeprecationPolicy, taskWorkflow domain.TaskWorkflow, extensions domain.ExtensionHooks, logger domain.Logger, config *infrastructure.Config, dbPing func(ctx context.Context) error) *gin.Engine {
+
+	// register custom validation rules before any binding happens
+	if err := infrastructure.RegisterValidators(); err != nil {
+		log.Fatal(err)
+	}
 
-	router := gin.Default()     // create default gin router
+	router := gin.New()         // create gin router (request logging is structured below)
+	// client ips key the rate limits, so forwarded headers are only believed from known proxies
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
+	router.Use(infrastructure.Recovery(logger, errorReporter))       // turn panics into 500 responses with the request id and report them
+	router.Use(infrastructure.RequestID())          // generate or propagate X-Request-ID
+	router.Use(infrastructure.RequestLogger(logger))        // log every request with status, latency and user
+	router.Use(infrastructure.Compression(config.CompressionMinSize))        // gzip larger bodies (outside the handlers rewriting them)
+	router.Use(infrastructure.ExtensionHeaders(extensions))        // headers added by response.decorate extensions
+	router.Use(infrastructure.AuditContext())       // expose client ip to usecases for auditing
+	deprecations.Annotate(deprecatedRoutes...)
+	router.Use(deprecations.Handler())              // deprecation headers and per-client usage logs of old endpoints
+	router.Use(infrastructure.ReadOnlyGuard(config.ReadOnly, "/login"))       // reject writes on read-only instances
+	router.Use(infrastructure.ResponseFormat(config.ResponseCase, config.ResponseEnvelope))       // key case and envelope negotiated per client
+	router.Use(infrastructure.FieldPolicy(domain.ResponseFieldRules))       // fields hidden by role (after ResponseFormat, sees native keys)
+	router.Use(infrastructure.DeadlineBudget(infrastructure.DeadlineBudgets{
+		Read:    config.ReadTimeout,
+		Write:   config.WriteTimeout,
+		Routes:  map[string]time.Duration{"GET /admin/audit": config.ExportTimeout, "GET /admin/workspace/export": config.ExportTimeout, "POST /admin/workspace/import": config.ExportTimeout, "POST /admin/integrity": config.ExportTimeout, "GET /tasks/export": config.ExportTimeout, "POST /tasks/import": config.ExportTimeout, "POST /admin/webhooks/:id/test": config.WebhookTimeout + time.Second, "GET /auth/oauth/:provider/callback": config.OAuthTimeout, "GET /ws": 0, "GET /tasks/events": 0},       // websocket and event stream connections stay open
+	}))       // per-endpoint time budgets enforced through context deadlines
+	router.Use(apierror.Handler())                  // status and code of errors handlers leave to it (inside the deadline, format and field handlers)
+	router.Use(infrastructure.RequestBodyGuard(infrastructure.RequestBodyPolicy{
+		MaxSize:       config.MaxRequestBodySize,
+		Routes:        map[string]int64{"POST /tasks/import": 0, "POST /admin/workspace/import": 0},       // the import handlers have their own limits
+		ContentTypes:  map[string][]string{"PATCH /tasks/:id": {"application/merge-patch+json", "application/json-patch+json"}, "POST /tasks/import": {"multipart/form-data", "text/csv"}, "POST /oauth/token": {"application/x-www-form-urlencoded", "multipart/form-data"}, "POST /oauth/authorize": {"application/x-www-form-urlencoded"}},
+		Raw:           map[string]bool{"POST /tasks/import": true, "POST /admin/workspace/import": true},       // files are read within those limits, bundles are restored as exported
+		EscapeHTML:    config.EscapeHTMLDescriptions,
+	}))       // body size, content type and string clean-up before binding
 
-	taskContrl := controllers.NewTaskController(taskUsc)        // initialize task controller with task usecase
+	taskContrl := controllers.NewTaskController(taskUsc, taskWorkflow)        // initialize task controller with task usecase
 	userContrl := controllers.NewUserController(userUsc)        // initialize user controller with user usecase
+	passwordResetContrl := controllers.NewPasswordResetController(passwordResetUsc)       // initialize password reset controller with password reset usecase
+	verificationContrl := controllers.NewEmailVerificationController(emailVerificationUsc)       // initialize email verification controller with email verification usecase
+	setupContrl := controllers.NewSetupController(setupUsc)     // initialize setup controller with setup usecase
+	adminInviteContrl := controllers.NewAdminInviteController(adminInviteUsc)      // initialize admin invite controller with admin invite usecase
+	oauthContrl := controllers.NewOAuthController(oauthUsc, userUsc, infrastructure.NewAuthMiddleware(jwtServ, patUsc, sessionUsc, userUsc, auditSink))     // initialize oauth controller with oauth usecase and the consent page's sign-in
+	patContrl := controllers.NewPersonalAccessTokenController(patUsc)      // initialize token controller with token usecase
+	healthContrl := controllers.NewHealthController(config.ReadOnly, dbPing)       // initialize health controller
+	labelContrl := controllers.NewLabelController(labelUsc)                        // initialize label controller with label usecase
+	projectContrl := controllers.NewProjectController(projectUsc)                  // initialize project controller with project usecase
+	taskLockContrl := controllers.NewTaskLockController(taskLockUsc)               // initialize task lock controller with task lock usecase
+	webhookContrl := controllers.NewWebhookController(webhookUsc)                  // initialize webhook controller with webhook usecase
+	taskHistoryContrl := controllers.NewTaskHistoryController(taskHistoryUsc)       // initialize task history controller with task history usecase
+	recurrenceContrl := controllers.NewRecurrenceController(recurrenceUsc, taskWorkflow)          // initialize recurrence controller with recurrence usecase
+	auditLogContrl := controllers.NewAuditLogController(auditLogUsc)               // initialize audit log controller with audit log usecase
+	wsContrl := controllers.NewWebSocketController(taskStreamUsc, eventBus, config.EventStreamPollInterval)       // initialize websocket controller with task event stream usecase
+	taskStreamContrl := controllers.NewTaskStreamController(taskStreamUsc, eventBus, config.EventStreamPollInterval)       // initialize task stream controller with task event stream usecase
+	cacheContrl := controllers.NewCacheController(cacheMetrics)                      // initialize cache controller with task cache counters
+	queryLogContrl := controllers.NewQueryLogController(queryLog)                    // initialize query log controller with query log switch
+	telemetryContrl := controllers.NewTelemetryController(telemetryUsc, config.TelemetryEnabled)       // initialize telemetry controller with telemetry usecase
+	apiUsageContrl := controllers.NewAPIUsageController(apiUsageUsc)                 // initialize api usage controller with api usage usecase
+	tagJobContrl := controllers.NewTagJobController(tagJobUsc)                       // initialize tag job controller with tag job usecase
+	exportContrl := controllers.NewExportController(exportUsc)                       // initialize export controller with export usecase
+	dueDateContrl := controllers.NewDueDateController(dueDateUsc)                    // initialize due date controller with due date usecase
+	dayPlanContrl := controllers.NewDayPlanController(dayPlanUsc, taskWorkflow)                    // initialize day plan controller with day plan usecase
+	securityContrl := controllers.NewSecurityController(sessionUsc)                  // initialize security controller with session usecase
+	jwksContrl := controllers.NewJWKSController(jwtServ)                             // initialize jwks controller with jwt service
+	externalLoginContrl := controllers.NewExternalLoginController(userUsc, oauthServ, config.ReadOnly)       // initialize external login controller with user usecase and oauth service
+	orgContrl := controllers.NewOrganizationController(orgUsc)                       // initialize organization controller with organization usecase
+	workspaceContrl := controllers.NewWorkspaceController(workspaceUsc)              // initialize workspace controller with workspace usecase
+	integrityContrl := controllers.NewIntegrityController(integrityUsc)              // initialize integrity controller with integrity usecase
+	worklogContrl := controllers.NewWorklogController(worklogUsc)                    // initialize worklog controller with worklog usecase
+	reportContrl := controllers.NewReportController(reportUsc)                       // initialize report controller with report usecase
+	taskArchiveContrl := controllers.NewTaskArchiveController(taskArchiveUsc, taskWorkflow)       // initialize task archive controller with task archive usecase
+	dependencyContrl := controllers.NewTaskDependencyController(dependencyUsc)                     // initialize task dependency controller with task dependency usecase
+	templateContrl := controllers.NewTemplateController(templateUsc, taskWorkflow)                 // initialize template controller with template usecase
+	checklistContrl := controllers.NewChecklistController(checklistUsc, taskWorkflow)              // initialize checklist controller with checklist usecase
+	taskTrashContrl := controllers.NewTaskTrashController(taskTrashUsc, taskWorkflow)              // initialize task trash controller with task trash usecase
 
-	// public routes
-	router.POST("/register", userContrl.Register)         // register new user
-	router.POST("/login", userContrl.Login)               // authenticate a user
+	// rate limits (login is stricter to slow down password guessing)
+	loginLimit := infrastructure.RateLimit(infrastructure.NewRateLimiter(config.LoginRateLimit, config.LoginRateBurst))
+	apiLimit := infrastructure.RateLimit(infrastructure.NewRateLimiter(config.APIRateLimit, config.APIRateBurst))
 
-	// authenticated routes
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtServ)
+	// per-token call counters of authenticated requests
+	trackUsage := infrastructure.TrackUsage(usageTracker)
 
-	authGroup := router.Group("")
-	authGroup.Use(authMiddleware.Handler())
-	{
-		authGroup.GET("/tasks", taskContrl.GetAllTasks)             // get all tasks
-		authGroup.GET("/tasks/:id", taskContrl.GetTaskByID)         // get specific task by id
-	}
+	// retried POSTs with an Idempotency-Key get the first response back
+	idempotent := infrastructure.Idempotency(idempotencyRepo, config.IdempotencyTTL, logger)
+
+	// every route of the api, registered once per mounted version
+	registerRoutes := func(api *gin.RouterGroup) {
+
+		// public routes
+		api.GET("/healthz", healthContrl.Health)           // health and mode of the instance
+		api.POST("/setup", loginLimit, setupContrl.CompleteSetup)    // create first admin with the setup token
+		api.POST("/register", apiLimit, userContrl.Register)         // register new user
+		api.POST("/login", loginLimit, userContrl.Login)             // authenticate a user
+		api.POST("/auth/2fa", loginLimit, userContrl.CompleteTwoFactorLogin)       // finish a login with an authenticator app or backup code
+		api.POST("/auth/forgot-password", loginLimit, passwordResetContrl.ForgotPassword)       // email a password reset link
+		api.POST("/auth/reset-password", loginLimit, passwordResetContrl.ResetPassword)         // set new password with a reset token
+		api.GET("/auth/verify", loginLimit, verificationContrl.VerifyEmail)                     // confirm email address with the emailed token
+		api.POST("/auth/resend-verification", loginLimit, verificationContrl.ResendVerification)       // email a new verification link
+		api.GET("/auth/oauth/:provider", loginLimit, externalLoginContrl.StartLogin)            // redirect to google or github sign-in
+		api.GET("/auth/oauth/:provider/callback", loginLimit, externalLoginContrl.Callback)     // finish provider sign-in and issue our token
+		api.POST("/admin/invites/accept", loginLimit, adminInviteContrl.AcceptInvite)          // create an admin account with an invite token
+		api.POST("/oauth/token", apiLimit, oauthContrl.Token)        // exchange authorization code for access token
+		api.GET("/oauth/authorize", apiLimit, oauthContrl.Authorize)          // consent page (signs in with its own cookie, not a bearer token)
+		api.POST("/oauth/authorize", loginLimit, oauthContrl.Consent)         // sign in, approve or deny on the consent page
+
+		// authenticated routes
+		authMiddleware := infrastructure.NewAuthMiddleware(jwtServ, patUsc, sessionUsc, userUsc, auditSink)
 
-	// admin routes
-	adminMiddleware := infrastructure.AdminOnly()
+		// each endpoint declares the permission its role must grant (and the scope third-party tokens need)
+		authGroup := api.Group("")
+		authGroup.Use(authMiddleware.Handler(), trackUsage, apiLimit, idempotent)
+		{
+			authGroup.GET("/tasks", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskContrl.GetAllTasks)                // get all tasks
+			authGroup.GET("/tasks/export", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), exportContrl.ExportTasks)      // download tasks as csv or xlsx
+			authGroup.POST("/tasks/batch-get", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskContrl.BatchGetTasks)     // get many tasks by id in one request
+			authGroup.GET("/tasks/search", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskContrl.SearchTasks)          // full-text search ranked by relevance
+			authGroup.GET("/tasks/suggest-due-date", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), dueDateContrl.SuggestDueDate)      // suggest a due date for a new task
+			authGroup.GET("/tasks/archive", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskArchiveContrl.GetArchivedTasks)          // archived completed tasks, newest first
+			authGroup.GET("/tasks/trash", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskTrashContrl.GetDeletedTasks)              // deleted tasks, newest first
+			authGroup.GET("/tasks/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskContrl.GetTaskByID)            // get specific task by id
+			authGroup.GET("/tasks/:id/subtasks", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskContrl.GetSubtasks)    // get subtasks of a task
+			authGroup.GET("/tasks/:id/as-of", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskHistoryContrl.GetTaskAsOf)        // task as it was at a point in time
+			authGroup.GET("/tasks/:id/history", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskHistoryContrl.GetTaskChanges)    // who changed which field of a task and when
+			authGroup.GET("/tasks/:id/events", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskHistoryContrl.GetTaskHistory)     // stored snapshots of a task (event sourced mode)
+			authGroup.POST("/tasks", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskContrl.CreateTask)              // create new task
+			authGroup.POST("/tasks/import", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskContrl.ImportTasks)      // create tasks from a csv or json file
+			authGroup.POST("/tasks/from-template/:templateId", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), templateContrl.CreateTaskFromTemplate)      // create task from own template
+			authGroup.POST("/tasks/:id/template", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), templateContrl.SaveTaskAsTemplate)                   // save task as own template
+			authGroup.PUT("/tasks/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskContrl.UpdateTask)           // update existing task by id
+			authGroup.PATCH("/tasks/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskContrl.PatchTask)          // change some fields of a task (merge patch or json patch)
+			authGroup.DELETE("/tasks/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskContrl.DeleteTask)        // delete existing task by id
+			authGroup.POST("/tasks/:id/unarchive", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskArchiveContrl.UnarchiveTask)   // move archived task back to the task list
+			authGroup.POST("/tasks/:id/restore", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskTrashContrl.RestoreTask)        // move deleted task back to the task list
+			authGroup.GET("/tasks/:id/lock", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskLockContrl.GetLock)                       // who is editing a task
+			authGroup.POST("/tasks/:id/lock", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskLockContrl.AcquireLock)              // lock task for editing
+			authGroup.POST("/tasks/:id/lock/heartbeat", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskLockContrl.Heartbeat)     // keep edit lock fresh
+			authGroup.DELETE("/tasks/:id/lock", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), taskLockContrl.ReleaseLock)           // release edit lock
+			authGroup.GET("/tasks/:id/worklogs", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), worklogContrl.GetWorklogs)                  // worklogs of a task with time spent
+			authGroup.POST("/tasks/:id/worklogs", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), worklogContrl.AddWorklog)               // record time spent afterwards
+			authGroup.DELETE("/tasks/:id/worklogs/:worklogId", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), worklogContrl.DeleteWorklog)  // delete own worklog (admins any)
+			authGroup.POST("/tasks/:id/timer/start", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), worklogContrl.StartTimer)           // start own timer (one at a time)
+			authGroup.POST("/tasks/:id/timer/stop", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), worklogContrl.StopTimer)             // stop own timer and log the time
+			authGroup.GET("/tasks/:id/dependencies", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), dependencyContrl.GetDependencies)           // blockers of a task and tasks it blocks
+			authGroup.POST("/tasks/:id/dependencies", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), dependencyContrl.AddDependency)        // mark a task as blocked by another
+			authGroup.DELETE("/tasks/:id/dependencies/:blockerId", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), dependencyContrl.RemoveDependency)   // unlink a blocker
+			authGroup.POST("/tasks/:id/checklist", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), checklistContrl.AddItem)                        // add checklist item
+			authGroup.POST("/tasks/:id/checklist/:index/toggle", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), checklistContrl.ToggleItem)      // tick off a checklist item or undo it
+			authGroup.PUT("/tasks/:id/checklist/order", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), checklistContrl.ReorderItems)            // reorder checklist items
+			authGroup.DELETE("/tasks/:id/checklist/:index", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), checklistContrl.DeleteItem)          // delete checklist item
+			authGroup.PUT("/tasks/:id/labels/:labelId", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), labelContrl.AttachLabel)       // attach label to task
+			authGroup.DELETE("/tasks/:id/labels/:labelId", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), labelContrl.DetachLabel)    // detach label from task
+			authGroup.PUT("/tasks/:id/recurrence", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), recurrenceContrl.SetRecurrence)               // start or replace recurrence series
+			authGroup.POST("/tasks/:id/recurrence/pause", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), recurrenceContrl.PauseRecurrence)     // stop creating occurrences
+			authGroup.POST("/tasks/:id/recurrence/resume", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), recurrenceContrl.ResumeRecurrence)   // create occurrences again
+			authGroup.DELETE("/tasks/:id/recurrence", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), recurrenceContrl.EndRecurrence)          // end recurrence series
+			authGroup.GET("/labels", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), labelContrl.GetLabels)                 // get all labels
+			authGroup.GET("/labels/budget", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), labelContrl.BudgetReport)      // label budgets against task costs
+			authGroup.GET("/reports/summary", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), reportContrl.Summary)                 // task counts (workspace for admins, own tasks otherwise)
+			authGroup.GET("/reports/productivity", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), reportContrl.Productivity)       // tasks created and completed and time logged per day
+			authGroup.GET("/labels/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), labelContrl.GetLabelByID)          // get specific label by id
+			authGroup.POST("/labels", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), labelContrl.CreateLabel)            // create new label
+			authGroup.PUT("/labels/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), labelContrl.UpdateLabel)         // update label (renames follow on tasks)
+			authGroup.DELETE("/labels/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), labelContrl.DeleteLabel)      // delete label and detach it from tasks
+			authGroup.GET("/templates", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), templateContrl.GetTemplates)                 // own task templates
+			authGroup.GET("/templates/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), templateContrl.GetTemplateByID)          // get own template by id
+			authGroup.POST("/templates", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), templateContrl.CreateTemplate)            // create task template
+			authGroup.PUT("/templates/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), templateContrl.UpdateTemplate)         // update own template
+			authGroup.DELETE("/templates/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), infrastructure.RequireScope(domain.ScopeWriteTasks), templateContrl.DeleteTemplate)      // delete own template
+			authGroup.GET("/projects", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), projectContrl.GetProjects)                  // projects the caller is a member of
+			authGroup.GET("/projects/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), projectContrl.GetProject)              // get specific project (members only)
+			authGroup.POST("/projects", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.CreateProject)            // create project owned by the caller
+			authGroup.PUT("/projects/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.UpdateProject)         // update project (owners)
+			authGroup.DELETE("/projects/:id", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.DeleteProject)      // delete project without tasks (owners)
+			authGroup.POST("/projects/:id/clone", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.CloneProject)        // copy project, members and open tasks on request (members)
+			authGroup.PUT("/projects/:id/members/:userId", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.SetMember)         // add member or change their role (owners)
+			authGroup.DELETE("/projects/:id/members/:userId", infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeWriteTasks), projectContrl.RemoveMember)   // remove member (owners, or members leaving)
+			authGroup.PUT("/promote/:id", infrastructure.RequirePermission(domain.PermissionUserManage), infrastructure.FirstPartyOnly(), userContrl.PromoteToAdmin)                        // promote user to admin by id
+		}
 
-	adminGroup := router.Group("")
-	adminGroup.Use(authMiddleware.Handler(), adminMiddleware)
-	{
-		adminGroup.POST("/tasks", taskContrl.CreateTask)                 // create new task
-		adminGroup.PUT("/tasks/:id", taskContrl.UpdateTask)              // update existing task by id
-		adminGroup.DELETE("/tasks/:id", taskContrl.DeleteTask)           // delete existing task by id
-		adminGroup.PUT("/promote/:id", userContrl.PromoteToAdmin)        // promote user to admin by id
+		// real-time task events (token may be sent as ?access_token=... since browsers can't set headers here)
+		api.GET("/ws", infrastructure.TokenFromQuery(), authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), wsContrl.TaskEvents)
+		api.GET("/tasks/events", infrastructure.TokenFromQuery(), authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.RequirePermission(domain.PermissionTaskRead), infrastructure.RequireScope(domain.ScopeReadTasks), taskStreamContrl.StreamTaskEvents)      // server-sent events, resumable with Last-Event-ID
+
+		// oauth routes (first-party tokens only, third-party apps can't grant themselves access)
+		oauthGroup := api.Group("/oauth")
+		oauthGroup.Use(authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.FirstPartyOnly())
+		{
+			oauthGroup.POST("/clients", oauthContrl.RegisterClient)       // register third-party client
+		}
+
+		// personal access token routes (tokens can't be used to mint more tokens)
+		meGroup := api.Group("/me")
+		meGroup.Use(authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.FirstPartyOnly(), idempotent)
+		{
+			meGroup.GET("", userContrl.GetProfile)                      // own profile
+			meGroup.PUT("", userContrl.UpdateProfile)                   // update own email, display name and notification preferences
+			meGroup.PUT("/password", userContrl.ChangePassword)         // change own password
+			meGroup.POST("/2fa/setup", userContrl.SetupTwoFactor)      // new authenticator app secret
+			meGroup.POST("/2fa/enable", userContrl.EnableTwoFactor)    // confirm the secret with a code, returns backup codes
+			meGroup.DELETE("/2fa", userContrl.DisableTwoFactor)        // turn off two-factor logins with a code
+			meGroup.POST("/tokens", patContrl.CreateToken)             // mint personal access token
+			meGroup.GET("/tokens", patContrl.ListTokens)               // list own tokens
+			meGroup.DELETE("/tokens/:id", patContrl.RevokeToken)       // revoke own token
+			meGroup.GET("/tokens/:id/usage", apiUsageContrl.TokenUsage)       // calls made with own token per endpoint
+			meGroup.POST("/plan-day", dayPlanContrl.PlanDay)                 // propose an order of today's tasks
+			meGroup.GET("/day", dayPlanContrl.GetMyDay)                      // today's tasks in the chosen order
+			meGroup.PUT("/day", dayPlanContrl.SetMyDay)                      // accept a plan or reorder my day
+		}
+
+		// admin routes (first-party tokens only)
+		// organization routes (members and owners of an organization, first-party tokens only)
+		orgGroup := api.Group("/orgs")
+		orgGroup.Use(authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.FirstPartyOnly(), idempotent)
+		{
+			orgGroup.POST("/invites/accept", orgContrl.AcceptInvite)       // join the organization of an invite (log in again afterwards)
+			orgGroup.GET("/:id", orgContrl.GetOrganization)                // own organization (admins see any)
+			orgGroup.GET("/:id/members", orgContrl.ListMembers)            // members of own organization
+			orgGroup.POST("/:id/invites", orgContrl.CreateInvite)          // invite a user, the token is only shown now (owners)
+		}
+
+		adminGroup := api.Group("/admin")
+		adminGroup.Use(authMiddleware.Handler(), trackUsage, apiLimit, infrastructure.FirstPartyOnly(), idempotent)
+		{
+			adminGroup.GET("/audit", infrastructure.RequirePermission(domain.PermissionAuditRead), auditLogContrl.GetEntries)       // query audit log of changes
+			adminGroup.GET("/telemetry/preview", infrastructure.RequirePermission(domain.PermissionAuditRead), telemetryContrl.Preview)       // usage report exactly as it would be sent
+			adminGroup.GET("/cache", infrastructure.RequirePermission(domain.PermissionAuditRead), cacheContrl.Stats)       // task cache hit and miss counters
+			adminGroup.GET("/debug/query-log", infrastructure.RequirePermission(domain.PermissionAuditRead), queryLogContrl.Status)             // whether database queries are logged
+			adminGroup.PUT("/debug/query-log", infrastructure.RequirePermission(domain.PermissionUserManage), queryLogContrl.Enable)            // log database queries for a few minutes
+			adminGroup.DELETE("/debug/query-log", infrastructure.RequirePermission(domain.PermissionUserManage), queryLogContrl.Disable)        // stop logging database queries
+			adminGroup.GET("/usage", infrastructure.RequirePermission(domain.PermissionAuditRead), apiUsageContrl.UsageRollup)       // api calls per user, token and client
+			adminGroup.GET("/deprecations", infrastructure.RequirePermission(domain.PermissionAuditRead), apiUsageContrl.DeprecatedUsage)       // who still calls deprecated endpoints
+			adminGroup.GET("/users", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.ListUsers)                      // list and search users
+			adminGroup.GET("/users/:id", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.GetUser)                    // user detail with project memberships
+			adminGroup.DELETE("/users/:id", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.DeleteUser)              // delete user, their projects are reassigned or deleted
+			adminGroup.POST("/users/:id/unlock", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.UnlockUser)          // clear a login lockout
+			adminGroup.POST("/users/:id/deactivate", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.DeactivateUser)  // block logins and end sessions
+			adminGroup.POST("/users/:id/reactivate", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.ReactivateUser)  // allow logins again
+			adminGroup.POST("/users/:id/demote", infrastructure.RequirePermission(domain.PermissionUserManage), userContrl.DemoteAdmin)         // turn an admin back into a user
+			adminGroup.POST("/invites", infrastructure.RequirePermission(domain.PermissionUserManage), adminInviteContrl.CreateInvite)         // invite a new admin
+			adminGroup.POST("/orgs", infrastructure.RequirePermission(domain.PermissionUserManage), orgContrl.CreateOrganization)             // create an organization around its first owner
+			adminGroup.GET("/workspace/export", infrastructure.RequirePermission(domain.PermissionUserManage), workspaceContrl.ExportWorkspace)       // download labels, projects, tasks and optionally users
+			adminGroup.POST("/workspace/import", infrastructure.RequirePermission(domain.PermissionUserManage), workspaceContrl.ImportWorkspace)      // recreate an exported workspace under new ids
+			adminGroup.POST("/integrity", infrastructure.RequirePermission(domain.PermissionUserManage), integrityContrl.CheckIntegrity)              // find broken references and drifted fields, repair unless dry run
+			adminGroup.GET("/webhooks", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.GetWebhooks)                     // workspace webhooks
+			adminGroup.POST("/webhooks", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.CreateWebhook)                  // add a webhook, its secret is only shown now
+			adminGroup.GET("/webhooks/:id", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.GetWebhook)                  // webhook with its failing and disabled state
+			adminGroup.PATCH("/webhooks/:id", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.UpdateWebhook)             // change url or events, enable or disable
+			adminGroup.DELETE("/webhooks/:id", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.DeleteWebhook)            // delete webhook and its delivery log
+			adminGroup.GET("/webhooks/:id/deliveries", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.GetDeliveries)    // delivery log, newest first
+			adminGroup.POST("/webhooks/:id/test", infrastructure.RequirePermission(domain.PermissionUserManage), webhookContrl.SendTestEvent)         // send a test event and return its delivery
+			adminGroup.GET("/settings/security", infrastructure.RequirePermission(domain.PermissionUserManage), securityContrl.GetSettings)         // workspace security settings
+			adminGroup.PUT("/settings/security", infrastructure.RequirePermission(domain.PermissionUserManage), securityContrl.UpdateSettings)      // change workspace security settings
+			adminGroup.POST("/tags/rename", infrastructure.RequirePermission(domain.PermissionTaskWrite), tagJobContrl.RenameTag)       // rename a tag on all tasks in the background
+			adminGroup.POST("/tags/merge", infrastructure.RequirePermission(domain.PermissionTaskWrite), tagJobContrl.MergeTags)        // merge one tag into another in the background
+			adminGroup.GET("/tags/jobs/:id", infrastructure.RequirePermission(domain.PermissionTaskWrite), tagJobContrl.GetJob)         // progress of a rename or merge
+			adminGroup.DELETE("/tasks/trash", infrastructure.RequirePermission(domain.PermissionUserManage), taskTrashContrl.PurgeTrash)       // remove deleted tasks for good
+		}
 	}
 
+	// v1 under its prefix, unversioned aliases kept for clients from before versioning
+	registerRoutes(router.Group(infrastructure.APIPrefixV1, infrastructure.APIVersion(infrastructure.APIVersionV1)))
+	if config.LegacyRoutes {
+		registerRoutes(router.Group("", infrastructure.APIVersion(infrastructure.APIVersionV1), infrastructure.Deprecated(infrastructure.APIPrefixV1, config.LegacySunset)))
+	} else {
+		router.GET("/healthz", healthContrl.Health)       // probes keep their path
+	}
+	router.GET("/.well-known/jwks.json", jwksContrl.GetKeys)       // public keys for services verifying our tokens (fixed path, not versioned)
+
+	// api contract (published from the routes above so it can't drift)
+	registerOpenAPI(router, infrastructure.APIPrefixV1, deprecations)
+
+	// unknown paths get the same error body as everything else
+	router.NoRoute(func(c *gin.Context) {
+		apierror.Message(c, http.StatusNotFound, "route not found")
+	})
+
 	return router        // return configured router
 }
\ No newline at end of file
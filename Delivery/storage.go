@@ -0,0 +1,203 @@
+package main
+
+// imports
+import (
+	"context";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"go.mongodb.org/mongo-driver/mongo/readpref";
+)
+
+// repositories of one storage backend, wrapped and shared by the usecases in main
+type storage struct {
+	taskRepo             domain.TaskRepository
+	taskReader           domain.TaskRepository                  // task queries (secondaries when configured)
+	taskHistoryRepo      domain.TaskHistoryRepository           // events of TASK_PERSISTENCE=events (nil in memory)
+	taskChangeRepo       domain.TaskChangeRepository
+	userRepo             domain.UserRepository
+	oauthRepo            domain.OAuthRepository
+	tokenRepo            domain.PersonalAccessTokenRepository
+	auditLogRepo         domain.AuditLogRepository
+	labelRepo            domain.LabelRepository
+	resetRepo            domain.PasswordResetRepository
+	verificationRepo     domain.EmailVerificationRepository
+	challengeRepo        domain.TwoFactorChallengeRepository
+	usageRepo            domain.UsageStatsRepository
+	settingsRepo         domain.SettingsRepository
+	adminInviteRepo      domain.AdminInviteRepository
+	apiUsageRepo         domain.APIUsageRepository
+	tagJobRepo           domain.TagJobRepository
+	myDayRepo            domain.MyDayRepository
+	sessionRepo          domain.SessionRepository
+	idempotencyRepo      domain.IdempotencyRepository
+	projectRepo          domain.ProjectRepository
+	orgRepo              domain.OrganizationRepository
+	taskLockRepo         domain.TaskLockRepository
+	worklogRepo          domain.WorklogRepository
+	dependencyRepo       domain.TaskDependencyRepository
+	eventLogRepo         domain.TaskEventLogRepository
+	reportRepo           domain.ReportRepository
+	archiveRepo          domain.TaskArchiveRepository
+	trashRepo            domain.TaskTrashRepository
+	webhookRepo          domain.WebhookRepository
+	webhookDeliveryRepo  domain.WebhookDeliveryRepository
+	integrityRepo        domain.IntegrityRepository
+	templateRepo         domain.TemplateRepository
+	migrationRepo        domain.MigrationRepository
+	textSearch           domain.SearchService                   // text index of the task read model (nil in memory)
+	unitOfWork           domain.UnitOfWork
+	ping                 func(ctx context.Context) error        // health check of the database
+	close                func(ctx context.Context)
+}
+
+// connect to mongodb and build the repositories over its collections
+func openMongoStorage(ctx context.Context, config *infrastructure.Config, queryLog *infrastructure.QueryLogger, logger domain.Logger) (*storage, error) {
+
+	// connect
+	clientOptions := options.Client().ApplyURI(config.MongoURI)
+	clientOptions.SetMonitor(queryLog.Monitor())       // admins can log queries for a while (off until then)
+	if config.ReadOnly {
+		clientOptions.SetReadPreference(readpref.SecondaryPreferred())       // read-only instances read from secondaries
+	}
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	db := client.Database("taskmanager")
+	taskCol := db.Collection("tasks")         // initialize task collection
+	taskReadCol := db.Collection("tasks", options.Collection().SetReadPreference(queryReadPreference(config)))       // initialize task collection for queries
+	userCol := db.Collection("users")         // initialize user collection
+	oauthClientCol := db.Collection("oauth_clients")       // initialize oauth client collection
+	oauthCodeCol := db.Collection("oauth_codes")           // initialize oauth authorization code collection
+	tokenCol := db.Collection("personal_access_tokens")    // initialize personal access token collection
+	auditLogCol := db.Collection("audit_log")              // initialize audit log collection
+	labelCol := db.Collection("labels")                    // initialize label collection
+	taskEventCol := db.Collection("task_events")           // initialize task event collection
+	taskChangeCol := db.Collection("task_history")         // initialize task change collection
+	resetTokenCol := db.Collection("password_reset_tokens")       // initialize password reset token collection
+	verificationTokenCol := db.Collection("email_verification_tokens")       // initialize email verification token collection
+	challengeCol := db.Collection("two_factor_challenges")        // initialize two-factor login challenge collection
+	settingsCol := db.Collection("settings")                      // initialize settings collection
+	adminInviteCol := db.Collection("admin_invites")              // initialize admin invite collection
+	apiUsageCol := db.Collection("api_usage")                     // initialize api usage collection
+	tagJobCol := db.Collection("tag_jobs")                        // initialize tag job collection
+	myDayCol := db.Collection("my_day")                           // initialize my day collection
+	sessionCol := db.Collection("sessions")                       // initialize session collection
+	idempotencyCol := db.Collection("idempotency_keys")           // initialize idempotency key collection
+	projectCol := db.Collection("projects")                       // initialize project collection
+	taskLockCol := db.Collection("task_locks")                    // initialize task lock collection
+	worklogCol := db.Collection("worklogs")                       // initialize worklog collection
+	archiveCol := db.Collection("archived_tasks")                 // initialize archived task collection
+	trashCol := db.Collection("deleted_tasks")                    // initialize deleted task collection
+	dependencyCol := db.Collection("task_dependencies")           // initialize task dependency collection
+	eventLogCol := db.Collection("task_event_log")                // initialize task event log collection
+	webhookCol := db.Collection("webhooks")                       // initialize webhook collection
+	webhookDeliveryCol := db.Collection("webhook_deliveries")     // initialize webhook delivery collection
+	orgCol := db.Collection("organizations")                      // initialize organization collection
+	orgInviteCol := db.Collection("organization_invites")         // initialize organization invite collection
+	templateCol := db.Collection("task_templates")                // initialize task template collection
+	migrationCol := db.Collection("schema_migrations")            // initialize applied migration collection
+
+	unitOfWork := repositories.NewDirectUnitOfWork()       // multi-document changes run in transactions where the server supports them
+	supported, err := repositories.TransactionsSupported(ctx, client)
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	if supported {
+		unitOfWork = repositories.NewMongoUnitOfWork(client)
+	} else {
+		logger.Warn(ctx, "mongodb server is standalone, multi-document changes run without transactions")
+	}
+
+	return &storage{
+		taskRepo:             repositories.NewTaskRepository(taskCol),
+		taskReader:           repositories.NewTaskRepository(taskReadCol),
+		taskHistoryRepo:      repositories.NewTaskHistoryRepository(taskEventCol),
+		taskChangeRepo:       repositories.NewTaskChangeRepository(taskChangeCol),
+		userRepo:             repositories.NewUserRepository(userCol),
+		oauthRepo:            repositories.NewOAuthRepository(oauthClientCol, oauthCodeCol),
+		tokenRepo:            repositories.NewPersonalAccessTokenRepository(tokenCol),
+		auditLogRepo:         repositories.NewAuditLogRepository(auditLogCol),
+		labelRepo:            repositories.NewLabelRepository(labelCol),
+		resetRepo:            repositories.NewPasswordResetRepository(resetTokenCol),
+		verificationRepo:     repositories.NewEmailVerificationRepository(verificationTokenCol),
+		challengeRepo:        repositories.NewTwoFactorChallengeRepository(challengeCol),
+		usageRepo:            repositories.NewUsageStatsRepository(taskCol, userCol, oauthClientCol, tokenCol),
+		settingsRepo:         repositories.NewSettingsRepository(settingsCol),
+		adminInviteRepo:      repositories.NewAdminInviteRepository(adminInviteCol),
+		apiUsageRepo:         repositories.NewAPIUsageRepository(apiUsageCol),
+		tagJobRepo:           repositories.NewTagJobRepository(tagJobCol),
+		myDayRepo:            repositories.NewMyDayRepository(myDayCol),
+		sessionRepo:          repositories.NewSessionRepository(sessionCol),
+		idempotencyRepo:      repositories.NewIdempotencyRepository(idempotencyCol),
+		projectRepo:          repositories.NewProjectRepository(projectCol),
+		orgRepo:              repositories.NewOrganizationRepository(orgCol, orgInviteCol),
+		taskLockRepo:         repositories.NewTaskLockRepository(taskLockCol),
+		worklogRepo:          repositories.NewWorklogRepository(worklogCol),
+		dependencyRepo:       repositories.NewTaskDependencyRepository(dependencyCol),
+		eventLogRepo:         repositories.NewTaskEventLogRepository(eventLogCol),
+		reportRepo:           repositories.NewReportRepository(taskReadCol, taskChangeCol, worklogCol),
+		archiveRepo:          repositories.NewTaskArchiveRepository(taskCol, archiveCol),
+		trashRepo:            repositories.NewTaskTrashRepository(trashCol),
+		webhookRepo:          repositories.NewWebhookRepository(webhookCol),
+		webhookDeliveryRepo:  repositories.NewWebhookDeliveryRepository(webhookDeliveryCol),
+		integrityRepo:        repositories.NewIntegrityRepository(taskCol, projectCol, labelCol, orgCol, userCol, tokenCol, sessionCol, myDayCol),
+		templateRepo:         repositories.NewTemplateRepository(templateCol),
+		migrationRepo:        repositories.NewMigrationRepository(migrationCol),
+		textSearch:           repositories.NewMongoSearchService(taskReadCol),
+		unitOfWork:           unitOfWork,
+		ping: func(ctx context.Context) error {
+			return client.Ping(ctx, readpref.Nearest())       // reachable member is enough for health
+		},
+		close: func(ctx context.Context) { client.Disconnect(ctx) },
+	}, nil
+}
+
+// keep everything in process memory, no database is needed (reports, integrity checks, usage statistics and the archive are unavailable)
+func newMemoryStorage() *storage {
+
+	taskRepo := repositories.NewMemoryTaskRepository()
+	return &storage{
+		taskRepo:             taskRepo,
+		taskReader:           taskRepo,        // one store serves commands and queries
+		taskChangeRepo:       repositories.NewMemoryTaskChangeRepository(),
+		userRepo:             repositories.NewMemoryUserRepository(),
+		oauthRepo:            repositories.NewMemoryOAuthRepository(),
+		tokenRepo:            repositories.NewMemoryPersonalAccessTokenRepository(),
+		auditLogRepo:         repositories.NewMemoryAuditLogRepository(),
+		labelRepo:            repositories.NewMemoryLabelRepository(),
+		resetRepo:            repositories.NewMemoryPasswordResetRepository(),
+		verificationRepo:     repositories.NewMemoryEmailVerificationRepository(),
+		challengeRepo:        repositories.NewMemoryTwoFactorChallengeRepository(),
+		usageRepo:            repositories.NewMemoryUsageStatsRepository(),
+		settingsRepo:         repositories.NewMemorySettingsRepository(),
+		adminInviteRepo:      repositories.NewMemoryAdminInviteRepository(),
+		apiUsageRepo:         repositories.NewMemoryAPIUsageRepository(),
+		tagJobRepo:           repositories.NewMemoryTagJobRepository(),
+		myDayRepo:            repositories.NewMemoryMyDayRepository(),
+		sessionRepo:          repositories.NewMemorySessionRepository(),
+		idempotencyRepo:      repositories.NewMemoryIdempotencyRepository(),
+		projectRepo:          repositories.NewMemoryProjectRepository(),
+		orgRepo:              repositories.NewMemoryOrganizationRepository(),
+		taskLockRepo:         repositories.NewMemoryTaskLockRepository(),
+		worklogRepo:          repositories.NewMemoryWorklogRepository(),
+		dependencyRepo:       repositories.NewMemoryTaskDependencyRepository(),
+		eventLogRepo:         repositories.NewMemoryTaskEventLogRepository(),
+		reportRepo:           repositories.NewMemoryReportRepository(),
+		archiveRepo:          repositories.NewMemoryTaskArchiveRepository(),
+		trashRepo:            repositories.NewMemoryTaskTrashRepository(),
+		webhookRepo:          repositories.NewMemoryWebhookRepository(),
+		webhookDeliveryRepo:  repositories.NewMemoryWebhookDeliveryRepository(),
+		integrityRepo:        repositories.NewMemoryIntegrityRepository(),
+		templateRepo:         repositories.NewMemoryTemplateRepository(),
+		migrationRepo:        repositories.NewMemoryMigrationRepository(),
+		unitOfWork:           repositories.NewDirectUnitOfWork(),
+		ping:                 func(ctx context.Context) error { return nil },
+		close:                func(ctx context.Context) {},
+	}
+}
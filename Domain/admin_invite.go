@@ -0,0 +1,44 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// admin invite item (single use, removed by a ttl index once expired)
+type AdminInvite struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                 // mongodb's unique identifier for invites
+	TokenHash    string                `bson:"token_hash" json:"-"`                     // sha256 of the token (the token itself is only returned once)
+	Email        string                `bson:"email,omitempty" json:"email,omitempty"`  // address the new admin must use (any when empty)
+	CreatedBy    string                `bson:"created_by" json:"created_by"`            // id of the admin who sent the invite
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`            // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`            // creation time
+}
+
+// admin invite payload
+type CreateAdminInviteRequest struct {
+	Email        string      `json:"email" binding:"omitempty,email,max=254"`       // restrict the invite to this address
+}
+
+// admin invite acceptance payload
+type AcceptAdminInviteRequest struct {
+	Token        string      `json:"token" binding:"required"`                                  // invite token (required field)
+	Username     string      `json:"username" binding:"required,min=3,max=32,alphanum"`        // admin username (required field)
+	Password     string      `json:"password" binding:"required,max=72,strongpassword"`        // admin password (required field, bcrypt limit 72 bytes)
+	Email        string      `json:"email" binding:"omitempty,email,max=254"`                   // admin email address (must match the invite when it names one)
+}
+
+// admin invite repository interface
+type AdminInviteRepository interface {
+	CreateInvite(ctx context.Context, invite *AdminInvite) error                                       // store new invite
+	ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*AdminInvite, error)          // atomically remove an unexpired invite and return it, or return error if none
+	EnsureIndexes(ctx context.Context) error                                                           // create ttl and lookup indexes
+}
+
+// custom admin invite errors
+var (
+	ErrInvalidAdminInvite = errors.New("invalid or expired admin invite")       // custom invite error
+)
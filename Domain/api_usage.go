@@ -0,0 +1,36 @@
+package domain
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// api calls of one user, token or client on one endpoint
+type APIUsage struct {
+	UserID      string       `bson:"user_id" json:"user_id"`                        // caller
+	TokenID     string       `bson:"token_id" json:"token_id,omitempty"`            // personal access token used (empty for login sessions)
+	ClientID    string       `bson:"client_id" json:"client_id,omitempty"`          // oauth client the token was issued to (empty for first-party calls)
+	Endpoint    string       `bson:"endpoint" json:"endpoint"`                      // method and route, e.g. "GET /tasks/:id"
+	Count       int64        `bson:"count" json:"count"`                            // number of calls
+	LastUsedAt  time.Time    `bson:"last_used_at" json:"last_used_at"`              // most recent call
+}
+
+// api calls of one user, token or client across all endpoints
+type APIUsageRollup struct {
+	UserID      string       `bson:"user_id" json:"user_id"`
+	TokenID     string       `bson:"token_id" json:"token_id,omitempty"`
+	ClientID    string       `bson:"client_id" json:"client_id,omitempty"`
+	Count       int64        `bson:"count" json:"count"`                            // calls across all endpoints
+	Endpoints   int          `bson:"endpoints" json:"endpoints"`                    // distinct endpoints called
+	LastUsedAt  time.Time    `bson:"last_used_at" json:"last_used_at"`              // most recent call
+}
+
+// api usage counter store
+type APIUsageRepository interface {
+	AddUsage(ctx context.Context, usage []APIUsage) error                                // add counts and move last used times forward
+	GetTokenUsage(ctx context.Context, tokenID string) ([]APIUsage, error)               // per-endpoint usage of a token, busiest first
+	GetUsageRollup(ctx context.Context, limit int64) ([]APIUsageRollup, error)          // usage per user, token and client, busiest first
+	GetEndpointUsage(ctx context.Context, endpoints []string) ([]APIUsage, error)       // callers of the endpoints, most recent first
+	EnsureIndexes(ctx context.Context) error                                             // create indexes if missing
+}
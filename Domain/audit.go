@@ -0,0 +1,100 @@
+package domain
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// security event types streamed to audit sinks
+const (
+	AuditLoginSucceeded      = "auth.login_succeeded"
+	AuditLoginFailed         = "auth.login_failed"
+	AuditTokenRejected       = "auth.token_rejected"
+	AuditUserRegistered      = "user.registered"
+	AuditOrgCreated          = "org.created"
+	AuditOrgInvited          = "org.member_invited"
+	AuditOrgJoined           = "org.member_joined"
+	AuditIdentityLinked      = "user.identity_linked"
+	AuditSetupCompleted      = "setup.completed"
+	AuditUserPromoted        = "user.promoted"
+	AuditAdminInvited        = "user.admin_invited"
+	AuditUserLocked          = "user.locked"
+	AuditUserUnlocked        = "user.unlocked"
+	AuditUserDemoted         = "user.demoted"
+	AuditUserDeactivated     = "user.deactivated"
+	AuditUserReactivated     = "user.reactivated"
+	AuditUserDeleted         = "user.deleted"
+	AuditPasswordResetRequested = "auth.password_reset_requested"
+	AuditPasswordReset       = "auth.password_reset"
+	AuditPasswordChanged     = "auth.password_changed"
+	AuditEmailVerificationSent = "auth.email_verification_sent"
+	AuditEmailVerified       = "auth.email_verified"
+	AuditTwoFactorEnabled    = "auth.two_factor_enabled"
+	AuditTwoFactorDisabled   = "auth.two_factor_disabled"
+	AuditTokenCreated        = "token.created"
+	AuditTokenRevoked        = "token.revoked"
+	AuditOAuthClientCreated  = "oauth.client_created"
+	AuditOAuthConsentGranted = "oauth.consent_granted"
+	AuditSessionRevoked      = "auth.session_revoked"
+	AuditSecurityUpdated     = "settings.security_updated"
+	AuditTasksPurged         = "task.purged"
+)
+
+// audit event outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// audit event item (security relevant action)
+type AuditEvent struct {
+	Timestamp   time.Time             `json:"timestamp"`                  // when the event happened (UTC)
+	Type        string                `json:"type"`                       // event type (e.g. auth.login_failed)
+	Outcome     string                `json:"outcome"`                    // success/failure
+	ActorID     string                `json:"actor_id,omitempty"`         // user who performed the action
+	Actor       string                `json:"actor,omitempty"`            // username of the actor
+	TargetID    string                `json:"target_id,omitempty"`        // entity the action was performed on
+	ClientIP    string                `json:"client_ip,omitempty"`        // remote address of the request
+	Details     map[string]string     `json:"details,omitempty"`          // extra context (reason, scopes, ...)
+}
+
+// audit sink interface (delivery must not fail or block the request)
+type AuditSink interface {
+	Emit(ctx context.Context, event AuditEvent)       // send event to the sink, errors are handled by the sink
+}
+
+type clientIPKey struct{}
+
+// store the client ip on a request context so deeper layers can audit it
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// read the client ip stored on a request context
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+type actorKey struct{}
+
+// authenticated user performing a request
+type Actor struct {
+	ID        string      // user id
+	Username  string      // username
+	Role      string      // user role (admins see every project)
+	TenantID  string      // organization of the user (empty for the default workspace)
+	SessionID string      // login session of the request's token (empty for tokens without one)
+}
+
+// store the authenticated actor on a request context
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// read the authenticated actor stored on a request context
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
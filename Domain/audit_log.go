@@ -0,0 +1,71 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// audited actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionPromote = "promote"
+	AuditActionUnlock  = "unlock"
+	AuditActionDemote  = "demote"
+	AuditActionDeactivate = "deactivate"
+	AuditActionReactivate = "reactivate"
+	AuditActionImport = "import"
+	AuditActionRepair = "repair"
+	AuditActionArchive = "archive"
+	AuditActionUnarchive = "unarchive"
+	AuditActionRestore = "restore"
+	AuditActionPurge = "purge"
+)
+
+// audited entity types
+const (
+	AuditEntityTask = "task"
+	AuditEntityUser = "user"
+	AuditEntityLabel = "label"
+	AuditEntityProject = "project"
+	AuditEntityWebhook = "webhook"
+	AuditEntityOrganization = "organization"
+	AuditEntityWorkspace = "workspace"
+)
+
+// audit log entry (who changed what, with before/after snapshots)
+type AuditLogEntry struct {
+	ID          primitive.ObjectID        `bson:"_id,omitempty" json:"id"`                      // mongodb's unique identifier for entries
+	ActorID     string                    `bson:"actor_id" json:"actor_id"`                      // user who performed the action
+	Actor       string                    `bson:"actor" json:"actor"`                            // username of the actor
+	Action      string                    `bson:"action" json:"action"`                          // create/update/delete/promote
+	EntityType  string                    `bson:"entity_type" json:"entity_type"`                // task/user
+	EntityID    string                    `bson:"entity_id" json:"entity_id"`                    // id of the changed entity
+	Before      map[string]interface{}    `bson:"before,omitempty" json:"before,omitempty"`      // entity before the change (nil on create)
+	After       map[string]interface{}    `bson:"after,omitempty" json:"after,omitempty"`        // entity after the change (nil on delete)
+	Details     map[string]string         `bson:"details,omitempty" json:"details,omitempty"`    // extra context (cascaded subtasks, ...)
+	Timestamp   time.Time                 `bson:"timestamp" json:"timestamp"`                    // when the change happened (UTC)
+}
+
+// audit log query filters
+type AuditLogFilter struct {
+	ActorID  string        // only entries by this user (empty for all)
+	From     time.Time     // only entries at or after this time (zero for no bound)
+	To       time.Time     // only entries at or before this time (zero for no bound)
+	Limit    int64         // maximum number of entries, newest first
+}
+
+// audit log repository interface
+type AuditLogRepository interface {
+	RecordEntry(ctx context.Context, entry *AuditLogEntry) error                          // store new audit log entry
+	GetEntries(ctx context.Context, filter AuditLogFilter) ([]AuditLogEntry, error)       // find entries matching filter, newest first
+}
+
+// audit log errors
+var (
+	ErrInvalidAuditFilter = errors.New("invalid audit log filter")        // returned when filter values can't be parsed
+)
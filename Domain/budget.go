@@ -0,0 +1,48 @@
+package domain
+
+// imports
+import (
+	"math";
+)
+
+// cost of a task for client work (amounts in major units of the currency, e.g. 12.50)
+type TaskCost struct {
+	Estimated  float64   `bson:"estimated,omitempty" json:"estimated,omitempty" binding:"min=0,max=1000000000"`       // expected cost
+	Actual     float64   `bson:"actual,omitempty" json:"actual,omitempty" binding:"min=0,max=1000000000"`             // cost so far
+	Currency   string    `bson:"currency" json:"currency" binding:"required,iso4217"`                                  // ISO 4217 code (e.g. EUR)
+}
+
+// budget of a project (labels group the tasks of a project or client)
+type Budget struct {
+	Amount    float64   `bson:"amount" json:"amount" binding:"min=0,max=1000000000"`           // money available for the label's tasks
+	Currency  string    `bson:"currency" json:"currency" binding:"required,iso4217"`          // ISO 4217 code, only task costs in it count against the budget
+}
+
+// costs of tasks in one currency
+type CostTotal struct {
+	Currency   string    `json:"currency"`
+	Estimated  float64   `json:"estimated"`
+	Actual     float64   `json:"actual"`
+	Tasks      int       `json:"tasks"`             // tasks with a cost in the currency
+}
+
+// budget of a label against the costs of its tasks
+type LabelBudget struct {
+	Label          string        `json:"label"`
+	Budget         *Budget       `json:"budget,omitempty"`                // nil when the label has no budget
+	Costs          []CostTotal   `json:"costs"`                           // task costs per currency
+	Remaining      *float64      `json:"remaining,omitempty"`             // budget minus actual costs in the budget currency
+	OverBudget     bool          `json:"over_budget"`
+	OtherCurrency  int           `json:"other_currency_tasks"`            // tasks costed in another currency, not counted against the budget
+}
+
+// budget report (labels with a budget or costed tasks, totals over all tasks)
+type BudgetReport struct {
+	Labels  []LabelBudget  `json:"labels"`
+	Totals  []CostTotal    `json:"totals"`         // per currency, amounts in different currencies are never added up
+}
+
+// round an amount to cents
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,40 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// key value cache interface (redis, ...)
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)                          // get value or return ErrCacheMiss
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error   // store value for ttl
+	Incr(ctx context.Context, key string) (int64, error)                          // atomically increment a counter and return the new value
+}
+
+// cache hit and miss counters since startup
+type CacheStats struct {
+	Enabled   bool       `json:"enabled"`       // whether reads are cached at all
+	Hits      uint64     `json:"hits"`          // reads answered from the cache
+	Misses    uint64     `json:"misses"`        // reads that went to the database
+	Errors    uint64     `json:"errors"`        // cache operations that failed (reads fell back to the database)
+	HitRate   float64    `json:"hit_rate"`      // hits / (hits + misses), 0 before the first read
+}
+
+// source of cache statistics
+type CacheMetrics interface {
+	CacheStats() CacheStats       // current counters
+}
+
+// task repository that caches reads and reports its hit rate
+type CachedTaskRepository interface {
+	TaskRepository
+	CacheMetrics
+}
+
+// custom cache errors
+var (
+	ErrCacheMiss = errors.New("cache miss")       // key not in cache
+)
@@ -0,0 +1,39 @@
+package domain
+
+// imports
+import (
+	"errors";
+)
+
+// most items a task or template checklist can have
+const MaxChecklistItems = 100
+
+// step of a task checklist
+type ChecklistItem struct {
+	Text  string   `bson:"text" json:"text" binding:"required,max=200"`       // what has to be done
+	Done  bool     `bson:"done" json:"done"`                                  // ticked off
+}
+
+// how far a task's checklist is done
+type ChecklistProgress struct {
+	Done     int   `json:"done"`         // items ticked off
+	Total    int   `json:"total"`        // items in the checklist
+	Percent  int   `json:"percent"`      // done items in percent, rounded down
+}
+
+// new checklist item (added at the end unless a position is given)
+type ChecklistItemInput struct {
+	Text      string   `json:"text" binding:"required,max=200"`       // what has to be done (required field)
+	Position  *int     `json:"position" binding:"omitempty,min=0"`    // index the item is inserted at
+}
+
+// new checklist order
+type ChecklistOrderRequest struct {
+	Order  []int   `json:"order" binding:"required"`       // current index of every item, in the new order
+}
+
+// custom checklist errors
+var (
+	ErrChecklistItemNotFound  = errors.New("checklist item not found")                                      // custom missing item error
+	ErrInvalidChecklistOrder  = errors.New("order must list every checklist item index exactly once")       // custom reorder error
+)
@@ -0,0 +1,111 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// working hours of a day as offsets from midnight
+type WorkingHours struct {
+	Start  time.Duration        // when work starts (e.g. 9h)
+	End    time.Duration        // when work ends (e.g. 17h)
+}
+
+// parse "HH:MM" start and end of the working day
+func ParseWorkingHours(start string, end string) (WorkingHours, error) {
+
+	var hours WorkingHours
+	var err error
+	if hours.Start, err = ParseClock(start); err != nil {
+		return WorkingHours{}, err
+	}
+	if hours.End, err = ParseClock(end); err != nil {
+		return WorkingHours{}, err
+	}
+	if hours.End <= hours.Start {
+		return WorkingHours{}, ErrInvalidWorkingHours
+	}
+
+	return hours, nil
+}
+
+// parse "HH:MM" into an offset from midnight
+func ParseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ErrInvalidWorkingHours
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// plan day request (empty fields use the server defaults)
+type PlanDayRequest struct {
+	WorkStart  string  `json:"work_start" binding:"omitempty,datetime=15:04"`     // start of the working day (HH:MM)
+	WorkEnd    string  `json:"work_end" binding:"omitempty,datetime=15:04"`       // end of the working day (HH:MM)
+	Timezone   string  `json:"timezone" binding:"omitempty,timezone"`             // IANA zone the day and hours are in (UTC when empty)
+}
+
+// task placed in a day plan
+type PlannedTask struct {
+	TaskID             primitive.ObjectID  `json:"task_id"`                        // planned task
+	Title              string              `json:"title"`                          // title of the task
+	Priority           string              `json:"priority"`                       // priority of the task
+	DueDate            time.Time           `json:"due_date"`                       // due date of the task
+	Estimate           int                 `json:"estimate"`                       // minutes planned for the task
+	EstimateDefaulted  bool                `json:"estimate_defaulted,omitempty"`   // task has no estimate, the default was used
+	Start              *time.Time          `json:"start,omitempty"`                // planned start (nil when deferred)
+	End                *time.Time          `json:"end,omitempty"`                  // planned end (nil when deferred)
+	AtRisk             bool                `json:"at_risk,omitempty"`              // planned to end after its due date
+	Reasons            []string            `json:"reasons"`                        // why the task is placed where it is
+}
+
+// proposed order of today's tasks
+type DayPlan struct {
+	Date              string         `json:"date"`                 // planned day (YYYY-MM-DD in the timezone)
+	Timezone          string         `json:"timezone"`             // zone of the day and times
+	WorkStart         time.Time      `json:"work_start"`           // start of the working day
+	WorkEnd           time.Time      `json:"work_end"`             // end of the working day
+	AvailableMinutes  int            `json:"available_minutes"`    // working time left when the plan was made
+	PlannedMinutes    int            `json:"planned_minutes"`      // estimates of the planned tasks
+	Tasks             []PlannedTask  `json:"tasks"`                // tasks in the proposed order
+	Deferred          []PlannedTask  `json:"deferred"`             // tasks that don't fit in the working hours, in the same order
+}
+
+// my day item (the user's accepted order of today's tasks)
+type MyDay struct {
+	UserID     string                `bson:"_id" json:"-"`                   // owner of the list
+	Date       string                `bson:"date" json:"date"`               // day the order is for (YYYY-MM-DD)
+	Timezone   string                `bson:"timezone" json:"timezone"`       // zone of the day
+	TaskIDs    []primitive.ObjectID  `bson:"task_ids" json:"task_ids"`       // tasks in the chosen order
+	UpdatedAt  time.Time             `bson:"updated_at" json:"updated_at"`   // last change
+}
+
+// set my day request (usually the task ids of an accepted plan)
+type SetMyDayRequest struct {
+	TaskIDs   []string  `json:"task_ids" binding:"required,max=100,dive,mongodb"`      // tasks in the chosen order
+	Timezone  string    `json:"timezone" binding:"omitempty,timezone"`                 // zone of the day (UTC when empty)
+}
+
+// my day with its tasks
+type MyDayTasks struct {
+	Date      string  `json:"date"`          // day the order is for
+	Timezone  string  `json:"timezone"`      // zone of the day
+	Tasks     []Task  `json:"tasks"`         // tasks in the chosen order (deleted ones are left out)
+}
+
+// my day repository interface
+type MyDayRepository interface {
+	GetMyDay(ctx context.Context, userID string) (*MyDay, error)      // get the user's order or return error if none
+	SaveMyDay(ctx context.Context, day *MyDay) error                  // store the user's order, replacing the earlier one
+}
+
+// custom day plan errors
+var (
+	ErrMyDayNotFound        = errors.New("my day not found")                                   // custom missing my day error
+	ErrInvalidWorkingHours  = errors.New("working hours must be HH:MM with start before end")   // custom working hours error
+	ErrDuplicateDayTask     = errors.New("task is listed more than once")                      // custom duplicate my day task error
+	ErrInvalidTimezone      = errors.New("unknown timezone")                                   // custom invalid timezone error
+)
@@ -0,0 +1,70 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"fmt";
+	"strings";
+	"time";
+)
+
+// deprecated endpoint (announced in Deprecation, Sunset and Link headers)
+type Deprecation struct {
+	Endpoint   string       `json:"endpoint"`                   // method and route, e.g. "GET /tasks/:id/events"
+	Sunset     *time.Time   `json:"sunset,omitempty"`           // planned removal
+	Successor  string       `json:"successor,omitempty"`        // route to use instead, e.g. "/tasks/:id/history"
+}
+
+// callers of a deprecated endpoint
+type DeprecatedEndpointUsage struct {
+	Deprecation
+	Calls    int64         `json:"calls"`          // calls across all callers
+	Callers  []APIUsage    `json:"callers"`        // one entry per user, token and client, most recent first
+}
+
+// deprecated endpoints known to the server (configured and annotated)
+type DeprecationRegistry interface {
+	Deprecations() []Deprecation       // deprecated endpoints sorted by endpoint
+}
+
+// custom deprecation errors
+var ErrInvalidDeprecation = errors.New("invalid deprecated route")
+
+// parse deprecated routes from configuration
+// entries are separated by ";", e.g. "GET /tasks/:id/events sunset=2027-01-31 successor=/tasks/:id/history; DELETE /labels/:id"
+func ParseDeprecations(raw string) ([]Deprecation, error) {
+
+	deprecations := []Deprecation{}
+	for _, entry := range strings.Split(raw, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || strings.ToUpper(fields[0]) != fields[0] || !strings.HasPrefix(fields[1], "/") {
+			return nil, fmt.Errorf("%w: %q, expected \"METHOD /route\"", ErrInvalidDeprecation, strings.TrimSpace(entry))
+		}
+
+		deprecation := Deprecation{Endpoint: fields[0] + " " + fields[1]}
+		for _, option := range fields[2:] {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "sunset":
+				sunset, err := time.Parse("2006-01-02", value)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s sunset must be a date like 2027-01-31", ErrInvalidDeprecation, deprecation.Endpoint)
+				}
+				deprecation.Sunset = &sunset
+			case "successor":
+				if !strings.HasPrefix(value, "/") {
+					return nil, fmt.Errorf("%w: %s successor must be a route like /tasks/:id", ErrInvalidDeprecation, deprecation.Endpoint)
+				}
+				deprecation.Successor = value
+			default:
+				return nil, fmt.Errorf("%w: %s has unknown option %q", ErrInvalidDeprecation, deprecation.Endpoint, option)
+			}
+		}
+		deprecations = append(deprecations, deprecation)
+	}
+
+	return deprecations, nil
+}
@@ -2,6 +2,7 @@ package domain
 
 // imports
 import (
+	"context";
 	"errors";
 	"time";
 	"github.com/dgrijalva/jwt-go";
@@ -11,18 +12,178 @@ import (
 // task item
 type Task struct {
 	ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                     // unique identifier of task generated by mongodb
-	Title         string                `bson:"title" json:"title"`                  		           // title of task
-	Description   string                `bson:"description" json:"description"`    				     // description of task
+	Title         string                `bson:"title" json:"title" binding:"max=200"`                  		           // title of task
+	Description   string                `bson:"description" json:"description" binding:"max=2000"`    				     // description of task
 	DueDate       time.Time             `bson:"due_date" json:"due_date"`  		                                // due date of task (ISO 8601 format)
-	Status        string      			`bson:"status" json:"status" binding:"oneof=pending in_progress completed"`       // status of task
+	Status        string      			`bson:"status" json:"status" binding:"omitempty,oneof=pending in_progress completed"`       // status of task
+	StatusEnteredAt map[string]time.Time `bson:"status_entered_at,omitempty" json:"status_entered_at,omitempty"`             // when the task last entered each status (maintained by the server)
+	Priority      string                `bson:"priority" json:"priority" binding:"omitempty,oneof=low medium high urgent"`        // priority of task (low/medium/high/urgent)
+	PriorityRank  int                   `bson:"priority_rank" json:"-"`                                                          // numeric priority used for sorting
+	Estimate      int                   `bson:"estimate,omitempty" json:"estimate,omitempty" binding:"omitempty,min=1,max=1440"`  // estimated effort in minutes (used to plan the day)
+	ParentID      *primitive.ObjectID   `bson:"parent_id,omitempty" json:"parent_id,omitempty"`                                  // parent task when this is a subtask
+	Reminder      *ReminderSettings     `bson:"reminder,omitempty" json:"reminder,omitempty"`                                    // due date reminder settings
+	IsOverdue     bool                  `bson:"is_overdue" json:"is_overdue"`                                                    // past due and not completed (maintained by the server)
+	Tags          []string              `bson:"tags,omitempty" json:"tags,omitempty" binding:"omitempty,max=20"`                  // names of attached labels
+	Checklist     []ChecklistItem       `bson:"checklist,omitempty" json:"checklist,omitempty" binding:"-"`                      // steps of the task in order (changed through the checklist endpoints)
+	Progress      *ChecklistProgress    `bson:"-" json:"progress,omitempty"`                                                     // how far the checklist is done (set on reads, nil without a checklist)
+	Recurrence    *Recurrence           `bson:"recurrence,omitempty" json:"recurrence,omitempty"`                                // recurrence rule (set through /tasks/:id/recurrence)
+	Cost          *TaskCost             `bson:"cost,omitempty" json:"cost,omitempty"`                                            // estimated and actual cost for client work
+	ClientID      string                `bson:"client_id,omitempty" json:"client_id,omitempty"`                                  // id generated by an offline client, unique (set on create only)
+	ProjectID     *primitive.ObjectID   `bson:"project_id,omitempty" json:"project_id,omitempty"`                                // project the task belongs to (none when nil)
+	Language      string                `bson:"language,omitempty" json:"language,omitempty" binding:"omitempty,oneof=en es fr de it pt nl"`       // language of title and description (detected when not given, picks the text analyzer)
+	TenantID      string                `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`                                  // organization of the task (set from the creator, empty for the default workspace)
+	CreatedAt     time.Time             `bson:"created_at" json:"created_at"`                                                    // creation time (set by the server)
+	UpdatedAt     time.Time             `bson:"updated_at" json:"updated_at"`                                                    // last change, including server maintained fields (set by the server)
+	ExpectedUpdatedAt *time.Time        `bson:"-" json:"-"`                                                                      // update precondition: only change the task if it was last changed at this time (never stored)
+}
+
+// task priorities ordered by rank
+var TaskPriorities = map[string]int{
+	"low":     1,
+	"medium":  2,
+	"high":    3,
+	"urgent":  4,
+}
+
+const DefaultTaskPriority = "medium"       // priority of tasks created without one
+
+// validate priority and set its sortable rank
+func (task *Task) ApplyPriority() error {
+	if task.Priority == "" {
+		return nil
+	}
+	rank, ok := TaskPriorities[task.Priority]
+	if !ok {
+		return ErrInvalidPriority
+	}
+	task.PriorityRank = rank
+	return nil
+}
+
+// sortable task fields mapped to their document keys
+var TaskSortFields = map[string]string{
+	"title":     "title",
+	"due_date":  "due_date",
+	"status":    "status",
+	"priority":  "priority_rank",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// task sort field item
+type SortField struct {
+	Field       string      // api field name (e.g. due_date)
+	Descending  bool        // sort direction
+}
+
+// task list query (sorting and filtering options)
+type TaskQuery struct {
+	Sort            []SortField      // applied in order
+	Overdue         *bool            // only overdue (true) or not overdue (false) tasks, nil for all
+	Labels          []string         // only tasks with these labels
+	MatchAllLabels  bool             // require every label instead of any of them
+	ProjectID       *primitive.ObjectID      // only tasks of this project
+	Visibility      *ProjectVisibility       // only tasks the caller may see (set by the usecase, nil for all)
+	Search          string           // words to find in title or description (text index, stemmed in Language)
+	Language        string           // only tasks in this language
+	UpdatedSince    *time.Time       // only tasks changed after this time (sync clients)
+	TenantID        *string          // only tasks of this organization (set by the tenant repository, nil for all)
+	IDs             []primitive.ObjectID     // only these tasks
 }
 
 // user item
 type User struct {
 	ID           primitive.ObjectID     `bson:"_id,omitempty" json:"id"`         // mongodb's unique identifier for users 
 	Username     string                 `bson:"username" json:"username"`        // username 
+	Email        string                 `bson:"email,omitempty" json:"email,omitempty"`       // email address for password resets
+	EmailVerificationPending bool       `bson:"email_verification_pending,omitempty" json:"email_verification_pending,omitempty"`       // registered with an email that wasn't verified yet
+	EmailVerifiedAt *time.Time          `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`       // when the owner confirmed the email address
+	DisplayName  string                 `bson:"display_name,omitempty" json:"display_name,omitempty"`       // name shown instead of the username
 	Password     string      	    `bson:"password" json:"password"`        // password (hashed before storage)
 	Role         string      	    `bson:"role" json:"role"`                // user role (role/user)
+	FailedLogins int                    `bson:"failed_logins" json:"-"`                              // failed login attempts since the last success or lock
+	LockedUntil  *time.Time             `bson:"locked_until,omitempty" json:"locked_until,omitempty"` // login blocked until this time
+	DeactivatedAt *time.Time            `bson:"deactivated_at,omitempty" json:"deactivated_at,omitempty"`       // account disabled by an admin (nil while active)
+	Identities   []LinkedIdentity       `bson:"identities,omitempty" json:"identities,omitempty"`         // google/github accounts the user signs in with
+	TwoFactor    *TwoFactorSettings     `bson:"two_factor,omitempty" json:"-"`                            // authenticator app secret and backup codes
+	TwoFactorEnabled bool               `bson:"-" json:"two_factor_enabled,omitempty"`                    // logins need an app code (set on profiles)
+	TenantID     string                 `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`           // organization of the user (empty for the default workspace)
+	Notifications *NotificationPreferences `bson:"notifications,omitempty" json:"notifications,omitempty"`       // what the user is emailed about (nothing when nil)
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`                             // registration time (set by the server)
+	UpdatedAt    time.Time              `bson:"updated_at" json:"updated_at"`                             // last change of the account (set by the server)
+}
+
+// check if user is locked out at the given time
+func (user *User) IsLocked(now time.Time) bool {
+	return user.LockedUntil != nil && now.Before(*user.LockedUntil)
+}
+
+// task creation payload
+type CreateTaskRequest struct {
+	Title         string       `json:"title" binding:"required,max=200"`                                   // title of task (required field)
+	Description   string       `json:"description" binding:"required,max=2000"`                            // description of task (required field)
+	DueDate       time.Time    `json:"due_date" binding:"required"`                                        // due date of task (required field)
+	Status        string       `json:"status" binding:"omitempty,oneof=pending in_progress completed"`     // status of task
+	Priority      string       `json:"priority" binding:"omitempty,oneof=low medium high urgent"`          // priority of task
+	Estimate      int          `json:"estimate" binding:"omitempty,min=1,max=1440"`                        // estimated effort in minutes
+	ParentID      *primitive.ObjectID    `json:"parent_id"`                                                // parent task when creating a subtask
+	Reminder      *ReminderSettings      `json:"reminder"`                                                 // due date reminder settings
+	Tags          []string               `json:"tags" binding:"omitempty,max=20"`                          // names of labels to attach
+	Recurrence    *Recurrence            `json:"recurrence"`                                               // repeat the task on a schedule
+	Cost          *TaskCost              `json:"cost"`                                                     // estimated and actual cost
+	ClientID      string                 `json:"client_id" binding:"omitempty,uuid"`                       // uuid generated by an offline client, a retry returns the task created first
+	ProjectID     *primitive.ObjectID    `json:"project_id"`                                               // project of the task (editor role needed)
+	Language      string                 `json:"language" binding:"omitempty,oneof=en es fr de it pt nl"`        // language of title and description (detected when omitted)
+}
+
+// convert creation payload into task
+func (req *CreateTaskRequest) ToTask() *Task {
+	return &Task{
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		Status:      req.Status,
+		Priority:    req.Priority,
+		Estimate:    req.Estimate,
+		ParentID:    req.ParentID,
+		Reminder:    req.Reminder,
+		Tags:        req.Tags,
+		Recurrence:  req.Recurrence,
+		Cost:        req.Cost,
+		ClientID:    req.ClientID,
+		ProjectID:   req.ProjectID,
+		Language:    req.Language,
+	}
+}
+
+// registration payload
+type RegisterRequest struct {
+	Username     string      `json:"username" binding:"required,min=3,max=32,alphanum"`       // username (required field)
+	Password     string      `json:"password" binding:"required,max=72,strongpassword"`       // password (required field, bcrypt limit 72 bytes)
+	Email        string      `json:"email" binding:"omitempty,email,max=254"`                  // email address for password resets
+}
+
+// profile update payload (empty fields are left unchanged)
+type UpdateProfileRequest struct {
+	Email        string      `json:"email" binding:"omitempty,email,max=254"`           // email address for password resets
+	DisplayName  string      `json:"display_name" binding:"omitempty,max=100"`          // name shown instead of the username
+	Notifications *NotificationPreferences `json:"notifications"`                    // replaces the notification preferences when given
+}
+
+// password change payload
+type ChangePasswordRequest struct {
+	CurrentPassword  string      `json:"current_password" binding:"required"`                          // password in use now (required field)
+	NewPassword      string      `json:"new_password" binding:"required,max=72,strongpassword"`       // new password (required field, bcrypt limit 72 bytes)
+}
+
+// initial setup payload (first admin account)
+type SetupRequest struct {
+	Token        string      `json:"token" binding:"required"`                                  // one-time setup token printed at startup (required field)
+	Username     string      `json:"username" binding:"required,min=3,max=32,alphanum"`        // admin username (required field)
+	Password     string      `json:"password" binding:"required,max=72,strongpassword"`        // admin password (required field, bcrypt limit 72 bytes)
+	Email        string      `json:"email" binding:"omitempty,email,max=254"`                   // admin email address (required when smtp is given, receives the test email)
+	WorkspaceName string     `json:"workspace_name" binding:"omitempty,max=100"`                // name of this installation
+	SMTP         *SMTPSettings `json:"smtp"`                                                    // outgoing email settings (environment defaults when omitted)
 }
 
 // credential item
@@ -31,43 +192,121 @@ type Credentials struct {
         Password 	 string 	 `json:"password" binding:"required"`       // login password (required field)
 }
 
+// task read model interface (query side)
+type TaskReader interface {
+	GetAllTasks(ctx context.Context, query TaskQuery) ([]Task, error)         	       // get all tasks in the system matching query
+	GetTaskByID(ctx context.Context, taskID string) (*Task, error) 		       // get specific task by id or return error if not found
+	GetSubtasks(ctx context.Context, parentID string) ([]Task, error)              // get direct children of a task
+	StreamTasks(ctx context.Context, query TaskQuery, fn func(task *Task) error) error      // pass tasks matching query to fn one at a time (stops at the first error)
+}
+
 // task repository interface 
 type TaskRepository interface {
-	CreateTask(task *Task) (*Task, error)                     // create new task with validation
-	DeleteTask(taskID string) error                           // delete existing task or return error if not found
-	GetAllTasks() ([]Task, error)         			  // get all tasks in the system
-	GetTaskByID(taskID string) (*Task, error) 		  // get specific task by id or return error if not found
-	UpdateTask(taskID string, task *Task) (*Task, error)      // update existing task or return error if not found
+	TaskReader
+	CreateTask(ctx context.Context, task *Task) (*Task, error)                     // create new task with validation (ErrTaskClientIDExists for a taken client id)
+	GetTaskByClientID(ctx context.Context, clientID string) (*Task, error)         // get task created with a client id or return error if not found
+	CreateTasks(ctx context.Context, tasks []*Task) error                          // create many tasks at once (ids are set on the tasks)
+	RestoreTasks(ctx context.Context, tasks []*Task) error                         // put tasks back under their ids, keeping created_at (updated_at is set to now)
+	DeleteTask(ctx context.Context, taskID string) error                           // delete existing task or return error if not found
+	UpdateTask(ctx context.Context, taskID string, update *TaskUpdate) (*Task, error)      // change the set fields of an existing task (nil leaves a field, empty values clear it) or return error if not found
+	GetDescendantIDs(ctx context.Context, taskID string) ([]primitive.ObjectID, error)      // get ids of all tasks below a task in the hierarchy
+	DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error                    // delete many tasks at once
+	AddTag(ctx context.Context, taskID string, tag string) (*Task, error)                   // attach label name to a task
+	RemoveTag(ctx context.Context, taskID string, tag string) (*Task, error)                // detach label name from a task
+	RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error                 // follow a label rename on every task
+	RemoveTagFromAll(ctx context.Context, tag string) error                                 // detach a deleted label from every task
+	UpdateOverdueFlags(ctx context.Context, now time.Time) (int64, error)                   // recompute is_overdue for tasks whose state changed, returns number of tasks updated
+	BackfillPriorities(ctx context.Context) (int64, error)                                  // give tasks stored before priorities existed the default priority and fix ranks, returns number of tasks updated
+	BackfillTimestamps(ctx context.Context) (int64, error)                                  // give tasks stored before timestamps existed their creation time, returns number of tasks updated
+	EnsureIndexes(ctx context.Context) error                                                // create indexes used by task queries
+	GetTasksDueForReminder(ctx context.Context, dueBefore time.Time) ([]Task, error)        // get unfinished tasks with pending reminders due before a time
+	MarkReminderSent(ctx context.Context, taskID primitive.ObjectID, sentAt time.Time) error      // record that a reminder went out
+	GetRecurringTasksDue(ctx context.Context, now time.Time) ([]Task, error)                     // get occurrences of active series that are completed or due by a time
+	SetRecurrence(ctx context.Context, taskID string, rule *Recurrence) (*Task, error)            // replace recurrence rule of a task (nil removes it) or return error if not found
+	AdvanceRecurrence(ctx context.Context, taskID primitive.ObjectID, at time.Time) (bool, error)      // mark an occurrence as advanced, false when another run already did
 }
 
 // user repository interface
 type UserRepository interface {    
-	CreateUser(user *User) error                              // create new user with validation
-	GetByUsername(username string) (*User, error)             // get specific user by username or return error if not found
-	GetUserById(id primitive.ObjectID) (*User, error)         // get specific user by id or return error if not found
-	GetUserCount() (int64, error)                             // get total user count or return error 
-	UpdateRole(id primitive.ObjectID, role string) error      // update user's role to admin or return error if not found                            
+	CreateUser(ctx context.Context, user *User) error                              // create new user with validation
+	GetByUsername(ctx context.Context, username string) (*User, error)             // get specific user by username or return error if not found
+	GetUserById(ctx context.Context, id primitive.ObjectID) (*User, error)         // get specific user by id or return error if not found
+	GetByEmail(ctx context.Context, email string) (*User, error)                   // get specific user by email or return error if not found
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error      // replace password hash or return error if not found
+	UpdateProfile(ctx context.Context, id primitive.ObjectID, profile *UpdateProfileRequest) (*User, error)       // update provided profile fields or return error if not found
+	GetUserCount(ctx context.Context) (int64, error)                               // get total user count or return error 
+	UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error      // update user's role to admin or return error if not found                            
+	IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error)       // count a failed login, returns failures so far
+	LockUser(ctx context.Context, id primitive.ObjectID, until time.Time) error         // block logins until a time and reset the failure count
+	ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error                 // clear failure count and lock or return error if not found
+	MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string, at time.Time) error       // confirm the email address if it is still the user's or return error if not found
+	SetTwoFactor(ctx context.Context, id primitive.ObjectID, settings *TwoFactorSettings) error        // replace two-factor settings (nil removes them) or return error if not found
+	UseTwoFactorStep(ctx context.Context, id primitive.ObjectID, step int64) error      // accept an app code once, error if a code of this or a later step was used
+	UseBackupCode(ctx context.Context, id primitive.ObjectID, codeHash string) error    // remove an unused backup code, error if it isn't one
+	ListUsers(ctx context.Context, query UserQuery) ([]User, int64, error)              // page of users matching query ordered by username, with the total match count
+	SetDeactivated(ctx context.Context, id primitive.ObjectID, at *time.Time) error     // deactivate an account at a time (nil reactivates) or return error if not found
+	DeleteUser(ctx context.Context, id primitive.ObjectID) error                        // delete user or return error if not found
+	GetByIdentity(ctx context.Context, provider string, subject string) (*User, error)  // get user linked to a provider account or return error if not found
+	LinkIdentity(ctx context.Context, id primitive.ObjectID, identity LinkedIdentity) error       // link a provider account or return error if not found
+	SetTenant(ctx context.Context, id primitive.ObjectID, tenantID string) error        // move a user into an organization or return error if not found
+	BackfillTimestamps(ctx context.Context) (int64, error)                              // give users stored before timestamps existed their creation time, returns number of users updated
+	EnsureIndexes(ctx context.Context) error                                            // create unique username, email and linked account indexes
 }
 
 // jwt service interface
 type JWTService interface {
-	GenerateToken(userID, username, role string) (string, error)       // generate token or return error
+	GenerateToken(userID, username, role, tenantID, sessionID string) (string, error)       // generate login token for a session or return error
+	GenerateScopedToken(userID, username, role, tenantID, clientID string, scopes []string, ttl time.Duration) (string, error)       // generate third-party token limited to scopes
 	ValidateToken(tokenStr string) (*jwt.Token, error)                 // validate token or return error
+	JWKS() JSONWebKeySet                                               // public keys other services verify tokens with
+}
+
+// public signing key in jwk format (rsa keys set n and e, ec keys set crv, x and y)
+type JSONWebKey struct {
+	KeyType    string   `json:"kty"`                // RSA or EC
+	KeyID      string   `json:"kid"`                // matches the kid header of tokens signed with the key
+	Use        string   `json:"use"`                // always sig
+	Algorithm  string   `json:"alg"`                // RS256 or ES256
+	N          string   `json:"n,omitempty"`        // rsa modulus (base64url)
+	E          string   `json:"e,omitempty"`        // rsa exponent (base64url)
+	Curve      string   `json:"crv,omitempty"`      // ec curve (P-256)
+	X          string   `json:"x,omitempty"`        // ec x coordinate (base64url)
+	Y          string   `json:"y,omitempty"`        // ec y coordinate (base64url)
+}
+
+// published signing keys (hmac secrets are never included)
+type JSONWebKeySet struct {
+	Keys  []JSONWebKey  `json:"keys"`
 }
 
 // password service interface
 type PasswordService interface {
 	HashPassword(password string) (string, error)       // hash password or return error
 	CheckPassword(hashed, plain string) bool            // check password and return bool (true/false)
+	NeedsRehash(hashed string) bool                     // check if hash should be replaced (hashing settings changed)
 }
 
 // custom errors
 var (
 	ErrTaskNotFound      = errors.New("task not found")              // custom task not found error
 	ErrInvalidTaskID     = errors.New("invalid task ID")             // custom invalid task id error
+	ErrInvalidPriority   = errors.New("invalid task priority")       // custom invalid priority error
+	ErrInvalidSortField  = errors.New("invalid sort field")          // custom invalid sort field error
+	ErrParentNotFound    = errors.New("parent task not found")       // custom missing parent error
+	ErrTaskCycle         = errors.New("task cannot be its own ancestor")       // custom hierarchy cycle error
+	ErrTaskHasSubtasks   = errors.New("task has subtasks, delete them first or use cascade")       // custom delete blocked error
+	ErrTaskClientIDExists = errors.New("task with this client id already exists")       // custom duplicate client id error
+	ErrTaskModified      = errors.New("task was changed since it was read, fetch it again")       // custom stale update error
 	ErrUserExists        = errors.New("user already exists")         // custom user exists error
 	ErrUserNotFound      = errors.New("user not found")              // custom user not found error
 	ErrInvalidUserID     = errors.New("invalid user ID")             // custom invalid user id error
 	ErrInvalidCredentials = errors.New("invalid credentials")        // custom invalid credentials error
+	ErrWrongPassword     = errors.New("current password is incorrect")       // custom password change error
+	ErrSetupRequired     = errors.New("initial setup not completed, create the admin account with POST /setup first")       // custom fresh database error
+	ErrSetupCompleted    = errors.New("initial setup already completed")      // custom repeated setup error
+	ErrInvalidSetupToken = errors.New("invalid setup token")                  // custom setup token error
+	ErrAccountLocked     = errors.New("account locked after too many failed logins, try again later")       // custom account lockout error
 	ErrUnauthorized      = errors.New("unauthorized access")         // custom unauthorized access error
+	ErrPartialResult     = errors.New("request deadline exceeded, results are incomplete")       // custom partial list error (results returned with it)
+	ErrMemoryStorage     = errors.New("not available with in-memory storage, run with STORAGE=mongo")       // custom feature unavailable error (STORAGE=memory)
 )
@@ -0,0 +1,47 @@
+package domain
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// domain event types (task events reuse the task event types)
+const (
+	EventUserRegistered       = "user.registered"
+	EventUserPromoted         = "user.promoted"
+	EventUserUpdated          = "user.updated"
+	EventUserLocked           = "user.locked"
+	EventUserUnlocked         = "user.unlocked"
+	EventUserPasswordChanged  = "user.password_changed"
+	EventUserDemoted          = "user.demoted"
+	EventUserDeactivated      = "user.deactivated"
+	EventUserReactivated      = "user.reactivated"
+	EventUserDeleted          = "user.deleted"
+)
+
+// domain event item (state change published for downstream systems)
+type DomainEvent struct {
+	ID          string          `json:"id"`                     // unique event id (consumers can dedupe on it)
+	Type        string          `json:"type"`                   // event type (e.g. task.created, user.promoted)
+	EntityType  string          `json:"entity_type"`            // changed entity (task/user)
+	EntityID    string          `json:"entity_id"`              // id of the changed entity
+	ActorID     string          `json:"actor_id,omitempty"`     // user who made the change (empty for the server)
+	TenantID    string          `json:"tenant_id,omitempty"`    // organization the change happened in (empty for the default workspace)
+	Data        interface{}     `json:"data,omitempty"`         // entity after the change, without secrets (omitted on delete)
+	OccurredAt  time.Time       `json:"occurred_at"`            // when the change happened (UTC)
+}
+
+// domain event handler
+type DomainEventHandler func(ctx context.Context, event DomainEvent)
+
+// event publisher interface (publishing must not fail or block the request)
+type EventPublisher interface {
+	Publish(ctx context.Context, event DomainEvent)       // deliver event, failures are handled by the publisher
+}
+
+// event bus interface (in-process publisher with subscribers)
+type EventBus interface {
+	EventPublisher
+	Subscribe(handler DomainEventHandler)        // receive every published event
+}
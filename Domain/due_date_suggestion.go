@@ -0,0 +1,35 @@
+package domain
+
+// imports
+import (
+	"time";
+)
+
+// suggestion confidence levels
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// facts a due date suggestion is based on (gathered once, shared by every strategy)
+type DueDateInput struct {
+	Title        string               // title of the task being planned
+	Now          time.Time            // time of the request (UTC)
+	Completions  []TaskCompletion     // tasks the user completed recently, latest first
+	OpenDueDates []time.Time          // due dates of unfinished tasks (current workload)
+}
+
+// suggested due date with why it was chosen
+type DueDateSuggestion struct {
+	DueDate     time.Time    `json:"due_date"`       // suggested due date (UTC)
+	Confidence  string       `json:"confidence"`     // low, medium or high
+	BasedOn     int          `json:"based_on"`       // completed tasks the estimate comes from
+	Reasons     []string     `json:"reasons"`        // "strategy: why" for every strategy that changed the suggestion
+}
+
+// due date heuristic, strategies run in order and each may refine what the previous ones suggested
+type DueDateStrategy interface {
+	Name() string                                                        // short name shown with its reasons
+	Apply(input *DueDateInput, suggestion *DueDateSuggestion) string     // set or adjust the suggestion, returns why (empty when unchanged)
+}
@@ -0,0 +1,43 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// email verification token item (single use, removed by a ttl index once expired)
+type EmailVerificationToken struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`          // mongodb's unique identifier for verification tokens
+	UserID       primitive.ObjectID    `bson:"user_id" json:"user_id"`           // user whose email is verified
+	Email        string                `bson:"email" json:"email"`               // address the link was sent to
+	TokenHash    string                `bson:"token_hash" json:"-"`              // sha256 of the token (the token itself is only emailed)
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`     // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`     // creation time
+}
+
+// resend verification payload
+type ResendVerificationRequest struct {
+	Email        string      `json:"email" binding:"required,email"`        // email address of the account (required field)
+}
+
+// email verification token repository interface
+type EmailVerificationRepository interface {
+	CreateToken(ctx context.Context, token *EmailVerificationToken) error                     // store new verification token
+	GetTokenByHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)     // get token by its hash or return error if not found
+	DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error                     // invalidate every verification token of a user
+	EnsureIndexes(ctx context.Context) error                                                   // create ttl and lookup indexes
+}
+
+// email verifier interface (used by registration)
+type EmailVerifier interface {
+	SendVerification(ctx context.Context, user *User) error       // email a verification link to the user's address
+}
+
+// custom email verification errors
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")       // custom verification token error
+	ErrEmailNotVerified         = errors.New("email address is not verified")                      // custom unverified login error
+)
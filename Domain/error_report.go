@@ -0,0 +1,23 @@
+package domain
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// crash captured while serving a request
+type ErrorReport struct {
+	Message    string       // panic value or error text
+	Stack      string       // goroutine stack at the time of the crash
+	RequestID  string       // correlation id returned to the client
+	Method     string       // http method of the request
+	Path       string       // request path
+	UserID     string       // authenticated user (empty when not signed in)
+	Time       time.Time    // when it happened
+}
+
+// error reporter interface (sentry, ...), reports are sent in the background
+type ErrorReporter interface {
+	Report(ctx context.Context, report ErrorReport)        // hand a crash to the error tracker, never blocks the request
+}
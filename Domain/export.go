@@ -0,0 +1,12 @@
+package domain
+
+// export file formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// destination of a tabular export, one call per row (header first)
+type RowWriter interface {
+	WriteRow(cells []string) error
+}
@@ -0,0 +1,53 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+)
+
+// extension points
+const (
+	HookPreTaskCreate     = "task.pre_create"       // before a task is stored (may modify or reject it)
+	HookPostTaskCreate    = "task.post_create"      // after a task is stored
+	HookPreLogin          = "auth.pre_login"        // before credentials are checked (may reject)
+	HookDecorateResponse  = "response.decorate"     // before a response is sent (may add headers)
+)
+
+// extension item (go plugin or http extension), hooks are the optional interfaces below
+type Extension interface {
+	Name() string       // shown in logs
+}
+
+// extension hooking task creation before storage
+type PreTaskCreateHook interface {
+	PreTaskCreate(ctx context.Context, task *Task) error       // modify task or return error to reject
+}
+
+// extension hooking task creation after storage
+type PostTaskCreateHook interface {
+	PostTaskCreate(ctx context.Context, task *Task) error      // observe created task (errors are only logged)
+}
+
+// extension hooking login
+type PreLoginHook interface {
+	PreLogin(ctx context.Context, username string) error       // return error to reject the login
+}
+
+// extension decorating responses
+type DecorateResponseHook interface {
+	DecorateResponse(ctx context.Context, method string, path string, status int) (map[string]string, error)       // extra response headers
+}
+
+// registered extensions called at each extension point
+type ExtensionHooks interface {
+	PreTaskCreate(ctx context.Context, task *Task) error
+	PostTaskCreate(ctx context.Context, task *Task)
+	PreLogin(ctx context.Context, username string) error
+	DecorateResponse(ctx context.Context, method string, path string, status int) map[string]string
+}
+
+// custom extension errors
+var (
+	ErrRejectedByExtension = errors.New("rejected by extension")        // wrapped with the extension's reason
+)
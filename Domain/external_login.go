@@ -0,0 +1,46 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// external identity providers users can sign in with
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+// verified identity returned by an external provider
+type ExternalIdentity struct {
+	Provider       string      // google or github
+	Subject        string      // provider's stable user id
+	Email          string      // email address at the provider (may be empty)
+	EmailVerified  bool        // provider confirmed the address belongs to the user
+	Username       string      // login name at the provider, suggested username of new accounts
+	Name           string      // full name, used as display name of new accounts
+}
+
+// provider account linked to a user
+type LinkedIdentity struct {
+	Provider   string      `bson:"provider" json:"provider"`        // google or github
+	Subject    string      `bson:"subject" json:"subject"`          // provider's stable user id
+	LinkedAt   time.Time   `bson:"linked_at" json:"linked_at"`      // when the account was linked
+}
+
+// oauth2 / openid connect client of the external providers
+type OAuthService interface {
+	Providers() []string                                                                              // configured providers
+	AuthCodeURL(provider string, state string) (string, error)                                        // provider sign-in page the user is sent to
+	Exchange(ctx context.Context, provider string, code string, state string) (*ExternalIdentity, error)       // redeem the callback code and verify the identity
+}
+
+// custom external login errors
+var (
+	ErrUnknownProvider      = errors.New("unknown or unconfigured login provider")                    // custom unknown provider error
+	ErrInvalidOAuthState    = errors.New("sign-in state missing or mismatched, start the sign-in again")       // custom csrf state error
+	ErrExternalLoginFailed  = errors.New("provider did not confirm the sign-in")                      // custom code exchange or verification error
+	ErrIdentityLinkRequired = errors.New("an account with this email exists but its email isn't verified, sign in with its password instead")       // custom unverified account link error
+)
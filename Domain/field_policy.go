@@ -0,0 +1,18 @@
+package domain
+
+// response field rule (the field is stripped from responses of callers without access)
+type FieldRule struct {
+	Field       string        // json key, wherever it appears in a response
+	Permission  Permission    // callers with this permission see the field
+	OwnerKey    string        // callers also see it on objects whose OwnerKey holds their own user id (none when empty)
+}
+
+// fields hidden by role, applied to every json response
+var ResponseFieldRules = []FieldRule{
+	{Field: "email", Permission: PermissionUserManage, OwnerKey: "id"},              // other users' email addresses
+	{Field: "locked_until", Permission: PermissionUserManage},                      // login lockout state
+	{Field: "client_ip", Permission: PermissionAuditRead, OwnerKey: "user_id"},      // where other users sign in from
+	{Field: "series_id", Permission: PermissionTaskWrite},                          // recurrence bookkeeping (maintained by the server)
+	{Field: "advanced_at", Permission: PermissionTaskWrite},                        // recurrence bookkeeping (maintained by the server)
+	{Field: "sent_at", Permission: PermissionTaskWrite},                            // reminder bookkeeping (maintained by the server)
+}
@@ -0,0 +1,45 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"       // request header naming a retryable write
+
+const MaxIdempotencyKeyLength = 255                   // longer keys are rejected
+
+// stored outcome of a request sent with an idempotency key (status 0 while the first request runs)
+type IdempotencyRecord struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty"`
+	Key          string                `bson:"key"`                              // client's idempotency key
+	Caller       string                `bson:"caller"`                           // user (or token) the key belongs to, keys of different callers never match
+	RequestHash  string                `bson:"request_hash"`                     // method, path and body of the first request
+	Status       int                   `bson:"status"`                           // response status, 0 until the first request finished
+	ContentType  string                `bson:"content_type,omitempty"`
+	Body         []byte                `bson:"body,omitempty"`                   // response body as written by the handler
+	CreatedAt    time.Time             `bson:"created_at"`
+	ExpiresAt    time.Time             `bson:"expires_at"`                       // key can be reused after this time
+}
+
+// check if the first request with the key has finished
+func (record *IdempotencyRecord) Completed() bool {
+	return record.Status != 0
+}
+
+// idempotency key repository interface
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)                   // store a new key, returns the existing record instead when the caller already used it
+	Complete(ctx context.Context, recordID primitive.ObjectID, status int, contentType string, body []byte) error      // save the response of the first request
+	Release(ctx context.Context, recordID primitive.ObjectID) error                                        // drop a key whose request failed so it can be retried
+	EnsureIndexes(ctx context.Context) error                                                              // unique key per caller, expire old keys
+}
+
+// custom idempotency errors
+var (
+	ErrIdempotencyKeyInUse     = errors.New("a request with this idempotency key is still being processed")       // custom concurrent retry error
+	ErrIdempotencyKeyMismatch  = errors.New("idempotency key was already used with a different request")         // custom reused key error
+)
@@ -0,0 +1,84 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// integrity checks (references to deleted documents and drifted server maintained fields)
+const (
+	IntegritySubtaskMissingParent    = "subtask_missing_parent"       // subtask of a deleted task
+	IntegrityTaskMissingProject      = "task_missing_project"         // task of a deleted project
+	IntegrityTaskMissingLabel        = "task_missing_label"           // tag without a label
+	IntegrityMissingOrganization     = "missing_organization"         // task or user of a deleted organization
+	IntegrityMemberMissingUser       = "project_member_missing_user"  // project membership of a deleted user
+	IntegrityTokenMissingUser        = "token_missing_user"           // personal access token of a deleted user
+	IntegritySessionMissingUser      = "session_missing_user"         // session of a deleted user
+	IntegrityDayPlanMissingTask      = "day_plan_missing_task"        // deleted task in a my day list
+	IntegrityPriorityRankDrift       = "priority_rank_drift"          // sort rank not matching the priority
+	IntegrityOverdueFlagDrift        = "overdue_flag_drift"           // overdue flag not matching due date and status
+)
+
+// checks in the order they run
+var IntegrityChecks = []string{IntegritySubtaskMissingParent, IntegrityTaskMissingProject, IntegrityTaskMissingLabel, IntegrityMissingOrganization,
+	IntegrityMemberMissingUser, IntegrityTokenMissingUser, IntegritySessionMissingUser, IntegrityDayPlanMissingTask, IntegrityPriorityRankDrift, IntegrityOverdueFlagDrift}
+
+// how each check repairs its problems (checks not listed only report)
+// tenant ids are never guessed, moving documents between organizations is left to an admin
+var IntegrityRepairs = map[string]string{
+	IntegritySubtaskMissingParent:  "the subtask becomes a top-level task",
+	IntegrityTaskMissingProject:    "the task is taken out of the project",
+	IntegrityTaskMissingLabel:      "the tag is removed from the task",
+	IntegrityMemberMissingUser:     "the membership is removed",
+	IntegrityTokenMissingUser:      "the token is deleted",
+	IntegritySessionMissingUser:    "the session is deleted",
+	IntegrityDayPlanMissingTask:    "the task is removed from the list",
+	IntegrityPriorityRankDrift:     "the rank is recomputed",
+	IntegrityOverdueFlagDrift:      "the flag is recomputed",
+}
+
+// issues listed per check in a report (all are counted and repaired)
+const MaxIntegrityIssuesListed = 50
+
+// document found broken by a check
+type IntegrityIssue struct {
+	Collection  string   `json:"collection"`         // collection of the broken document
+	DocumentID  string   `json:"document_id"`        // broken document
+	Reference   string   `json:"reference"`          // missing id or name, or the drifted value
+}
+
+// outcome of one check
+type IntegrityCheckResult struct {
+	Check       string             `json:"check"`
+	Found       int                `json:"found"`                  // problems found
+	Repair      string             `json:"repair,omitempty"`       // what a repair does (empty when the check only reports)
+	Repaired    int64              `json:"repaired"`               // documents changed (0 on a dry run)
+	Issues      []IntegrityIssue   `json:"issues"`                 // first problems found
+}
+
+// integrity check run request
+type IntegrityRequest struct {
+	Checks  []string   `json:"checks"`        // checks to run (all when empty)
+	DryRun  *bool      `json:"dry_run"`       // only report, repair when false (default true)
+}
+
+// outcome of an integrity run
+type IntegrityReport struct {
+	DryRun      bool                     `json:"dry_run"`
+	StartedAt   time.Time                `json:"started_at"`
+	FinishedAt  time.Time                `json:"finished_at"`
+	Found       int                      `json:"found"`
+	Repaired    int64                    `json:"repaired"`
+	Checks      []IntegrityCheckResult   `json:"checks"`
+}
+
+// integrity repository interface (scans collections for broken references and drifted fields)
+type IntegrityRepository interface {
+	FindIssues(ctx context.Context, check string, now time.Time) ([]IntegrityIssue, error)                   // every problem of one check
+	RepairIssues(ctx context.Context, check string, issues []IntegrityIssue, now time.Time) (int64, error)   // fix found problems, returns documents changed
+}
+
+// custom integrity errors
+var ErrUnknownIntegrityCheck = errors.New("unknown integrity check")       // custom check name error
@@ -0,0 +1,37 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// label item (tasks refer to labels by name in their tags)
+type Label struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                              // mongodb's unique identifier for labels
+	Name         string                `bson:"name" json:"name" binding:"max=50"`                                     // unique label name
+	Color        string                `bson:"color,omitempty" json:"color,omitempty" binding:"omitempty,hexcolor"`    // display color (e.g. #ff0000)
+	Description  string                `bson:"description,omitempty" json:"description,omitempty" binding:"max=200"`  // what the label is for
+	Budget       *Budget               `bson:"budget,omitempty" json:"budget,omitempty"`                              // budget of the project or client the label stands for
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`                                          // creation time
+}
+
+// label repository interface
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, label *Label) error                                 // store new label
+	GetLabels(ctx context.Context) ([]Label, error)                                      // get all labels ordered by name
+	GetLabelByID(ctx context.Context, labelID string) (*Label, error)                    // get label or return error if not found
+	GetLabelsByName(ctx context.Context, names []string) ([]Label, error)                // get labels with the given names
+	UpdateLabel(ctx context.Context, labelID string, label *Label) (*Label, error)       // update label or return error if not found
+	DeleteLabel(ctx context.Context, labelID string) error                               // delete label or return error if not found
+	EnsureIndexes(ctx context.Context) error                                             // create unique name index
+}
+
+// custom label errors
+var (
+	ErrLabelNotFound   = errors.New("label not found")              // custom label not found error
+	ErrInvalidLabelID  = errors.New("invalid label ID")             // custom invalid label id error
+	ErrLabelExists     = errors.New("label already exists")         // custom duplicate label name error
+)
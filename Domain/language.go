@@ -0,0 +1,30 @@
+package domain
+
+// imports
+import (
+	"errors";
+)
+
+// languages tasks can be tagged with (iso 639-1 codes, each has a mongodb text search analyzer)
+var TaskLanguages = []string{"en", "es", "fr", "de", "it", "pt", "nl"}
+
+// language used for tasks without one (text index default and notification fallback)
+const DefaultTaskLanguage = "en"
+
+// check if a code is a task language
+func IsTaskLanguage(code string) bool {
+	for _, language := range TaskLanguages {
+		if language == code {
+			return true
+		}
+	}
+	return false
+}
+
+// language detector interface
+type LanguageDetector interface {
+	Detect(text string) string        // task language of the text, empty when it is too short or unclear
+}
+
+// custom language errors
+var ErrInvalidTaskLanguage = errors.New("language must be one of en, es, fr, de, it, pt, nl")
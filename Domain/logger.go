@@ -0,0 +1,27 @@
+package domain
+
+// imports
+import (
+	"context";
+)
+
+// structured logger interface (args are key/value pairs, request fields come from ctx)
+type Logger interface {
+	Debug(ctx context.Context, msg string, args ...interface{})       // diagnostic detail
+	Info(ctx context.Context, msg string, args ...interface{})        // normal operation
+	Warn(ctx context.Context, msg string, args ...interface{})        // something went wrong but was handled
+	Error(ctx context.Context, msg string, args ...interface{})       // operation failed
+}
+
+type requestIDKey struct{}
+
+// store the request id on a request context so every log line can carry it
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// read the request id stored on a request context
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
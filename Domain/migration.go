@@ -0,0 +1,32 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// one-time change of stored data, applied once per database in version order
+type Migration struct {
+	Version  int                                            // order of the migration, never reused
+	Name     string                                         // short description shown in logs
+	Run      func(ctx context.Context) (int64, error)       // apply the change (safe to repeat), returns documents changed
+}
+
+// record of a migration applied to the database
+type AppliedMigration struct {
+	Version    int         `bson:"_id" json:"version"`
+	Name       string      `bson:"name" json:"name"`
+	Documents  int64       `bson:"documents" json:"documents"`       // documents changed
+	AppliedAt  time.Time   `bson:"applied_at" json:"applied_at"`
+}
+
+// migration repository interface (versions already applied to the database)
+type MigrationRepository interface {
+	GetApplied(ctx context.Context) ([]AppliedMigration, error)              // applied migrations ordered by version
+	RecordApplied(ctx context.Context, migration AppliedMigration) error     // remember an applied migration (recording it twice is not an error)
+}
+
+// custom migration errors
+var ErrMigrationOrder = errors.New("migration versions must be unique and ascending")       // custom migration list error
@@ -0,0 +1,85 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// access scopes that third-party tokens can be granted
+const (
+	ScopeReadTasks  = "read:tasks"       // read tasks
+	ScopeWriteTasks = "write:tasks"      // create, update and delete tasks
+)
+
+// all scopes a client can request
+var ValidScopes = map[string]bool{
+	ScopeReadTasks:  true,
+	ScopeWriteTasks: true,
+}
+
+// oauth client item (third-party application)
+type OAuthClient struct {
+	ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                 // mongodb's unique identifier for clients
+	ClientID      string                `bson:"client_id" json:"client_id"`              // public client identifier
+	ClientSecret  string                `bson:"client_secret" json:"-"`                  // client secret (hashed before storage)
+	Name          string                `bson:"name" json:"name"`                        // application name shown on consent screen
+	RedirectURIs  []string              `bson:"redirect_uris" json:"redirect_uris"`      // allowed redirect uris
+	Scopes        []string              `bson:"scopes" json:"scopes"`                    // maximum scopes the client may request
+	OwnerID       primitive.ObjectID    `bson:"owner_id" json:"owner_id"`                // user who registered the client
+	CreatedAt     time.Time             `bson:"created_at" json:"created_at"`            // registration time
+}
+
+// oauth authorization code item (short lived, single use)
+type OAuthAuthorizationCode struct {
+	CodeHash      string                `bson:"_id" json:"-"`                            // sha256 of the code (the code itself is only sent to the client)
+	ClientID      string                `bson:"client_id" json:"client_id"`              // client the code was issued to
+	UserID        primitive.ObjectID    `bson:"user_id" json:"user_id"`                  // user who gave consent
+	RedirectURI   string                `bson:"redirect_uri" json:"redirect_uri"`        // redirect uri used in the authorize request
+	Scopes        []string              `bson:"scopes" json:"scopes"`                    // scopes the user consented to
+	CodeChallenge string                `bson:"code_challenge,omitempty" json:"-"`       // pkce S256 challenge the token request's verifier must match (empty without pkce)
+	ExpiresAt     time.Time             `bson:"expires_at" json:"expires_at"`            // code expiry
+}
+
+// oauth authorize request (what the third-party app asks for)
+type OAuthAuthorizeRequest struct {
+	ResponseType         string      `form:"response_type" json:"response_type" binding:"required"`     // must be "code"
+	ClientID             string      `form:"client_id" json:"client_id" binding:"required"`             // requesting client
+	RedirectURI          string      `form:"redirect_uri" json:"redirect_uri" binding:"required"`       // where to send the code
+	Scope                string      `form:"scope" json:"scope" binding:"required"`                     // space separated scopes
+	State                string      `form:"state" json:"state"`                                        // opaque value echoed back
+	CodeChallenge        string      `form:"code_challenge" json:"code_challenge"`                      // pkce: base64url sha256 of the client's code verifier
+	CodeChallengeMethod  string      `form:"code_challenge_method" json:"code_challenge_method"`        // pkce: must be "S256"
+}
+
+// oauth token request (authorization code exchange)
+type OAuthTokenRequest struct {
+	GrantType     string      `form:"grant_type" json:"grant_type" binding:"required"`           // must be "authorization_code"
+	Code          string      `form:"code" json:"code" binding:"required"`                       // authorization code
+	RedirectURI   string      `form:"redirect_uri" json:"redirect_uri" binding:"required"`       // must match the authorize request
+	ClientID      string      `form:"client_id" json:"client_id" binding:"required"`             // client identifier
+	ClientSecret  string      `form:"client_secret" json:"client_secret" binding:"required"`     // client secret
+	CodeVerifier  string      `form:"code_verifier" json:"code_verifier"`                        // pkce: required when the authorize request had a code challenge
+}
+
+// oauth repository interface
+type OAuthRepository interface {
+	CreateClient(ctx context.Context, client *OAuthClient) error                                     // register new client
+	GetClientByClientID(ctx context.Context, clientID string) (*OAuthClient, error)                  // get client or return error if not found
+	SaveAuthorizationCode(ctx context.Context, code *OAuthAuthorizationCode) error                   // store issued authorization code
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string) (*OAuthAuthorizationCode, error)  // fetch and delete code by its hash in one step (single use)
+}
+
+// custom oauth errors
+var (
+	ErrOAuthClientNotFound  = errors.New("oauth client not found")                  // custom unknown client error
+	ErrInvalidRedirectURI   = errors.New("redirect uri not registered for client")  // custom redirect mismatch error
+	ErrInvalidScope         = errors.New("invalid scope")                           // custom invalid scope error
+	ErrInvalidGrant         = errors.New("invalid or expired authorization code")   // custom invalid grant error
+	ErrInvalidClient        = errors.New("invalid client credentials")              // custom client authentication error
+	ErrUnsupportedGrant     = errors.New("unsupported grant or response type")      // custom unsupported flow error
+	ErrInvalidCodeChallenge = errors.New("code_challenge must be a S256 challenge") // custom pkce error
+	ErrInsufficientScope    = errors.New("insufficient scope")                      // custom missing scope error
+)
@@ -0,0 +1,87 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// organization item (tenant), its id is the tenant id stored on its users, tasks and projects
+// users, tasks and projects without a tenant id belong to the default workspace
+type Organization struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty" json:"id"`               // mongodb's unique identifier for organizations
+	Name        string                `bson:"name" json:"name"`                      // organization name
+	Owners      []string              `bson:"owners" json:"owners"`                  // user ids of members who may invite others
+	CreatedBy   string                `bson:"created_by" json:"created_by"`          // id of the admin who created it
+	CreatedAt   time.Time             `bson:"created_at" json:"created_at"`          // creation time
+}
+
+// check if a user owns the organization
+func (org *Organization) IsOwner(userID string) bool {
+	for _, owner := range org.Owners {
+		if owner == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// organization create payload
+type CreateOrganizationRequest struct {
+	Name     string   `json:"name" binding:"required,max=100"`       // organization name (required field)
+	OwnerID  string   `json:"owner_id" binding:"required"`           // user moved into the organization as its first owner (required field)
+}
+
+// organization invite item (single use, removed by a ttl index once expired)
+type OrganizationInvite struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                 // mongodb's unique identifier for invites
+	OrgID        string                `bson:"org_id" json:"org_id"`                    // organization the invite joins
+	TokenHash    string                `bson:"token_hash" json:"-"`                     // sha256 of the token (the token itself is only returned once)
+	Email        string                `bson:"email,omitempty" json:"email,omitempty"`  // address the invited account must have (any when empty)
+	CreatedBy    string                `bson:"created_by" json:"created_by"`            // id of the owner who sent the invite
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`            // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`            // creation time
+}
+
+// organization invite payload
+type CreateOrganizationInviteRequest struct {
+	Email    string   `json:"email" binding:"omitempty,email,max=254"`       // restrict the invite to this address
+}
+
+// organization invite acceptance payload
+type AcceptOrganizationInviteRequest struct {
+	Token    string   `json:"token" binding:"required"`       // invite token (required field)
+}
+
+// organization repository interface
+type OrganizationRepository interface {
+	CreateOrganization(ctx context.Context, org *Organization) error                                      // store new organization
+	GetOrganizationByID(ctx context.Context, orgID string) (*Organization, error)                         // get organization or return error if not found
+	CreateInvite(ctx context.Context, invite *OrganizationInvite) error                                   // store new invite
+	ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*OrganizationInvite, error)      // atomically remove an unexpired invite and return it, or return error if none
+	EnsureIndexes(ctx context.Context) error                                                              // create ttl and lookup indexes
+}
+
+// tenant of the authenticated actor (false for background jobs, which see every tenant)
+func TenantFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ActorFromContext(ctx)
+	return actor.TenantID, ok
+}
+
+// check if a tenant id may be read by the request (background jobs read every tenant)
+func TenantVisible(ctx context.Context, tenantID string) bool {
+	tenant, scoped := TenantFromContext(ctx)
+	return !scoped || tenant == tenantID
+}
+
+// custom organization errors
+var (
+	ErrOrganizationNotFound    = errors.New("organization not found")                                   // custom organization not found error
+	ErrInvalidOrganizationID   = errors.New("invalid organization ID")                                  // custom invalid organization id error
+	ErrNotOrganizationOwner    = errors.New("only owners of the organization can do this")              // custom organization role error
+	ErrInvalidOrgInvite        = errors.New("invalid or expired organization invite")                   // custom invite error
+	ErrAlreadyInOrganization   = errors.New("user already belongs to an organization")                  // custom membership error
+	ErrAdminCannotJoin         = errors.New("admins stay in the default workspace")                     // custom admin membership error
+)
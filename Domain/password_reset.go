@@ -0,0 +1,47 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// password reset token item (single use, removed by a ttl index once expired)
+type PasswordResetToken struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`          // mongodb's unique identifier for reset tokens
+	UserID       primitive.ObjectID    `bson:"user_id" json:"user_id"`           // user whose password can be reset
+	TokenHash    string                `bson:"token_hash" json:"-"`              // sha256 of the token (the token itself is only emailed)
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`     // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`     // creation time
+}
+
+// forgot password payload
+type ForgotPasswordRequest struct {
+	Email        string      `json:"email" binding:"required,email"`        // email address of the account (required field)
+}
+
+// reset password payload
+type ResetPasswordRequest struct {
+	Token        string      `json:"token" binding:"required"`                                 // token from the reset email (required field)
+	Password     string      `json:"password" binding:"required,max=72,strongpassword"`       // new password (required field, bcrypt limit 72 bytes)
+}
+
+// password reset token repository interface
+type PasswordResetRepository interface {
+	CreateToken(ctx context.Context, token *PasswordResetToken) error                         // store new reset token
+	ConsumeToken(ctx context.Context, tokenHash string) (*PasswordResetToken, error)           // atomically remove a token by its hash and return it, or return error if not found
+	DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error                     // invalidate every reset token of a user
+	EnsureIndexes(ctx context.Context) error                                                   // create ttl and lookup indexes
+}
+
+// email service interface (smtp, log, ...)
+type EmailService interface {
+	SendEmail(ctx context.Context, to string, subject string, body string) error       // deliver plain text email
+}
+
+// custom password reset errors
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")       // custom reset token error
+)
@@ -0,0 +1,49 @@
+package domain
+
+// imports
+import (
+	"context";
+)
+
+// permission item (action a role is allowed to perform)
+type Permission string
+
+// available permissions
+const (
+	PermissionTaskRead    Permission = "task:read"       // view tasks
+	PermissionTaskWrite   Permission = "task:write"      // create, update and delete tasks
+	PermissionUserManage  Permission = "user:manage"     // manage other users (promote, ...)
+	PermissionAuditRead   Permission = "audit:read"      // view the audit log
+)
+
+// user roles
+const (
+	RoleUser   = "user"       // default role
+	RoleAdmin  = "admin"      // administrator
+)
+
+// permissions granted to each role
+var RolePermissions = map[string][]Permission{
+	RoleUser:  {PermissionTaskRead},
+	RoleAdmin: {PermissionTaskRead, PermissionTaskWrite, PermissionUserManage, PermissionAuditRead},
+}
+
+// current role of a user (requests are authorized with it, not with the role a token was issued for)
+type RoleLookup interface {
+	CurrentRole(ctx context.Context, userID string) (string, error)       // role of the user, ErrUserNotFound once they are gone or ErrAccountDeactivated while deactivated
+}
+
+// get permissions of a role (unknown roles get none)
+func PermissionsForRole(role string) []Permission {
+	return RolePermissions[role]
+}
+
+// check if permission list contains permission
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
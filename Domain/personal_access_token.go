@@ -0,0 +1,56 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// prefix that marks a bearer token as a personal access token instead of a jwt
+const PersonalAccessTokenPrefix = "tmpat_"
+
+// personal access token item
+type PersonalAccessToken struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                       // mongodb's unique identifier for tokens
+	UserID       primitive.ObjectID    `bson:"user_id" json:"user_id"`                        // owner of the token
+	Name         string                `bson:"name" json:"name"`                              // label chosen by the user
+	TokenHash    string                `bson:"token_hash" json:"-"`                           // sha256 of the token (never returned)
+	Hint         string                `bson:"hint" json:"hint"`                              // first characters of the token to recognize it
+	Scopes       []string              `bson:"scopes" json:"scopes"`                          // granted scopes
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`                  // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`                  // creation time
+	LastUsedAt   *time.Time            `bson:"last_used_at,omitempty" json:"last_used_at"`    // last successful authentication
+	Revoked      bool                  `bson:"revoked" json:"revoked"`                        // revoked tokens can't authenticate
+}
+
+// personal access token creation request
+type CreatePersonalAccessTokenRequest struct {
+	Name           string      `json:"name" binding:"required"`        // token label
+	Scopes         []string    `json:"scopes" binding:"required"`      // requested scopes
+	ExpiresInDays  int         `json:"expires_in_days"`                // lifetime in days (defaults to 30)
+}
+
+// personal access token repository interface
+type PersonalAccessTokenRepository interface {
+	CreateToken(ctx context.Context, token *PersonalAccessToken) error                                         // store new token
+	GetTokensByUser(ctx context.Context, userID primitive.ObjectID) ([]PersonalAccessToken, error)             // get all tokens of a user
+	GetTokenByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)                        // get token by its hash or return error if not found
+	RevokeToken(ctx context.Context, tokenID primitive.ObjectID, userID primitive.ObjectID) error              // revoke a user's token or return error if not found
+	RevokeUserTokens(ctx context.Context, userID primitive.ObjectID) (int64, error)                           // revoke every active token of a user, returns number revoked
+	TouchToken(ctx context.Context, tokenID primitive.ObjectID, usedAt time.Time) error                        // record last usage time
+}
+
+// authenticates personal access tokens for the auth middleware
+type PersonalAccessTokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*PersonalAccessToken, *User, error)       // validate token and return it with its owner
+}
+
+// custom personal access token errors
+var (
+	ErrTokenNotFound   = errors.New("token not found")                 // custom token not found error
+	ErrInvalidTokenID  = errors.New("invalid token ID")                // custom invalid token id error
+	ErrTokenExpired    = errors.New("token has expired")               // custom expired token error
+	ErrTokenRevoked    = errors.New("token has been revoked")          // custom revoked token error
+)
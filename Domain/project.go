@@ -0,0 +1,107 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// project roles, each includes the ones below it
+const (
+	ProjectRoleOwner   = "owner"       // manage the project and its members
+	ProjectRoleEditor  = "editor"      // create, change and delete the project's tasks
+	ProjectRoleViewer  = "viewer"      // see the project's tasks
+)
+
+// rank of each project role
+var ProjectRoles = map[string]int{
+	ProjectRoleViewer:  1,
+	ProjectRoleEditor:  2,
+	ProjectRoleOwner:   3,
+}
+
+// project item (board grouping tasks, only its members see them)
+type Project struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                                   // mongodb's unique identifier for projects
+	Name         string                `bson:"name" json:"name" binding:"max=100"`                                         // project name
+	Description  string                `bson:"description,omitempty" json:"description,omitempty" binding:"max=2000"`      // what the project is about
+	Members      []ProjectMember       `bson:"members" json:"members" binding:"-"`                                         // who can see the project (changed through the member endpoints)
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`                                               // creation time
+	TenantID     string                `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`                             // organization of the project (set from the creator)
+}
+
+// member of a project
+type ProjectMember struct {
+	UserID  string   `bson:"user_id" json:"user_id"`                                                // member's user id
+	Role    string   `bson:"role" json:"role" binding:"required,oneof=owner editor viewer"`         // project role
+}
+
+// project created for the first admin by setup
+const SampleProjectName = "Getting started"
+
+// most open tasks a project can be cloned with (they are copied in one unit of work)
+const MaxClonedTasks = 1000
+
+// project clone request
+type ProjectCloneRequest struct {
+	Name          string   `json:"name" binding:"max=100"`                           // name of the copy ("<name> (copy)" when empty)
+	CopyMembers   bool     `json:"copy_members"`                                     // members of the source keep their roles in the copy
+	IncludeTasks  bool     `json:"include_tasks"`                                    // copy the open tasks (pending and in progress)
+	ShiftDays     int      `json:"shift_days" binding:"min=-3650,max=3650"`          // move due dates of copied tasks by this many days
+}
+
+// result of a project clone
+type ProjectClone struct {
+	Project      *Project   `json:"project"`          // the new project
+	TasksCopied  int        `json:"tasks_copied"`     // open tasks copied into it
+}
+
+// role of a user in the project (empty when not a member)
+func (project *Project) RoleOf(userID string) string {
+	for _, member := range project.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// check if a project role includes another
+func ProjectRoleAtLeast(role string, need string) bool {
+	return role != "" && ProjectRoles[role] >= ProjectRoles[need]
+}
+
+// check if the actor sees and changes every project without being a member
+func (actor Actor) ManagesAllProjects() bool {
+	return HasPermission(PermissionsForRole(actor.Role), PermissionUserManage)
+}
+
+// tasks a caller may see: those without a project and those of the listed projects
+type ProjectVisibility struct {
+	Projects  []primitive.ObjectID
+}
+
+// project repository interface
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *Project) error                                              // store new project
+	GetProjects(ctx context.Context, userID string) ([]Project, error)                                      // projects of a member ordered by name (all projects when userID is empty)
+	GetProjectByID(ctx context.Context, projectID string) (*Project, error)                                 // get project or return error if not found
+	UpdateProject(ctx context.Context, projectID string, project *Project) (*Project, error)                // update name and description or return error if not found
+	DeleteProject(ctx context.Context, projectID string) error                                              // delete project or return error if not found
+	SetMember(ctx context.Context, projectID string, member ProjectMember) (*Project, error)                // add a member or change their role
+	RemoveMember(ctx context.Context, projectID string, userID string) (*Project, error)                    // remove a member
+	EnsureIndexes(ctx context.Context) error                                                                // index member lookups
+}
+
+// custom project errors
+var (
+	ErrProjectNotFound      = errors.New("project not found")                                   // custom project not found error
+	ErrInvalidProjectID     = errors.New("invalid project ID")                                  // custom invalid project id error
+	ErrProjectAccessDenied  = errors.New("project role does not allow this")                    // custom project role error
+	ErrProjectHasTasks      = errors.New("project still has tasks, move or delete them first")       // custom delete blocked error
+	ErrLastProjectOwner     = errors.New("project must keep at least one owner")                // custom last owner error
+	ErrProjectMemberNotFound = errors.New("user is not a member of the project")                // custom missing member error
+	ErrTooManyTasksToClone  = errors.New("project has too many open tasks to clone")           // custom clone size error
+)
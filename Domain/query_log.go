@@ -0,0 +1,32 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"time";
+)
+
+// state of the temporary database query log
+type QueryLogStatus struct {
+	Enabled     bool         `json:"enabled"`                  // queries are being logged
+	Until       *time.Time   `json:"until,omitempty"`          // when logging turns itself off
+	Logged      uint64       `json:"logged"`                   // queries logged since the window opened
+	MaxMinutes  int          `json:"max_minutes"`              // longest window that can be opened
+}
+
+// query log window request
+type QueryLogRequest struct {
+	Minutes  int   `json:"minutes" binding:"required,min=1"`       // how long queries are logged (at most the configured maximum)
+}
+
+// switch for logging every database query with its sanitized filter and duration
+type QueryLogSwitch interface {
+	EnableQueryLog(window time.Duration) (QueryLogStatus, error)       // log queries for a window, replacing an open one
+	DisableQueryLog() QueryLogStatus                                  // stop logging now
+	QueryLogStatus() QueryLogStatus                                   // current state
+}
+
+// custom query log errors
+var (
+	ErrQueryLogWindow = errors.New("query log window is longer than allowed")       // custom window too long error
+)
@@ -0,0 +1,217 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"fmt";
+	"strconv";
+	"strings";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// recurrence frequencies
+const (
+	RecurrenceDaily    = "daily"
+	RecurrenceWeekly   = "weekly"
+	RecurrenceMonthly  = "monthly"
+	RecurrenceCron     = "cron"       // five field cron expression (minute hour day-of-month month day-of-week, UTC)
+)
+
+// recurrence rule of a task (the newest occurrence of a series carries the active rule)
+type Recurrence struct {
+	Frequency   string                `bson:"frequency" json:"frequency" binding:"required,oneof=daily weekly monthly cron"`     // how the next due date is computed
+	Interval    int                   `bson:"interval,omitempty" json:"interval,omitempty" binding:"min=0,max=365"`             // every n days, weeks or months (defaults to 1)
+	Cron        string                `bson:"cron,omitempty" json:"cron,omitempty"`                                              // cron expression when frequency is cron
+	EndsAt      *time.Time            `bson:"ends_at,omitempty" json:"ends_at,omitempty"`                                        // no occurrences due after this time
+	Paused      bool                  `bson:"paused" json:"paused"`                                                              // paused series create no occurrences
+	SeriesID    primitive.ObjectID    `bson:"series_id,omitempty" json:"series_id,omitempty"`                                    // first task of the series (maintained by the server)
+	AdvancedAt  *time.Time            `bson:"advanced_at,omitempty" json:"advanced_at,omitempty"`                                // when the series moved past this occurrence (maintained by the server)
+}
+
+// check rule fields and default the interval
+func (rule *Recurrence) Validate() error {
+
+	switch rule.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		if rule.Interval < 0 {
+			return ErrInvalidRecurrence
+		}
+		if rule.Interval == 0 {
+			rule.Interval = 1
+		}
+		rule.Cron = ""
+	case RecurrenceCron:
+		if _, err := parseCron(rule.Cron); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+		rule.Interval = 0
+	default:
+		return ErrInvalidRecurrence
+	}
+
+	return nil
+}
+
+// first due date of the series after both the current due date and a time
+// ok is false when the series ends before then
+func (rule *Recurrence) NextOccurrence(due time.Time, after time.Time) (next time.Time, ok bool) {
+
+	if due.After(after) {
+		after = due
+	}
+
+	if rule.Frequency == RecurrenceCron {
+		schedule, err := parseCron(rule.Cron)
+		if err != nil {
+			return time.Time{}, false
+		}
+		next, ok = schedule.next(after.UTC())
+	} else {
+		// steps are counted from the current due date so month ends don't drift
+		next = due
+		for step := 1; !next.After(after); step++ {
+			switch rule.Frequency {
+			case RecurrenceDaily:
+				next = due.AddDate(0, 0, step*rule.Interval)
+			case RecurrenceWeekly:
+				next = due.AddDate(0, 0, 7*step*rule.Interval)
+			case RecurrenceMonthly:
+				next = addMonths(due, step*rule.Interval)
+			default:
+				return time.Time{}, false
+			}
+		}
+		ok = true
+	}
+
+	if ok && rule.EndsAt != nil && next.After(*rule.EndsAt) {
+		return time.Time{}, false
+	}
+	return next, ok
+}
+
+// same day months later, clamped to the last day of shorter months (jan 31 -> feb 28)
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// parsed cron expression (allowed values of each field)
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays  map[int]bool
+	anyDay, anyWeekday                      bool
+}
+
+// parse "minute hour day-of-month month day-of-week" with *, lists, ranges and steps
+func parseCron(expr string) (*cronSchedule, error) {
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.New("cron expression needs 5 fields")
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	sets := make([]map[int]bool, 5)
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron field %d: %v", i+1, err)
+		}
+		sets[i] = set
+	}
+
+	return &cronSchedule{
+		minutes: sets[0], hours: sets[1], days: sets[2], months: sets[3], weekdays: sets[4],
+		anyDay: fields[2] == "*", anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// values of one cron field
+func parseCronField(field string, min int, max int) (map[int]bool, error) {
+
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+
+		step := 1
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[slash+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			part = part[:slash]
+		}
+
+		low, high := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid range %q", part)
+				}
+			} else if step > 1 {
+				high = max        // "5/15" means from 5 to the end
+			}
+		}
+		if max == 6 && high == 7 {
+			high = 6       // sunday may be written as 7
+			set[0] = true
+		}
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			set[value] = true
+		}
+	}
+
+	return set, nil
+}
+
+// first matching minute after a time (searches up to five years ahead)
+func (schedule *cronSchedule) next(after time.Time) (time.Time, bool) {
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !schedule.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !schedule.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !schedule.hours[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !schedule.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// day matches like cron: either day field when both are restricted, otherwise both
+func (schedule *cronSchedule) matchesDay(t time.Time) bool {
+	day, weekday := schedule.days[t.Day()], schedule.weekdays[int(t.Weekday())]
+	if !schedule.anyDay && !schedule.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// custom recurrence errors
+var (
+	ErrInvalidRecurrence   = errors.New("invalid recurrence rule")              // custom invalid rule error
+	ErrRecurrenceNotFound  = errors.New("task has no active recurrence")        // custom missing rule error
+)
@@ -0,0 +1,49 @@
+package domain
+
+// imports
+import (
+	"context";
+	"slices";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// per-task reminder settings
+type ReminderSettings struct {
+	Enabled        bool          `bson:"enabled" json:"enabled"`                                                 // send a reminder for this task
+	MinutesBefore  int           `bson:"minutes_before" json:"minutes_before" binding:"min=0,max=10080"`        // how long before the due date (0 uses the default window)
+	SentAt         *time.Time    `bson:"sent_at,omitempty" json:"sent_at,omitempty"`                             // when the reminder went out
+}
+
+// notification item
+type Notification struct {
+	Type      string                `json:"type"`                  // notification kind (e.g. task.due_soon)
+	TaskID    primitive.ObjectID    `json:"task_id"`               // task the notification is about
+	Subject   string                `json:"subject"`               // short summary
+	Message   string                `json:"message"`               // full text
+	Recipient string                `json:"recipient,omitempty"`   // email address of a user (the notifier's own recipient when empty)
+}
+
+// notification types
+const (
+	NotificationTaskDueSoon = "task.due_soon"
+)
+
+// notification types users can receive (and mute)
+var NotificationTypes = []string{NotificationTaskDueSoon}
+
+// what a user is notified about by email (kept on the user document)
+type NotificationPreferences struct {
+	Email  bool       `bson:"email" json:"email"`                          // send notifications to the account's email address
+	Muted  []string   `bson:"muted,omitempty" json:"muted,omitempty"`      // notification types not wanted
+}
+
+// check if a notification type goes out to the user (nothing is sent without preferences)
+func (preferences *NotificationPreferences) Wants(notificationType string) bool {
+	return preferences != nil && preferences.Email && !slices.Contains(preferences.Muted, notificationType)
+}
+
+// notifier interface (log, email, ...)
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error       // deliver notification or return error
+}
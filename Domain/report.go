@@ -0,0 +1,82 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// who a report covers
+const (
+	ReportScopeOrganization  = "organization"       // every task and user of the caller's workspace (admins)
+	ReportScopePersonal      = "personal"           // tasks the caller sees, their own completions and time
+)
+
+// longest range of a productivity report
+const MaxReportRangeDays = 366
+
+// filter of the documents a report is computed from
+type ReportScope struct {
+	TenantID    *string              // only this organization (nil for every tenant)
+	Visibility  *ProjectVisibility   // summary: only tasks without a project or of these projects (nil for all)
+	UserID      string               // productivity: only changes and worklogs of this user (empty for everyone)
+}
+
+// counts of tasks as they are now
+type TaskSummaryReport struct {
+	Scope              string             `json:"scope"`                  // organization or personal
+	Total              int64              `json:"total"`
+	ByStatus           map[string]int64   `json:"by_status"`              // every status, 0 when no task has it
+	ByPriority         map[string]int64   `json:"by_priority"`            // every priority, 0 when no task has it
+	Overdue            int64              `json:"overdue"`
+	CompletedThisWeek  int64              `json:"completed_this_week"`    // completed tasks that entered the status since week_start
+	WeekStart          time.Time          `json:"week_start"`             // monday 00:00 UTC
+	GeneratedAt        time.Time          `json:"generated_at"`
+}
+
+// work done on one day (UTC)
+type ProductivityDay struct {
+	Date       string   `json:"date"`           // e.g. 2025-07-22
+	Created    int64    `json:"created"`        // tasks created
+	Completed  int64    `json:"completed"`      // tasks moved to completed (again after a reopen)
+	Seconds    int64    `json:"seconds"`        // time logged in worklogs started that day
+}
+
+// work done by one user in the range
+type UserProductivity struct {
+	UserID     string   `json:"user_id"`
+	Username   string   `json:"username"`
+	Created    int64    `json:"created"`
+	Completed  int64    `json:"completed"`
+	Seconds    int64    `json:"seconds"`
+}
+
+// work done between two times
+type ProductivityReport struct {
+	Scope      string               `json:"scope"`                   // organization or personal
+	UserID     string               `json:"user_id,omitempty"`       // user of a personal report
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`                      // exclusive
+	Created    int64                `json:"created"`
+	Completed  int64                `json:"completed"`
+	Seconds    int64                `json:"seconds"`
+	Days       []ProductivityDay    `json:"days"`                    // every day of the range, oldest first
+	Users      []UserProductivity   `json:"users,omitempty"`         // most completions first (organization reports only)
+}
+
+// productivity report request
+type ProductivityQuery struct {
+	From    *time.Time       // start of the range (30 days before to when nil)
+	To      *time.Time       // end of the range, exclusive (now when nil)
+	UserID  string           // personal report of another user (admins only)
+}
+
+// report repository interface (aggregations over tasks, task history and worklogs)
+type ReportRepository interface {
+	TaskSummary(ctx context.Context, scope ReportScope, weekStart time.Time) (*TaskSummaryReport, error)         // task counts by status and priority
+	Productivity(ctx context.Context, scope ReportScope, from time.Time, to time.Time) (*ProductivityReport, error)       // created, completed and logged time per day and user
+}
+
+// custom report errors
+var ErrReportForbidden = errors.New("only admins can see reports of other users")       // custom report access error
@@ -0,0 +1,67 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+const SessionTTL = 24 * time.Hour        // sessions last as long as the login token
+
+// why a session was revoked
+const (
+	SessionRevokedLimit = "session_limit"       // a newer login went over the concurrent session limit
+	SessionRevokedAccount = "account_changed"   // an admin deactivated, demoted or deleted the account
+	SessionRevokedPassword = "password_changed" // the password was changed or reset
+)
+
+// session item (one per login, referenced by the token's sid claim)
+type Session struct {
+	ID             primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                   // mongodb's unique identifier for sessions
+	UserID         string                `bson:"user_id" json:"user_id"`                                    // user who logged in
+	ClientIP       string                `bson:"client_ip,omitempty" json:"client_ip,omitempty"`            // address the login came from
+	CreatedAt      time.Time             `bson:"created_at" json:"created_at"`                              // login time
+	ExpiresAt      time.Time             `bson:"expires_at" json:"expires_at"`                              // when the token of the session expires
+	RevokedAt      *time.Time            `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`          // when the session was ended early
+	RevokedReason  string                `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`  // why the session was ended early
+}
+
+// check if session can still be used at the given time
+func (session *Session) IsActive(now time.Time) bool {
+	return session.RevokedAt == nil && now.Before(session.ExpiresAt)
+}
+
+// workspace security settings
+type SecuritySettings struct {
+	MaxSessions  int  `bson:"max_sessions" json:"max_sessions" binding:"min=0,max=100"`      // concurrent sessions per user, the oldest is revoked beyond it (0 for no limit)
+}
+
+// session repository interface
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *Session) error                                          // store new session
+	GetSession(ctx context.Context, sessionID string) (*Session, error)                                 // get session or return error if not found
+	GetActiveSessions(ctx context.Context, userID string, now time.Time) ([]Session, error)             // unrevoked and unexpired sessions of a user, oldest first
+	RevokeSessions(ctx context.Context, sessionIDs []primitive.ObjectID, reason string, at time.Time) error      // end sessions early
+	EnsureIndexes(ctx context.Context) error                                                            // index user lookups and drop expired sessions
+}
+
+// session starter interface (used by login and account management)
+type SessionStarter interface {
+	StartSession(ctx context.Context, user *User) (*Session, error)       // record a login and enforce the session limit
+	RevokeUserSessions(ctx context.Context, userID string, reason string) (int, error)      // end every active session of a user, returns sessions ended
+	RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) (int, error)      // end every active session of a user but one, returns sessions ended
+}
+
+// session validator interface (used by the auth middleware for tokens with a sid claim)
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, sessionID string) error       // return error unless the session is active
+}
+
+// custom session errors
+var (
+	ErrSessionNotFound   = errors.New("session not found")          // custom session not found error
+	ErrInvalidSessionID  = errors.New("invalid session ID")         // custom invalid session id error
+	ErrSessionRevoked    = errors.New("session has ended")          // custom revoked or expired session error
+)
@@ -0,0 +1,51 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// id of the single settings document
+const InstanceSettingsID = "instance"
+
+// name of the default workspace (users, tasks and projects without an organization) when setup is given none
+const DefaultWorkspaceName = "Default workspace"
+
+// smtp settings item
+type SMTPSettings struct {
+	Host       string    `bson:"host" json:"host" binding:"required,hostname|ip"`         // smtp server host (required field)
+	Port       int       `bson:"port" json:"port" binding:"required,min=1,max=65535"`     // smtp server port (required field)
+	Username   string    `bson:"username,omitempty" json:"username"`                       // smtp login (no auth when empty)
+	Password   string    `bson:"password,omitempty" json:"password"`                       // smtp password (never returned by the api)
+	From       string    `bson:"from" json:"from" binding:"required,email"`                // sender address (required field)
+}
+
+// instance settings item (configuration saved by setup, overrides environment defaults)
+type InstanceSettings struct {
+	ID             string          `bson:"_id"`                            // always InstanceSettingsID
+	WorkspaceName  string          `bson:"workspace_name,omitempty"`       // name of this installation
+	SMTP           *SMTPSettings   `bson:"smtp,omitempty"`                 // outgoing email settings (environment is used when nil)
+	Security       *SecuritySettings `bson:"security,omitempty"`           // workspace security settings (environment is used when nil)
+	SetupCompletedAt *time.Time    `bson:"setup_completed_at,omitempty"`   // when first run setup was claimed (at most once per database)
+	UpdatedAt      time.Time       `bson:"updated_at"`                     // last change
+}
+
+// settings repository interface
+type SettingsRepository interface {
+	GetSettings(ctx context.Context) (*InstanceSettings, error)            // get saved settings or return error if none
+	SaveSettings(ctx context.Context, settings *InstanceSettings) error    // store settings, replacing earlier ones
+	ClaimSetup(ctx context.Context, at time.Time) error                    // atomically mark setup as done or return ErrSetupCompleted if it already is
+	ReleaseSetup(ctx context.Context) error                                // undo a claim after setup failed
+	SaveSecuritySettings(ctx context.Context, security *SecuritySettings, at time.Time) error      // store security settings, keeping the rest
+}
+
+// builds an email service for smtp settings (used to test settings before saving them)
+type EmailServiceFactory func(settings SMTPSettings) EmailService
+
+// custom settings errors
+var (
+	ErrSettingsNotFound = errors.New("settings not found")                    // custom missing settings error
+	ErrSMTPTestFailed   = errors.New("smtp settings test email failed")       // custom smtp validation error
+)
@@ -0,0 +1,63 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// tag job kinds
+const (
+	TagJobRename = "rename"       // rename a tag on every task
+	TagJobMerge  = "merge"        // move tasks from one tag to another and drop the first
+)
+
+// tag job states
+const (
+	TagJobRunning   = "running"
+	TagJobCompleted = "completed"
+	TagJobFailed    = "failed"
+)
+
+// background bulk update of task tags with its progress
+type TagJob struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                               // mongodb's unique identifier for tag jobs
+	Kind        string                `bson:"kind" json:"kind"`                                      // rename or merge
+	From        string                `bson:"from" json:"from"`                                      // tag taken off tasks
+	To          string                `bson:"to" json:"to"`                                          // tag put on tasks
+	Status      string                `bson:"status" json:"status"`                                  // running, completed or failed
+	Total       int                   `bson:"total" json:"total"`                                    // tasks carrying the old tag when the job started
+	Processed   int                   `bson:"processed" json:"processed"`                            // tasks retagged so far
+	Error       string                `bson:"error,omitempty" json:"error,omitempty"`                // why the job failed
+	ActorID     string                `bson:"actor_id,omitempty" json:"actor_id,omitempty"`          // who started the job
+	StartedAt   time.Time             `bson:"started_at" json:"started_at"`                          // start time
+	FinishedAt  *time.Time            `bson:"finished_at,omitempty" json:"finished_at,omitempty"`    // completion time (nil while running)
+}
+
+// rename tag request
+type RenameTagRequest struct {
+	From  string  `json:"from" binding:"required"`        // current tag name
+	To    string  `json:"to" binding:"required,max=50"`   // new tag name (must not be a label yet)
+}
+
+// merge tags request
+type MergeTagsRequest struct {
+	Source  string  `json:"source" binding:"required"`   // tag merged away (its label is deleted)
+	Target  string  `json:"target" binding:"required"`   // tag kept on tasks
+}
+
+// tag job repository interface
+type TagJobRepository interface {
+	CreateJob(ctx context.Context, job *TagJob) error                    // store new job
+	UpdateJob(ctx context.Context, job *TagJob) error                    // save progress and status of a job
+	GetJob(ctx context.Context, jobID string) (*TagJob, error)           // get job or return error if not found
+}
+
+// custom tag job errors
+var (
+	ErrTagJobNotFound   = errors.New("tag job not found")          // custom tag job not found error
+	ErrInvalidTagJobID  = errors.New("invalid tag job ID")         // custom invalid tag job id error
+	ErrSameTag          = errors.New("tags must be different")     // custom same source and target error
+)
@@ -0,0 +1,41 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// completed task moved out of the tasks collection
+type ArchivedTask struct {
+	Task        `bson:",inline"`
+	ArchivedAt  time.Time   `bson:"archived_at" json:"archived_at"`
+}
+
+// page sizes of the archive listing
+const (
+	DefaultArchiveLimit  = 50
+	MaxArchiveLimit      = 200
+)
+
+// archived task listing filter
+type ArchiveQuery struct {
+	TenantID    *string              // only tasks of this organization (nil for all)
+	Visibility  *ProjectVisibility   // only tasks the caller may see (nil for all)
+	Before      time.Time            // only tasks archived before this time (zero for the newest)
+	Limit       int64
+}
+
+// task archive repository interface (moves tasks between the tasks and archived_tasks collections)
+type TaskArchiveRepository interface {
+	FindArchivable(ctx context.Context, completedBefore time.Time, limit int64) ([]Task, error)     // completed tasks without subtasks that entered the status before a time
+	Archive(ctx context.Context, tasks []Task, archivedAt time.Time) ([]Task, error)              // move tasks into the archive, returns those moved (tasks changed meanwhile stay)
+	GetArchivedTasks(ctx context.Context, query ArchiveQuery) ([]ArchivedTask, error)              // archived tasks newest first
+	GetArchivedTask(ctx context.Context, taskID string) (*ArchivedTask, error)                      // archived task or ErrArchivedTaskNotFound
+	Restore(ctx context.Context, taskID string, restoredAt time.Time) (*Task, error)               // move a task back to the tasks collection
+	EnsureIndexes(ctx context.Context) error                                                        // archive listing order
+}
+
+// custom task archive errors
+var ErrArchivedTaskNotFound = errors.New("archived task not found")       // custom missing archived task error
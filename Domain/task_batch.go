@@ -0,0 +1,15 @@
+package domain
+
+// most tasks fetched by one batch get
+const MaxTaskBatchSize = 100
+
+// ids of the tasks to fetch at once
+type TaskBatchInput struct {
+	IDs  []string  `json:"ids" binding:"required,min=1,max=100"`        // task ids, duplicates are returned once
+}
+
+// tasks fetched by id, in the requested order
+type TaskBatch struct {
+	Tasks     []Task     // tasks found
+	NotFound  []string   // requested ids that are invalid, missing or hidden from the caller
+}
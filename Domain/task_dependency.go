@@ -0,0 +1,58 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// "blocked by" link between two tasks (the blocker has to be completed first)
+type TaskDependency struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`                     // mongodb's unique identifier for dependencies
+	TaskID     string               `bson:"task_id" json:"task_id"`                      // blocked task
+	BlockerID  string               `bson:"blocker_id" json:"blocker_id"`                // task the blocked task waits for
+	TenantID   string               `bson:"tenant_id,omitempty" json:"-"`                // organization of the tasks
+	CreatedBy  string               `bson:"created_by" json:"created_by"`                // user who linked the tasks
+	CreatedAt  time.Time            `bson:"created_at" json:"created_at"`
+}
+
+// dependency payload
+type TaskDependencyInput struct {
+	BlockerID  string   `json:"blocker_id" binding:"required"`       // task that blocks the task
+}
+
+// task on the other end of a dependency
+type DependencyTask struct {
+	TaskID     string   `json:"task_id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Resolved   bool     `json:"resolved"`       // the blocker is completed
+}
+
+// dependencies of a task in both directions
+type TaskDependencies struct {
+	TaskID     string             `json:"task_id"`
+	Blocked    bool               `json:"blocked"`         // a blocker isn't completed yet
+	BlockedBy  []DependencyTask   `json:"blocked_by"`      // tasks to complete first
+	Blocks     []DependencyTask   `json:"blocks"`          // tasks waiting for this one
+}
+
+// task dependency repository interface
+type TaskDependencyRepository interface {
+	AddDependency(ctx context.Context, dependency *TaskDependency) error                     // link two tasks, ErrDependencyExists when linked already
+	RemoveDependency(ctx context.Context, taskID string, blockerID string) error             // unlink two tasks or return ErrDependencyNotFound
+	GetBlockers(ctx context.Context, taskIDs []string) ([]TaskDependency, error)             // dependencies of these blocked tasks
+	GetBlocked(ctx context.Context, blockerID string) ([]TaskDependency, error)              // dependencies of tasks waiting for a task
+	DeleteTaskDependencies(ctx context.Context, taskID string) error                         // drop the links of a deleted task in both directions
+	EnsureIndexes(ctx context.Context) error                                                 // one link per pair, lookups in both directions
+}
+
+// custom task dependency errors
+var (
+	ErrDependencyExists    = errors.New("task is already blocked by this task")                      // custom duplicate dependency error
+	ErrDependencyNotFound  = errors.New("dependency not found")                                      // custom missing dependency error
+	ErrDependencyCycle     = errors.New("dependency would create a cycle")                           // custom circular dependency error
+	ErrTaskBlocked         = errors.New("task can't be completed while blockers are open")          // custom open blocker error
+)
@@ -0,0 +1,37 @@
+package domain
+
+// imports
+import (
+	"context";
+)
+
+// task event types
+const (
+	TaskEventCreated = "task.created"
+	TaskEventUpdated = "task.updated"
+	TaskEventDeleted = "task.deleted"
+	TaskEventArchived = "task.archived"          // moved to the archive (Before holds the task)
+	TaskEventUnarchived = "task.unarchived"      // restored from the archive (After holds the task)
+	TaskEventRestored = "task.restored"          // restored from the trash (After holds the task)
+	TaskEventPurged = "task.purged"              // removed from the trash for good (TaskID only)
+)
+
+// task event item (published by task commands after a change is stored)
+type TaskEvent struct {
+	Type      string               // event type (e.g. task.created)
+	TaskID    string               // task the event is about
+	Before    *Task                // state before the change (nil on create)
+	After     *Task                // state after the change (nil on delete)
+	Details   map[string]string    // extra context (e.g. cascaded_subtasks)
+}
+
+// task event handler interface (audit log, extensions, ...)
+type TaskEventHandler interface {
+	HandleTaskEvent(ctx context.Context, event TaskEvent)       // react to a stored change, failures are handled by the handler
+}
+
+// task event bus interface (fans published events out to live subscribers)
+type TaskEventBus interface {
+	TaskEventHandler
+	Subscribe(userID string) (<-chan TaskEvent, func())        // receive events until the returned cancel function is called
+}
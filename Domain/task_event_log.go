@@ -0,0 +1,44 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// most logged events read at once, streams read again right away when a read is full
+const TaskEventLogBatch = 200
+
+// task event kept so stream clients can resume after a disconnect
+type TaskEventLogEntry struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                  // sent as the event id, later events sort after earlier ones
+	Type       string                `bson:"type" json:"type"`                         // event type (e.g. task.updated)
+	TaskID     string                `bson:"task_id" json:"task_id"`                   // task the event is about
+	Task       *Task                 `bson:"task,omitempty" json:"task,omitempty"`     // task after the change (omitted on delete and archive)
+	ProjectID  *primitive.ObjectID   `bson:"project_id,omitempty" json:"-"`            // project of the task, only its members see the event
+	TenantID   string                `bson:"tenant_id,omitempty" json:"-"`             // organization of the task
+	CreatedAt  time.Time             `bson:"created_at" json:"timestamp"`              // when the change was stored (UTC)
+	ExpiresAt  time.Time             `bson:"expires_at" json:"-"`                      // dropped from the log after this time
+}
+
+// logged events to read
+type TaskEventLogQuery struct {
+	After       primitive.ObjectID     // only events logged after this one
+	Visibility  *ProjectVisibility     // only events of tasks the caller may see (nil for all)
+	TenantID    *string                // only events of this organization (nil for all)
+	Limit       int64                  // oldest events returned first
+}
+
+// task event log repository interface
+type TaskEventLogRepository interface {
+	AppendEvent(ctx context.Context, entry *TaskEventLogEntry) error                            // store an event at the end of the log
+	GetEvents(ctx context.Context, query TaskEventLogQuery) ([]TaskEventLogEntry, error)         // find events oldest first
+	EnsureIndexes(ctx context.Context) error                                                     // create lookup and expiry indexes
+}
+
+// custom task event log errors
+var (
+	ErrInvalidEventID = errors.New("invalid event ID")        // custom invalid last event id error
+)
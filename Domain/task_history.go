@@ -0,0 +1,181 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"reflect";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// task persistence modes
+const (
+	TaskPersistenceState  = "state"       // tasks collection only (default)
+	TaskPersistenceEvents = "events"      // every change stored as an event, tasks collection is the projection
+)
+
+// stored task change (snapshot of the task after the change)
+type TaskHistoryEvent struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                          // mongodb's unique identifier for events
+	TaskID      primitive.ObjectID    `bson:"task_id" json:"task_id"`                           // task the event belongs to
+	Type        string                `bson:"type" json:"type"`                                 // task event type (task.created/task.updated/task.deleted)
+	Task        *Task                 `bson:"task,omitempty" json:"task,omitempty"`             // task state after the change (nil once deleted)
+	ActorID     string                `bson:"actor_id,omitempty" json:"actor_id,omitempty"`     // user who made the change (empty for the server)
+	Timestamp   time.Time             `bson:"timestamp" json:"timestamp"`                       // when the change was stored (UTC)
+}
+
+// task history repository interface (append only event store)
+type TaskHistoryRepository interface {
+	AppendEvents(ctx context.Context, events []TaskHistoryEvent) error                          // store events in order
+	GetEvents(ctx context.Context, taskID string) ([]TaskHistoryEvent, error)                  // get every event of a task, oldest first
+	GetEventAsOf(ctx context.Context, taskID string, at time.Time) (*TaskHistoryEvent, error)  // get last event of a task at or before a time or return error if none
+	EnsureIndexes(ctx context.Context) error                                                    // create index used by history queries
+}
+
+// change of one task field (recorded in every persistence mode)
+type TaskChange struct {
+	ID          primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                          // mongodb's unique identifier for changes
+	TaskID      primitive.ObjectID    `bson:"task_id" json:"task_id"`                           // task the change belongs to
+	Action      string                `bson:"action" json:"action"`                             // task event type (task.created/task.updated/task.deleted)
+	Field       string                `bson:"field,omitempty" json:"field,omitempty"`           // json name of the changed field (empty for deletions)
+	OldValue    interface{}           `bson:"old_value" json:"old_value"`                       // value before the change (null when created)
+	NewValue    interface{}           `bson:"new_value" json:"new_value"`                       // value after the change (null when cleared)
+	ActorID     string                `bson:"actor_id,omitempty" json:"actor_id,omitempty"`     // user who made the change (empty for the server)
+	Actor       string                `bson:"actor,omitempty" json:"actor,omitempty"`           // username of the actor
+	Timestamp   time.Time             `bson:"timestamp" json:"timestamp"`                       // when the change was made (UTC)
+}
+
+// old and new value of one changed task field
+type TaskFieldChange struct {
+	Field     string        `json:"field"`         // json name of the field
+	OldValue  interface{}   `json:"old_value"`     // value before the change (null when unset)
+	NewValue  interface{}   `json:"new_value"`     // value after the change (null when cleared)
+}
+
+// user editable fields that differ between two states of a task
+// server maintained fields (overdue flag, reminder sent time, series bookkeeping) are ignored
+func DiffTasks(before *Task, after *Task) []TaskFieldChange {
+
+	fields := []struct {
+		name      string
+		old, new  interface{}
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"due_date", before.DueDate, after.DueDate},
+		{"status", before.Status, after.Status},
+		{"priority", before.Priority, after.Priority},
+		{"estimate", before.Estimate, after.Estimate},
+		{"parent_id", before.ParentID, after.ParentID},
+		{"reminder", reminderSettings(before.Reminder), reminderSettings(after.Reminder)},
+		{"tags", before.Tags, after.Tags},
+		{"checklist", before.Checklist, after.Checklist},
+		{"cost", before.Cost, after.Cost},
+		{"recurrence", recurrenceRule(before.Recurrence), recurrenceRule(after.Recurrence)},
+	}
+
+	changes := []TaskFieldChange{}
+	for _, field := range fields {
+		if sameFieldValue(field.old, field.new) {
+			continue
+		}
+		changes = append(changes, TaskFieldChange{Field: field.name, OldValue: fieldValue(field.old), NewValue: fieldValue(field.new)})
+	}
+
+	return changes
+}
+
+// reminder settings chosen by the user (the sent time is maintained by the server)
+func reminderSettings(reminder *ReminderSettings) *ReminderSettings {
+	if reminder == nil {
+		return nil
+	}
+	settings := *reminder
+	settings.SentAt = nil
+	return &settings
+}
+
+// recurrence rule chosen by the user (series bookkeeping is maintained by the server)
+func recurrenceRule(rule *Recurrence) *Recurrence {
+	if rule == nil {
+		return nil
+	}
+	chosen := *rule
+	chosen.SeriesID = primitive.NilObjectID
+	chosen.AdvancedAt = nil
+	return &chosen
+}
+
+// equal field values (empty and missing tags or checklists are the same)
+func sameFieldValue(old interface{}, new interface{}) bool {
+	if oldTags, ok := old.([]string); ok {
+		newTags := new.([]string)
+		return len(oldTags) == 0 && len(newTags) == 0 || reflect.DeepEqual(oldTags, newTags)
+	}
+	if oldItems, ok := old.([]ChecklistItem); ok {
+		newItems := new.([]ChecklistItem)
+		return len(oldItems) == 0 && len(newItems) == 0 || reflect.DeepEqual(oldItems, newItems)
+	}
+	return reflect.DeepEqual(old, new)
+}
+
+// value of a field as stored and returned (nil pointers, zero times, empty tags and checklists become null)
+func fieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *primitive.ObjectID:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ReminderSettings:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *Recurrence:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *TaskCost:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+	case []ChecklistItem:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	return value
+}
+
+// task change repository interface (append only)
+type TaskChangeRepository interface {
+	AppendChanges(ctx context.Context, changes []TaskChange) error                   // store changes in order
+	GetChanges(ctx context.Context, taskID string) ([]TaskChange, error)            // get every change of a task, oldest first
+	GetCompletions(ctx context.Context, actorID string, limit int64) ([]TaskCompletion, error)      // get tasks a user completed, latest first
+	EnsureIndexes(ctx context.Context) error                                         // create indexes used by history queries
+}
+
+// task completed by a user with its creation and completion time
+type TaskCompletion struct {
+	TaskID       primitive.ObjectID    `json:"task_id"`         // completed task
+	Title        string                `json:"title"`           // title the task was created with
+	CreatedAt    time.Time             `json:"created_at"`      // when the task was created
+	CompletedAt  time.Time             `json:"completed_at"`    // when its status became completed
+}
+
+// custom task history errors
+var (
+	ErrTaskHistoryDisabled = errors.New("task history requires TASK_PERSISTENCE=events")       // custom history unavailable error
+)
@@ -0,0 +1,40 @@
+package domain
+
+// imports
+import (
+	"errors";
+)
+
+const MaxImportRows = 1000        // rows accepted in one import
+
+// import file formats
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// parsed row of an import file (task is nil when the row couldn't be parsed)
+type TaskImportRow struct {
+	Row    int        // line of a csv file or position in a json array (1 based)
+	Task   *Task      // task to create
+	Error  string     // why the row was rejected while parsing
+}
+
+// outcome of one import row
+type TaskImportResult struct {
+	Row     int      `json:"row"`                          // line or position in the file
+	TaskID  string   `json:"task_id,omitempty"`            // created task
+	Error   string   `json:"error,omitempty"`              // why the row was rejected
+}
+
+// per-row report of an import
+type TaskImportReport struct {
+	Created  int                  `json:"created"`      // rows created as tasks
+	Failed   int                  `json:"failed"`       // rows rejected
+	Rows     []TaskImportResult   `json:"rows"`         // outcome of every row in file order
+}
+
+// custom task import errors
+var (
+	ErrTooManyImportRows = errors.New("import file has too many rows")        // custom import limit error
+)
@@ -0,0 +1,38 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+)
+
+// advisory edit lock of a task (lets clients warn that someone else is editing it)
+type TaskLock struct {
+	TaskID      string      `bson:"_id" json:"task_id"`
+	UserID      string      `bson:"user_id" json:"user_id"`                // user editing the task
+	Username    string      `bson:"username" json:"username"`              // shown to other editors
+	AcquiredAt  time.Time   `bson:"acquired_at" json:"acquired_at"`
+	ExpiresAt   time.Time   `bson:"expires_at" json:"expires_at"`          // lock is stale after this time unless the holder sends a heartbeat
+}
+
+// check if the lock still holds at the given time
+func (lock *TaskLock) Fresh(now time.Time) bool {
+	return now.Before(lock.ExpiresAt)
+}
+
+// task lock repository interface
+type TaskLockRepository interface {
+	Acquire(ctx context.Context, lock *TaskLock) (*TaskLock, error)                                    // take a free or stale lock (or renew own), returns the holder's lock with ErrTaskLocked otherwise
+	Heartbeat(ctx context.Context, taskID string, userID string, expiresAt time.Time) (*TaskLock, error)      // extend own fresh lock or return ErrTaskLockNotHeld
+	Release(ctx context.Context, taskID string, userID string) error                                   // drop the lock of the user (any user when userID is empty)
+	GetLock(ctx context.Context, taskID string) (*TaskLock, error)                                      // fresh lock of a task or ErrTaskLockNotFound
+	EnsureIndexes(ctx context.Context) error                                                            // expire stale locks
+}
+
+// custom task lock errors
+var (
+	ErrTaskLocked        = errors.New("task is being edited by another user")            // custom lock conflict error
+	ErrTaskLockNotFound  = errors.New("task is not locked")                              // custom missing lock error
+	ErrTaskLockNotHeld   = errors.New("task lock is not held by the caller")            // custom lost lock error
+)
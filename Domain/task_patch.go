@@ -0,0 +1,157 @@
+package domain
+
+// imports
+import (
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// partial task update (nil fields are left unchanged, a pointer to the empty value clears a field)
+type UpdateTaskInput struct {
+	Title          *string              `json:"title" binding:"omitempty,max=200"`                                            // title of task (can't be cleared)
+	Description    *string              `json:"description" binding:"omitempty,max=2000"`                                     // description of task ("" clears)
+	DueDate        *time.Time           `json:"due_date"`                                                                     // due date of task (can't be cleared)
+	Status         *string              `json:"status" binding:"omitempty,oneof=pending in_progress completed"`               // status of task (can't be cleared)
+	Priority       *string              `json:"priority" binding:"omitempty,oneof=low medium high urgent"`                    // priority of task (can't be cleared)
+	Estimate       *int                 `json:"estimate" binding:"omitempty,min=0,max=1440"`                                  // estimated effort in minutes (0 clears)
+	ParentID       *string              `json:"parent_id"`                                                                    // parent task ("" makes the task top-level)
+	ProjectID      *string              `json:"project_id"`                                                                   // project of the task ("" takes it out of its project)
+	Reminder       *ReminderSettings    `json:"reminder"`                                                                     // due date reminder settings
+	ClearReminder  bool                 `json:"-"`                                                                            // remove the reminder settings
+	Cost           *TaskCost            `json:"cost"`                                                                         // estimated and actual cost
+	ClearCost      bool                 `json:"-"`                                                                            // remove the cost
+	Tags           *[]string            `json:"tags" binding:"omitempty,max=20"`                                              // names of attached labels ([] clears)
+	Language       *string              `json:"language" binding:"omitempty,oneof=en es fr de it pt nl"`                      // language of title and description (can't be cleared)
+	ExpectedUpdatedAt *time.Time        `json:"-"`                                                                            // update precondition: only change the task if it was last changed at this time
+}
+
+// convert a partial update into the stored change, checks the ids and the fields that can't be cleared
+func (input *UpdateTaskInput) ToTaskUpdate() (*TaskUpdate, error) {
+
+	invalid := &ValidationError{}
+	update := &TaskUpdate{
+		Title:        input.Title,
+		Description:  input.Description,
+		DueDate:      input.DueDate,
+		Status:       input.Status,
+		Priority:     input.Priority,
+		Estimate:     input.Estimate,
+		Reminder:     input.Reminder,
+		ClearReminder: input.ClearReminder,
+		Cost:         input.Cost,
+		ClearCost:    input.ClearCost,
+		Tags:         input.Tags,
+		Language:     input.Language,
+		ExpectedUpdatedAt: input.ExpectedUpdatedAt,
+	}
+	if input.Title != nil && *input.Title == "" {
+		invalid.Add("title", "can't be removed")
+	}
+	if input.DueDate != nil && input.DueDate.IsZero() {
+		invalid.Add("due_date", "can't be removed")
+	}
+	if input.Status != nil && *input.Status == "" {
+		invalid.Add("status", "can't be removed")
+	}
+	if input.Priority != nil && *input.Priority == "" {
+		invalid.Add("priority", "can't be removed")
+	}
+	if input.Language != nil && *input.Language == "" {
+		invalid.Add("language", "can't be removed")
+	}
+	if input.Reminder != nil && input.ClearReminder {
+		invalid.Add("reminder", "can't be set and removed at once")
+	}
+	if input.Cost != nil && input.ClearCost {
+		invalid.Add("cost", "can't be set and removed at once")
+	}
+	if input.Tags != nil && *input.Tags == nil {
+		update.Tags = &[]string{}        // clear labels
+	}
+	if input.ParentID != nil {
+		parentID := primitive.NilObjectID        // top-level task
+		if *input.ParentID != "" {
+			var err error
+			if parentID, err = primitive.ObjectIDFromHex(*input.ParentID); err != nil {
+				invalid.Add("parent_id", "must be a task id")
+			}
+		}
+		update.ParentID = &parentID
+	}
+	if input.ProjectID != nil {
+		projectID := primitive.NilObjectID        // no project
+		if *input.ProjectID != "" {
+			var err error
+			if projectID, err = primitive.ObjectIDFromHex(*input.ProjectID); err != nil {
+				invalid.Add("project_id", "must be a project id")
+			}
+		}
+		update.ProjectID = &projectID
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// stored change of a task (nil fields are left unchanged, a pointer to the empty value clears a field)
+type TaskUpdate struct {
+	Title            *string
+	Description      *string                  // "" clears
+	DueDate          *time.Time
+	Status           *string
+	StatusEnteredAt  map[string]time.Time     // statuses entered with this change (set by the usecase, other statuses keep their times)
+	Priority         *string
+	PriorityRank     int                      // rank of Priority (set by the usecase)
+	Estimate         *int                     // 0 clears
+	ParentID         *primitive.ObjectID      // primitive.NilObjectID makes the task top-level
+	ProjectID        *primitive.ObjectID      // primitive.NilObjectID takes the task out of its project
+	Reminder         *ReminderSettings        // replaces the settings, a reminder sent before is sent again
+	ClearReminder    bool                     // remove the reminder settings
+	Cost             *TaskCost                // replaces estimated and actual cost together
+	ClearCost        bool                     // remove the cost
+	Tags             *[]string                // replaces all labels ([] clears them)
+	Checklist        *[]ChecklistItem         // replaces the checklist ([] clears it)
+	Language         *string
+	ExpectedUpdatedAt *time.Time              // only change the task if it was last changed at this time
+}
+
+// stored change of a full task payload, empty values are left unchanged (PUT semantics, [] tags clear the labels)
+func NewTaskUpdate(task *Task) *TaskUpdate {
+
+	update := &TaskUpdate{Reminder: task.Reminder, Cost: task.Cost, ParentID: task.ParentID, ProjectID: task.ProjectID, ExpectedUpdatedAt: task.ExpectedUpdatedAt}
+	if task.Title != "" {
+		update.Title = &task.Title
+	}
+	if task.Description != "" {
+		update.Description = &task.Description
+	}
+	if !task.DueDate.IsZero() {
+		update.DueDate = &task.DueDate
+	}
+	if task.Status != "" {
+		update.Status = &task.Status
+	}
+	if task.Priority != "" {
+		update.Priority = &task.Priority
+	}
+	if task.Estimate != 0 {
+		update.Estimate = &task.Estimate
+	}
+	if task.Tags != nil {
+		update.Tags = &task.Tags
+	}
+	if task.Language != "" {
+		update.Language = &task.Language
+	}
+
+	return update
+}
+
+// check if the update changes nothing
+func (update *TaskUpdate) Empty() bool {
+	return update.Title == nil && update.Description == nil && update.DueDate == nil && update.Status == nil && update.Priority == nil &&
+		update.Estimate == nil && update.ParentID == nil && update.ProjectID == nil && update.Reminder == nil && !update.ClearReminder &&
+		update.Cost == nil && !update.ClearCost && update.Tags == nil && update.Checklist == nil && update.Language == nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"time";
+)
+
+// tasks an operator lists or purges with the admin cli
+type TaskPurgeFilter struct {
+	Status         string        // only tasks in this status (any when empty)
+	DueBefore      *time.Time    // only tasks due before this time
+	UpdatedBefore  *time.Time    // only tasks last changed before this time
+	TenantID       *string       // only tasks of this organization, "" for the default workspace (all when nil)
+}
+
+// check if a filter narrows the tasks down (purging needs at least one condition besides the organization)
+func (filter TaskPurgeFilter) Narrowed() bool {
+	return filter.Status != "" || filter.DueBefore != nil || filter.UpdatedBefore != nil
+}
+
+// check if a task passes the filter (organization is left to the task query)
+func (filter TaskPurgeFilter) Matches(task *Task) bool {
+	if filter.Status != "" && task.Status != filter.Status {
+		return false
+	}
+	if filter.DueBefore != nil && !task.DueDate.Before(*filter.DueBefore) {
+		return false
+	}
+	if filter.UpdatedBefore != nil && !task.UpdatedAt.Before(*filter.UpdatedBefore) {
+		return false
+	}
+	return true
+}
+
+// custom task purge errors
+var ErrTaskPurgeUnfiltered = errors.New("purge needs a status, due date or last change condition")       // custom purge everything error
@@ -0,0 +1,52 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+)
+
+// search backends (SEARCH_BACKEND)
+const (
+	SearchBackendMongo          = "mongo"              // text index of the tasks collection
+	SearchBackendElasticsearch  = "elasticsearch"      // separate index kept in sync with task events
+)
+
+// search result page sizes
+const (
+	DefaultTaskSearchLimit  = 20
+	MaxTaskSearchLimit      = 50
+)
+
+// full-text task search query
+type TaskSearchQuery struct {
+	Text        string                  // words to find in title or description
+	Language    string                  // only tasks in this language, words are stemmed in it
+	Visibility  *ProjectVisibility      // only tasks the caller may see (set by the usecase, nil for all)
+	TenantID    *string                 // only tasks of this organization (set by the tenant decorator, nil for all)
+	Limit       int                     // most relevant matches returned
+}
+
+// task found by a search, best match first
+type TaskSearchMatch struct {
+	TaskID      string
+	Score       float64                 // relevance, only comparable within one search
+	Highlights  map[string][]string     // field -> fragments with the matched words in <em></em>
+}
+
+// task found by a search with its relevance and highlighted fragments
+type TaskSearchResult struct {
+	Task        Task                    `json:"task"`
+	Score       float64                 `json:"score"`
+	Highlights  map[string][]string     `json:"highlights,omitempty"`
+}
+
+// search service interface (keeps a full-text index of tasks and answers queries from it)
+type SearchService interface {
+	IndexTask(ctx context.Context, task *Task) error                                          // add or replace a task in the index
+	RemoveTask(ctx context.Context, taskID string) error                                      // drop a task from the index (no error when missing)
+	SearchTasks(ctx context.Context, query TaskSearchQuery) ([]TaskSearchMatch, error)        // most relevant tasks first
+}
+
+// custom search errors
+var ErrInvalidSearchBackend = errors.New("invalid search backend, expected mongo or elasticsearch")       // custom configuration error
@@ -0,0 +1,48 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// reusable task of a user (its fields are copied into tasks created from it)
+type TaskTemplate struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                                               // mongodb's unique identifier for templates
+	OwnerID      string                `bson:"owner_id" json:"owner_id"`                                                              // user who saved the template (only they see it)
+	TenantID     string                `bson:"tenant_id,omitempty" json:"-"`                                                          // organization of the owner
+	Title        string                `bson:"title" json:"title" binding:"max=200"`                                                  // title of new tasks
+	Description  string                `bson:"description,omitempty" json:"description,omitempty" binding:"max=2000"`                // description of new tasks
+	Checklist    []string              `bson:"checklist,omitempty" json:"checklist,omitempty" binding:"omitempty,max=100,dive,max=200"`       // checklist items of new tasks (not done)
+	Tags         []string              `bson:"tags,omitempty" json:"tags,omitempty" binding:"omitempty,max=20"`                       // names of labels attached to new tasks
+	Priority     string                `bson:"priority,omitempty" json:"priority,omitempty" binding:"omitempty,oneof=low medium high urgent"`      // default priority of new tasks
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`                                                          // creation time (set by the server)
+	UpdatedAt    time.Time             `bson:"updated_at" json:"updated_at"`                                                          // last change (set by the server)
+}
+
+// task creation from a template (fields given here win over the template's)
+type TaskFromTemplateRequest struct {
+	Title        string                `json:"title" binding:"max=200"`                                             // title instead of the template's
+	Description  string                `json:"description" binding:"max=2000"`                                      // description instead of the template's
+	DueDate      time.Time             `json:"due_date" binding:"required"`                                         // due date of the task (required field)
+	Priority     string                `json:"priority" binding:"omitempty,oneof=low medium high urgent"`           // priority instead of the template's
+	ProjectID    *primitive.ObjectID   `json:"project_id"`                                                          // project of the task (editor role needed)
+}
+
+// template repository interface
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, template *TaskTemplate) error                                      // store new template
+	GetTemplates(ctx context.Context, ownerID string) ([]TaskTemplate, error)                              // get templates of a user ordered by title
+	GetTemplateByID(ctx context.Context, templateID string) (*TaskTemplate, error)                         // get template or return error if not found
+	UpdateTemplate(ctx context.Context, templateID string, template *TaskTemplate) (*TaskTemplate, error)   // update the given fields (nil lists are left, empty ones cleared) or return error if not found
+	DeleteTemplate(ctx context.Context, templateID string) error                                           // delete template or return error if not found
+	EnsureIndexes(ctx context.Context) error                                                               // create owner index
+}
+
+// custom template errors
+var (
+	ErrTemplateNotFound   = errors.New("template not found")           // custom template not found error
+	ErrInvalidTemplateID  = errors.New("invalid template ID")          // custom invalid template id error
+)
@@ -0,0 +1,44 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// deleted task kept in the trash until it is restored or purged
+type DeletedTask struct {
+	Task         `bson:",inline"`
+	DeletedAt    time.Time             `bson:"deleted_at" json:"deleted_at"`
+	DeletedBy    string                `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`         // user who deleted it
+	DeletedWith  *primitive.ObjectID   `bson:"deleted_with,omitempty" json:"deleted_with,omitempty"`     // task whose cascading delete took this subtask along
+}
+
+// page sizes of the trash listing
+const (
+	DefaultTrashLimit  = 50
+	MaxTrashLimit      = 200
+)
+
+// deleted task listing filter
+type TrashQuery struct {
+	TenantID    *string              // only tasks of this organization (nil for all)
+	Visibility  *ProjectVisibility   // only tasks the caller may see (nil for all)
+	Before      time.Time            // only tasks deleted before this time (zero for the newest)
+	Limit       int64
+}
+
+// task trash repository interface (deleted tasks are kept in the deleted_tasks collection)
+type TaskTrashRepository interface {
+	Add(ctx context.Context, tasks []DeletedTask) error                                              // keep copies of tasks about to be deleted
+	GetDeletedTasks(ctx context.Context, query TrashQuery) ([]DeletedTask, error)                     // deleted tasks newest first
+	GetDeletedTask(ctx context.Context, taskID string) (*DeletedTask, error)                          // deleted task or ErrDeletedTaskNotFound
+	Restore(ctx context.Context, taskID string) ([]Task, error)                                       // take a task and the subtasks deleted with it out of the trash, the task comes first
+	Purge(ctx context.Context, deletedBefore time.Time, tenantID *string, limit int64) ([]primitive.ObjectID, error)     // remove deleted tasks for good, returns the ids removed
+	EnsureIndexes(ctx context.Context) error                                                          // trash listing order
+}
+
+// custom task trash errors
+var ErrDeletedTaskNotFound = errors.New("deleted task not found")       // custom missing deleted task error
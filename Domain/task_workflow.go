@@ -0,0 +1,96 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"fmt";
+	"strings";
+)
+
+// task statuses in workflow order (todo, doing, done)
+const (
+	TaskStatusPending     = "pending"
+	TaskStatusInProgress  = "in_progress"
+	TaskStatusCompleted   = "completed"
+)
+
+var TaskStatuses = []string{TaskStatusPending, TaskStatusInProgress, TaskStatusCompleted}
+
+// transitions allowed unless configured otherwise: work starts before it is done, and can be paused or reopened
+const DefaultTaskTransitions = "pending->in_progress, in_progress->pending, in_progress->completed, completed->in_progress"
+
+// status changes a task may go through
+type TaskWorkflow struct {
+	transitions  map[string][]string       // status -> statuses it may change to, in workflow order
+}
+
+// check if a status is known
+func IsTaskStatus(status string) bool {
+	for _, known := range TaskStatuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
+
+// check if a task may change from one status to another (keeping the status is always allowed)
+func (workflow TaskWorkflow) Allows(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range workflow.transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// statuses a task may change to from a status
+func (workflow TaskWorkflow) Next(from string) []string {
+	return workflow.transitions[from]
+}
+
+// parse allowed transitions from configuration
+// transitions are separated by ",", e.g. "pending->in_progress, in_progress->completed"
+func ParseTaskWorkflow(raw string) (TaskWorkflow, error) {
+
+	allowed := map[string]map[string]bool{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(entry, "->")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || !IsTaskStatus(from) || !IsTaskStatus(to) || from == to {
+			return TaskWorkflow{}, fmt.Errorf("%w: %q, expected \"status->status\" with statuses %s", ErrInvalidTaskWorkflow, entry, strings.Join(TaskStatuses, ", "))
+		}
+		if allowed[from] == nil {
+			allowed[from] = map[string]bool{}
+		}
+		allowed[from][to] = true
+	}
+	if len(allowed) == 0 {
+		return TaskWorkflow{}, fmt.Errorf("%w: no transitions", ErrInvalidTaskWorkflow)
+	}
+
+	// keep workflow order so transition links are stable
+	workflow := TaskWorkflow{transitions: map[string][]string{}}
+	for _, from := range TaskStatuses {
+		for _, to := range TaskStatuses {
+			if allowed[from][to] {
+				workflow.transitions[from] = append(workflow.transitions[from], to)
+			}
+		}
+	}
+
+	return workflow, nil
+}
+
+// custom task workflow errors
+var (
+	ErrInvalidTaskWorkflow = errors.New("invalid task status transitions")       // custom configuration error
+	ErrInvalidTransition   = errors.New("task status change not allowed")        // custom workflow violation error
+)
@@ -0,0 +1,37 @@
+package domain
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// aggregate usage counts (no ids, names or content)
+type UsageCounts struct {
+	Tasks                 int64    `json:"tasks"`                      // all tasks
+	Subtasks              int64    `json:"subtasks"`                   // tasks with a parent
+	TasksWithReminders    int64    `json:"tasks_with_reminders"`       // tasks with reminders enabled
+	Users                 int64    `json:"users"`                      // registered users
+	Admins                int64    `json:"admins"`                     // users with the admin role
+	OAuthClients          int64    `json:"oauth_clients"`              // registered third-party clients
+	PersonalAccessTokens  int64    `json:"personal_access_tokens"`     // active personal access tokens
+}
+
+// anonymized usage report sent when telemetry is enabled
+type TelemetryReport struct {
+	InstanceID   string             `json:"instance_id"`       // stable anonymous id of the installation
+	Version      string             `json:"version"`           // application version
+	GeneratedAt  time.Time          `json:"generated_at"`      // when the report was built
+	Counts       UsageCounts        `json:"counts"`            // aggregate usage
+	Features     map[string]bool    `json:"features"`          // optional features in use
+}
+
+// usage statistics repository interface
+type UsageStatsRepository interface {
+	GetUsageCounts(ctx context.Context) (*UsageCounts, error)       // count entities across collections
+}
+
+// telemetry sender interface
+type TelemetrySender interface {
+	Send(ctx context.Context, report *TelemetryReport) error       // deliver report to the collector
+}
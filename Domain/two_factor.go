@@ -0,0 +1,96 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// two-factor login limits
+const (
+	TwoFactorBackupCodeCount  = 10        // backup codes issued when two-factor authentication is enabled
+	MaxTwoFactorAttempts      = 5         // wrong codes before a login challenge is dropped
+)
+
+// two-factor authentication settings of a user (time-based one-time passwords, rfc 6238)
+type TwoFactorSettings struct {
+	Secret         string        `bson:"secret,omitempty"`             // base32 secret shared with the authenticator app
+	PendingSecret  string        `bson:"pending_secret,omitempty"`     // secret from setup, becomes Secret once a code from it is confirmed
+	EnabledAt      *time.Time    `bson:"enabled_at,omitempty"`         // logins need a second step since this time (nil while only set up)
+	BackupCodes    []string      `bson:"backup_codes,omitempty"`       // sha256 of the unused backup codes
+	LastUsedStep   int64         `bson:"last_used_step,omitempty"`     // time step of the newest accepted code, older codes can't be used again
+}
+
+// check if logins need a second step
+func (settings *TwoFactorSettings) IsEnabled() bool {
+	return settings != nil && settings.EnabledAt != nil
+}
+
+// secret to add to an authenticator app
+type TwoFactorSetup struct {
+	Secret           string   `json:"secret"`              // base32 secret for manual entry
+	ProvisioningURI  string   `json:"provisioning_uri"`    // otpauth:// uri, shown as a qr code to scan
+}
+
+// backup codes shown once when two-factor authentication is enabled
+type TwoFactorBackupCodes struct {
+	BackupCodes  []string   `json:"backup_codes"`       // each code signs in once instead of an app code
+}
+
+// code from the authenticator app (or a backup code where allowed)
+type TwoFactorCodeRequest struct {
+	Code         string      `json:"code" binding:"required,max=32"`        // current app code (required field)
+}
+
+// second login step payload
+type TwoFactorLoginRequest struct {
+	ChallengeToken  string   `json:"challenge_token" binding:"required"`        // token from the password login (required field)
+	Code            string   `json:"code" binding:"required,max=32"`            // app code or backup code (required field)
+}
+
+// login waiting for the second step (single use, removed by a ttl index once expired)
+type TwoFactorChallenge struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty" json:"id"`          // mongodb's unique identifier for challenges
+	UserID       primitive.ObjectID    `bson:"user_id" json:"user_id"`           // user who passed the password check
+	TokenHash    string                `bson:"token_hash" json:"-"`              // sha256 of the challenge token (the token itself is only returned to the client)
+	Attempts     int                   `bson:"attempts" json:"attempts"`         // wrong codes entered so far
+	ExpiresAt    time.Time             `bson:"expires_at" json:"expires_at"`     // expiry time
+	CreatedAt    time.Time             `bson:"created_at" json:"created_at"`     // creation time
+}
+
+// password login that still needs a code, the client completes it with the challenge token
+type TwoFactorRequiredError struct {
+	ChallengeToken  string
+	ExpiresAt       time.Time
+}
+
+func (err *TwoFactorRequiredError) Error() string {
+	return "two-factor authentication required"
+}
+
+// two-factor challenge repository interface
+type TwoFactorChallengeRepository interface {
+	CreateChallenge(ctx context.Context, challenge *TwoFactorChallenge) error                    // store new challenge
+	GetChallengeByHash(ctx context.Context, tokenHash string) (*TwoFactorChallenge, error)        // get challenge by its hash or return error if not found
+	IncrementAttempts(ctx context.Context, id primitive.ObjectID) (int, error)                    // count a wrong code, returns wrong codes so far
+	DeleteChallenge(ctx context.Context, id primitive.ObjectID) error                             // end a challenge
+	EnsureIndexes(ctx context.Context) error                                                      // create ttl and lookup indexes
+}
+
+// time-based one-time password service interface
+type TOTPService interface {
+	GenerateSecret() (string, error)                                        // new random base32 secret
+	ProvisioningURI(secret string, account string) string                   // otpauth:// uri authenticator apps read from a qr code
+	Validate(secret string, code string, at time.Time) (int64, bool)        // check a code, returns the time step it belongs to
+}
+
+// custom two-factor errors
+var (
+	ErrTwoFactorEnabled           = errors.New("two-factor authentication is already enabled")               // custom setup while enabled error
+	ErrTwoFactorNotSetUp          = errors.New("two-factor authentication has not been set up")              // custom enable without setup error
+	ErrTwoFactorNotEnabled        = errors.New("two-factor authentication is not enabled")                   // custom disable while off error
+	ErrInvalidTwoFactorCode       = errors.New("invalid two-factor code")                                    // custom wrong or reused code error
+	ErrInvalidTwoFactorChallenge  = errors.New("invalid or expired two-factor challenge")                    // custom challenge error
+)
@@ -0,0 +1,13 @@
+package domain
+
+// imports
+import (
+	"context";
+)
+
+// unit of work (runs several repository calls atomically)
+// repositories called with the context given to fn take part in the unit, fn may run again when the
+// store retries a conflicting unit, so side effects (events, emails) belong after Do returns
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error       // commit when fn succeeds, roll back when it returns an error
+}
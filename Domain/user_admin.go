@@ -0,0 +1,97 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// account states for user listing filters
+const (
+	UserStatusActive       = "active"
+	UserStatusDeactivated  = "deactivated"
+)
+
+const DefaultUserPageSize = 20        // users per page when no limit is given
+const MaxUserPageSize = 100           // largest page of users
+
+// user listing filter for admins
+type UserQuery struct {
+	Search  string      // case-insensitive part of username, email or display name (empty for all)
+	Role    string      // only users with this role (empty for all)
+	Status  string      // only active or deactivated accounts (empty for all)
+	Page    int         // 1-based page number
+	Limit   int         // users per page
+	TenantID *string    // only users of this organization (set by the tenant repository, nil for all)
+	Sort    SortField   // order of the page (by username when empty)
+}
+
+// sortable user fields mapped to their document keys
+var UserSortFields = map[string]string{
+	"username":    "username",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+}
+
+// user as listed to admins (no credentials)
+type UserSummary struct {
+	ID             primitive.ObjectID    `json:"id"`
+	Username       string                `json:"username"`
+	Email          string                `json:"email,omitempty"`
+	DisplayName    string                `json:"display_name,omitempty"`
+	Role           string                `json:"role"`
+	LockedUntil    *time.Time            `json:"locked_until,omitempty"`        // login blocked after failed logins
+	DeactivatedAt  *time.Time            `json:"deactivated_at,omitempty"`      // account disabled by an admin
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// page of users
+type UserPage struct {
+	Users  []UserSummary  `json:"users"`
+	Total  int64          `json:"total"`         // users matching the query across all pages
+	Page   int            `json:"page"`
+	Limit  int            `json:"limit"`
+}
+
+// user detail for admins (account with project memberships)
+type UserDetail struct {
+	UserSummary
+	Projects  []ProjectMembership  `json:"projects"`
+}
+
+// project a user is a member of
+type ProjectMembership struct {
+	ProjectID  primitive.ObjectID  `json:"project_id"`
+	Name       string              `json:"name"`
+	Role       string              `json:"role"`
+}
+
+// what happened to the projects of a deleted user
+type UserDeletion struct {
+	ProjectsReassigned  int  `json:"projects_reassigned"`       // projects the user owned alone, handed to the new owner
+	ProjectsDeleted     int  `json:"projects_deleted"`          // projects the user owned alone, deleted with their tasks
+	TasksDeleted        int  `json:"tasks_deleted"`             // tasks of the deleted projects
+	SessionsRevoked     int  `json:"sessions_revoked"`
+}
+
+// summary of a user without credentials
+func (user *User) Summary() UserSummary {
+	return UserSummary{ID: user.ID, Username: user.Username, Email: user.Email, DisplayName: user.DisplayName, Role: user.Role,
+		LockedUntil: user.LockedUntil, DeactivatedAt: user.DeactivatedAt, CreatedAt: user.CreatedAt, UpdatedAt: user.UpdatedAt}
+}
+
+// check if an admin deactivated the account
+func (user *User) IsDeactivated() bool {
+	return user.DeactivatedAt != nil
+}
+
+// custom user management errors
+var (
+	ErrAccountDeactivated  = errors.New("account is deactivated")                                   // custom deactivated account error
+	ErrLastAdmin           = errors.New("the last active admin cannot be demoted, deactivated or deleted")      // custom last admin error
+	ErrManageSelf          = errors.New("admins cannot demote, deactivate or delete their own account")      // custom self management error
+	ErrUserNotAdmin        = errors.New("user is not an admin")                                     // custom demote error
+	ErrInvalidUserQuery    = errors.New("invalid user query")                                       // custom user listing error
+)
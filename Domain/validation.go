@@ -0,0 +1,54 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"strings";
+)
+
+// field that broke a domain rule
+type FieldViolation struct {
+	Field    string    `json:"field"`        // json name of the invalid field
+	Message  string    `json:"message"`      // what is wrong with it
+}
+
+// invalid data rejected by a usecase, with every broken field rule (answered with 422)
+// errors.Is(err, ErrValidation) matches it, errors.As gives the violations
+type ValidationError struct {
+	Violations  []FieldViolation    `json:"errors"`
+}
+
+// new validation error for one field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Violations: []FieldViolation{{Field: field, Message: message}}}
+}
+
+// record a broken rule
+func (validationErr *ValidationError) Add(field, message string) {
+	validationErr.Violations = append(validationErr.Violations, FieldViolation{Field: field, Message: message})
+}
+
+// the error when a rule was broken, nil otherwise
+func (validationErr *ValidationError) Err() error {
+	if len(validationErr.Violations) == 0 {
+		return nil
+	}
+	return validationErr
+}
+
+// violations as text, e.g. "title: cannot be empty; due_date: must be in the future"
+func (validationErr *ValidationError) Error() string {
+	messages := make([]string, 0, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		messages = append(messages, violation.Field+": "+violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// match ErrValidation
+func (validationErr *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// custom validation errors
+var ErrValidation = errors.New("validation failed")
@@ -0,0 +1,109 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// event type of test deliveries (sent on request, never published on the event bus)
+const EventWebhookTest = "webhook.test"
+
+// delivery log page sizes
+const (
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 200
+)
+
+// webhook (receives domain events of the workspace or of one organization as signed json posts)
+type Webhook struct {
+	ID              primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                                                // mongodb's unique identifier for webhooks
+	URL             string                `bson:"url" json:"url"`                                                         // endpoint receiving the events
+	Events          []string              `bson:"events" json:"events"`                                                   // event types delivered (empty for all)
+	TenantID        string                `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`                         // only events of this organization (events of every tenant when empty)
+	Secret          string                `bson:"secret" json:"secret,omitempty"`                                         // signing key, only shown when the webhook is created
+	Enabled         bool                  `bson:"enabled" json:"enabled"`                                                 // disabled webhooks receive nothing
+	FailingSince    *time.Time            `bson:"failing_since,omitempty" json:"failing_since,omitempty"`                 // first failed delivery since the last successful one
+	DisabledAt      *time.Time            `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`                     // when the webhook was disabled
+	DisabledReason  string                `bson:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`             // why (by an admin or after failing too long)
+	CreatedBy       string                `bson:"created_by" json:"created_by"`                                           // user id of the admin who added it
+	CreatedAt       time.Time             `bson:"created_at" json:"created_at"`                                           // creation time
+}
+
+// webhook create and update request (omitted fields stay unchanged on update)
+type WebhookInput struct {
+	URL      string      `json:"url" binding:"omitempty,url,max=2000"`       // endpoint receiving the events
+	Events   []string    `json:"events" binding:"omitempty,max=50"`          // event types delivered (empty for all)
+	TenantID *string     `json:"tenant_id"`                                  // organization whose events are delivered ("" for every tenant)
+	Enabled  *bool       `json:"enabled"`                                    // true re-enables an auto-disabled webhook
+}
+
+// check if the webhook receives an event (type and organization)
+func (webhook *Webhook) Accepts(event DomainEvent) bool {
+	if webhook.TenantID != "" && webhook.TenantID != event.TenantID {
+		return false
+	}
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, accepted := range webhook.Events {
+		if accepted == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// delivery of one event to a webhook with all its attempts
+type WebhookDelivery struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty" json:"id"`                    // mongodb's unique identifier for deliveries
+	WebhookID  string                `bson:"webhook_id" json:"webhook_id"`               // receiving webhook
+	EventID    string                `bson:"event_id" json:"event_id"`                   // delivered domain event
+	EventType  string                `bson:"event_type" json:"event_type"`               // e.g. task.created, webhook.test
+	Test       bool                  `bson:"test" json:"test"`                           // sent through the test endpoint
+	Payload    string                `bson:"payload" json:"payload"`                     // request body as sent
+	Attempts   []WebhookAttempt      `bson:"attempts" json:"attempts"`                   // one entry per try, oldest first
+	Succeeded  bool                  `bson:"succeeded" json:"succeeded"`                 // an attempt got a 2xx answer
+	CreatedAt  time.Time             `bson:"created_at" json:"created_at"`               // when the event was delivered
+	ExpiresAt  time.Time             `bson:"expires_at" json:"-"`                        // mongodb removes the delivery after this time
+}
+
+// single try of a delivery
+type WebhookAttempt struct {
+	At          time.Time   `bson:"at" json:"at"`                                         // when the request was sent
+	StatusCode  int         `bson:"status_code,omitempty" json:"status_code,omitempty"`   // http status of the answer (omitted when none came)
+	LatencyMS   int64       `bson:"latency_ms" json:"latency_ms"`                         // time until the answer or the failure
+	Error       string      `bson:"error,omitempty" json:"error,omitempty"`               // network error or unexpected status
+}
+
+// webhook repository interface
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) error                                        // store new webhook
+	GetWebhooks(ctx context.Context) ([]Webhook, error)                                               // all webhooks ordered by creation
+	GetWebhookByID(ctx context.Context, webhookID string) (*Webhook, error)                           // get webhook or return error if not found
+	UpdateWebhook(ctx context.Context, webhookID string, webhook *Webhook) (*Webhook, error)          // replace url, events and enabled state
+	DeleteWebhook(ctx context.Context, webhookID string) error                                        // delete webhook or return error if not found
+	RecordOutcome(ctx context.Context, webhookID string, succeeded bool, at time.Time) error           // clear or start the failing streak
+}
+
+// webhook delivery repository interface
+type WebhookDeliveryRepository interface {
+	RecordDelivery(ctx context.Context, delivery *WebhookDelivery) error                                        // store delivery with its attempts
+	GetDeliveries(ctx context.Context, webhookID string, before time.Time, limit int64) ([]WebhookDelivery, error)       // deliveries newest first (before zero for the latest)
+	DeleteDeliveries(ctx context.Context, webhookID string) error                                               // drop the log of a deleted webhook
+	EnsureIndexes(ctx context.Context) error                                                                    // index lookups and expire deliveries at expires_at
+}
+
+// webhook sender interface
+type WebhookSender interface {
+	Send(ctx context.Context, url string, secret string, payload []byte) (int, error)        // post payload, returns the status code (0 when no answer came)
+}
+
+// custom webhook errors
+var (
+	ErrWebhookNotFound   = errors.New("webhook not found")                                     // custom webhook not found error
+	ErrInvalidWebhookID  = errors.New("invalid webhook ID")                                    // custom invalid webhook id error
+	ErrInvalidWebhook    = errors.New("webhook needs an http or https url")                    // custom invalid webhook error
+)
@@ -0,0 +1,73 @@
+package domain
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// how a worklog was recorded
+const (
+	WorklogSourceTimer   = "timer"        // started and stopped on the task
+	WorklogSourceManual  = "manual"       // entered afterwards
+)
+
+// time a user spent on a task
+type Worklog struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`                     // mongodb's unique identifier for worklogs
+	TaskID     string               `bson:"task_id" json:"task_id"`
+	UserID     string               `bson:"user_id" json:"user_id"`                      // user who did the work
+	Username   string               `bson:"username" json:"username"`
+	TenantID   string               `bson:"tenant_id,omitempty" json:"-"`                // organization of the task (for reports)
+	Source     string               `bson:"source" json:"source"`                        // timer or manual
+	Running    bool                 `bson:"running,omitempty" json:"running"`            // timer not stopped yet (one per user)
+	StartedAt  time.Time            `bson:"started_at" json:"started_at"`
+	EndedAt    *time.Time           `bson:"ended_at,omitempty" json:"ended_at,omitempty"` // nil while the timer runs
+	Seconds    int64                `bson:"seconds" json:"seconds"`                      // time spent (0 while the timer runs)
+	Note       string               `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt  time.Time            `bson:"created_at" json:"created_at"`
+}
+
+// manual worklog entry
+type WorklogInput struct {
+	Minutes    int          `json:"minutes" binding:"required,min=1,max=1440"`       // time spent, at most a day per entry
+	StartedAt  *time.Time   `json:"started_at"`                                       // when the work started (minutes before now when empty)
+	Note       string       `json:"note" binding:"max=500"`
+}
+
+// time spent by one user on a task
+type WorklogUserTotal struct {
+	UserID    string   `json:"user_id"`
+	Username  string   `json:"username"`
+	Seconds   int64    `json:"seconds"`
+}
+
+// worklogs of a task with the time spent
+type TaskWorklogs struct {
+	TaskID        string               `json:"task_id"`
+	TotalSeconds  int64                `json:"total_seconds"`      // stopped timers and manual entries
+	Users         []WorklogUserTotal   `json:"users"`              // time spent per user, most first
+	Worklogs      []Worklog            `json:"worklogs"`           // oldest first, running timers included
+}
+
+// worklog repository interface
+type WorklogRepository interface {
+	StartTimer(ctx context.Context, worklog *Worklog) error                                          // store a running timer, ErrTimerRunning when the user has one already
+	GetRunningTimer(ctx context.Context, userID string) (*Worklog, error)                             // running timer of a user or ErrTimerNotRunning
+	StopTimer(ctx context.Context, userID string, taskID string, endedAt time.Time) (*Worklog, error) // stop the user's timer on a task or return ErrTimerNotRunning
+	AddWorklog(ctx context.Context, worklog *Worklog) error                                          // store a manual entry
+	GetWorklogs(ctx context.Context, taskID string) ([]Worklog, error)                                // worklogs of a task, oldest first
+	DeleteWorklog(ctx context.Context, taskID string, worklogID string, userID string) error          // delete an entry of the user (any user when userID is empty)
+	DeleteTaskWorklogs(ctx context.Context, taskID string) error                                      // drop the worklogs of a deleted task
+	EnsureIndexes(ctx context.Context) error                                                          // one running timer per user
+}
+
+// custom worklog errors
+var (
+	ErrTimerRunning       = errors.New("a timer is already running")             // custom active timer error
+	ErrTimerNotRunning    = errors.New("no timer is running on the task")        // custom missing timer error
+	ErrWorklogNotFound    = errors.New("worklog not found")                      // custom missing worklog error
+	ErrInvalidWorklogID   = errors.New("invalid worklog ID")                     // custom worklog id format error
+)
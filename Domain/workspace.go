@@ -0,0 +1,64 @@
+package domain
+
+// imports
+import (
+	"errors";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// version of the workspace bundle format written by exports
+const WorkspaceBundleVersion = 1
+
+// whole workspace as moved between instances (ids are the source instance's, imports remap them)
+type WorkspaceBundle struct {
+	Version     int               `json:"version"`                 // bundle format version
+	ExportedAt  time.Time         `json:"exported_at"`             // when the export was taken
+	Users       []WorkspaceUser   `json:"users,omitempty"`         // accounts (only when exported with users)
+	Labels      []Label           `json:"labels"`                  // labels (instance-wide, matched by name on import)
+	Projects    []Project         `json:"projects"`                // projects with their members
+	Tasks       []Task            `json:"tasks"`                   // tasks with subtasks, recurrence and reminders
+}
+
+// account in a workspace bundle (the password stays hashed)
+type WorkspaceUser struct {
+	ID             primitive.ObjectID    `json:"id"`
+	Username       string                `json:"username"`
+	Email          string                `json:"email,omitempty"`
+	DisplayName    string                `json:"display_name,omitempty"`
+	PasswordHash   string                `json:"password_hash"`                      // bcrypt or argon2id hash, users keep their password
+	Role           string                `json:"role"`
+	DeactivatedAt  *time.Time            `json:"deactivated_at,omitempty"`
+	Identities     []LinkedIdentity      `json:"identities,omitempty"`               // google/github sign-ins
+}
+
+// workspace export options
+type WorkspaceExportOptions struct {
+	TenantID      string      // organization exported (ignored for requests, they export their own)
+	IncludeUsers  bool        // add accounts with their password hashes
+}
+
+// workspace import options
+type WorkspaceImportOptions struct {
+	TenantID      string      // organization imported into (ignored for requests, they import into their own)
+	IncludeUsers  bool        // create the bundle's accounts (otherwise member references must name existing users)
+	OwnerID       string      // owner of projects left without one (the caller for requests)
+}
+
+// outcome of a workspace import
+type WorkspaceImportReport struct {
+	UsersCreated     int      `json:"users_created"`
+	UsersMatched     int      `json:"users_matched"`       // bundle accounts that already existed (same username or email)
+	LabelsCreated    int      `json:"labels_created"`
+	LabelsMatched    int      `json:"labels_matched"`      // labels that already existed (same name)
+	ProjectsCreated  int      `json:"projects_created"`
+	TasksCreated     int      `json:"tasks_created"`
+	MembersDropped   int      `json:"members_dropped"`     // project members with no account on this instance
+}
+
+// custom workspace migration errors
+var (
+	ErrUnsupportedBundle     = errors.New("unsupported workspace bundle version")                                  // custom bundle version error
+	ErrWorkspaceOwnerNeeded  = errors.New("a project would be left without owner, name an owner for the import")   // custom project owner error
+	ErrWorkspaceUserConflict = errors.New("username or email belongs to an account of another workspace")          // custom cross-tenant account error
+)
@@ -0,0 +1,63 @@
+package infrastructure
+
+// imports
+import (
+	"strings";
+	"time";
+	"github.com/gin-gonic/gin";
+)
+
+// api versions and where they are mounted
+const (
+	APIVersionV1 = "v1"
+	APIPrefixV1  = "/api/v1"
+)
+
+const apiVersionKey = "apiVersion"
+
+// api version handler
+// marks every route of a group with its version so handlers can answer differently per version
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionKey, version)
+		c.Header("API-Version", version)
+		c.Next()
+	}
+}
+
+// unversioned alias handler
+// unversioned routes answer like their successor under prefix and point clients to it (RFC 8594 sunset when known),
+// calls are logged by the deprecation policy
+func Deprecated(successorPrefix string, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(deprecatedAliasKey, true)
+		c.Header("Deprecation", "true")
+		if !sunset.IsZero() {
+			setSunset(c, sunset)
+		}
+		c.Writer.Header().Add("Link", "<"+successorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
+
+// api version the request was routed to (empty outside versioned groups)
+func APIVersionOf(c *gin.Context) string {
+	return c.GetString(apiVersionKey)
+}
+
+// matched route without its version prefix (e.g. /tasks/:id for /api/v1/tasks/:id and /tasks/:id)
+func RoutePath(c *gin.Context) string {
+	path := c.FullPath()
+	if rest, ok := strings.CutPrefix(path, "/api/"); ok {
+		if slash := strings.Index(rest, "/"); slash >= 0 {
+			return rest[slash:]
+		}
+		return "/"
+	}
+	return path
+}
+
+// version prefix of the matched route (e.g. /api/v1, empty for unversioned routes)
+func APIPrefix(c *gin.Context) string {
+	return strings.TrimSuffix(c.FullPath(), RoutePath(c))
+}
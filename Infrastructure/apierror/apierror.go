@@ -0,0 +1,127 @@
+package apierror
+
+// imports
+import (
+	"errors";
+	"net/http";
+	"strings";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// codes of errors that aren't domain errors (the others are derived from the status text, e.g. NOT_FOUND)
+const (
+	CodeValidationFailed  = "VALIDATION_FAILED"       // request body or data breaks field rules
+	CodeUnauthorized      = "UNAUTHORIZED"            // missing or invalid credentials
+	CodeForbidden         = "FORBIDDEN"               // caller may not do this
+	CodeRateLimited       = "RATE_LIMITED"            // too many requests
+	CodeInternal          = "INTERNAL_ERROR"          // unexpected server error
+)
+
+// error body of every failed request, other fields are added next to them (violations, limits, ...)
+type Response struct {
+	Code   string   `json:"code"`      // machine-readable error code (e.g. TASK_NOT_FOUND)
+	Error  string   `json:"error"`     // human-readable message
+}
+
+// answer with a status chosen by the handler, the code comes from the error
+func Respond(c *gin.Context, status int, err error) {
+	RespondWith(c, status, err, nil)
+}
+
+// answer with a status chosen by the handler and extra fields (e.g. the lock holder)
+func RespondWith(c *gin.Context, status int, err error, fields gin.H) {
+	write(c, status, Code(err, status), err.Error(), fields)
+}
+
+// answer with a message that isn't a domain error, the code comes from the status
+func Message(c *gin.Context, status int, message string) {
+	MessageWith(c, status, message, nil)
+}
+
+// answer with a message and extra fields (e.g. the missing permission)
+func MessageWith(c *gin.Context, status int, message string, fields gin.H) {
+	write(c, status, statusCode(status), message, fields)
+}
+
+// answer with field level errors of a body that couldn't be bound
+func Invalid(c *gin.Context, violations interface{}) {
+	write(c, http.StatusBadRequest, CodeValidationFailed, domain.ErrValidation.Error(), gin.H{"errors": violations})
+}
+
+// leave the answer to the error middleware, status and code come from the error
+func Abort(c *gin.Context, err error) {
+	c.Error(err).SetType(gin.ErrorTypePublic)
+	c.Abort()
+}
+
+// error handler
+// answers requests aborted with Abort, unknown errors become 500 INTERNAL_ERROR
+func Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		c.Next()
+
+		public := c.Errors.ByType(gin.ErrorTypePublic)
+		if len(public) == 0 {
+			return
+		}
+		err := public.Last().Err
+
+		// field violations are listed like bodies rejected during binding
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			write(c, http.StatusUnprocessableEntity, CodeValidationFailed, domain.ErrValidation.Error(), gin.H{"errors": validationErr.Violations})
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if entry, ok := lookup(err); ok {
+			status = entry.status
+		}
+		Respond(c, status, err)
+	}
+}
+
+// machine-readable code of an error answered with a status
+func Code(err error, status int) string {
+	if entry, ok := lookup(err); ok {
+		return entry.code
+	}
+	return statusCode(status)
+}
+
+// registered mapping of an error or the error it wraps
+func lookup(err error) (mapping, bool) {
+	if entry, ok := registry[err]; ok {
+		return entry, true
+	}
+	for _, entry := range mappings {
+		if errors.Is(err, entry.err) {
+			return entry, true
+		}
+	}
+	return mapping{}, false
+}
+
+// code of a status, e.g. 404 -> NOT_FOUND
+func statusCode(status int) string {
+	switch status {
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusInternalServerError:
+		return CodeInternal
+	}
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// write the error body and stop the handler chain
+func write(c *gin.Context, status int, code string, message string, fields gin.H) {
+	body := gin.H{"code": code, "error": message}
+	for key, value := range fields {
+		body[key] = value
+	}
+	c.AbortWithStatusJSON(status, body)
+}
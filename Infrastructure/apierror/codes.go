@@ -0,0 +1,146 @@
+package apierror
+
+// imports
+import (
+	"net/http";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// status and code a domain error is answered with
+type mapping struct {
+	err     error
+	status  int
+	code    string
+}
+
+// every domain error the api returns, the status is the one used when a handler leaves the choice to the error middleware
+var mappings = []mapping{
+	{domain.ErrInvalidAdminInvite,        http.StatusForbidden,             "INVALID_ADMIN_INVITE"},
+	{domain.ErrInvalidAuditFilter,        http.StatusBadRequest,            "INVALID_AUDIT_FILTER"},
+	{domain.ErrMyDayNotFound,             http.StatusNotFound,              "MY_DAY_NOT_FOUND"},
+	{domain.ErrInvalidWorkingHours,       http.StatusBadRequest,            "INVALID_WORKING_HOURS"},
+	{domain.ErrDuplicateDayTask,          http.StatusBadRequest,            "DUPLICATE_DAY_TASK"},
+	{domain.ErrInvalidTimezone,           http.StatusBadRequest,            "INVALID_TIMEZONE"},
+	{domain.ErrTaskNotFound,              http.StatusNotFound,              "TASK_NOT_FOUND"},
+	{domain.ErrInvalidTaskID,             http.StatusBadRequest,            "INVALID_TASK_ID"},
+	{domain.ErrInvalidPriority,           http.StatusBadRequest,            "INVALID_PRIORITY"},
+	{domain.ErrInvalidSortField,          http.StatusBadRequest,            "INVALID_SORT_FIELD"},
+	{domain.ErrParentNotFound,            http.StatusBadRequest,            "PARENT_NOT_FOUND"},
+	{domain.ErrTaskCycle,                 http.StatusBadRequest,            "TASK_CYCLE"},
+	{domain.ErrTaskHasSubtasks,           http.StatusConflict,              "TASK_HAS_SUBTASKS"},
+	{domain.ErrTaskClientIDExists,        http.StatusConflict,              "TASK_CLIENT_ID_EXISTS"},
+	{domain.ErrTaskModified,              http.StatusPreconditionFailed,    "TASK_MODIFIED"},
+	{domain.ErrUserExists,                http.StatusConflict,              "USER_EXISTS"},
+	{domain.ErrUserNotFound,              http.StatusNotFound,              "USER_NOT_FOUND"},
+	{domain.ErrInvalidUserID,             http.StatusBadRequest,            "INVALID_USER_ID"},
+	{domain.ErrInvalidCredentials,        http.StatusUnauthorized,          "INVALID_CREDENTIALS"},
+	{domain.ErrWrongPassword,             http.StatusForbidden,             "WRONG_PASSWORD"},
+	{domain.ErrSetupRequired,             http.StatusConflict,              "SETUP_REQUIRED"},
+	{domain.ErrSetupCompleted,            http.StatusConflict,              "SETUP_COMPLETED"},
+	{domain.ErrInvalidSetupToken,         http.StatusForbidden,             "INVALID_SETUP_TOKEN"},
+	{domain.ErrAccountLocked,             http.StatusLocked,                "ACCOUNT_LOCKED"},
+	{domain.ErrUnauthorized,              http.StatusUnauthorized,          "UNAUTHORIZED"},
+	{domain.ErrPartialResult,             http.StatusGatewayTimeout,        "PARTIAL_RESULT"},
+	{domain.ErrInvalidVerificationToken,  http.StatusBadRequest,            "INVALID_VERIFICATION_TOKEN"},
+	{domain.ErrEmailNotVerified,          http.StatusForbidden,             "EMAIL_NOT_VERIFIED"},
+	{domain.ErrRejectedByExtension,       http.StatusForbidden,             "REJECTED_BY_EXTENSION"},
+	{domain.ErrUnknownProvider,           http.StatusNotFound,              "UNKNOWN_PROVIDER"},
+	{domain.ErrInvalidOAuthState,         http.StatusBadRequest,            "INVALID_O_AUTH_STATE"},
+	{domain.ErrExternalLoginFailed,       http.StatusUnauthorized,          "EXTERNAL_LOGIN_FAILED"},
+	{domain.ErrIdentityLinkRequired,      http.StatusConflict,              "IDENTITY_LINK_REQUIRED"},
+	{domain.ErrIdempotencyKeyInUse,       http.StatusConflict,              "IDEMPOTENCY_KEY_IN_USE"},
+	{domain.ErrIdempotencyKeyMismatch,    http.StatusUnprocessableEntity,   "IDEMPOTENCY_KEY_MISMATCH"},
+	{domain.ErrUnknownIntegrityCheck,     http.StatusBadRequest,            "UNKNOWN_INTEGRITY_CHECK"},
+	{domain.ErrLabelNotFound,             http.StatusNotFound,              "LABEL_NOT_FOUND"},
+	{domain.ErrInvalidLabelID,            http.StatusBadRequest,            "INVALID_LABEL_ID"},
+	{domain.ErrLabelExists,               http.StatusConflict,              "LABEL_EXISTS"},
+	{domain.ErrInvalidTaskLanguage,       http.StatusBadRequest,            "INVALID_TASK_LANGUAGE"},
+	{domain.ErrOAuthClientNotFound,       http.StatusNotFound,              "O_AUTH_CLIENT_NOT_FOUND"},
+	{domain.ErrInvalidRedirectURI,        http.StatusBadRequest,            "INVALID_REDIRECT_URI"},
+	{domain.ErrInvalidScope,              http.StatusBadRequest,            "INVALID_SCOPE"},
+	{domain.ErrInvalidGrant,              http.StatusBadRequest,            "INVALID_GRANT"},
+	{domain.ErrInvalidClient,             http.StatusUnauthorized,          "INVALID_CLIENT"},
+	{domain.ErrUnsupportedGrant,          http.StatusBadRequest,            "UNSUPPORTED_GRANT"},
+	{domain.ErrInvalidCodeChallenge,      http.StatusBadRequest,            "INVALID_CODE_CHALLENGE"},
+	{domain.ErrInsufficientScope,         http.StatusForbidden,             "INSUFFICIENT_SCOPE"},
+	{domain.ErrOrganizationNotFound,      http.StatusNotFound,              "ORGANIZATION_NOT_FOUND"},
+	{domain.ErrInvalidOrganizationID,     http.StatusBadRequest,            "INVALID_ORGANIZATION_ID"},
+	{domain.ErrNotOrganizationOwner,      http.StatusForbidden,             "NOT_ORGANIZATION_OWNER"},
+	{domain.ErrInvalidOrgInvite,          http.StatusForbidden,             "INVALID_ORG_INVITE"},
+	{domain.ErrAlreadyInOrganization,     http.StatusConflict,              "ALREADY_IN_ORGANIZATION"},
+	{domain.ErrAdminCannotJoin,           http.StatusConflict,              "ADMIN_CANNOT_JOIN"},
+	{domain.ErrInvalidResetToken,         http.StatusBadRequest,            "INVALID_RESET_TOKEN"},
+	{domain.ErrTokenNotFound,             http.StatusNotFound,              "TOKEN_NOT_FOUND"},
+	{domain.ErrInvalidTokenID,            http.StatusBadRequest,            "INVALID_TOKEN_ID"},
+	{domain.ErrTokenExpired,              http.StatusUnauthorized,          "TOKEN_EXPIRED"},
+	{domain.ErrTokenRevoked,              http.StatusUnauthorized,          "TOKEN_REVOKED"},
+	{domain.ErrProjectNotFound,           http.StatusNotFound,              "PROJECT_NOT_FOUND"},
+	{domain.ErrInvalidProjectID,          http.StatusBadRequest,            "INVALID_PROJECT_ID"},
+	{domain.ErrProjectAccessDenied,       http.StatusForbidden,             "PROJECT_ACCESS_DENIED"},
+	{domain.ErrProjectHasTasks,           http.StatusConflict,              "PROJECT_HAS_TASKS"},
+	{domain.ErrLastProjectOwner,          http.StatusConflict,              "LAST_PROJECT_OWNER"},
+	{domain.ErrProjectMemberNotFound,     http.StatusNotFound,              "PROJECT_MEMBER_NOT_FOUND"},
+	{domain.ErrTooManyTasksToClone,       http.StatusConflict,              "TOO_MANY_TASKS_TO_CLONE"},
+	{domain.ErrQueryLogWindow,            http.StatusBadRequest,            "QUERY_LOG_WINDOW"},
+	{domain.ErrInvalidRecurrence,         http.StatusBadRequest,            "INVALID_RECURRENCE"},
+	{domain.ErrRecurrenceNotFound,        http.StatusConflict,              "RECURRENCE_NOT_FOUND"},
+	{domain.ErrReportForbidden,           http.StatusForbidden,             "REPORT_FORBIDDEN"},
+	{domain.ErrSessionNotFound,           http.StatusNotFound,              "SESSION_NOT_FOUND"},
+	{domain.ErrInvalidSessionID,          http.StatusBadRequest,            "INVALID_SESSION_ID"},
+	{domain.ErrSessionRevoked,            http.StatusUnauthorized,          "SESSION_REVOKED"},
+	{domain.ErrSettingsNotFound,          http.StatusNotFound,              "SETTINGS_NOT_FOUND"},
+	{domain.ErrSMTPTestFailed,            http.StatusUnprocessableEntity,   "SMTP_TEST_FAILED"},
+	{domain.ErrTagJobNotFound,            http.StatusNotFound,              "TAG_JOB_NOT_FOUND"},
+	{domain.ErrInvalidTagJobID,           http.StatusBadRequest,            "INVALID_TAG_JOB_ID"},
+	{domain.ErrSameTag,                   http.StatusBadRequest,            "SAME_TAG"},
+	{domain.ErrArchivedTaskNotFound,      http.StatusNotFound,              "ARCHIVED_TASK_NOT_FOUND"},
+	{domain.ErrDeletedTaskNotFound,       http.StatusNotFound,              "DELETED_TASK_NOT_FOUND"},
+	{domain.ErrDependencyExists,          http.StatusConflict,              "DEPENDENCY_EXISTS"},
+	{domain.ErrDependencyNotFound,        http.StatusNotFound,              "DEPENDENCY_NOT_FOUND"},
+	{domain.ErrDependencyCycle,           http.StatusConflict,              "DEPENDENCY_CYCLE"},
+	{domain.ErrTaskBlocked,               http.StatusConflict,              "TASK_BLOCKED"},
+	{domain.ErrInvalidEventID,            http.StatusBadRequest,            "INVALID_EVENT_ID"},
+	{domain.ErrTaskHistoryDisabled,       http.StatusNotImplemented,        "TASK_HISTORY_DISABLED"},
+	{domain.ErrMemoryStorage,             http.StatusNotImplemented,        "MEMORY_STORAGE"},
+	{domain.ErrTooManyImportRows,         http.StatusRequestEntityTooLarge, "TOO_MANY_IMPORT_ROWS"},
+	{domain.ErrChecklistItemNotFound,     http.StatusNotFound,              "CHECKLIST_ITEM_NOT_FOUND"},
+	{domain.ErrInvalidChecklistOrder,     http.StatusBadRequest,            "INVALID_CHECKLIST_ORDER"},
+	{domain.ErrTemplateNotFound,          http.StatusNotFound,              "TEMPLATE_NOT_FOUND"},
+	{domain.ErrInvalidTemplateID,         http.StatusBadRequest,            "INVALID_TEMPLATE_ID"},
+	{domain.ErrTaskLocked,                http.StatusLocked,                "TASK_LOCKED"},
+	{domain.ErrTaskLockNotFound,          http.StatusNotFound,              "TASK_LOCK_NOT_FOUND"},
+	{domain.ErrTaskLockNotHeld,           http.StatusConflict,              "TASK_LOCK_NOT_HELD"},
+	{domain.ErrTaskPurgeUnfiltered,       http.StatusBadRequest,            "TASK_PURGE_UNFILTERED"},
+	{domain.ErrInvalidTaskWorkflow,       http.StatusBadRequest,            "INVALID_TASK_WORKFLOW"},
+	{domain.ErrInvalidTransition,         http.StatusUnprocessableEntity,   "INVALID_TRANSITION"},
+	{domain.ErrTwoFactorEnabled,          http.StatusConflict,              "TWO_FACTOR_ENABLED"},
+	{domain.ErrTwoFactorNotSetUp,         http.StatusConflict,              "TWO_FACTOR_NOT_SET_UP"},
+	{domain.ErrTwoFactorNotEnabled,       http.StatusConflict,              "TWO_FACTOR_NOT_ENABLED"},
+	{domain.ErrInvalidTwoFactorCode,      http.StatusUnauthorized,          "INVALID_TWO_FACTOR_CODE"},
+	{domain.ErrInvalidTwoFactorChallenge, http.StatusUnauthorized,          "INVALID_TWO_FACTOR_CHALLENGE"},
+	{domain.ErrAccountDeactivated,        http.StatusForbidden,             "ACCOUNT_DEACTIVATED"},
+	{domain.ErrLastAdmin,                 http.StatusConflict,              "LAST_ADMIN"},
+	{domain.ErrManageSelf,                http.StatusForbidden,             "MANAGE_SELF"},
+	{domain.ErrUserNotAdmin,              http.StatusConflict,              "USER_NOT_ADMIN"},
+	{domain.ErrInvalidUserQuery,          http.StatusBadRequest,            "INVALID_USER_QUERY"},
+	{domain.ErrValidation,                http.StatusUnprocessableEntity,   "VALIDATION_FAILED"},
+	{domain.ErrWebhookNotFound,           http.StatusNotFound,              "WEBHOOK_NOT_FOUND"},
+	{domain.ErrInvalidWebhookID,          http.StatusBadRequest,            "INVALID_WEBHOOK_ID"},
+	{domain.ErrInvalidWebhook,            http.StatusBadRequest,            "INVALID_WEBHOOK"},
+	{domain.ErrTimerRunning,              http.StatusConflict,              "TIMER_RUNNING"},
+	{domain.ErrTimerNotRunning,           http.StatusConflict,              "TIMER_NOT_RUNNING"},
+	{domain.ErrWorklogNotFound,           http.StatusNotFound,              "WORKLOG_NOT_FOUND"},
+	{domain.ErrInvalidWorklogID,          http.StatusBadRequest,            "INVALID_WORKLOG_ID"},
+	{domain.ErrUnsupportedBundle,         http.StatusBadRequest,            "UNSUPPORTED_BUNDLE"},
+	{domain.ErrWorkspaceOwnerNeeded,      http.StatusConflict,              "WORKSPACE_OWNER_NEEDED"},
+	{domain.ErrWorkspaceUserConflict,     http.StatusConflict,              "WORKSPACE_USER_CONFLICT"},
+}
+
+// registered errors by identity, wrapped errors are matched with errors.Is
+var registry = func() map[error]mapping {
+	byErr := make(map[error]mapping, len(mappings))
+	for _, entry := range mappings {
+		byErr[entry.err] = entry
+	}
+	return byErr
+}()
@@ -0,0 +1,218 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"encoding/json";
+	"fmt";
+	"net";
+	"net/http";
+	"os";
+	"sync";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// fans events out to every configured sink after filling common fields
+type auditDispatcher struct {
+	sinks []domain.AuditSink
+}
+
+// create audit sink from configuration (no sinks configured means events are dropped)
+func NewAuditSink(config *Config, logger domain.Logger) domain.AuditSink {
+
+	dispatcher := &auditDispatcher{}
+	for _, name := range config.AuditSinks {
+		switch name {
+		case "syslog":
+			dispatcher.sinks = append(dispatcher.sinks, NewSyslogAuditSink(config.AuditSyslogNetwork, config.AuditSyslogAddr, config.AuditSyslogTag, logger))
+		case "file":
+			sink, err := NewFileAuditSink(config.AuditFilePath, logger)
+			if err != nil {
+				logger.Warn(context.Background(), "audit file sink disabled", "error", err)
+				continue
+			}
+			dispatcher.sinks = append(dispatcher.sinks, sink)
+		case "http":
+			dispatcher.sinks = append(dispatcher.sinks, NewHTTPAuditSink(config.AuditHTTPURL, config.AuditHTTPToken, logger))
+		default:
+			logger.Warn(context.Background(), "unknown audit sink ignored", "sink", name)
+		}
+	}
+
+	return dispatcher
+}
+
+func (dispatcher *auditDispatcher) Emit(ctx context.Context, event domain.AuditEvent) {
+
+	// fill fields every sink needs
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.ClientIP == "" {
+		event.ClientIP = domain.ClientIPFromContext(ctx)
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok && event.ActorID == "" {
+		event.ActorID = actor.ID
+		event.Actor = actor.Username
+	}
+
+	for _, sink := range dispatcher.sinks {
+		sink.Emit(ctx, event)
+	}
+}
+
+// syslog sink (rfc 5424 messages with json payload)
+type syslogAuditSink struct {
+	network  string
+	addr     string
+	tag      string
+	hostname string
+	logger   domain.Logger
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+func NewSyslogAuditSink(network, addr, tag string, logger domain.Logger) domain.AuditSink {
+	hostname, _ := os.Hostname()
+	return &syslogAuditSink{network: network, addr: addr, tag: tag, hostname: hostname, logger: logger}
+}
+
+func (syslogSink *syslogAuditSink) Emit(ctx context.Context, event domain.AuditEvent) {
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		syslogSink.logger.Error(ctx, "audit syslog marshal failed", "error", err)
+		return
+	}
+
+	// facility authpriv (10), severity notice (5) or warning (4) for failures
+	priority := 10*8 + 5
+	if event.Outcome == domain.AuditOutcomeFailure {
+		priority = 10*8 + 4
+	}
+	message := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s\n",
+		priority, event.Timestamp.Format(time.RFC3339Nano), syslogSink.hostname, syslogSink.tag, os.Getpid(), event.Type, payload)
+
+	syslogSink.mu.Lock()
+	defer syslogSink.mu.Unlock()
+
+	// connect lazily and reconnect after failures
+	if syslogSink.conn == nil {
+		conn, err := net.DialTimeout(syslogSink.network, syslogSink.addr, 2*time.Second)
+		if err != nil {
+			syslogSink.logger.Error(ctx, "audit syslog connect failed", "error", err)
+			return
+		}
+		syslogSink.conn = conn
+	}
+	syslogSink.conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	if _, err := syslogSink.conn.Write([]byte(message)); err != nil {
+		syslogSink.logger.Error(ctx, "audit syslog write failed", "error", err)
+		syslogSink.conn.Close()
+		syslogSink.conn = nil
+	}
+}
+
+// json lines file sink (one event per line)
+type fileAuditSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	logger domain.Logger
+}
+
+func NewFileAuditSink(path string, logger domain.Logger) (domain.AuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileAuditSink{file: file, logger: logger}, nil
+}
+
+func (fileSink *fileAuditSink) Emit(ctx context.Context, event domain.AuditEvent) {
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		fileSink.logger.Error(ctx, "audit file marshal failed", "error", err)
+		return
+	}
+
+	fileSink.mu.Lock()
+	defer fileSink.mu.Unlock()
+
+	if _, err := fileSink.file.Write(append(line, '\n')); err != nil {
+		fileSink.logger.Error(ctx, "audit file write failed", "error", err)
+	}
+}
+
+// http collector sink (events are posted in the background so requests don't wait)
+type httpAuditSink struct {
+	url     string
+	token   string
+	client  *http.Client
+	events  chan domain.AuditEvent
+	logger  domain.Logger
+}
+
+func NewHTTPAuditSink(url, token string, logger domain.Logger) domain.AuditSink {
+	httpSink := &httpAuditSink{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 5 * time.Second},
+		events: make(chan domain.AuditEvent, 1000),
+		logger: logger,
+	}
+	go httpSink.run()
+	return httpSink
+}
+
+func (httpSink *httpAuditSink) Emit(ctx context.Context, event domain.AuditEvent) {
+	select {
+	case httpSink.events <- event:
+	default:
+		httpSink.logger.Warn(ctx, "audit http sink queue full, event dropped", "event_type", event.Type)
+	}
+}
+
+// deliver queued events one by one
+func (httpSink *httpAuditSink) run() {
+	ctx := context.Background()
+	for event := range httpSink.events {
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			httpSink.logger.Error(ctx, "audit http marshal failed", "error", err)
+			continue
+		}
+
+		req, err := http.NewRequest(http.MethodPost, httpSink.url, bytes.NewReader(payload))
+		if err != nil {
+			httpSink.logger.Error(ctx, "audit http request failed", "error", err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if httpSink.token != "" {
+			req.Header.Set("Authorization", "Bearer "+httpSink.token)
+		}
+
+		resp, err := httpSink.client.Do(req)
+		if err != nil {
+			httpSink.logger.Error(ctx, "audit http delivery failed", "error", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			httpSink.logger.Error(ctx, "audit http collector rejected event", "status", resp.StatusCode)
+		}
+	}
+}
+
+// audit context handler (makes the client ip available to usecases)
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.ContextWithClientIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
@@ -2,18 +2,131 @@ package infrastructure
 
 // imports
 import (
+	"context";
 	"net/http";
+	"strings";
 	"github.com/dgrijalva/jwt-go";
 	"github.com/gin-gonic/gin";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
 )
 
 type AuthMiddleWare struct {
 	jwtService domain.JWTService
+	patAuth    domain.PersonalAccessTokenAuthenticator
+	sessions   domain.SessionValidator
+	roles      domain.RoleLookup        // role changes apply to tokens issued before them
+	auditSink  domain.AuditSink
 }
 
-func NewAuthMiddleware(jwtServ domain.JWTService) *AuthMiddleWare {
-	return &AuthMiddleWare{jwtService: jwtServ}
+func NewAuthMiddleware(jwtServ domain.JWTService, patAuth domain.PersonalAccessTokenAuthenticator, sessions domain.SessionValidator, roles domain.RoleLookup, auditSink domain.AuditSink) *AuthMiddleWare {
+	return &AuthMiddleWare{jwtService: jwtServ, patAuth: patAuth, sessions: sessions, roles: roles, auditSink: auditSink}
+}
+
+// token query handler
+// browsers can't set headers on websocket handshakes, so the token may come as ?access_token=...
+// it is moved into the authorization header and dropped from the url before anything logs it
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if token := query.Get("access_token"); token != "" {
+			if c.GetHeader("Authorization") == "" {
+				c.Request.Header.Set("Authorization", token)
+			}
+			query.Del("access_token")
+			c.Request.URL.RawQuery = query.Encode()
+		}
+		c.Next()
+	}
+}
+
+// authenticated caller of a request (shared by the http and grpc delivery layers)
+type Principal struct {
+	UserID       string
+	Username     string
+	Role         string
+	TenantID     string                     // organization of the user (empty for the default workspace)
+	Permissions  []domain.Permission        // permissions granted to the token
+	Scopes       []string                   // scopes of third-party and personal access tokens
+	Scoped       bool                       // token is limited to its scopes
+	ClientID     string                     // oauth client of third-party tokens
+	TokenID      string                     // personal access token used for the request
+	SessionID    string                     // login session of the token (empty for tokens without one)
+}
+
+// authenticate a raw or bearer token, failures are audited and returned as ErrUnauthorized or ErrSessionRevoked
+// (any other error is a server error), path is recorded in the audit event
+func (authmidlw *AuthMiddleWare) Authenticate(ctx context.Context, tokenStr string, path string) (*Principal, error) {
+
+	tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")       // accept both raw and bearer tokens
+
+	// personal access tokens are looked up instead of parsed
+	if strings.HasPrefix(tokenStr, domain.PersonalAccessTokenPrefix) {
+		pat, user, err := authmidlw.patAuth.Authenticate(ctx, tokenStr)
+		if err != nil {
+			authmidlw.auditRejected(ctx, path, "personal_access_token", err)
+			return nil, domain.ErrUnauthorized
+		}
+		return &Principal{
+			UserID:       user.ID.Hex(),
+			Username:     user.Username,
+			Role:         user.Role,
+			TenantID:     user.TenantID,                             // owner's current organization
+			Permissions:  domain.PermissionsForRole(user.Role),      // permissions of the owner's current role
+			Scopes:       pat.Scopes,
+			Scoped:       true,
+			TokenID:      pat.ID.Hex(),
+		}, nil
+	}
+
+	// validate token structure/signature with error handling
+	token, err := authmidlw.jwtService.ValidateToken(tokenStr)
+	if err != nil || !token.Valid {
+		authmidlw.auditRejected(ctx, path, "jwt", err)
+		return nil, domain.ErrUnauthorized
+	}
+
+	principal := &Principal{}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		authmidlw.auditRejected(ctx, path, "jwt", domain.ErrUnauthorized)
+		return nil, domain.ErrUnauthorized
+	}
+
+	// login tokens stop working once their session is revoked (tokens from before sessions carry no sid)
+	if sessionID, hasSession := claims["sid"].(string); hasSession {
+		if err := authmidlw.sessions.ValidateSession(ctx, sessionID); err != nil {
+			if err == domain.ErrSessionRevoked {
+				authmidlw.auditRejected(ctx, path, "session", err)
+			}
+			return nil, err
+		}
+		principal.SessionID = sessionID
+	}
+
+	principal.UserID, _ = claims["userId"].(string)
+	principal.Username, _ = claims["username"].(string)
+	principal.TenantID, _ = claims["tenant_id"].(string)
+
+	// the role and permissions claims are those at login, a promotion or demotion since then counts
+	role, err := authmidlw.roles.CurrentRole(ctx, principal.UserID)
+	if err == domain.ErrUserNotFound || err == domain.ErrInvalidUserID || err == domain.ErrAccountDeactivated {
+		authmidlw.auditRejected(ctx, path, "jwt", err)
+		return nil, domain.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	principal.Role = role
+	principal.Permissions = domain.PermissionsForRole(role)
+
+	// third-party tokens carry the scopes the user consented to
+	if scope, scoped := claims["scope"].(string); scoped {
+		principal.Scopes, principal.Scoped = strings.Fields(scope), true
+		principal.ClientID, _ = claims["client_id"].(string)
+	}
+
+	return principal, nil
 }
 
 // auth handler
@@ -23,47 +136,131 @@ func (authmidlw *AuthMiddleWare) Handler() gin.HandlerFunc {
 
 		tokenStr := c.GetHeader("Authorization")        // get token from authorization header
 		// reject if empty
-		if tokenStr == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			c.Abort()
+		if strings.TrimPrefix(tokenStr, "Bearer ") == "" {
+			apierror.Message(c, http.StatusUnauthorized, "authorization header required")
 			return
 		}
-		
-		// validate token structure/signature with error handling 
-		token, err := authmidlw.jwtService.ValidateToken(tokenStr)     
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
+
+		principal, err := authmidlw.Authenticate(c.Request.Context(), tokenStr, c.Request.URL.Path)
+		switch err {
+		case nil:
+		case domain.ErrUnauthorized:
+			apierror.Message(c, http.StatusUnauthorized, "invalid token")
+			return
+		case domain.ErrSessionRevoked:
+			apierror.Respond(c, http.StatusUnauthorized, err)
+			return
+		default:
+			apierror.Abort(c, err)
 			return
 		}
 
-		// if token is valid, extract claims and store in request context
-		claims, ok := token.Claims.(jwt.MapClaims)      
-		if ok {
-			c.Set("userID", claims["sub"])             // user id
-			c.Set("username", claims["username"])      // username 
-			c.Set("role", claims["role"])              // user role (admin/user)
+		c.Set("userID", principal.UserID)          // user id
+		c.Set("username", principal.Username)      // username
+		c.Set("role", principal.Role)              // user role (admin/user)
+		c.Set("permissions", principal.Permissions)      // permissions granted to the token
+		if principal.Scoped {
+			c.Set("scopes", principal.Scopes)      // scopes granted to the token
+		}
+		if principal.TokenID != "" {
+			c.Set("tokenID", principal.TokenID)    // token used for this request
 		}
+		if principal.ClientID != "" {
+			c.Set("clientID", principal.ClientID)
+		}
+
+		// make the actor available to usecases for auditing
+		c.Request = c.Request.WithContext(domain.ContextWithActor(c.Request.Context(), domain.Actor{ID: principal.UserID, Username: principal.Username, Role: principal.Role, TenantID: principal.TenantID, SessionID: principal.SessionID}))
 
 		c.Next()       // proceed to next handler
 	}
 }
 
-func AdminOnly() gin.HandlerFunc {
+// record rejected token
+func (authmidlw *AuthMiddleWare) auditRejected(ctx context.Context, path string, kind string, err error) {
+	reason := "invalid token"
+	if err != nil {
+		reason = err.Error()
+	}
+	authmidlw.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditTokenRejected,
+		Outcome:  domain.AuditOutcomeFailure,
+		Details:  map[string]string{"token_type": kind, "reason": reason, "path": path},
+	})
+}
+
+// require permission handler
+func RequirePermission(permission domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		permissions, _ := c.Get("permissions")        // set by the auth handler
+		granted, _ := permissions.([]domain.Permission)
+
+		// block if permission isn't granted
+		if !domain.HasPermission(granted, permission) {
+			apierror.MessageWith(c, http.StatusForbidden, "permission denied", gin.H{"required_permission": permission})
+			return
+		}
+
+		c.Next()       // permission granted
+	}
+}
+
+// check permission and scope of the current request without blocking it (used to decide which actions to offer)
+func HasAccess(c *gin.Context, permission domain.Permission, scope string) bool {
+
+	permissions, _ := c.Get("permissions")        // set by the auth handler
+	granted, _ := permissions.([]domain.Permission)
+	if !domain.HasPermission(granted, permission) {
+		return false
+	}
+
+	scopes, scoped := c.Get("scopes")       // only set for third-party tokens
+	if !scoped {
+		return true
+	}
+	for _, s := range scopes.([]string) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// require scope handler (first-party tokens without scopes have full access)
+func RequireScope(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		
-		role, exists := c.Get("role")          // get role from context 
-
-		// block if either role doesn't exist in context or role isn't "admin"
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "admin access required",
-			})
-			
-			c.Abort()
+
+		scopes, scoped := c.Get("scopes")       // only set for third-party tokens
+		if scoped {
+			granted := false
+			for _, s := range scopes.([]string) {
+				if s == scope {
+					granted = true
+					break
+				}
+			}
+			// block if required scope wasn't granted
+			if !granted {
+				apierror.RespondWith(c, http.StatusForbidden, domain.ErrInsufficientScope, gin.H{"required_scope": scope})
+				return
+			}
+		}
+
+		c.Next()       // scope granted or token is first-party
+	}
+}
+
+// first party only handler (blocks scoped third-party tokens)
+func FirstPartyOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		_, scoped := c.Get("scopes")
+		if scoped {
+			apierror.Message(c, http.StatusForbidden, "endpoint not available to third-party tokens")
 			return
 		}
 
-		c.Next()       // allow admin to proceed
+		c.Next()       // allow first-party tokens
 	}
 }
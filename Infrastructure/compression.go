@@ -0,0 +1,151 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"compress/gzip";
+	"strconv";
+	"strings";
+	"sync";
+	"github.com/gin-gonic/gin";
+)
+
+// content types worth compressing (images, archives, ... are already compressed)
+var compressibleTypes = []string{"application/json", "application/problem+json", "application/x-ndjson", "application/xml", "application/yaml", "text/"}
+
+// gzip writers are reused between responses
+var gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+
+// compresses the body once enough of it was written to be worth it
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize     int
+	pending     bytes.Buffer       // start of the body until compression is decided
+	decided     bool
+	compressor  *gzip.Writer       // nil when the body goes out as is
+}
+
+func (writer *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !writer.decided {
+		writer.pending.Write(data)
+		if writer.pending.Len() < writer.minSize {
+			return len(data), nil
+		}
+		if err := writer.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if writer.compressor != nil {
+		return writer.compressor.Write(data)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *gzipResponseWriter) WriteString(data string) (int, error) {
+	return writer.Write([]byte(data))
+}
+
+// streamed responses (exports, ...) go out as they are flushed
+func (writer *gzipResponseWriter) Flush() {
+	if !writer.decided {
+		writer.decide(true)
+	}
+	if writer.compressor != nil {
+		writer.compressor.Flush()
+	}
+	writer.ResponseWriter.Flush()
+}
+
+// choose between compressed and plain output and send what was held back
+func (writer *gzipResponseWriter) decide(bigEnough bool) error {
+
+	writer.decided = true
+	header := writer.Header()
+	if bigEnough && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		compressor := gzipWriters.Get().(*gzip.Writer)
+		compressor.Reset(writer.ResponseWriter)
+		writer.compressor = compressor
+	}
+	if writer.pending.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if writer.compressor != nil {
+		_, err = writer.compressor.Write(writer.pending.Bytes())
+	} else {
+		_, err = writer.ResponseWriter.Write(writer.pending.Bytes())
+	}
+	writer.pending.Reset()
+	return err
+}
+
+// send the rest of the body (small bodies go out uncompressed)
+func (writer *gzipResponseWriter) finish() {
+	if !writer.decided {
+		writer.decide(false)
+	}
+	if writer.compressor != nil {
+		writer.compressor.Close()
+		gzipWriters.Put(writer.compressor)
+		writer.compressor = nil
+	}
+}
+
+// check if a content type is worth compressing
+func compressible(contentType string) bool {
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// check if the client takes gzip bodies (Accept-Encoding: gzip, deflate;q=0.5)
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if coding = strings.ToLower(strings.TrimSpace(coding)); coding != "gzip" && coding != "*" {
+			continue
+		}
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if quality, err := strconv.ParseFloat(value, 64); err == nil && quality == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// response compression handler
+// bodies of at least minSize bytes are gzipped for clients sending Accept-Encoding: gzip (0 disables)
+func Compression(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if minSize <= 0 {
+			c.Next()
+			return
+		}
+		c.Header("Vary", "Accept-Encoding")        // caches keep compressed and plain copies apart
+
+		// websocket upgrades and head requests have no body to compress
+		if c.Request.Method == "HEAD" || c.GetHeader("Upgrade") != "" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		compressed := &gzipResponseWriter{ResponseWriter: original, minSize: minSize}
+		c.Writer = compressed
+
+		c.Next()
+
+		compressed.finish()
+		c.Writer = original
+	}
+}
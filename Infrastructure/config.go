@@ -0,0 +1,389 @@
+package infrastructure
+
+// imports
+import (
+	"log";
+	"path/filepath";
+	"runtime";
+	"strings";
+	"sync";
+	"time";
+	"github.com/spf13/viper";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// application configuration read from .env or environment variables
+type Config struct {
+	MongoURI            string        // mongodb connection string
+	RedisURL            string        // redis caching task reads (no caching when empty)
+	SearchBackend       string        // full-text task search (mongo/elasticsearch)
+	ElasticsearchURL    string        // elasticsearch server url
+	ElasticsearchIndex  string        // elasticsearch index holding tasks
+	TaskCacheTTL        time.Duration // how long cached task reads are kept
+	Storage             string        // where tasks and users are kept (mongo/memory)
+	ReadOnly            bool          // serve read endpoints only (standby/reporting instances)
+	QueryReadPreference string        // mongodb read preference of task queries (primary/secondaryPreferred/...)
+	TaskPersistence     string        // how tasks are stored (state/events)
+	ResponseCase        string        // default json key case (snake/camel)
+	ResponseEnvelope    bool          // wrap responses in an envelope by default
+	CompressionMinSize  int           // smallest response body sent gzipped to clients accepting it (0 disables compression)
+	MaxRequestBodySize  int64         // largest accepted request body in bytes, imports have their own limits (0 disables the limit)
+	EscapeHTMLDescriptions bool       // html-escape task and project descriptions before they are stored
+	LegacyRoutes        bool          // keep serving the unversioned routes as deprecated aliases of /api/v1
+	LegacySunset        time.Time     // announced removal date of the unversioned routes (none when zero)
+	DeprecatedRoutes    string        // endpoints to announce as deprecated, e.g. "GET /tasks/:id/events sunset=2027-01-31 successor=/tasks/:id/history; ..."
+	GRPCAddr            string        // listen address of the grpc server (not started when empty)
+	GRPCTLSCert         string        // tls certificate of the grpc server (plain http/2 when empty)
+	GRPCTLSKey          string        // tls private key of the grpc server
+	ReadTimeout         time.Duration // time budget of read requests
+	WriteTimeout        time.Duration // time budget of write requests
+	ExportTimeout       time.Duration // time budget of bulk export requests
+	TelemetryEnabled    bool          // send anonymized usage reports (opt-in)
+	TelemetryEndpoint   string        // collector url for usage reports
+	TelemetryInterval   time.Duration // how often to send usage reports
+	TelemetryInstanceID string        // anonymous installation id (derived when empty)
+	BcryptCost          int           // bcrypt cost of password hashes (older hashes are upgraded on login)
+	PasswordHashAlgorithm string      // algorithm of new password hashes (bcrypt/argon2id), hashes of the other one are upgraded on login
+	Argon2Memory        int           // argon2id memory in KiB
+	Argon2Iterations    int           // argon2id passes over the memory
+	Argon2Parallelism   int           // argon2id threads
+	AdminUsername       string        // first admin created on a fresh database (setup token is printed when empty)
+	AdminPassword       string        // password of the first admin
+	AdminEmail          string        // email of the first admin
+	MaxFailedLogins     int           // failed logins before an account is locked (0 disables lockout)
+	MaxSessions         int           // concurrent login sessions per user until set in the workspace settings (0 for no limit)
+	LockoutDuration     time.Duration // how long a locked account stays locked
+	LoginRateLimit      int           // login attempts per minute per client ip (0 disables)
+	LoginRateBurst      int           // login attempts allowed in a burst
+	APIRateLimit        int           // api requests per minute per user or client ip (0 disables)
+	APIRateBurst        int           // api requests allowed in a burst
+	TrustedProxies      []string      // proxy addresses or cidr ranges whose X-Forwarded-For gives the client ip (none by default)
+	UsageFlushInterval  time.Duration // how often per-token api usage counters are written
+	IdempotencyTTL      time.Duration // how long responses of requests with an Idempotency-Key are replayed
+	TaskLockTTL         time.Duration // how long a task edit lock holds without a heartbeat
+	WebhookTimeout      time.Duration // how long a webhook endpoint gets to answer one attempt
+	WebhookMaxAttempts  int           // tries per webhook delivery before it counts as failed
+	WebhookDisableAfterDays int       // consecutive days of failed deliveries before a webhook is disabled (0 never disables)
+	WebhookDeliveryRetention time.Duration // how long webhook deliveries stay in the delivery log
+	TaskEventRetention  time.Duration // how long task events stay in the event log streams resume from
+	EventStreamPollInterval time.Duration // how often event streams read changes stored by other instances
+	EventBroker         string        // external broker receiving domain events (none/nats)
+	NATSURL             string        // nats server url
+	EventSubjectPrefix  string        // prefix of broker subjects (subject is prefix + event type)
+	ExtensionPlugins    []string      // go plugin files providing extensions
+	ExtensionHooks      []string      // http extensions as hook=url pairs
+	LogFormat           string        // log output format (json/text)
+	LogLevel            string        // minimum log level (debug/info/warn/error)
+	QueryLogMaxWindow   time.Duration // longest window admins can log database queries for
+	OverdueInterval     time.Duration // how often to recompute overdue flags
+	RecurrenceInterval  time.Duration // how often to create occurrences of recurring tasks that are due
+	IntegrityCheckInterval time.Duration // how often to scan for broken references and drifted fields (0 disables)
+	ArchiveAfterDays    int           // completed tasks unchanged for this many days move to the archive (0 never archives)
+	IntegrityAutoRepair bool          // the scheduled integrity check repairs what it finds
+	AutoMigrate         bool          // apply pending data migrations at startup (indexes are always created)
+	MigrationTimeout    time.Duration // time budget of index creation and migrations at startup
+	DueDateStrategies   []string      // due date suggestion heuristics in the order they run
+	DueDateMaxPerDay    int           // open tasks due on a day before suggestions move past it
+	WorkDayStart        string        // default start of the working day for day plans (HH:MM)
+	WorkDayEnd          string        // default end of the working day for day plans (HH:MM)
+	DefaultTaskEstimate time.Duration // effort planned for tasks without an estimate
+	TaskTransitions     string        // allowed task status changes, e.g. "pending->in_progress, in_progress->completed"
+	EnforceTaskDependencies bool      // refuse completing a task while one of its blockers is open
+	RemindersEnabled    bool          // run the due date reminder scheduler
+	ReminderInterval    time.Duration // how often to scan for due tasks
+	ReminderWindow      time.Duration // default reminder lead time before the due date
+	Notifier            string        // notification channel (log/email)
+	NotificationEmail   string        // recipient for the email notifier
+	SMTPHost            string        // smtp server host (emails are logged when empty)
+	SMTPPort            int           // smtp server port
+	SMTPUsername        string        // smtp login (no auth when empty)
+	SMTPPassword        string        // smtp password
+	EmailFrom           string        // sender address of outgoing emails
+	PasswordResetTTL    time.Duration // how long a password reset token stays valid
+	PasswordResetURL    string        // link sent in reset emails, the token is appended
+	EmailVerificationTTL time.Duration // how long an email verification link stays valid
+	EmailVerificationURL string       // link sent in verification emails, the token is appended
+	RequireVerifiedEmail bool         // refuse logins until the registration email is verified
+	TwoFactorIssuer     string        // name authenticator apps show next to the account
+	TwoFactorChallengeTTL time.Duration // how long a password login waits for its two-factor code
+	AdminInviteTTL      time.Duration // how long an admin invite stays valid
+	OrgInviteTTL        time.Duration // how long an organization invite stays valid
+	OAuthRedirectURL    string        // public url of the sign-in routes, providers call back to it + "/<provider>/callback"
+	GoogleClientID      string        // google oauth client (google sign-in disabled when empty)
+	GoogleClientSecret  string        // google oauth client secret
+	GitHubClientID      string        // github oauth app (github sign-in disabled when empty)
+	GitHubClientSecret  string        // github oauth app secret
+	OAuthTimeout        time.Duration // time budget of sign-in callbacks (code exchange and account lookups at the provider)
+	AuditSinks          []string      // audit sinks to enable (syslog, file, http)
+	AuditSyslogNetwork  string        // syslog transport (udp/tcp)
+	AuditSyslogAddr     string        // syslog collector address (host:port)
+	AuditSyslogTag      string        // syslog app name
+	AuditFilePath       string        // json lines file path
+	AuditHTTPURL        string        // http collector url
+	AuditHTTPToken      string        // bearer token for the http collector
+	SentryDSN           string        // sentry project dsn, crashes are reported there (only logged when empty)
+	SentryEnvironment   string        // environment name attached to sentry events (e.g. production)
+}
+
+// password hashing algorithms
+const (
+	PasswordHashBcrypt   = "bcrypt"        // bcrypt with BcryptCost (default)
+	PasswordHashArgon2id = "argon2id"      // argon2id with the Argon2 parameters
+)
+
+// storage backends
+const (
+	StorageMongo  = "mongo"        // mongodb collections (default)
+	StorageMemory = "memory"       // process memory, for demos (lost on restart)
+)
+
+var viperOnce sync.Once
+
+// initialize viper once for the whole process
+func initViper() {
+	viperOnce.Do(func() {
+
+		// intialize viper
+		viper.AutomaticEnv()
+
+		_, filename, _, _ := runtime.Caller(0)
+		rootDir := filepath.Dir(filepath.Dir(filename))
+
+		// configure viper
+		viper.SetConfigName(".env")               // set config name
+		viper.SetConfigType("env")                // set config type
+		viper.AddConfigPath(".")                  // current directory
+		viper.AddConfigPath(rootDir)              // project root
+
+		err := viper.ReadInConfig();
+		if err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				log.Printf("error reading config: %v", err)
+			}
+		}
+	})
+}
+
+// re-read the .env file (environment variables are fixed for the life of the process)
+func ReloadConfigFile() error {
+
+	initViper()
+	err := viper.ReadInConfig()
+	if _, notFound := err.(viper.ConfigFileNotFoundError); notFound {
+		return nil
+	}
+	return err
+}
+
+// load application configuration
+func LoadConfig() *Config {
+
+	initViper()
+
+	// defaults
+	viper.SetDefault("MONGO_URI", "mongodb://localhost:27017")
+	viper.SetDefault("READ_ONLY_MODE", false)
+	viper.SetDefault("QUERY_READ_PREFERENCE", "primary")
+	viper.SetDefault("TASK_PERSISTENCE", "state")
+	viper.SetDefault("STORAGE", StorageMongo)
+	viper.SetDefault("TASK_CACHE_TTL", "30s")
+	viper.SetDefault("SEARCH_BACKEND", domain.SearchBackendMongo)
+	viper.SetDefault("ELASTICSEARCH_URL", "http://localhost:9200")
+	viper.SetDefault("ELASTICSEARCH_INDEX", "tasks")
+	viper.SetDefault("RESPONSE_CASE", ResponseCaseSnake)
+	viper.SetDefault("RESPONSE_ENVELOPE", false)
+	viper.SetDefault("COMPRESSION_MIN_SIZE", 1024)
+	viper.SetDefault("MAX_REQUEST_BODY_SIZE", 1<<20)
+	viper.SetDefault("ESCAPE_HTML_DESCRIPTIONS", false)
+	viper.SetDefault("LEGACY_ROUTES", true)
+	viper.SetDefault("READ_TIMEOUT", "2s")
+	viper.SetDefault("WRITE_TIMEOUT", "5s")
+	viper.SetDefault("EXPORT_TIMEOUT", "30s")
+	viper.SetDefault("TELEMETRY_ENABLED", false)
+	viper.SetDefault("TELEMETRY_INTERVAL", "24h")
+	viper.SetDefault("BCRYPT_COST", 10)
+	viper.SetDefault("PASSWORD_HASH_ALGORITHM", PasswordHashBcrypt)
+	viper.SetDefault("ARGON2_MEMORY", 19456)
+	viper.SetDefault("ARGON2_ITERATIONS", 2)
+	viper.SetDefault("ARGON2_PARALLELISM", 1)
+	viper.SetDefault("MAX_FAILED_LOGINS", 5)
+	viper.SetDefault("MAX_SESSIONS", 0)
+	viper.SetDefault("LOCKOUT_DURATION", "15m")
+	viper.SetDefault("LOGIN_RATE_LIMIT", 10)
+	viper.SetDefault("LOGIN_RATE_BURST", 5)
+	viper.SetDefault("API_RATE_LIMIT", 600)
+	viper.SetDefault("API_RATE_BURST", 100)
+	viper.SetDefault("USAGE_FLUSH_INTERVAL", "1m")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("TASK_LOCK_TTL", "2m")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 3)
+	viper.SetDefault("WEBHOOK_DISABLE_AFTER_DAYS", 3)
+	viper.SetDefault("WEBHOOK_DELIVERY_RETENTION", "720h")
+	viper.SetDefault("TASK_EVENT_RETENTION", "24h")
+	viper.SetDefault("EVENT_STREAM_POLL_INTERVAL", "5s")
+	viper.SetDefault("EVENT_BROKER", "none")
+	viper.SetDefault("NATS_URL", "nats://localhost:4222")
+	viper.SetDefault("EVENT_SUBJECT_PREFIX", "taskmanager.")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("QUERY_LOG_MAX_WINDOW", "1h")
+	viper.SetDefault("OVERDUE_INTERVAL", "1m")
+	viper.SetDefault("RECURRENCE_INTERVAL", "1m")
+	viper.SetDefault("INTEGRITY_CHECK_INTERVAL", "24h")
+	viper.SetDefault("ARCHIVE_AFTER_DAYS", 0)
+	viper.SetDefault("INTEGRITY_AUTO_REPAIR", false)
+	viper.SetDefault("AUTO_MIGRATE", true)
+	viper.SetDefault("MIGRATION_TIMEOUT", "10m")
+	viper.SetDefault("DUE_DATE_STRATEGIES", "similar_tasks,user_pace,default,workload")
+	viper.SetDefault("DUE_DATE_MAX_PER_DAY", 5)
+	viper.SetDefault("WORK_DAY_START", "09:00")
+	viper.SetDefault("WORK_DAY_END", "17:00")
+	viper.SetDefault("DEFAULT_TASK_ESTIMATE", "30m")
+	viper.SetDefault("TASK_TRANSITIONS", domain.DefaultTaskTransitions)
+	viper.SetDefault("ENFORCE_TASK_DEPENDENCIES", false)
+	viper.SetDefault("REMINDERS_ENABLED", true)
+	viper.SetDefault("REMINDER_INTERVAL", "1m")
+	viper.SetDefault("REMINDER_WINDOW", "1h")
+	viper.SetDefault("NOTIFIER", "log")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("EMAIL_FROM", "no-reply@localhost")
+	viper.SetDefault("PASSWORD_RESET_TTL", "1h")
+	viper.SetDefault("PASSWORD_RESET_URL", "http://localhost:8080/reset-password?token=")
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "48h")
+	viper.SetDefault("EMAIL_VERIFICATION_URL", "http://localhost:8080/api/v1/auth/verify?token=")
+	viper.SetDefault("REQUIRE_VERIFIED_EMAIL", false)
+	viper.SetDefault("TWO_FACTOR_ISSUER", "Task Manager")
+	viper.SetDefault("TWO_FACTOR_CHALLENGE_TTL", "5m")
+	viper.SetDefault("ADMIN_INVITE_TTL", "72h")
+	viper.SetDefault("ORG_INVITE_TTL", "168h")
+	viper.SetDefault("OAUTH_REDIRECT_URL", "http://localhost:8080/api/v1/auth/oauth")
+	viper.SetDefault("OAUTH_TIMEOUT", "15s")
+	viper.SetDefault("AUDIT_SYSLOG_NETWORK", "udp")
+	viper.SetDefault("AUDIT_SYSLOG_TAG", "task-manager")
+	viper.SetDefault("AUDIT_FILE_PATH", "audit.jsonl")
+
+	return &Config{
+		MongoURI:           viper.GetString("MONGO_URI"),
+		Storage:            viper.GetString("STORAGE"),
+		RedisURL:           viper.GetString("REDIS_URL"),
+		SearchBackend:      viper.GetString("SEARCH_BACKEND"),
+		ElasticsearchURL:   viper.GetString("ELASTICSEARCH_URL"),
+		ElasticsearchIndex: viper.GetString("ELASTICSEARCH_INDEX"),
+		TaskCacheTTL:       viper.GetDuration("TASK_CACHE_TTL"),
+		ReadOnly:           viper.GetBool("READ_ONLY_MODE"),
+		QueryReadPreference: viper.GetString("QUERY_READ_PREFERENCE"),
+		TaskPersistence:    viper.GetString("TASK_PERSISTENCE"),
+		ResponseCase:       viper.GetString("RESPONSE_CASE"),
+		ResponseEnvelope:   viper.GetBool("RESPONSE_ENVELOPE"),
+		CompressionMinSize: viper.GetInt("COMPRESSION_MIN_SIZE"),
+		MaxRequestBodySize: viper.GetInt64("MAX_REQUEST_BODY_SIZE"),
+		EscapeHTMLDescriptions: viper.GetBool("ESCAPE_HTML_DESCRIPTIONS"),
+		LegacyRoutes:       viper.GetBool("LEGACY_ROUTES"),
+		LegacySunset:       viper.GetTime("LEGACY_ROUTES_SUNSET"),
+		DeprecatedRoutes:   viper.GetString("DEPRECATED_ROUTES"),
+		GRPCAddr:           viper.GetString("GRPC_ADDR"),
+		GRPCTLSCert:        viper.GetString("GRPC_TLS_CERT"),
+		GRPCTLSKey:         viper.GetString("GRPC_TLS_KEY"),
+		ReadTimeout:        viper.GetDuration("READ_TIMEOUT"),
+		WriteTimeout:       viper.GetDuration("WRITE_TIMEOUT"),
+		ExportTimeout:      viper.GetDuration("EXPORT_TIMEOUT"),
+		TelemetryEnabled:   viper.GetBool("TELEMETRY_ENABLED"),
+		TelemetryEndpoint:  viper.GetString("TELEMETRY_ENDPOINT"),
+		TelemetryInterval:  viper.GetDuration("TELEMETRY_INTERVAL"),
+		TelemetryInstanceID: viper.GetString("TELEMETRY_INSTANCE_ID"),
+		BcryptCost:         viper.GetInt("BCRYPT_COST"),
+		PasswordHashAlgorithm: viper.GetString("PASSWORD_HASH_ALGORITHM"),
+		Argon2Memory:       viper.GetInt("ARGON2_MEMORY"),
+		Argon2Iterations:   viper.GetInt("ARGON2_ITERATIONS"),
+		Argon2Parallelism:  viper.GetInt("ARGON2_PARALLELISM"),
+		AdminUsername:      viper.GetString("ADMIN_USERNAME"),
+		AdminPassword:      viper.GetString("ADMIN_PASSWORD"),
+		AdminEmail:         viper.GetString("ADMIN_EMAIL"),
+		MaxFailedLogins:    viper.GetInt("MAX_FAILED_LOGINS"),
+		MaxSessions:        viper.GetInt("MAX_SESSIONS"),
+		LockoutDuration:    viper.GetDuration("LOCKOUT_DURATION"),
+		LoginRateLimit:     viper.GetInt("LOGIN_RATE_LIMIT"),
+		LoginRateBurst:     viper.GetInt("LOGIN_RATE_BURST"),
+		APIRateLimit:       viper.GetInt("API_RATE_LIMIT"),
+		TrustedProxies:     splitList(viper.GetString("TRUSTED_PROXIES")),
+		APIRateBurst:       viper.GetInt("API_RATE_BURST"),
+		UsageFlushInterval: viper.GetDuration("USAGE_FLUSH_INTERVAL"),
+		IdempotencyTTL:     viper.GetDuration("IDEMPOTENCY_TTL"),
+		TaskLockTTL:        viper.GetDuration("TASK_LOCK_TTL"),
+		WebhookTimeout:     viper.GetDuration("WEBHOOK_TIMEOUT"),
+		WebhookMaxAttempts: viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookDisableAfterDays: viper.GetInt("WEBHOOK_DISABLE_AFTER_DAYS"),
+		WebhookDeliveryRetention: viper.GetDuration("WEBHOOK_DELIVERY_RETENTION"),
+		TaskEventRetention: viper.GetDuration("TASK_EVENT_RETENTION"),
+		EventStreamPollInterval: viper.GetDuration("EVENT_STREAM_POLL_INTERVAL"),
+		EventBroker:        viper.GetString("EVENT_BROKER"),
+		NATSURL:            viper.GetString("NATS_URL"),
+		EventSubjectPrefix: viper.GetString("EVENT_SUBJECT_PREFIX"),
+		ExtensionPlugins:   splitList(viper.GetString("EXTENSION_PLUGINS")),
+		ExtensionHooks:     splitList(viper.GetString("EXTENSION_HOOKS")),
+		LogFormat:          viper.GetString("LOG_FORMAT"),
+		LogLevel:           viper.GetString("LOG_LEVEL"),
+		QueryLogMaxWindow:  viper.GetDuration("QUERY_LOG_MAX_WINDOW"),
+		OverdueInterval:    viper.GetDuration("OVERDUE_INTERVAL"),
+		RecurrenceInterval: viper.GetDuration("RECURRENCE_INTERVAL"),
+		IntegrityCheckInterval: viper.GetDuration("INTEGRITY_CHECK_INTERVAL"),
+		ArchiveAfterDays:   viper.GetInt("ARCHIVE_AFTER_DAYS"),
+		IntegrityAutoRepair: viper.GetBool("INTEGRITY_AUTO_REPAIR"),
+		AutoMigrate:        viper.GetBool("AUTO_MIGRATE"),
+		MigrationTimeout:   viper.GetDuration("MIGRATION_TIMEOUT"),
+		DueDateStrategies:  splitList(viper.GetString("DUE_DATE_STRATEGIES")),
+		DueDateMaxPerDay:   viper.GetInt("DUE_DATE_MAX_PER_DAY"),
+		WorkDayStart:       viper.GetString("WORK_DAY_START"),
+		WorkDayEnd:         viper.GetString("WORK_DAY_END"),
+		DefaultTaskEstimate: viper.GetDuration("DEFAULT_TASK_ESTIMATE"),
+		TaskTransitions:    viper.GetString("TASK_TRANSITIONS"),
+		EnforceTaskDependencies: viper.GetBool("ENFORCE_TASK_DEPENDENCIES"),
+		RemindersEnabled:   viper.GetBool("REMINDERS_ENABLED"),
+		ReminderInterval:   viper.GetDuration("REMINDER_INTERVAL"),
+		ReminderWindow:     viper.GetDuration("REMINDER_WINDOW"),
+		Notifier:           viper.GetString("NOTIFIER"),
+		NotificationEmail:  viper.GetString("NOTIFICATION_EMAIL"),
+		SMTPHost:           viper.GetString("SMTP_HOST"),
+		SMTPPort:           viper.GetInt("SMTP_PORT"),
+		SMTPUsername:       viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:       viper.GetString("SMTP_PASSWORD"),
+		EmailFrom:          viper.GetString("EMAIL_FROM"),
+		PasswordResetTTL:   viper.GetDuration("PASSWORD_RESET_TTL"),
+		PasswordResetURL:   viper.GetString("PASSWORD_RESET_URL"),
+		EmailVerificationTTL: viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+		EmailVerificationURL: viper.GetString("EMAIL_VERIFICATION_URL"),
+		RequireVerifiedEmail: viper.GetBool("REQUIRE_VERIFIED_EMAIL"),
+		TwoFactorIssuer: viper.GetString("TWO_FACTOR_ISSUER"),
+		TwoFactorChallengeTTL: viper.GetDuration("TWO_FACTOR_CHALLENGE_TTL"),
+		AdminInviteTTL:     viper.GetDuration("ADMIN_INVITE_TTL"),
+		OrgInviteTTL:       viper.GetDuration("ORG_INVITE_TTL"),
+		OAuthRedirectURL:   viper.GetString("OAUTH_REDIRECT_URL"),
+		GoogleClientID:     viper.GetString("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret: viper.GetString("GOOGLE_CLIENT_SECRET"),
+		GitHubClientID:     viper.GetString("GITHUB_CLIENT_ID"),
+		GitHubClientSecret: viper.GetString("GITHUB_CLIENT_SECRET"),
+		OAuthTimeout:       viper.GetDuration("OAUTH_TIMEOUT"),
+		AuditSinks:         splitList(viper.GetString("AUDIT_SINKS")),
+		AuditSyslogNetwork: viper.GetString("AUDIT_SYSLOG_NETWORK"),
+		AuditSyslogAddr:    viper.GetString("AUDIT_SYSLOG_ADDR"),
+		AuditSyslogTag:     viper.GetString("AUDIT_SYSLOG_TAG"),
+		AuditFilePath:      viper.GetString("AUDIT_FILE_PATH"),
+		AuditHTTPURL:       viper.GetString("AUDIT_HTTP_URL"),
+		AuditHTTPToken:     viper.GetString("AUDIT_HTTP_TOKEN"),
+		SentryDSN:          viper.GetString("SENTRY_DSN"),
+		SentryEnvironment:  viper.GetString("SENTRY_ENVIRONMENT"),
+	}
+}
+
+// split comma separated config value into trimmed items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+)
+
+// time budget of each request
+type DeadlineBudgets struct {
+	Read    time.Duration                 // GET requests
+	Write   time.Duration                 // POST, PUT and DELETE requests
+	Routes  map[string]time.Duration      // overrides by "METHOD /route/:param" (exports, ...)
+}
+
+// deadline handler
+// sets the request's context deadline from its budget, repositories derive their queries from it
+func DeadlineBudget(budgets DeadlineBudgets) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		budget, ok := budgets.Routes[c.Request.Method+" "+RoutePath(c)]
+		if !ok {
+			budget = budgets.Write
+			if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+				budget = budgets.Read
+			}
+		}
+		if budget <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), budget)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		c.Writer = &deadlineResponseWriter{ResponseWriter: original, ctx: ctx}
+		c.Next()
+		c.Writer = original
+	}
+}
+
+// turns error responses caused by an expired budget into 504
+type deadlineResponseWriter struct {
+	gin.ResponseWriter
+	ctx       context.Context
+	replaced  bool        // handler's error body is dropped
+}
+
+func (writer *deadlineResponseWriter) WriteHeader(code int) {
+
+	// handlers that already answer 504 (e.g. with partial results) are left alone
+	if code >= 400 && code != http.StatusGatewayTimeout && writer.ctx.Err() == context.DeadlineExceeded {
+		writer.replaced = true
+		writer.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writer.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+		writer.ResponseWriter.WriteString(`{"code":"GATEWAY_TIMEOUT","error":"request deadline exceeded"}`)
+		return
+	}
+	writer.ResponseWriter.WriteHeader(code)
+}
+
+func (writer *deadlineResponseWriter) Write(data []byte) (int, error) {
+	if writer.replaced {
+		return len(data), nil
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *deadlineResponseWriter) WriteString(data string) (int, error) {
+	if writer.replaced {
+		return len(data), nil
+	}
+	return writer.ResponseWriter.WriteString(data)
+}
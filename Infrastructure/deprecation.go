@@ -0,0 +1,148 @@
+package infrastructure
+
+// imports
+import (
+	"net/http";
+	"sort";
+	"strconv";
+	"sync";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// how often the same client is logged for the same deprecated endpoint
+const deprecationLogInterval = time.Hour
+
+// logged clients kept before old entries are dropped
+const maxDeprecationLogEntries = 10000
+
+const deprecatedAliasKey = "deprecatedAlias"
+
+// deprecated endpoints (annotated in code or configured, configuration wins) and who still calls them
+type DeprecationPolicy struct {
+	logger     domain.Logger
+	mu         sync.RWMutex
+	routes     map[string]domain.Deprecation      // keyed by "METHOD /route"
+	configured map[string]bool                    // endpoints from configuration, annotations don't replace them
+	logged     map[string]time.Time               // last log line per endpoint and client
+}
+
+func NewDeprecationPolicy(configured []domain.Deprecation, logger domain.Logger) *DeprecationPolicy {
+	policy := &DeprecationPolicy{logger: logger, routes: map[string]domain.Deprecation{}, configured: map[string]bool{}, logged: map[string]time.Time{}}
+	for _, deprecation := range configured {
+		policy.routes[deprecation.Endpoint] = deprecation
+		policy.configured[deprecation.Endpoint] = true
+	}
+	return policy
+}
+
+// mark endpoints deprecated in code (configured entries for the same endpoint are kept)
+func (policy *DeprecationPolicy) Annotate(deprecations ...domain.Deprecation) {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	for _, deprecation := range deprecations {
+		if !policy.configured[deprecation.Endpoint] {
+			policy.routes[deprecation.Endpoint] = deprecation
+		}
+	}
+}
+
+// deprecation of an endpoint ("METHOD /route")
+func (policy *DeprecationPolicy) Lookup(endpoint string) (domain.Deprecation, bool) {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	deprecation, ok := policy.routes[endpoint]
+	return deprecation, ok
+}
+
+// deprecated endpoints sorted by endpoint
+func (policy *DeprecationPolicy) Deprecations() []domain.Deprecation {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	deprecations := make([]domain.Deprecation, 0, len(policy.routes))
+	for _, deprecation := range policy.routes {
+		deprecations = append(deprecations, deprecation)
+	}
+	sort.Slice(deprecations, func(i, j int) bool { return deprecations[i].Endpoint < deprecations[j].Endpoint })
+	return deprecations
+}
+
+// deprecation handler
+// announces deprecated endpoints in response headers and logs each caller once per interval
+// (calls of unversioned aliases, marked by Deprecated, are logged too)
+func (policy *DeprecationPolicy) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		endpoint := c.Request.Method + " " + RoutePath(c)
+		deprecation, deprecated := policy.Lookup(endpoint)
+		if deprecated {
+			c.Header("Deprecation", "true")
+			if deprecation.Sunset != nil {
+				setSunset(c, *deprecation.Sunset)
+			}
+			if deprecation.Successor != "" {
+				c.Writer.Header().Add("Link", "<"+APIPrefix(c)+deprecation.Successor+`>; rel="successor-version"`)
+			}
+		}
+
+		c.Next()
+
+		// the auth handler has run, so the caller is known
+		alias := c.GetBool(deprecatedAliasKey)
+		if deprecated || alias {
+			policy.logCall(c, endpoint, alias)
+		}
+	}
+}
+
+// log a call of a deprecated endpoint unless the same client was logged recently
+func (policy *DeprecationPolicy) logCall(c *gin.Context, endpoint string, alias bool) {
+
+	clientID, _ := c.Get("clientID")
+	client, _ := clientID.(string)
+	caller := c.GetString("userID") + "|" + c.GetString("tokenID") + "|" + client
+	if c.GetString("userID") == "" {
+		caller = c.ClientIP()        // public and rejected calls
+	}
+	key := endpoint + "|" + strconv.FormatBool(alias) + "|" + caller
+
+	now := time.Now()
+	policy.mu.Lock()
+	last, seen := policy.logged[key]
+	if seen && now.Sub(last) < deprecationLogInterval {
+		policy.mu.Unlock()
+		return
+	}
+	if len(policy.logged) >= maxDeprecationLogEntries {
+		for loggedKey, at := range policy.logged {
+			if now.Sub(at) >= deprecationLogInterval {
+				delete(policy.logged, loggedKey)
+			}
+		}
+		if len(policy.logged) >= maxDeprecationLogEntries {
+			policy.logged = map[string]time.Time{}        // too many recent clients, start over
+		}
+	}
+	policy.logged[key] = now
+	policy.mu.Unlock()
+
+	policy.logger.Warn(c.Request.Context(), "deprecated endpoint called",
+		"endpoint", endpoint,
+		"path", c.Request.URL.Path,
+		"unversioned_alias", alias,
+		"token_id", c.GetString("tokenID"),
+		"client_id", client,
+		"client_ip", c.ClientIP(),
+		"user_agent", c.Request.UserAgent(),
+		"status", c.Writer.Status(),
+	)
+}
+
+// set the Sunset header, keeping an earlier date already announced
+func setSunset(c *gin.Context, sunset time.Time) {
+	if current, err := http.ParseTime(c.Writer.Header().Get("Sunset")); err == nil && current.Before(sunset) {
+		return
+	}
+	c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,161 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"encoding/json";
+	"fmt";
+	"net/http";
+	"net/url";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// search service on an elasticsearch index (rest api, kept in sync by task events)
+// documents hold the searchable text and the fields searches are filtered on
+type elasticsearchSearchService struct {
+	indexURL  string
+	client    *http.Client
+}
+
+// indexed task document
+type elasticsearchTask struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Language     string   `json:"language,omitempty"`
+	ProjectID    string   `json:"project_id,omitempty"`
+	TenantID     string   `json:"tenant_id,omitempty"`
+}
+
+func NewElasticsearchSearchService(baseURL string, index string) domain.SearchService {
+	return &elasticsearchSearchService{indexURL: strings.TrimRight(baseURL, "/") + "/" + url.PathEscape(index), client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (searchService *elasticsearchSearchService) IndexTask(ctx context.Context, task *domain.Task) error {
+
+	document := elasticsearchTask{Title: task.Title, Description: task.Description, Language: task.Language, TenantID: task.TenantID}
+	if task.ProjectID != nil {
+		document.ProjectID = task.ProjectID.Hex()
+	}
+
+	return searchService.do(ctx, http.MethodPut, "/_doc/"+task.ID.Hex(), document, nil)
+}
+
+func (searchService *elasticsearchSearchService) RemoveTask(ctx context.Context, taskID string) error {
+	err := searchService.do(ctx, http.MethodDelete, "/_doc/"+url.PathEscape(taskID), nil, nil)
+	if err == errElasticsearchNotFound {
+		return nil        // never indexed or already removed
+	}
+	return err
+}
+
+func (searchService *elasticsearchSearchService) SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchMatch, error) {
+
+	// same filters as a task list search, title words weigh like in the mongodb text index
+	filters := []interface{}{}
+	if query.Language != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"language": query.Language}})
+	}
+	if query.Visibility != nil {
+		projects := make([]string, 0, len(query.Visibility.Projects))
+		for _, projectID := range query.Visibility.Projects {
+			projects = append(projects, projectID.Hex())
+		}
+		filters = append(filters, anyOrMissing("project_id", projects))
+	}
+	if query.TenantID != nil {
+		if *query.TenantID == "" {
+			filters = append(filters, missing("tenant_id"))        // tasks from before organizations
+		} else {
+			filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"tenant_id": *query.TenantID}})
+		}
+	}
+	body := map[string]interface{}{
+		"size": query.Limit,
+		"query": map[string]interface{}{"bool": map[string]interface{}{
+			"must":    map[string]interface{}{"simple_query_string": map[string]interface{}{"query": query.Text, "fields": []string{"title^3", "description"}}},
+			"filter":  filters,
+		}},
+		"highlight": map[string]interface{}{
+			"encoder":    "html",
+			"pre_tags":   []string{"<em>"},
+			"post_tags":  []string{"</em>"},
+			"fields":     map[string]interface{}{"title": map[string]interface{}{"number_of_fragments": 0}, "description": map[string]interface{}{"number_of_fragments": 3}},
+		},
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID         string               `json:"_id"`
+				Score      float64              `json:"_score"`
+				Highlight  map[string][]string  `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := searchService.do(ctx, http.MethodPost, "/_search", body, &result); err != nil {
+		if err == errElasticsearchNotFound {
+			return []domain.TaskSearchMatch{}, nil        // index is created with the first task
+		}
+		return nil, err
+	}
+
+	matches := make([]domain.TaskSearchMatch, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		matches = append(matches, domain.TaskSearchMatch{TaskID: hit.ID, Score: hit.Score, Highlights: hit.Highlight})
+	}
+
+	return matches, nil
+}
+
+// missing index or document
+var errElasticsearchNotFound = fmt.Errorf("elasticsearch returned %d", http.StatusNotFound)
+
+// send a json request to the index and decode the answer into out (when not nil)
+func (searchService *elasticsearchSearchService) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, searchService.indexURL+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := searchService.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errElasticsearchNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch returned %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// filter matching documents without the field
+func missing(field string) map[string]interface{} {
+	return map[string]interface{}{"bool": map[string]interface{}{"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": field}}}}
+}
+
+// filter matching documents with one of the values or without the field
+func anyOrMissing(field string, values []string) map[string]interface{} {
+	return map[string]interface{}{"bool": map[string]interface{}{"should": []interface{}{
+		missing(field),
+		map[string]interface{}{"terms": map[string]interface{}{field: values}},
+	}}}
+}
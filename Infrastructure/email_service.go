@@ -0,0 +1,95 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"fmt";
+	"net";
+	"net/smtp";
+	"strconv";
+	"strings";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// create email service from configuration (smtp when a host is set, log otherwise)
+func NewEmailService(config *Config, logger domain.Logger) domain.EmailService {
+	if config.SMTPHost == "" {
+		return NewLogEmailService(logger)
+	}
+	return NewSMTPEmailService(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.EmailFrom)
+}
+
+// create smtp email service from saved settings
+func NewSMTPEmailServiceFromSettings(settings domain.SMTPSettings) domain.EmailService {
+	return NewSMTPEmailService(settings.Host, settings.Port, settings.Username, settings.Password, settings.From)
+}
+
+// settings email service (uses smtp settings saved by setup, falls back to the configured service)
+type settingsEmailService struct {
+	settingsRepo  domain.SettingsRepository
+	fallback      domain.EmailService
+}
+
+func NewSettingsEmailService(settingsRepo domain.SettingsRepository, fallback domain.EmailService) domain.EmailService {
+	return &settingsEmailService{settingsRepo: settingsRepo, fallback: fallback}
+}
+
+func (settingsServ *settingsEmailService) SendEmail(ctx context.Context, to string, subject string, body string) error {
+
+	// settings are read on every send so changes apply without a restart
+	settings, err := settingsServ.settingsRepo.GetSettings(ctx)
+	if err != nil && err != domain.ErrSettingsNotFound {
+		return err
+	}
+	if settings == nil || settings.SMTP == nil {
+		return settingsServ.fallback.SendEmail(ctx, to, subject, body)
+	}
+
+	return NewSMTPEmailServiceFromSettings(*settings.SMTP).SendEmail(ctx, to, subject, body)
+}
+
+// smtp email service (STARTTLS is negotiated by net/smtp when the server offers it)
+type smtpEmailService struct {
+	addr      string
+	auth      smtp.Auth
+	from      string
+}
+
+func NewSMTPEmailService(host string, port int, username string, password string, from string) domain.EmailService {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpEmailService{addr: net.JoinHostPort(host, strconv.Itoa(port)), auth: auth, from: from}
+}
+
+func (smtpServ *smtpEmailService) SendEmail(ctx context.Context, to string, subject string, body string) error {
+
+	// header values can't contain line breaks (header injection)
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	message := "From: " + smtpServ.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body + "\r\n"
+
+	return smtp.SendMail(smtpServ.addr, smtpServ.auth, smtpServ.from, []string{to}, []byte(message))
+}
+
+// log email service (writes emails to the application log, for development)
+type logEmailService struct {
+	logger domain.Logger
+}
+
+func NewLogEmailService(logger domain.Logger) domain.EmailService {
+	return &logEmailService{logger: logger}
+}
+
+func (logServ *logEmailService) SendEmail(ctx context.Context, to string, subject string, body string) error {
+	logServ.logger.Info(ctx, "email", "to", to, "subject", subject, "body", body)
+	return nil
+}
@@ -0,0 +1,137 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"crypto/rand";
+	"encoding/hex";
+	"encoding/json";
+	"errors";
+	"fmt";
+	"net/http";
+	"net/url";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// creates the configured error reporter (crashes are only logged when no tracker is set up)
+func NewErrorReporter(config *Config, logger domain.Logger) domain.ErrorReporter {
+	if config.SentryDSN == "" {
+		return noopErrorReporter{}
+	}
+	reporter, err := NewSentryReporter(config.SentryDSN, config.SentryEnvironment, logger)
+	if err != nil {
+		logger.Warn(context.Background(), "sentry error reporting disabled", "error", err)
+		return noopErrorReporter{}
+	}
+	return reporter
+}
+
+// reporter used without an error tracker
+type noopErrorReporter struct{}
+
+func (noopErrorReporter) Report(ctx context.Context, report domain.ErrorReport) {}
+
+// sentry reporter (events are posted in the background so requests don't wait)
+type sentryReporter struct {
+	storeURL     string        // project's store endpoint
+	auth         string        // X-Sentry-Auth header
+	environment  string
+	client       *http.Client
+	reports      chan domain.ErrorReport
+	logger       domain.Logger
+}
+
+// dsn format: https://<public key>@<host>/<project id>
+func NewSentryReporter(dsn string, environment string, logger domain.Logger) (domain.ErrorReporter, error) {
+
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	slash := strings.LastIndex(parsed.Path, "/")
+	if parsed.User == nil || parsed.User.Username() == "" || slash < 0 || parsed.Path[slash+1:] == "" {
+		return nil, errors.New("sentry dsn needs a public key and a project id")
+	}
+
+	sentry := &sentryReporter{
+		storeURL:    parsed.Scheme + "://" + parsed.Host + parsed.Path[:slash] + "/api/" + parsed.Path[slash+1:] + "/store/",
+		auth:        "Sentry sentry_version=7, sentry_client=task-manager/1.0, sentry_key=" + parsed.User.Username(),
+		environment: environment,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		reports:     make(chan domain.ErrorReport, 100),
+		logger:      logger,
+	}
+	go sentry.run()
+	return sentry, nil
+}
+
+func (sentry *sentryReporter) Report(ctx context.Context, report domain.ErrorReport) {
+	select {
+	case sentry.reports <- report:
+	default:
+		sentry.logger.Warn(ctx, "sentry queue full, error report dropped", "request_id", report.RequestID)
+	}
+}
+
+// deliver queued reports one by one
+func (sentry *sentryReporter) run() {
+	ctx := context.Background()
+	for report := range sentry.reports {
+
+		payload, err := json.Marshal(sentry.event(report))
+		if err != nil {
+			sentry.logger.Error(ctx, "sentry event marshal failed", "error", err)
+			continue
+		}
+
+		req, err := http.NewRequest(http.MethodPost, sentry.storeURL, bytes.NewReader(payload))
+		if err != nil {
+			sentry.logger.Error(ctx, "sentry request failed", "error", err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sentry-Auth", sentry.auth)
+
+		resp, err := sentry.client.Do(req)
+		if err != nil {
+			sentry.logger.Error(ctx, "sentry delivery failed", "error", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			sentry.logger.Error(ctx, "sentry rejected event", "status", resp.StatusCode)
+		}
+	}
+}
+
+// sentry event payload of a report
+func (sentry *sentryReporter) event(report domain.ErrorReport) map[string]interface{} {
+
+	eventID := make([]byte, 16)
+	if _, err := rand.Read(eventID); err != nil {
+		copy(eventID, fmt.Sprintf("%016x", report.Time.UnixNano()))
+	}
+
+	event := map[string]interface{}{
+		"event_id":  hex.EncodeToString(eventID),
+		"timestamp": report.Time.UTC().Format(time.RFC3339),
+		"level":     "fatal",
+		"platform":  "go",
+		"logger":    "recovery",
+		"message":   report.Message,
+		"exception": map[string]interface{}{"values": []map[string]interface{}{{"type": "panic", "value": report.Message}}},
+		"tags":      map[string]string{"request_id": report.RequestID},
+		"request":   map[string]string{"method": report.Method, "url": report.Path},
+		"extra":     map[string]string{"stack": report.Stack},
+	}
+	if sentry.environment != "" {
+		event["environment"] = sentry.environment
+	}
+	if report.UserID != "" {
+		event["user"] = map[string]string{"id": report.UserID}
+	}
+	return event
+}
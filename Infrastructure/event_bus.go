@@ -0,0 +1,65 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"sync";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// events buffered per subscriber before new ones are dropped for it
+const subscriberBuffer = 64
+
+// in-memory task event bus (subscribers only see events of this instance)
+type taskEventBus struct {
+	mutex        sync.RWMutex
+	subscribers  map[*taskSubscriber]struct{}
+	logger       domain.Logger
+}
+
+// live subscriber
+type taskSubscriber struct {
+	userID  string
+	events  chan domain.TaskEvent
+}
+
+func NewTaskEventBus(logger domain.Logger) domain.TaskEventBus {
+	return &taskEventBus{subscribers: map[*taskSubscriber]struct{}{}, logger: logger}
+}
+
+// register subscriber, cancel removes it and closes its channel
+func (bus *taskEventBus) Subscribe(userID string) (<-chan domain.TaskEvent, func()) {
+
+	subscriber := &taskSubscriber{userID: userID, events: make(chan domain.TaskEvent, subscriberBuffer)}
+
+	bus.mutex.Lock()
+	bus.subscribers[subscriber] = struct{}{}
+	bus.mutex.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			bus.mutex.Lock()
+			delete(bus.subscribers, subscriber)
+			bus.mutex.Unlock()
+			close(subscriber.events)
+		})
+	}
+
+	return subscriber.events, cancel
+}
+
+// publish event to every subscriber without waiting on slow ones
+func (bus *taskEventBus) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+
+	for subscriber := range bus.subscribers {
+		select {
+		case subscriber.events <- event:
+		default:
+			bus.logger.Warn(ctx, "task event dropped for slow subscriber", "user_id", subscriber.userID, "event", event.Type, "task_id", event.TaskID)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"sync";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// create event bus from configuration, external brokers subscribe to it
+func NewEventBus(config *Config, logger domain.Logger) domain.EventBus {
+
+	bus := NewInMemoryEventBus()
+	switch config.EventBroker {
+	case "nats":
+		nats := NewNATSEventPublisher(config.NATSURL, config.EventSubjectPrefix, logger)
+		bus.Subscribe(func(ctx context.Context, event domain.DomainEvent) {
+			nats.Publish(ctx, event)
+		})
+	case "", "none":
+	default:
+		logger.Warn(context.Background(), "unknown event broker, events stay in process", "broker", config.EventBroker)
+	}
+
+	return bus
+}
+
+// in-memory event bus (handlers run in the publishing goroutine, so they must be quick)
+type inMemoryEventBus struct {
+	mutex     sync.RWMutex
+	handlers  []domain.DomainEventHandler
+}
+
+func NewInMemoryEventBus() domain.EventBus {
+	return &inMemoryEventBus{}
+}
+
+func (bus *inMemoryEventBus) Subscribe(handler domain.DomainEventHandler) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.handlers = append(bus.handlers, handler)
+}
+
+func (bus *inMemoryEventBus) Publish(ctx context.Context, event domain.DomainEvent) {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+	for _, handler := range bus.handlers {
+		handler(ctx, event)
+	}
+}
@@ -0,0 +1,282 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"encoding/json";
+	"errors";
+	"fmt";
+	"net/http";
+	"plugin";
+	"strings";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// calls registered extensions in configuration order
+type extensionRegistry struct {
+	extensions  []domain.Extension
+	logger      domain.Logger
+}
+
+// create extension hooks from configuration
+// EXTENSION_PLUGINS lists go plugin files exporting an "Extension" symbol,
+// EXTENSION_HOOKS maps hooks to http endpoints (task.pre_create=https://...,auth.pre_login=https://...)
+func NewExtensionHooks(config *Config, logger domain.Logger) domain.ExtensionHooks {
+
+	registry := &extensionRegistry{logger: logger}
+	ctx := context.Background()
+
+	for _, path := range config.ExtensionPlugins {
+		extension, err := loadPluginExtension(path)
+		if err != nil {
+			logger.Error(ctx, "extension plugin not loaded", "path", path, "error", err)
+			continue
+		}
+		registry.extensions = append(registry.extensions, extension)
+		logger.Info(ctx, "extension plugin loaded", "extension", extension.Name())
+	}
+
+	for _, mapping := range config.ExtensionHooks {
+		hook, url, ok := strings.Cut(mapping, "=")
+		if !ok || url == "" {
+			logger.Warn(ctx, "invalid extension hook ignored", "hook", mapping)
+			continue
+		}
+		extension, err := NewHTTPExtension(hook, url)
+		if err != nil {
+			logger.Warn(ctx, "invalid extension hook ignored", "hook", mapping, "error", err)
+			continue
+		}
+		registry.extensions = append(registry.extensions, extension)
+	}
+
+	return registry
+}
+
+// load go plugin built with -buildmode=plugin against this module
+func loadPluginExtension(path string) (domain.Extension, error) {
+
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := plug.Lookup("Extension")
+	if err != nil {
+		return nil, err
+	}
+
+	// exported as a value or as a pointer to a variable
+	if extension, ok := symbol.(domain.Extension); ok {
+		return extension, nil
+	}
+	if extension, ok := symbol.(*domain.Extension); ok && *extension != nil {
+		return *extension, nil
+	}
+
+	return nil, fmt.Errorf("symbol Extension does not implement domain.Extension")
+}
+
+func (registry *extensionRegistry) PreTaskCreate(ctx context.Context, task *domain.Task) error {
+	for _, extension := range registry.extensions {
+		if hook, ok := extension.(domain.PreTaskCreateHook); ok {
+			if err := hook.PreTaskCreate(ctx, task); err != nil {
+				return rejection(extension, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (registry *extensionRegistry) PostTaskCreate(ctx context.Context, task *domain.Task) {
+	for _, extension := range registry.extensions {
+		if hook, ok := extension.(domain.PostTaskCreateHook); ok {
+			if err := hook.PostTaskCreate(ctx, task); err != nil {
+				registry.logger.Warn(ctx, "extension hook failed", "extension", extension.Name(), "hook", domain.HookPostTaskCreate, "error", err)
+			}
+		}
+	}
+}
+
+func (registry *extensionRegistry) PreLogin(ctx context.Context, username string) error {
+	for _, extension := range registry.extensions {
+		if hook, ok := extension.(domain.PreLoginHook); ok {
+			if err := hook.PreLogin(ctx, username); err != nil {
+				return rejection(extension, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (registry *extensionRegistry) DecorateResponse(ctx context.Context, method string, path string, status int) map[string]string {
+	headers := map[string]string{}
+	for _, extension := range registry.extensions {
+		if hook, ok := extension.(domain.DecorateResponseHook); ok {
+			extra, err := hook.DecorateResponse(ctx, method, path, status)
+			if err != nil {
+				registry.logger.Warn(ctx, "extension hook failed", "extension", extension.Name(), "hook", domain.HookDecorateResponse, "error", err)
+				continue
+			}
+			for key, value := range extra {
+				headers[key] = value
+			}
+		}
+	}
+	return headers
+}
+
+// wrap extension error so callers can tell rejections apart
+func rejection(extension domain.Extension, err error) error {
+	if errors.Is(err, domain.ErrRejectedByExtension) {
+		return err
+	}
+	return fmt.Errorf("%w (%s): %v", domain.ErrRejectedByExtension, extension.Name(), err)
+}
+
+// extension decoration handler (adds headers returned by response.decorate extensions)
+func ExtensionHeaders(hooks domain.ExtensionHooks) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		// headers must be set before the handler writes the body
+		c.Writer = &decoratingResponseWriter{ResponseWriter: c.Writer, c: c, hooks: hooks}
+		c.Next()
+	}
+}
+
+// asks extensions for headers right before the status line goes out
+type decoratingResponseWriter struct {
+	gin.ResponseWriter
+	c          *gin.Context
+	hooks      domain.ExtensionHooks
+	decorated  bool
+}
+
+func (writer *decoratingResponseWriter) WriteHeader(code int) {
+	if !writer.decorated {
+		writer.decorated = true
+		for key, value := range writer.hooks.DecorateResponse(writer.c.Request.Context(), writer.c.Request.Method, RoutePath(writer.c), code) {
+			writer.ResponseWriter.Header().Set(key, value)
+		}
+	}
+	writer.ResponseWriter.WriteHeader(code)
+}
+
+// http extension (one hook per endpoint)
+type httpExtension struct {
+	hook    string
+	url     string
+	client  *http.Client
+}
+
+// http extension request body
+type extensionRequest struct {
+	Hook     string          `json:"hook"`
+	Payload  interface{}     `json:"payload"`
+}
+
+// http extension response body
+type extensionResponse struct {
+	Allow    *bool                `json:"allow,omitempty"`       // false rejects the operation
+	Reason   string               `json:"reason,omitempty"`      // shown to the client on rejection
+	Task     *domain.Task         `json:"task,omitempty"`        // replacement task (task.pre_create)
+	Headers  map[string]string    `json:"headers,omitempty"`     // extra headers (response.decorate)
+}
+
+func NewHTTPExtension(hook string, url string) (domain.Extension, error) {
+	extension := &httpExtension{hook: hook, url: url, client: &http.Client{Timeout: 2 * time.Second}}
+	switch hook {
+	case domain.HookPreTaskCreate:
+		return &httpPreTaskCreate{extension}, nil
+	case domain.HookPostTaskCreate:
+		return &httpPostTaskCreate{extension}, nil
+	case domain.HookPreLogin:
+		return &httpPreLogin{extension}, nil
+	case domain.HookDecorateResponse:
+		return &httpDecorateResponse{extension}, nil
+	default:
+		return nil, fmt.Errorf("unknown hook %q", hook)
+	}
+}
+
+func (httpExt *httpExtension) Name() string {
+	return httpExt.hook + " " + httpExt.url
+}
+
+// post hook payload and decode the extension's answer
+func (httpExt *httpExtension) call(ctx context.Context, payload interface{}) (*extensionResponse, error) {
+
+	body, err := json.Marshal(extensionRequest{Hook: httpExt.hook, Payload: payload})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, httpExt.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if requestID := domain.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+
+	resp, err := httpExt.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("extension returned %d", resp.StatusCode)
+	}
+
+	var result extensionResponse
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+	if result.Allow != nil && !*result.Allow {
+		return &result, fmt.Errorf("%w: %s", domain.ErrRejectedByExtension, result.Reason)
+	}
+
+	return &result, nil
+}
+
+type httpPreTaskCreate struct{ *httpExtension }
+
+func (hook *httpPreTaskCreate) PreTaskCreate(ctx context.Context, task *domain.Task) error {
+	result, err := hook.call(ctx, task)
+	if err != nil {
+		return err
+	}
+	if result.Task != nil {
+		result.Task.ID = task.ID        // extensions can change fields, not identity
+		*task = *result.Task
+	}
+	return nil
+}
+
+type httpPostTaskCreate struct{ *httpExtension }
+
+func (hook *httpPostTaskCreate) PostTaskCreate(ctx context.Context, task *domain.Task) error {
+	_, err := hook.call(ctx, task)
+	return err
+}
+
+type httpPreLogin struct{ *httpExtension }
+
+func (hook *httpPreLogin) PreLogin(ctx context.Context, username string) error {
+	_, err := hook.call(ctx, map[string]string{"username": username, "client_ip": domain.ClientIPFromContext(ctx)})
+	return err
+}
+
+type httpDecorateResponse struct{ *httpExtension }
+
+func (hook *httpDecorateResponse) DecorateResponse(ctx context.Context, method string, path string, status int) (map[string]string, error) {
+	result, err := hook.call(ctx, map[string]interface{}{"method": method, "path": path, "status": status})
+	if err != nil {
+		return nil, err
+	}
+	return result.Headers, nil
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"encoding/json";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// field policy handler
+// strips fields the caller may not see from json responses (see domain.ResponseFieldRules), must be registered
+// after ResponseFormat so it sees the native snake_case keys
+func FieldPolicy(rules []domain.FieldRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		original := c.Writer
+		buffered := &bufferedResponseWriter{ResponseWriter: original, body: &bytes.Buffer{}}
+		c.Writer = buffered
+
+		c.Next()
+
+		c.Writer = original
+		body := buffered.body.Bytes()
+		if len(body) == 0 {
+			return        // nothing buffered (streamed or hijacked responses)
+		}
+
+		// the auth handler has run, so the caller's permissions are known
+		permissions, _ := c.Get("permissions")
+		granted, _ := permissions.([]domain.Permission)
+		hidden := hiddenFields(rules, granted, body)
+		if len(hidden) > 0 {
+			if filtered, err := filterJSON(body, hidden, c.GetString("userID")); err == nil {
+				body = filtered
+				original.Header().Del("Content-Length")
+			}
+		}
+
+		original.Write(body)
+	}
+}
+
+// rules the caller doesn't pass by permission, only those whose field occurs in the body
+func hiddenFields(rules []domain.FieldRule, granted []domain.Permission, body []byte) map[string]domain.FieldRule {
+	hidden := map[string]domain.FieldRule{}
+	for _, rule := range rules {
+		if domain.HasPermission(granted, rule.Permission) {
+			continue
+		}
+		if bytes.Contains(body, []byte(`"`+rule.Field+`"`)) {
+			hidden[rule.Field] = rule
+		}
+	}
+	return hidden
+}
+
+// strip hidden fields from a json body (kept on objects the caller owns)
+func filterJSON(body []byte, hidden map[string]domain.FieldRule, userID string) ([]byte, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()        // keep numbers exactly as written
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(stripFields(value, hidden, userID))
+}
+
+// remove hidden keys recursively
+func stripFields(value interface{}, hidden map[string]domain.FieldRule, userID string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			if rule, ok := hidden[key]; ok {
+				if owner, _ := typed[rule.OwnerKey].(string); rule.OwnerKey == "" || userID == "" || owner != userID {
+					delete(typed, key)
+					continue
+				}
+			}
+			typed[key] = stripFields(item, hidden, userID)
+		}
+		return typed
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = stripFields(item, hidden, userID)
+		}
+		return typed
+	default:
+		return value
+	}
+}
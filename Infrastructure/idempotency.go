@@ -0,0 +1,113 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"crypto/sha256";
+	"encoding/hex";
+	"io";
+	"net/http";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// largest response kept for replays (bigger ones run again on retry)
+const maxIdempotentResponseSize = 1 << 20
+
+// response writer keeping a copy of what the handler writes
+type recordingResponseWriter struct {
+	gin.ResponseWriter
+	body      *bytes.Buffer
+	overflow  bool
+}
+
+func (writer *recordingResponseWriter) Write(data []byte) (int, error) {
+	writer.record(data)
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *recordingResponseWriter) WriteString(data string) (int, error) {
+	writer.record([]byte(data))
+	return writer.ResponseWriter.WriteString(data)
+}
+
+func (writer *recordingResponseWriter) record(data []byte) {
+	if writer.overflow || writer.body.Len()+len(data) > maxIdempotentResponseSize {
+		writer.overflow = true
+		return
+	}
+	writer.body.Write(data)
+}
+
+// idempotency handler (register after the auth handler)
+// a POST retried with the same Idempotency-Key gets the stored response of the first request instead of running again,
+// keys belong to the calling user and are kept for ttl
+func Idempotency(repo domain.IdempotencyRepository, ttl time.Duration, logger domain.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		key := c.GetHeader(domain.IdempotencyKeyHeader)
+		caller := c.GetString("userID")
+		if key == "" || caller == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(key) > domain.MaxIdempotencyKeyLength {
+			apierror.Message(c, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		// a retry must repeat the request exactly
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Message(c, http.StatusBadRequest, "could not read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+
+		now := time.Now().UTC()
+		record := &domain.IdempotencyRecord{Key: key, Caller: caller, RequestHash: hex.EncodeToString(hash[:]), CreatedAt: now, ExpiresAt: now.Add(ttl)}
+		existing, err := repo.Reserve(c.Request.Context(), record)
+		if err != nil {
+			logger.Error(c.Request.Context(), "idempotency key store failed, request runs without it", "error", err)       // never fail the write because of the key store
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				apierror.Respond(c, http.StatusUnprocessableEntity, domain.ErrIdempotencyKeyMismatch)
+			case !existing.Completed():
+				apierror.Respond(c, http.StatusConflict, domain.ErrIdempotencyKeyInUse)
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &recordingResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		c.Writer = recorder.ResponseWriter
+
+		// the outcome is saved even when the client went away, that's when it retries
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || recorder.overflow {
+			err = repo.Release(ctx, record.ID)        // failed or too big to keep, a retry runs again
+		} else {
+			err = repo.Complete(ctx, record.ID, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			logger.Error(ctx, "idempotency key update failed", "error", err)
+		}
+	}
+}
@@ -2,71 +2,153 @@ package infrastructure
 
 // imports
 import (
+	"crypto/ecdsa";
+	"crypto/elliptic";
+	"crypto/rsa";
+	"encoding/base64";
 	"errors";
+	"fmt";
 	"log";
-	"path/filepath";
-	"runtime";
+	"math/big";
+	"os";
+	"strings";
+	"sync";
 	"time";
 	"github.com/dgrijalva/jwt-go";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 	"github.com/spf13/viper";
 )
 
+// key id of JWT_SECRET when JWT_SECRET_KID is not set
+const defaultSecretKID = "default"
+
+var errUnknownSigningKey = errors.New("token signed with unknown key")
+
+// signing or verification key
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	sign    interface{}        // private key or hmac secret (nil for keys that only verify)
+	verify  interface{}        // public key or hmac secret
+}
+
+// keys loaded from configuration
+type signingKeys struct {
+	current  *signingKey                 // signs new tokens
+	byKID    map[string]*signingKey      // every key tokens are accepted from
+	legacy   *signingKey                 // hmac secret for tokens issued before key ids (no kid header)
+	order    []string                    // key ids in configuration order (jwks output)
+}
+
 type JWTService struct {
-	secret []byte
+	mu    sync.RWMutex
+	keys  *signingKeys
 }
 
 func NewJWTService() (*JWTService, error) {
 	
-	// intialize viper
-	viper.AutomaticEnv() 
-	
-	_, filename, _, _ := runtime.Caller(0)
-	rootDir := filepath.Dir(filepath.Dir(filename))
-	
-	// configure viper
-	viper.SetConfigName(".env")               // set config name
-	viper.SetConfigType("env")                // set config type
-	viper.AddConfigPath(".")                  // current directory
-	viper.AddConfigPath(rootDir)              // project root
-	
-	err := viper.ReadInConfig(); 
+	initViper()        // read .env and environment variables
+
+	keys, err := loadSigningKeys()
 	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			log.Printf("error reading config: %v", err)
-		}
+		log.Fatal(err)
 	}
-    
-	// get from JWT_SECRET variable in .env
-	secret := viper.GetString("JWT_SECRET")
-	if secret == "" {
-		log.Fatal("JWT_SECRET must be set in .env or environment variables")
+
+	return &JWTService{keys: keys}, nil        // success 
+}
+
+// re-read .env and the key files, the old keys stay in use when the new ones are invalid
+func (jwtServ *JWTService) Reload() error {
+
+	if err := ReloadConfigFile(); err != nil {
+		return err
+	}
+	keys, err := loadSigningKeys()
+	if err != nil {
+		return err
 	}
 
-	return &JWTService{secret: []byte(secret)}, nil        // success 
+	jwtServ.mu.Lock()
+	jwtServ.keys = keys
+	jwtServ.mu.Unlock()
+	return nil
+}
+
+// key id of the key signing new tokens
+func (jwtServ *JWTService) SigningKeyID() string {
+	return jwtServ.signingKeys().current.kid
+}
+
+func (jwtServ *JWTService) signingKeys() *signingKeys {
+	jwtServ.mu.RLock()
+	defer jwtServ.mu.RUnlock()
+	return jwtServ.keys
 }
 
-func (jwtServ *JWTService) GenerateToken(userID, username, role string) (string, error) {
+func (jwtServ *JWTService) GenerateToken(userID, username, role, tenantID, sessionID string) (string, error) {
 	
 	// create token with claims 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"userId": userID,            // user id          
 		"username": username,        // username
 		"role": role,                // user role (admin/user)
-		"exp": time.Now().Add(time.Hour * 24).Unix(),      // expires in 24h
-	})
+		"permissions": domain.PermissionsForRole(role),    // permissions granted by the role
+		"exp": time.Now().Add(domain.SessionTTL).Unix(),   // expires with the session (24h)
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID        // login session (can be revoked before expiry)
+	}
+	if tenantID != "" {
+		claims["tenant_id"] = tenantID   // organization whose data the token sees (default workspace when missing)
+	}
+
+	// sign with the current key
+	return jwtServ.sign(claims)         // success 
+}
 
-	// sign with secret key
-	return token.SignedString(jwtServ.secret)         // success 
+func (jwtServ *JWTService) GenerateScopedToken(userID, username, role, tenantID, clientID string, scopes []string, ttl time.Duration) (string, error) {
+	
+	// create token with claims limited to the granted scopes
+	claims := jwt.MapClaims{
+		"userId": userID,                           // user id
+		"username": username,                       // username
+		"role": role,                               // user role (admin/user)
+		"permissions": domain.PermissionsForRole(role),       // permissions granted by the role
+		"client_id": clientID,                      // third-party client the token was issued to
+		"scope": strings.Join(scopes, " "),         // space separated granted scopes
+		"exp": time.Now().Add(ttl).Unix(),          // expiry chosen by caller
+	}
+	if tenantID != "" {
+		claims["tenant_id"] = tenantID              // organization whose data the token sees
+	}
+
+	// sign with the current key
+	return jwtServ.sign(claims)         // success 
+}
+
+// sign claims with the current key, its id goes into the kid header
+func (jwtServ *JWTService) sign(claims jwt.MapClaims) (string, error) {
+	key := jwtServ.signingKeys().current
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.sign)
 }
 
 func (jwtServ *JWTService) ValidateToken(tokenStr string) (*jwt.Token, error) {
 	
+	keys := jwtServ.signingKeys()
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {	
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)    // check if token uses HMAC signing  
-		if !ok {
-			return nil, jwt.ErrSignatureInvalid      // block invalid signing 
+		key := keys.legacy        // tokens from before key ids
+		if kid, ok := token.Header["kid"].(string); ok {
+			key = keys.byKID[kid]
+		}
+		if key == nil {
+			return nil, errUnknownSigningKey
 		}
-		return jwtServ.secret, nil     // return secret to verify signature
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, jwt.ErrSignatureInvalid      // block tokens signed with another algorithm than the key's
+		}
+		return key.verify, nil     // return key to verify signature
 	})
 
 	if err != nil {
@@ -93,3 +175,118 @@ func (jwtServ *JWTService) ValidateToken(tokenStr string) (*jwt.Token, error) {
 	return token, nil       // success 
 } 
 
+// public keys of the rsa and ec keys in configuration order
+func (jwtServ *JWTService) JWKS() domain.JSONWebKeySet {
+
+	keys := jwtServ.signingKeys()
+	set := domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	for _, kid := range keys.order {
+		key := keys.byKID[kid]
+		jwk := domain.JSONWebKey{KeyID: kid, Use: "sig", Algorithm: key.method.Alg()}
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			jwk.KeyType = "EC"
+			jwk.Curve = public.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, 32)))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, 32)))
+		default:
+			continue        // hmac secrets stay private
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// load keys from configuration
+// JWT_SECRET is an hs256 key (id JWT_SECRET_KID), JWT_KEYS lists pem files as kid=path (rsa keys sign with RS256, P-256 keys with ES256,
+// public keys only verify) and JWT_SIGNING_KEY picks the key for new tokens (first private key of JWT_KEYS, else JWT_SECRET)
+func loadSigningKeys() (*signingKeys, error) {
+
+	keys := &signingKeys{byKID: map[string]*signingKey{}}
+
+	if secret := viper.GetString("JWT_SECRET"); secret != "" {
+		kid := viper.GetString("JWT_SECRET_KID")
+		if kid == "" {
+			kid = defaultSecretKID
+		}
+		keys.legacy = &signingKey{kid: kid, method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)}
+		keys.byKID[kid] = keys.legacy
+		keys.order = append(keys.order, kid)
+	}
+
+	var firstPrivate *signingKey
+	for _, entry := range strings.Split(viper.GetString("JWT_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid JWT_KEYS entry %q, expected kid=path", entry)
+		}
+		if _, exists := keys.byKID[kid]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", kid)
+		}
+		key, err := loadPEMKey(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		keys.byKID[kid] = key
+		keys.order = append(keys.order, kid)
+		if firstPrivate == nil && key.sign != nil {
+			firstPrivate = key
+		}
+	}
+
+	switch kid := viper.GetString("JWT_SIGNING_KEY"); {
+	case kid != "":
+		keys.current = keys.byKID[kid]
+		if keys.current == nil || keys.current.sign == nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY %q is not a configured private key or secret", kid)
+		}
+	case firstPrivate != nil:
+		keys.current = firstPrivate
+	default:
+		keys.current = keys.legacy
+	}
+	if keys.current == nil {
+		return nil, errors.New("JWT_SECRET or JWT_KEYS must be set in .env or environment variables")
+	}
+
+	return keys, nil
+}
+
+// rsa or ec key from a pem file, private keys sign and verify, public keys only verify
+func loadPEMKey(kid string, path string) (*signingKey, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+	}
+
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, sign: private, verify: &private.PublicKey}, nil
+	}
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, verify: public}, nil
+	}
+	if private, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		if private.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("jwt key %q: only P-256 ec keys are supported (ES256)", kid)
+		}
+		return &signingKey{kid: kid, method: jwt.SigningMethodES256, sign: private, verify: &private.PublicKey}, nil
+	}
+	if public, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		if public.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("jwt key %q: only P-256 ec keys are supported (ES256)", kid)
+		}
+		return &signingKey{kid: kid, method: jwt.SigningMethodES256, verify: public}, nil
+	}
+
+	return nil, fmt.Errorf("jwt key %q: %s holds no rsa or ec key in pem format", kid, path)
+}
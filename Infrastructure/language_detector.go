@@ -0,0 +1,64 @@
+package infrastructure
+
+// imports
+import (
+	"strings";
+	"unicode";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// stop word hits a language needs before it is trusted
+const minLanguageHits = 2
+
+// common function words of each task language (words shared by languages count for each of them)
+var stopWords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "on", "this", "be", "are", "was", "have", "not", "you", "we", "from", "by", "at", "or", "an", "will", "should", "need", "please", "before", "after"},
+	"es": {"el", "la", "los", "las", "y", "que", "en", "un", "una", "es", "por", "con", "para", "del", "al", "se", "no", "lo", "como", "más", "pero", "su", "este", "esta", "hay", "antes", "después", "hacer"},
+	"fr": {"le", "la", "les", "et", "des", "du", "un", "une", "est", "que", "pour", "dans", "sur", "pas", "avec", "ce", "cette", "il", "nous", "vous", "au", "aux", "sont", "être", "faire", "avant", "après", "qui"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "ein", "eine", "zu", "den", "dem", "von", "für", "auf", "auch", "es", "sich", "wir", "bitte", "vor", "nach", "bis", "werden", "oder", "noch"},
+	"it": {"il", "lo", "gli", "e", "di", "che", "è", "per", "con", "non", "una", "un", "del", "della", "sono", "questo", "questa", "anche", "alla", "nel", "prima", "dopo", "fare", "da"},
+	"pt": {"o", "os", "as", "e", "de", "que", "não", "um", "uma", "para", "com", "do", "da", "dos", "das", "em", "no", "na", "por", "mais", "é", "ser", "fazer", "antes", "depois", "você"},
+	"nl": {"de", "het", "een", "en", "van", "is", "niet", "op", "te", "dat", "die", "met", "voor", "zijn", "er", "ook", "aan", "wordt", "bij", "naar", "moet", "nog", "graag"},
+}
+
+// stop word detector (counts function words, good enough for a sentence or more)
+type stopWordDetector struct {
+	languages  map[string][]string        // languages each word counts for
+}
+
+func NewLanguageDetector() domain.LanguageDetector {
+	detector := &stopWordDetector{languages: map[string][]string{}}
+	for language, words := range stopWords {
+		for _, word := range words {
+			detector.languages[word] = append(detector.languages[word], language)
+		}
+	}
+	return detector
+}
+
+// language with the most stop words, empty without enough of them or on a tie
+func (detector *stopWordDetector) Detect(text string) string {
+
+	hits := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		for _, language := range detector.languages[word] {
+			hits[language]++
+		}
+	}
+
+	best, bestHits, secondHits := "", 0, 0
+	for _, language := range domain.TaskLanguages {        // fixed order keeps ties deterministic
+		switch count := hits[language]; {
+		case count > bestHits:
+			best, bestHits, secondHits = language, count, bestHits
+		case count > secondHits:
+			secondHits = count
+		}
+	}
+	if bestHits < minLanguageHits || bestHits == secondHits {
+		return ""
+	}
+
+	return best
+}
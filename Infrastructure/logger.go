@@ -0,0 +1,65 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"log/slog";
+	"os";
+	"strings";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// slog backed logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// create logger from configuration (LOG_FORMAT json/text, LOG_LEVEL debug/info/warn/error)
+// it also becomes the default logger so remaining standard library log calls are structured too
+func NewLogger(config *Config) domain.Logger {
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, options)
+	if strings.ToLower(config.LogFormat) == "text" {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	return &slogLogger{logger: logger}
+}
+
+func (slogLog *slogLogger) Debug(ctx context.Context, msg string, args ...interface{}) {
+	slogLog.log(ctx, slog.LevelDebug, msg, args)
+}
+
+func (slogLog *slogLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slogLog.log(ctx, slog.LevelInfo, msg, args)
+}
+
+func (slogLog *slogLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slogLog.log(ctx, slog.LevelWarn, msg, args)
+}
+
+func (slogLog *slogLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slogLog.log(ctx, slog.LevelError, msg, args)
+}
+
+// add request fields from the context and write the record
+func (slogLog *slogLogger) log(ctx context.Context, level slog.Level, msg string, args []interface{}) {
+
+	if requestID := domain.RequestIDFromContext(ctx); requestID != "" {
+		args = append(args, "request_id", requestID)
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		args = append(args, "user_id", actor.ID)
+	}
+
+	slogLog.logger.Log(ctx, level, msg, args...)
+}
@@ -0,0 +1,162 @@
+package infrastructure
+
+// imports
+import (
+	"bufio";
+	"context";
+	"encoding/json";
+	"fmt";
+	"net";
+	"net/url";
+	"strings";
+	"sync";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const (
+	natsQueueSize     = 1024               // events waiting to be sent before new ones are dropped
+	natsTimeout       = 5 * time.Second    // dial and write timeout
+	natsRetryDelay    = 5 * time.Second    // wait before reconnecting after a failure
+)
+
+// nats event publisher (core nats protocol, events are published to <prefix><event type>)
+// publishing is asynchronous so a slow or unreachable broker never blocks requests
+type natsEventPublisher struct {
+	addr     string
+	prefix   string
+	logger   domain.Logger
+	queue    chan domain.DomainEvent
+	mutex    sync.Mutex        // guards writes to conn (publisher and ping replies)
+	conn     net.Conn
+}
+
+func NewNATSEventPublisher(natsURL string, prefix string, logger domain.Logger) domain.EventPublisher {
+
+	addr := natsURL
+	if parsed, err := url.Parse(natsURL); err == nil && parsed.Host != "" {
+		addr = parsed.Host
+	}
+
+	publisher := &natsEventPublisher{addr: addr, prefix: prefix, logger: logger, queue: make(chan domain.DomainEvent, natsQueueSize)}
+	go publisher.run()
+
+	return publisher
+}
+
+// queue event for delivery
+func (natsPub *natsEventPublisher) Publish(ctx context.Context, event domain.DomainEvent) {
+	select {
+	case natsPub.queue <- event:
+	default:
+		natsPub.logger.Warn(ctx, "nats queue full, event dropped", "event", event.Type, "event_id", event.ID)
+	}
+}
+
+// deliver queued events, reconnecting after failures
+func (natsPub *natsEventPublisher) run() {
+	for event := range natsPub.queue {
+		data, err := json.Marshal(event)
+		if err != nil {
+			natsPub.logger.Error(context.Background(), "nats event encoding failed", "event", event.Type, "error", err)
+			continue
+		}
+		for {
+			err := natsPub.send(natsPub.prefix+event.Type, data)
+			if err == nil {
+				break
+			}
+			natsPub.logger.Warn(context.Background(), "nats publish failed, retrying", "addr", natsPub.addr, "error", err)
+			natsPub.disconnect()
+			time.Sleep(natsRetryDelay)
+		}
+	}
+}
+
+// publish one message, connecting first when needed
+func (natsPub *natsEventPublisher) send(subject string, data []byte) error {
+
+	if err := natsPub.connect(); err != nil {
+		return err
+	}
+
+	natsPub.mutex.Lock()
+	defer natsPub.mutex.Unlock()
+	if natsPub.conn == nil {
+		return fmt.Errorf("nats connection closed")
+	}
+	natsPub.conn.SetWriteDeadline(time.Now().Add(natsTimeout))
+	_, err := fmt.Fprintf(natsPub.conn, "PUB %s %d\r\n%s\r\n", subject, len(data), data)
+	return err
+}
+
+// open connection and answer server pings in the background
+func (natsPub *natsEventPublisher) connect() error {
+
+	natsPub.mutex.Lock()
+	defer natsPub.mutex.Unlock()
+	if natsPub.conn != nil {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("tcp", natsPub.addr, natsTimeout)
+	if err != nil {
+		return err
+	}
+
+	// server greets with INFO, client answers with CONNECT
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(natsTimeout))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		conn.Close()
+		return fmt.Errorf("unexpected nats greeting: %q %v", info, err)
+	}
+	conn.SetReadDeadline(time.Time{})
+	conn.SetWriteDeadline(time.Now().Add(natsTimeout))
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"task-manager\"}\r\n")); err != nil {
+		conn.Close()
+		return err
+	}
+
+	natsPub.conn = conn
+	go natsPub.readLoop(conn, reader)
+
+	return nil
+}
+
+// reply to keepalive pings and notice when the server goes away
+func (natsPub *natsEventPublisher) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			natsPub.mutex.Lock()
+			conn.SetWriteDeadline(time.Now().Add(natsTimeout))
+			conn.Write([]byte("PONG\r\n"))
+			natsPub.mutex.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			natsPub.logger.Warn(context.Background(), "nats server error", "error", strings.TrimSpace(line))
+		}
+	}
+
+	natsPub.mutex.Lock()
+	if natsPub.conn == conn {
+		natsPub.conn = nil
+	}
+	natsPub.mutex.Unlock()
+	conn.Close()
+}
+
+// drop current connection
+func (natsPub *natsEventPublisher) disconnect() {
+	natsPub.mutex.Lock()
+	defer natsPub.mutex.Unlock()
+	if natsPub.conn != nil {
+		natsPub.conn.Close()
+		natsPub.conn = nil
+	}
+}
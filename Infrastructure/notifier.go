@@ -0,0 +1,54 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// create notifier from configuration
+func NewNotifier(config *Config, emailServ domain.EmailService, logger domain.Logger) domain.Notifier {
+	if config.Notifier == "email" {
+		return NewEmailNotifier(config.NotificationEmail, emailServ)
+	}
+	return NewLogNotifier(logger)
+}
+
+// log notifier (writes notifications to the application log)
+type logNotifier struct {
+	logger domain.Logger
+}
+
+func NewLogNotifier(logger domain.Logger) domain.Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (logNotif *logNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	args := []interface{}{"notification", notification.Type, "task_id", notification.TaskID.Hex()}
+	if notification.Recipient != "" {
+		args = append(args, "recipient", notification.Recipient)
+	}
+	logNotif.logger.Info(ctx, notification.Message, args...)
+	return nil
+}
+
+// email notifier (sends notifications through the email service, to the user they are for or the configured recipient)
+type emailNotifier struct {
+	recipient    string
+	emailServ    domain.EmailService
+}
+
+func NewEmailNotifier(recipient string, emailServ domain.EmailService) domain.Notifier {
+	return &emailNotifier{recipient: recipient, emailServ: emailServ}
+}
+
+func (emailNotif *emailNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	recipient := notification.Recipient
+	if recipient == "" {
+		recipient = emailNotif.recipient
+	}
+	if recipient == "" {
+		return nil        // no NOTIFICATION_EMAIL, only users with preferences get emails
+	}
+	return emailNotif.emailServ.SendEmail(ctx, recipient, notification.Subject, notification.Message)
+}
@@ -0,0 +1,320 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"crypto/rsa";
+	"encoding/base64";
+	"encoding/json";
+	"fmt";
+	"io";
+	"math/big";
+	"net/http";
+	"net/url";
+	"strconv";
+	"strings";
+	"sync";
+	"time";
+	"github.com/dgrijalva/jwt-go";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// provider endpoints
+const (
+	googleAuthURL   = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL  = "https://oauth2.googleapis.com/token"
+	googleCertsURL  = "https://www.googleapis.com/oauth2/v3/certs"
+	githubAuthURL   = "https://github.com/login/oauth/authorize"
+	githubTokenURL  = "https://github.com/login/oauth/access_token"
+	githubAPIURL    = "https://api.github.com"
+)
+
+// how long fetched google signing keys are trusted before they are fetched again
+const googleCertsTTL = time.Hour
+
+// registered oauth app at a provider
+type oauthProvider struct {
+	clientID      string
+	clientSecret  string
+	authURL       string
+	scopes        string
+}
+
+// oauth2 / openid connect client of google and github
+type oauthService struct {
+	client       *http.Client
+	redirectURL  string                       // callback base, the provider name and /callback are appended
+	providers    map[string]oauthProvider     // providers with a client id
+	mu           sync.Mutex
+	googleKeys   map[string]*rsa.PublicKey    // google id token keys by kid
+	keysFetched  time.Time                    // when googleKeys were fetched
+}
+
+func NewOAuthService(config *Config) domain.OAuthService {
+
+	providers := map[string]oauthProvider{}
+	if config.GoogleClientID != "" {
+		providers[domain.ProviderGoogle] = oauthProvider{clientID: config.GoogleClientID, clientSecret: config.GoogleClientSecret, authURL: googleAuthURL, scopes: "openid email profile"}
+	}
+	if config.GitHubClientID != "" {
+		providers[domain.ProviderGitHub] = oauthProvider{clientID: config.GitHubClientID, clientSecret: config.GitHubClientSecret, authURL: githubAuthURL, scopes: "read:user user:email"}
+	}
+
+	return &oauthService{client: &http.Client{Timeout: 10 * time.Second}, redirectURL: strings.TrimRight(config.OAuthRedirectURL, "/"), providers: providers}
+}
+
+func (oauthServ *oauthService) Providers() []string {
+
+	configured := []string{}
+	for _, name := range []string{domain.ProviderGoogle, domain.ProviderGitHub} {
+		if _, ok := oauthServ.providers[name]; ok {
+			configured = append(configured, name)
+		}
+	}
+	return configured
+}
+
+func (oauthServ *oauthService) AuthCodeURL(providerName string, state string) (string, error) {
+
+	provider, ok := oauthServ.providers[providerName]
+	if !ok {
+		return "", domain.ErrUnknownProvider
+	}
+
+	params := url.Values{}
+	params.Set("client_id", provider.clientID)
+	params.Set("redirect_uri", oauthServ.callbackURL(providerName))
+	params.Set("response_type", "code")
+	params.Set("scope", provider.scopes)
+	params.Set("state", state)
+	if providerName == domain.ProviderGoogle {
+		params.Set("nonce", state)        // echoed in the id token, ties it to this sign-in
+		params.Set("prompt", "select_account")
+	}
+
+	return provider.authURL + "?" + params.Encode(), nil
+}
+
+func (oauthServ *oauthService) Exchange(ctx context.Context, providerName string, code string, state string) (*domain.ExternalIdentity, error) {
+
+	provider, ok := oauthServ.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+
+	var identity *domain.ExternalIdentity
+	var err error
+	switch providerName {
+	case domain.ProviderGoogle:
+		identity, err = oauthServ.googleIdentity(ctx, provider, code, state)
+	case domain.ProviderGitHub:
+		identity, err = oauthServ.githubIdentity(ctx, provider, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", domain.ErrExternalLoginFailed, providerName, err)
+	}
+
+	return identity, nil
+}
+
+// callback url registered at the provider
+func (oauthServ *oauthService) callbackURL(providerName string) string {
+	return oauthServ.redirectURL + "/" + providerName + "/callback"
+}
+
+// redeem the code for an id token and verify it (signature, issuer, audience, expiry and nonce)
+func (oauthServ *oauthService) googleIdentity(ctx context.Context, provider oauthProvider, code string, state string) (*domain.ExternalIdentity, error) {
+
+	var tokens struct {
+		IDToken  string  `json:"id_token"`
+	}
+	if err := oauthServ.redeemCode(ctx, googleTokenURL, provider, code, domain.ProviderGoogle, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("no id token in the token response")
+	}
+
+	token, err := jwt.Parse(tokens.IDToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return oauthServ.googleKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid id token")
+	}
+	if !claims.VerifyIssuer("https://accounts.google.com", true) && !claims.VerifyIssuer("accounts.google.com", true) {
+		return nil, fmt.Errorf("id token from unexpected issuer")
+	}
+	if !claims.VerifyAudience(provider.clientID, true) {
+		return nil, fmt.Errorf("id token issued to another client")
+	}
+	if nonce, _ := claims["nonce"].(string); nonce != state {
+		return nil, fmt.Errorf("id token nonce mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("id token without subject")
+	}
+	email, _ := claims["email"].(string)
+	verified, _ := claims["email_verified"].(bool)
+	name, _ := claims["name"].(string)
+
+	return &domain.ExternalIdentity{
+		Provider:      domain.ProviderGoogle,
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: verified,
+		Username:      strings.SplitN(email, "@", 2)[0],
+		Name:          name,
+	}, nil
+}
+
+// google signing key by kid (keys are fetched again when unknown or older than an hour)
+func (oauthServ *oauthService) googleKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+
+	oauthServ.mu.Lock()
+	defer oauthServ.mu.Unlock()
+
+	if key, ok := oauthServ.googleKeys[kid]; ok && time.Since(oauthServ.keysFetched) < googleCertsTTL {
+		return key, nil
+	}
+
+	var keySet domain.JSONWebKeySet
+	if err := oauthServ.getJSON(ctx, googleCertsURL, "", &keySet); err != nil {
+		return nil, err
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		if jwk.KeyType != "RSA" {
+			continue
+		}
+		modulus, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		exponent, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[jwk.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+	}
+	oauthServ.googleKeys = keys
+	oauthServ.keysFetched = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("id token signed with unknown key %q", kid)
+	}
+	return key, nil
+}
+
+// redeem the code for an access token and read the account (the primary email counts when github verified it)
+func (oauthServ *oauthService) githubIdentity(ctx context.Context, provider oauthProvider, code string) (*domain.ExternalIdentity, error) {
+
+	var tokens struct {
+		AccessToken       string  `json:"access_token"`
+		Error             string  `json:"error"`
+		ErrorDescription  string  `json:"error_description"`
+	}
+	if err := oauthServ.redeemCode(ctx, githubTokenURL, provider, code, domain.ProviderGitHub, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+
+	var account struct {
+		ID     int64   `json:"id"`
+		Login  string  `json:"login"`
+		Name   string  `json:"name"`
+	}
+	if err := oauthServ.getJSON(ctx, githubAPIURL+"/user", tokens.AccessToken, &account); err != nil {
+		return nil, err
+	}
+	if account.ID == 0 {
+		return nil, fmt.Errorf("account without id")
+	}
+
+	var emails []struct {
+		Email     string  `json:"email"`
+		Primary   bool    `json:"primary"`
+		Verified  bool    `json:"verified"`
+	}
+	if err := oauthServ.getJSON(ctx, githubAPIURL+"/user/emails", tokens.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &domain.ExternalIdentity{
+		Provider: domain.ProviderGitHub,
+		Subject:  strconv.FormatInt(account.ID, 10),
+		Username: account.Login,
+		Name:     account.Name,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+
+	return identity, nil
+}
+
+// post the authorization code to the token endpoint and decode the json answer
+func (oauthServ *oauthService) redeemCode(ctx context.Context, tokenURL string, provider oauthProvider, code string, providerName string, out interface{}) error {
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", provider.clientID)
+	form.Set("client_secret", provider.clientSecret)
+	form.Set("redirect_uri", oauthServ.callbackURL(providerName))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	return oauthServ.doJSON(req, out)
+}
+
+// get a json document, with a bearer token when given
+func (oauthServ *oauthService) getJSON(ctx context.Context, url string, accessToken string, out interface{}) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	return oauthServ.doJSON(req, out)
+}
+
+func (oauthServ *oauthService) doJSON(req *http.Request, out interface{}) error {
+
+	resp, err := oauthServ.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return json.Unmarshal(body, out)
+}
@@ -2,24 +2,121 @@ package infrastructure
 
 // imports
 import (
+	"crypto/rand";
+	"crypto/subtle";
+	"encoding/base64";
+	"fmt";
+	"strings";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"golang.org/x/crypto/argon2";
 	"golang.org/x/crypto/bcrypt";
 )
 
-type passwordService struct{}
+// argon2id hashes use the phc string format, bcrypt hashes start with $2a$/$2b$ so both can be stored side by side
+const (
+	argon2idPrefix   = "$argon2id$"
+	argon2SaltSize   = 16        // bytes of random salt per hash
+	argon2KeySize    = 32        // bytes of derived key
+)
+
+var argon2Encoding = base64.RawStdEncoding
+
+// argon2id cost parameters
+type argon2Params struct {
+	memory       uint32       // KiB
+	iterations   uint32
+	parallelism  uint8
+}
 
-func NewPasswordService() domain.PasswordService {
-	return &passwordService{}
+type passwordService struct{
+	algorithm  string          // algorithm of new hashes (bcrypt/argon2id)
+	cost       int             // bcrypt cost of new hashes
+	argon2     argon2Params    // argon2id parameters of new hashes
+}
+
+// creates password service hashing with the configured algorithm (bcrypt with default cost when out of range)
+func NewPasswordService(config *Config) domain.PasswordService {
+	cost := config.BcryptCost
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	params := argon2Params{memory: uint32(config.Argon2Memory), iterations: uint32(config.Argon2Iterations), parallelism: uint8(config.Argon2Parallelism)}
+	if config.Argon2Memory < 8*config.Argon2Parallelism || config.Argon2Iterations < 1 || config.Argon2Parallelism < 1 || config.Argon2Parallelism > 255 {
+		params = argon2Params{memory: 19456, iterations: 2, parallelism: 1}        // owasp minimum when out of range
+	}
+	algorithm := PasswordHashBcrypt
+	if config.PasswordHashAlgorithm == PasswordHashArgon2id {
+		algorithm = PasswordHashArgon2id
+	}
+	return &passwordService{algorithm: algorithm, cost: cost, argon2: params}
 }
 
 // hash password
 func (pswserv *passwordService) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if pswserv.algorithm == PasswordHashArgon2id {
+		return pswserv.hashArgon2id(password)
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), pswserv.cost)
 	return string(bytes), err
 }
 
-// check password
+// check password against a hash of either algorithm
 func (pswserv *passwordService) CheckPassword(hashed, plain string) bool {
+	if strings.HasPrefix(hashed, argon2idPrefix) {
+		params, salt, key, err := parseArgon2id(hashed)
+		if err != nil {
+			return false
+		}
+		derived := argon2.IDKey([]byte(plain), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(derived, key) == 1
+	}
 	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
 	return err == nil
-}
\ No newline at end of file
+}
+
+// check if hash was made with another algorithm or different parameters than the configured ones
+func (pswserv *passwordService) NeedsRehash(hashed string) bool {
+	if pswserv.algorithm == PasswordHashArgon2id {
+		params, _, _, err := parseArgon2id(hashed)
+		return err != nil || params != pswserv.argon2
+	}
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost != pswserv.cost
+}
+
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (pswserv *passwordService) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	params := pswserv.argon2
+	key := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, argon2KeySize)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, params.memory, params.iterations, params.parallelism,
+		argon2Encoding.EncodeToString(salt), argon2Encoding.EncodeToString(key)), nil
+}
+
+// split an argon2id hash into its parameters, salt and key
+func parseArgon2id(hashed string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("not an argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, err
+	}
+	salt, err := argon2Encoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := argon2Encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key")
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,202 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"fmt";
+	"strings";
+	"sync";
+	"sync/atomic";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/bsontype";
+	"go.mongodb.org/mongo-driver/event";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// longest logged command, longer ones are cut
+const maxLoggedCommand = 2000
+
+// array elements shown before the rest is summarized
+const maxLoggedElements = 3
+
+// driver commands that are not queries of the repositories
+var unloggedCommands = map[string]bool{"hello": true, "isMaster": true, "ismaster": true, "ping": true, "saslStart": true, "saslContinue": true, "buildInfo": true, "endSessions": true, "killCursors": true}
+
+// command fields that only carry session and routing state
+var skippedCommandFields = map[string]bool{"lsid": true, "$clusterTime": true, "$db": true, "$readPreference": true, "txnNumber": true, "autocommit": true, "startTransaction": true, "readConcern": true, "writeConcern": true}
+
+// command fields that shape the query and never hold user data
+var plainCommandFields = map[string]bool{"sort": true, "projection": true, "limit": true, "skip": true, "batchSize": true, "singleBatch": true, "ordered": true, "hint": true}
+
+// mongodb command monitor logging queries with sanitized filters while a window is open
+// values are replaced by ? so task titles, emails and token hashes never reach the logs
+type QueryLogger struct {
+	logger     domain.Logger
+	maxWindow  time.Duration             // longest window that can be opened
+	until      atomic.Int64              // end of the window in unix nanoseconds (0 when closed)
+	logged     atomic.Uint64             // queries logged since the window opened
+	started    sync.Map                  // request id -> sanitized command of queries in flight
+}
+
+func NewQueryLogger(logger domain.Logger, maxWindow time.Duration) *QueryLogger {
+	return &QueryLogger{logger: logger, maxWindow: maxWindow}
+}
+
+// command monitor to register on the mongodb client
+func (queryLog *QueryLogger) Monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if queryLog.enabled() && !unloggedCommands[evt.CommandName] {
+				queryLog.started.Store(evt.RequestID, sanitizeCommand(evt.Command))
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			queryLog.finish(ctx, evt.CommandFinishedEvent, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			queryLog.finish(ctx, evt.CommandFinishedEvent, evt.Failure)
+		},
+	}
+}
+
+func (queryLog *QueryLogger) EnableQueryLog(window time.Duration) (domain.QueryLogStatus, error) {
+
+	if window <= 0 || window > queryLog.maxWindow {
+		return queryLog.QueryLogStatus(), domain.ErrQueryLogWindow
+	}
+	until := time.Now().Add(window)
+	queryLog.logged.Store(0)
+	queryLog.until.Store(until.UnixNano())
+	queryLog.logger.Warn(context.Background(), "query logging enabled", "until", until.UTC())
+
+	return queryLog.QueryLogStatus(), nil
+}
+
+func (queryLog *QueryLogger) DisableQueryLog() domain.QueryLogStatus {
+
+	if queryLog.until.Swap(0) != 0 {
+		queryLog.logger.Warn(context.Background(), "query logging disabled", "logged", queryLog.logged.Load())
+	}
+	return queryLog.QueryLogStatus()
+}
+
+func (queryLog *QueryLogger) QueryLogStatus() domain.QueryLogStatus {
+
+	status := domain.QueryLogStatus{Enabled: queryLog.enabled(), Logged: queryLog.logged.Load(), MaxMinutes: int(queryLog.maxWindow / time.Minute)}
+	if status.Enabled {
+		until := time.Unix(0, queryLog.until.Load()).UTC()
+		status.Until = &until
+	}
+	return status
+}
+
+// check if the window is open (it closes itself when its time is up)
+func (queryLog *QueryLogger) enabled() bool {
+	until := queryLog.until.Load()
+	return until != 0 && time.Now().UnixNano() < until
+}
+
+// log a finished query that was started while the window was open
+func (queryLog *QueryLogger) finish(ctx context.Context, evt event.CommandFinishedEvent, failure string) {
+
+	command, ok := queryLog.started.LoadAndDelete(evt.RequestID)
+	if !ok {
+		return
+	}
+	queryLog.logged.Add(1)
+
+	args := []interface{}{"command", evt.CommandName, "query", command, "duration_ms", float64(evt.Duration.Microseconds()) / 1000}
+	if failure != "" {
+		args = append(args, "error", failure)
+	}
+	queryLog.logger.Info(ctx, "mongo query", args...)
+}
+
+// command as text with every value replaced by ? (collection name and query shape stay)
+func sanitizeCommand(command bson.Raw) string {
+
+	elements, err := command.Elements()
+	if err != nil {
+		return "?"
+	}
+
+	var out strings.Builder
+	out.WriteString("{")
+	written := 0
+	for i, element := range elements {
+		key := element.Key()
+		if skippedCommandFields[key] {
+			continue
+		}
+		if written > 0 {
+			out.WriteString(", ")
+		}
+		written++
+		out.WriteString(key + ": ")
+		writeSanitized(&out, element.Value(), i == 0 || plainCommandFields[key])        // command name's value is the collection
+	}
+	out.WriteString("}")
+
+	text := out.String()
+	if len(text) > maxLoggedCommand {
+		text = text[:maxLoggedCommand] + "…"
+	}
+	return text
+}
+
+// write a value keeping document keys and operators, literals become ? unless kept
+func writeSanitized(out *strings.Builder, value bson.RawValue, keepLiterals bool) {
+
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		elements, err := value.Document().Elements()
+		if err != nil {
+			out.WriteString("?")
+			return
+		}
+		out.WriteString("{")
+		for i, element := range elements {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			out.WriteString(element.Key() + ": ")
+			writeSanitized(out, element.Value(), keepLiterals)
+		}
+		out.WriteString("}")
+
+	case bsontype.Array:
+		values, err := value.Array().Values()
+		if err != nil {
+			out.WriteString("?")
+			return
+		}
+		out.WriteString("[")
+		for i, item := range values {
+			if i == maxLoggedElements {
+				fmt.Fprintf(out, ", …%d more", len(values)-i)
+				break
+			}
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			writeSanitized(out, item, keepLiterals)
+		}
+		out.WriteString("]")
+
+	default:
+		if !keepLiterals {
+			out.WriteString("?")
+			return
+		}
+		if text, ok := value.StringValueOK(); ok {
+			fmt.Fprintf(out, "%q", text)
+			return
+		}
+		if number, ok := value.AsInt64OK(); ok {
+			fmt.Fprint(out, number)
+			return
+		}
+		out.WriteString(value.String())
+	}
+}
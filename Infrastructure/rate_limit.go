@@ -0,0 +1,104 @@
+package infrastructure
+
+// imports
+import (
+	"math";
+	"net/http";
+	"strconv";
+	"sync";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// token bucket of one client
+type tokenBucket struct {
+	tokens    float64
+	updated   time.Time
+}
+
+// in-memory token bucket rate limiter keyed by client
+type RateLimiter struct {
+	rate       float64        // tokens added per second
+	burst      float64        // bucket size
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	lastSweep  time.Time
+}
+
+// limiter allowing perMinute requests on average with bursts of up to burst requests (perMinute <= 0 disables it)
+func NewRateLimiter(perMinute int, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{rate: float64(perMinute) / 60, burst: float64(burst), buckets: map[string]*tokenBucket{}, lastSweep: time.Now()}
+}
+
+// take a token for key, or report how long until one is available
+func (limiter *RateLimiter) Allow(key string) (bool, time.Duration) {
+
+	if limiter.rate <= 0 {
+		return true, 0
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	limiter.sweep(now)
+
+	bucket, ok := limiter.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limiter.burst, updated: now}
+		limiter.buckets[key] = bucket
+	}
+
+	// refill for the time passed since the last request
+	bucket.tokens = math.Min(limiter.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*limiter.rate)
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / limiter.rate * float64(time.Second))
+	return false, wait
+}
+
+// drop buckets that have refilled completely (bounds memory for many one-off clients)
+func (limiter *RateLimiter) sweep(now time.Time) {
+
+	if now.Sub(limiter.lastSweep) < time.Minute {
+		return
+	}
+	limiter.lastSweep = now
+
+	full := time.Duration(limiter.burst / limiter.rate * float64(time.Second))
+	for key, bucket := range limiter.buckets {
+		if now.Sub(bucket.updated) > full {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+// rate limit handler
+// limits per authenticated user when the auth middleware ran before it, per client ip otherwise
+func RateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		key := "ip:" + c.ClientIP()
+		if userID := c.GetString("userID"); userID != "" {
+			key = "user:" + userID
+		}
+
+		allowed, wait := limiter.Allow(key)
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			apierror.Message(c, http.StatusTooManyRequests, "rate limit exceeded, retry later")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package infrastructure
+
+// imports
+import (
+	"net/http";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// read only handler (rejects writes when the instance runs in read-only mode)
+func ReadOnlyGuard(enabled bool, allowedPaths ...string) gin.HandlerFunc {
+
+	// writes that are still allowed (e.g. login only reads users)
+	allowed := map[string]bool{}
+	for _, path := range allowedPaths {
+		allowed[path] = true
+	}
+
+	return func(c *gin.Context) {
+
+		// reads always pass
+		method := c.Request.Method
+		if !enabled || method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions || allowed[RoutePath(c)] {
+			c.Next()
+			return
+		}
+
+		apierror.Message(c, http.StatusServiceUnavailable, "instance is in read-only mode, writes are disabled")
+	}
+}
@@ -0,0 +1,59 @@
+package infrastructure
+
+// imports
+import (
+	"errors";
+	"fmt";
+	"net/http";
+	"runtime/debug";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// recovery handler
+// turns panics into 500 responses carrying the request id, logs the stack and hands the crash to the error reporter,
+// must be registered first so it also covers the other handlers
+func Recovery(logger domain.Logger, reporter domain.ErrorReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		// handlers that buffer the body (response format, field policy) don't get to restore the writer
+		original := c.Writer
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)        // deliberate abort of the connection, net/http handles it
+			}
+
+			// the request id handler has put the id on the request by now (unless it panicked itself)
+			ctx := c.Request.Context()
+			requestID := domain.RequestIDFromContext(ctx)
+			stack := string(debug.Stack())
+			message := fmt.Sprint(recovered)
+			logger.Error(ctx, "panic recovered", "panic", message, "method", c.Request.Method, "path", c.Request.URL.Path, "stack", stack)
+			reporter.Report(ctx, domain.ErrorReport{
+				Message:    message,
+				Stack:      stack,
+				RequestID:  requestID,
+				Method:     c.Request.Method,
+				Path:       c.Request.URL.Path,
+				UserID:     c.GetString("userID"),
+				Time:       time.Now().UTC(),
+			})
+
+			// nothing can be sent once the response has started
+			c.Writer = original
+			if original.Written() {
+				c.Abort()
+				return
+			}
+			apierror.MessageWith(c, http.StatusInternalServerError, "internal server error", gin.H{"request_id": requestID})
+		}()
+
+		c.Next()
+	}
+}
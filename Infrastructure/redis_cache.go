@@ -0,0 +1,211 @@
+package infrastructure
+
+// imports
+import (
+	"bufio";
+	"context";
+	"fmt";
+	"io";
+	"net";
+	"net/url";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const (
+	redisPoolSize   = 16                        // idle connections kept open
+	redisTimeout    = 500 * time.Millisecond    // dial and command timeout (a slow cache is worse than none)
+)
+
+// redis cache (RESP protocol over a small connection pool)
+type redisCache struct {
+	addr      string
+	password  string
+	database  int
+	idle      chan *redisConn
+}
+
+// connection with its buffered reader
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// create redis cache from a url like redis://:password@host:6379/0
+func NewRedisCache(redisURL string) (domain.Cache, error) {
+
+	parsed, err := url.Parse(redisURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid redis url %q", redisURL)
+	}
+
+	cache := &redisCache{addr: parsed.Host, idle: make(chan *redisConn, redisPoolSize)}
+	if !strings.Contains(parsed.Host, ":") {
+		cache.addr = parsed.Host + ":6379"
+	}
+	if parsed.User != nil {
+		cache.password, _ = parsed.User.Password()
+	}
+	if db := strings.Trim(parsed.Path, "/"); db != "" {
+		if cache.database, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+
+	return cache, nil
+}
+
+func (cache *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := cache.do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, domain.ErrCacheMiss
+	}
+	return reply.([]byte), nil
+}
+
+func (cache *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := cache.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (cache *redisCache) Incr(ctx context.Context, key string) (int64, error) {
+	reply, err := cache.do(ctx, "INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply %v", reply)
+	}
+	return value, nil
+}
+
+// run one command on a pooled connection
+func (cache *redisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+
+	conn, err := cache.get()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.command(ctx, args...)
+	if err != nil {
+		if _, isReplyErr := err.(redisError); !isReplyErr {
+			conn.Close()        // connection state unknown
+			return nil, err
+		}
+	}
+	cache.put(conn)
+
+	return reply, err
+}
+
+// idle connection or a new one
+func (cache *redisCache) get() (*redisConn, error) {
+	select {
+	case conn := <-cache.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", cache.addr, redisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	// authenticate and pick the database before first use
+	if cache.password != "" {
+		if _, err := conn.command(context.Background(), "AUTH", cache.password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if cache.database != 0 {
+		if _, err := conn.command(context.Background(), "SELECT", strconv.Itoa(cache.database)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// return connection to the pool (closed when the pool is full)
+func (cache *redisCache) put(conn *redisConn) {
+	select {
+	case cache.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// error reply sent by the server (the connection stays usable)
+type redisError string
+
+func (err redisError) Error() string {
+	return "redis: " + string(err)
+}
+
+// send command as a RESP array and read the reply
+func (conn *redisConn) command(ctx context.Context, args ...string) (interface{}, error) {
+
+	deadline := time.Now().Add(redisTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	var request strings.Builder
+	fmt.Fprintf(&request, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&request, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(conn, request.String()); err != nil {
+		return nil, err
+	}
+
+	return conn.readReply()
+}
+
+// read one RESP reply (nil for a missing value)
+func (conn *redisConn) readReply() (interface{}, error) {
+
+	line, err := conn.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)        // value and trailing \r\n
+		if _, err := io.ReadFull(conn.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	}
+
+	return nil, fmt.Errorf("unsupported redis reply %q", line)
+}
@@ -0,0 +1,152 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"encoding/json";
+	"html";
+	"io";
+	"mime";
+	"net/http";
+	"strings";
+	"unicode";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure/apierror";
+)
+
+// keys whose values are passed on exactly as sent (spaces may be part of a password)
+var unsanitizedKeys = map[string]bool{"password": true, "current_password": true, "new_password": true}
+
+// limits and clean-up of request bodies
+type RequestBodyPolicy struct {
+	MaxSize       int64                   // bytes of a body (0 for no limit)
+	Routes        map[string]int64        // size overrides by "METHOD /route/:param" (imports, ...)
+	ContentTypes  map[string][]string     // media types accepted besides application/json, by "METHOD /route/:param"
+	Raw           map[string]bool         // routes whose json is passed on unchanged (restores, ...)
+	EscapeHTML    bool                    // html-escape description fields
+}
+
+// request body handler
+// rejects bodies that are too large or of an unexpected content type, and cleans the strings of json bodies
+// (trimmed, control characters removed) before they are bound by the controllers
+func RequestBodyGuard(policy RequestBodyPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		// bodies of reads are never used
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions || c.Request.Body == nil || c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+		route := method + " " + RoutePath(c)
+
+		// declared sizes are checked before anything is read, chunked bodies while reading
+		limit, ok := policy.Routes[route]
+		if !ok {
+			limit = policy.MaxSize
+		}
+		if limit > 0 {
+			if c.Request.ContentLength > limit {
+				apierror.Message(c, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.ContentType())
+		if err != nil || !acceptedMediaType(mediaType, policy.ContentTypes[route]) {
+			apierror.Message(c, http.StatusUnsupportedMediaType, "unsupported content type, send application/json")
+			return
+		}
+		if !strings.HasSuffix(mediaType, "json") || policy.Raw[route] {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if _, tooLarge := err.(*http.MaxBytesError); tooLarge {
+				apierror.Message(c, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			apierror.Message(c, http.StatusBadRequest, "could not read request body")
+			return
+		}
+
+		// invalid json is left for the controller to report
+		if sanitized, err := sanitizeJSON(body, policy.EscapeHTML); err == nil {
+			body = sanitized
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+
+		c.Next()
+	}
+}
+
+// application/json and the route's own media types
+func acceptedMediaType(mediaType string, extra []string) bool {
+	if mediaType == "application/json" {
+		return true
+	}
+	for _, accepted := range extra {
+		if mediaType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// clean every string of a json body
+func sanitizeJSON(body []byte, escapeHTML bool) ([]byte, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()        // keep numbers exactly as written
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var sanitized bytes.Buffer
+	encoder := json.NewEncoder(&sanitized)
+	encoder.SetEscapeHTML(false)        // strings keep the characters they were sent with
+	if err := encoder.Encode(sanitizeValue(value, "", escapeHTML)); err != nil {
+		return nil, err
+	}
+	return sanitized.Bytes(), nil
+}
+
+// clean strings recursively, key is the name of the field holding value
+func sanitizeValue(value interface{}, key string, escapeHTML bool) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for name, item := range typed {
+			typed[name] = sanitizeValue(item, name, escapeHTML)
+		}
+		return typed
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = sanitizeValue(item, key, escapeHTML)
+		}
+		return typed
+	case string:
+		if unsanitizedKeys[key] {
+			return typed
+		}
+		cleaned := strings.TrimSpace(strings.Map(dropControl, typed))
+		if escapeHTML && key == "description" {
+			cleaned = html.EscapeString(cleaned)
+		}
+		return cleaned
+	}
+	return value
+}
+
+// remove control characters except line breaks and tabs
+func dropControl(r rune) rune {
+	if r == '\n' || r == '\r' || r == '\t' || !unicode.IsControl(r) {
+		return r
+	}
+	return -1
+}
@@ -0,0 +1,68 @@
+package infrastructure
+
+// imports
+import (
+	"crypto/rand";
+	"encoding/hex";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// header carrying the request id between services
+const RequestIDHeader = "X-Request-ID"
+
+// request id handler
+// keeps the caller's X-Request-ID (or generates one), echoes it back and puts it on the request context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = NewRequestID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(domain.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// request logging handler (one line per request)
+func RequestLogger(logger domain.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		start := time.Now()
+		c.Next()
+
+		// request context now carries the authenticated user (logged as user_id)
+		args := []interface{}{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			args = append(args, "errors", c.Errors.String())
+		}
+
+		switch {
+		case c.Writer.Status() >= 500:
+			logger.Error(c.Request.Context(), "request", args...)
+		case c.Writer.Status() >= 400:
+			logger.Warn(c.Request.Context(), "request", args...)
+		default:
+			logger.Info(c.Request.Context(), "request", args...)
+		}
+	}
+}
+
+// random 16 byte hex id (also used for grpc calls without one)
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(buf)
+}
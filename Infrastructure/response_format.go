@@ -0,0 +1,141 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"encoding/json";
+	"strconv";
+	"strings";
+	"github.com/gin-gonic/gin";
+)
+
+// response formats clients can ask for while migrating
+const (
+	ResponseCaseSnake = "snake"       // snake_case keys (native format)
+	ResponseCaseCamel = "camel"       // camelCase keys
+)
+
+// buffers the response so it can be rewritten before reaching the client
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (writer *bufferedResponseWriter) Write(data []byte) (int, error) {
+	if writer.streaming() {
+		return writer.ResponseWriter.Write(data)
+	}
+	return writer.body.Write(data)
+}
+
+func (writer *bufferedResponseWriter) WriteString(data string) (int, error) {
+	if writer.streaming() {
+		return writer.ResponseWriter.WriteString(data)
+	}
+	return writer.body.WriteString(data)
+}
+
+// non-json bodies (file downloads, ...) are never rewritten so they go straight out
+func (writer *bufferedResponseWriter) streaming() bool {
+	contentType := writer.Header().Get("Content-Type")
+	return contentType != "" && !strings.HasPrefix(contentType, "application/json")
+}
+
+// response format handler
+// clients choose with X-Response-Case (snake/camel) and X-Response-Envelope (true/false), defaults come from config
+func ResponseFormat(defaultCase string, defaultEnvelope bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		keyCase := defaultCase
+		if header := c.GetHeader("X-Response-Case"); header != "" {
+			keyCase = strings.ToLower(header)
+		}
+		envelope := defaultEnvelope
+		if header := c.GetHeader("X-Response-Envelope"); header != "" {
+			envelope, _ = strconv.ParseBool(header)
+		}
+
+		// native format needs no rewriting (and keeps streaming responses unbuffered)
+		if keyCase != ResponseCaseCamel && !envelope {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedResponseWriter{ResponseWriter: original, body: &bytes.Buffer{}}
+		c.Writer = buffered
+
+		c.Next()
+
+		c.Writer = original
+		body := buffered.body.Bytes()
+
+		// only json bodies are rewritten
+		if strings.HasPrefix(original.Header().Get("Content-Type"), "application/json") && len(body) > 0 {
+			if rewritten, err := rewriteJSON(body, keyCase == ResponseCaseCamel, envelope, original.Status()); err == nil {
+				body = rewritten
+			}
+		}
+
+		original.Header().Del("Content-Length")
+		original.Write(body)
+	}
+}
+
+// apply key case and envelope to a json body
+func rewriteJSON(body []byte, camel bool, envelope bool, status int) ([]byte, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()        // keep numbers exactly as written
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if camel {
+		value = camelizeKeys(value)
+	}
+
+	if envelope {
+		if status >= 400 {
+			value = map[string]interface{}{"status": status, "error": value}
+		} else {
+			value = map[string]interface{}{"status": status, "data": value}
+		}
+	}
+
+	return json.Marshal(value)
+}
+
+// convert object keys from snake_case to camelCase recursively
+func camelizeKeys(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			converted[snakeToCamel(key)] = camelizeKeys(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = camelizeKeys(item)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// due_date -> dueDate (leading underscores are kept, _links stays _links)
+func snakeToCamel(key string) string {
+	trimmed := strings.TrimLeft(key, "_")
+	prefix := key[:len(key)-len(trimmed)]
+	parts := strings.Split(trimmed, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return prefix + strings.Join(parts, "")
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// scheduled job item
+type scheduledJob struct {
+	name      string
+	interval  time.Duration
+	run       func(ctx context.Context) error
+}
+
+// background scheduler running jobs at fixed intervals
+type Scheduler struct {
+	jobs    []scheduledJob
+	logger  domain.Logger
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewScheduler(logger domain.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// register job to run every interval (must be called before Start)
+func (scheduler *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	scheduler.jobs = append(scheduler.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// start all jobs in the background
+func (scheduler *Scheduler) Start() {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.cancel = cancel
+
+	for _, job := range scheduler.jobs {
+		scheduler.wg.Add(1)
+		go func(job scheduledJob) {
+			defer scheduler.wg.Done()
+
+			ticker := time.NewTicker(job.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					// a run gets at most one interval so runs never overlap
+					runCtx, cancelRun := context.WithTimeout(ctx, job.interval)
+					if err := job.run(runCtx); err != nil {
+						scheduler.logger.Error(ctx, "scheduled job failed", "job", job.name, "error", err)
+					}
+					cancelRun()
+				}
+			}
+		}(job)
+	}
+}
+
+// stop all jobs and wait for running ones to finish
+func (scheduler *Scheduler) Stop() {
+	if scheduler.cancel != nil {
+		scheduler.cancel()
+	}
+	scheduler.wg.Wait()
+}
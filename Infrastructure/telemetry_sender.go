@@ -0,0 +1,70 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"crypto/sha256";
+	"encoding/hex";
+	"encoding/json";
+	"fmt";
+	"net/http";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// http telemetry sender (posts the report as json)
+type httpTelemetrySender struct {
+	endpoint  string
+	client    *http.Client
+}
+
+func NewTelemetrySender(endpoint string) domain.TelemetrySender {
+	return &httpTelemetrySender{endpoint: endpoint, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (telemetrySender *httpTelemetrySender) Send(ctx context.Context, report *domain.TelemetryReport) error {
+
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, telemetrySender.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := telemetrySender.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry collector returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// stable anonymous installation id (configured, or derived one-way from the database uri)
+func TelemetryInstanceID(config *Config) string {
+	if config.TelemetryInstanceID != "" {
+		return config.TelemetryInstanceID
+	}
+	sum := sha256.Sum256([]byte("task-manager:" + config.MongoURI))
+	return hex.EncodeToString(sum[:8])
+}
+
+// optional features in use on this instance
+func TelemetryFeatures(config *Config) map[string]bool {
+	return map[string]bool{
+		"read_only":          config.ReadOnly,
+		"reminders":          config.RemindersEnabled,
+		"email_notifier":     config.Notifier == "email",
+		"audit_export":       len(config.AuditSinks) > 0,
+		"camel_case":         config.ResponseCase == ResponseCaseCamel,
+		"response_envelope":  config.ResponseEnvelope,
+	}
+}
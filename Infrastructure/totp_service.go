@@ -0,0 +1,81 @@
+package infrastructure
+
+// imports
+import (
+	"crypto/hmac";
+	"crypto/rand";
+	"crypto/sha1";
+	"encoding/base32";
+	"encoding/binary";
+	"fmt";
+	"net/url";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// totp parameters every authenticator app supports (rfc 6238 defaults)
+const (
+	totpPeriod      = 30        // seconds per code
+	totpDigits      = 6
+	totpSecretSize  = 20        // bytes, the size of a sha1 block key
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type totpService struct {
+	issuer string       // name shown next to the account in authenticator apps
+}
+
+func NewTOTPService(issuer string) domain.TOTPService {
+	return &totpService{issuer: issuer}
+}
+
+// generate random secret
+func (totp *totpService) GenerateSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// key uri format read by google authenticator and compatible apps
+func (totp *totpService) ProvisioningURI(secret string, account string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totp.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(totp.issuer+":"+account) + "?" + params.Encode()
+}
+
+// check code against the current time step and one step either way (clock drift)
+func (totp *totpService) Validate(secret string, code string, at time.Time) (int64, bool) {
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	step := at.Unix() / totpPeriod
+	for _, candidate := range []int64{step, step - 1, step + 1} {
+		if hmac.Equal([]byte(totpCode(key, candidate)), []byte(code)) {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+// code of a time step (hotp with dynamic truncation, rfc 4226)
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
@@ -0,0 +1,102 @@
+package infrastructure
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"github.com/gin-gonic/gin";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// key of one in-memory counter
+type usageKey struct {
+	userID    string
+	tokenID   string
+	clientID  string
+	endpoint  string
+}
+
+// in-memory api call counters, flushed to the usage repository in batches (no database write per request)
+type UsageTracker struct {
+	repo     domain.APIUsageRepository
+	mu       sync.Mutex
+	pending  map[usageKey]*domain.APIUsage
+}
+
+func NewUsageTracker(repo domain.APIUsageRepository) *UsageTracker {
+	return &UsageTracker{repo: repo, pending: map[usageKey]*domain.APIUsage{}}
+}
+
+// count one call
+func (tracker *UsageTracker) Record(usage domain.APIUsage) {
+	usage.Count = 1
+	tracker.add(usage)
+}
+
+// merge counts into the pending counter of the same key
+func (tracker *UsageTracker) add(usage domain.APIUsage) {
+
+	key := usageKey{userID: usage.UserID, tokenID: usage.TokenID, clientID: usage.ClientID, endpoint: usage.Endpoint}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	counter, ok := tracker.pending[key]
+	if !ok {
+		tracker.pending[key] = &usage
+		return
+	}
+	counter.Count += usage.Count
+	if usage.LastUsedAt.After(counter.LastUsedAt) {
+		counter.LastUsedAt = usage.LastUsedAt
+	}
+}
+
+// write pending counts (they are kept for the next flush when the write fails)
+func (tracker *UsageTracker) Flush(ctx context.Context) error {
+
+	tracker.mu.Lock()
+	pending := tracker.pending
+	tracker.pending = map[usageKey]*domain.APIUsage{}
+	tracker.mu.Unlock()
+
+	usage := make([]domain.APIUsage, 0, len(pending))
+	for _, counter := range pending {
+		usage = append(usage, *counter)
+	}
+	if err := tracker.repo.AddUsage(ctx, usage); err != nil {
+		for _, counter := range usage {
+			tracker.add(counter)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// usage tracking handler (must run after the auth handler, calls without a user are not counted)
+// a nil tracker disables counting
+func TrackUsage(tracker *UsageTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		c.Next()
+
+		if tracker == nil {
+			return
+		}
+		userID := c.GetString("userID")
+		if userID == "" || c.FullPath() == "" {
+			return
+		}
+		clientID, _ := c.Get("clientID")
+		client, _ := clientID.(string)
+		tracker.Record(domain.APIUsage{
+			UserID:      userID,
+			TokenID:     c.GetString("tokenID"),
+			ClientID:    client,
+			Endpoint:    c.Request.Method + " " + RoutePath(c),
+			LastUsedAt:  time.Now().UTC(),
+		})
+	}
+}
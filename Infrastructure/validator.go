@@ -0,0 +1,122 @@
+package infrastructure
+
+// imports
+import (
+	"encoding/json";
+	"errors";
+	"fmt";
+	"io";
+	"reflect";
+	"strings";
+	"time";
+	"unicode";
+	"github.com/gin-gonic/gin/binding";
+	"github.com/go-playground/validator/v10";
+)
+
+// field level validation error returned to clients
+type FieldError struct {
+	Field    string    `json:"field"`        // json name of the invalid field
+	Message  string    `json:"message"`      // what is wrong with it
+}
+
+// register custom validation rules on gin's validator
+func RegisterValidators() error {
+
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	// report json field names instead of go struct field names
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	return validate.RegisterValidation("strongpassword", strongPassword)
+}
+
+// strong password rule: at least 8 characters with upper case, lower case, digit and symbol
+func strongPassword(fl validator.FieldLevel) bool {
+
+	password := fl.Field().String()
+	if len(password) < 8 {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	return upper && lower && digit && symbol
+}
+
+// translate binding error into field level errors
+func BindingErrors(err error) []FieldError {
+
+	// validation rule failures
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrs := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fieldErrs = append(fieldErrs, FieldError{Field: fieldErr.Field(), Message: validationMessage(fieldErr)})
+		}
+		return fieldErrs
+	}
+
+	// wrong json type for a field
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}}
+	}
+
+	// dates are the only time values in payloads
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return []FieldError{{Field: "due_date", Message: "must be an ISO 8601 date like 2025-07-22T00:00:00Z"}}
+	}
+
+	// malformed or empty body
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) {
+		return []FieldError{{Field: "body", Message: "must be valid JSON"}}
+	}
+
+	return []FieldError{{Field: "body", Message: err.Error()}}
+}
+
+// human readable message for a failed rule
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "required"
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fieldErr.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fieldErr.Param())
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "hexcolor":
+		return "must be a hex color like #ff0000"
+	case "strongpassword":
+		return "must be at least 8 characters and include upper and lower case letters, a digit and a symbol"
+	default:
+		return "failed " + fieldErr.Tag() + " validation"
+	}
+}
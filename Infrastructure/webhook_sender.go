@@ -0,0 +1,59 @@
+package infrastructure
+
+// imports
+import (
+	"bytes";
+	"context";
+	"crypto/hmac";
+	"crypto/sha256";
+	"encoding/hex";
+	"fmt";
+	"io";
+	"net/http";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// header carrying the hmac-sha256 of the body, keyed with the webhook secret
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// http webhook sender (posts signed json)
+type httpWebhookSender struct {
+	client  *http.Client
+}
+
+func NewWebhookSender(timeout time.Duration) domain.WebhookSender {
+	return &httpWebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+func (webhookSender *httpWebhookSender) Send(ctx context.Context, url string, secret string, payload []byte) (int, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, "sha256="+signPayload(secret, payload))
+	if requestID := domain.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+
+	resp, err := webhookSender.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))        // drain so the connection is reused
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// hex hmac-sha256 of the payload
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,77 @@
+package migrations
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// index creation of one store (mongodb creates missing indexes and keeps existing ones)
+type IndexEnsurer func(ctx context.Context) error
+
+// data migrations in version order, append new ones at the end and never renumber
+// every migration must be safe to repeat: databases changed by the admin cli before versions were recorded run them again
+func Versioned(taskRepo domain.TaskRepository, userRepo domain.UserRepository) []domain.Migration {
+	return []domain.Migration{
+		{Version: 1, Name: "task priorities", Run: taskRepo.BackfillPriorities},        // default priority and sort ranks for tasks from before priorities
+		{Version: 2, Name: "task and user timestamps", Run: func(ctx context.Context) (int64, error) {
+			stampedTasks, err := taskRepo.BackfillTimestamps(ctx)
+			if err != nil {
+				return stampedTasks, err
+			}
+			stampedUsers, err := userRepo.BackfillTimestamps(ctx)
+			return stampedTasks + stampedUsers, err
+		}},
+	}
+}
+
+// bring a database up to date: missing indexes first, then the migrations not applied yet
+// stops at the first failure, migrations applied before it stay recorded
+func Run(ctx context.Context, repo domain.MigrationRepository, indexes []IndexEnsurer, migrations []domain.Migration, logger domain.Logger) ([]domain.AppliedMigration, error) {
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version <= migrations[i-1].Version {
+			return nil, domain.ErrMigrationOrder
+		}
+	}
+
+	// unique usernames, text search, ... (existing duplicates must be fixed first)
+	for _, ensure := range indexes {
+		if err := ensure(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+
+	applied, err := repo.GetApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]bool, len(applied))
+	for _, migration := range applied {
+		done[migration.Version] = true
+	}
+
+	var ran []domain.AppliedMigration
+	for _, migration := range migrations {
+		if done[migration.Version] {
+			continue
+		}
+		logger.Info(ctx, "applying migration", "version", migration.Version, "name", migration.Name)
+		documents, err := migration.Run(ctx)
+		if err != nil {
+			logger.Error(ctx, "migration failed", "version", migration.Version, "name", migration.Name, "documents", documents, "error", err)
+			return ran, err
+		}
+		record := domain.AppliedMigration{Version: migration.Version, Name: migration.Name, Documents: documents, AppliedAt: time.Now().UTC()}
+		if err := repo.RecordApplied(ctx, record); err != nil {
+			return ran, err
+		}
+		ran = append(ran, record)
+	}
+
+	return ran, nil
+}
@@ -0,0 +1,104 @@
+package migrations
+
+// imports
+import (
+	"context";
+	"crypto/rand";
+	"encoding/hex";
+	"errors";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// username of the seeded admin when none is configured
+const SeedAdminUsername = "admin"
+
+// sample tasks of a fresh database (due dates are relative to the seeding time)
+var sampleTasks = []struct {
+	title        string
+	description  string
+	status       string
+	priority     string
+	dueIn        time.Duration
+}{
+	{"Welcome to Task Management", "Open GET /tasks to see your tasks and PUT /tasks/:id to change them.", "pending", "medium", 24 * time.Hour},
+	{"Invite your team", "Create accounts for your teammates with POST /register, admins can promote them.", "pending", "high", 3 * 24 * time.Hour},
+	{"Set up your first project", "Group related tasks in a project and add its members.", "in_progress", "medium", 7 * 24 * time.Hour},
+	{"Review overdue tasks", "Tasks past their due date are flagged overdue, like this one.", "pending", "urgent", -24 * time.Hour},
+	{"Read the API documentation", "docs/api_documentation.md lists every endpoint.", "completed", "low", -2 * 24 * time.Hour},
+}
+
+// stops the scan for existing tasks at the first one
+var errTasksExist = errors.New("tasks exist")
+
+// fill an empty database for demos and local development: an admin account and a few sample tasks
+// the admin is only created without users and the tasks without tasks, so seeding twice changes nothing
+// admin is the configured account, nil creates SeedAdminUsername with a generated password (logged once)
+func Seed(ctx context.Context, userRepo domain.UserRepository, taskRepo domain.TaskRepository, pwdService domain.PasswordService, admin *domain.User, logger domain.Logger) error {
+
+	users, err := userRepo.GetUserCount(ctx)
+	if err != nil {
+		return err
+	}
+	if users == 0 {
+		generated := admin == nil
+		if generated {
+			password, err := randomPassword()
+			if err != nil {
+				return err
+			}
+			admin = &domain.User{Username: SeedAdminUsername, Password: password}
+		}
+		password := admin.Password
+		hashed, err := pwdService.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		seeded := *admin
+		seeded.Password, seeded.Role = hashed, domain.RoleAdmin
+		if err := userRepo.CreateUser(ctx, &seeded); err != nil {
+			return err
+		}
+		if generated {
+			logger.Warn(ctx, "seeded admin account, change the password after the first login", "username", seeded.Username, "password", password)
+		} else {
+			logger.Info(ctx, "seeded admin account", "username", seeded.Username)
+		}
+	}
+
+	err = taskRepo.StreamTasks(ctx, domain.TaskQuery{}, func(task *domain.Task) error {
+		return errTasksExist
+	})
+	if err == errTasksExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	tasks := make([]*domain.Task, 0, len(sampleTasks))
+	for _, sample := range sampleTasks {
+		task := &domain.Task{Title: sample.title, Description: sample.description, Status: sample.status, Priority: sample.priority, Language: "en",
+			DueDate: now.Add(sample.dueIn).Truncate(time.Hour), StatusEnteredAt: map[string]time.Time{sample.status: now}}
+		if err := task.ApplyPriority(); err != nil {
+			return err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := taskRepo.CreateTasks(ctx, tasks); err != nil {
+		return err
+	}
+	logger.Info(ctx, "seeded sample tasks", "count", len(tasks))
+
+	return nil
+}
+
+// random 24 character password
+func randomPassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
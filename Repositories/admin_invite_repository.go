@@ -0,0 +1,67 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type adminInviteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAdminInviteRepository(col *mongo.Collection) domain.AdminInviteRepository {
+	return &adminInviteRepository{collection: col}
+}
+
+// store new invite in database
+func (inviteRepo *adminInviteRepository) CreateInvite(ctx context.Context, invite *domain.AdminInvite) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if invite.ID.IsZero() {
+		invite.ID = primitive.NewObjectID()
+	}
+
+	_, err := inviteRepo.collection.InsertOne(contx, invite)
+	return err
+}
+
+// remove an unexpired invite by its hash (concurrent accepts can't both get it)
+func (inviteRepo *adminInviteRepository) ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*domain.AdminInvite, error) {
+
+	var invite domain.AdminInvite
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// expired invites may still exist until the ttl index removes them
+	err := inviteRepo.collection.FindOneAndDelete(contx, bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": now}}).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidAdminInvite
+		}
+		return nil, err
+	}
+
+	return &invite, nil        // success
+}
+
+// create ttl index (mongodb removes expired invites) and lookup index
+func (inviteRepo *adminInviteRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := inviteRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},               // token lookup
+	})
+	return err
+}
@@ -0,0 +1,147 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// one counter document per user, token, client and endpoint
+type apiUsageRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAPIUsageRepository(col *mongo.Collection) domain.APIUsageRepository {
+	return &apiUsageRepository{collection: col}
+}
+
+// add counts in one round trip (counters are created on first use)
+func (usageRepo *apiUsageRepository) AddUsage(ctx context.Context, usage []domain.APIUsage) error {
+
+	if len(usage) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	models := make([]mongo.WriteModel, 0, len(usage))
+	for _, u := range usage {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": u.UserID, "token_id": u.TokenID, "client_id": u.ClientID, "endpoint": u.Endpoint}).
+			SetUpdate(bson.M{"$inc": bson.M{"count": u.Count}, "$max": bson.M{"last_used_at": u.LastUsedAt}}).
+			SetUpsert(true))
+	}
+
+	_, err := usageRepo.collection.BulkWrite(contx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// per-endpoint usage of a token, busiest first
+func (usageRepo *apiUsageRepository) GetTokenUsage(ctx context.Context, tokenID string) ([]domain.APIUsage, error) {
+
+	var usage []domain.APIUsage
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "count", Value: -1}})
+	cursor, err := usageRepo.collection.Find(contx, bson.M{"token_id": tokenID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &usage); err != nil {
+		return nil, err
+	}
+
+	if usage == nil {
+		return []domain.APIUsage{}, nil
+	}
+
+	return usage, nil
+}
+
+// usage per user, token and client, busiest first
+func (usageRepo *apiUsageRepository) GetUsageRollup(ctx context.Context, limit int64) ([]domain.APIUsageRollup, error) {
+
+	var rollup []domain.APIUsageRollup
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":           bson.M{"user_id": "$user_id", "token_id": "$token_id", "client_id": "$client_id"},
+			"count":         bson.M{"$sum": "$count"},
+			"endpoints":     bson.M{"$sum": 1},
+			"last_used_at":  bson.M{"$max": "$last_used_at"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id": 0, "user_id": "$_id.user_id", "token_id": "$_id.token_id", "client_id": "$_id.client_id",
+			"count": 1, "endpoints": 1, "last_used_at": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := usageRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &rollup); err != nil {
+		return nil, err
+	}
+
+	if rollup == nil {
+		return []domain.APIUsageRollup{}, nil
+	}
+
+	return rollup, nil
+}
+
+// callers of the endpoints, most recent first
+func (usageRepo *apiUsageRepository) GetEndpointUsage(ctx context.Context, endpoints []string) ([]domain.APIUsage, error) {
+
+	var usage []domain.APIUsage
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}})
+	cursor, err := usageRepo.collection.Find(contx, bson.M{"endpoint": bson.M{"$in": endpoints}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &usage); err != nil {
+		return nil, err
+	}
+
+	if usage == nil {
+		return []domain.APIUsage{}, nil
+	}
+
+	return usage, nil
+}
+
+// one counter per key, token lookups are indexed
+func (usageRepo *apiUsageRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := usageRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{
+			Keys:     bson.D{{Key: "token_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}, {Key: "endpoint", Value: 1}},
+			Options:  options.Index().SetUnique(true),
+		},
+	})
+	return err
+}
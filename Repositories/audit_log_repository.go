@@ -0,0 +1,79 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type auditLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository(col *mongo.Collection) domain.AuditLogRepository {
+	return &auditLogRepository{collection: col}
+}
+
+// store new audit log entry in database
+func (auditRepo *auditLogRepository) RecordEntry(ctx context.Context, entry *domain.AuditLogEntry) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := auditRepo.collection.InsertOne(contx, entry)
+	return err
+}
+
+// find audit log entries matching filter, newest first
+func (auditRepo *auditLogRepository) GetEntries(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLogEntry, error) {
+
+	var entries []domain.AuditLogEntry
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	timeRange := bson.M{}
+	if !filter.From.IsZero() {
+		timeRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timeRange["$lte"] = filter.To
+	}
+	if len(timeRange) > 0 {
+		query["timestamp"] = timeRange
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := auditRepo.collection.Find(contx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		return []domain.AuditLogEntry{}, nil
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,246 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"crypto/sha256";
+	"encoding/hex";
+	"encoding/json";
+	"sync/atomic";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const (
+	taskCacheKeyPrefix     = "taskmanager:tasks:"                   // prefix of every task cache key
+	taskCacheGenerationKey = taskCacheKeyPrefix + "generation"      // bumped on every write, old entries are never read again and expire
+)
+
+// cache-aside task repository: GetTaskByID and GetAllTasks are served from the cache,
+// any write moves all instances to a new key generation so no stale entry is read after it
+// cache failures never fail a request, reads fall back to the database
+type cachedTaskRepository struct {
+	domain.TaskRepository                         // writes and uncached reads
+	reader     domain.TaskReader                  // source of cached reads (query side)
+	cache      domain.Cache
+	ttl        time.Duration
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	errors     atomic.Uint64
+}
+
+func NewCachedTaskRepository(repo domain.TaskRepository, reader domain.TaskReader, cache domain.Cache, ttl time.Duration) domain.CachedTaskRepository {
+	return &cachedTaskRepository{TaskRepository: repo, reader: reader, cache: cache, ttl: ttl}
+}
+
+func (taskRepo *cachedTaskRepository) GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	key, ok := taskRepo.key(ctx, "id:"+taskID)
+	if ok {
+		var task domain.Task
+		if taskRepo.lookup(ctx, key, &task) {
+			return &task, nil
+		}
+	}
+
+	task, err := taskRepo.reader.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		taskRepo.store(ctx, key, task)
+	}
+
+	return task, nil
+}
+
+func (taskRepo *cachedTaskRepository) GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
+
+	// one entry per distinct query
+	encoded, _ := json.Marshal(query)
+	sum := sha256.Sum256(encoded)
+	key, ok := taskRepo.key(ctx, "list:"+hex.EncodeToString(sum[:16]))
+	if ok {
+		var cached taskList
+		if taskRepo.lookup(ctx, key, &cached) {
+			if cached.Tasks == nil {
+				return []domain.Task{}, nil
+			}
+			return cached.Tasks, nil
+		}
+	}
+
+	tasks, err := taskRepo.reader.GetAllTasks(ctx, query)
+	if err != nil {
+		return tasks, err        // partial results are not cached
+	}
+	if ok {
+		taskRepo.store(ctx, key, taskList{Tasks: tasks})
+	}
+
+	return tasks, nil
+}
+
+func (taskRepo *cachedTaskRepository) GetSubtasks(ctx context.Context, parentID string) ([]domain.Task, error) {
+	return taskRepo.reader.GetSubtasks(ctx, parentID)
+}
+
+// streams are too large to cache
+func (taskRepo *cachedTaskRepository) StreamTasks(ctx context.Context, query domain.TaskQuery, fn func(task *domain.Task) error) error {
+	return taskRepo.reader.StreamTasks(ctx, query, fn)
+}
+
+func (taskRepo *cachedTaskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.CreateTask(ctx, task)
+}
+
+func (taskRepo *cachedTaskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.CreateTasks(ctx, tasks)
+}
+
+func (taskRepo *cachedTaskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.RestoreTasks(ctx, tasks)
+}
+
+func (taskRepo *cachedTaskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.UpdateTask(ctx, taskID, taskUpdate)
+}
+
+func (taskRepo *cachedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.DeleteTask(ctx, taskID)
+}
+
+func (taskRepo *cachedTaskRepository) DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.DeleteTasks(ctx, taskIDs)
+}
+
+func (taskRepo *cachedTaskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.AddTag(ctx, taskID, tag)
+}
+
+func (taskRepo *cachedTaskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.RemoveTag(ctx, taskID, tag)
+}
+
+func (taskRepo *cachedTaskRepository) RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.RenameTagOnAll(ctx, oldTag, newTag)
+}
+
+func (taskRepo *cachedTaskRepository) RemoveTagFromAll(ctx context.Context, tag string) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.RemoveTagFromAll(ctx, tag)
+}
+
+func (taskRepo *cachedTaskRepository) MarkReminderSent(ctx context.Context, taskID primitive.ObjectID, sentAt time.Time) error {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.MarkReminderSent(ctx, taskID, sentAt)
+}
+
+func (taskRepo *cachedTaskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+	defer taskRepo.invalidate(ctx)
+	return taskRepo.TaskRepository.SetRecurrence(ctx, taskID, rule)
+}
+
+func (taskRepo *cachedTaskRepository) AdvanceRecurrence(ctx context.Context, taskID primitive.ObjectID, at time.Time) (bool, error) {
+	advanced, err := taskRepo.TaskRepository.AdvanceRecurrence(ctx, taskID, at)
+	if advanced {
+		taskRepo.invalidate(ctx)
+	}
+	return advanced, err
+}
+
+// periodic job, only invalidate when a flag actually changed
+func (taskRepo *cachedTaskRepository) UpdateOverdueFlags(ctx context.Context, now time.Time) (int64, error) {
+	updated, err := taskRepo.TaskRepository.UpdateOverdueFlags(ctx, now)
+	if updated > 0 {
+		taskRepo.invalidate(ctx)
+	}
+	return updated, err
+}
+
+func (taskRepo *cachedTaskRepository) BackfillPriorities(ctx context.Context) (int64, error) {
+	updated, err := taskRepo.TaskRepository.BackfillPriorities(ctx)
+	if updated > 0 {
+		taskRepo.invalidate(ctx)
+	}
+	return updated, err
+}
+
+// hit and miss counters since startup
+func (taskRepo *cachedTaskRepository) CacheStats() domain.CacheStats {
+	stats := domain.CacheStats{Enabled: true, Hits: taskRepo.hits.Load(), Misses: taskRepo.misses.Load(), Errors: taskRepo.errors.Load()}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// cached task list (bson needs a document at the top level)
+type taskList struct {
+	Tasks []domain.Task `bson:"tasks"`
+}
+
+// key of an entry in the current generation (false when the cache is unavailable)
+func (taskRepo *cachedTaskRepository) key(ctx context.Context, suffix string) (string, bool) {
+
+	generation := "0"        // no write seen yet
+	value, err := taskRepo.cache.Get(ctx, taskCacheGenerationKey)
+	if err == nil {
+		generation = string(value)
+	} else if err != domain.ErrCacheMiss {
+		taskRepo.errors.Add(1)
+		taskRepo.misses.Add(1)
+		return "", false
+	}
+
+	return taskCacheKeyPrefix + generation + ":" + suffix, true
+}
+
+// read and decode an entry, counting the hit or miss
+func (taskRepo *cachedTaskRepository) lookup(ctx context.Context, key string, out interface{}) bool {
+
+	value, err := taskRepo.cache.Get(ctx, key)
+	if err == nil {
+		err = bson.Unmarshal(value, out)
+	}
+	if err != nil {
+		if err != domain.ErrCacheMiss {
+			taskRepo.errors.Add(1)
+		}
+		taskRepo.misses.Add(1)
+		return false
+	}
+
+	taskRepo.hits.Add(1)
+	return true
+}
+
+// encode and write an entry
+func (taskRepo *cachedTaskRepository) store(ctx context.Context, key string, value interface{}) {
+
+	encoded, err := bson.Marshal(value)
+	if err == nil {
+		err = taskRepo.cache.Set(ctx, key, encoded, taskRepo.ttl)
+	}
+	if err != nil {
+		taskRepo.errors.Add(1)
+	}
+}
+
+// start a new key generation after a write (entries of older generations expire with their ttl)
+func (taskRepo *cachedTaskRepository) invalidate(ctx context.Context) {
+	if _, err := taskRepo.cache.Incr(context.WithoutCancel(ctx), taskCacheGenerationKey); err != nil {
+		taskRepo.errors.Add(1)        // readers may see stale entries until their ttl runs out
+	}
+}
@@ -0,0 +1,237 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// task repository recording who changed which field of a task and when
+// server maintained fields (overdue flag, reminder sent time, backfilled priorities, series bookkeeping) are not recorded
+type changeTrackingTaskRepository struct {
+	domain.TaskRepository                                // tracked repository (reads pass through)
+	changes                domain.TaskChangeRepository
+}
+
+func NewChangeTrackingTaskRepository(repo domain.TaskRepository, changes domain.TaskChangeRepository) domain.TaskRepository {
+	return &changeTrackingTaskRepository{TaskRepository: repo, changes: changes}
+}
+
+func (taskRepo *changeTrackingTaskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+
+	created, err := taskRepo.TaskRepository.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.changes.AppendChanges(ctx, creationChanges(ctx, created)); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (taskRepo *changeTrackingTaskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if err := taskRepo.TaskRepository.CreateTasks(ctx, tasks); err != nil {
+		return err
+	}
+
+	changes := []domain.TaskChange{}
+	for _, task := range tasks {
+		changes = append(changes, creationChanges(ctx, task)...)
+	}
+
+	return taskRepo.changes.AppendChanges(ctx, changes)
+}
+
+func (taskRepo *changeTrackingTaskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if err := taskRepo.TaskRepository.RestoreTasks(ctx, tasks); err != nil {
+		return err
+	}
+
+	changes := []domain.TaskChange{}
+	for _, task := range tasks {
+		changes = append(changes, taskFieldChanges(ctx, domain.TaskEventRestored, &domain.Task{ID: task.ID}, task)...)
+	}
+
+	return taskRepo.changes.AppendChanges(ctx, changes)
+}
+
+func (taskRepo *changeTrackingTaskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
+	return taskRepo.track(ctx, taskID, func() (*domain.Task, error) {
+		return taskRepo.TaskRepository.UpdateTask(ctx, taskID, taskUpdate)
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.track(ctx, taskID, func() (*domain.Task, error) {
+		return taskRepo.TaskRepository.AddTag(ctx, taskID, tag)
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.track(ctx, taskID, func() (*domain.Task, error) {
+		return taskRepo.TaskRepository.RemoveTag(ctx, taskID, tag)
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+	return taskRepo.track(ctx, taskID, func() (*domain.Task, error) {
+		return taskRepo.TaskRepository.SetRecurrence(ctx, taskID, rule)
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return domain.ErrInvalidTaskID
+	}
+
+	if err := taskRepo.TaskRepository.DeleteTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	return taskRepo.changes.AppendChanges(ctx, []domain.TaskChange{taskDeletion(ctx, objID)})
+}
+
+func (taskRepo *changeTrackingTaskRepository) DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error {
+
+	if err := taskRepo.TaskRepository.DeleteTasks(ctx, taskIDs); err != nil {
+		return err
+	}
+
+	changes := make([]domain.TaskChange, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		changes = append(changes, taskDeletion(ctx, taskID))
+	}
+
+	return taskRepo.changes.AppendChanges(ctx, changes)
+}
+
+func (taskRepo *changeTrackingTaskRepository) RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error {
+	return taskRepo.trackTagOnAll(ctx, oldTag, func() error {
+		return taskRepo.TaskRepository.RenameTagOnAll(ctx, oldTag, newTag)
+	}, func(tag string) []string {
+		if tag == oldTag {
+			return []string{newTag}
+		}
+		return []string{tag}
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) RemoveTagFromAll(ctx context.Context, tag string) error {
+	return taskRepo.trackTagOnAll(ctx, tag, func() error {
+		return taskRepo.TaskRepository.RemoveTagFromAll(ctx, tag)
+	}, func(existing string) []string {
+		if existing == tag {
+			return nil
+		}
+		return []string{existing}
+	})
+}
+
+func (taskRepo *changeTrackingTaskRepository) EnsureIndexes(ctx context.Context) error {
+
+	if err := taskRepo.TaskRepository.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+
+	return taskRepo.changes.EnsureIndexes(ctx)
+}
+
+// apply a change to one task and record the fields it changed
+func (taskRepo *changeTrackingTaskRepository) track(ctx context.Context, taskID string, change func() (*domain.Task, error)) (*domain.Task, error) {
+
+	before, err := taskRepo.TaskRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := change()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.changes.AppendChanges(ctx, taskFieldChanges(ctx, domain.TaskEventUpdated, before, after)); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// apply a change to the tags of every task carrying a tag and record the new tags
+func (taskRepo *changeTrackingTaskRepository) trackTagOnAll(ctx context.Context, tag string, change func() error, mapTag func(tag string) []string) error {
+
+	// tasks that carry the tag before the change
+	affected, err := taskRepo.TaskRepository.GetAllTasks(ctx, domain.TaskQuery{Labels: []string{tag}})
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	changes := []domain.TaskChange{}
+	for i := range affected {
+		before := affected[i]
+		after := before
+		after.Tags = []string{}
+		for _, existing := range before.Tags {
+			after.Tags = append(after.Tags, mapTag(existing)...)
+		}
+		changes = append(changes, taskFieldChanges(ctx, domain.TaskEventUpdated, &before, &after)...)
+	}
+
+	return taskRepo.changes.AppendChanges(ctx, changes)
+}
+
+// every field a task was created with
+func creationChanges(ctx context.Context, created *domain.Task) []domain.TaskChange {
+	changes := taskFieldChanges(ctx, domain.TaskEventCreated, &domain.Task{ID: created.ID}, created)
+	for i := range changes {
+		changes[i].OldValue = nil
+	}
+	return changes
+}
+
+// history entries for the fields that differ between two states of a task
+func taskFieldChanges(ctx context.Context, action string, before *domain.Task, after *domain.Task) []domain.TaskChange {
+
+	changes := []domain.TaskChange{}
+	for _, diff := range domain.DiffTasks(before, after) {
+		change := taskChange(ctx, action, after.ID)
+		change.Field = diff.Field
+		change.OldValue = diff.OldValue
+		change.NewValue = diff.NewValue
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// deletion of a task
+func taskDeletion(ctx context.Context, taskID primitive.ObjectID) domain.TaskChange {
+	return taskChange(ctx, domain.TaskEventDeleted, taskID)
+}
+
+// change made by the actor on the request context
+func taskChange(ctx context.Context, action string, taskID primitive.ObjectID) domain.TaskChange {
+
+	change := domain.TaskChange{
+		TaskID:     taskID,
+		Action:     action,
+		Timestamp:  time.Now().UTC(),
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		change.ActorID = actor.ID
+		change.Actor = actor.Username
+	}
+
+	return change
+}
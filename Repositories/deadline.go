@@ -0,0 +1,18 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+)
+
+// timeout for queries made without a request deadline (background jobs, startup)
+const defaultQueryTimeout = 5 * time.Second
+
+// derive query context honoring the caller's deadline
+func withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, defaultQueryTimeout)
+}
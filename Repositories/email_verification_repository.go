@@ -0,0 +1,75 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type emailVerificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailVerificationRepository(col *mongo.Collection) domain.EmailVerificationRepository {
+	return &emailVerificationRepository{collection: col}
+}
+
+// store new verification token in database
+func (verificationRepo *emailVerificationRepository) CreateToken(ctx context.Context, token *domain.EmailVerificationToken) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := verificationRepo.collection.InsertOne(contx, token)
+	return err
+}
+
+// find verification token by its hash
+func (verificationRepo *emailVerificationRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+
+	var token domain.EmailVerificationToken
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := verificationRepo.collection.FindOne(contx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	return &token, nil        // success
+}
+
+// remove every verification token of a user
+func (verificationRepo *emailVerificationRepository) DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := verificationRepo.collection.DeleteMany(contx, bson.M{"user_id": userID})
+	return err
+}
+
+// create ttl index (mongodb removes expired tokens) and lookup index
+func (verificationRepo *emailVerificationRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := verificationRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},               // token lookup
+	})
+	return err
+}
@@ -0,0 +1,259 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// task repository storing every change as an event next to the current state projection
+// the server maintained overdue flag is derived from due date and status, so its refreshes are not recorded
+type eventSourcedTaskRepository struct {
+	domain.TaskRepository                                  // current state projection (reads pass through)
+	history                domain.TaskHistoryRepository
+}
+
+func NewEventSourcedTaskRepository(projection domain.TaskRepository, history domain.TaskHistoryRepository) domain.TaskRepository {
+	return &eventSourcedTaskRepository{TaskRepository: projection, history: history}
+}
+
+func (taskRepo *eventSourcedTaskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+
+	created, err := taskRepo.TaskRepository.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventCreated, created.ID, created)); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (taskRepo *eventSourcedTaskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if err := taskRepo.TaskRepository.CreateTasks(ctx, tasks); err != nil {
+		return err
+	}
+
+	events := make([]domain.TaskHistoryEvent, 0, len(tasks))
+	for _, task := range tasks {
+		events = append(events, taskHistoryEvent(ctx, domain.TaskEventCreated, task.ID, task))
+	}
+
+	return taskRepo.append(ctx, events...)
+}
+
+func (taskRepo *eventSourcedTaskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if err := taskRepo.TaskRepository.RestoreTasks(ctx, tasks); err != nil {
+		return err
+	}
+
+	events := make([]domain.TaskHistoryEvent, 0, len(tasks))
+	for _, task := range tasks {
+		events = append(events, taskHistoryEvent(ctx, domain.TaskEventRestored, task.ID, task))
+	}
+
+	return taskRepo.append(ctx, events...)
+}
+
+func (taskRepo *eventSourcedTaskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
+
+	updated, err := taskRepo.TaskRepository.UpdateTask(ctx, taskID, taskUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, updated.ID, updated)); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *eventSourcedTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return domain.ErrInvalidTaskID
+	}
+
+	if err := taskRepo.TaskRepository.DeleteTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	return taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventDeleted, objID, nil))
+}
+
+func (taskRepo *eventSourcedTaskRepository) DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error {
+
+	if err := taskRepo.TaskRepository.DeleteTasks(ctx, taskIDs); err != nil {
+		return err
+	}
+
+	events := make([]domain.TaskHistoryEvent, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		events = append(events, taskHistoryEvent(ctx, domain.TaskEventDeleted, taskID, nil))
+	}
+
+	return taskRepo.append(ctx, events...)
+}
+
+func (taskRepo *eventSourcedTaskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+
+	updated, err := taskRepo.TaskRepository.AddTag(ctx, taskID, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, updated.ID, updated)); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *eventSourcedTaskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+
+	updated, err := taskRepo.TaskRepository.RemoveTag(ctx, taskID, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, updated.ID, updated)); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *eventSourcedTaskRepository) RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error {
+
+	// tasks that carry the tag before the rename
+	affected, err := taskRepo.TaskRepository.GetAllTasks(ctx, domain.TaskQuery{Labels: []string{oldTag}})
+	if err != nil {
+		return err
+	}
+
+	if err := taskRepo.TaskRepository.RenameTagOnAll(ctx, oldTag, newTag); err != nil {
+		return err
+	}
+
+	events := make([]domain.TaskHistoryEvent, 0, len(affected))
+	for i := range affected {
+		task := affected[i]
+		for j, tag := range task.Tags {
+			if tag == oldTag {
+				task.Tags[j] = newTag
+			}
+		}
+		events = append(events, taskHistoryEvent(ctx, domain.TaskEventUpdated, task.ID, &task))
+	}
+
+	return taskRepo.append(ctx, events...)
+}
+
+func (taskRepo *eventSourcedTaskRepository) RemoveTagFromAll(ctx context.Context, tag string) error {
+
+	// tasks that carry the tag before it is removed
+	affected, err := taskRepo.TaskRepository.GetAllTasks(ctx, domain.TaskQuery{Labels: []string{tag}})
+	if err != nil {
+		return err
+	}
+
+	if err := taskRepo.TaskRepository.RemoveTagFromAll(ctx, tag); err != nil {
+		return err
+	}
+
+	events := make([]domain.TaskHistoryEvent, 0, len(affected))
+	for i := range affected {
+		task := affected[i]
+		tags := []string{}
+		for _, existing := range task.Tags {
+			if existing != tag {
+				tags = append(tags, existing)
+			}
+		}
+		task.Tags = tags
+		events = append(events, taskHistoryEvent(ctx, domain.TaskEventUpdated, task.ID, &task))
+	}
+
+	return taskRepo.append(ctx, events...)
+}
+
+func (taskRepo *eventSourcedTaskRepository) MarkReminderSent(ctx context.Context, taskID primitive.ObjectID, sentAt time.Time) error {
+
+	if err := taskRepo.TaskRepository.MarkReminderSent(ctx, taskID, sentAt); err != nil {
+		return err
+	}
+
+	updated, err := taskRepo.TaskRepository.GetTaskByID(ctx, taskID.Hex())
+	if err != nil {
+		return err
+	}
+
+	return taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, taskID, updated))
+}
+
+func (taskRepo *eventSourcedTaskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+
+	updated, err := taskRepo.TaskRepository.SetRecurrence(ctx, taskID, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, updated.ID, updated)); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *eventSourcedTaskRepository) AdvanceRecurrence(ctx context.Context, taskID primitive.ObjectID, at time.Time) (bool, error) {
+
+	advanced, err := taskRepo.TaskRepository.AdvanceRecurrence(ctx, taskID, at)
+	if err != nil || !advanced {
+		return advanced, err
+	}
+
+	updated, err := taskRepo.TaskRepository.GetTaskByID(ctx, taskID.Hex())
+	if err != nil {
+		return true, err
+	}
+
+	return true, taskRepo.append(ctx, taskHistoryEvent(ctx, domain.TaskEventUpdated, taskID, updated))
+}
+
+func (taskRepo *eventSourcedTaskRepository) EnsureIndexes(ctx context.Context) error {
+
+	if err := taskRepo.TaskRepository.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+
+	return taskRepo.history.EnsureIndexes(ctx)
+}
+
+// store events of changes already applied to the projection
+func (taskRepo *eventSourcedTaskRepository) append(ctx context.Context, events ...domain.TaskHistoryEvent) error {
+	return taskRepo.history.AppendEvents(ctx, events)
+}
+
+// build history event for a change made by the actor on the request context
+func taskHistoryEvent(ctx context.Context, eventType string, taskID primitive.ObjectID, task *domain.Task) domain.TaskHistoryEvent {
+
+	event := domain.TaskHistoryEvent{
+		TaskID:     taskID,
+		Type:       eventType,
+		Task:       task,
+		Timestamp:  time.Now().UTC(),
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID = actor.ID
+	}
+
+	return event
+}
@@ -0,0 +1,86 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type idempotencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewIdempotencyRepository(col *mongo.Collection) domain.IdempotencyRepository {
+	return &idempotencyRepository{collection: col}
+}
+
+// store a new key, or return the caller's existing record for it
+func (idempotencyRepo *idempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"caller": record.Caller, "key": record.Key}
+
+	// expired records wait for the ttl monitor, the key is free again already
+	_, err := idempotencyRepo.collection.DeleteOne(contx, bson.M{"caller": record.Caller, "key": record.Key, "expires_at": bson.M{"$lte": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+	_, err = idempotencyRepo.collection.InsertOne(contx, record)
+	if err == nil {
+		return nil, nil        // first request with the key
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	var existing domain.IdempotencyRecord
+	if err := idempotencyRepo.collection.FindOne(contx, filter).Decode(&existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// save the response of the first request
+func (idempotencyRepo *idempotencyRepository) Complete(ctx context.Context, recordID primitive.ObjectID, status int, contentType string, body []byte) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"status": status, "content_type": contentType, "body": body}}
+	_, err := idempotencyRepo.collection.UpdateByID(contx, recordID, update)
+	return err
+}
+
+// drop a key so the request can be retried
+func (idempotencyRepo *idempotencyRepository) Release(ctx context.Context, recordID primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := idempotencyRepo.collection.DeleteOne(contx, bson.M{"_id": recordID})
+	return err
+}
+
+// create indexes if missing
+func (idempotencyRepo *idempotencyRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := idempotencyRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "caller", Value: 1}, {Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},       // one record per caller and key
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},                  // expire at expires_at
+	})
+	return err
+}
@@ -0,0 +1,405 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"fmt";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type integrityRepository struct {
+	taskCollection     *mongo.Collection
+	projectCollection  *mongo.Collection
+	labelCollection    *mongo.Collection
+	orgCollection      *mongo.Collection
+	userCollection     *mongo.Collection
+	tokenCollection    *mongo.Collection
+	sessionCollection  *mongo.Collection
+	myDayCollection    *mongo.Collection
+}
+
+func NewIntegrityRepository(taskCol *mongo.Collection, projectCol *mongo.Collection, labelCol *mongo.Collection, orgCol *mongo.Collection,
+	userCol *mongo.Collection, tokenCol *mongo.Collection, sessionCol *mongo.Collection, myDayCol *mongo.Collection) domain.IntegrityRepository {
+	return &integrityRepository{taskCollection: taskCol, projectCollection: projectCol, labelCollection: labelCol, orgCollection: orgCol,
+		userCollection: userCol, tokenCollection: tokenCol, sessionCollection: sessionCol, myDayCollection: myDayCol}
+}
+
+func (integrityRepo *integrityRepository) FindIssues(ctx context.Context, check string, now time.Time) ([]domain.IntegrityIssue, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	switch check {
+	case domain.IntegritySubtaskMissingParent:
+		return integrityRepo.subtasksMissingParent(contx)
+	case domain.IntegrityTaskMissingProject:
+		projectIDs, err := integrityRepo.distinct(contx, integrityRepo.projectCollection, "_id")
+		if err != nil {
+			return nil, err
+		}
+		return findIssues(contx, integrityRepo.taskCollection, bson.M{"project_id": bson.M{"$exists": true, "$ne": nil, "$nin": projectIDs}}, "project_id")
+	case domain.IntegrityTaskMissingLabel:
+		return integrityRepo.tasksMissingLabel(contx)
+	case domain.IntegrityMissingOrganization:
+		return integrityRepo.missingOrganization(contx)
+	case domain.IntegrityMemberMissingUser:
+		return integrityRepo.membersMissingUser(contx)
+	case domain.IntegrityTokenMissingUser:
+		userIDs, err := integrityRepo.distinct(contx, integrityRepo.userCollection, "_id")
+		if err != nil {
+			return nil, err
+		}
+		return findIssues(contx, integrityRepo.tokenCollection, bson.M{"user_id": bson.M{"$nin": userIDs}}, "user_id")
+	case domain.IntegritySessionMissingUser:
+		userIDs, err := integrityRepo.userHexIDs(contx)
+		if err != nil {
+			return nil, err
+		}
+		return findIssues(contx, integrityRepo.sessionCollection, bson.M{"user_id": bson.M{"$nin": userIDs}}, "user_id")
+	case domain.IntegrityDayPlanMissingTask:
+		return integrityRepo.dayPlansMissingTask(contx)
+	case domain.IntegrityPriorityRankDrift:
+		var issues []domain.IntegrityIssue
+		for priority, rank := range domain.TaskPriorities {
+			found, err := findIssues(contx, integrityRepo.taskCollection, bson.M{"priority": priority, "priority_rank": bson.M{"$ne": rank}}, "priority_rank")
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, found...)
+		}
+		return issues, nil
+	case domain.IntegrityOverdueFlagDrift:
+		return findIssues(contx, integrityRepo.taskCollection, overdueDriftFilter(now), "is_overdue")
+	}
+
+	return nil, domain.ErrUnknownIntegrityCheck
+}
+
+func (integrityRepo *integrityRepository) RepairIssues(ctx context.Context, check string, issues []domain.IntegrityIssue, now time.Time) (int64, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	ids := issueIDs(issues)
+	switch check {
+	case domain.IntegritySubtaskMissingParent:
+		return updateMany(contx, integrityRepo.taskCollection, bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"$unset": bson.M{"parent_id": ""}, "$set": bson.M{"updated_at": storedNow()}})
+	case domain.IntegrityTaskMissingProject:
+		return updateMany(contx, integrityRepo.taskCollection, bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"$unset": bson.M{"project_id": ""}, "$set": bson.M{"updated_at": storedNow()}})
+	case domain.IntegrityTaskMissingLabel:
+		var repaired int64
+		for _, issue := range issues {
+			taskID, _ := primitive.ObjectIDFromHex(issue.DocumentID)
+			changed, err := updateMany(contx, integrityRepo.taskCollection, bson.M{"_id": taskID},
+				bson.M{"$pull": bson.M{"tags": issue.Reference}, "$set": bson.M{"updated_at": storedNow()}})
+			if err != nil {
+				return repaired, err
+			}
+			repaired += changed
+		}
+		return repaired, nil
+	case domain.IntegrityMemberMissingUser:
+		var repaired int64
+		for _, issue := range issues {
+			projectID, _ := primitive.ObjectIDFromHex(issue.DocumentID)
+			changed, err := updateMany(contx, integrityRepo.projectCollection, bson.M{"_id": projectID},
+				bson.M{"$pull": bson.M{"members": bson.M{"user_id": issue.Reference}}})
+			if err != nil {
+				return repaired, err
+			}
+			repaired += changed
+		}
+		return repaired, nil
+	case domain.IntegrityTokenMissingUser:
+		result, err := integrityRepo.tokenCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return 0, err
+		}
+		return result.DeletedCount, nil
+	case domain.IntegritySessionMissingUser:
+		result, err := integrityRepo.sessionCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return 0, err
+		}
+		return result.DeletedCount, nil
+	case domain.IntegrityDayPlanMissingTask:
+		var repaired int64
+		for _, issue := range issues {
+			taskID, _ := primitive.ObjectIDFromHex(issue.Reference)
+			changed, err := updateMany(contx, integrityRepo.myDayCollection, bson.M{"_id": issue.DocumentID},
+				bson.M{"$pull": bson.M{"task_ids": taskID}, "$set": bson.M{"updated_at": now}})
+			if err != nil {
+				return repaired, err
+			}
+			repaired += changed
+		}
+		return repaired, nil
+	case domain.IntegrityPriorityRankDrift:
+		var repaired int64
+		for priority, rank := range domain.TaskPriorities {
+			changed, err := updateMany(contx, integrityRepo.taskCollection, bson.M{"_id": bson.M{"$in": ids}, "priority": priority, "priority_rank": bson.M{"$ne": rank}},
+				bson.M{"$set": bson.M{"priority_rank": rank, "updated_at": storedNow()}})
+			if err != nil {
+				return repaired, err
+			}
+			repaired += changed
+		}
+		return repaired, nil
+	case domain.IntegrityOverdueFlagDrift:
+		// same rules as the overdue job, only for the tasks found
+		marked, err := updateMany(contx, integrityRepo.taskCollection,
+			bson.M{"_id": bson.M{"$in": ids}, "is_overdue": bson.M{"$ne": true}, "due_date": bson.M{"$lt": now}, "status": bson.M{"$ne": "completed"}},
+			bson.M{"$set": bson.M{"is_overdue": true, "updated_at": storedNow()}})
+		if err != nil {
+			return 0, err
+		}
+		cleared, err := updateMany(contx, integrityRepo.taskCollection,
+			bson.M{"_id": bson.M{"$in": ids}, "is_overdue": true, "$or": bson.A{bson.M{"due_date": bson.M{"$gte": now}}, bson.M{"status": "completed"}}},
+			bson.M{"$set": bson.M{"is_overdue": false, "updated_at": storedNow()}})
+		return marked + cleared, err
+	}
+
+	return 0, domain.ErrUnknownIntegrityCheck
+}
+
+// subtasks whose parent no longer exists
+func (integrityRepo *integrityRepository) subtasksMissingParent(ctx context.Context) ([]domain.IntegrityIssue, error) {
+
+	cursor, err := integrityRepo.taskCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"parent_id": bson.M{"$exists": true, "$ne": nil}}}},
+		{{Key: "$lookup", Value: bson.M{"from": integrityRepo.taskCollection.Name(), "localField": "parent_id", "foreignField": "_id", "as": "parent"}}},
+		{{Key: "$match", Value: bson.M{"parent": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"parent_id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeIssues(ctx, cursor, integrityRepo.taskCollection.Name(), "parent_id")
+}
+
+// tags of tasks without a label of that name
+func (integrityRepo *integrityRepository) tasksMissingLabel(ctx context.Context) ([]domain.IntegrityIssue, error) {
+
+	names, err := integrityRepo.distinct(ctx, integrityRepo.labelCollection, "name")
+	if err != nil {
+		return nil, err
+	}
+	known := map[interface{}]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+
+	cursor, err := integrityRepo.taskCollection.Find(ctx, bson.M{"tags": bson.M{"$elemMatch": bson.M{"$nin": names}}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var issues []domain.IntegrityIssue
+	for cursor.Next(ctx) {
+		var task struct {
+			ID    primitive.ObjectID  `bson:"_id"`
+			Tags  []string            `bson:"tags"`
+		}
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		for _, tag := range task.Tags {
+			if !known[tag] {
+				issues = append(issues, domain.IntegrityIssue{Collection: integrityRepo.taskCollection.Name(), DocumentID: task.ID.Hex(), Reference: tag})
+			}
+		}
+	}
+
+	return issues, cursor.Err()
+}
+
+// tasks and users of an organization that no longer exists (the default workspace has no tenant id)
+func (integrityRepo *integrityRepository) missingOrganization(ctx context.Context) ([]domain.IntegrityIssue, error) {
+
+	orgIDs, err := integrityRepo.distinct(ctx, integrityRepo.orgCollection, "_id")
+	if err != nil {
+		return nil, err
+	}
+	tenants := bson.A{""}
+	for _, orgID := range orgIDs {
+		if id, ok := orgID.(primitive.ObjectID); ok {
+			tenants = append(tenants, id.Hex())
+		}
+	}
+
+	var issues []domain.IntegrityIssue
+	for _, collection := range []*mongo.Collection{integrityRepo.taskCollection, integrityRepo.userCollection} {
+		found, err := findIssues(ctx, collection, bson.M{"tenant_id": bson.M{"$exists": true, "$nin": tenants}}, "tenant_id")
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+
+	return issues, nil
+}
+
+// project members whose account was deleted
+func (integrityRepo *integrityRepository) membersMissingUser(ctx context.Context) ([]domain.IntegrityIssue, error) {
+
+	userIDs, err := integrityRepo.userHexIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := map[interface{}]bool{}
+	for _, userID := range userIDs {
+		known[userID] = true
+	}
+
+	cursor, err := integrityRepo.projectCollection.Find(ctx, bson.M{"members.user_id": bson.M{"$nin": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var issues []domain.IntegrityIssue
+	for cursor.Next(ctx) {
+		var project domain.Project
+		if err := cursor.Decode(&project); err != nil {
+			return nil, err
+		}
+		for _, member := range project.Members {
+			if !known[member.UserID] {
+				issues = append(issues, domain.IntegrityIssue{Collection: integrityRepo.projectCollection.Name(), DocumentID: project.ID.Hex(), Reference: member.UserID})
+			}
+		}
+	}
+
+	return issues, cursor.Err()
+}
+
+// tasks in my day lists that were deleted
+func (integrityRepo *integrityRepository) dayPlansMissingTask(ctx context.Context) ([]domain.IntegrityIssue, error) {
+
+	var lists []domain.MyDay
+	cursor, err := integrityRepo.myDayCollection.Find(ctx, bson.M{"task_ids.0": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+
+	// look up every listed task once
+	listed := bson.A{}
+	for _, list := range lists {
+		for _, taskID := range list.TaskIDs {
+			listed = append(listed, taskID)
+		}
+	}
+	existing, err := integrityRepo.taskCollection.Distinct(ctx, "_id", bson.M{"_id": bson.M{"$in": listed}})
+	if err != nil {
+		return nil, err
+	}
+	known := map[interface{}]bool{}
+	for _, taskID := range existing {
+		known[taskID] = true
+	}
+
+	var issues []domain.IntegrityIssue
+	for _, list := range lists {
+		for _, taskID := range list.TaskIDs {
+			if !known[taskID] {
+				issues = append(issues, domain.IntegrityIssue{Collection: integrityRepo.myDayCollection.Name(), DocumentID: list.UserID, Reference: taskID.Hex()})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// every value of a field in a collection
+func (integrityRepo *integrityRepository) distinct(ctx context.Context, collection *mongo.Collection, field string) (bson.A, error) {
+	values, err := collection.Distinct(ctx, field, bson.M{})
+	return bson.A(values), err
+}
+
+// user ids as stored in string references
+func (integrityRepo *integrityRepository) userHexIDs(ctx context.Context) (bson.A, error) {
+	userIDs, err := integrityRepo.distinct(ctx, integrityRepo.userCollection, "_id")
+	if err != nil {
+		return nil, err
+	}
+	hexIDs := bson.A{}
+	for _, userID := range userIDs {
+		if id, ok := userID.(primitive.ObjectID); ok {
+			hexIDs = append(hexIDs, id.Hex())
+		}
+	}
+	return hexIDs, nil
+}
+
+// documents matching a filter as issues, the field holds the broken value
+func findIssues(ctx context.Context, collection *mongo.Collection, filter bson.M, field string) ([]domain.IntegrityIssue, error) {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIssues(ctx, cursor, collection.Name(), field)
+}
+
+// issues from documents with an _id and the broken field
+func decodeIssues(ctx context.Context, cursor *mongo.Cursor, collection string, field string) ([]domain.IntegrityIssue, error) {
+
+	defer cursor.Close(ctx)
+
+	var issues []domain.IntegrityIssue
+	for cursor.Next(ctx) {
+		issue := domain.IntegrityIssue{Collection: collection, DocumentID: fmt.Sprint(cursor.Current.Lookup("_id"))}
+		if id, ok := cursor.Current.Lookup("_id").ObjectIDOK(); ok {
+			issue.DocumentID = id.Hex()
+		}
+		value := cursor.Current.Lookup(field)
+		if id, ok := value.ObjectIDOK(); ok {
+			issue.Reference = id.Hex()
+		} else if text, ok := value.StringValueOK(); ok {
+			issue.Reference = text
+		} else {
+			issue.Reference = value.String()
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, cursor.Err()
+}
+
+// object ids of the documents with issues
+func issueIDs(issues []domain.IntegrityIssue) bson.A {
+	ids := bson.A{}
+	for _, issue := range issues {
+		if id, err := primitive.ObjectIDFromHex(issue.DocumentID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// tasks whose overdue flag doesn't match their due date and status
+func overdueDriftFilter(now time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"is_overdue": bson.M{"$ne": true}, "due_date": bson.M{"$lt": now}, "status": bson.M{"$ne": "completed"}},
+		bson.M{"is_overdue": true, "$or": bson.A{bson.M{"due_date": bson.M{"$gte": now}}, bson.M{"status": "completed"}}},
+	}}
+}
+
+// apply an update and return the documents changed
+func updateMany(ctx context.Context, collection *mongo.Collection, filter bson.M, update bson.M) (int64, error) {
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
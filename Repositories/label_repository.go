@@ -0,0 +1,186 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type labelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLabelRepository(col *mongo.Collection) domain.LabelRepository {
+	return &labelRepository{collection: col}
+}
+
+// store new label in database
+func (labelRepo *labelRepository) CreateLabel(ctx context.Context, label *domain.Label) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if label.ID.IsZero() {
+		label.ID = primitive.NewObjectID()
+	}
+
+	_, err := labelRepo.collection.InsertOne(contx, label)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrLabelExists
+	}
+	return err
+}
+
+// find all labels ordered by name
+func (labelRepo *labelRepository) GetLabels(ctx context.Context) ([]domain.Label, error) {
+
+	var labels []domain.Label
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := labelRepo.collection.Find(contx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &labels)
+	if err != nil {
+		return nil, err
+	}
+
+	if labels == nil {
+		return []domain.Label{}, nil
+	}
+
+	return labels, nil
+}
+
+// find label by its id
+func (labelRepo *labelRepository) GetLabelByID(ctx context.Context, labelID string) (*domain.Label, error) {
+
+	var label domain.Label
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return nil, domain.ErrInvalidLabelID
+	}
+
+	err = labelRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&label)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrLabelNotFound
+		}
+		return nil, err
+	}
+
+	return &label, nil        // success
+}
+
+// find labels with the given names
+func (labelRepo *labelRepository) GetLabelsByName(ctx context.Context, names []string) ([]domain.Label, error) {
+
+	var labels []domain.Label
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	cursor, err := labelRepo.collection.Find(contx, bson.M{"name": bson.M{"$in": names}})
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// update label fields that were provided
+func (labelRepo *labelRepository) UpdateLabel(ctx context.Context, labelID string, label *domain.Label) (*domain.Label, error) {
+
+	var updated domain.Label
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return nil, domain.ErrInvalidLabelID
+	}
+
+	setFields := bson.M{}
+	if label.Name != "" {
+		setFields["name"] = label.Name
+	}
+	if label.Color != "" {
+		setFields["color"] = label.Color
+	}
+	if label.Description != "" {
+		setFields["description"] = label.Description
+	}
+	if label.Budget != nil {
+		setFields["budget"] = label.Budget
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = labelRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": objID}, bson.M{"$set": setFields}, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrLabelNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrLabelExists
+		}
+		return nil, err
+	}
+
+	return &updated, nil        // success
+}
+
+// delete label by its id
+func (labelRepo *labelRepository) DeleteLabel(ctx context.Context, labelID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return domain.ErrInvalidLabelID
+	}
+
+	result, err := labelRepo.collection.DeleteOne(contx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrLabelNotFound
+	}
+
+	return nil        // success
+}
+
+// unique label names
+func (labelRepo *labelRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := labelRepo.collection.Indexes().CreateOne(contx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,52 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory admin invites keyed by token hash
+type memoryAdminInviteRepository struct {
+	mutex    sync.Mutex
+	invites  map[string]domain.AdminInvite
+}
+
+func NewMemoryAdminInviteRepository() domain.AdminInviteRepository {
+	return &memoryAdminInviteRepository{invites: map[string]domain.AdminInvite{}}
+}
+
+func (inviteRepo *memoryAdminInviteRepository) CreateInvite(ctx context.Context, invite *domain.AdminInvite) error {
+
+	inviteRepo.mutex.Lock()
+	defer inviteRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if invite.ID.IsZero() {
+		invite.ID = primitive.NewObjectID()
+	}
+	inviteRepo.invites[invite.TokenHash] = *invite
+
+	return nil
+}
+
+func (inviteRepo *memoryAdminInviteRepository) ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*domain.AdminInvite, error) {
+
+	inviteRepo.mutex.Lock()
+	defer inviteRepo.mutex.Unlock()
+
+	invite, ok := inviteRepo.invites[tokenHash]
+	if !ok || !invite.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidAdminInvite
+	}
+	delete(inviteRepo.invites, tokenHash)
+
+	return &invite, nil
+}
+
+func (inviteRepo *memoryAdminInviteRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory api usage counters, one per caller and endpoint
+type memoryAPIUsageRepository struct {
+	mutex     sync.RWMutex
+	counters  map[domain.APIUsage]*domain.APIUsage        // keyed by caller and endpoint (count and time zeroed)
+}
+
+func NewMemoryAPIUsageRepository() domain.APIUsageRepository {
+	return &memoryAPIUsageRepository{counters: map[domain.APIUsage]*domain.APIUsage{}}
+}
+
+func (usageRepo *memoryAPIUsageRepository) AddUsage(ctx context.Context, usage []domain.APIUsage) error {
+
+	usageRepo.mutex.Lock()
+	defer usageRepo.mutex.Unlock()
+
+	for _, u := range usage {
+		key := domain.APIUsage{UserID: u.UserID, TokenID: u.TokenID, ClientID: u.ClientID, Endpoint: u.Endpoint}
+		counter, ok := usageRepo.counters[key]
+		if !ok {
+			counter = &key
+			usageRepo.counters[key] = counter
+		}
+		counter.Count += u.Count
+		if u.LastUsedAt.After(counter.LastUsedAt) {
+			counter.LastUsedAt = u.LastUsedAt
+		}
+	}
+
+	return nil
+}
+
+func (usageRepo *memoryAPIUsageRepository) GetTokenUsage(ctx context.Context, tokenID string) ([]domain.APIUsage, error) {
+
+	usage := usageRepo.find(func(counter *domain.APIUsage) bool { return counter.TokenID == tokenID })
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Count > usage[j].Count })       // busiest first
+
+	return usage, nil
+}
+
+func (usageRepo *memoryAPIUsageRepository) GetUsageRollup(ctx context.Context, limit int64) ([]domain.APIUsageRollup, error) {
+
+	usageRepo.mutex.RLock()
+	defer usageRepo.mutex.RUnlock()
+
+	// group the counters per user, token and client
+	rollups := map[domain.APIUsageRollup]*domain.APIUsageRollup{}
+	for _, counter := range usageRepo.counters {
+		key := domain.APIUsageRollup{UserID: counter.UserID, TokenID: counter.TokenID, ClientID: counter.ClientID}
+		rollup, ok := rollups[key]
+		if !ok {
+			rollup = &key
+			rollups[key] = rollup
+		}
+		rollup.Count += counter.Count
+		rollup.Endpoints++
+		if counter.LastUsedAt.After(rollup.LastUsedAt) {
+			rollup.LastUsedAt = counter.LastUsedAt
+		}
+	}
+
+	rollup := []domain.APIUsageRollup{}
+	for _, entry := range rollups {
+		rollup = append(rollup, *entry)
+	}
+	sort.Slice(rollup, func(i, j int) bool { return rollup[i].Count > rollup[j].Count })       // busiest first
+	if limit > 0 && int64(len(rollup)) > limit {
+		rollup = rollup[:limit]
+	}
+
+	return rollup, nil
+}
+
+func (usageRepo *memoryAPIUsageRepository) GetEndpointUsage(ctx context.Context, endpoints []string) ([]domain.APIUsage, error) {
+
+	wanted := map[string]bool{}
+	for _, endpoint := range endpoints {
+		wanted[endpoint] = true
+	}
+	usage := usageRepo.find(func(counter *domain.APIUsage) bool { return wanted[counter.Endpoint] })
+	sort.Slice(usage, func(i, j int) bool { return usage[i].LastUsedAt.After(usage[j].LastUsedAt) })       // most recent first
+
+	return usage, nil
+}
+
+func (usageRepo *memoryAPIUsageRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copies of the counters matching a condition
+func (usageRepo *memoryAPIUsageRepository) find(match func(counter *domain.APIUsage) bool) []domain.APIUsage {
+
+	usageRepo.mutex.RLock()
+	defer usageRepo.mutex.RUnlock()
+
+	usage := []domain.APIUsage{}
+	for _, counter := range usageRepo.counters {
+		if match(counter) {
+			usage = append(usage, *counter)
+		}
+	}
+	return usage
+}
@@ -0,0 +1,59 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory audit log (entries are never changed once recorded)
+type memoryAuditLogRepository struct {
+	mutex    sync.RWMutex
+	entries  []domain.AuditLogEntry
+}
+
+func NewMemoryAuditLogRepository() domain.AuditLogRepository {
+	return &memoryAuditLogRepository{}
+}
+
+func (auditRepo *memoryAuditLogRepository) RecordEntry(ctx context.Context, entry *domain.AuditLogEntry) error {
+
+	auditRepo.mutex.Lock()
+	defer auditRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	auditRepo.entries = append(auditRepo.entries, *entry)
+
+	return nil
+}
+
+func (auditRepo *memoryAuditLogRepository) GetEntries(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLogEntry, error) {
+
+	auditRepo.mutex.RLock()
+	defer auditRepo.mutex.RUnlock()
+
+	entries := []domain.AuditLogEntry{}
+	for _, entry := range auditRepo.entries {
+		if filter.ActorID != "" && entry.ActorID != filter.ActorID {
+			continue
+		}
+		if (!filter.From.IsZero() && entry.Timestamp.Before(filter.From)) || (!filter.To.IsZero() && entry.Timestamp.After(filter.To)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	// newest first
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.After(entries[j].Timestamp) })
+	if filter.Limit > 0 && int64(len(entries)) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory email verification tokens keyed by hash
+type memoryEmailVerificationRepository struct {
+	mutex   sync.RWMutex
+	tokens  map[string]domain.EmailVerificationToken
+}
+
+func NewMemoryEmailVerificationRepository() domain.EmailVerificationRepository {
+	return &memoryEmailVerificationRepository{tokens: map[string]domain.EmailVerificationToken{}}
+}
+
+func (verificationRepo *memoryEmailVerificationRepository) CreateToken(ctx context.Context, token *domain.EmailVerificationToken) error {
+
+	verificationRepo.mutex.Lock()
+	defer verificationRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	verificationRepo.tokens[token.TokenHash] = *token
+
+	return nil
+}
+
+func (verificationRepo *memoryEmailVerificationRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+
+	verificationRepo.mutex.RLock()
+	defer verificationRepo.mutex.RUnlock()
+
+	token, ok := verificationRepo.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidVerificationToken
+	}
+
+	return &token, nil
+}
+
+func (verificationRepo *memoryEmailVerificationRepository) DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error {
+
+	verificationRepo.mutex.Lock()
+	defer verificationRepo.mutex.Unlock()
+
+	for hash, token := range verificationRepo.tokens {
+		if token.UserID == userID {
+			delete(verificationRepo.tokens, hash)
+		}
+	}
+
+	return nil
+}
+
+func (verificationRepo *memoryEmailVerificationRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory idempotency keys, one record per caller and key
+type memoryIdempotencyRepository struct {
+	mutex    sync.Mutex
+	records  map[primitive.ObjectID]*domain.IdempotencyRecord
+}
+
+func NewMemoryIdempotencyRepository() domain.IdempotencyRepository {
+	return &memoryIdempotencyRepository{records: map[primitive.ObjectID]*domain.IdempotencyRecord{}}
+}
+
+func (idempotencyRepo *memoryIdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+
+	idempotencyRepo.mutex.Lock()
+	defer idempotencyRepo.mutex.Unlock()
+
+	now := time.Now()
+	for id, existing := range idempotencyRepo.records {
+		if !existing.ExpiresAt.After(now) {
+			delete(idempotencyRepo.records, id)        // expired keys are free again
+			continue
+		}
+		if existing.Caller == record.Caller && existing.Key == record.Key {
+			found := *existing
+			found.Body = append([]byte(nil), existing.Body...)
+			return &found, nil
+		}
+	}
+
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+	stored := *record
+	idempotencyRepo.records[record.ID] = &stored
+
+	return nil, nil        // first request with the key
+}
+
+func (idempotencyRepo *memoryIdempotencyRepository) Complete(ctx context.Context, recordID primitive.ObjectID, status int, contentType string, body []byte) error {
+
+	idempotencyRepo.mutex.Lock()
+	defer idempotencyRepo.mutex.Unlock()
+
+	if record, ok := idempotencyRepo.records[recordID]; ok {
+		record.Status, record.ContentType, record.Body = status, contentType, append([]byte(nil), body...)
+	}
+
+	return nil
+}
+
+func (idempotencyRepo *memoryIdempotencyRepository) Release(ctx context.Context, recordID primitive.ObjectID) error {
+
+	idempotencyRepo.mutex.Lock()
+	defer idempotencyRepo.mutex.Unlock()
+
+	delete(idempotencyRepo.records, recordID)
+	return nil
+}
+
+func (idempotencyRepo *memoryIdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
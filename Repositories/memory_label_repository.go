@@ -0,0 +1,169 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory labels with unique names
+type memoryLabelRepository struct {
+	mutex   sync.RWMutex
+	labels  map[primitive.ObjectID]*domain.Label
+}
+
+func NewMemoryLabelRepository() domain.LabelRepository {
+	return &memoryLabelRepository{labels: map[primitive.ObjectID]*domain.Label{}}
+}
+
+func (labelRepo *memoryLabelRepository) CreateLabel(ctx context.Context, label *domain.Label) error {
+
+	labelRepo.mutex.Lock()
+	defer labelRepo.mutex.Unlock()
+
+	if labelRepo.nameTaken(label.Name, primitive.NilObjectID) {
+		return domain.ErrLabelExists
+	}
+
+	// generate new ObjectID if not set
+	if label.ID.IsZero() {
+		label.ID = primitive.NewObjectID()
+	}
+	labelRepo.labels[label.ID] = cloneLabel(label)
+
+	return nil
+}
+
+func (labelRepo *memoryLabelRepository) GetLabels(ctx context.Context) ([]domain.Label, error) {
+
+	labels := labelRepo.find(func(label *domain.Label) bool { return true })
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+
+	return labels, nil
+}
+
+func (labelRepo *memoryLabelRepository) GetLabelByID(ctx context.Context, labelID string) (*domain.Label, error) {
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return nil, domain.ErrInvalidLabelID
+	}
+
+	labelRepo.mutex.RLock()
+	defer labelRepo.mutex.RUnlock()
+
+	label, ok := labelRepo.labels[objID]
+	if !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+
+	return cloneLabel(label), nil
+}
+
+func (labelRepo *memoryLabelRepository) GetLabelsByName(ctx context.Context, names []string) ([]domain.Label, error) {
+
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	return labelRepo.find(func(label *domain.Label) bool { return wanted[label.Name] }), nil
+}
+
+func (labelRepo *memoryLabelRepository) UpdateLabel(ctx context.Context, labelID string, label *domain.Label) (*domain.Label, error) {
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return nil, domain.ErrInvalidLabelID
+	}
+
+	labelRepo.mutex.Lock()
+	defer labelRepo.mutex.Unlock()
+
+	stored, ok := labelRepo.labels[objID]
+	if !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+	if label.Name != "" && labelRepo.nameTaken(label.Name, objID) {
+		return nil, domain.ErrLabelExists
+	}
+
+	// only provided fields change
+	updated := cloneLabel(stored)
+	if label.Name != "" {
+		updated.Name = label.Name
+	}
+	if label.Color != "" {
+		updated.Color = label.Color
+	}
+	if label.Description != "" {
+		updated.Description = label.Description
+	}
+	if label.Budget != nil {
+		budget := *label.Budget
+		updated.Budget = &budget
+	}
+	labelRepo.labels[objID] = updated
+
+	return cloneLabel(updated), nil
+}
+
+func (labelRepo *memoryLabelRepository) DeleteLabel(ctx context.Context, labelID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return domain.ErrInvalidLabelID
+	}
+
+	labelRepo.mutex.Lock()
+	defer labelRepo.mutex.Unlock()
+
+	if _, ok := labelRepo.labels[objID]; !ok {
+		return domain.ErrLabelNotFound
+	}
+	delete(labelRepo.labels, objID)
+
+	return nil
+}
+
+func (labelRepo *memoryLabelRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// same uniqueness as the mongodb index (callers hold the mutex)
+func (labelRepo *memoryLabelRepository) nameTaken(name string, except primitive.ObjectID) bool {
+	for id, label := range labelRepo.labels {
+		if id != except && label.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// copies of the labels matching a condition
+func (labelRepo *memoryLabelRepository) find(match func(label *domain.Label) bool) []domain.Label {
+
+	labelRepo.mutex.RLock()
+	defer labelRepo.mutex.RUnlock()
+
+	labels := []domain.Label{}
+	for _, label := range labelRepo.labels {
+		if match(label) {
+			labels = append(labels, *cloneLabel(label))
+		}
+	}
+	return labels
+}
+
+// copy a label so callers can't change the stored one
+func cloneLabel(label *domain.Label) *domain.Label {
+	clone := *label
+	if label.Budget != nil {
+		budget := *label.Budget
+		clone.Budget = &budget
+	}
+	return &clone
+}
@@ -0,0 +1,46 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// applied migrations of an in-memory store (index migrations run on every start)
+type memoryMigrationRepository struct {
+	mutex    sync.RWMutex
+	applied  map[int]domain.AppliedMigration
+}
+
+func NewMemoryMigrationRepository() domain.MigrationRepository {
+	return &memoryMigrationRepository{applied: map[int]domain.AppliedMigration{}}
+}
+
+func (migrationRepo *memoryMigrationRepository) GetApplied(ctx context.Context) ([]domain.AppliedMigration, error) {
+
+	migrationRepo.mutex.RLock()
+	defer migrationRepo.mutex.RUnlock()
+
+	applied := []domain.AppliedMigration{}
+	for _, migration := range migrationRepo.applied {
+		applied = append(applied, migration)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version < applied[j].Version })
+
+	return applied, nil
+}
+
+func (migrationRepo *memoryMigrationRepository) RecordApplied(ctx context.Context, migration domain.AppliedMigration) error {
+
+	migrationRepo.mutex.Lock()
+	defer migrationRepo.mutex.Unlock()
+
+	// first record wins like the unique _id
+	if _, ok := migrationRepo.applied[migration.Version]; !ok {
+		migrationRepo.applied[migration.Version] = migration
+	}
+
+	return nil
+}
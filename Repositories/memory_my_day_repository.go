@@ -0,0 +1,46 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory my day lists, one per user
+type memoryMyDayRepository struct {
+	mutex  sync.RWMutex
+	days   map[string]*domain.MyDay
+}
+
+func NewMemoryMyDayRepository() domain.MyDayRepository {
+	return &memoryMyDayRepository{days: map[string]*domain.MyDay{}}
+}
+
+func (myDayRepo *memoryMyDayRepository) GetMyDay(ctx context.Context, userID string) (*domain.MyDay, error) {
+
+	myDayRepo.mutex.RLock()
+	defer myDayRepo.mutex.RUnlock()
+
+	day, ok := myDayRepo.days[userID]
+	if !ok {
+		return nil, domain.ErrMyDayNotFound
+	}
+
+	clone := *day
+	clone.TaskIDs = append([]primitive.ObjectID(nil), day.TaskIDs...)
+	return &clone, nil
+}
+
+func (myDayRepo *memoryMyDayRepository) SaveMyDay(ctx context.Context, day *domain.MyDay) error {
+
+	myDayRepo.mutex.Lock()
+	defer myDayRepo.mutex.Unlock()
+
+	clone := *day
+	clone.TaskIDs = append([]primitive.ObjectID(nil), day.TaskIDs...)
+	myDayRepo.days[day.UserID] = &clone
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory oauth clients and authorization codes
+type memoryOAuthRepository struct {
+	mutex    sync.Mutex
+	clients  map[string]domain.OAuthClient                   // by public client id
+	codes    map[string]domain.OAuthAuthorizationCode        // by code hash
+}
+
+func NewMemoryOAuthRepository() domain.OAuthRepository {
+	return &memoryOAuthRepository{clients: map[string]domain.OAuthClient{}, codes: map[string]domain.OAuthAuthorizationCode{}}
+}
+
+func (oauthRepo *memoryOAuthRepository) CreateClient(ctx context.Context, client *domain.OAuthClient) error {
+
+	oauthRepo.mutex.Lock()
+	defer oauthRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if client.ID.IsZero() {
+		client.ID = primitive.NewObjectID()
+	}
+	stored := *client
+	stored.RedirectURIs = append([]string(nil), client.RedirectURIs...)
+	stored.Scopes = append([]string(nil), client.Scopes...)
+	oauthRepo.clients[client.ClientID] = stored
+
+	return nil
+}
+
+func (oauthRepo *memoryOAuthRepository) GetClientByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+
+	oauthRepo.mutex.Lock()
+	defer oauthRepo.mutex.Unlock()
+
+	client, ok := oauthRepo.clients[clientID]
+	if !ok {
+		return nil, domain.ErrOAuthClientNotFound
+	}
+	client.RedirectURIs = append([]string(nil), client.RedirectURIs...)
+	client.Scopes = append([]string(nil), client.Scopes...)
+
+	return &client, nil
+}
+
+func (oauthRepo *memoryOAuthRepository) SaveAuthorizationCode(ctx context.Context, code *domain.OAuthAuthorizationCode) error {
+
+	oauthRepo.mutex.Lock()
+	defer oauthRepo.mutex.Unlock()
+
+	stored := *code
+	stored.Scopes = append([]string(nil), code.Scopes...)
+	oauthRepo.codes[code.CodeHash] = stored
+
+	return nil
+}
+
+func (oauthRepo *memoryOAuthRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+
+	oauthRepo.mutex.Lock()
+	defer oauthRepo.mutex.Unlock()
+
+	code, ok := oauthRepo.codes[codeHash]
+	if !ok {
+		return nil, domain.ErrInvalidGrant
+	}
+	delete(oauthRepo.codes, codeHash)
+
+	return &code, nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory organizations and their pending invites
+type memoryOrganizationRepository struct {
+	mutex          sync.Mutex
+	organizations  map[primitive.ObjectID]domain.Organization
+	invites        map[string]domain.OrganizationInvite        // by token hash
+}
+
+func NewMemoryOrganizationRepository() domain.OrganizationRepository {
+	return &memoryOrganizationRepository{organizations: map[primitive.ObjectID]domain.Organization{}, invites: map[string]domain.OrganizationInvite{}}
+}
+
+func (orgRepo *memoryOrganizationRepository) CreateOrganization(ctx context.Context, org *domain.Organization) error {
+
+	orgRepo.mutex.Lock()
+	defer orgRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if org.ID.IsZero() {
+		org.ID = primitive.NewObjectID()
+	}
+	stored := *org
+	stored.Owners = append([]string(nil), org.Owners...)
+	orgRepo.organizations[org.ID] = stored
+
+	return nil
+}
+
+func (orgRepo *memoryOrganizationRepository) GetOrganizationByID(ctx context.Context, orgID string) (*domain.Organization, error) {
+
+	objID, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return nil, domain.ErrInvalidOrganizationID
+	}
+
+	orgRepo.mutex.Lock()
+	defer orgRepo.mutex.Unlock()
+
+	org, ok := orgRepo.organizations[objID]
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	org.Owners = append([]string(nil), org.Owners...)
+
+	return &org, nil
+}
+
+func (orgRepo *memoryOrganizationRepository) CreateInvite(ctx context.Context, invite *domain.OrganizationInvite) error {
+
+	orgRepo.mutex.Lock()
+	defer orgRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if invite.ID.IsZero() {
+		invite.ID = primitive.NewObjectID()
+	}
+	orgRepo.invites[invite.TokenHash] = *invite
+
+	return nil
+}
+
+func (orgRepo *memoryOrganizationRepository) ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*domain.OrganizationInvite, error) {
+
+	orgRepo.mutex.Lock()
+	defer orgRepo.mutex.Unlock()
+
+	invite, ok := orgRepo.invites[tokenHash]
+	if !ok || !invite.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidOrgInvite
+	}
+	delete(orgRepo.invites, tokenHash)
+
+	return &invite, nil
+}
+
+func (orgRepo *memoryOrganizationRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
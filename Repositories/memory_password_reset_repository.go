@@ -0,0 +1,65 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory password reset tokens keyed by hash (expiry is checked by the usecase)
+type memoryPasswordResetRepository struct {
+	mutex   sync.Mutex
+	tokens  map[string]domain.PasswordResetToken
+}
+
+func NewMemoryPasswordResetRepository() domain.PasswordResetRepository {
+	return &memoryPasswordResetRepository{tokens: map[string]domain.PasswordResetToken{}}
+}
+
+func (resetRepo *memoryPasswordResetRepository) CreateToken(ctx context.Context, token *domain.PasswordResetToken) error {
+
+	resetRepo.mutex.Lock()
+	defer resetRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	resetRepo.tokens[token.TokenHash] = *token
+
+	return nil
+}
+
+func (resetRepo *memoryPasswordResetRepository) ConsumeToken(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+
+	resetRepo.mutex.Lock()
+	defer resetRepo.mutex.Unlock()
+
+	token, ok := resetRepo.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidResetToken
+	}
+	delete(resetRepo.tokens, tokenHash)
+
+	return &token, nil
+}
+
+func (resetRepo *memoryPasswordResetRepository) DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error {
+
+	resetRepo.mutex.Lock()
+	defer resetRepo.mutex.Unlock()
+
+	for hash, token := range resetRepo.tokens {
+		if token.UserID == userID {
+			delete(resetRepo.tokens, hash)
+		}
+	}
+
+	return nil
+}
+
+func (resetRepo *memoryPasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
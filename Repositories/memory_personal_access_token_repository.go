@@ -0,0 +1,118 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory personal access tokens
+type memoryPersonalAccessTokenRepository struct {
+	mutex   sync.RWMutex
+	tokens  map[primitive.ObjectID]*domain.PersonalAccessToken
+}
+
+func NewMemoryPersonalAccessTokenRepository() domain.PersonalAccessTokenRepository {
+	return &memoryPersonalAccessTokenRepository{tokens: map[primitive.ObjectID]*domain.PersonalAccessToken{}}
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+
+	patRepo.mutex.Lock()
+	defer patRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	patRepo.tokens[token.ID] = clonePersonalAccessToken(token)
+
+	return nil
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) GetTokensByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.PersonalAccessToken, error) {
+
+	patRepo.mutex.RLock()
+	defer patRepo.mutex.RUnlock()
+
+	tokens := []domain.PersonalAccessToken{}
+	for _, token := range patRepo.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, *clonePersonalAccessToken(token))
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })       // newest first
+
+	return tokens, nil
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+
+	patRepo.mutex.RLock()
+	defer patRepo.mutex.RUnlock()
+
+	for _, token := range patRepo.tokens {
+		if token.TokenHash == tokenHash {
+			return clonePersonalAccessToken(token), nil
+		}
+	}
+
+	return nil, domain.ErrTokenNotFound
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) RevokeToken(ctx context.Context, tokenID primitive.ObjectID, userID primitive.ObjectID) error {
+
+	patRepo.mutex.Lock()
+	defer patRepo.mutex.Unlock()
+
+	token, ok := patRepo.tokens[tokenID]
+	if !ok || token.UserID != userID {
+		return domain.ErrTokenNotFound
+	}
+	token.Revoked = true
+
+	return nil
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) RevokeUserTokens(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+
+	patRepo.mutex.Lock()
+	defer patRepo.mutex.Unlock()
+
+	var revoked int64
+	for _, token := range patRepo.tokens {
+		if token.UserID == userID && !token.Revoked {
+			token.Revoked = true
+			revoked++
+		}
+	}
+
+	return revoked, nil
+}
+
+func (patRepo *memoryPersonalAccessTokenRepository) TouchToken(ctx context.Context, tokenID primitive.ObjectID, usedAt time.Time) error {
+
+	patRepo.mutex.Lock()
+	defer patRepo.mutex.Unlock()
+
+	if token, ok := patRepo.tokens[tokenID]; ok {
+		token.LastUsedAt = &usedAt
+	}
+
+	return nil
+}
+
+// copy a token so callers can't change the stored one
+func clonePersonalAccessToken(token *domain.PersonalAccessToken) *domain.PersonalAccessToken {
+	clone := *token
+	clone.Scopes = append([]string(nil), token.Scopes...)
+	if token.LastUsedAt != nil {
+		lastUsedAt := *token.LastUsedAt
+		clone.LastUsedAt = &lastUsedAt
+	}
+	return &clone
+}
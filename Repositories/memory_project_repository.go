@@ -0,0 +1,164 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory projects and their members
+type memoryProjectRepository struct {
+	mutex     sync.RWMutex
+	projects  map[primitive.ObjectID]*domain.Project
+}
+
+func NewMemoryProjectRepository() domain.ProjectRepository {
+	return &memoryProjectRepository{projects: map[primitive.ObjectID]*domain.Project{}}
+}
+
+func (projectRepo *memoryProjectRepository) CreateProject(ctx context.Context, project *domain.Project) error {
+
+	projectRepo.mutex.Lock()
+	defer projectRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+	projectRepo.projects[project.ID] = cloneProject(project)
+
+	return nil
+}
+
+func (projectRepo *memoryProjectRepository) GetProjects(ctx context.Context, userID string) ([]domain.Project, error) {
+
+	projectRepo.mutex.RLock()
+	defer projectRepo.mutex.RUnlock()
+
+	projects := []domain.Project{}
+	for _, project := range projectRepo.projects {
+		if userID == "" || memberIndex(project, userID) >= 0 {
+			projects = append(projects, *cloneProject(project))
+		}
+	}
+
+	// by name, then id like the mongodb sort
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return bytes.Compare(projects[i].ID[:], projects[j].ID[:]) < 0
+	})
+
+	return projects, nil
+}
+
+func (projectRepo *memoryProjectRepository) GetProjectByID(ctx context.Context, projectID string) (*domain.Project, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	projectRepo.mutex.RLock()
+	defer projectRepo.mutex.RUnlock()
+
+	project, ok := projectRepo.projects[objID]
+	if !ok {
+		return nil, domain.ErrProjectNotFound
+	}
+
+	return cloneProject(project), nil
+}
+
+func (projectRepo *memoryProjectRepository) UpdateProject(ctx context.Context, projectID string, project *domain.Project) (*domain.Project, error) {
+	return projectRepo.update(projectID, func(stored *domain.Project) {
+		if project.Name != "" {
+			stored.Name = project.Name
+		}
+		if project.Description != "" {
+			stored.Description = project.Description
+		}
+	})
+}
+
+func (projectRepo *memoryProjectRepository) DeleteProject(ctx context.Context, projectID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return domain.ErrInvalidProjectID
+	}
+
+	projectRepo.mutex.Lock()
+	defer projectRepo.mutex.Unlock()
+
+	if _, ok := projectRepo.projects[objID]; !ok {
+		return domain.ErrProjectNotFound
+	}
+	delete(projectRepo.projects, objID)
+
+	return nil
+}
+
+func (projectRepo *memoryProjectRepository) SetMember(ctx context.Context, projectID string, member domain.ProjectMember) (*domain.Project, error) {
+	return projectRepo.update(projectID, func(stored *domain.Project) {
+		if index := memberIndex(stored, member.UserID); index >= 0 {
+			stored.Members[index].Role = member.Role        // change the role in place
+			return
+		}
+		stored.Members = append(stored.Members, member)
+	})
+}
+
+func (projectRepo *memoryProjectRepository) RemoveMember(ctx context.Context, projectID string, userID string) (*domain.Project, error) {
+	return projectRepo.update(projectID, func(stored *domain.Project) {
+		if index := memberIndex(stored, userID); index >= 0 {
+			stored.Members = append(stored.Members[:index], stored.Members[index+1:]...)
+		}
+	})
+}
+
+func (projectRepo *memoryProjectRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// apply a change to one project and return it
+func (projectRepo *memoryProjectRepository) update(projectID string, change func(project *domain.Project)) (*domain.Project, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	projectRepo.mutex.Lock()
+	defer projectRepo.mutex.Unlock()
+
+	project, ok := projectRepo.projects[objID]
+	if !ok {
+		return nil, domain.ErrProjectNotFound
+	}
+	change(project)
+
+	return cloneProject(project), nil
+}
+
+// position of a member in the project (-1 when not a member)
+func memberIndex(project *domain.Project, userID string) int {
+	for i, member := range project.Members {
+		if member.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+// copy a project so callers can't change the stored members
+func cloneProject(project *domain.Project) *domain.Project {
+	clone := *project
+	clone.Members = append([]domain.ProjectMember{}, project.Members...)
+	return &clone
+}
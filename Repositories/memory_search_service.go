@@ -0,0 +1,62 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"strings";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// search service reading the task store directly (in-memory storage)
+// scores like the text index weights: title words count three times as much as description words
+type memorySearchService struct {
+	reader domain.TaskReader
+}
+
+func NewMemorySearchService(reader domain.TaskReader) domain.SearchService {
+	return &memorySearchService{reader: reader}
+}
+
+func (searchService *memorySearchService) IndexTask(ctx context.Context, task *domain.Task) error {
+	return nil
+}
+
+func (searchService *memorySearchService) RemoveTask(ctx context.Context, taskID string) error {
+	return nil
+}
+
+func (searchService *memorySearchService) SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchMatch, error) {
+
+	tasks, err := searchService.reader.GetAllTasks(ctx, domain.TaskQuery{Search: query.Text, Language: query.Language, Visibility: query.Visibility, TenantID: query.TenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query.Text)
+	matches := make([]domain.TaskSearchMatch, 0, len(tasks))
+	for _, task := range tasks {
+		score := 3*countMatches(task.Title, terms) + countMatches(task.Description, terms)
+		if score == 0 {
+			continue
+		}
+		matches = append(matches, domain.TaskSearchMatch{TaskID: task.ID.Hex(), Score: float64(score), Highlights: highlightTask(task.Title, task.Description, terms)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > query.Limit {
+		matches = matches[:query.Limit]
+	}
+
+	return matches, nil
+}
+
+// number of words of a text matching the search terms
+func countMatches(text string, terms []string) int {
+	count := 0
+	for _, word := range searchWordPattern.FindAllString(text, -1) {
+		if matchesTerm(strings.ToLower(word), terms) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,91 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory login sessions
+type memorySessionRepository struct {
+	mutex     sync.RWMutex
+	sessions  map[primitive.ObjectID]domain.Session
+}
+
+func NewMemorySessionRepository() domain.SessionRepository {
+	return &memorySessionRepository{sessions: map[primitive.ObjectID]domain.Session{}}
+}
+
+func (sessionRepo *memorySessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+
+	sessionRepo.mutex.Lock()
+	defer sessionRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	sessionRepo.sessions[session.ID] = *session
+
+	return nil
+}
+
+func (sessionRepo *memorySessionRepository) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return nil, domain.ErrInvalidSessionID
+	}
+
+	sessionRepo.mutex.RLock()
+	defer sessionRepo.mutex.RUnlock()
+
+	session, ok := sessionRepo.sessions[objID]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	return &session, nil
+}
+
+func (sessionRepo *memorySessionRepository) GetActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error) {
+
+	sessionRepo.mutex.RLock()
+	defer sessionRepo.mutex.RUnlock()
+
+	sessions := []domain.Session{}
+	for _, session := range sessionRepo.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.Before(sessions[j].CreatedAt) })
+
+	return sessions, nil
+}
+
+func (sessionRepo *memorySessionRepository) RevokeSessions(ctx context.Context, sessionIDs []primitive.ObjectID, reason string, at time.Time) error {
+
+	sessionRepo.mutex.Lock()
+	defer sessionRepo.mutex.Unlock()
+
+	for _, id := range sessionIDs {
+		session, ok := sessionRepo.sessions[id]
+		if !ok || session.RevokedAt != nil {
+			continue
+		}
+		revokedAt := at
+		session.RevokedAt, session.RevokedReason = &revokedAt, reason
+		sessionRepo.sessions[id] = session
+	}
+
+	return nil
+}
+
+func (sessionRepo *memorySessionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory instance settings (nil until saved or claimed)
+type memorySettingsRepository struct {
+	mutex     sync.Mutex
+	settings  *domain.InstanceSettings
+}
+
+func NewMemorySettingsRepository() domain.SettingsRepository {
+	return &memorySettingsRepository{}
+}
+
+func (settingsRepo *memorySettingsRepository) GetSettings(ctx context.Context) (*domain.InstanceSettings, error) {
+
+	settingsRepo.mutex.Lock()
+	defer settingsRepo.mutex.Unlock()
+
+	if settingsRepo.settings == nil {
+		return nil, domain.ErrSettingsNotFound
+	}
+
+	return cloneSettings(settingsRepo.settings), nil
+}
+
+func (settingsRepo *memorySettingsRepository) SaveSettings(ctx context.Context, settings *domain.InstanceSettings) error {
+
+	settingsRepo.mutex.Lock()
+	defer settingsRepo.mutex.Unlock()
+
+	settings.ID = domain.InstanceSettingsID
+	settingsRepo.settings = cloneSettings(settings)
+
+	return nil
+}
+
+func (settingsRepo *memorySettingsRepository) SaveSecuritySettings(ctx context.Context, security *domain.SecuritySettings, at time.Time) error {
+
+	settingsRepo.mutex.Lock()
+	defer settingsRepo.mutex.Unlock()
+
+	settings := settingsRepo.stored()
+	if security != nil {
+		saved := *security
+		security = &saved
+	}
+	settings.Security = security
+	settings.UpdatedAt = at
+
+	return nil
+}
+
+func (settingsRepo *memorySettingsRepository) ClaimSetup(ctx context.Context, at time.Time) error {
+
+	settingsRepo.mutex.Lock()
+	defer settingsRepo.mutex.Unlock()
+
+	settings := settingsRepo.stored()
+	if settings.SetupCompletedAt != nil {
+		return domain.ErrSetupCompleted
+	}
+	settings.SetupCompletedAt = &at
+
+	return nil
+}
+
+func (settingsRepo *memorySettingsRepository) ReleaseSetup(ctx context.Context) error {
+
+	settingsRepo.mutex.Lock()
+	defer settingsRepo.mutex.Unlock()
+
+	if settingsRepo.settings != nil {
+		settingsRepo.settings.SetupCompletedAt = nil
+	}
+
+	return nil
+}
+
+// stored settings, created like an upsert when missing (callers hold the mutex)
+func (settingsRepo *memorySettingsRepository) stored() *domain.InstanceSettings {
+	if settingsRepo.settings == nil {
+		settingsRepo.settings = &domain.InstanceSettings{ID: domain.InstanceSettingsID}
+	}
+	return settingsRepo.settings
+}
+
+// copy of settings that shares no pointers with the stored ones
+func cloneSettings(settings *domain.InstanceSettings) *domain.InstanceSettings {
+	clone := *settings
+	if settings.SMTP != nil {
+		smtp := *settings.SMTP
+		clone.SMTP = &smtp
+	}
+	if settings.Security != nil {
+		security := *settings.Security
+		clone.Security = &security
+	}
+	if settings.SetupCompletedAt != nil {
+		completedAt := *settings.SetupCompletedAt
+		clone.SetupCompletedAt = &completedAt
+	}
+	return &clone
+}
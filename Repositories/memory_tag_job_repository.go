@@ -0,0 +1,74 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory tag jobs
+type memoryTagJobRepository struct {
+	mutex  sync.RWMutex
+	jobs   map[primitive.ObjectID]*domain.TagJob
+}
+
+func NewMemoryTagJobRepository() domain.TagJobRepository {
+	return &memoryTagJobRepository{jobs: map[primitive.ObjectID]*domain.TagJob{}}
+}
+
+func (jobRepo *memoryTagJobRepository) CreateJob(ctx context.Context, job *domain.TagJob) error {
+
+	jobRepo.mutex.Lock()
+	defer jobRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	jobRepo.jobs[job.ID] = cloneTagJob(job)
+
+	return nil
+}
+
+func (jobRepo *memoryTagJobRepository) UpdateJob(ctx context.Context, job *domain.TagJob) error {
+
+	jobRepo.mutex.Lock()
+	defer jobRepo.mutex.Unlock()
+
+	if _, ok := jobRepo.jobs[job.ID]; !ok {
+		return domain.ErrTagJobNotFound
+	}
+	jobRepo.jobs[job.ID] = cloneTagJob(job)
+
+	return nil
+}
+
+func (jobRepo *memoryTagJobRepository) GetJob(ctx context.Context, jobID string) (*domain.TagJob, error) {
+
+	objID, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, domain.ErrInvalidTagJobID
+	}
+
+	jobRepo.mutex.RLock()
+	defer jobRepo.mutex.RUnlock()
+
+	job, ok := jobRepo.jobs[objID]
+	if !ok {
+		return nil, domain.ErrTagJobNotFound
+	}
+
+	return cloneTagJob(job), nil
+}
+
+// copy a job, the job runner keeps changing its own
+func cloneTagJob(job *domain.TagJob) *domain.TagJob {
+	clone := *job
+	if job.FinishedAt != nil {
+		finishedAt := *job.FinishedAt
+		clone.FinishedAt = &finishedAt
+	}
+	return &clone
+}
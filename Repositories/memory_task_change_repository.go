@@ -0,0 +1,109 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory field history of tasks
+type memoryTaskChangeRepository struct {
+	mutex    sync.RWMutex
+	changes  []domain.TaskChange
+}
+
+func NewMemoryTaskChangeRepository() domain.TaskChangeRepository {
+	return &memoryTaskChangeRepository{}
+}
+
+func (changeRepo *memoryTaskChangeRepository) AppendChanges(ctx context.Context, changes []domain.TaskChange) error {
+
+	changeRepo.mutex.Lock()
+	defer changeRepo.mutex.Unlock()
+
+	for i := range changes {
+		if changes[i].ID.IsZero() {
+			changes[i].ID = primitive.NewObjectID()       // ids keep the order of changes stored in the same instant
+		}
+		changeRepo.changes = append(changeRepo.changes, changes[i])
+	}
+
+	return nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) GetChanges(ctx context.Context, taskID string) ([]domain.TaskChange, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	changeRepo.mutex.RLock()
+	defer changeRepo.mutex.RUnlock()
+
+	changes := []domain.TaskChange{}
+	for _, change := range changeRepo.changes {
+		if change.TaskID == objID {
+			changes = append(changes, change)
+		}
+	}
+
+	// oldest first like the mongodb sort
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].Timestamp.Equal(changes[j].Timestamp) {
+			return changes[i].Timestamp.Before(changes[j].Timestamp)
+		}
+		return bytes.Compare(changes[i].ID[:], changes[j].ID[:]) < 0
+	})
+
+	return changes, nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) GetCompletions(ctx context.Context, actorID string, limit int64) ([]domain.TaskCompletion, error) {
+
+	changeRepo.mutex.RLock()
+	defer changeRepo.mutex.RUnlock()
+
+	completed := []domain.TaskChange{}
+	creations := map[primitive.ObjectID]domain.TaskChange{}
+	for _, change := range changeRepo.changes {
+		if status, _ := change.NewValue.(string); change.ActorID == actorID && change.Field == "status" && status == "completed" {
+			completed = append(completed, change)
+		}
+		if change.Action == domain.TaskEventCreated && change.Field == "title" {
+			creations[change.TaskID] = change
+		}
+	}
+
+	// latest first and limited like the mongodb query
+	sort.SliceStable(completed, func(i, j int) bool { return completed[i].Timestamp.After(completed[j].Timestamp) })
+	if limit > 0 && int64(len(completed)) > limit {
+		completed = completed[:limit]
+	}
+
+	completions := []domain.TaskCompletion{}
+	completedAt := map[primitive.ObjectID]time.Time{}
+	for _, change := range completed {
+		if _, seen := completedAt[change.TaskID]; seen {        // a task completed again after being reopened counts once
+			continue
+		}
+		completedAt[change.TaskID] = change.Timestamp
+		creation, ok := creations[change.TaskID]
+		if !ok {
+			continue        // created before field history was recorded
+		}
+		title, _ := creation.NewValue.(string)
+		completions = append(completions, domain.TaskCompletion{TaskID: change.TaskID, Title: title, CreatedAt: creation.Timestamp, CompletedAt: change.Timestamp})
+	}
+
+	return completions, nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
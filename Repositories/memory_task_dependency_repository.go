@@ -0,0 +1,104 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory task dependencies in the order they were added
+type memoryTaskDependencyRepository struct {
+	mutex         sync.RWMutex
+	dependencies  []domain.TaskDependency
+}
+
+func NewMemoryTaskDependencyRepository() domain.TaskDependencyRepository {
+	return &memoryTaskDependencyRepository{}
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) AddDependency(ctx context.Context, dependency *domain.TaskDependency) error {
+
+	dependencyRepo.mutex.Lock()
+	defer dependencyRepo.mutex.Unlock()
+
+	// one link per pair like the unique index
+	for _, stored := range dependencyRepo.dependencies {
+		if stored.TaskID == dependency.TaskID && stored.BlockerID == dependency.BlockerID {
+			return domain.ErrDependencyExists
+		}
+	}
+
+	// generate new ObjectID if not set
+	if dependency.ID.IsZero() {
+		dependency.ID = primitive.NewObjectID()
+	}
+	dependencyRepo.dependencies = append(dependencyRepo.dependencies, *dependency)
+
+	return nil
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) RemoveDependency(ctx context.Context, taskID string, blockerID string) error {
+
+	dependencyRepo.mutex.Lock()
+	defer dependencyRepo.mutex.Unlock()
+
+	for i, stored := range dependencyRepo.dependencies {
+		if stored.TaskID == taskID && stored.BlockerID == blockerID {
+			dependencyRepo.dependencies = append(dependencyRepo.dependencies[:i], dependencyRepo.dependencies[i+1:]...)
+			return nil
+		}
+	}
+
+	return domain.ErrDependencyNotFound
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) GetBlockers(ctx context.Context, taskIDs []string) ([]domain.TaskDependency, error) {
+
+	blocked := map[string]bool{}
+	for _, taskID := range taskIDs {
+		blocked[taskID] = true
+	}
+
+	return dependencyRepo.find(func(dependency domain.TaskDependency) bool { return blocked[dependency.TaskID] }), nil
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) GetBlocked(ctx context.Context, blockerID string) ([]domain.TaskDependency, error) {
+	return dependencyRepo.find(func(dependency domain.TaskDependency) bool { return dependency.BlockerID == blockerID }), nil
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) DeleteTaskDependencies(ctx context.Context, taskID string) error {
+
+	dependencyRepo.mutex.Lock()
+	defer dependencyRepo.mutex.Unlock()
+
+	kept := dependencyRepo.dependencies[:0]
+	for _, stored := range dependencyRepo.dependencies {
+		if stored.TaskID != taskID && stored.BlockerID != taskID {
+			kept = append(kept, stored)
+		}
+	}
+	dependencyRepo.dependencies = kept
+
+	return nil
+}
+
+func (dependencyRepo *memoryTaskDependencyRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// dependencies matching a condition, oldest first
+func (dependencyRepo *memoryTaskDependencyRepository) find(match func(dependency domain.TaskDependency) bool) []domain.TaskDependency {
+
+	dependencyRepo.mutex.RLock()
+	defer dependencyRepo.mutex.RUnlock()
+
+	dependencies := []domain.TaskDependency{}
+	for _, dependency := range dependencyRepo.dependencies {
+		if match(dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
@@ -0,0 +1,85 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory task event log, entries are kept in the order they were appended
+type memoryTaskEventLogRepository struct {
+	mutex    sync.RWMutex
+	entries  []domain.TaskEventLogEntry
+}
+
+func NewMemoryTaskEventLogRepository() domain.TaskEventLogRepository {
+	return &memoryTaskEventLogRepository{}
+}
+
+func (eventLogRepo *memoryTaskEventLogRepository) AppendEvent(ctx context.Context, entry *domain.TaskEventLogEntry) error {
+
+	eventLogRepo.mutex.Lock()
+	defer eventLogRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	// expired events leave the front of the log (what the ttl index does in mongodb)
+	now := time.Now()
+	expired := 0
+	for expired < len(eventLogRepo.entries) && !eventLogRepo.entries[expired].ExpiresAt.After(now) {
+		expired++
+	}
+	eventLogRepo.entries = append(eventLogRepo.entries[expired:], cloneTaskEventLogEntry(entry))
+
+	return nil
+}
+
+func (eventLogRepo *memoryTaskEventLogRepository) GetEvents(ctx context.Context, query domain.TaskEventLogQuery) ([]domain.TaskEventLogEntry, error) {
+
+	eventLogRepo.mutex.RLock()
+	defer eventLogRepo.mutex.RUnlock()
+
+	visibility := domain.TaskQuery{Visibility: query.Visibility}
+	entries := []domain.TaskEventLogEntry{}
+	for _, entry := range eventLogRepo.entries {
+		if bytes.Compare(entry.ID[:], query.After[:]) <= 0 {
+			continue
+		}
+		if query.TenantID != nil && entry.TenantID != *query.TenantID {
+			continue
+		}
+		if !matchesProject(entry.ProjectID, visibility) {
+			continue
+		}
+		entries = append(entries, cloneTaskEventLogEntry(&entry))
+		if query.Limit > 0 && int64(len(entries)) == query.Limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+func (eventLogRepo *memoryTaskEventLogRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copy an entry so the logged task can't change afterwards
+func cloneTaskEventLogEntry(entry *domain.TaskEventLogEntry) domain.TaskEventLogEntry {
+	clone := *entry
+	if entry.Task != nil {
+		clone.Task = cloneTask(entry.Task)
+	}
+	if entry.ProjectID != nil {
+		projectID := *entry.ProjectID
+		clone.ProjectID = &projectID
+	}
+	return clone
+}
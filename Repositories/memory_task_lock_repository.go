@@ -0,0 +1,80 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory edit locks keyed by task id
+type memoryTaskLockRepository struct {
+	mutex  sync.Mutex
+	locks  map[string]domain.TaskLock
+}
+
+func NewMemoryTaskLockRepository() domain.TaskLockRepository {
+	return &memoryTaskLockRepository{locks: map[string]domain.TaskLock{}}
+}
+
+func (lockRepo *memoryTaskLockRepository) Acquire(ctx context.Context, lock *domain.TaskLock) (*domain.TaskLock, error) {
+
+	lockRepo.mutex.Lock()
+	defer lockRepo.mutex.Unlock()
+
+	// a fresh lock of another user wins
+	if holder, ok := lockRepo.locks[lock.TaskID]; ok && holder.UserID != lock.UserID && holder.Fresh(time.Now()) {
+		return &holder, domain.ErrTaskLocked
+	}
+	lockRepo.locks[lock.TaskID] = *lock
+
+	acquired := *lock
+	return &acquired, nil
+}
+
+func (lockRepo *memoryTaskLockRepository) Heartbeat(ctx context.Context, taskID string, userID string, expiresAt time.Time) (*domain.TaskLock, error) {
+
+	lockRepo.mutex.Lock()
+	defer lockRepo.mutex.Unlock()
+
+	lock, ok := lockRepo.locks[taskID]
+	if !ok || lock.UserID != userID || !lock.Fresh(time.Now()) {
+		return nil, domain.ErrTaskLockNotHeld
+	}
+	lock.ExpiresAt = expiresAt
+	lockRepo.locks[taskID] = lock
+
+	return &lock, nil
+}
+
+func (lockRepo *memoryTaskLockRepository) Release(ctx context.Context, taskID string, userID string) error {
+
+	lockRepo.mutex.Lock()
+	defer lockRepo.mutex.Unlock()
+
+	lock, ok := lockRepo.locks[taskID]
+	if !ok || (userID != "" && lock.UserID != userID) {
+		return domain.ErrTaskLockNotHeld
+	}
+	delete(lockRepo.locks, taskID)
+
+	return nil
+}
+
+func (lockRepo *memoryTaskLockRepository) GetLock(ctx context.Context, taskID string) (*domain.TaskLock, error) {
+
+	lockRepo.mutex.Lock()
+	defer lockRepo.mutex.Unlock()
+
+	lock, ok := lockRepo.locks[taskID]
+	if !ok || !lock.Fresh(time.Now()) {
+		return nil, domain.ErrTaskLockNotFound
+	}
+
+	return &lock, nil
+}
+
+func (lockRepo *memoryTaskLockRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
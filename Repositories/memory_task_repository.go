@@ -0,0 +1,723 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"errors";
+	"sort";
+	"strings";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory task repository (demo mode and tests, contents are lost on restart)
+type memoryTaskRepository struct {
+	mutex  sync.RWMutex
+	tasks  map[primitive.ObjectID]*domain.Task
+}
+
+func NewMemoryTaskRepository() domain.TaskRepository {
+	return &memoryTaskRepository{tasks: map[primitive.ObjectID]*domain.Task{}}
+}
+
+func (taskRepo *memoryTaskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	// same uniqueness as the mongodb index
+	if task.ClientID != "" {
+		for _, existing := range taskRepo.tasks {
+			if existing.ClientID == task.ClientID {
+				return nil, domain.ErrTaskClientIDExists
+			}
+		}
+	}
+
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()    // create a unique id for the new task
+	}
+	task.CreatedAt = storedNow()             // server clock, client values are ignored
+	task.UpdatedAt = task.CreatedAt
+	taskRepo.tasks[task.ID] = cloneTask(task)
+
+	return task, nil
+}
+
+func (taskRepo *memoryTaskRepository) GetTaskByClientID(ctx context.Context, clientID string) (*domain.Task, error) {
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	for _, task := range taskRepo.tasks {
+		if task.ClientID == clientID {
+			return cloneTask(task), nil
+		}
+	}
+	return nil, domain.ErrTaskNotFound
+}
+
+func (taskRepo *memoryTaskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	now := storedNow()
+	for _, task := range tasks {
+		if task.ID.IsZero() {
+			task.ID = primitive.NewObjectID()
+		}
+		task.CreatedAt, task.UpdatedAt = now, now
+		taskRepo.tasks[task.ID] = cloneTask(task)
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	now := storedNow()
+	for _, task := range tasks {
+		task.PriorityRank = domain.TaskPriorities[task.Priority]
+		task.UpdatedAt = now
+		taskRepo.tasks[task.ID] = cloneTask(task)
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	if _, ok := taskRepo.tasks[objID]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	delete(taskRepo.tasks, objID)
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	allTasks := []domain.Task{}
+	for _, task := range taskRepo.tasks {
+		if len(query.IDs) > 0 && !containsObjectID(query.IDs, task.ID) {
+			continue
+		}
+		if query.Overdue != nil && task.IsOverdue != *query.Overdue {
+			continue
+		}
+		if len(query.Labels) > 0 && !matchesLabels(task.Tags, query.Labels, query.MatchAllLabels) {
+			continue
+		}
+		if !matchesProject(task.ProjectID, query) {
+			continue
+		}
+		if query.Language != "" && task.Language != query.Language {
+			continue
+		}
+		if query.UpdatedSince != nil && !task.UpdatedAt.After(*query.UpdatedSince) {
+			continue
+		}
+		if query.TenantID != nil && task.TenantID != *query.TenantID {
+			continue
+		}
+		if query.Search != "" && !matchesSearch(task, query.Search) {
+			continue
+		}
+		allTasks = append(allTasks, *cloneTask(task))
+	}
+
+	// requested order, insertion order (ids grow) for ties like mongodb's natural order
+	sort.SliceStable(allTasks, func(i, j int) bool {
+		for _, sortField := range query.Sort {
+			cmp := compareTasks(&allTasks[i], &allTasks[j], sortField.Field)
+			if cmp == 0 {
+				continue
+			}
+			if sortField.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return bytes.Compare(allTasks[i].ID[:], allTasks[j].ID[:]) < 0
+	})
+
+	return allTasks, nil
+}
+
+// matching tasks are copied first so fn can use the repository
+func (taskRepo *memoryTaskRepository) StreamTasks(ctx context.Context, query domain.TaskQuery, fn func(task *domain.Task) error) error {
+
+	tasks, err := taskRepo.GetAllTasks(ctx, query)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		if err := fn(&tasks[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	task, ok := taskRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return cloneTask(task), nil
+}
+
+func (taskRepo *memoryTaskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	// stop if nothing valid to update
+	if taskUpdate.Empty() {
+		return nil, errors.New("no valid fields provided for update")
+	}
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	task, ok := taskRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	if taskUpdate.ExpectedUpdatedAt != nil && !task.UpdatedAt.Equal(*taskUpdate.ExpectedUpdatedAt) {
+		return nil, domain.ErrTaskModified        // changed since the caller read it
+	}
+
+	// same rules as the mongodb repository, only provided fields change
+	if taskUpdate.Title != nil {
+		task.Title = *taskUpdate.Title
+	}
+	if taskUpdate.Description != nil {
+		task.Description = *taskUpdate.Description
+	}
+	if taskUpdate.DueDate != nil {
+		task.DueDate = *taskUpdate.DueDate
+		task.IsOverdue = taskUpdate.DueDate.Before(time.Now())
+	}
+	if taskUpdate.Status != nil {
+		task.Status = *taskUpdate.Status
+		if task.Status == "completed" {
+			task.IsOverdue = false
+		}
+	}
+	if len(taskUpdate.StatusEnteredAt) > 0 {
+		enteredAt := map[string]time.Time{}
+		for status, at := range task.StatusEnteredAt {
+			enteredAt[status] = at
+		}
+		for status, at := range taskUpdate.StatusEnteredAt {
+			enteredAt[status] = at
+		}
+		task.StatusEnteredAt = enteredAt
+	}
+	if taskUpdate.Priority != nil {
+		task.Priority = *taskUpdate.Priority
+		task.PriorityRank = taskUpdate.PriorityRank
+	}
+	if taskUpdate.Estimate != nil {
+		task.Estimate = *taskUpdate.Estimate
+	}
+	if taskUpdate.ParentID != nil {
+		task.ParentID = nil
+		if !taskUpdate.ParentID.IsZero() {
+			parentID := *taskUpdate.ParentID
+			task.ParentID = &parentID
+		}
+	}
+	if taskUpdate.Tags != nil {
+		task.Tags = append([]string{}, *taskUpdate.Tags...)
+	}
+	if taskUpdate.Checklist != nil {
+		task.Checklist = nil
+		if len(*taskUpdate.Checklist) > 0 {
+			task.Checklist = append([]domain.ChecklistItem{}, *taskUpdate.Checklist...)
+		}
+	}
+	if taskUpdate.ClearCost {
+		task.Cost = nil
+	} else if taskUpdate.Cost != nil {
+		cost := *taskUpdate.Cost
+		task.Cost = &cost
+	}
+	if taskUpdate.ProjectID != nil {
+		task.ProjectID = nil
+		if !taskUpdate.ProjectID.IsZero() {
+			projectID := *taskUpdate.ProjectID
+			task.ProjectID = &projectID
+		}
+	}
+	if taskUpdate.Language != nil {
+		task.Language = *taskUpdate.Language
+	}
+	if taskUpdate.ClearReminder {
+		task.Reminder = nil
+	} else if taskUpdate.Reminder != nil {
+		taskUpdate.Reminder.SentAt = nil
+		reminder := *taskUpdate.Reminder
+		task.Reminder = &reminder
+	} else if taskUpdate.DueDate != nil && task.Reminder != nil {
+		task.Reminder.SentAt = nil
+	}
+	task.UpdatedAt = storedNow()
+
+	return cloneTask(task), nil
+}
+
+func (taskRepo *memoryTaskRepository) GetSubtasks(ctx context.Context, parentID string) ([]domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	subtasks := []domain.Task{}
+	for _, task := range taskRepo.tasks {
+		if task.ParentID != nil && *task.ParentID == objID {
+			subtasks = append(subtasks, *cloneTask(task))
+		}
+	}
+	sortByID(subtasks)
+
+	return subtasks, nil
+}
+
+func (taskRepo *memoryTaskRepository) GetDescendantIDs(ctx context.Context, taskID string) ([]primitive.ObjectID, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	if _, ok := taskRepo.tasks[objID]; !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	// walk the hierarchy downwards from the task
+	descendants := []primitive.ObjectID{}
+	seen := map[primitive.ObjectID]bool{objID: true}
+	queue := []primitive.ObjectID{objID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for id, task := range taskRepo.tasks {
+			if task.ParentID != nil && *task.ParentID == current && !seen[id] {
+				seen[id] = true
+				descendants = append(descendants, id)
+				queue = append(queue, id)
+			}
+		}
+	}
+
+	return descendants, nil
+}
+
+func (taskRepo *memoryTaskRepository) DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	for _, id := range taskIDs {
+		delete(taskRepo.tasks, id)
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.updateTags(taskID, func(tags []string) []string {
+		for _, existing := range tags {
+			if existing == tag {
+				return tags        // no duplicates
+			}
+		}
+		return append(tags, tag)
+	})
+}
+
+func (taskRepo *memoryTaskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.updateTags(taskID, func(tags []string) []string {
+		return removeTag(tags, tag)
+	})
+}
+
+// apply tag update to one task and return it
+func (taskRepo *memoryTaskRepository) updateTags(taskID string, update func([]string) []string) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	task, ok := taskRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	task.Tags = update(task.Tags)
+	task.UpdatedAt = storedNow()
+
+	return cloneTask(task), nil
+}
+
+func (taskRepo *memoryTaskRepository) RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	now := storedNow()
+	for _, task := range taskRepo.tasks {
+		for i, tag := range task.Tags {
+			if tag == oldTag {
+				task.Tags[i] = newTag
+				task.UpdatedAt = now
+			}
+		}
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) RemoveTagFromAll(ctx context.Context, tag string) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	now := storedNow()
+	for _, task := range taskRepo.tasks {
+		if tags := removeTag(task.Tags, tag); len(tags) != len(task.Tags) {
+			task.Tags = tags
+			task.UpdatedAt = now
+		}
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) UpdateOverdueFlags(ctx context.Context, now time.Time) (int64, error) {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	var updated int64
+	for _, task := range taskRepo.tasks {
+		overdue := task.DueDate.Before(now) && task.Status != "completed"
+		if task.IsOverdue != overdue {
+			task.IsOverdue = overdue
+			task.UpdatedAt = storedNow()
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *memoryTaskRepository) BackfillPriorities(ctx context.Context) (int64, error) {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	var updated int64
+	for _, task := range taskRepo.tasks {
+		priority := task.Priority
+		if priority == "" {
+			priority = domain.DefaultTaskPriority
+		}
+		rank, ok := domain.TaskPriorities[priority]
+		if !ok || (task.Priority == priority && task.PriorityRank == rank) {
+			continue
+		}
+		task.Priority, task.PriorityRank = priority, rank
+		updated++
+	}
+
+	return updated, nil
+}
+
+// tasks kept in memory always have timestamps
+func (taskRepo *memoryTaskRepository) BackfillTimestamps(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// nothing to index in memory
+func (taskRepo *memoryTaskRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) GetTasksDueForReminder(ctx context.Context, dueBefore time.Time) ([]domain.Task, error) {
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	// enabled reminders not sent yet on unfinished tasks that aren't overdue
+	now := time.Now()
+	var tasks []domain.Task
+	for _, task := range taskRepo.tasks {
+		if task.Reminder == nil || !task.Reminder.Enabled || task.Reminder.SentAt != nil || task.Status == "completed" {
+			continue
+		}
+		if task.DueDate.Before(now) || task.DueDate.After(dueBefore) {
+			continue
+		}
+		tasks = append(tasks, *cloneTask(task))
+	}
+	sortByID(tasks)
+
+	return tasks, nil
+}
+
+func (taskRepo *memoryTaskRepository) MarkReminderSent(ctx context.Context, taskID primitive.ObjectID, sentAt time.Time) error {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	if task, ok := taskRepo.tasks[taskID]; ok && task.Reminder != nil {
+		task.Reminder.SentAt = &sentAt
+		task.UpdatedAt = storedNow()
+	}
+
+	return nil
+}
+
+func (taskRepo *memoryTaskRepository) GetRecurringTasksDue(ctx context.Context, now time.Time) ([]domain.Task, error) {
+
+	taskRepo.mutex.RLock()
+	defer taskRepo.mutex.RUnlock()
+
+	// occurrences of active series that are completed or due
+	var tasks []domain.Task
+	for _, task := range taskRepo.tasks {
+		if task.Recurrence == nil || task.Recurrence.Paused || task.Recurrence.AdvancedAt != nil {
+			continue
+		}
+		if task.Status != "completed" && task.DueDate.After(now) {
+			continue
+		}
+		tasks = append(tasks, *cloneTask(task))
+	}
+	sortByID(tasks)
+
+	return tasks, nil
+}
+
+func (taskRepo *memoryTaskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	task, ok := taskRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	task.Recurrence = nil
+	if rule != nil {
+		task.Recurrence = cloneTask(&domain.Task{Recurrence: rule}).Recurrence
+	}
+	task.UpdatedAt = storedNow()
+
+	return cloneTask(task), nil
+}
+
+func (taskRepo *memoryTaskRepository) AdvanceRecurrence(ctx context.Context, taskID primitive.ObjectID, at time.Time) (bool, error) {
+
+	taskRepo.mutex.Lock()
+	defer taskRepo.mutex.Unlock()
+
+	task, ok := taskRepo.tasks[taskID]
+	if !ok || task.Recurrence == nil || task.Recurrence.AdvancedAt != nil {
+		return false, nil
+	}
+	task.Recurrence.AdvancedAt = &at
+	task.UpdatedAt = storedNow()
+
+	return true, nil
+}
+
+// copy of a task that shares no pointers or slices with the stored one
+func cloneTask(task *domain.Task) *domain.Task {
+	clone := *task
+	if task.ParentID != nil {
+		parentID := *task.ParentID
+		clone.ParentID = &parentID
+	}
+	if task.Reminder != nil {
+		reminder := *task.Reminder
+		if task.Reminder.SentAt != nil {
+			sentAt := *task.Reminder.SentAt
+			reminder.SentAt = &sentAt
+		}
+		clone.Reminder = &reminder
+	}
+	if task.Tags != nil {
+		clone.Tags = append([]string{}, task.Tags...)
+	}
+	if task.Checklist != nil {
+		clone.Checklist = append([]domain.ChecklistItem{}, task.Checklist...)
+	}
+	if task.Cost != nil {
+		cost := *task.Cost
+		clone.Cost = &cost
+	}
+	if task.ProjectID != nil {
+		projectID := *task.ProjectID
+		clone.ProjectID = &projectID
+	}
+	if task.Recurrence != nil {
+		recurrence := *task.Recurrence
+		if task.Recurrence.EndsAt != nil {
+			endsAt := *task.Recurrence.EndsAt
+			recurrence.EndsAt = &endsAt
+		}
+		if task.Recurrence.AdvancedAt != nil {
+			advancedAt := *task.Recurrence.AdvancedAt
+			recurrence.AdvancedAt = &advancedAt
+		}
+		clone.Recurrence = &recurrence
+	}
+	if task.StatusEnteredAt != nil {
+		clone.StatusEnteredAt = map[string]time.Time{}
+		for status, at := range task.StatusEnteredAt {
+			clone.StatusEnteredAt[status] = at
+		}
+	}
+	return &clone
+}
+
+// check task labels against a label filter
+func matchesLabels(tags []string, labels []string, matchAll bool) bool {
+	found := 0
+	for _, label := range labels {
+		for _, tag := range tags {
+			if tag == label {
+				found++
+				break
+			}
+		}
+	}
+	if matchAll {
+		return found == len(labels)
+	}
+	return found > 0
+}
+
+// check if an id is in a list of ids
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// check task project against the project filter and visibility
+func matchesProject(projectID *primitive.ObjectID, query domain.TaskQuery) bool {
+	if query.ProjectID != nil {
+		return projectID != nil && *projectID == *query.ProjectID
+	}
+	if query.Visibility == nil || projectID == nil {
+		return true
+	}
+	for _, visible := range query.Visibility.Projects {
+		if visible == *projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// check if title or description contain any search word like the text index does (without stemming)
+func matchesSearch(task *domain.Task, search string) bool {
+	text := strings.ToLower(task.Title + " " + task.Description)
+	for _, word := range strings.Fields(strings.ToLower(search)) {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare two tasks on a sortable field (-1, 0, 1)
+func compareTasks(a *domain.Task, b *domain.Task, field string) int {
+	switch field {
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "due_date":
+		return a.DueDate.Compare(b.DueDate)
+	case "status":
+		return strings.Compare(a.Status, b.Status)
+	case "priority":
+		return a.PriorityRank - b.PriorityRank
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+	return 0
+}
+
+// order tasks by id (creation order)
+func sortByID(tasks []domain.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return bytes.Compare(tasks[i].ID[:], tasks[j].ID[:]) < 0
+	})
+}
+
+// list without the given tag
+func removeTag(tags []string, tag string) []string {
+	kept := tags[:0]
+	for _, existing := range tags {
+		if existing != tag {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
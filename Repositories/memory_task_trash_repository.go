@@ -0,0 +1,175 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory task trash (used with the in-memory task repository, contents are lost on restart)
+type memoryTaskTrashRepository struct {
+	mutex  sync.RWMutex
+	tasks  map[primitive.ObjectID]*domain.DeletedTask
+}
+
+func NewMemoryTaskTrashRepository() domain.TaskTrashRepository {
+	return &memoryTaskTrashRepository{tasks: map[primitive.ObjectID]*domain.DeletedTask{}}
+}
+
+func (trashRepo *memoryTaskTrashRepository) Add(ctx context.Context, tasks []domain.DeletedTask) error {
+
+	trashRepo.mutex.Lock()
+	defer trashRepo.mutex.Unlock()
+
+	for _, task := range tasks {
+		deleted := task
+		deleted.Task = *cloneTask(&task.Task)
+		trashRepo.tasks[task.ID] = &deleted
+	}
+
+	return nil
+}
+
+func (trashRepo *memoryTaskTrashRepository) GetDeletedTasks(ctx context.Context, query domain.TrashQuery) ([]domain.DeletedTask, error) {
+
+	trashRepo.mutex.RLock()
+	defer trashRepo.mutex.RUnlock()
+
+	visibility := domain.TaskQuery{Visibility: query.Visibility}
+	tasks := []domain.DeletedTask{}
+	for _, task := range trashRepo.tasks {
+		if query.TenantID != nil && task.TenantID != *query.TenantID {
+			continue
+		}
+		if !matchesProject(task.ProjectID, visibility) {
+			continue
+		}
+		if !query.Before.IsZero() && !task.DeletedAt.Before(query.Before) {
+			continue
+		}
+		tasks = append(tasks, cloneDeletedTask(task))
+	}
+
+	// newest first like the mongodb index
+	sortDeletedTasks(tasks, true)
+	if query.Limit > 0 && int64(len(tasks)) > query.Limit {
+		tasks = tasks[:query.Limit]
+	}
+
+	return tasks, nil
+}
+
+func (trashRepo *memoryTaskTrashRepository) GetDeletedTask(ctx context.Context, taskID string) (*domain.DeletedTask, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	trashRepo.mutex.RLock()
+	defer trashRepo.mutex.RUnlock()
+
+	task, ok := trashRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrDeletedTaskNotFound
+	}
+
+	deleted := cloneDeletedTask(task)
+	return &deleted, nil
+}
+
+func (trashRepo *memoryTaskTrashRepository) Restore(ctx context.Context, taskID string) ([]domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	trashRepo.mutex.Lock()
+	defer trashRepo.mutex.Unlock()
+
+	deleted, ok := trashRepo.tasks[objID]
+	if !ok {
+		return nil, domain.ErrDeletedTaskNotFound
+	}
+
+	subtasks := []domain.DeletedTask{}
+	for _, task := range trashRepo.tasks {
+		if task.DeletedWith != nil && *task.DeletedWith == objID {
+			subtasks = append(subtasks, cloneDeletedTask(task))
+		}
+	}
+	sort.Slice(subtasks, func(i, j int) bool {
+		return bytes.Compare(subtasks[i].ID[:], subtasks[j].ID[:]) < 0
+	})
+
+	tasks := []domain.Task{*cloneTask(&deleted.Task)}
+	delete(trashRepo.tasks, objID)
+	for _, subtask := range subtasks {
+		tasks = append(tasks, subtask.Task)
+		delete(trashRepo.tasks, subtask.ID)
+	}
+
+	return tasks, nil
+}
+
+func (trashRepo *memoryTaskTrashRepository) Purge(ctx context.Context, deletedBefore time.Time, tenantID *string, limit int64) ([]primitive.ObjectID, error) {
+
+	trashRepo.mutex.Lock()
+	defer trashRepo.mutex.Unlock()
+
+	tasks := []domain.DeletedTask{}
+	for _, task := range trashRepo.tasks {
+		if !task.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		if tenantID != nil && task.TenantID != *tenantID {
+			continue
+		}
+		tasks = append(tasks, *task)
+	}
+
+	// oldest first
+	sortDeletedTasks(tasks, false)
+	if limit > 0 && int64(len(tasks)) > limit {
+		tasks = tasks[:limit]
+	}
+
+	ids := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+		delete(trashRepo.tasks, task.ID)
+	}
+
+	return ids, nil
+}
+
+func (trashRepo *memoryTaskTrashRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// order deleted tasks by deletion time, then id
+func sortDeletedTasks(tasks []domain.DeletedTask, newestFirst bool) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].DeletedAt.Equal(tasks[j].DeletedAt) {
+			return tasks[i].DeletedAt.Before(tasks[j].DeletedAt) != newestFirst
+		}
+		return (bytes.Compare(tasks[i].ID[:], tasks[j].ID[:]) < 0) != newestFirst
+	})
+}
+
+// copy a deleted task so callers can't change the stored one
+func cloneDeletedTask(task *domain.DeletedTask) domain.DeletedTask {
+	clone := *task
+	clone.Task = *cloneTask(&task.Task)
+	if task.DeletedWith != nil {
+		deletedWith := *task.DeletedWith
+		clone.DeletedWith = &deletedWith
+	}
+	return clone
+}
@@ -0,0 +1,144 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory task templates
+type memoryTemplateRepository struct {
+	mutex      sync.RWMutex
+	templates  map[primitive.ObjectID]*domain.TaskTemplate
+}
+
+func NewMemoryTemplateRepository() domain.TemplateRepository {
+	return &memoryTemplateRepository{templates: map[primitive.ObjectID]*domain.TaskTemplate{}}
+}
+
+func (templateRepo *memoryTemplateRepository) CreateTemplate(ctx context.Context, template *domain.TaskTemplate) error {
+
+	templateRepo.mutex.Lock()
+	defer templateRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if template.ID.IsZero() {
+		template.ID = primitive.NewObjectID()
+	}
+	templateRepo.templates[template.ID] = cloneTemplate(template)
+
+	return nil
+}
+
+func (templateRepo *memoryTemplateRepository) GetTemplates(ctx context.Context, ownerID string) ([]domain.TaskTemplate, error) {
+
+	templateRepo.mutex.RLock()
+	defer templateRepo.mutex.RUnlock()
+
+	templates := []domain.TaskTemplate{}
+	for _, template := range templateRepo.templates {
+		if template.OwnerID == ownerID {
+			templates = append(templates, *cloneTemplate(template))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Title < templates[j].Title })
+
+	return templates, nil
+}
+
+func (templateRepo *memoryTemplateRepository) GetTemplateByID(ctx context.Context, templateID string) (*domain.TaskTemplate, error) {
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, domain.ErrInvalidTemplateID
+	}
+
+	templateRepo.mutex.RLock()
+	defer templateRepo.mutex.RUnlock()
+
+	template, ok := templateRepo.templates[objID]
+	if !ok {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return cloneTemplate(template), nil
+}
+
+func (templateRepo *memoryTemplateRepository) UpdateTemplate(ctx context.Context, templateID string, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, domain.ErrInvalidTemplateID
+	}
+
+	templateRepo.mutex.Lock()
+	defer templateRepo.mutex.Unlock()
+
+	stored, ok := templateRepo.templates[objID]
+	if !ok {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	stored.UpdatedAt = template.UpdatedAt
+	if template.Title != "" {
+		stored.Title = template.Title
+	}
+	if template.Description != "" {
+		stored.Description = template.Description
+	}
+	if template.Priority != "" {
+		stored.Priority = template.Priority
+	}
+	// nil lists are left, empty ones cleared
+	if template.Checklist != nil {
+		stored.Checklist = nil
+		if len(template.Checklist) > 0 {
+			stored.Checklist = append([]string(nil), template.Checklist...)
+		}
+	}
+	if template.Tags != nil {
+		stored.Tags = nil
+		if len(template.Tags) > 0 {
+			stored.Tags = append([]string(nil), template.Tags...)
+		}
+	}
+
+	return cloneTemplate(stored), nil
+}
+
+func (templateRepo *memoryTemplateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return domain.ErrInvalidTemplateID
+	}
+
+	templateRepo.mutex.Lock()
+	defer templateRepo.mutex.Unlock()
+
+	if _, ok := templateRepo.templates[objID]; !ok {
+		return domain.ErrTemplateNotFound
+	}
+	delete(templateRepo.templates, objID)
+
+	return nil
+}
+
+func (templateRepo *memoryTemplateRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copy a template so callers can't change the stored lists
+func cloneTemplate(template *domain.TaskTemplate) *domain.TaskTemplate {
+	clone := *template
+	if template.Checklist != nil {
+		clone.Checklist = append([]string(nil), template.Checklist...)
+	}
+	if template.Tags != nil {
+		clone.Tags = append([]string(nil), template.Tags...)
+	}
+	return &clone
+}
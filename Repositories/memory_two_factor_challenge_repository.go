@@ -0,0 +1,76 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sync";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory two-factor login challenges
+type memoryTwoFactorChallengeRepository struct {
+	mutex       sync.Mutex
+	challenges  map[primitive.ObjectID]*domain.TwoFactorChallenge
+}
+
+func NewMemoryTwoFactorChallengeRepository() domain.TwoFactorChallengeRepository {
+	return &memoryTwoFactorChallengeRepository{challenges: map[primitive.ObjectID]*domain.TwoFactorChallenge{}}
+}
+
+func (challengeRepo *memoryTwoFactorChallengeRepository) CreateChallenge(ctx context.Context, challenge *domain.TwoFactorChallenge) error {
+
+	challengeRepo.mutex.Lock()
+	defer challengeRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if challenge.ID.IsZero() {
+		challenge.ID = primitive.NewObjectID()
+	}
+	stored := *challenge
+	challengeRepo.challenges[challenge.ID] = &stored
+
+	return nil
+}
+
+func (challengeRepo *memoryTwoFactorChallengeRepository) GetChallengeByHash(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error) {
+
+	challengeRepo.mutex.Lock()
+	defer challengeRepo.mutex.Unlock()
+
+	for _, challenge := range challengeRepo.challenges {
+		if challenge.TokenHash == tokenHash {
+			found := *challenge
+			return &found, nil
+		}
+	}
+
+	return nil, domain.ErrInvalidTwoFactorChallenge
+}
+
+func (challengeRepo *memoryTwoFactorChallengeRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) (int, error) {
+
+	challengeRepo.mutex.Lock()
+	defer challengeRepo.mutex.Unlock()
+
+	challenge, ok := challengeRepo.challenges[id]
+	if !ok {
+		return 0, domain.ErrInvalidTwoFactorChallenge
+	}
+	challenge.Attempts++
+
+	return challenge.Attempts, nil
+}
+
+func (challengeRepo *memoryTwoFactorChallengeRepository) DeleteChallenge(ctx context.Context, id primitive.ObjectID) error {
+
+	challengeRepo.mutex.Lock()
+	defer challengeRepo.mutex.Unlock()
+
+	delete(challengeRepo.challenges, id)
+	return nil
+}
+
+func (challengeRepo *memoryTwoFactorChallengeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
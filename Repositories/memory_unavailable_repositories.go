@@ -0,0 +1,80 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// reports, integrity checks, usage statistics and the archive query mongodb collections directly,
+// with in-memory storage they answer ErrMemoryStorage (nothing is ever archived)
+
+type memoryReportRepository struct{}
+
+func NewMemoryReportRepository() domain.ReportRepository {
+	return memoryReportRepository{}
+}
+
+func (memoryReportRepository) TaskSummary(ctx context.Context, scope domain.ReportScope, weekStart time.Time) (*domain.TaskSummaryReport, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+func (memoryReportRepository) Productivity(ctx context.Context, scope domain.ReportScope, from time.Time, to time.Time) (*domain.ProductivityReport, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+type memoryIntegrityRepository struct{}
+
+func NewMemoryIntegrityRepository() domain.IntegrityRepository {
+	return memoryIntegrityRepository{}
+}
+
+func (memoryIntegrityRepository) FindIssues(ctx context.Context, check string, now time.Time) ([]domain.IntegrityIssue, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+func (memoryIntegrityRepository) RepairIssues(ctx context.Context, check string, issues []domain.IntegrityIssue, now time.Time) (int64, error) {
+	return 0, domain.ErrMemoryStorage
+}
+
+type memoryUsageStatsRepository struct{}
+
+func NewMemoryUsageStatsRepository() domain.UsageStatsRepository {
+	return memoryUsageStatsRepository{}
+}
+
+func (memoryUsageStatsRepository) GetUsageCounts(ctx context.Context) (*domain.UsageCounts, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+type memoryTaskArchiveRepository struct{}
+
+func NewMemoryTaskArchiveRepository() domain.TaskArchiveRepository {
+	return memoryTaskArchiveRepository{}
+}
+
+func (memoryTaskArchiveRepository) FindArchivable(ctx context.Context, completedBefore time.Time, limit int64) ([]domain.Task, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+func (memoryTaskArchiveRepository) Archive(ctx context.Context, tasks []domain.Task, archivedAt time.Time) ([]domain.Task, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+func (memoryTaskArchiveRepository) GetArchivedTasks(ctx context.Context, query domain.ArchiveQuery) ([]domain.ArchivedTask, error) {
+	return nil, domain.ErrMemoryStorage
+}
+
+// history and unarchiving look tasks up here, none are archived
+func (memoryTaskArchiveRepository) GetArchivedTask(ctx context.Context, taskID string) (*domain.ArchivedTask, error) {
+	return nil, domain.ErrArchivedTaskNotFound
+}
+
+func (memoryTaskArchiveRepository) Restore(ctx context.Context, taskID string, restoredAt time.Time) (*domain.Task, error) {
+	return nil, domain.ErrArchivedTaskNotFound
+}
+
+func (memoryTaskArchiveRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,409 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"strings";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory user repository (demo mode and tests, contents are lost on restart)
+type memoryUserRepository struct {
+	mutex  sync.RWMutex
+	users  map[primitive.ObjectID]*domain.User
+}
+
+func NewMemoryUserRepository() domain.UserRepository {
+	return &memoryUserRepository{users: map[primitive.ObjectID]*domain.User{}}
+}
+
+func (userRepo *memoryUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	// same uniqueness as the mongodb indexes
+	for _, existing := range userRepo.users {
+		if existing.Username == user.Username || (user.Email != "" && existing.Email == user.Email) {
+			return domain.ErrUserExists
+		}
+		for _, identity := range user.Identities {
+			if hasIdentity(existing, identity.Provider, identity.Subject) {
+				return domain.ErrUserExists
+			}
+		}
+	}
+
+	// generate new ObjectID if not set
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.CreatedAt = storedNow()        // server clock, client values are ignored
+	user.UpdatedAt = user.CreatedAt
+	userRepo.users[user.ID] = cloneUser(user)
+
+	return nil
+}
+
+func (userRepo *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return userRepo.find(func(user *domain.User) bool { return user.Username == username })
+}
+
+func (userRepo *memoryUserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	return userRepo.find(func(user *domain.User) bool { return user.ID == id })
+}
+
+func (userRepo *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return userRepo.find(func(user *domain.User) bool { return user.Email == email })
+}
+
+// first user matching a condition
+func (userRepo *memoryUserRepository) find(match func(user *domain.User) bool) (*domain.User, error) {
+
+	userRepo.mutex.RLock()
+	defer userRepo.mutex.RUnlock()
+
+	for _, user := range userRepo.users {
+		if match(user) {
+			return cloneUser(user), nil
+		}
+	}
+
+	return nil, domain.ErrUserNotFound
+}
+
+func (userRepo *memoryUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.Password = hashedPassword
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, profile *domain.UpdateProfileRequest) (*domain.User, error) {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// only set fields that were provided
+	if profile.Email != "" {
+		for otherID, other := range userRepo.users {
+			if otherID != id && other.Email == profile.Email {
+				return nil, domain.ErrUserExists        // email belongs to another account
+			}
+		}
+		user.Email = profile.Email
+	}
+	if profile.DisplayName != "" {
+		user.DisplayName = profile.DisplayName
+	}
+	if profile.Notifications != nil {
+		preferences := *profile.Notifications
+		preferences.Muted = append([]string(nil), preferences.Muted...)
+		user.Notifications = &preferences
+	}
+	user.UpdatedAt = storedNow()
+
+	return cloneUser(user), nil
+}
+
+func (userRepo *memoryUserRepository) GetUserCount(ctx context.Context) (int64, error) {
+
+	userRepo.mutex.RLock()
+	defer userRepo.mutex.RUnlock()
+
+	return int64(len(userRepo.users)), nil
+}
+
+func (userRepo *memoryUserRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.Role = role
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error) {
+
+	var failures int
+	err := userRepo.update(id, func(user *domain.User) {
+		user.FailedLogins++
+		failures = user.FailedLogins
+	})
+
+	return failures, err
+}
+
+func (userRepo *memoryUserRepository) LockUser(ctx context.Context, id primitive.ObjectID, until time.Time) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.LockedUntil = &until
+		user.FailedLogins = 0
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.FailedLogins = 0
+		user.LockedUntil = nil
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) ListUsers(ctx context.Context, query domain.UserQuery) ([]domain.User, int64, error) {
+
+	userRepo.mutex.RLock()
+	defer userRepo.mutex.RUnlock()
+
+	search := strings.ToLower(query.Search)
+	matched := []domain.User{}
+	for _, user := range userRepo.users {
+		if search != "" && !strings.Contains(strings.ToLower(user.Username), search) &&
+			!strings.Contains(strings.ToLower(user.Email), search) && !strings.Contains(strings.ToLower(user.DisplayName), search) {
+			continue
+		}
+		if query.Role != "" && user.Role != query.Role {
+			continue
+		}
+		if (query.Status == domain.UserStatusActive && user.IsDeactivated()) || (query.Status == domain.UserStatusDeactivated && !user.IsDeactivated()) {
+			continue
+		}
+		if query.TenantID != nil && user.TenantID != *query.TenantID {
+			continue
+		}
+		matched = append(matched, *cloneUser(user))
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return lessUser(&matched[i], &matched[j], query.Sort) })
+
+	// same skip and limit as the mongodb query
+	start := (query.Page - 1) * query.Limit
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + query.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return matched[start:end], int64(len(matched)), nil
+}
+
+func (userRepo *memoryUserRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string, at time.Time) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok || user.Email != email {
+		return domain.ErrUserNotFound
+	}
+	verifiedAt := at
+	user.EmailVerifiedAt = &verifiedAt
+	user.EmailVerificationPending = false
+	user.UpdatedAt = storedNow()
+	return nil
+}
+
+func (userRepo *memoryUserRepository) SetTwoFactor(ctx context.Context, id primitive.ObjectID, settings *domain.TwoFactorSettings) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.TwoFactor = cloneTwoFactor(settings)
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) UseTwoFactorStep(ctx context.Context, id primitive.ObjectID, step int64) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if !user.TwoFactor.IsEnabled() || user.TwoFactor.LastUsedStep >= step {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	user.TwoFactor.LastUsedStep = step
+	return nil
+}
+
+func (userRepo *memoryUserRepository) UseBackupCode(ctx context.Context, id primitive.ObjectID, codeHash string) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if !user.TwoFactor.IsEnabled() {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	for i, stored := range user.TwoFactor.BackupCodes {
+		if stored == codeHash {
+			user.TwoFactor.BackupCodes = append(user.TwoFactor.BackupCodes[:i:i], user.TwoFactor.BackupCodes[i+1:]...)
+			user.UpdatedAt = storedNow()
+			return nil
+		}
+	}
+	return domain.ErrInvalidTwoFactorCode
+}
+
+func (userRepo *memoryUserRepository) SetDeactivated(ctx context.Context, id primitive.ObjectID, at *time.Time) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.DeactivatedAt = nil
+		if at != nil {
+			deactivatedAt := *at
+			user.DeactivatedAt = &deactivatedAt
+		}
+		user.UpdatedAt = storedNow()
+	})
+}
+
+func (userRepo *memoryUserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	if _, ok := userRepo.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(userRepo.users, id)
+
+	return nil
+}
+
+func (userRepo *memoryUserRepository) GetByIdentity(ctx context.Context, provider string, subject string) (*domain.User, error) {
+	return userRepo.find(func(user *domain.User) bool { return hasIdentity(user, provider, subject) })
+}
+
+func (userRepo *memoryUserRepository) LinkIdentity(ctx context.Context, id primitive.ObjectID, identity domain.LinkedIdentity) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	// same uniqueness as the mongodb index
+	for otherID, other := range userRepo.users {
+		if hasIdentity(other, identity.Provider, identity.Subject) {
+			if otherID != id {
+				return domain.ErrUserExists
+			}
+			return nil
+		}
+	}
+	user.Identities = append(user.Identities, identity)
+	user.UpdatedAt = storedNow()
+
+	return nil
+}
+
+func (userRepo *memoryUserRepository) SetTenant(ctx context.Context, id primitive.ObjectID, tenantID string) error {
+	return userRepo.update(id, func(user *domain.User) {
+		user.TenantID = tenantID
+		user.UpdatedAt = storedNow()
+	})
+}
+
+// check if a provider account is linked to a user
+func hasIdentity(user *domain.User, provider string, subject string) bool {
+	for _, identity := range user.Identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// same order as the mongodb sort: requested field, then username
+func lessUser(left, right *domain.User, order domain.SortField) bool {
+	var cmp int
+	switch order.Field {
+	case "created_at":
+		cmp = left.CreatedAt.Compare(right.CreatedAt)
+	case "updated_at":
+		cmp = left.UpdatedAt.Compare(right.UpdatedAt)
+	case "username":
+		cmp = strings.Compare(left.Username, right.Username)
+	}
+	if order.Descending {
+		cmp = -cmp
+	}
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return left.Username < right.Username
+}
+
+// documents are created with timestamps
+func (userRepo *memoryUserRepository) BackfillTimestamps(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// uniqueness is checked on every write
+func (userRepo *memoryUserRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// apply a change to one user or return error if not found
+func (userRepo *memoryUserRepository) update(id primitive.ObjectID, change func(user *domain.User)) error {
+
+	userRepo.mutex.Lock()
+	defer userRepo.mutex.Unlock()
+
+	user, ok := userRepo.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	change(user)
+
+	return nil
+}
+
+// copy of a user that shares no pointers with the stored one
+func cloneTwoFactor(settings *domain.TwoFactorSettings) *domain.TwoFactorSettings {
+	if settings == nil {
+		return nil
+	}
+	clone := *settings
+	if settings.EnabledAt != nil {
+		enabledAt := *settings.EnabledAt
+		clone.EnabledAt = &enabledAt
+	}
+	clone.BackupCodes = append([]string(nil), settings.BackupCodes...)
+	return &clone
+}
+
+func cloneUser(user *domain.User) *domain.User {
+	clone := *user
+	if user.LockedUntil != nil {
+		lockedUntil := *user.LockedUntil
+		clone.LockedUntil = &lockedUntil
+	}
+	if user.DeactivatedAt != nil {
+		deactivatedAt := *user.DeactivatedAt
+		clone.DeactivatedAt = &deactivatedAt
+	}
+	if user.EmailVerifiedAt != nil {
+		verifiedAt := *user.EmailVerifiedAt
+		clone.EmailVerifiedAt = &verifiedAt
+	}
+	clone.Identities = append([]domain.LinkedIdentity(nil), user.Identities...)
+	clone.TwoFactor = cloneTwoFactor(user.TwoFactor)
+	if user.Notifications != nil {
+		preferences := *user.Notifications
+		preferences.Muted = append([]string(nil), preferences.Muted...)
+		clone.Notifications = &preferences
+	}
+	return &clone
+}
@@ -0,0 +1,96 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory delivery log, expired deliveries are dropped on the next write
+type memoryWebhookDeliveryRepository struct {
+	mutex       sync.RWMutex
+	deliveries  []domain.WebhookDelivery
+}
+
+func NewMemoryWebhookDeliveryRepository() domain.WebhookDeliveryRepository {
+	return &memoryWebhookDeliveryRepository{}
+}
+
+func (deliveryRepo *memoryWebhookDeliveryRepository) RecordDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+
+	deliveryRepo.mutex.Lock()
+	defer deliveryRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	kept := deliveryRepo.deliveries[:0]
+	for _, stored := range deliveryRepo.deliveries {
+		if stored.ExpiresAt.IsZero() || stored.ExpiresAt.After(now) {
+			kept = append(kept, stored)
+		}
+	}
+	deliveryRepo.deliveries = append(kept, cloneWebhookDelivery(*delivery))
+
+	return nil
+}
+
+func (deliveryRepo *memoryWebhookDeliveryRepository) GetDeliveries(ctx context.Context, webhookID string, before time.Time, limit int64) ([]domain.WebhookDelivery, error) {
+
+	deliveryRepo.mutex.RLock()
+	defer deliveryRepo.mutex.RUnlock()
+
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range deliveryRepo.deliveries {
+		if delivery.WebhookID == webhookID && (before.IsZero() || delivery.CreatedAt.Before(before)) {
+			deliveries = append(deliveries, cloneWebhookDelivery(delivery))
+		}
+	}
+
+	// newest first like the mongodb sort
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return bytes.Compare(deliveries[i].ID[:], deliveries[j].ID[:]) > 0
+	})
+	if limit > 0 && int64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (deliveryRepo *memoryWebhookDeliveryRepository) DeleteDeliveries(ctx context.Context, webhookID string) error {
+
+	deliveryRepo.mutex.Lock()
+	defer deliveryRepo.mutex.Unlock()
+
+	kept := deliveryRepo.deliveries[:0]
+	for _, delivery := range deliveryRepo.deliveries {
+		if delivery.WebhookID != webhookID {
+			kept = append(kept, delivery)
+		}
+	}
+	deliveryRepo.deliveries = kept
+
+	return nil
+}
+
+func (deliveryRepo *memoryWebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copy a delivery so callers can't change the stored attempts
+func cloneWebhookDelivery(delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	delivery.Attempts = append([]domain.WebhookAttempt(nil), delivery.Attempts...)
+	return delivery
+}
@@ -0,0 +1,159 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory webhooks
+type memoryWebhookRepository struct {
+	mutex     sync.RWMutex
+	webhooks  map[primitive.ObjectID]*domain.Webhook
+}
+
+func NewMemoryWebhookRepository() domain.WebhookRepository {
+	return &memoryWebhookRepository{webhooks: map[primitive.ObjectID]*domain.Webhook{}}
+}
+
+func (webhookRepo *memoryWebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+
+	webhookRepo.mutex.Lock()
+	defer webhookRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if webhook.ID.IsZero() {
+		webhook.ID = primitive.NewObjectID()
+	}
+	webhookRepo.webhooks[webhook.ID] = cloneWebhook(webhook)
+
+	return nil
+}
+
+func (webhookRepo *memoryWebhookRepository) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+
+	webhookRepo.mutex.RLock()
+	defer webhookRepo.mutex.RUnlock()
+
+	webhooks := []domain.Webhook{}
+	for _, webhook := range webhookRepo.webhooks {
+		webhooks = append(webhooks, *cloneWebhook(webhook))
+	}
+
+	// ids grow with the creation time
+	sort.Slice(webhooks, func(i, j int) bool { return bytes.Compare(webhooks[i].ID[:], webhooks[j].ID[:]) < 0 })
+
+	return webhooks, nil
+}
+
+func (webhookRepo *memoryWebhookRepository) GetWebhookByID(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrInvalidWebhookID
+	}
+
+	webhookRepo.mutex.RLock()
+	defer webhookRepo.mutex.RUnlock()
+
+	webhook, ok := webhookRepo.webhooks[objID]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	return cloneWebhook(webhook), nil
+}
+
+func (webhookRepo *memoryWebhookRepository) UpdateWebhook(ctx context.Context, webhookID string, webhook *domain.Webhook) (*domain.Webhook, error) {
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrInvalidWebhookID
+	}
+
+	webhookRepo.mutex.Lock()
+	defer webhookRepo.mutex.Unlock()
+
+	stored, ok := webhookRepo.webhooks[objID]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	// the disabled mark goes with the enabled state, a failing streak can only end here
+	stored.URL = webhook.URL
+	stored.Events = append([]string(nil), webhook.Events...)
+	stored.Enabled = webhook.Enabled
+	stored.TenantID = webhook.TenantID
+	stored.DisabledAt = webhook.DisabledAt
+	stored.DisabledReason = ""
+	if webhook.DisabledAt != nil {
+		stored.DisabledReason = webhook.DisabledReason
+	}
+	if webhook.FailingSince == nil {
+		stored.FailingSince = nil
+	}
+
+	return cloneWebhook(stored), nil
+}
+
+func (webhookRepo *memoryWebhookRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrInvalidWebhookID
+	}
+
+	webhookRepo.mutex.Lock()
+	defer webhookRepo.mutex.Unlock()
+
+	if _, ok := webhookRepo.webhooks[objID]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	delete(webhookRepo.webhooks, objID)
+
+	return nil
+}
+
+func (webhookRepo *memoryWebhookRepository) RecordOutcome(ctx context.Context, webhookID string, succeeded bool, at time.Time) error {
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrInvalidWebhookID
+	}
+
+	webhookRepo.mutex.Lock()
+	defer webhookRepo.mutex.Unlock()
+
+	// webhooks deleted meanwhile are skipped, which is fine
+	webhook, ok := webhookRepo.webhooks[objID]
+	if !ok {
+		return nil
+	}
+	if succeeded {
+		webhook.FailingSince = nil
+	} else if webhook.FailingSince == nil {
+		webhook.FailingSince = &at
+	}
+
+	return nil
+}
+
+// copy a webhook so callers can't change the stored one
+func cloneWebhook(webhook *domain.Webhook) *domain.Webhook {
+	clone := *webhook
+	clone.Events = append([]string(nil), webhook.Events...)
+	if webhook.FailingSince != nil {
+		failingSince := *webhook.FailingSince
+		clone.FailingSince = &failingSince
+	}
+	if webhook.DisabledAt != nil {
+		disabledAt := *webhook.DisabledAt
+		clone.DisabledAt = &disabledAt
+	}
+	return &clone
+}
@@ -0,0 +1,170 @@
+package repositories
+
+// imports
+import (
+	"bytes";
+	"context";
+	"sort";
+	"sync";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// in-memory worklogs and running timers
+type memoryWorklogRepository struct {
+	mutex     sync.RWMutex
+	worklogs  map[primitive.ObjectID]*domain.Worklog
+}
+
+func NewMemoryWorklogRepository() domain.WorklogRepository {
+	return &memoryWorklogRepository{worklogs: map[primitive.ObjectID]*domain.Worklog{}}
+}
+
+func (worklogRepo *memoryWorklogRepository) StartTimer(ctx context.Context, worklog *domain.Worklog) error {
+
+	worklogRepo.mutex.Lock()
+	defer worklogRepo.mutex.Unlock()
+
+	// one running timer per user
+	if worklogRepo.runningTimer(worklog.UserID, "") != nil {
+		return domain.ErrTimerRunning
+	}
+
+	// generate new ObjectID if not set
+	if worklog.ID.IsZero() {
+		worklog.ID = primitive.NewObjectID()
+	}
+	worklog.Running = true
+	worklogRepo.worklogs[worklog.ID] = cloneWorklog(worklog)
+
+	return nil
+}
+
+func (worklogRepo *memoryWorklogRepository) GetRunningTimer(ctx context.Context, userID string) (*domain.Worklog, error) {
+
+	worklogRepo.mutex.RLock()
+	defer worklogRepo.mutex.RUnlock()
+
+	worklog := worklogRepo.runningTimer(userID, "")
+	if worklog == nil {
+		return nil, domain.ErrTimerNotRunning
+	}
+
+	return cloneWorklog(worklog), nil
+}
+
+func (worklogRepo *memoryWorklogRepository) StopTimer(ctx context.Context, userID string, taskID string, endedAt time.Time) (*domain.Worklog, error) {
+
+	worklogRepo.mutex.Lock()
+	defer worklogRepo.mutex.Unlock()
+
+	worklog := worklogRepo.runningTimer(userID, taskID)
+	if worklog == nil {
+		return nil, domain.ErrTimerNotRunning
+	}
+
+	seconds := int64(endedAt.Sub(worklog.StartedAt) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	worklog.Running = false
+	worklog.EndedAt = &endedAt
+	worklog.Seconds = seconds
+
+	return cloneWorklog(worklog), nil
+}
+
+func (worklogRepo *memoryWorklogRepository) AddWorklog(ctx context.Context, worklog *domain.Worklog) error {
+
+	worklogRepo.mutex.Lock()
+	defer worklogRepo.mutex.Unlock()
+
+	// generate new ObjectID if not set
+	if worklog.ID.IsZero() {
+		worklog.ID = primitive.NewObjectID()
+	}
+	worklogRepo.worklogs[worklog.ID] = cloneWorklog(worklog)
+
+	return nil
+}
+
+func (worklogRepo *memoryWorklogRepository) GetWorklogs(ctx context.Context, taskID string) ([]domain.Worklog, error) {
+
+	worklogRepo.mutex.RLock()
+	defer worklogRepo.mutex.RUnlock()
+
+	worklogs := []domain.Worklog{}
+	for _, worklog := range worklogRepo.worklogs {
+		if worklog.TaskID == taskID {
+			worklogs = append(worklogs, *cloneWorklog(worklog))
+		}
+	}
+
+	// oldest first like the mongodb sort
+	sort.Slice(worklogs, func(i, j int) bool {
+		if !worklogs[i].StartedAt.Equal(worklogs[j].StartedAt) {
+			return worklogs[i].StartedAt.Before(worklogs[j].StartedAt)
+		}
+		return bytes.Compare(worklogs[i].ID[:], worklogs[j].ID[:]) < 0
+	})
+
+	return worklogs, nil
+}
+
+func (worklogRepo *memoryWorklogRepository) DeleteWorklog(ctx context.Context, taskID string, worklogID string, userID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(worklogID)
+	if err != nil {
+		return domain.ErrInvalidWorklogID
+	}
+
+	worklogRepo.mutex.Lock()
+	defer worklogRepo.mutex.Unlock()
+
+	worklog, ok := worklogRepo.worklogs[objID]
+	if !ok || worklog.TaskID != taskID || (userID != "" && worklog.UserID != userID) {
+		return domain.ErrWorklogNotFound
+	}
+	delete(worklogRepo.worklogs, objID)
+
+	return nil
+}
+
+func (worklogRepo *memoryWorklogRepository) DeleteTaskWorklogs(ctx context.Context, taskID string) error {
+
+	worklogRepo.mutex.Lock()
+	defer worklogRepo.mutex.Unlock()
+
+	for id, worklog := range worklogRepo.worklogs {
+		if worklog.TaskID == taskID {
+			delete(worklogRepo.worklogs, id)
+		}
+	}
+
+	return nil
+}
+
+func (worklogRepo *memoryWorklogRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// running timer of a user, on the given task unless it is empty (callers hold the mutex)
+func (worklogRepo *memoryWorklogRepository) runningTimer(userID string, taskID string) *domain.Worklog {
+	for _, worklog := range worklogRepo.worklogs {
+		if worklog.Running && worklog.UserID == userID && (taskID == "" || worklog.TaskID == taskID) {
+			return worklog
+		}
+	}
+	return nil
+}
+
+// copy a worklog so callers can't change the stored one
+func cloneWorklog(worklog *domain.Worklog) *domain.Worklog {
+	clone := *worklog
+	if worklog.EndedAt != nil {
+		endedAt := *worklog.EndedAt
+		clone.EndedAt = &endedAt
+	}
+	return &clone
+}
@@ -0,0 +1,51 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// applied migrations keyed by version
+type migrationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMigrationRepository(col *mongo.Collection) domain.MigrationRepository {
+	return &migrationRepository{collection: col}
+}
+
+// migrations applied so far, lowest version first
+func (migrationRepo *migrationRepository) GetApplied(ctx context.Context) ([]domain.AppliedMigration, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	cursor, err := migrationRepo.collection.Find(contx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []domain.AppliedMigration{}
+	if err := cursor.All(contx, &applied); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// remember an applied migration, another instance recording it first is fine (migrations are safe to repeat)
+func (migrationRepo *migrationRepository) RecordApplied(ctx context.Context, migration domain.AppliedMigration) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := migrationRepo.collection.InsertOne(contx, migration)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
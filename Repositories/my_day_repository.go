@@ -0,0 +1,46 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type myDayRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMyDayRepository(col *mongo.Collection) domain.MyDayRepository {
+	return &myDayRepository{collection: col}
+}
+
+// find the user's order of today's tasks
+func (myDayRepo *myDayRepository) GetMyDay(ctx context.Context, userID string) (*domain.MyDay, error) {
+
+	var day domain.MyDay
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := myDayRepo.collection.FindOne(contx, bson.M{"_id": userID}).Decode(&day)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrMyDayNotFound
+		}
+		return nil, err
+	}
+
+	return &day, nil        // success
+}
+
+// replace the user's order (one document per user)
+func (myDayRepo *myDayRepository) SaveMyDay(ctx context.Context, day *domain.MyDay) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := myDayRepo.collection.ReplaceOne(contx, bson.M{"_id": day.UserID}, day, options.Replace().SetUpsert(true))
+	return err
+}
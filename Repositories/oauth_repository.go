@@ -0,0 +1,84 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type oauthRepository struct {
+	clientCollection *mongo.Collection
+	codeCollection   *mongo.Collection
+}
+
+func NewOAuthRepository(clientCol *mongo.Collection, codeCol *mongo.Collection) domain.OAuthRepository {
+	return &oauthRepository{clientCollection: clientCol, codeCollection: codeCol}
+}
+
+// register oauth client in to database
+func (oauthRepo *oauthRepository) CreateClient(ctx context.Context, client *domain.OAuthClient) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if client.ID.IsZero() {
+		client.ID = primitive.NewObjectID()
+	}
+
+	_, err := oauthRepo.clientCollection.InsertOne(contx, client)
+	if err != nil {
+		return err
+	}
+
+	return nil        // success
+}
+
+// find oauth client from database by its public client id
+func (oauthRepo *oauthRepository) GetClientByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+
+	var client domain.OAuthClient
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := oauthRepo.clientCollection.FindOne(contx, bson.M{"client_id": clientID}).Decode(&client)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOAuthClientNotFound
+		}
+		return nil, err
+	}
+
+	return &client, nil        // success
+}
+
+// store issued authorization code
+func (oauthRepo *oauthRepository) SaveAuthorizationCode(ctx context.Context, code *domain.OAuthAuthorizationCode) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := oauthRepo.codeCollection.InsertOne(contx, code)
+	return err
+}
+
+// fetch and delete authorization code atomically so it can only be used once
+func (oauthRepo *oauthRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+
+	var authCode domain.OAuthAuthorizationCode
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := oauthRepo.codeCollection.FindOneAndDelete(contx, bson.M{"_id": codeHash}).Decode(&authCode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	return &authCode, nil        // success
+}
@@ -0,0 +1,106 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type organizationRepository struct {
+	collection        *mongo.Collection      // organizations
+	inviteCollection  *mongo.Collection      // pending member invites
+}
+
+func NewOrganizationRepository(col *mongo.Collection, inviteCol *mongo.Collection) domain.OrganizationRepository {
+	return &organizationRepository{collection: col, inviteCollection: inviteCol}
+}
+
+// store new organization in database
+func (orgRepo *organizationRepository) CreateOrganization(ctx context.Context, org *domain.Organization) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if org.ID.IsZero() {
+		org.ID = primitive.NewObjectID()
+	}
+
+	_, err := orgRepo.collection.InsertOne(contx, org)
+	return err
+}
+
+// find organization by id
+func (orgRepo *organizationRepository) GetOrganizationByID(ctx context.Context, orgID string) (*domain.Organization, error) {
+
+	var org domain.Organization
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return nil, domain.ErrInvalidOrganizationID
+	}
+
+	err = orgRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&org)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	return &org, nil        // success
+}
+
+// store new invite in database
+func (orgRepo *organizationRepository) CreateInvite(ctx context.Context, invite *domain.OrganizationInvite) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if invite.ID.IsZero() {
+		invite.ID = primitive.NewObjectID()
+	}
+
+	_, err := orgRepo.inviteCollection.InsertOne(contx, invite)
+	return err
+}
+
+// remove an unexpired invite by its hash (concurrent accepts can't both get it)
+func (orgRepo *organizationRepository) ConsumeInvite(ctx context.Context, tokenHash string, now time.Time) (*domain.OrganizationInvite, error) {
+
+	var invite domain.OrganizationInvite
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// expired invites may still exist until the ttl index removes them
+	err := orgRepo.inviteCollection.FindOneAndDelete(contx, bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": now}}).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidOrgInvite
+		}
+		return nil, err
+	}
+
+	return &invite, nil        // success
+}
+
+// create ttl index (mongodb removes expired invites) and lookup index
+func (orgRepo *organizationRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := orgRepo.inviteCollection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},               // token lookup
+	})
+	return err
+}
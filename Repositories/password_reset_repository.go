@@ -0,0 +1,75 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type passwordResetRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPasswordResetRepository(col *mongo.Collection) domain.PasswordResetRepository {
+	return &passwordResetRepository{collection: col}
+}
+
+// store new reset token in database
+func (resetRepo *passwordResetRepository) CreateToken(ctx context.Context, token *domain.PasswordResetToken) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := resetRepo.collection.InsertOne(contx, token)
+	return err
+}
+
+// remove reset token by its hash and return it (concurrent requests can't both use it)
+func (resetRepo *passwordResetRepository) ConsumeToken(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+
+	var token domain.PasswordResetToken
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := resetRepo.collection.FindOneAndDelete(contx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidResetToken
+		}
+		return nil, err
+	}
+
+	return &token, nil        // success
+}
+
+// remove every reset token of a user
+func (resetRepo *passwordResetRepository) DeleteUserTokens(ctx context.Context, userID primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := resetRepo.collection.DeleteMany(contx, bson.M{"user_id": userID})
+	return err
+}
+
+// create ttl index (mongodb removes expired tokens) and lookup index
+func (resetRepo *passwordResetRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := resetRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},               // token lookup
+	})
+	return err
+}
@@ -0,0 +1,130 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type personalAccessTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPersonalAccessTokenRepository(col *mongo.Collection) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{collection: col}
+}
+
+// store new token in database
+func (patRepo *personalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := patRepo.collection.InsertOne(contx, token)
+	return err
+}
+
+// find all tokens of a user, newest first
+func (patRepo *personalAccessTokenRepository) GetTokensByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.PersonalAccessToken, error) {
+
+	var tokens []domain.PersonalAccessToken
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := patRepo.collection.Find(contx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens == nil {
+		return []domain.PersonalAccessToken{}, nil
+	}
+
+	return tokens, nil
+}
+
+// find token by its hash
+func (patRepo *personalAccessTokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+
+	var token domain.PersonalAccessToken
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := patRepo.collection.FindOne(contx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil        // success
+}
+
+// revoke a token owned by the user
+func (patRepo *personalAccessTokenRepository) RevokeToken(ctx context.Context, tokenID primitive.ObjectID, userID primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := patRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": tokenID, "user_id": userID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrTokenNotFound
+	}
+
+	return nil        // success
+}
+
+// revoke every active token of the user
+func (patRepo *personalAccessTokenRepository) RevokeUserTokens(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := patRepo.collection.UpdateMany(
+		contx,
+		bson.M{"user_id": userID, "revoked": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil        // success
+}
+
+// record last usage time of a token
+func (patRepo *personalAccessTokenRepository) TouchToken(ctx context.Context, tokenID primitive.ObjectID, usedAt time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := patRepo.collection.UpdateOne(contx, bson.M{"_id": tokenID}, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	return err
+}
@@ -0,0 +1,175 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type projectRepository struct {
+	collection *mongo.Collection
+}
+
+func NewProjectRepository(col *mongo.Collection) domain.ProjectRepository {
+	return &projectRepository{collection: col}
+}
+
+// store new project in database
+func (projectRepo *projectRepository) CreateProject(ctx context.Context, project *domain.Project) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+
+	_, err := projectRepo.collection.InsertOne(contx, project)
+	return err
+}
+
+// find projects of a member (all projects when userID is empty) ordered by name
+func (projectRepo *projectRepository) GetProjects(ctx context.Context, userID string) ([]domain.Project, error) {
+
+	var projects []domain.Project
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{}
+	if userID != "" {
+		filter["members.user_id"] = userID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := projectRepo.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &projects); err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		return []domain.Project{}, nil
+	}
+
+	return projects, nil
+}
+
+// find project by its id
+func (projectRepo *projectRepository) GetProjectByID(ctx context.Context, projectID string) (*domain.Project, error) {
+
+	var project domain.Project
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	err = projectRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &project, nil        // success
+}
+
+// update provided name and description
+func (projectRepo *projectRepository) UpdateProject(ctx context.Context, projectID string, project *domain.Project) (*domain.Project, error) {
+
+	setFields := bson.M{}
+	if project.Name != "" {
+		setFields["name"] = project.Name
+	}
+	if project.Description != "" {
+		setFields["description"] = project.Description
+	}
+
+	return projectRepo.update(ctx, projectID, bson.M{}, bson.M{"$set": setFields})
+}
+
+// delete project by its id
+func (projectRepo *projectRepository) DeleteProject(ctx context.Context, projectID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return domain.ErrInvalidProjectID
+	}
+
+	result, err := projectRepo.collection.DeleteOne(contx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// add a member or change the role of an existing one
+func (projectRepo *projectRepository) SetMember(ctx context.Context, projectID string, member domain.ProjectMember) (*domain.Project, error) {
+
+	// change the role in place, otherwise append
+	updated, err := projectRepo.update(ctx, projectID, bson.M{"members.user_id": member.UserID}, bson.M{"$set": bson.M{"members.$.role": member.Role}})
+	if err != domain.ErrProjectNotFound {
+		return updated, err
+	}
+	return projectRepo.update(ctx, projectID, bson.M{"members.user_id": bson.M{"$ne": member.UserID}}, bson.M{"$push": bson.M{"members": member}})
+}
+
+// remove a member
+func (projectRepo *projectRepository) RemoveMember(ctx context.Context, projectID string, userID string) (*domain.Project, error) {
+	return projectRepo.update(ctx, projectID, bson.M{}, bson.M{"$pull": bson.M{"members": bson.M{"user_id": userID}}})
+}
+
+// apply an update to the project matching the filter and return it
+func (projectRepo *projectRepository) update(ctx context.Context, projectID string, filter bson.M, update bson.M) (*domain.Project, error) {
+
+	var updated domain.Project
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+	filter["_id"] = objID
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = projectRepo.collection.FindOneAndUpdate(contx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// create indexes if missing
+func (projectRepo *projectRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := projectRepo.collection.Indexes().CreateOne(contx, mongo.IndexModel{
+		Keys: bson.D{{Key: "members.user_id", Value: 1}},       // projects of a member
+	})
+	return err
+}
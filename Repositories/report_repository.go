@@ -0,0 +1,256 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"sort";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// reports computed by aggregation pipelines
+type reportRepository struct {
+	taskCollection     *mongo.Collection
+	changeCollection   *mongo.Collection        // task field changes (who created and completed tasks)
+	worklogCollection  *mongo.Collection
+}
+
+func NewReportRepository(taskCol *mongo.Collection, changeCol *mongo.Collection, worklogCol *mongo.Collection) domain.ReportRepository {
+	return &reportRepository{taskCollection: taskCol, changeCollection: changeCol, worklogCollection: worklogCol}
+}
+
+// count tasks by status and priority in one pass
+func (reportRepo *reportRepository) TaskSummary(ctx context.Context, scope domain.ReportScope, weekStart time.Time) (*domain.TaskSummaryReport, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{}
+	if scope.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*scope.TenantID)
+	}
+	if scope.Visibility != nil {
+		visible := []interface{}{nil}        // null matches tasks without a project
+		for _, projectID := range scope.Visibility.Projects {
+			visible = append(visible, projectID)
+		}
+		filter["project_id"] = bson.M{"$in": visible}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"status":    bson.A{bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+			"priority":  bson.A{bson.M{"$group": bson.M{"_id": "$priority", "count": bson.M{"$sum": 1}}}},
+			"overdue":   bson.A{bson.M{"$match": bson.M{"is_overdue": true}}, bson.M{"$count": "count"}},
+			"completed": bson.A{
+				bson.M{"$match": bson.M{"status": domain.TaskStatusCompleted, "status_entered_at." + domain.TaskStatusCompleted: bson.M{"$gte": weekStart}}},
+				bson.M{"$count": "count"},
+			},
+		}}},
+	}
+
+	cursor, err := reportRepo.taskCollection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	type bucket struct {
+		Key    string  `bson:"_id"`
+		Count  int64   `bson:"count"`
+	}
+	var results []struct {
+		Status     []bucket  `bson:"status"`
+		Priority   []bucket  `bson:"priority"`
+		Overdue    []bucket  `bson:"overdue"`
+		Completed  []bucket  `bson:"completed"`
+	}
+	if err := cursor.All(contx, &results); err != nil {
+		return nil, err
+	}
+
+	report := &domain.TaskSummaryReport{ByStatus: map[string]int64{}, ByPriority: map[string]int64{}, WeekStart: weekStart}
+	for _, status := range domain.TaskStatuses {
+		report.ByStatus[status] = 0
+	}
+	for priority := range domain.TaskPriorities {
+		report.ByPriority[priority] = 0
+	}
+	if len(results) == 0 {
+		return report, nil
+	}
+	for _, status := range results[0].Status {
+		report.ByStatus[status.Key] += status.Count
+		report.Total += status.Count
+	}
+	for _, priority := range results[0].Priority {
+		if priority.Key == "" {
+			priority.Key = domain.DefaultTaskPriority        // tasks stored before priorities existed
+		}
+		report.ByPriority[priority.Key] += priority.Count
+	}
+	if len(results[0].Overdue) > 0 {
+		report.Overdue = results[0].Overdue[0].Count
+	}
+	if len(results[0].Completed) > 0 {
+		report.CompletedThisWeek = results[0].Completed[0].Count
+	}
+
+	return report, nil
+}
+
+// created and completed tasks from the change history, logged time from worklogs, per day and user
+func (reportRepo *reportRepository) Productivity(ctx context.Context, scope domain.ReportScope, from time.Time, to time.Time) (*domain.ProductivityReport, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	report := &domain.ProductivityReport{From: from, To: to, Days: []domain.ProductivityDay{}}
+	days := map[string]*domain.ProductivityDay{}
+	for day := from.Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		report.Days = append(report.Days, domain.ProductivityDay{Date: day.Format("2006-01-02")})
+	}
+	for i := range report.Days {
+		days[report.Days[i].Date] = &report.Days[i]
+	}
+	users := map[string]*domain.UserProductivity{}
+	userOf := func(userID string, username string) *domain.UserProductivity {
+		user, seen := users[userID]
+		if !seen {
+			user = &domain.UserProductivity{UserID: userID, Username: username}
+			users[userID] = user
+		}
+		if user.Username == "" {
+			user.Username = username
+		}
+		return user
+	}
+
+	// every created task has a title, so its title change marks the creation
+	changeFilter := bson.M{
+		"timestamp": bson.M{"$gte": from, "$lt": to},
+		"$or": bson.A{
+			bson.M{"action": domain.TaskEventCreated, "field": "title"},
+			bson.M{"field": "status", "new_value": domain.TaskStatusCompleted},
+		},
+	}
+	if scope.UserID != "" {
+		changeFilter["actor_id"] = scope.UserID
+	}
+	changePipeline := mongo.Pipeline{{{Key: "$match", Value: changeFilter}}}
+	if scope.TenantID != nil {
+		// changes don't carry the tenant, the task does (changes of deleted tasks drop out)
+		changePipeline = append(changePipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{"from": reportRepo.taskCollection.Name(), "localField": "task_id", "foreignField": "_id", "as": "task"}}},
+			bson.D{{Key: "$match", Value: bson.M{"task": bson.M{"$ne": bson.A{}}, "task.tenant_id": tenantFilter(*scope.TenantID)}}},
+		)
+	}
+	changePipeline = append(changePipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":    bson.M{"day": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$timestamp"}}, "completed": bson.M{"$eq": bson.A{"$field", "status"}}, "actor_id": "$actor_id"},
+		"actor":  bson.M{"$last": "$actor"},
+		"count":  bson.M{"$sum": 1},
+	}}})
+
+	var changes []struct {
+		Key struct {
+			Day        string  `bson:"day"`
+			Completed  bool    `bson:"completed"`
+			ActorID    string  `bson:"actor_id"`
+		} `bson:"_id"`
+		Actor  string  `bson:"actor"`
+		Count  int64   `bson:"count"`
+	}
+	if err := aggregateAll(contx, reportRepo.changeCollection, changePipeline, &changes); err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		day := days[change.Key.Day]
+		user := userOf(change.Key.ActorID, change.Actor)
+		if change.Key.Completed {
+			report.Completed += change.Count
+			user.Completed += change.Count
+			if day != nil {
+				day.Completed += change.Count
+			}
+			continue
+		}
+		report.Created += change.Count
+		user.Created += change.Count
+		if day != nil {
+			day.Created += change.Count
+		}
+	}
+
+	// stopped timers and manual entries by the day they started
+	worklogFilter := bson.M{"started_at": bson.M{"$gte": from, "$lt": to}, "running": bson.M{"$ne": true}}
+	if scope.UserID != "" {
+		worklogFilter["user_id"] = scope.UserID
+	}
+	if scope.TenantID != nil {
+		worklogFilter["tenant_id"] = tenantFilter(*scope.TenantID)
+	}
+	worklogPipeline := mongo.Pipeline{
+		{{Key: "$match", Value: worklogFilter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"day": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$started_at"}}, "user_id": "$user_id"},
+			"username":  bson.M{"$last": "$username"},
+			"seconds":   bson.M{"$sum": "$seconds"},
+		}}},
+	}
+
+	var worklogs []struct {
+		Key struct {
+			Day     string  `bson:"day"`
+			UserID  string  `bson:"user_id"`
+		} `bson:"_id"`
+		Username  string  `bson:"username"`
+		Seconds   int64   `bson:"seconds"`
+	}
+	if err := aggregateAll(contx, reportRepo.worklogCollection, worklogPipeline, &worklogs); err != nil {
+		return nil, err
+	}
+	for _, worklog := range worklogs {
+		report.Seconds += worklog.Seconds
+		userOf(worklog.Key.UserID, worklog.Username).Seconds += worklog.Seconds
+		if day := days[worklog.Key.Day]; day != nil {
+			day.Seconds += worklog.Seconds
+		}
+	}
+
+	// changes made by the server (recurrence, imports without an actor) aren't anyone's work
+	delete(users, "")
+	if scope.UserID == "" {
+		report.Users = []domain.UserProductivity{}
+		for _, user := range users {
+			report.Users = append(report.Users, *user)
+		}
+		sort.Slice(report.Users, func(i, j int) bool {
+			if report.Users[i].Completed != report.Users[j].Completed {
+				return report.Users[i].Completed > report.Users[j].Completed
+			}
+			if report.Users[i].Seconds != report.Users[j].Seconds {
+				return report.Users[i].Seconds > report.Users[j].Seconds
+			}
+			return report.Users[i].Username < report.Users[j].Username
+		})
+	}
+
+	return report, nil
+}
+
+// run a pipeline and decode every result
+func aggregateAll(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, results interface{}) error {
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)      // close cursor when done
+
+	return cursor.All(ctx, results)
+}
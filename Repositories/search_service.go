@@ -0,0 +1,191 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"html";
+	"regexp";
+	"strings";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// words around a match shown in a description fragment
+const highlightContextWords = 8
+
+// most description fragments per task
+const maxHighlightFragments = 3
+
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// search service on the text index of the tasks collection
+// mongodb keeps the index up to date with every write, so indexing is a no-op
+type mongoSearchService struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSearchService(col *mongo.Collection) domain.SearchService {
+	return &mongoSearchService{collection: col}
+}
+
+func (searchService *mongoSearchService) IndexTask(ctx context.Context, task *domain.Task) error {
+	return nil
+}
+
+func (searchService *mongoSearchService) RemoveTask(ctx context.Context, taskID string) error {
+	return nil
+}
+
+func (searchService *mongoSearchService) SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchMatch, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// same filters as a task list search, ranked by the text score
+	filter, _ := taskQueryFilter(domain.TaskQuery{Search: query.Text, Language: query.Language, Visibility: query.Visibility, TenantID: query.TenantID})
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().SetProjection(bson.M{"title": 1, "description": 1, "score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}}).SetLimit(int64(query.Limit))
+	cursor, err := searchService.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []struct {
+		ID           primitive.ObjectID  `bson:"_id"`
+		Title        string              `bson:"title"`
+		Description  string              `bson:"description"`
+		Score        float64             `bson:"score"`
+	}
+	if err := cursor.All(contx, &found); err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query.Text)
+	matches := make([]domain.TaskSearchMatch, 0, len(found))
+	for _, task := range found {
+		matches = append(matches, domain.TaskSearchMatch{TaskID: task.ID.Hex(), Score: task.Score, Highlights: highlightTask(task.Title, task.Description, terms)})
+	}
+
+	return matches, nil
+}
+
+// lowercase words of a search, negated words (-word) are left out
+func searchTerms(text string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		terms = append(terms, searchWordPattern.FindAllString(field, -1)...)
+	}
+	return terms
+}
+
+// check if a word matches a search term, a shared stem of 4+ letters counts like the stemmed index (report, reports, reporting)
+func matchesTerm(word string, terms []string) bool {
+	word = roughStem(word)
+	for _, term := range terms {
+		term = roughStem(term)
+		if word == term {
+			return true
+		}
+		if min(len(word), len(term)) >= 4 && (strings.HasPrefix(word, term) || strings.HasPrefix(term, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// word without a common english ending (tests, testing, tested -> test)
+func roughStem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if stem := strings.TrimSuffix(word, suffix); stem != word && len(stem) >= 3 {
+			return stem
+		}
+	}
+	return word
+}
+
+// highlighted title and description fragments of a task (nil when no word matches)
+func highlightTask(title string, description string, terms []string) map[string][]string {
+	highlights := map[string][]string{}
+	if fragments := highlightText(title, terms, true); len(fragments) > 0 {
+		highlights["title"] = fragments
+	}
+	if fragments := highlightText(description, terms, false); len(fragments) > 0 {
+		highlights["description"] = fragments
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// html escaped fragments of a text with matched words in <em></em>
+// the whole text is one fragment, otherwise each fragment shows a few words around its matches
+func highlightText(text string, terms []string, whole bool) []string {
+
+	words := searchWordPattern.FindAllStringIndex(text, -1)
+	var matched []int
+	for i, word := range words {
+		if matchesTerm(strings.ToLower(text[word[0]:word[1]]), terms) {
+			matched = append(matched, i)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	// word ranges to show, close matches share a fragment
+	type span struct{ first, last int }
+	spans := []span{{first: 0, last: len(words) - 1}}
+	if !whole {
+		spans = nil
+		for _, i := range matched {
+			first, last := max(i-highlightContextWords, 0), min(i+highlightContextWords, len(words)-1)
+			if n := len(spans); n > 0 && first <= spans[n-1].last+1 {
+				spans[n-1].last = last
+				continue
+			}
+			if len(spans) == maxHighlightFragments {
+				break
+			}
+			spans = append(spans, span{first: first, last: last})
+		}
+	}
+
+	fragments := make([]string, 0, len(spans))
+	for _, fragmentSpan := range spans {
+		start, end := words[fragmentSpan.first][0], words[fragmentSpan.last][1]
+		if fragmentSpan.first == 0 {
+			start = 0
+		}
+		if fragmentSpan.last == len(words)-1 {
+			end = len(text)        // keep closing punctuation
+		}
+		var fragment strings.Builder
+		if start > 0 {
+			fragment.WriteString("…")
+		}
+		position := start
+		for _, i := range matched {
+			if i < fragmentSpan.first || i > fragmentSpan.last {
+				continue
+			}
+			fragment.WriteString(html.EscapeString(text[position:words[i][0]]))
+			fragment.WriteString("<em>" + html.EscapeString(text[words[i][0]:words[i][1]]) + "</em>")
+			position = words[i][1]
+		}
+		fragment.WriteString(html.EscapeString(text[position:end]))
+		if end < len(text) {
+			fragment.WriteString("…")
+		}
+		fragments = append(fragments, fragment.String())
+	}
+
+	return fragments
+}
@@ -0,0 +1,105 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type sessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionRepository(col *mongo.Collection) domain.SessionRepository {
+	return &sessionRepository{collection: col}
+}
+
+// store new session in database
+func (sessionRepo *sessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+
+	_, err := sessionRepo.collection.InsertOne(contx, session)
+	return err
+}
+
+// find session by its id
+func (sessionRepo *sessionRepository) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+
+	var session domain.Session
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return nil, domain.ErrInvalidSessionID
+	}
+
+	err = sessionRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil        // success
+}
+
+// unrevoked and unexpired sessions of a user, oldest first
+func (sessionRepo *sessionRepository) GetActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}, "expires_at": bson.M{"$gt": now}}
+	cursor, err := sessionRepo.collection.Find(contx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)
+
+	sessions := []domain.Session{}
+	if err := cursor.All(contx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// end sessions early
+func (sessionRepo *sessionRepository) RevokeSessions(ctx context.Context, sessionIDs []primitive.ObjectID, reason string, at time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := sessionRepo.collection.UpdateMany(contx,
+		bson.M{"_id": bson.M{"$in": sessionIDs}, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at, "revoked_reason": reason}},
+	)
+	return err
+}
+
+// create lookup index and drop sessions once their token expired
+func (sessionRepo *sessionRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := sessionRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},                              // active sessions of a user
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type settingsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSettingsRepository(col *mongo.Collection) domain.SettingsRepository {
+	return &settingsRepository{collection: col}
+}
+
+// find the settings document
+func (settingsRepo *settingsRepository) GetSettings(ctx context.Context) (*domain.InstanceSettings, error) {
+
+	var settings domain.InstanceSettings
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := settingsRepo.collection.FindOne(contx, bson.M{"_id": domain.InstanceSettingsID}).Decode(&settings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrSettingsNotFound
+		}
+		return nil, err
+	}
+
+	return &settings, nil        // success
+}
+
+// replace the settings document
+func (settingsRepo *settingsRepository) SaveSettings(ctx context.Context, settings *domain.InstanceSettings) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	settings.ID = domain.InstanceSettingsID
+	_, err := settingsRepo.collection.ReplaceOne(contx, bson.M{"_id": domain.InstanceSettingsID}, settings, options.Replace().SetUpsert(true))
+	return err
+}
+
+// set the security settings of the settings document (created when missing)
+func (settingsRepo *settingsRepository) SaveSecuritySettings(ctx context.Context, security *domain.SecuritySettings, at time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := settingsRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": domain.InstanceSettingsID},
+		bson.M{"$set": bson.M{"security": security, "updated_at": at}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// mark setup as done unless it already is (single atomic update, safe across instances)
+func (settingsRepo *settingsRepository) ClaimSetup(ctx context.Context, at time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// the upsert can only insert when no settings exist, an existing claim makes it collide on _id
+	_, err := settingsRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": domain.InstanceSettingsID, "setup_completed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"setup_completed_at": at}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrSetupCompleted
+	}
+	return err
+}
+
+// remove the setup claim
+func (settingsRepo *settingsRepository) ReleaseSetup(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := settingsRepo.collection.UpdateOne(contx, bson.M{"_id": domain.InstanceSettingsID}, bson.M{"$unset": bson.M{"setup_completed_at": ""}})
+	return err
+}
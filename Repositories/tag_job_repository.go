@@ -0,0 +1,73 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type tagJobRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTagJobRepository(col *mongo.Collection) domain.TagJobRepository {
+	return &tagJobRepository{collection: col}
+}
+
+// store new job in database
+func (jobRepo *tagJobRepository) CreateJob(ctx context.Context, job *domain.TagJob) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+
+	_, err := jobRepo.collection.InsertOne(contx, job)
+	return err
+}
+
+// save progress and status of a job
+func (jobRepo *tagJobRepository) UpdateJob(ctx context.Context, job *domain.TagJob) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := jobRepo.collection.ReplaceOne(contx, bson.M{"_id": job.ID}, job)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTagJobNotFound
+	}
+
+	return nil
+}
+
+// find job by its id
+func (jobRepo *tagJobRepository) GetJob(ctx context.Context, jobID string) (*domain.TagJob, error) {
+
+	var job domain.TagJob
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, domain.ErrInvalidTagJobID
+	}
+
+	err = jobRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTagJobNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil        // success
+}
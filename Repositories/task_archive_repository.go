@@ -0,0 +1,229 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// completed tasks moved between the tasks and archived_tasks collections
+// writes go to mongodb directly, so cached task reads catch up within the cache ttl
+type taskArchiveRepository struct {
+	taskCollection     *mongo.Collection
+	archiveCollection  *mongo.Collection
+}
+
+func NewTaskArchiveRepository(taskCol *mongo.Collection, archiveCol *mongo.Collection) domain.TaskArchiveRepository {
+	return &taskArchiveRepository{taskCollection: taskCol, archiveCollection: archiveCol}
+}
+
+// completed tasks unchanged since a time, leaves first (parents follow once their subtasks are archived)
+func (archiveRepo *taskArchiveRepository) FindArchivable(ctx context.Context, completedBefore time.Time, limit int64) ([]domain.Task, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status":      domain.TaskStatusCompleted,
+			"updated_at":  bson.M{"$lt": completedBefore},
+			"$or":         bson.A{bson.M{"recurrence": bson.M{"$exists": false}}, bson.M{"recurrence.advanced_at": bson.M{"$exists": true}}},       // the series moved past the occurrence
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{"from": archiveRepo.taskCollection.Name(), "localField": "_id", "foreignField": "parent_id", "as": "subtasks"}}},
+		{{Key: "$match", Value: bson.M{"subtasks": bson.A{}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{"subtasks": 0}}},
+	}
+
+	var tasks []domain.Task
+	if err := aggregateAll(contx, archiveRepo.taskCollection, pipeline, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// copy tasks into the archive and remove them from the tasks collection
+func (archiveRepo *taskArchiveRepository) Archive(ctx context.Context, tasks []domain.Task, archivedAt time.Time) ([]domain.Task, error) {
+
+	if len(tasks) == 0 {
+		return []domain.Task{}, nil
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	docs := make([]interface{}, len(tasks))
+	unchanged := make(bson.A, len(tasks))
+	ids := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		docs[i] = domain.ArchivedTask{Task: task, ArchivedAt: archivedAt}
+		unchanged[i] = bson.M{"_id": task.ID, "updated_at": task.UpdatedAt}
+		ids[i] = task.ID
+	}
+
+	// a copy left by an interrupted run is replaced
+	if _, err := archiveRepo.archiveCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+	if _, err := archiveRepo.archiveCollection.InsertMany(contx, docs); err != nil {
+		return nil, err
+	}
+	if _, err := archiveRepo.taskCollection.DeleteMany(contx, bson.M{"$or": unchanged}); err != nil {
+		return nil, err
+	}
+
+	// tasks changed since they were found stay where they are
+	cursor, err := archiveRepo.taskCollection.Find(contx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var kept []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(contx, &kept); err != nil {
+		return nil, err
+	}
+	if len(kept) == 0 {
+		return tasks, nil
+	}
+
+	keptIDs := map[primitive.ObjectID]bool{}
+	for _, task := range kept {
+		keptIDs[task.ID] = true
+	}
+	stale := []primitive.ObjectID{}
+	moved := []domain.Task{}
+	for _, task := range tasks {
+		if keptIDs[task.ID] {
+			stale = append(stale, task.ID)
+			continue
+		}
+		moved = append(moved, task)
+	}
+	if _, err := archiveRepo.archiveCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": stale}}); err != nil {
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+// find archived tasks newest first
+func (archiveRepo *taskArchiveRepository) GetArchivedTasks(ctx context.Context, query domain.ArchiveQuery) ([]domain.ArchivedTask, error) {
+
+	var tasks []domain.ArchivedTask
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{}
+	if query.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*query.TenantID)
+	}
+	if query.Visibility != nil {
+		visible := []interface{}{nil}        // null matches tasks without a project
+		for _, projectID := range query.Visibility.Projects {
+			visible = append(visible, projectID)
+		}
+		filter["project_id"] = bson.M{"$in": visible}
+	}
+	if !query.Before.IsZero() {
+		filter["archived_at"] = bson.M{"$lt": query.Before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "archived_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(query.Limit)
+	cursor, err := archiveRepo.archiveCollection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &tasks); err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		return []domain.ArchivedTask{}, nil
+	}
+
+	return tasks, nil
+}
+
+// find an archived task
+func (archiveRepo *taskArchiveRepository) GetArchivedTask(ctx context.Context, taskID string) (*domain.ArchivedTask, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)       // convert string id to mongodb's id format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	var task domain.ArchivedTask
+	err = archiveRepo.archiveCollection.FindOne(contx, bson.M{"_id": objID}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrArchivedTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// move an archived task back, it becomes a top-level task when its parent is gone
+func (archiveRepo *taskArchiveRepository) Restore(ctx context.Context, taskID string, restoredAt time.Time) (*domain.Task, error) {
+
+	archived, err := archiveRepo.GetArchivedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	task := archived.Task
+	restored, err := archiveRepo.taskCollection.CountDocuments(contx, bson.M{"_id": task.ID})       // restored before an interrupted cleanup
+	if err != nil {
+		return nil, err
+	}
+	if task.ParentID != nil {
+		count, err := archiveRepo.taskCollection.CountDocuments(contx, bson.M{"_id": *task.ParentID})
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			task.ParentID = nil
+		}
+	}
+	task.UpdatedAt = restoredAt        // unchanged tasks are archived again after the retention
+
+	if restored == 0 {
+		if _, err := archiveRepo.taskCollection.InsertOne(contx, task); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := archiveRepo.archiveCollection.DeleteOne(contx, bson.M{"_id": task.ID}); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// create indexes if missing
+func (archiveRepo *taskArchiveRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := archiveRepo.archiveCollection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "archived_at", Value: -1}, {Key: "_id", Value: -1}}},        // newest first
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "archived_at", Value: -1}}},    // archive of an organization
+	})
+	return err
+}
@@ -0,0 +1,146 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type taskChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskChangeRepository(col *mongo.Collection) domain.TaskChangeRepository {
+	col, _ = col.Clone(options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true}))       // old and new values decode to json objects, not key-value lists
+	return &taskChangeRepository{collection: col}
+}
+
+// store changes in the order given
+func (changeRepo *taskChangeRepository) AppendChanges(ctx context.Context, changes []domain.TaskChange) error {
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	docs := make([]interface{}, len(changes))
+	for i := range changes {
+		if changes[i].ID.IsZero() {
+			changes[i].ID = primitive.NewObjectID()       // ids keep the order of changes stored in the same instant
+		}
+		docs[i] = changes[i]
+	}
+
+	_, err := changeRepo.collection.InsertMany(contx, docs, options.InsertMany().SetOrdered(true))
+	return err
+}
+
+// find every change of a task, oldest first
+func (changeRepo *taskChangeRepository) GetChanges(ctx context.Context, taskID string) ([]domain.TaskChange, error) {
+
+	var changes []domain.TaskChange
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := changeRepo.collection.Find(contx, bson.M{"task_id": objID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes == nil {
+		return []domain.TaskChange{}, nil
+	}
+
+	return changes, nil
+}
+
+// find tasks a user completed, latest first, with the title and time they were created
+func (changeRepo *taskChangeRepository) GetCompletions(ctx context.Context, actorID string, limit int64) ([]domain.TaskCompletion, error) {
+
+	var completed []domain.TaskChange
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}}).SetLimit(limit)
+	cursor, err := changeRepo.collection.Find(contx, bson.M{"actor_id": actorID, "field": "status", "new_value": "completed"}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)      // close cursor when done
+	if err := cursor.All(contx, &completed); err != nil {
+		return nil, err
+	}
+
+	// a task completed again after being reopened counts once (latest completion)
+	taskIDs := []primitive.ObjectID{}
+	completedAt := map[primitive.ObjectID]time.Time{}
+	for _, change := range completed {
+		if _, seen := completedAt[change.TaskID]; !seen {
+			completedAt[change.TaskID] = change.Timestamp
+			taskIDs = append(taskIDs, change.TaskID)
+		}
+	}
+	if len(taskIDs) == 0 {
+		return []domain.TaskCompletion{}, nil
+	}
+
+	// titles the tasks were created with
+	var created []domain.TaskChange
+	cursor, err = changeRepo.collection.Find(contx, bson.M{"task_id": bson.M{"$in": taskIDs}, "action": domain.TaskEventCreated, "field": "title"})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)      // close cursor when done
+	if err := cursor.All(contx, &created); err != nil {
+		return nil, err
+	}
+	titles := map[primitive.ObjectID]domain.TaskChange{}
+	for _, change := range created {
+		titles[change.TaskID] = change
+	}
+
+	completions := make([]domain.TaskCompletion, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		creation, ok := titles[taskID]
+		if !ok {
+			continue        // created before field history was recorded
+		}
+		title, _ := creation.NewValue.(string)
+		completions = append(completions, domain.TaskCompletion{TaskID: taskID, Title: title, CreatedAt: creation.Timestamp, CompletedAt: completedAt[taskID]})
+	}
+
+	return completions, nil
+}
+
+// indexes for per-task history in order and completions per user
+func (changeRepo *taskChangeRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := changeRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "field", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	return err
+}
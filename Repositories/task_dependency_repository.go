@@ -0,0 +1,111 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type taskDependencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskDependencyRepository(col *mongo.Collection) domain.TaskDependencyRepository {
+	return &taskDependencyRepository{collection: col}
+}
+
+// store a dependency, the unique index rejects linking the same tasks twice
+func (dependencyRepo *taskDependencyRepository) AddDependency(ctx context.Context, dependency *domain.TaskDependency) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if dependency.ID.IsZero() {
+		dependency.ID = primitive.NewObjectID()
+	}
+
+	_, err := dependencyRepo.collection.InsertOne(contx, dependency)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrDependencyExists
+	}
+	return err
+}
+
+// delete the dependency between two tasks
+func (dependencyRepo *taskDependencyRepository) RemoveDependency(ctx context.Context, taskID string, blockerID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := dependencyRepo.collection.DeleteOne(contx, bson.M{"task_id": taskID, "blocker_id": blockerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrDependencyNotFound
+	}
+
+	return nil
+}
+
+// find what blocks a set of tasks
+func (dependencyRepo *taskDependencyRepository) GetBlockers(ctx context.Context, taskIDs []string) ([]domain.TaskDependency, error) {
+	return dependencyRepo.find(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+}
+
+// find the tasks a task blocks
+func (dependencyRepo *taskDependencyRepository) GetBlocked(ctx context.Context, blockerID string) ([]domain.TaskDependency, error) {
+	return dependencyRepo.find(ctx, bson.M{"blocker_id": blockerID})
+}
+
+// find dependencies oldest first
+func (dependencyRepo *taskDependencyRepository) find(ctx context.Context, filter bson.M) ([]domain.TaskDependency, error) {
+
+	var dependencies []domain.TaskDependency
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	cursor, err := dependencyRepo.collection.Find(contx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &dependencies); err != nil {
+		return nil, err
+	}
+	if dependencies == nil {
+		return []domain.TaskDependency{}, nil
+	}
+
+	return dependencies, nil
+}
+
+// delete the links of a task in both directions
+func (dependencyRepo *taskDependencyRepository) DeleteTaskDependencies(ctx context.Context, taskID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := dependencyRepo.collection.DeleteMany(contx, bson.M{"$or": bson.A{bson.M{"task_id": taskID}, bson.M{"blocker_id": taskID}}})
+	return err
+}
+
+// create indexes if missing
+func (dependencyRepo *taskDependencyRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := dependencyRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "blocker_id", Value: 1}}, Options: options.Index().SetUnique(true)},        // one link per pair, blockers of a task
+		{Keys: bson.D{{Key: "blocker_id", Value: 1}}},        // tasks waiting for a task
+	})
+	return err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type taskEventLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskEventLogRepository(col *mongo.Collection) domain.TaskEventLogRepository {
+	return &taskEventLogRepository{collection: col}
+}
+
+// store event, its id orders it after the events logged before
+func (eventLogRepo *taskEventLogRepository) AppendEvent(ctx context.Context, entry *domain.TaskEventLogEntry) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := eventLogRepo.collection.InsertOne(contx, entry)
+	return err
+}
+
+// find events after the given one, oldest first
+func (eventLogRepo *taskEventLogRepository) GetEvents(ctx context.Context, query domain.TaskEventLogQuery) ([]domain.TaskEventLogEntry, error) {
+
+	var entries []domain.TaskEventLogEntry
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": bson.M{"$gt": query.After}}
+	if query.Visibility != nil {
+		visible := []interface{}{nil}        // null matches tasks without a project
+		for _, projectID := range query.Visibility.Projects {
+			visible = append(visible, projectID)
+		}
+		filter["project_id"] = bson.M{"$in": visible}
+	}
+	if query.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*query.TenantID)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(query.Limit)
+	cursor, err := eventLogRepo.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &entries); err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		return []domain.TaskEventLogEntry{}, nil
+	}
+
+	return entries, nil
+}
+
+// create lookup index and drop events once they expired
+func (eventLogRepo *taskEventLogRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := eventLogRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: 1}}},                                   // events of an organization after a resume point
+	})
+	return err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type taskHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskHistoryRepository(col *mongo.Collection) domain.TaskHistoryRepository {
+	return &taskHistoryRepository{collection: col}
+}
+
+// store events in the order given
+func (historyRepo *taskHistoryRepository) AppendEvents(ctx context.Context, events []domain.TaskHistoryEvent) error {
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	docs := make([]interface{}, len(events))
+	for i := range events {
+		if events[i].ID.IsZero() {
+			events[i].ID = primitive.NewObjectID()       // ids keep the order of events stored in the same instant
+		}
+		docs[i] = events[i]
+	}
+
+	_, err := historyRepo.collection.InsertMany(contx, docs, options.InsertMany().SetOrdered(true))
+	return err
+}
+
+// find every event of a task, oldest first
+func (historyRepo *taskHistoryRepository) GetEvents(ctx context.Context, taskID string) ([]domain.TaskHistoryEvent, error) {
+
+	var events []domain.TaskHistoryEvent
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := historyRepo.collection.Find(contx, bson.M{"task_id": objID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	if events == nil {
+		return []domain.TaskHistoryEvent{}, nil
+	}
+
+	return events, nil
+}
+
+// find the last event of a task stored at or before a time
+func (historyRepo *taskHistoryRepository) GetEventAsOf(ctx context.Context, taskID string, at time.Time) (*domain.TaskHistoryEvent, error) {
+
+	var event domain.TaskHistoryEvent
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	filter := bson.M{"task_id": objID, "timestamp": bson.M{"$lte": at}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	err = historyRepo.collection.FindOne(contx, filter, opts).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// create index used to replay a task's events
+func (historyRepo *taskHistoryRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := historyRepo.collection.Indexes().CreateOne(contx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "timestamp", Value: 1}},
+	})
+	return err
+}
@@ -0,0 +1,127 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type taskLockRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskLockRepository(col *mongo.Collection) domain.TaskLockRepository {
+	return &taskLockRepository{collection: col}
+}
+
+// take the lock when it is free, stale or already the user's
+func (lockRepo *taskLockRepository) Acquire(ctx context.Context, lock *domain.TaskLock) (*domain.TaskLock, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": lock.TaskID, "$or": bson.A{
+		bson.M{"user_id": lock.UserID},
+		bson.M{"expires_at": bson.M{"$lte": time.Now()}},       // stale locks wait for the ttl monitor, they are free already
+	}}
+	update := bson.M{"$set": bson.M{"user_id": lock.UserID, "username": lock.Username, "acquired_at": lock.AcquiredAt, "expires_at": lock.ExpiresAt}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var acquired domain.TaskLock
+	err := lockRepo.collection.FindOneAndUpdate(contx, filter, update, opts).Decode(&acquired)
+	if err == nil {
+		return &acquired, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	// a fresh lock of another user exists
+	var holder domain.TaskLock
+	err = lockRepo.collection.FindOne(contx, bson.M{"_id": lock.TaskID}).Decode(&holder)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrTaskLocked        // released in between, the client may retry
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &holder, domain.ErrTaskLocked
+}
+
+// extend the user's lock while it is still fresh
+func (lockRepo *taskLockRepository) Heartbeat(ctx context.Context, taskID string, userID string, expiresAt time.Time) (*domain.TaskLock, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": taskID, "user_id": userID, "expires_at": bson.M{"$gt": time.Now()}}
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var lock domain.TaskLock
+	err := lockRepo.collection.FindOneAndUpdate(contx, filter, update, opts).Decode(&lock)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskLockNotHeld
+		}
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+// drop the lock of the user (any user when userID is empty)
+func (lockRepo *taskLockRepository) Release(ctx context.Context, taskID string, userID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": taskID}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+	result, err := lockRepo.collection.DeleteOne(contx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTaskLockNotHeld
+	}
+
+	return nil
+}
+
+// find the fresh lock of a task
+func (lockRepo *taskLockRepository) GetLock(ctx context.Context, taskID string) (*domain.TaskLock, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	var lock domain.TaskLock
+	err := lockRepo.collection.FindOne(contx, bson.M{"_id": taskID, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&lock)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskLockNotFound
+		}
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+// create indexes if missing
+func (lockRepo *taskLockRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := lockRepo.collection.Indexes().CreateOne(contx, mongo.IndexModel{
+		Keys:     bson.D{{Key: "expires_at", Value: 1}},
+		Options:  options.Index().SetExpireAfterSeconds(0),       // expire at expires_at
+	})
+	return err
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 )
 
+// fields served by task queries (internal sort keys stay in the database)
+var taskReadProjection = bson.M{"priority_rank": 0}
+
 type taskRepository struct {
 	collection *mongo.Collection
 }
@@ -20,23 +23,93 @@ func NewTaskRepository(col *mongo.Collection) domain.TaskRepository {
 	return &taskRepository{collection: col}
 }
 
-func (taskRepo *taskRepository) CreateTask(task *domain.Task) (*domain.Task, error) {
+func (taskRepo *taskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)     // set timeout
+	contx, cancel := withDeadline(ctx)     // honor request deadline (default timeout when none)
 	defer cancel()
 
-	task.ID = primitive.NewObjectID()                         // create a unique id for the new task
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()                     // create a unique id for the new task
+	}
+	task.CreatedAt = storedNow()                              // server clock, client values are ignored
+	task.UpdatedAt = task.CreatedAt
 	_, err := taskRepo.collection.InsertOne(contx, task)      // create the new task with error handling
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrTaskClientIDExists       // only client ids are unique
+		}
         return nil, err
     }
 
 	return task, nil       // return the new created task and nil
 }
 
-func (taskRepo *taskRepository) DeleteTask(taskID string) error {
+func (taskRepo *taskRepository) GetTaskByClientID(ctx context.Context, clientID string) (*domain.Task, error) {
+
+	var task domain.Task
+	contx, cancel := withDeadline(ctx)     // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.FindOne().SetProjection(taskReadProjection)
+	err := taskRepo.collection.FindOne(contx, bson.M{"client_id": clientID}, opts).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (taskRepo *taskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)     // honor request deadline (default timeout when none)
+	defer cancel()
+
+	now := storedNow()
+	docs := make([]interface{}, len(tasks))
+	for i, task := range tasks {
+		if task.ID.IsZero() {
+			task.ID = primitive.NewObjectID()       // create a unique id for each new task
+		}
+		task.CreatedAt, task.UpdatedAt = now, now       // server clock, client values are ignored
+		docs[i] = task
+	}
+
+	_, err := taskRepo.collection.InsertMany(contx, docs)
+	return err
+}
+
+func (taskRepo *taskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)     // honor request deadline (default timeout when none)
+	defer cancel()
+
+	now := storedNow()
+	models := make([]mongo.WriteModel, len(tasks))
+	for i, task := range tasks {
+		task.PriorityRank = domain.TaskPriorities[task.Priority]       // not read back with the task
+		task.UpdatedAt = now
+		// replaces a copy left by a restore that failed afterwards
+		models[i] = mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": task.ID}).SetReplacement(task).SetUpsert(true)
+	}
+
+	_, err := taskRepo.collection.BulkWrite(contx, models)
+	return err
+}
+
+func (taskRepo *taskRepository) DeleteTask(ctx context.Context, taskID string) error {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)       // convert string id to mongodb's id format with error handling 
@@ -57,21 +130,32 @@ func (taskRepo *taskRepository) DeleteTask(taskID string) error {
 	return nil
 }
 
-func (taskRepo *taskRepository) GetAllTasks() ([]domain.Task, error) {
+func (taskRepo *taskRepository) GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
 	
 	var allTasks []domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
-	cursor, err := taskRepo.collection.Find(contx, bson.M{})      // find all documents in the collection
+	filter, opts := taskQueryFilter(query)
+	cursor, err := taskRepo.collection.Find(contx, filter, opts)      // find matching documents in the collection
 	if err != nil {
 		return nil, err
 	}
 
 	defer cursor.Close(contx)      // close cursor when done
 
-	err = cursor.All(contx, &allTasks)      // read all result into our slice
-	if err != nil {  
+	// read results one by one so tasks read before the deadline can still be returned
+	for cursor.Next(contx) {
+		var task domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		allTasks = append(allTasks, task)
+	}
+	if err := cursor.Err(); err != nil {
+		if contx.Err() == context.DeadlineExceeded && len(allTasks) > 0 {
+			return allTasks, domain.ErrPartialResult
+		}
 		return nil, err
 	}
 
@@ -82,10 +166,98 @@ func (taskRepo *taskRepository) GetAllTasks() ([]domain.Task, error) {
 	return allTasks, nil
 }
 
-func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error) {
+// pass matching tasks to fn one at a time (only one task is held in memory)
+func (taskRepo *taskRepository) StreamTasks(ctx context.Context, query domain.TaskQuery, fn func(task *domain.Task) error) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter, opts := taskQueryFilter(query)
+	cursor, err := taskRepo.collection.Find(contx, filter, opts.SetBatchSize(500))
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	for cursor.Next(contx) {
+		var task domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// filter and find options of a task list query
+func taskQueryFilter(query domain.TaskQuery) (bson.M, *options.FindOptions) {
+
+	// build sort in the requested order
+	opts := options.Find().SetProjection(taskReadProjection)
+	if len(query.Sort) > 0 {
+		sort := bson.D{}
+		for _, sortField := range query.Sort {
+			direction := 1
+			if sortField.Descending {
+				direction = -1
+			}
+			sort = append(sort, bson.E{Key: domain.TaskSortFields[sortField.Field], Value: direction})
+		}
+		opts.SetSort(sort)
+	}
+
+	// precomputed overdue flag keeps this filter on the index
+	filter := bson.M{}
+	if len(query.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": query.IDs}
+	}
+	if query.Overdue != nil {
+		filter["is_overdue"] = *query.Overdue
+	}
+	if len(query.Labels) > 0 {
+		if query.MatchAllLabels {
+			filter["tags"] = bson.M{"$all": query.Labels}
+		} else {
+			filter["tags"] = bson.M{"$in": query.Labels}
+		}
+	}
+	if query.ProjectID != nil {
+		filter["project_id"] = *query.ProjectID
+	} else if query.Visibility != nil {
+		visible := []interface{}{nil}        // null matches tasks without a project
+		for _, projectID := range query.Visibility.Projects {
+			visible = append(visible, projectID)
+		}
+		filter["project_id"] = bson.M{"$in": visible}
+	}
+	if query.Language != "" {
+		filter["language"] = query.Language
+	}
+	if query.UpdatedSince != nil {
+		filter["updated_at"] = bson.M{"$gt": *query.UpdatedSince}
+	}
+	if query.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*query.TenantID)
+	}
+	if query.Search != "" {
+		text := bson.M{"$search": query.Search}
+		if query.Language != "" {
+			text["$language"] = query.Language        // stem the words like the tasks they should find
+		}
+		filter["$text"] = text
+	}
+
+	return filter, opts
+}
+
+func (taskRepo *taskRepository) GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) {
 	
 	var task domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
@@ -93,7 +265,8 @@ func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error)
 		return nil, domain.ErrInvalidTaskID
 	}
 
-	err = taskRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&task)       // check if task exists
+	opts := options.FindOne().SetProjection(taskReadProjection)
+	err = taskRepo.collection.FindOne(contx, bson.M{"_id": objID}, opts).Decode(&task)       // check if task exists
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrTaskNotFound
@@ -104,10 +277,10 @@ func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error)
 	return &task, nil
 }
 
-func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Task) (*domain.Task, error) {
+func (taskRepo *taskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
 	
 	var updatedTask domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
@@ -115,45 +288,453 @@ func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Tas
 		return nil, domain.ErrInvalidTaskID
 	}
 
-	update := bson.M{"$set": bson.M{}}
-	setFields := update["$set"].(bson.M)        // prepare what we want to change
+	setFields := bson.M{}        // prepare what we want to change
+	unsetFields := bson.M{}      // and what we want to remove
 
 	// only update fields that were actually provided
-	if taskUpdate.Title != "" {
-		setFields["title"] = taskUpdate.Title
+	if taskUpdate.Title != nil {
+		setFields["title"] = *taskUpdate.Title
+	}
+	if taskUpdate.Description != nil {
+		setFields["description"] = *taskUpdate.Description
+	}
+	if taskUpdate.DueDate != nil {
+		setFields["due_date"] = *taskUpdate.DueDate
+		setFields["is_overdue"] = taskUpdate.DueDate.Before(time.Now())      // correct flag right away instead of waiting for the job
+	}
+	if taskUpdate.Status != nil {
+		setFields["status"] = *taskUpdate.Status
+		if *taskUpdate.Status == "completed" {
+			setFields["is_overdue"] = false        // completed tasks are never overdue
+		}
+	}
+	for status, enteredAt := range taskUpdate.StatusEnteredAt {
+		setFields["status_entered_at."+status] = enteredAt        // other statuses keep their times
+	}
+	if taskUpdate.Priority != nil {
+		setFields["priority"] = *taskUpdate.Priority
+		setFields["priority_rank"] = taskUpdate.PriorityRank
 	}
-	if taskUpdate.Description != "" {
-		setFields["description"] = taskUpdate.Description
+	if taskUpdate.Estimate != nil {
+		if *taskUpdate.Estimate == 0 {
+			unsetFields["estimate"] = ""
+		} else {
+			setFields["estimate"] = *taskUpdate.Estimate
+		}
+	}
+	if taskUpdate.ParentID != nil {
+		if taskUpdate.ParentID.IsZero() {
+			unsetFields["parent_id"] = ""        // top-level task
+		} else {
+			setFields["parent_id"] = *taskUpdate.ParentID
+		}
+	}
+	if taskUpdate.Tags != nil {
+		setFields["tags"] = *taskUpdate.Tags       // replaces all labels ([] clears them)
+	}
+	if taskUpdate.Checklist != nil {
+		if len(*taskUpdate.Checklist) == 0 {
+			unsetFields["checklist"] = ""
+		} else {
+			setFields["checklist"] = *taskUpdate.Checklist       // replaces the whole list, keeps the order
+		}
+	}
+	if taskUpdate.ClearCost {
+		unsetFields["cost"] = ""
+	} else if taskUpdate.Cost != nil {
+		setFields["cost"] = taskUpdate.Cost       // replaces estimated and actual cost together
+	}
+	if taskUpdate.ProjectID != nil {
+		if taskUpdate.ProjectID.IsZero() {
+			unsetFields["project_id"] = ""        // no project
+		} else {
+			setFields["project_id"] = *taskUpdate.ProjectID
+		}
 	}
-	if !taskUpdate.DueDate.IsZero() {
-		setFields["due_date"] = taskUpdate.DueDate
+	if taskUpdate.Language != nil {
+		setFields["language"] = *taskUpdate.Language
 	}
-	if taskUpdate.Status != "" {
-		setFields["status"] = taskUpdate.Status
+	if taskUpdate.ClearReminder {
+		unsetFields["reminder"] = ""
+	} else if taskUpdate.Reminder != nil {
+		taskUpdate.Reminder.SentAt = nil        // new settings start fresh
+		setFields["reminder"] = taskUpdate.Reminder
+	} else if taskUpdate.DueDate != nil {
+		setFields["reminder.sent_at"] = nil      // moved due date needs a new reminder
 	}
 
 	// stop if nothing valid to update
-	if len(setFields) == 0 {
+	if len(setFields) == 0 && len(unsetFields) == 0 {
 		return nil, errors.New("no valid fields provided for update")
 	}
+	setFields["updated_at"] = storedNow()
+	update := bson.M{"$set": setFields}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
  
 	opts := options.FindOneAndUpdate().         // to get updated document back
 		SetReturnDocument(options.After)
 
+	// only the version the caller has seen is changed when it names one
+	filter := bson.M{"_id": objID}
+	if taskUpdate.ExpectedUpdatedAt != nil {
+		filter["updated_at"] = *taskUpdate.ExpectedUpdatedAt
+	}
+
 	// perform update and get the updated task
 	err = taskRepo.collection.FindOneAndUpdate(
 		contx,
-		bson.M{"_id": objID},
+		filter,
 		update,
 		opts,
 	).Decode(&updatedTask)
 
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			if taskUpdate.ExpectedUpdatedAt != nil {
+				if count, countErr := taskRepo.collection.CountDocuments(contx, bson.M{"_id": objID}); countErr == nil && count > 0 {
+					return nil, domain.ErrTaskModified        // changed since the caller read it
+				}
+			}
 			return nil, domain.ErrTaskNotFound
 		}
 		return nil, err
 	}
 
 	return &updatedTask, nil       // return the updated task and nil
-}
\ No newline at end of file
+}
+func (taskRepo *taskRepository) GetSubtasks(ctx context.Context, parentID string) ([]domain.Task, error) {
+	
+	var subtasks []domain.Task
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(parentID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	opts := options.Find().SetProjection(taskReadProjection)
+	cursor, err := taskRepo.collection.Find(contx, bson.M{"parent_id": objID}, opts)      // find direct children
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &subtasks)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return subtasks, nil
+}
+
+func (taskRepo *taskRepository) GetDescendantIDs(ctx context.Context, taskID string) ([]primitive.ObjectID, error) {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	// walk the hierarchy downwards from the task
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": objID}}},
+		{{Key: "$graphLookup", Value: bson.M{
+			"from":             taskRepo.collection.Name(),
+			"startWith":        "$_id",
+			"connectFromField": "_id",
+			"connectToField":   "parent_id",
+			"as":               "descendants",
+		}}},
+		{{Key: "$project", Value: bson.M{"ids": "$descendants._id"}}},
+	}
+
+	cursor, err := taskRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	var results []struct {
+		IDs []primitive.ObjectID `bson:"ids"`
+	}
+	err = cursor.All(contx, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return results[0].IDs, nil
+}
+
+func (taskRepo *taskRepository) DeleteTasks(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	_, err := taskRepo.collection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": taskIDs}})      // delete all given tasks
+	return err
+}
+
+func (taskRepo *taskRepository) GetTasksDueForReminder(ctx context.Context, dueBefore time.Time) ([]domain.Task, error) {
+	
+	var tasks []domain.Task
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// enabled reminders not sent yet on unfinished tasks that aren't overdue
+	filter := bson.M{
+		"reminder.enabled": true,
+		"reminder.sent_at": nil,
+		"status":           bson.M{"$ne": "completed"},
+		"due_date":         bson.M{"$gte": time.Now(), "$lte": dueBefore},
+	}
+
+	cursor, err := taskRepo.collection.Find(contx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (taskRepo *taskRepository) MarkReminderSent(ctx context.Context, taskID primitive.ObjectID, sentAt time.Time) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := taskRepo.collection.UpdateOne(contx, bson.M{"_id": taskID}, bson.M{"$set": bson.M{"reminder.sent_at": sentAt, "updated_at": storedNow()}})
+	return err
+}
+
+// occurrences of active series that are completed or due
+func (taskRepo *taskRepository) GetRecurringTasksDue(ctx context.Context, now time.Time) ([]domain.Task, error) {
+
+	var tasks []domain.Task
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{
+		"recurrence":             bson.M{"$exists": true},
+		"recurrence.paused":      false,
+		"recurrence.advanced_at": bson.M{"$exists": false},
+		"$or":                    bson.A{bson.M{"status": "completed"}, bson.M{"due_date": bson.M{"$lte": now}}},
+	}
+
+	cursor, err := taskRepo.collection.Find(contx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (taskRepo *taskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+
+	var updatedTask domain.Task
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	update := bson.M{"$unset": bson.M{"recurrence": ""}, "$set": bson.M{"updated_at": storedNow()}}
+	if rule != nil {
+		update = bson.M{"$set": bson.M{"recurrence": rule, "updated_at": storedNow()}}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = taskRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": objID}, update, opts).Decode(&updatedTask)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &updatedTask, nil
+}
+
+// conditional update, so only one instance creates the next occurrence
+func (taskRepo *taskRepository) AdvanceRecurrence(ctx context.Context, taskID primitive.ObjectID, at time.Time) (bool, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": taskID, "recurrence": bson.M{"$exists": true}, "recurrence.advanced_at": bson.M{"$exists": false}}
+	result, err := taskRepo.collection.UpdateOne(contx, filter, bson.M{"$set": bson.M{"recurrence.advanced_at": at, "updated_at": storedNow()}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (taskRepo *taskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.updateTags(ctx, taskID, bson.M{"$addToSet": bson.M{"tags": tag}, "$set": bson.M{"updated_at": storedNow()}})       // no duplicates
+}
+
+func (taskRepo *taskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	return taskRepo.updateTags(ctx, taskID, bson.M{"$pull": bson.M{"tags": tag}, "$set": bson.M{"updated_at": storedNow()}})
+}
+
+// apply tag update to one task and return it
+func (taskRepo *taskRepository) updateTags(ctx context.Context, taskID string, update bson.M) (*domain.Task, error) {
+	
+	var updatedTask domain.Task
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = taskRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": objID}, update, opts).Decode(&updatedTask)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &updatedTask, nil
+}
+
+func (taskRepo *taskRepository) RenameTagOnAll(ctx context.Context, oldTag string, newTag string) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"tag": oldTag}}})
+	_, err := taskRepo.collection.UpdateMany(contx, bson.M{"tags": oldTag}, bson.M{"$set": bson.M{"tags.$[tag]": newTag, "updated_at": storedNow()}}, opts)
+	return err
+}
+
+func (taskRepo *taskRepository) RemoveTagFromAll(ctx context.Context, tag string) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := taskRepo.collection.UpdateMany(contx, bson.M{"tags": tag}, bson.M{"$pull": bson.M{"tags": tag}, "$set": bson.M{"updated_at": storedNow()}})
+	return err
+}
+
+func (taskRepo *taskRepository) UpdateOverdueFlags(ctx context.Context, now time.Time) (int64, error) {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// unfinished tasks that just passed their due date
+	marked, err := taskRepo.collection.UpdateMany(
+		contx,
+		bson.M{"is_overdue": bson.M{"$ne": true}, "due_date": bson.M{"$lt": now}, "status": bson.M{"$ne": "completed"}},
+		bson.M{"$set": bson.M{"is_overdue": true, "updated_at": storedNow()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// overdue tasks that were completed or moved to a later date
+	cleared, err := taskRepo.collection.UpdateMany(
+		contx,
+		bson.M{"is_overdue": true, "$or": bson.A{bson.M{"due_date": bson.M{"$gte": now}}, bson.M{"status": "completed"}}},
+		bson.M{"$set": bson.M{"is_overdue": false, "updated_at": storedNow()}},
+	)
+	if err != nil {
+		return marked.ModifiedCount, err
+	}
+
+	return marked.ModifiedCount + cleared.ModifiedCount, nil
+}
+
+// give old tasks the default priority and make every rank match its priority
+func (taskRepo *taskRepository) BackfillPriorities(ctx context.Context) (int64, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// tasks stored before priorities existed
+	result, err := taskRepo.collection.UpdateMany(
+		contx,
+		bson.M{"$or": bson.A{bson.M{"priority": bson.M{"$exists": false}}, bson.M{"priority": ""}}},
+		bson.M{"$set": bson.M{"priority": domain.DefaultTaskPriority, "priority_rank": domain.TaskPriorities[domain.DefaultTaskPriority]}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	updated := result.ModifiedCount
+
+	// ranks out of step with their priority
+	for priority, rank := range domain.TaskPriorities {
+		result, err := taskRepo.collection.UpdateMany(
+			contx,
+			bson.M{"priority": priority, "priority_rank": bson.M{"$ne": rank}},
+			bson.M{"$set": bson.M{"priority_rank": rank}},
+		)
+		if err != nil {
+			return updated, err
+		}
+		updated += result.ModifiedCount
+	}
+
+	return updated, nil
+}
+
+func (taskRepo *taskRepository) BackfillTimestamps(ctx context.Context) (int64, error) {
+	return backfillTimestamps(ctx, taskRepo.collection)
+}
+
+func (taskRepo *taskRepository) EnsureIndexes(ctx context.Context) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := taskRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "is_overdue", Value: 1}, {Key: "due_date", Value: 1}}},       // overdue filters and counts
+		{Keys: bson.D{{Key: "tags", Value: 1}}},                                         // label filters
+		{Keys: bson.D{{Key: "project_id", Value: 1}}},                                   // project scoping
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},                                    // organization scoping
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},                                   // newest/oldest first
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},                                   // recently changed first, sync since a time
+		{Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}}, Options: options.Index().
+			SetWeights(bson.M{"title": 3, "description": 1}).SetDefaultLanguage(domain.DefaultTaskLanguage).SetLanguageOverride("language")},       // search, each task analyzed in its own language
+		{Keys: bson.D{{Key: "client_id", Value: 1}}, Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"client_id": bson.M{"$type": "string"}})},        // offline clients' ids, only tasks that have one
+	})
+	return err
+}
@@ -0,0 +1,194 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// deleted tasks kept in the deleted_tasks collection
+// restored tasks are put back through the task repository
+type taskTrashRepository struct {
+	trashCollection  *mongo.Collection
+}
+
+func NewTaskTrashRepository(trashCol *mongo.Collection) domain.TaskTrashRepository {
+	return &taskTrashRepository{trashCollection: trashCol}
+}
+
+// copy tasks into the trash, the caller deletes them from the task list
+func (trashRepo *taskTrashRepository) Add(ctx context.Context, tasks []domain.DeletedTask) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	docs := make([]interface{}, len(tasks))
+	ids := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		docs[i] = task
+		ids[i] = task.ID
+	}
+
+	// a copy left by a delete that failed afterwards is replaced
+	if _, err := trashRepo.trashCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return err
+	}
+	_, err := trashRepo.trashCollection.InsertMany(contx, docs)
+	return err
+}
+
+// find deleted tasks newest first
+func (trashRepo *taskTrashRepository) GetDeletedTasks(ctx context.Context, query domain.TrashQuery) ([]domain.DeletedTask, error) {
+
+	var tasks []domain.DeletedTask
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{}
+	if query.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*query.TenantID)
+	}
+	if query.Visibility != nil {
+		visible := []interface{}{nil}        // null matches tasks without a project
+		for _, projectID := range query.Visibility.Projects {
+			visible = append(visible, projectID)
+		}
+		filter["project_id"] = bson.M{"$in": visible}
+	}
+	if !query.Before.IsZero() {
+		filter["deleted_at"] = bson.M{"$lt": query.Before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(query.Limit)
+	cursor, err := trashRepo.trashCollection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &tasks); err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		return []domain.DeletedTask{}, nil
+	}
+
+	return tasks, nil
+}
+
+// find a deleted task
+func (trashRepo *taskTrashRepository) GetDeletedTask(ctx context.Context, taskID string) (*domain.DeletedTask, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)       // convert string id to mongodb's id format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	var task domain.DeletedTask
+	err = trashRepo.trashCollection.FindOne(contx, bson.M{"_id": objID}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDeletedTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// take a deleted task and the subtasks deleted with it out of the trash
+func (trashRepo *taskTrashRepository) Restore(ctx context.Context, taskID string) ([]domain.Task, error) {
+
+	deleted, err := trashRepo.GetDeletedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	var subtasks []domain.DeletedTask
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := trashRepo.trashCollection.Find(contx, bson.M{"deleted_with": deleted.ID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(contx, &subtasks); err != nil {
+		return nil, err
+	}
+
+	tasks := []domain.Task{deleted.Task}
+	ids := []primitive.ObjectID{deleted.ID}
+	for _, subtask := range subtasks {
+		tasks = append(tasks, subtask.Task)
+		ids = append(ids, subtask.ID)
+	}
+
+	if _, err := trashRepo.trashCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// remove tasks deleted before a time for good, oldest first
+func (trashRepo *taskTrashRepository) Purge(ctx context.Context, deletedBefore time.Time, tenantID *string, limit int64) ([]primitive.ObjectID, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": bson.M{"$lt": deletedBefore}}
+	if tenantID != nil {
+		filter["tenant_id"] = tenantFilter(*tenantID)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit).SetProjection(bson.M{"_id": 1})
+	cursor, err := trashRepo.trashCollection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var found []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(contx, &found); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(found))
+	for i, task := range found {
+		ids[i] = task.ID
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	if _, err := trashRepo.trashCollection.DeleteMany(contx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// create indexes if missing
+func (trashRepo *taskTrashRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := trashRepo.trashCollection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}},        // newest first, purge takes the oldest
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "deleted_at", Value: -1}}},    // trash of an organization
+		{Keys: bson.D{{Key: "deleted_with", Value: 1}}},                                 // subtasks deleted together with a task
+	})
+	return err
+}
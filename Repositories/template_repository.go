@@ -0,0 +1,175 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type templateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTemplateRepository(col *mongo.Collection) domain.TemplateRepository {
+	return &templateRepository{collection: col}
+}
+
+// store new template in database
+func (templateRepo *templateRepository) CreateTemplate(ctx context.Context, template *domain.TaskTemplate) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if template.ID.IsZero() {
+		template.ID = primitive.NewObjectID()
+	}
+
+	_, err := templateRepo.collection.InsertOne(contx, template)
+	return err
+}
+
+// find templates of a user ordered by title
+func (templateRepo *templateRepository) GetTemplates(ctx context.Context, ownerID string) ([]domain.TaskTemplate, error) {
+
+	var templates []domain.TaskTemplate
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "title", Value: 1}})
+	cursor, err := templateRepo.collection.Find(contx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	err = cursor.All(contx, &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	if templates == nil {
+		return []domain.TaskTemplate{}, nil
+	}
+
+	return templates, nil
+}
+
+// find template by its id
+func (templateRepo *templateRepository) GetTemplateByID(ctx context.Context, templateID string) (*domain.TaskTemplate, error) {
+
+	var template domain.TaskTemplate
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, domain.ErrInvalidTemplateID
+	}
+
+	err = templateRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil        // success
+}
+
+// update template fields that were provided
+func (templateRepo *templateRepository) UpdateTemplate(ctx context.Context, templateID string, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+
+	var updated domain.TaskTemplate
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, domain.ErrInvalidTemplateID
+	}
+
+	setFields := bson.M{"updated_at": template.UpdatedAt}
+	unsetFields := bson.M{}
+	if template.Title != "" {
+		setFields["title"] = template.Title
+	}
+	if template.Description != "" {
+		setFields["description"] = template.Description
+	}
+	if template.Priority != "" {
+		setFields["priority"] = template.Priority
+	}
+	// empty lists clear the checklist or labels
+	if template.Checklist != nil {
+		if len(template.Checklist) == 0 {
+			unsetFields["checklist"] = ""
+		} else {
+			setFields["checklist"] = template.Checklist
+		}
+	}
+	if template.Tags != nil {
+		if len(template.Tags) == 0 {
+			unsetFields["tags"] = ""
+		} else {
+			setFields["tags"] = template.Tags
+		}
+	}
+
+	change := bson.M{"$set": setFields}
+	if len(unsetFields) > 0 {
+		change["$unset"] = unsetFields
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = templateRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": objID}, change, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &updated, nil        // success
+}
+
+// delete template by its id
+func (templateRepo *templateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return domain.ErrInvalidTemplateID
+	}
+
+	result, err := templateRepo.collection.DeleteOne(contx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil        // success
+}
+
+// templates are listed per owner
+func (templateRepo *templateRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := templateRepo.collection.Indexes().CreateOne(contx, mongo.IndexModel{
+		Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "title", Value: 1}},
+	})
+	return err
+}
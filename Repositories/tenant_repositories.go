@@ -0,0 +1,343 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// stored tenant id of a filter (null also matches documents from before organizations)
+func tenantFilter(tenantID string) interface{} {
+	if tenantID == "" {
+		return nil
+	}
+	return tenantID
+}
+
+// task repository limited to the organization of the request's actor
+// new tasks get the actor's tenant, tasks of other tenants are reported as not found
+// background jobs (no actor) see every tenant, label-wide changes follow instance-wide labels
+type tenantTaskRepository struct {
+	domain.TaskRepository                     // scoped repository (background queries pass through)
+}
+
+func NewTenantTaskRepository(repo domain.TaskRepository) domain.TaskRepository {
+	return &tenantTaskRepository{TaskRepository: repo}
+}
+
+func (taskRepo *tenantTaskRepository) GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
+	scopeTenantQuery(ctx, &query.TenantID)
+	return taskRepo.TaskRepository.GetAllTasks(ctx, query)
+}
+
+func (taskRepo *tenantTaskRepository) StreamTasks(ctx context.Context, query domain.TaskQuery, fn func(task *domain.Task) error) error {
+	scopeTenantQuery(ctx, &query.TenantID)
+	return taskRepo.TaskRepository.StreamTasks(ctx, query, fn)
+}
+
+func (taskRepo *tenantTaskRepository) GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	task, err := taskRepo.TaskRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, task.TenantID) {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+func (taskRepo *tenantTaskRepository) GetTaskByClientID(ctx context.Context, clientID string) (*domain.Task, error) {
+
+	task, err := taskRepo.TaskRepository.GetTaskByClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, task.TenantID) {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+func (taskRepo *tenantTaskRepository) GetSubtasks(ctx context.Context, parentID string) ([]domain.Task, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, parentID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.GetSubtasks(ctx, parentID)
+}
+
+func (taskRepo *tenantTaskRepository) GetDescendantIDs(ctx context.Context, taskID string) ([]primitive.ObjectID, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.GetDescendantIDs(ctx, taskID)
+}
+
+func (taskRepo *tenantTaskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	stampTenant(ctx, &task.TenantID)
+	return taskRepo.TaskRepository.CreateTask(ctx, task)
+}
+
+func (taskRepo *tenantTaskRepository) CreateTasks(ctx context.Context, tasks []*domain.Task) error {
+	for _, task := range tasks {
+		stampTenant(ctx, &task.TenantID)
+	}
+	return taskRepo.TaskRepository.CreateTasks(ctx, tasks)
+}
+
+func (taskRepo *tenantTaskRepository) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+	for _, task := range tasks {
+		if !domain.TenantVisible(ctx, task.TenantID) {        // restored tasks keep their tenant
+			return domain.ErrTaskNotFound
+		}
+	}
+	return taskRepo.TaskRepository.RestoreTasks(ctx, tasks)
+}
+
+func (taskRepo *tenantTaskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.TaskUpdate) (*domain.Task, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.UpdateTask(ctx, taskID, taskUpdate)
+}
+
+func (taskRepo *tenantTaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+	return taskRepo.TaskRepository.DeleteTask(ctx, taskID)
+}
+
+func (taskRepo *tenantTaskRepository) AddTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.AddTag(ctx, taskID, tag)
+}
+
+func (taskRepo *tenantTaskRepository) RemoveTag(ctx context.Context, taskID string, tag string) (*domain.Task, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.RemoveTag(ctx, taskID, tag)
+}
+
+func (taskRepo *tenantTaskRepository) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+	if _, err := taskRepo.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskRepo.TaskRepository.SetRecurrence(ctx, taskID, rule)
+}
+
+// search service limited to the organization of the request's actor
+type tenantSearchService struct {
+	domain.SearchService                      // scoped service (indexing passes through)
+}
+
+func NewTenantSearchService(service domain.SearchService) domain.SearchService {
+	return &tenantSearchService{SearchService: service}
+}
+
+func (searchService *tenantSearchService) SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchMatch, error) {
+	scopeTenantQuery(ctx, &query.TenantID)
+	return searchService.SearchService.SearchTasks(ctx, query)
+}
+
+// user repository limited to the organization of the request's actor
+// logins, registrations and uniqueness checks look up usernames and emails across tenants
+type tenantUserRepository struct {
+	domain.UserRepository                     // scoped repository (username and email lookups pass through)
+}
+
+func NewTenantUserRepository(repo domain.UserRepository) domain.UserRepository {
+	return &tenantUserRepository{UserRepository: repo}
+}
+
+func (userRepo *tenantUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	stampTenant(ctx, &user.TenantID)
+	return userRepo.UserRepository.CreateUser(ctx, user)
+}
+
+func (userRepo *tenantUserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+
+	user, err := userRepo.UserRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, user.TenantID) {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (userRepo *tenantUserRepository) ListUsers(ctx context.Context, query domain.UserQuery) ([]domain.User, int64, error) {
+	scopeTenantQuery(ctx, &query.TenantID)
+	return userRepo.UserRepository.ListUsers(ctx, query)
+}
+
+func (userRepo *tenantUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.UpdatePassword(ctx, id, hashedPassword)
+}
+
+func (userRepo *tenantUserRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, profile *domain.UpdateProfileRequest) (*domain.User, error) {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return nil, err
+	}
+	return userRepo.UserRepository.UpdateProfile(ctx, id, profile)
+}
+
+func (userRepo *tenantUserRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.UpdateRole(ctx, id, role)
+}
+
+func (userRepo *tenantUserRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.ResetFailedLogins(ctx, id)
+}
+
+func (userRepo *tenantUserRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string, at time.Time) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.MarkEmailVerified(ctx, id, email, at)
+}
+
+func (userRepo *tenantUserRepository) SetTwoFactor(ctx context.Context, id primitive.ObjectID, settings *domain.TwoFactorSettings) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.SetTwoFactor(ctx, id, settings)
+}
+
+func (userRepo *tenantUserRepository) UseTwoFactorStep(ctx context.Context, id primitive.ObjectID, step int64) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.UseTwoFactorStep(ctx, id, step)
+}
+
+func (userRepo *tenantUserRepository) UseBackupCode(ctx context.Context, id primitive.ObjectID, codeHash string) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.UseBackupCode(ctx, id, codeHash)
+}
+
+func (userRepo *tenantUserRepository) SetDeactivated(ctx context.Context, id primitive.ObjectID, at *time.Time) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.SetDeactivated(ctx, id, at)
+}
+
+func (userRepo *tenantUserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.DeleteUser(ctx, id)
+}
+
+func (userRepo *tenantUserRepository) SetTenant(ctx context.Context, id primitive.ObjectID, tenantID string) error {
+	if _, err := userRepo.GetUserById(ctx, id); err != nil {
+		return err
+	}
+	return userRepo.UserRepository.SetTenant(ctx, id, tenantID)
+}
+
+// project repository limited to the organization of the request's actor
+type tenantProjectRepository struct {
+	domain.ProjectRepository                  // scoped repository
+}
+
+func NewTenantProjectRepository(repo domain.ProjectRepository) domain.ProjectRepository {
+	return &tenantProjectRepository{ProjectRepository: repo}
+}
+
+func (projectRepo *tenantProjectRepository) CreateProject(ctx context.Context, project *domain.Project) error {
+	stampTenant(ctx, &project.TenantID)
+	return projectRepo.ProjectRepository.CreateProject(ctx, project)
+}
+
+func (projectRepo *tenantProjectRepository) GetProjects(ctx context.Context, userID string) ([]domain.Project, error) {
+
+	projects, err := projectRepo.ProjectRepository.GetProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []domain.Project{}
+	for _, project := range projects {
+		if domain.TenantVisible(ctx, project.TenantID) {
+			visible = append(visible, project)
+		}
+	}
+	return visible, nil
+}
+
+func (projectRepo *tenantProjectRepository) GetProjectByID(ctx context.Context, projectID string) (*domain.Project, error) {
+
+	project, err := projectRepo.ProjectRepository.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, project.TenantID) {
+		return nil, domain.ErrProjectNotFound
+	}
+
+	return project, nil
+}
+
+func (projectRepo *tenantProjectRepository) UpdateProject(ctx context.Context, projectID string, project *domain.Project) (*domain.Project, error) {
+	if _, err := projectRepo.GetProjectByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return projectRepo.ProjectRepository.UpdateProject(ctx, projectID, project)
+}
+
+func (projectRepo *tenantProjectRepository) DeleteProject(ctx context.Context, projectID string) error {
+	if _, err := projectRepo.GetProjectByID(ctx, projectID); err != nil {
+		return err
+	}
+	return projectRepo.ProjectRepository.DeleteProject(ctx, projectID)
+}
+
+func (projectRepo *tenantProjectRepository) SetMember(ctx context.Context, projectID string, member domain.ProjectMember) (*domain.Project, error) {
+	if _, err := projectRepo.GetProjectByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return projectRepo.ProjectRepository.SetMember(ctx, projectID, member)
+}
+
+func (projectRepo *tenantProjectRepository) RemoveMember(ctx context.Context, projectID string, userID string) (*domain.Project, error) {
+	if _, err := projectRepo.GetProjectByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return projectRepo.ProjectRepository.RemoveMember(ctx, projectID, userID)
+}
+
+// limit a query to the actor's tenant (background jobs query every tenant)
+func scopeTenantQuery(ctx context.Context, tenantID **string) {
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		*tenantID = &tenant
+	}
+}
+
+// give new records the actor's tenant (background jobs keep the tenant they set)
+func stampTenant(ctx context.Context, tenantID *string) {
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		*tenantID = tenant
+	}
+}
@@ -0,0 +1,36 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+)
+
+// current time as mongodb stores it (utc, milliseconds), so a written timestamp reads back unchanged
+func storedNow() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// give documents stored before timestamps existed their creation time from the object id
+// (the last change is unknown, creation stands in for it)
+func backfillTimestamps(ctx context.Context, collection *mongo.Collection) (int64, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := collection.UpdateMany(
+		contx,
+		bson.M{"created_at": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$_id"}}}},
+			{{Key: "$set", Value: bson.M{"updated_at": bson.M{"$ifNull": bson.A{"$updated_at", "$created_at"}}}}},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type twoFactorChallengeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTwoFactorChallengeRepository(col *mongo.Collection) domain.TwoFactorChallengeRepository {
+	return &twoFactorChallengeRepository{collection: col}
+}
+
+// store new login challenge in database
+func (challengeRepo *twoFactorChallengeRepository) CreateChallenge(ctx context.Context, challenge *domain.TwoFactorChallenge) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if challenge.ID.IsZero() {
+		challenge.ID = primitive.NewObjectID()
+	}
+
+	_, err := challengeRepo.collection.InsertOne(contx, challenge)
+	return err
+}
+
+// find login challenge by its hash
+func (challengeRepo *twoFactorChallengeRepository) GetChallengeByHash(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error) {
+
+	var challenge domain.TwoFactorChallenge
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	err := challengeRepo.collection.FindOne(contx, bson.M{"token_hash": tokenHash}).Decode(&challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidTwoFactorChallenge
+		}
+		return nil, err
+	}
+
+	return &challenge, nil        // success
+}
+
+// count a wrong code atomically so parallel guesses all count
+func (challengeRepo *twoFactorChallengeRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) (int, error) {
+
+	var challenge domain.TwoFactorChallenge
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := challengeRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, domain.ErrInvalidTwoFactorChallenge
+		}
+		return 0, err
+	}
+
+	return challenge.Attempts, nil
+}
+
+// remove a login challenge
+func (challengeRepo *twoFactorChallengeRepository) DeleteChallenge(ctx context.Context, id primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := challengeRepo.collection.DeleteOne(contx, bson.M{"_id": id})
+	return err
+}
+
+// create ttl index (mongodb removes expired challenges) and lookup index
+func (challengeRepo *twoFactorChallengeRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := challengeRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},               // challenge lookup
+	})
+	return err
+}
@@ -0,0 +1,63 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+)
+
+// unit of work backed by a mongodb multi-document transaction (needs a replica set or sharded cluster)
+type mongoUnitOfWork struct {
+	client  *mongo.Client
+}
+
+func NewMongoUnitOfWork(client *mongo.Client) domain.UnitOfWork {
+	return &mongoUnitOfWork{client: client}
+}
+
+// run fn in a transaction, the session travels in the context to every collection call
+func (uow *mongoUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	// already inside a unit, join it
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := uow.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// unit of work without a transaction (memory storage and standalone mongodb servers)
+type directUnitOfWork struct{}
+
+func NewDirectUnitOfWork() domain.UnitOfWork {
+	return directUnitOfWork{}
+}
+
+// run fn as is, calls are not rolled back when a later one fails
+func (directUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// check if the server accepts transactions (replica set members and mongos do, standalone servers don't)
+func TransactionsSupported(ctx context.Context, client *mongo.Client) (bool, error) {
+
+	var hello struct {
+		SetName  string  `bson:"setName"`
+		Msg      string  `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
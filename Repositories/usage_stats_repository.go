@@ -0,0 +1,53 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/mongo";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type usageStatsRepository struct {
+	taskCollection    *mongo.Collection
+	userCollection    *mongo.Collection
+	clientCollection  *mongo.Collection
+	tokenCollection   *mongo.Collection
+}
+
+func NewUsageStatsRepository(taskCol *mongo.Collection, userCol *mongo.Collection, clientCol *mongo.Collection, tokenCol *mongo.Collection) domain.UsageStatsRepository {
+	return &usageStatsRepository{taskCollection: taskCol, userCollection: userCol, clientCollection: clientCol, tokenCollection: tokenCol}
+}
+
+// count entities across collections
+func (usageRepo *usageStatsRepository) GetUsageCounts(ctx context.Context) (*domain.UsageCounts, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	counts := &domain.UsageCounts{}
+	queries := []struct {
+		collection  *mongo.Collection
+		filter      bson.M
+		target      *int64
+	}{
+		{usageRepo.taskCollection, bson.M{}, &counts.Tasks},
+		{usageRepo.taskCollection, bson.M{"parent_id": bson.M{"$exists": true}}, &counts.Subtasks},
+		{usageRepo.taskCollection, bson.M{"reminder.enabled": true}, &counts.TasksWithReminders},
+		{usageRepo.userCollection, bson.M{}, &counts.Users},
+		{usageRepo.userCollection, bson.M{"role": domain.RoleAdmin}, &counts.Admins},
+		{usageRepo.clientCollection, bson.M{}, &counts.OAuthClients},
+		{usageRepo.tokenCollection, bson.M{"revoked": false, "expires_at": bson.M{"$gt": time.Now()}}, &counts.PersonalAccessTokens},
+	}
+
+	for _, query := range queries {
+		count, err := query.collection.CountDocuments(contx, query.filter)
+		if err != nil {
+			return nil, err
+		}
+		*query.target = count
+	}
+
+	return counts, nil        // success
+}
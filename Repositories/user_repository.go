@@ -3,10 +3,12 @@ package repositories
 // imports
 import (
 	"context";
+	"regexp";
 	"time";
 	"go.mongodb.org/mongo-driver/bson";
 	"go.mongodb.org/mongo-driver/bson/primitive";
 	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 )
 
@@ -19,15 +21,17 @@ func NewUserRepository(col *mongo.Collection) domain.UserRepository {
 }
 
 //  register user in to database
-func (userRepo *userRepository) CreateUser(user *domain.User) error {
+func (userRepo *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	// generate new ObjectID if not set
 	if user.ID.IsZero() {
 		user.ID = primitive.NewObjectID()
 	}
+	user.CreatedAt = storedNow()        // server clock, client values are ignored
+	user.UpdatedAt = user.CreatedAt
 
 	// save user to database
 	_, err := userRepo.collection.InsertOne(contx, user)
@@ -42,10 +46,10 @@ func (userRepo *userRepository) CreateUser(user *domain.User) error {
 }
 
 // find user from database by username
-func (userRepo *userRepository) GetByUsername(username string) (*domain.User, error) {
+func (userRepo *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	
 	var user domain.User
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 	
 	// find user by username
@@ -61,10 +65,10 @@ func (userRepo *userRepository) GetByUsername(username string) (*domain.User, er
 }
 
 // find user from database by id
-func (userRepo *userRepository) GetUserById(userID primitive.ObjectID) (*domain.User, error) {
+func (userRepo *userRepository) GetUserById(ctx context.Context, userID primitive.ObjectID) (*domain.User, error) {
 	
 	var user domain.User
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 	
 	// find user by id
@@ -79,10 +83,29 @@ func (userRepo *userRepository) GetUserById(userID primitive.ObjectID) (*domain.
 	return &user, nil         // success
 }
 
+// find user from database by email
+func (userRepo *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	
+	var user domain.User
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+	
+	// find user by email
+	err := userRepo.collection.FindOne(contx, bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil        // success
+}
+
 // count users in the database currently
-func (userRepo *userRepository) GetUserCount() (int64, error) {
+func (userRepo *userRepository) GetUserCount(ctx context.Context) (int64, error) {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	// count users in user collection currently
@@ -95,16 +118,16 @@ func (userRepo *userRepository) GetUserCount() (int64, error) {
 }
 
 // update user role to admin in database (only admins can perform this operation)
-func (userRepo *userRepository) UpdateRole(id primitive.ObjectID, role string) error {
+func (userRepo *userRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
 	defer cancel()
 
 	// update user's role to admin
 	result, err := userRepo.collection.UpdateOne(
 		contx,
 		bson.M{"_id": id},
-		bson.M{"$set": bson.M{"role": role}},
+		bson.M{"$set": bson.M{"role": role, "updated_at": storedNow()}},
 	)
 
 	if err != nil {
@@ -116,4 +139,392 @@ func (userRepo *userRepository) UpdateRole(id primitive.ObjectID, role string) e
 	}
 
 	return nil        // success
-}
\ No newline at end of file
+}
+
+// replace user password hash in database
+func (userRepo *userRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": hashedPassword, "updated_at": storedNow()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil        // success
+}
+
+// update provided profile fields in database
+func (userRepo *userRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, profile *domain.UpdateProfileRequest) (*domain.User, error) {
+	
+	var user domain.User
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// only set fields that were provided
+	updateFields := bson.M{}
+	if profile.Email != "" {
+		updateFields["email"] = profile.Email
+	}
+	if profile.DisplayName != "" {
+		updateFields["display_name"] = profile.DisplayName
+	}
+	if profile.Notifications != nil {
+		updateFields["notifications"] = profile.Notifications
+	}
+	updateFields["updated_at"] = storedNow()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := userRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": id}, bson.M{"$set": updateFields}, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrUserExists        // email belongs to another account
+		}
+		return nil, err
+	}
+
+	return &user, nil        // success
+}
+
+// count a failed login attempt and return the failures so far
+func (userRepo *userRepository) IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error) {
+	
+	var user domain.User
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// increment atomically so concurrent attempts are all counted
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := userRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"failed_logins": 1}},
+		opts,
+	).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, domain.ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return user.FailedLogins, nil        // success
+}
+
+// block logins until a time and start counting failures again
+func (userRepo *userRepository) LockUser(ctx context.Context, id primitive.ObjectID, until time.Time) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"locked_until": until, "failed_logins": 0, "updated_at": storedNow()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil        // success
+}
+
+// clear failed login count and lock
+func (userRepo *userRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"failed_logins": 0, "updated_at": storedNow()}, "$unset": bson.M{"locked_until": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil        // success
+}
+
+// confirm email address, a link sent before the address changed confirms nothing
+func (userRepo *userRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string, at time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id, "email": email},
+		bson.M{"$set": bson.M{"email_verified_at": at, "updated_at": storedNow()}, "$unset": bson.M{"email_verification_pending": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil        // success
+}
+
+// replace two-factor settings, nil removes them
+func (userRepo *userRepository) SetTwoFactor(ctx context.Context, id primitive.ObjectID, settings *domain.TwoFactorSettings) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"two_factor": ""}, "$set": bson.M{"updated_at": storedNow()}}
+	if settings != nil {
+		update = bson.M{"$set": bson.M{"two_factor": settings, "updated_at": storedNow()}}
+	}
+	result, err := userRepo.collection.UpdateOne(contx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// move the last used time step forward, a code replayed (even concurrently) matches nothing
+func (userRepo *userRepository) UseTwoFactorStep(ctx context.Context, id primitive.ObjectID, step int64) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id, "two_factor.enabled_at": bson.M{"$exists": true}, "two_factor.last_used_step": bson.M{"$not": bson.M{"$gte": step}}},
+		bson.M{"$set": bson.M{"two_factor.last_used_step": step}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// pull a backup code, each one matches once
+func (userRepo *userRepository) UseBackupCode(ctx context.Context, id primitive.ObjectID, codeHash string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.UpdateOne(
+		contx,
+		bson.M{"_id": id, "two_factor.enabled_at": bson.M{"$exists": true}, "two_factor.backup_codes": codeHash},
+		bson.M{"$pull": bson.M{"two_factor.backup_codes": codeHash}, "$set": bson.M{"updated_at": storedNow()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// find a page of users matching query ordered by username, with the total match count
+func (userRepo *userRepository) ListUsers(ctx context.Context, query domain.UserQuery) ([]domain.User, int64, error) {
+
+	var users []domain.User
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{}
+	if query.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Search), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"username": pattern}, bson.M{"email": pattern}, bson.M{"display_name": pattern}}
+	}
+	if query.Role != "" {
+		filter["role"] = query.Role
+	}
+	switch query.Status {
+	case domain.UserStatusActive:
+		filter["deactivated_at"] = bson.M{"$exists": false}
+	case domain.UserStatusDeactivated:
+		filter["deactivated_at"] = bson.M{"$exists": true}
+	}
+	if query.TenantID != nil {
+		filter["tenant_id"] = tenantFilter(*query.TenantID)
+	}
+
+	total, err := userRepo.collection.CountDocuments(contx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort := bson.D{{Key: "username", Value: 1}}
+	if query.Sort.Field != "" {
+		direction := 1
+		if query.Sort.Descending {
+			direction = -1
+		}
+		sort = bson.D{{Key: domain.UserSortFields[query.Sort.Field], Value: direction}, {Key: "username", Value: 1}}
+	}
+	opts := options.Find().SetSort(sort).
+		SetSkip(int64((query.Page - 1) * query.Limit)).SetLimit(int64(query.Limit))
+	cursor, err := userRepo.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &users); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// deactivate an account at a time, nil reactivates it
+func (userRepo *userRepository) SetDeactivated(ctx context.Context, id primitive.ObjectID, at *time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"deactivated_at": ""}, "$set": bson.M{"updated_at": storedNow()}}
+	if at != nil {
+		update = bson.M{"$set": bson.M{"deactivated_at": *at, "updated_at": storedNow()}}
+	}
+	result, err := userRepo.collection.UpdateOne(contx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// delete user by its id
+func (userRepo *userRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	result, err := userRepo.collection.DeleteOne(contx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// find user linked to a provider account
+func (userRepo *userRepository) GetByIdentity(ctx context.Context, provider string, subject string) (*domain.User, error) {
+
+	var user domain.User
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	err := userRepo.collection.FindOne(contx, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// link a provider account to a user (already linked accounts are left alone)
+func (userRepo *userRepository) LinkIdentity(ctx context.Context, id primitive.ObjectID, identity domain.LinkedIdentity) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "identities": bson.M{"$not": bson.M{"$elemMatch": bson.M{"provider": identity.Provider, "subject": identity.Subject}}}}
+	result, err := userRepo.collection.UpdateOne(contx, filter, bson.M{"$push": bson.M{"identities": identity}, "$set": bson.M{"updated_at": storedNow()}})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrUserExists        // account linked to another user
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := userRepo.GetUserById(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move a user into an organization
+func (userRepo *userRepository) SetTenant(ctx context.Context, id primitive.ObjectID, tenantID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"tenant_id": tenantID, "updated_at": storedNow()}}
+	if tenantID == "" {
+		update = bson.M{"$unset": bson.M{"tenant_id": ""}, "$set": bson.M{"updated_at": storedNow()}}
+	}
+	result, err := userRepo.collection.UpdateOne(contx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (userRepo *userRepository) BackfillTimestamps(ctx context.Context) (int64, error) {
+	return backfillTimestamps(ctx, userRepo.collection)
+}
+
+// unique indexes close the race between the existence check and the insert
+func (userRepo *userRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := userRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"email": bson.M{"$exists": true}})},       // email is optional
+		{Keys: bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}}, Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"identities": bson.M{"$exists": true}})},       // a provider account signs in to one user
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},                                    // newest/oldest accounts first
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},                                    // recently changed accounts first
+	})
+	return err
+}
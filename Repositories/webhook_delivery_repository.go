@@ -0,0 +1,87 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type webhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(col *mongo.Collection) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{collection: col}
+}
+
+// store delivery with its attempts
+func (deliveryRepo *webhookDeliveryRepository) RecordDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+
+	_, err := deliveryRepo.collection.InsertOne(contx, delivery)
+	return err
+}
+
+// find deliveries of a webhook newest first, only those before the given time unless it is zero
+func (deliveryRepo *webhookDeliveryRepository) GetDeliveries(ctx context.Context, webhookID string, before time.Time, limit int64) ([]domain.WebhookDelivery, error) {
+
+	var deliveries []domain.WebhookDelivery
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	filter := bson.M{"webhook_id": webhookID}
+	if !before.IsZero() {
+		filter["created_at"] = bson.M{"$lt": before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := deliveryRepo.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &deliveries); err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		return []domain.WebhookDelivery{}, nil
+	}
+
+	return deliveries, nil
+}
+
+// delete every delivery of a webhook
+func (deliveryRepo *webhookDeliveryRepository) DeleteDeliveries(ctx context.Context, webhookID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := deliveryRepo.collection.DeleteMany(contx, bson.M{"webhook_id": webhookID})
+	return err
+}
+
+// create lookup index and drop deliveries once they expired
+func (deliveryRepo *webhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := deliveryRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},       // expire at expires_at
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},                          // delivery log of a webhook
+	})
+	return err
+}
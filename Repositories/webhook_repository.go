@@ -0,0 +1,172 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type webhookRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookRepository(col *mongo.Collection) domain.WebhookRepository {
+	return &webhookRepository{collection: col}
+}
+
+// store new webhook in database
+func (webhookRepo *webhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if webhook.ID.IsZero() {
+		webhook.ID = primitive.NewObjectID()
+	}
+
+	_, err := webhookRepo.collection.InsertOne(contx, webhook)
+	return err
+}
+
+// find all webhooks ordered by creation
+func (webhookRepo *webhookRepository) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+
+	var webhooks []domain.Webhook
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := webhookRepo.collection.Find(contx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &webhooks); err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		return []domain.Webhook{}, nil
+	}
+
+	return webhooks, nil
+}
+
+// find webhook by its id
+func (webhookRepo *webhookRepository) GetWebhookByID(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+
+	var webhook domain.Webhook
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrInvalidWebhookID
+	}
+
+	err = webhookRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil        // success
+}
+
+// replace url, events and enabled state (with the disabled and failing marks)
+func (webhookRepo *webhookRepository) UpdateWebhook(ctx context.Context, webhookID string, webhook *domain.Webhook) (*domain.Webhook, error) {
+
+	var updated domain.Webhook
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrInvalidWebhookID
+	}
+
+	setFields := bson.M{"url": webhook.URL, "events": webhook.Events, "enabled": webhook.Enabled}
+	unsetFields := bson.M{}
+	if webhook.TenantID != "" {
+		setFields["tenant_id"] = webhook.TenantID
+	} else {
+		unsetFields["tenant_id"] = ""
+	}
+	if webhook.DisabledAt != nil {
+		setFields["disabled_at"] = webhook.DisabledAt
+		setFields["disabled_reason"] = webhook.DisabledReason
+	} else {
+		unsetFields["disabled_at"] = ""
+		unsetFields["disabled_reason"] = ""
+	}
+	if webhook.FailingSince == nil {
+		unsetFields["failing_since"] = ""
+	}
+	update := bson.M{"$set": setFields}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = webhookRepo.collection.FindOneAndUpdate(contx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// delete webhook by its id
+func (webhookRepo *webhookRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrInvalidWebhookID
+	}
+
+	result, err := webhookRepo.collection.DeleteOne(contx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// a success ends the failing streak, a failure starts one unless it is running already
+func (webhookRepo *webhookRepository) RecordOutcome(ctx context.Context, webhookID string, succeeded bool, at time.Time) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrInvalidWebhookID
+	}
+
+	// webhooks deleted meanwhile match nothing, which is fine
+	if succeeded {
+		_, err = webhookRepo.collection.UpdateOne(contx, bson.M{"_id": objID}, bson.M{"$unset": bson.M{"failing_since": ""}})
+		return err
+	}
+	filter := bson.M{"_id": objID, "failing_since": bson.M{"$exists": false}}
+	_, err = webhookRepo.collection.UpdateOne(contx, filter, bson.M{"$set": bson.M{"failing_since": at}})
+	return err
+}
@@ -0,0 +1,180 @@
+package repositories
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+type worklogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWorklogRepository(col *mongo.Collection) domain.WorklogRepository {
+	return &worklogRepository{collection: col}
+}
+
+// store a running timer, the unique index on running timers rejects a second one of the user
+func (worklogRepo *worklogRepository) StartTimer(ctx context.Context, worklog *domain.Worklog) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if worklog.ID.IsZero() {
+		worklog.ID = primitive.NewObjectID()
+	}
+	worklog.Running = true
+
+	_, err := worklogRepo.collection.InsertOne(contx, worklog)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTimerRunning
+	}
+	return err
+}
+
+// find the running timer of a user
+func (worklogRepo *worklogRepository) GetRunningTimer(ctx context.Context, userID string) (*domain.Worklog, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	var worklog domain.Worklog
+	err := worklogRepo.collection.FindOne(contx, bson.M{"user_id": userID, "running": true}).Decode(&worklog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTimerNotRunning
+		}
+		return nil, err
+	}
+
+	return &worklog, nil
+}
+
+// stop the user's timer on a task and record the time spent
+func (worklogRepo *worklogRepository) StopTimer(ctx context.Context, userID string, taskID string, endedAt time.Time) (*domain.Worklog, error) {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	var worklog domain.Worklog
+	err := worklogRepo.collection.FindOne(contx, bson.M{"user_id": userID, "task_id": taskID, "running": true}).Decode(&worklog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTimerNotRunning
+		}
+		return nil, err
+	}
+
+	seconds := int64(endedAt.Sub(worklog.StartedAt) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	update := bson.M{"$set": bson.M{"ended_at": endedAt, "seconds": seconds}, "$unset": bson.M{"running": ""}}
+	result, err := worklogRepo.collection.UpdateOne(contx, bson.M{"_id": worklog.ID, "running": true}, update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrTimerNotRunning        // stopped by a concurrent request
+	}
+
+	worklog.Running = false
+	worklog.EndedAt = &endedAt
+	worklog.Seconds = seconds
+	return &worklog, nil
+}
+
+// store a manual entry
+func (worklogRepo *worklogRepository) AddWorklog(ctx context.Context, worklog *domain.Worklog) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if worklog.ID.IsZero() {
+		worklog.ID = primitive.NewObjectID()
+	}
+
+	_, err := worklogRepo.collection.InsertOne(contx, worklog)
+	return err
+}
+
+// find worklogs of a task oldest first
+func (worklogRepo *worklogRepository) GetWorklogs(ctx context.Context, taskID string) ([]domain.Worklog, error) {
+
+	var worklogs []domain.Worklog
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := worklogRepo.collection.Find(contx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &worklogs); err != nil {
+		return nil, err
+	}
+	if worklogs == nil {
+		return []domain.Worklog{}, nil
+	}
+
+	return worklogs, nil
+}
+
+// delete an entry of the user (any user when userID is empty)
+func (worklogRepo *worklogRepository) DeleteWorklog(ctx context.Context, taskID string, worklogID string, userID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(worklogID)
+	if err != nil {
+		return domain.ErrInvalidWorklogID
+	}
+
+	filter := bson.M{"_id": objID, "task_id": taskID}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+	result, err := worklogRepo.collection.DeleteOne(contx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWorklogNotFound
+	}
+
+	return nil
+}
+
+// delete every worklog of a task (running timers too)
+func (worklogRepo *worklogRepository) DeleteTaskWorklogs(ctx context.Context, taskID string) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := worklogRepo.collection.DeleteMany(contx, bson.M{"task_id": taskID})
+	return err
+}
+
+// create indexes if missing
+func (worklogRepo *worklogRepository) EnsureIndexes(ctx context.Context) error {
+
+	contx, cancel := withDeadline(ctx)        // honor request deadline (default timeout when none)
+	defer cancel()
+
+	_, err := worklogRepo.collection.Indexes().CreateMany(contx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "started_at", Value: 1}}},        // worklogs of a task
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"running": true})},       // one running timer per user
+	})
+	return err
+}
@@ -0,0 +1,124 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// admin invite usecase (the only way besides setup and promotion to get a new admin account)
+type AdminInviteUseCase interface {
+	CreateInvite(ctx context.Context, actorID string, email string) (*domain.AdminInvite, string, error)      // create single-use invite, returns plain token once
+	AcceptInvite(ctx context.Context, token string, admin *domain.User) error                                // create an admin account with an invite token
+}
+
+type adminInviteUseCase struct {
+	inviteRepo  domain.AdminInviteRepository
+	userRepo    domain.UserRepository
+	pwdService  domain.PasswordService
+	auditSink   domain.AuditSink
+	inviteTTL   time.Duration      // how long an invite stays valid
+}
+
+// creates new AdminInviteUseCase instance
+func NewAdminInviteUseCase(inviteRepo domain.AdminInviteRepository, userRepo domain.UserRepository, pwdServ domain.PasswordService, auditSink domain.AuditSink, inviteTTL time.Duration) AdminInviteUseCase {
+	return &adminInviteUseCase{inviteRepo: inviteRepo, userRepo: userRepo, pwdService: pwdServ, auditSink: auditSink, inviteTTL: inviteTTL}
+}
+
+// create single-use invite
+func (inviteUsc *adminInviteUseCase) CreateInvite(ctx context.Context, actorID string, email string) (*domain.AdminInvite, string, error) {
+
+	// generate token, only its hash is stored
+	plain, err := generateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	invite := &domain.AdminInvite{
+		TokenHash: hashToken(plain),
+		Email:     email,
+		CreatedBy: actorID,
+		ExpiresAt: now.Add(inviteUsc.inviteTTL),
+		CreatedAt: now,
+	}
+	if err := inviteUsc.inviteRepo.CreateInvite(ctx, invite); err != nil {
+		return nil, "", err
+	}
+
+	inviteUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditAdminInvited,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   actorID,
+		Details:   map[string]string{"invite_id": invite.ID.Hex(), "email": email},
+	})
+
+	return invite, plain, nil
+}
+
+// create an admin account with an invite token
+func (inviteUsc *adminInviteUseCase) AcceptInvite(ctx context.Context, token string, admin *domain.User) error {
+
+	// validate input
+	if token == "" {
+		return domain.ErrInvalidAdminInvite
+	}
+	if admin.Username == "" {
+		return domain.NewValidationError("username", "cannot be empty")
+	}
+	if len(admin.Password) < 8 {
+		return domain.NewValidationError("password", "must be at least 8 characters")
+	}
+
+	// check if user already exists before the invite is used up
+	if _, err := inviteUsc.userRepo.GetByUsername(ctx, admin.Username); err == nil {
+		return domain.ErrUserExists
+	} else if err != domain.ErrUserNotFound {
+		return err
+	}
+
+	invite, err := inviteUsc.inviteRepo.ConsumeInvite(ctx, hashToken(token), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	// invites naming an address are only valid for it
+	if invite.Email != "" {
+		if admin.Email == "" {
+			admin.Email = invite.Email
+		}
+		if admin.Email != invite.Email {
+			inviteUsc.restoreInvite(ctx, invite)
+			return domain.ErrInvalidAdminInvite
+		}
+	}
+
+	// hash password securely
+	hashed, err := inviteUsc.pwdService.HashPassword(admin.Password)
+	if err != nil {
+		inviteUsc.restoreInvite(ctx, invite)
+		return err
+	}
+	admin.Password = hashed
+	admin.Role = domain.RoleAdmin
+
+	if err := inviteUsc.userRepo.CreateUser(ctx, admin); err != nil {
+		inviteUsc.restoreInvite(ctx, invite)
+		return err
+	}
+
+	inviteUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserRegistered,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   admin.ID.Hex(),
+		Actor:     admin.Username,
+		Details:   map[string]string{"role": domain.RoleAdmin, "method": "invite", "invited_by": invite.CreatedBy},
+	})
+
+	return nil
+}
+
+// put back an invite whose account could not be created, so it can be used again
+func (inviteUsc *adminInviteUseCase) restoreInvite(ctx context.Context, invite *domain.AdminInvite) {
+	inviteUsc.inviteRepo.CreateInvite(context.WithoutCancel(ctx), invite)        // best effort, the admin can send a new invite
+}
@@ -0,0 +1,243 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// admin usecase (account and data maintenance for operators, used by the admin cli)
+type AdminUseCase interface {
+	CreateAdmin(ctx context.Context, admin *domain.User) error                          // create a new admin account
+	ResetPassword(ctx context.Context, username string, password string) error          // set a user's password and lift any login lockout
+	RevokeTokens(ctx context.Context, username string) (int64, error)                   // revoke a user's personal access tokens and pending reset links, returns tokens revoked
+	IndexTasks(ctx context.Context) (int64, error)                                      // put every stored task in the search index, returns tasks indexed
+	FindTasks(ctx context.Context, filter domain.TaskPurgeFilter) ([]domain.Task, error)        // tasks matching an operator filter
+	PurgeTasks(ctx context.Context, filter domain.TaskPurgeFilter) (int64, error)               // move tasks matching an operator filter with their subtasks to the trash, returns tasks deleted
+}
+
+type adminUseCase struct {
+	userRepo    domain.UserRepository
+	taskRepo    domain.TaskRepository
+	trashRepo   domain.TaskTrashRepository      // purged tasks are kept in the trash
+	tokenRepo   domain.PersonalAccessTokenRepository
+	resetRepo   domain.PasswordResetRepository
+	pwdService  domain.PasswordService
+	auditSink   domain.AuditSink
+	search      domain.SearchService
+	handlers    []domain.TaskEventHandler       // purged tasks are published like deleted tasks (search index, audit log, event log)
+}
+
+// creates new AdminUseCase instance
+func NewAdminUseCase(userRepo domain.UserRepository, taskRepo domain.TaskRepository, trashRepo domain.TaskTrashRepository, tokenRepo domain.PersonalAccessTokenRepository, resetRepo domain.PasswordResetRepository, pwdServ domain.PasswordService, auditSink domain.AuditSink, search domain.SearchService, handlers ...domain.TaskEventHandler) AdminUseCase {
+	return &adminUseCase{userRepo: userRepo, taskRepo: taskRepo, trashRepo: trashRepo, tokenRepo: tokenRepo, resetRepo: resetRepo, pwdService: pwdServ, auditSink: auditSink, search: search, handlers: handlers}
+}
+
+// create a new admin account
+func (adminUsc *adminUseCase) CreateAdmin(ctx context.Context, admin *domain.User) error {
+
+	// validate input
+	if admin.Username == "" {
+		return domain.NewValidationError("username", "cannot be empty")
+	}
+	if len(admin.Password) < 8 {
+		return domain.NewValidationError("password", "must be at least 8 characters")
+	}
+
+	// check if user already exists
+	if _, err := adminUsc.userRepo.GetByUsername(ctx, admin.Username); err == nil {
+		return domain.ErrUserExists
+	} else if err != domain.ErrUserNotFound {
+		return err
+	}
+
+	// hash password securely
+	hashed, err := adminUsc.pwdService.HashPassword(admin.Password)
+	if err != nil {
+		return err
+	}
+	admin.Password = hashed
+	admin.Role = domain.RoleAdmin
+
+	if err := adminUsc.userRepo.CreateUser(ctx, admin); err != nil {
+		return err
+	}
+
+	adminUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserRegistered,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   admin.ID.Hex(),
+		Actor:     admin.Username,
+		Details:   map[string]string{"role": domain.RoleAdmin, "method": "cli"},
+	})
+
+	return nil
+}
+
+// set a user's password and lift any login lockout
+func (adminUsc *adminUseCase) ResetPassword(ctx context.Context, username string, password string) error {
+
+	// validate input
+	if len(password) < 8 {
+		return domain.NewValidationError("password", "must be at least 8 characters")
+	}
+
+	user, err := adminUsc.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	// hash password securely
+	hashed, err := adminUsc.pwdService.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := adminUsc.userRepo.UpdatePassword(ctx, user.ID, hashed); err != nil {
+		return err
+	}
+	if err := adminUsc.userRepo.ResetFailedLogins(ctx, user.ID); err != nil {
+		return err
+	}
+
+	adminUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditPasswordReset,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   user.ID.Hex(),
+		Actor:     user.Username,
+		Details:   map[string]string{"method": "cli"},
+	})
+
+	return nil
+}
+
+// revoke a user's personal access tokens and pending reset links
+func (adminUsc *adminUseCase) RevokeTokens(ctx context.Context, username string) (int64, error) {
+
+	user, err := adminUsc.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, err := adminUsc.tokenRepo.RevokeUserTokens(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if err := adminUsc.resetRepo.DeleteUserTokens(ctx, user.ID); err != nil {
+		return revoked, err
+	}
+
+	adminUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditTokenRevoked,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   user.ID.Hex(),
+		Actor:     user.Username,
+		Details:   map[string]string{"method": "cli", "scope": "all"},
+	})
+
+	return revoked, nil
+}
+
+// fill the search index from the stored tasks (after switching backends or losing the index)
+func (adminUsc *adminUseCase) IndexTasks(ctx context.Context) (int64, error) {
+
+	var indexed int64
+	err := adminUsc.taskRepo.StreamTasks(ctx, domain.TaskQuery{}, func(task *domain.Task) error {
+		if err := adminUsc.search.IndexTask(ctx, task); err != nil {
+			return err
+		}
+		indexed++
+		return nil
+	})
+
+	return indexed, err
+}
+
+// tasks matching an operator filter, in stored order
+func (adminUsc *adminUseCase) FindTasks(ctx context.Context, filter domain.TaskPurgeFilter) ([]domain.Task, error) {
+
+	if filter.Status != "" && !domain.IsTaskStatus(filter.Status) {
+		return nil, domain.NewValidationError("status", "must be one of: "+strings.Join(domain.TaskStatuses, ", "))
+	}
+
+	tasks := []domain.Task{}
+	err := adminUsc.taskRepo.StreamTasks(ctx, domain.TaskQuery{TenantID: filter.TenantID}, func(task *domain.Task) error {
+		if filter.Matches(task) {
+			tasks = append(tasks, *task)
+		}
+		return nil
+	})
+
+	return tasks, err
+}
+
+// move tasks matching an operator filter to the trash, subtasks go with their parents like a cascading delete
+func (adminUsc *adminUseCase) PurgeTasks(ctx context.Context, filter domain.TaskPurgeFilter) (int64, error) {
+
+	if !filter.Narrowed() {
+		return 0, domain.ErrTaskPurgeUnfiltered
+	}
+	tasks, err := adminUsc.FindTasks(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	var ids []primitive.ObjectID
+	for _, task := range tasks {
+		descendants, err := adminUsc.taskRepo.GetDescendantIDs(ctx, task.ID.Hex())
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range append([]primitive.ObjectID{task.ID}, descendants...) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	matched, err := adminUsc.taskRepo.GetAllTasks(ctx, domain.TaskQuery{IDs: ids})
+	if err != nil {
+		return 0, err
+	}
+	if err := trashTasks(ctx, adminUsc.trashRepo, matched); err != nil {
+		return 0, err
+	}
+	if err := adminUsc.taskRepo.DeleteTasks(ctx, ids); err != nil {
+		return 0, err
+	}
+	for i := range matched {
+		event := domain.TaskEvent{Type: domain.TaskEventDeleted, TaskID: matched[i].ID.Hex(), Before: &matched[i], Details: map[string]string{"trashed": "true"}}
+		for _, handler := range adminUsc.handlers {
+			handler.HandleTaskEvent(ctx, event)
+		}
+	}
+
+	details := map[string]string{"method": "cli", "count": strconv.Itoa(len(ids))}
+	if filter.Status != "" {
+		details["status"] = filter.Status
+	}
+	if filter.DueBefore != nil {
+		details["due_before"] = filter.DueBefore.Format(time.RFC3339)
+	}
+	if filter.UpdatedBefore != nil {
+		details["updated_before"] = filter.UpdatedBefore.Format(time.RFC3339)
+	}
+	if filter.TenantID != nil {
+		details["tenant_id"] = *filter.TenantID
+	}
+	adminUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditTasksPurged,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Details:   details,
+	})
+
+	return int64(len(ids)), nil
+}
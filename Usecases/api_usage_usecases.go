@@ -0,0 +1,106 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// default and maximum number of entries in the admin rollup
+const (
+	defaultUsageRollupLimit = 100
+	maxUsageRollupLimit     = 1000
+)
+
+// api usage usecase
+type APIUsageUseCase interface {
+	GetTokenUsage(ctx context.Context, userID string, tokenID string) ([]domain.APIUsage, error)       // per-endpoint usage of one of the user's tokens
+	GetUsageRollup(ctx context.Context, limit int64) ([]domain.APIUsageRollup, error)                  // usage per user, token and client (admin)
+	GetDeprecatedUsage(ctx context.Context) ([]domain.DeprecatedEndpointUsage, error)                  // who still calls each deprecated endpoint (admin)
+}
+
+type apiUsageUseCase struct {
+	usageRepo     domain.APIUsageRepository
+	tokenRepo     domain.PersonalAccessTokenRepository
+	deprecations  domain.DeprecationRegistry
+}
+
+// creates new APIUsageUseCase instance
+func NewAPIUsageUseCase(usageRepo domain.APIUsageRepository, tokenRepo domain.PersonalAccessTokenRepository, deprecations domain.DeprecationRegistry) APIUsageUseCase {
+	return &apiUsageUseCase{usageRepo: usageRepo, tokenRepo: tokenRepo, deprecations: deprecations}
+}
+
+// per-endpoint usage of a token (only its owner can see it)
+func (usageUsc *apiUsageUseCase) GetTokenUsage(ctx context.Context, userID string, tokenID string) ([]domain.APIUsage, error) {
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	tokenObjID, err := primitive.ObjectIDFromHex(tokenID)      // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidTokenID
+	}
+
+	// other users' tokens look the same as missing ones
+	tokens, err := usageUsc.tokenRepo.GetTokensByUser(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.ID == tokenObjID {
+			return usageUsc.usageRepo.GetTokenUsage(ctx, tokenID)
+		}
+	}
+
+	return nil, domain.ErrTokenNotFound
+}
+
+// usage per user, token and client, busiest first
+func (usageUsc *apiUsageUseCase) GetUsageRollup(ctx context.Context, limit int64) ([]domain.APIUsageRollup, error) {
+
+	if limit <= 0 {
+		limit = defaultUsageRollupLimit
+	}
+	if limit > maxUsageRollupLimit {
+		limit = maxUsageRollupLimit
+	}
+
+	return usageUsc.usageRepo.GetUsageRollup(ctx, limit)
+}
+
+// who still calls each deprecated endpoint (endpoints without calls are listed with no callers)
+func (usageUsc *apiUsageUseCase) GetDeprecatedUsage(ctx context.Context) ([]domain.DeprecatedEndpointUsage, error) {
+
+	deprecations := usageUsc.deprecations.Deprecations()
+	report := make([]domain.DeprecatedEndpointUsage, 0, len(deprecations))
+	if len(deprecations) == 0 {
+		return report, nil
+	}
+
+	endpoints := make([]string, 0, len(deprecations))
+	for _, deprecation := range deprecations {
+		endpoints = append(endpoints, deprecation.Endpoint)
+	}
+	usage, err := usageUsc.usageRepo.GetEndpointUsage(ctx, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// group callers by endpoint, keeping the most recent first
+	callers := map[string][]domain.APIUsage{}
+	for _, u := range usage {
+		callers[u.Endpoint] = append(callers[u.Endpoint], u)
+	}
+	for _, deprecation := range deprecations {
+		entry := domain.DeprecatedEndpointUsage{Deprecation: deprecation, Callers: []domain.APIUsage{}}
+		for _, u := range callers[deprecation.Endpoint] {
+			entry.Calls += u.Count
+			entry.Callers = append(entry.Callers, u)
+		}
+		report = append(report, entry)
+	}
+
+	return report, nil
+}
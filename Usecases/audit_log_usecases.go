@@ -0,0 +1,81 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"encoding/json";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// default and maximum number of entries returned by one query
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 1000
+)
+
+// audit log usecase
+type AuditLogUseCase interface {
+	GetEntries(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLogEntry, error)       // query audit log (admin)
+}
+
+type auditLogUseCase struct {
+	auditLogRepo domain.AuditLogRepository
+}
+
+// creates new AuditLogUseCase instance
+func NewAuditLogUseCase(repo domain.AuditLogRepository) AuditLogUseCase {
+	return &auditLogUseCase{auditLogRepo: repo}
+}
+
+// query audit log entries
+func (auditLogUsc *auditLogUseCase) GetEntries(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLogEntry, error) {
+
+	// validate filter
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, domain.ErrInvalidAuditFilter
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+	if filter.Limit > maxAuditLogLimit {
+		filter.Limit = maxAuditLogLimit
+	}
+
+	return auditLogUsc.auditLogRepo.GetEntries(ctx, filter)
+}
+
+// record a change made by the actor on the request context
+// the change already happened, so a failed write is logged instead of failing the request
+func recordAuditLog(ctx context.Context, repo domain.AuditLogRepository, logger domain.Logger, entry domain.AuditLogEntry) {
+	if err := writeAuditLog(ctx, repo, entry); err != nil {
+		logger.Error(ctx, "audit log write failed", "action", entry.Action, "entity_type", entry.EntityType, "entity_id", entry.EntityID, "error", err)
+	}
+}
+
+// write an audit log entry for the actor on the request context (inside a unit of work, a failed write rolls back the change)
+func writeAuditLog(ctx context.Context, repo domain.AuditLogRepository, entry domain.AuditLogEntry) error {
+
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		entry.ActorID = actor.ID
+		entry.Actor = actor.Username
+	}
+	entry.Timestamp = time.Now().UTC()
+
+	return repo.RecordEntry(ctx, &entry)
+}
+
+// snapshot of an entity as it is shown to clients (json field names, hidden fields left out)
+func auditSnapshot(entity interface{}) map[string]interface{} {
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+
+	return snapshot
+}
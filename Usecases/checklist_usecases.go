@@ -0,0 +1,139 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"strings";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// checklist usecase (ordered steps of a task, items are addressed by their index)
+type ChecklistUseCase interface {
+	AddChecklistItem(ctx context.Context, taskID string, input *domain.ChecklistItemInput) (*domain.Task, error)      // add item at the end or at a position
+	ToggleChecklistItem(ctx context.Context, taskID string, index int) (*domain.Task, error)                          // tick off an item or undo it
+	ReorderChecklist(ctx context.Context, taskID string, order []int) (*domain.Task, error)                           // put items in a new order
+	DeleteChecklistItem(ctx context.Context, taskID string, index int) (*domain.Task, error)                          // remove an item
+}
+
+type checklistUseCase struct {
+	taskCommands  TaskCommandUseCase        // checklist changes are task updates (locks, history and events apply)
+	taskQuery     TaskQueryUseCase          // only tasks the caller can see are changed
+}
+
+// creates new ChecklistUseCase instance
+func NewChecklistUseCase(taskCommands TaskCommandUseCase, taskQuery TaskQueryUseCase) ChecklistUseCase {
+	return &checklistUseCase{taskCommands: taskCommands, taskQuery: taskQuery}
+}
+
+// add an item to the checklist of a task
+func (checklistUsc *checklistUseCase) AddChecklistItem(ctx context.Context, taskID string, input *domain.ChecklistItemInput) (*domain.Task, error) {
+
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return nil, domain.NewValidationError("text", "cannot be empty")
+	}
+
+	return checklistUsc.changeChecklist(ctx, taskID, func(items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		if len(items) >= domain.MaxChecklistItems {
+			return nil, domain.NewValidationError("checklist", "cannot have more than "+strconv.Itoa(domain.MaxChecklistItems)+" items")
+		}
+		item := domain.ChecklistItem{Text: text}
+		if input.Position == nil || *input.Position >= len(items) {
+			return append(items, item), nil
+		}
+		position := *input.Position
+		items = append(items, domain.ChecklistItem{})
+		copy(items[position+1:], items[position:])
+		items[position] = item
+		return items, nil
+	})
+}
+
+// flip the done state of an item
+func (checklistUsc *checklistUseCase) ToggleChecklistItem(ctx context.Context, taskID string, index int) (*domain.Task, error) {
+	return checklistUsc.changeChecklist(ctx, taskID, func(items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		if index < 0 || index >= len(items) {
+			return nil, domain.ErrChecklistItemNotFound
+		}
+		items[index].Done = !items[index].Done
+		return items, nil
+	})
+}
+
+// order the items by their current indexes
+func (checklistUsc *checklistUseCase) ReorderChecklist(ctx context.Context, taskID string, order []int) (*domain.Task, error) {
+	return checklistUsc.changeChecklist(ctx, taskID, func(items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+
+		// every index once, so no item is lost or copied
+		if len(order) != len(items) {
+			return nil, domain.ErrInvalidChecklistOrder
+		}
+		seen := make([]bool, len(items))
+		reordered := make([]domain.ChecklistItem, 0, len(items))
+		for _, index := range order {
+			if index < 0 || index >= len(items) || seen[index] {
+				return nil, domain.ErrInvalidChecklistOrder
+			}
+			seen[index] = true
+			reordered = append(reordered, items[index])
+		}
+		return reordered, nil
+	})
+}
+
+// remove an item from the checklist
+func (checklistUsc *checklistUseCase) DeleteChecklistItem(ctx context.Context, taskID string, index int) (*domain.Task, error) {
+	return checklistUsc.changeChecklist(ctx, taskID, func(items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		if index < 0 || index >= len(items) {
+			return nil, domain.ErrChecklistItemNotFound
+		}
+		return append(items[:index], items[index+1:]...), nil
+	})
+}
+
+// apply a change to a copy of the checklist and store it, unless the task changed in between
+func (checklistUsc *checklistUseCase) changeChecklist(ctx context.Context, taskID string, change func(items []domain.ChecklistItem) ([]domain.ChecklistItem, error)) (*domain.Task, error) {
+
+	task, err := checklistUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := change(append([]domain.ChecklistItem{}, task.Checklist...))
+	if err != nil {
+		return nil, err
+	}
+
+	// the change is based on the checklist read above, a concurrent change is refused instead of lost
+	expected := task.UpdatedAt
+	updated, _, err := checklistUsc.taskCommands.UpdateTask(ctx, taskID, &domain.TaskUpdate{Checklist: &items, ExpectedUpdatedAt: &expected})
+	if err != nil {
+		return nil, err
+	}
+	updated.Progress = checklistProgress(updated.Checklist)
+
+	return updated, nil
+}
+
+// done items of a checklist, nil without items
+func checklistProgress(items []domain.ChecklistItem) *domain.ChecklistProgress {
+	if len(items) == 0 {
+		return nil
+	}
+	progress := &domain.ChecklistProgress{Total: len(items)}
+	for _, item := range items {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	progress.Percent = progress.Done * 100 / progress.Total
+	return progress
+}
+
+// set the checklist progress of listed tasks
+func setChecklistProgress(tasks []domain.Task) {
+	for i := range tasks {
+		tasks[i].Progress = checklistProgress(tasks[i].Checklist)
+	}
+}
@@ -0,0 +1,260 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"sort";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// day plan scoring weights (priority rank is multiplied by dayPlanPriorityWeight)
+const (
+	dayPlanPriorityWeight = 10       // urgent 40, high 30, medium 20, low 10
+	dayPlanOverdueScore   = 50       // overdue tasks come before anything due later
+	dayPlanDueTodayScore  = 30       // due today, minus one point per hour left
+	dayPlanStartedScore   = 15       // finish what's started
+)
+
+// day plan usecase (proposes an order of today's tasks and keeps the accepted one as my day)
+type DayPlanUseCase interface {
+	PlanDay(ctx context.Context, req domain.PlanDayRequest) (*domain.DayPlan, error)                          // propose an order of today's tasks within working hours
+	SetMyDay(ctx context.Context, userID string, req domain.SetMyDayRequest) (*domain.MyDayTasks, error)      // keep the chosen order of today's tasks
+	GetMyDay(ctx context.Context, userID string) (*domain.MyDayTasks, error)                                  // today's tasks in the chosen order
+}
+
+type dayPlanUseCase struct {
+	taskReader       domain.TaskReader
+	myDayRepo        domain.MyDayRepository
+	hours            domain.WorkingHours
+	defaultEstimate  time.Duration
+}
+
+// creates new DayPlanUseCase instance (hours and estimate apply when the request and task don't set them)
+func NewDayPlanUseCase(reader domain.TaskReader, myDayRepo domain.MyDayRepository, hours domain.WorkingHours, defaultEstimate time.Duration) DayPlanUseCase {
+	return &dayPlanUseCase{taskReader: reader, myDayRepo: myDayRepo, hours: hours, defaultEstimate: defaultEstimate}
+}
+
+// task with its planning score
+type scoredTask struct {
+	task     domain.Task
+	score    int
+	reasons  []string
+}
+
+// propose an order of today's tasks within working hours
+func (dayPlanUsc *dayPlanUseCase) PlanDay(ctx context.Context, req domain.PlanDayRequest) (*domain.DayPlan, error) {
+
+	// working hours of the request, server defaults for what it leaves out
+	hours := dayPlanUsc.hours
+	var err error
+	if req.WorkStart != "" {
+		if hours.Start, err = domain.ParseClock(req.WorkStart); err != nil {
+			return nil, err
+		}
+	}
+	if req.WorkEnd != "" {
+		if hours.End, err = domain.ParseClock(req.WorkEnd); err != nil {
+			return nil, err
+		}
+	}
+	if hours.End <= hours.Start {
+		return nil, domain.ErrInvalidWorkingHours
+	}
+	location, err := dayLocation(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(location)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	plan := &domain.DayPlan{
+		Date:       dayStart.Format("2006-01-02"),
+		Timezone:   location.String(),
+		WorkStart:  dayStart.Add(hours.Start),
+		WorkEnd:    dayStart.Add(hours.End),
+		Tasks:      []domain.PlannedTask{},
+		Deferred:   []domain.PlannedTask{},
+	}
+
+	// today's tasks: unfinished and due before the day ends, or already started
+	candidates := []scoredTask{}
+	err = dayPlanUsc.taskReader.StreamTasks(ctx, domain.TaskQuery{}, func(task *domain.Task) error {
+		if task.Status == "completed" || (task.DueDate.After(dayEnd) && task.Status != "in_progress") {
+			return nil
+		}
+		candidates = append(candidates, scoreDayTask(*task, now, dayEnd, location))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// highest score first, earlier due date breaks ties
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if !candidates[i].task.DueDate.Equal(candidates[j].task.DueDate) {
+			return candidates[i].task.DueDate.Before(candidates[j].task.DueDate)
+		}
+		return candidates[i].task.ID.Hex() < candidates[j].task.ID.Hex()
+	})
+
+	// fill the remaining working time in order, tasks that don't fit are deferred (later shorter ones may still fit)
+	cursor := plan.WorkStart
+	if now.After(cursor) {
+		cursor = now.Truncate(time.Minute)
+	}
+	if cursor.Before(plan.WorkEnd) {
+		plan.AvailableMinutes = int(plan.WorkEnd.Sub(cursor) / time.Minute)
+	}
+	for _, candidate := range candidates {
+		planned := domain.PlannedTask{
+			TaskID:    candidate.task.ID,
+			Title:     candidate.task.Title,
+			Priority:  candidate.task.Priority,
+			DueDate:   candidate.task.DueDate,
+			Estimate:  candidate.task.Estimate,
+			Reasons:   candidate.reasons,
+		}
+		if planned.Estimate == 0 {
+			planned.Estimate = int(dayPlanUsc.defaultEstimate / time.Minute)
+			planned.EstimateDefaulted = true
+		}
+
+		end := cursor.Add(time.Duration(planned.Estimate) * time.Minute)
+		if end.After(plan.WorkEnd) {
+			planned.Reasons = append(planned.Reasons, "doesn't fit in the remaining working hours")
+			plan.Deferred = append(plan.Deferred, planned)
+			continue
+		}
+
+		start := cursor
+		planned.Start, planned.End = &start, &end
+		if end.After(candidate.task.DueDate) {
+			planned.AtRisk = true
+			planned.Reasons = append(planned.Reasons, "planned to end after its due date")
+		}
+		plan.Tasks = append(plan.Tasks, planned)
+		plan.PlannedMinutes += planned.Estimate
+		cursor = end
+	}
+
+	return plan, nil
+}
+
+// keep the chosen order of today's tasks
+func (dayPlanUsc *dayPlanUseCase) SetMyDay(ctx context.Context, userID string, req domain.SetMyDayRequest) (*domain.MyDayTasks, error) {
+
+	location, err := dayLocation(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	// every task must exist and appear once
+	seen := map[string]bool{}
+	day := &domain.MyDay{UserID: userID, Timezone: location.String(), TaskIDs: []primitive.ObjectID{}, UpdatedAt: time.Now().UTC()}
+	day.Date = day.UpdatedAt.In(location).Format("2006-01-02")
+	tasks := make([]domain.Task, 0, len(req.TaskIDs))
+	for _, taskID := range req.TaskIDs {
+		if seen[taskID] {
+			return nil, domain.ErrDuplicateDayTask
+		}
+		seen[taskID] = true
+
+		task, err := dayPlanUsc.taskReader.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		day.TaskIDs = append(day.TaskIDs, task.ID)
+		tasks = append(tasks, *task)
+	}
+
+	if err := dayPlanUsc.myDayRepo.SaveMyDay(ctx, day); err != nil {
+		return nil, err
+	}
+
+	return &domain.MyDayTasks{Date: day.Date, Timezone: day.Timezone, Tasks: tasks}, nil
+}
+
+// today's tasks in the chosen order (empty when nothing was chosen for today)
+func (dayPlanUsc *dayPlanUseCase) GetMyDay(ctx context.Context, userID string) (*domain.MyDayTasks, error) {
+
+	day, err := dayPlanUsc.myDayRepo.GetMyDay(ctx, userID)
+	if err != nil && err != domain.ErrMyDayNotFound {
+		return nil, err
+	}
+	if day == nil {
+		day = &domain.MyDay{Timezone: time.UTC.String()}
+	}
+
+	location, err := dayLocation(day.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().In(location).Format("2006-01-02")
+	result := &domain.MyDayTasks{Date: today, Timezone: location.String(), Tasks: []domain.Task{}}
+	if day.Date != today {
+		return result, nil        // yesterday's order doesn't carry over
+	}
+
+	for _, taskID := range day.TaskIDs {
+		task, err := dayPlanUsc.taskReader.GetTaskByID(ctx, taskID.Hex())
+		if err == domain.ErrTaskNotFound {
+			continue        // deleted since it was chosen
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Tasks = append(result.Tasks, *task)
+	}
+
+	return result, nil
+}
+
+// how urgent a task is today and why
+func scoreDayTask(task domain.Task, now time.Time, dayEnd time.Time, location *time.Location) scoredTask {
+
+	scored := scoredTask{task: task, reasons: []string{}}
+
+	rank, ok := domain.TaskPriorities[task.Priority]
+	if !ok {
+		rank = domain.TaskPriorities[domain.DefaultTaskPriority]
+	}
+	scored.score = rank * dayPlanPriorityWeight
+	if rank >= domain.TaskPriorities["high"] {
+		scored.reasons = append(scored.reasons, task.Priority+" priority")
+	}
+
+	switch {
+	case task.DueDate.Before(now):
+		scored.score += dayPlanOverdueScore
+		scored.reasons = append(scored.reasons, "overdue since "+task.DueDate.In(location).Format("2006-01-02 15:04"))
+	case task.DueDate.Before(dayEnd):
+		hoursLeft := int(task.DueDate.Sub(now) / time.Hour)
+		scored.score += max(dayPlanDueTodayScore-hoursLeft, 0)
+		scored.reasons = append(scored.reasons, "due today at "+task.DueDate.In(location).Format("15:04"))
+	}
+
+	if task.Status == "in_progress" {
+		scored.score += dayPlanStartedScore
+		scored.reasons = append(scored.reasons, "already in progress")
+	}
+
+	return scored
+}
+
+// location of a timezone name (UTC when empty)
+func dayLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, domain.ErrInvalidTimezone
+	}
+	return location, nil
+}
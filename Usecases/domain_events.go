@@ -0,0 +1,61 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// publish a change made by the actor on the request context
+func publishEvent(ctx context.Context, publisher domain.EventPublisher, eventType string, entityType string, entityID string, data interface{}) {
+	publisher.Publish(ctx, newDomainEvent(ctx, eventType, entityType, entityID, data))
+}
+
+// domain event made by the request's actor, in the actor's organization
+func newDomainEvent(ctx context.Context, eventType string, entityType string, entityID string, data interface{}) domain.DomainEvent {
+
+	event := domain.DomainEvent{
+		ID:          primitive.NewObjectID().Hex(),
+		Type:        eventType,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Data:        data,
+		OccurredAt:  time.Now().UTC(),
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID = actor.ID
+		event.TenantID = actor.TenantID
+	}
+
+	return event
+}
+
+// publishes task events as domain events
+type domainEventTaskEventHandler struct {
+	publisher  domain.EventPublisher
+}
+
+// creates task event handler forwarding to an event publisher
+func NewDomainEventTaskEventHandler(publisher domain.EventPublisher) domain.TaskEventHandler {
+	return &domainEventTaskEventHandler{publisher: publisher}
+}
+
+func (handler *domainEventTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	var data interface{}
+	if event.After != nil {
+		data = event.After
+	}
+
+	// tasks changed by background jobs or admins belong to the task's organization
+	published := newDomainEvent(ctx, event.Type, domain.AuditEntityTask, event.TaskID, data)
+	if event.After != nil {
+		published.TenantID = event.After.TenantID
+	} else if event.Before != nil {
+		published.TenantID = event.Before.TenantID
+	}
+
+	handler.publisher.Publish(ctx, published)
+}
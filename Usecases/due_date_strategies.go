@@ -0,0 +1,231 @@
+package usecases
+
+// imports
+import (
+	"fmt";
+	"sort";
+	"strings";
+	"time";
+	"unicode";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const (
+	defaultDueDateLead   = 3 * 24 * time.Hour      // lead time when nothing better is known
+	maxCompletionTime    = 365 * 24 * time.Hour    // longer completions are treated as abandoned tasks
+	similarTitleMinScore = 0.3                     // shared words (jaccard) for titles to count as similar
+	workloadMaxShift     = 14                      // days a suggestion may be moved by the workload
+)
+
+// strategies by configured name in the order given (e.g. similar_tasks,user_pace,default,workload)
+func NewDueDateStrategies(names []string, maxTasksPerDay int) ([]domain.DueDateStrategy, error) {
+
+	strategies := []domain.DueDateStrategy{}
+	for _, name := range names {
+		switch name {
+		case "similar_tasks":
+			strategies = append(strategies, NewSimilarTasksStrategy(similarTitleMinScore))
+		case "user_pace":
+			strategies = append(strategies, NewUserPaceStrategy())
+		case "default":
+			strategies = append(strategies, NewDefaultLeadStrategy(defaultDueDateLead))
+		case "workload":
+			strategies = append(strategies, NewWorkloadStrategy(maxTasksPerDay))
+		default:
+			return nil, fmt.Errorf("unknown due date strategy %q", name)
+		}
+	}
+
+	return strategies, nil
+}
+
+// typical completion time of tasks with similar titles
+type similarTasksStrategy struct {
+	minScore float64
+}
+
+func NewSimilarTasksStrategy(minScore float64) domain.DueDateStrategy {
+	return &similarTasksStrategy{minScore: minScore}
+}
+
+func (strategy *similarTasksStrategy) Name() string {
+	return "similar_tasks"
+}
+
+func (strategy *similarTasksStrategy) Apply(input *domain.DueDateInput, suggestion *domain.DueDateSuggestion) string {
+
+	if !suggestion.DueDate.IsZero() {
+		return ""
+	}
+
+	words := titleWords(input.Title)
+	durations := []time.Duration{}
+	for _, completion := range input.Completions {
+		if titleSimilarity(words, titleWords(completion.Title)) >= strategy.minScore {
+			if took, ok := completionTime(completion); ok {
+				durations = append(durations, took)
+			}
+		}
+	}
+	if len(durations) == 0 {
+		return ""
+	}
+
+	typical := medianDuration(durations)
+	suggestion.DueDate = input.Now.Add(typical)
+	suggestion.BasedOn = len(durations)
+	suggestion.Confidence = domain.ConfidenceMedium
+	if len(durations) >= 3 {
+		suggestion.Confidence = domain.ConfidenceHigh
+	}
+
+	return fmt.Sprintf("%d similar completed tasks took %s (median)", len(durations), formatLead(typical))
+}
+
+// typical completion time of any task the user finished
+type userPaceStrategy struct{}
+
+func NewUserPaceStrategy() domain.DueDateStrategy {
+	return &userPaceStrategy{}
+}
+
+func (strategy *userPaceStrategy) Name() string {
+	return "user_pace"
+}
+
+func (strategy *userPaceStrategy) Apply(input *domain.DueDateInput, suggestion *domain.DueDateSuggestion) string {
+
+	if !suggestion.DueDate.IsZero() {
+		return ""
+	}
+
+	durations := []time.Duration{}
+	for _, completion := range input.Completions {
+		if took, ok := completionTime(completion); ok {
+			durations = append(durations, took)
+		}
+	}
+	if len(durations) == 0 {
+		return ""
+	}
+
+	typical := medianDuration(durations)
+	suggestion.DueDate = input.Now.Add(typical)
+	suggestion.BasedOn = len(durations)
+	suggestion.Confidence = domain.ConfidenceLow
+
+	return fmt.Sprintf("your %d completed tasks took %s (median)", len(durations), formatLead(typical))
+}
+
+// fixed lead time when no other strategy had anything to go on
+type defaultLeadStrategy struct {
+	lead time.Duration
+}
+
+func NewDefaultLeadStrategy(lead time.Duration) domain.DueDateStrategy {
+	return &defaultLeadStrategy{lead: lead}
+}
+
+func (strategy *defaultLeadStrategy) Name() string {
+	return "default"
+}
+
+func (strategy *defaultLeadStrategy) Apply(input *domain.DueDateInput, suggestion *domain.DueDateSuggestion) string {
+
+	if !suggestion.DueDate.IsZero() {
+		return ""
+	}
+
+	suggestion.DueDate = input.Now.Add(strategy.lead)
+	suggestion.Confidence = domain.ConfidenceLow
+
+	return fmt.Sprintf("no completed tasks to learn from, %s from now", formatLead(strategy.lead))
+}
+
+// move the suggestion past days that already have many tasks due
+type workloadStrategy struct {
+	maxPerDay int
+}
+
+func NewWorkloadStrategy(maxPerDay int) domain.DueDateStrategy {
+	return &workloadStrategy{maxPerDay: maxPerDay}
+}
+
+func (strategy *workloadStrategy) Name() string {
+	return "workload"
+}
+
+func (strategy *workloadStrategy) Apply(input *domain.DueDateInput, suggestion *domain.DueDateSuggestion) string {
+
+	if suggestion.DueDate.IsZero() || strategy.maxPerDay <= 0 {
+		return ""
+	}
+
+	dueOn := map[time.Time]int{}
+	for _, due := range input.OpenDueDates {
+		dueOn[due.UTC().Truncate(24*time.Hour)]++
+	}
+
+	day := suggestion.DueDate.UTC().Truncate(24 * time.Hour)
+	busy := dueOn[day]
+	shift := 0
+	for dueOn[day] >= strategy.maxPerDay && shift < workloadMaxShift {
+		day = day.AddDate(0, 0, 1)
+		shift++
+	}
+	if shift == 0 {
+		return ""
+	}
+
+	suggestion.DueDate = suggestion.DueDate.AddDate(0, 0, shift)
+	return fmt.Sprintf("moved %d day(s) later, %d open tasks are already due that day", shift, busy)
+}
+
+// time from creation to completion (false for unusable records)
+func completionTime(completion domain.TaskCompletion) (time.Duration, bool) {
+	took := completion.CompletedAt.Sub(completion.CreatedAt)
+	return took, took > 0 && took <= maxCompletionTime
+}
+
+func medianDuration(durations []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// lowercase words of a title (short words like "a" or "to" carry no meaning)
+func titleWords(title string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if len(word) > 2 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// shared words over all words of two titles (0 when nothing is shared)
+func titleSimilarity(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// duration as days or hours for reasons
+func formatLead(lead time.Duration) string {
+	if lead >= 24*time.Hour {
+		return fmt.Sprintf("%.1f days", lead.Hours()/24)
+	}
+	return fmt.Sprintf("%.0f hours", lead.Hours())
+}
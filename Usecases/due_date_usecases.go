@@ -0,0 +1,79 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const (
+	dueDateHistoryLimit = 200                // completed tasks looked at per suggestion
+	dueDateEndOfDay     = 17 * time.Hour     // suggested due dates fall at the end of a working day (UTC)
+)
+
+// due date suggestion usecase (heuristics over the user's history and the current workload)
+type DueDateUseCase interface {
+	SuggestDueDate(ctx context.Context, userID string, title string) (*domain.DueDateSuggestion, error)        // suggest a due date for a new task
+}
+
+type dueDateUseCase struct {
+	changeRepo  domain.TaskChangeRepository
+	taskReader  domain.TaskReader
+	strategies  []domain.DueDateStrategy
+}
+
+// creates new DueDateUseCase instance (strategies run in the order given)
+func NewDueDateUseCase(changeRepo domain.TaskChangeRepository, reader domain.TaskReader, strategies ...domain.DueDateStrategy) DueDateUseCase {
+	return &dueDateUseCase{changeRepo: changeRepo, taskReader: reader, strategies: strategies}
+}
+
+// suggest a due date for a new task
+func (dueDateUsc *dueDateUseCase) SuggestDueDate(ctx context.Context, userID string, title string) (*domain.DueDateSuggestion, error) {
+
+	// validate input
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, domain.NewValidationError("title", "cannot be empty")
+	}
+
+	input := &domain.DueDateInput{Title: title, Now: time.Now().UTC()}
+
+	completions, err := dueDateUsc.changeRepo.GetCompletions(ctx, userID, dueDateHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	input.Completions = completions
+
+	// workload: unfinished tasks due from now on
+	err = dueDateUsc.taskReader.StreamTasks(ctx, domain.TaskQuery{}, func(task *domain.Task) error {
+		if task.Status != "completed" && task.DueDate.After(input.Now) {
+			input.OpenDueDates = append(input.OpenDueDates, task.DueDate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := &domain.DueDateSuggestion{Reasons: []string{}}
+	for _, strategy := range dueDateUsc.strategies {
+		if reason := strategy.Apply(input, suggestion); reason != "" {
+			suggestion.Reasons = append(suggestion.Reasons, strategy.Name()+": "+reason)
+		}
+	}
+	if suggestion.DueDate.IsZero() {
+		suggestion.DueDate = input.Now.Add(defaultDueDateLead)
+		suggestion.Confidence = domain.ConfidenceLow
+	}
+
+	// end of the suggested day, never in the past
+	day := suggestion.DueDate.UTC().Truncate(24 * time.Hour)
+	suggestion.DueDate = day.Add(dueDateEndOfDay)
+	if !suggestion.DueDate.After(input.Now) {
+		suggestion.DueDate = suggestion.DueDate.AddDate(0, 0, 1)
+	}
+
+	return suggestion, nil
+}
@@ -0,0 +1,143 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// email verification usecase
+type EmailVerificationUseCase interface {
+	domain.EmailVerifier
+	VerifyEmail(ctx context.Context, token string) error                  // confirm the email address a token was sent to, invalidating all verification tokens of the user
+	ResendVerification(ctx context.Context, email string) error           // email a new verification link (silently does nothing for unknown or verified addresses)
+}
+
+type emailVerificationUseCase struct {
+	userRepo          domain.UserRepository
+	verificationRepo  domain.EmailVerificationRepository
+	emailServ         domain.EmailService
+	auditSink         domain.AuditSink
+	logger            domain.Logger
+	tokenTTL          time.Duration      // how long a verification token stays valid
+	verifyURL         string             // link sent in the email, the token is appended
+}
+
+// creates new EmailVerificationUseCase instance
+func NewEmailVerificationUseCase(userRepo domain.UserRepository, verificationRepo domain.EmailVerificationRepository, emailServ domain.EmailService, auditSink domain.AuditSink, logger domain.Logger, tokenTTL time.Duration, verifyURL string) EmailVerificationUseCase {
+	return &emailVerificationUseCase{userRepo: userRepo, verificationRepo: verificationRepo, emailServ: emailServ, auditSink: auditSink, logger: logger, tokenTTL: tokenTTL, verifyURL: verifyURL}
+}
+
+// generate verification token and email it to the user's address
+func (verificationUsc *emailVerificationUseCase) SendVerification(ctx context.Context, user *domain.User) error {
+
+	// validate input
+	if user.Email == "" {
+		return errors.New("email cannot be empty")
+	}
+
+	// only the newest token is valid
+	if err := verificationUsc.verificationRepo.DeleteUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// generate token, only its hash is stored
+	plain, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	token := &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(plain),
+		ExpiresAt: now.Add(verificationUsc.tokenTTL),
+		CreatedAt: now,
+	}
+	if err := verificationUsc.verificationRepo.CreateToken(ctx, token); err != nil {
+		return err
+	}
+
+	body := "Hi " + user.Username + ",\n\n" +
+		"Use the link below to confirm your email address. It expires at " + token.ExpiresAt.Format(time.RFC1123) + ".\n\n" +
+		verificationUsc.verifyURL + plain + "\n\n" +
+		"If you didn't create an account you can ignore this email."
+	if err := verificationUsc.emailServ.SendEmail(ctx, user.Email, "Confirm your email address", body); err != nil {
+		verificationUsc.logger.Error(ctx, "email verification email failed", "user_id", user.ID.Hex(), "error", err)
+		return err
+	}
+
+	verificationUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditEmailVerificationSent,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  user.ID.Hex(),
+	})
+
+	return nil
+}
+
+// confirm email address using a verification token
+func (verificationUsc *emailVerificationUseCase) VerifyEmail(ctx context.Context, token string) error {
+
+	// validate input
+	if token == "" {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	// expired tokens may still exist until the ttl index removes them
+	verificationToken, err := verificationUsc.verificationRepo.GetTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if time.Now().After(verificationToken.ExpiresAt) {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	// the address may have changed since the link was sent
+	err = verificationUsc.userRepo.MarkEmailVerified(ctx, verificationToken.UserID, verificationToken.Email, time.Now().UTC())
+	if err == domain.ErrUserNotFound {
+		return domain.ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+
+	// tokens are single use
+	if err := verificationUsc.verificationRepo.DeleteUserTokens(ctx, verificationToken.UserID); err != nil {
+		return err
+	}
+
+	verificationUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditEmailVerified,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   verificationToken.UserID.Hex(),
+		Details:   map[string]string{"email": verificationToken.Email},
+	})
+
+	return nil
+}
+
+// send a new verification link to an account still waiting for verification
+func (verificationUsc *emailVerificationUseCase) ResendVerification(ctx context.Context, email string) error {
+
+	// validate input
+	if email == "" {
+		return errors.New("email cannot be empty")
+	}
+
+	// unknown and verified addresses get the same answer so accounts can't be discovered
+	user, err := verificationUsc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+	if !user.EmailVerificationPending {
+		return nil
+	}
+
+	return verificationUsc.SendVerification(ctx, user)
+}
@@ -0,0 +1,76 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// columns of exported tasks
+var taskExportColumns = []string{"id", "title", "description", "status", "priority", "due_date", "is_overdue", "tags", "parent_id", "recurrence", "estimate"}
+
+// export usecase (tasks as rows for spreadsheet downloads)
+type ExportUseCase interface {
+	ExportTasks(ctx context.Context, query domain.TaskQuery, writer domain.RowWriter) error        // write header and one row per task matching query
+}
+
+type exportUseCase struct {
+	taskReader   domain.TaskReader
+	projectRepo  domain.ProjectRepository
+}
+
+// creates new ExportUseCase instance
+func NewExportUseCase(reader domain.TaskReader, projectRepo domain.ProjectRepository) ExportUseCase {
+	return &exportUseCase{taskReader: reader, projectRepo: projectRepo}
+}
+
+// write header and one row per task (nothing is written when the query is invalid)
+func (exportUsc *exportUseCase) ExportTasks(ctx context.Context, query domain.TaskQuery, writer domain.RowWriter) error {
+
+	// validate sort fields
+	for _, sortField := range query.Sort {
+		if _, ok := domain.TaskSortFields[sortField.Field]; !ok {
+			return domain.ErrInvalidSortField
+		}
+	}
+
+	// only tasks of the caller's projects
+	if err := scopeTaskQuery(ctx, exportUsc.projectRepo, &query); err != nil {
+		return err
+	}
+
+	if err := writer.WriteRow(taskExportColumns); err != nil {
+		return err
+	}
+
+	return exportUsc.taskReader.StreamTasks(ctx, query, func(task *domain.Task) error {
+		return writer.WriteRow(taskExportRow(task))
+	})
+}
+
+// cells of a task in column order
+func taskExportRow(task *domain.Task) []string {
+
+	var dueDate, parentID, recurrence, estimate string
+	if !task.DueDate.IsZero() {
+		dueDate = task.DueDate.UTC().Format(time.RFC3339)
+	}
+	if task.ParentID != nil {
+		parentID = task.ParentID.Hex()
+	}
+	if task.Estimate > 0 {
+		estimate = strconv.Itoa(task.Estimate)
+	}
+	if task.Recurrence != nil {
+		recurrence = task.Recurrence.Frequency
+		if task.Recurrence.Frequency == domain.RecurrenceCron {
+			recurrence += " " + task.Recurrence.Cron
+		}
+	}
+
+	return []string{task.ID.Hex(), task.Title, task.Description, task.Status, task.Priority, dueDate,
+		strconv.FormatBool(task.IsOverdue), strings.Join(task.Tags, ", "), parentID, recurrence, estimate}
+}
@@ -0,0 +1,116 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// integrity usecase (finds and repairs broken references and drifted fields)
+type IntegrityUseCase interface {
+	CheckIntegrity(ctx context.Context, request domain.IntegrityRequest) (*domain.IntegrityReport, error)      // run checks, repair unless it is a dry run
+	RunScheduledCheck(ctx context.Context) error                                                               // run every check from the maintenance job and log what was found
+}
+
+type integrityUseCase struct {
+	integrityRepo  domain.IntegrityRepository
+	auditLogRepo   domain.AuditLogRepository
+	logger         domain.Logger
+	autoRepair     bool        // the maintenance job repairs what it finds
+}
+
+// creates new IntegrityUseCase instance
+func NewIntegrityUseCase(integrityRepo domain.IntegrityRepository, auditLogRepo domain.AuditLogRepository, logger domain.Logger, autoRepair bool) IntegrityUseCase {
+	return &integrityUseCase{integrityRepo: integrityRepo, auditLogRepo: auditLogRepo, logger: logger, autoRepair: autoRepair}
+}
+
+// run checks in order, repairing what can be repaired unless it is a dry run
+func (integrityUsc *integrityUseCase) CheckIntegrity(ctx context.Context, request domain.IntegrityRequest) (*domain.IntegrityReport, error) {
+
+	// validate check names (every check when none given)
+	checks := request.Checks
+	if len(checks) == 0 {
+		checks = domain.IntegrityChecks
+	}
+	invalid := &domain.ValidationError{}
+	for _, check := range checks {
+		if !isIntegrityCheck(check) {
+			invalid.Add("checks", "unknown check "+strconv.Quote(check))
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &domain.IntegrityReport{DryRun: request.DryRun == nil || *request.DryRun, StartedAt: now, Checks: []domain.IntegrityCheckResult{}}
+	repairs := map[string]string{}
+	for _, check := range checks {
+		issues, err := integrityUsc.integrityRepo.FindIssues(ctx, check, now)
+		if err != nil {
+			return nil, err
+		}
+
+		result := domain.IntegrityCheckResult{Check: check, Found: len(issues), Repair: domain.IntegrityRepairs[check], Issues: issues}
+		if len(result.Issues) > domain.MaxIntegrityIssuesListed {
+			result.Issues = result.Issues[:domain.MaxIntegrityIssuesListed]
+		}
+		if result.Issues == nil {
+			result.Issues = []domain.IntegrityIssue{}
+		}
+		if !report.DryRun && result.Repair != "" && len(issues) > 0 {
+			result.Repaired, err = integrityUsc.integrityRepo.RepairIssues(ctx, check, issues, now)
+			if err != nil {
+				return nil, err
+			}
+			repairs[check] = strconv.FormatInt(result.Repaired, 10)
+		}
+
+		report.Found += result.Found
+		report.Repaired += result.Repaired
+		report.Checks = append(report.Checks, result)
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	// repairs change documents outside their usecases, keep a record
+	if report.Repaired > 0 {
+		recordAuditLog(ctx, integrityUsc.auditLogRepo, integrityUsc.logger, domain.AuditLogEntry{
+			Action:      domain.AuditActionRepair,
+			EntityType:  domain.AuditEntityWorkspace,
+			EntityID:    "integrity",
+			Details:     repairs,
+		})
+	}
+
+	return report, nil
+}
+
+// maintenance job: report problems (and repair them when configured)
+func (integrityUsc *integrityUseCase) RunScheduledCheck(ctx context.Context) error {
+
+	dryRun := !integrityUsc.autoRepair
+	report, err := integrityUsc.CheckIntegrity(ctx, domain.IntegrityRequest{DryRun: &dryRun})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range report.Checks {
+		if result.Found > 0 {
+			integrityUsc.logger.Warn(ctx, "integrity problems found", "check", result.Check, "found", result.Found, "repaired", result.Repaired)
+		}
+	}
+
+	return nil
+}
+
+// check if a name is a known integrity check
+func isIntegrityCheck(name string) bool {
+	for _, check := range domain.IntegrityChecks {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"sort";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// label usecase
+type LabelUseCase interface {
+	CreateLabel(ctx context.Context, label *domain.Label) (*domain.Label, error)                         // create new label with unique name
+	GetLabels(ctx context.Context) ([]domain.Label, error)                                               // get all labels
+	GetLabelByID(ctx context.Context, labelID string) (*domain.Label, error)                             // get specific label or return error if not found
+	UpdateLabel(ctx context.Context, labelID string, label *domain.Label) (*domain.Label, error)         // update label (renames follow on tasks)
+	DeleteLabel(ctx context.Context, labelID string) error                                               // delete label and detach it from tasks
+	AttachLabel(ctx context.Context, taskID string, labelID string) (*domain.Task, error)                // attach label to task
+	DetachLabel(ctx context.Context, taskID string, labelID string) (*domain.Task, error)                // detach label from task
+	GetBudgetReport(ctx context.Context) (*domain.BudgetReport, error)                                   // label budgets against task costs
+}
+
+type labelUseCase struct {
+	labelRepo     domain.LabelRepository
+	taskRepo      domain.TaskRepository
+	projectRepo   domain.ProjectRepository
+	auditLogRepo  domain.AuditLogRepository
+	logger        domain.Logger
+}
+
+// creates new LabelUseCase instance
+func NewLabelUseCase(labelRepo domain.LabelRepository, taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, auditLogRepo domain.AuditLogRepository, logger domain.Logger) LabelUseCase {
+	return &labelUseCase{labelRepo: labelRepo, taskRepo: taskRepo, projectRepo: projectRepo, auditLogRepo: auditLogRepo, logger: logger}
+}
+
+// create a label
+func (labelUsc *labelUseCase) CreateLabel(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+
+	// validate label name
+	label.Name = strings.TrimSpace(label.Name)
+	if err := validateLabelName(label.Name); err != nil {
+		return nil, err
+	}
+	label.CreatedAt = time.Now().UTC()
+
+	if err := labelUsc.labelRepo.CreateLabel(ctx, label); err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, labelUsc.auditLogRepo, labelUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionCreate,
+		EntityType:  domain.AuditEntityLabel,
+		EntityID:    label.ID.Hex(),
+		After:       auditSnapshot(label),
+	})
+
+	return label, nil
+}
+
+// get all labels
+func (labelUsc *labelUseCase) GetLabels(ctx context.Context) ([]domain.Label, error) {
+	return labelUsc.labelRepo.GetLabels(ctx)
+}
+
+// find label by its id
+func (labelUsc *labelUseCase) GetLabelByID(ctx context.Context, labelID string) (*domain.Label, error) {
+	return labelUsc.labelRepo.GetLabelByID(ctx, labelID)
+}
+
+// update label by its id
+func (labelUsc *labelUseCase) UpdateLabel(ctx context.Context, labelID string, label *domain.Label) (*domain.Label, error) {
+
+	// stop if nothing valid to update
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" && label.Color == "" && label.Description == "" && label.Budget == nil {
+		return nil, domain.NewValidationError("body", "no valid fields provided for update")
+	}
+	if label.Name != "" {
+		if err := validateLabelName(label.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := labelUsc.labelRepo.GetLabelByID(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := labelUsc.labelRepo.UpdateLabel(ctx, labelID, label)
+	if err != nil {
+		return nil, err
+	}
+
+	// tasks refer to labels by name
+	if updated.Name != existing.Name {
+		if err := labelUsc.taskRepo.RenameTagOnAll(ctx, existing.Name, updated.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	recordAuditLog(ctx, labelUsc.auditLogRepo, labelUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityLabel,
+		EntityID:    labelID,
+		Before:      auditSnapshot(existing),
+		After:       auditSnapshot(updated),
+	})
+
+	return updated, nil
+}
+
+// delete label by its id
+func (labelUsc *labelUseCase) DeleteLabel(ctx context.Context, labelID string) error {
+
+	existing, err := labelUsc.labelRepo.GetLabelByID(ctx, labelID)
+	if err != nil {
+		return err
+	}
+
+	if err := labelUsc.labelRepo.DeleteLabel(ctx, labelID); err != nil {
+		return err
+	}
+	if err := labelUsc.taskRepo.RemoveTagFromAll(ctx, existing.Name); err != nil {
+		return err
+	}
+
+	recordAuditLog(ctx, labelUsc.auditLogRepo, labelUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionDelete,
+		EntityType:  domain.AuditEntityLabel,
+		EntityID:    labelID,
+		Before:      auditSnapshot(existing),
+	})
+
+	return nil
+}
+
+// attach label to task
+func (labelUsc *labelUseCase) AttachLabel(ctx context.Context, taskID string, labelID string) (*domain.Task, error) {
+	return labelUsc.changeTaskLabel(ctx, taskID, labelID, labelUsc.taskRepo.AddTag)
+}
+
+// detach label from task
+func (labelUsc *labelUseCase) DetachLabel(ctx context.Context, taskID string, labelID string) (*domain.Task, error) {
+	return labelUsc.changeTaskLabel(ctx, taskID, labelID, labelUsc.taskRepo.RemoveTag)
+}
+
+// apply label change to task and record it
+func (labelUsc *labelUseCase) changeTaskLabel(ctx context.Context, taskID string, labelID string, change func(ctx context.Context, taskID string, tag string) (*domain.Task, error)) (*domain.Task, error) {
+
+	label, err := labelUsc.labelRepo.GetLabelByID(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := labelUsc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := change(ctx, taskID, label.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, labelUsc.auditLogRepo, labelUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityTask,
+		EntityID:    taskID,
+		Before:      auditSnapshot(existing),
+		After:       auditSnapshot(updated),
+	})
+
+	return updated, nil
+}
+
+// label budgets against the costs of their tasks
+func (labelUsc *labelUseCase) GetBudgetReport(ctx context.Context) (*domain.BudgetReport, error) {
+
+	labels, err := labelUsc.labelRepo.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// only tasks of the caller's projects
+	var query domain.TaskQuery
+	if err := scopeTaskQuery(ctx, labelUsc.projectRepo, &query); err != nil {
+		return nil, err
+	}
+
+	// sum costs per currency, over all tasks and per label
+	totals := map[string]*domain.CostTotal{}
+	labelTotals := map[string]map[string]*domain.CostTotal{}
+	err = labelUsc.taskRepo.StreamTasks(ctx, query, func(task *domain.Task) error {
+		if task.Cost == nil {
+			return nil
+		}
+		addCost(totals, task.Cost)
+		for _, tag := range task.Tags {
+			if labelTotals[tag] == nil {
+				labelTotals[tag] = map[string]*domain.CostTotal{}
+			}
+			addCost(labelTotals[tag], task.Cost)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.BudgetReport{Labels: []domain.LabelBudget{}, Totals: sortedCosts(totals)}
+	for _, label := range labels {
+		if label.Budget == nil && labelTotals[label.Name] == nil {
+			continue
+		}
+		entry := domain.LabelBudget{Label: label.Name, Budget: label.Budget, Costs: sortedCosts(labelTotals[label.Name])}
+
+		// only costs in the budget currency count against it, amounts are never converted
+		if label.Budget != nil {
+			spent := 0.0
+			for _, cost := range entry.Costs {
+				if cost.Currency == label.Budget.Currency {
+					spent = cost.Actual
+				} else {
+					entry.OtherCurrency += cost.Tasks
+				}
+			}
+			remaining := domain.RoundAmount(label.Budget.Amount - spent)
+			entry.Remaining = &remaining
+			entry.OverBudget = remaining < 0
+		}
+		report.Labels = append(report.Labels, entry)
+	}
+
+	return report, nil
+}
+
+// add a task cost to the totals of its currency
+func addCost(totals map[string]*domain.CostTotal, cost *domain.TaskCost) {
+	total, ok := totals[cost.Currency]
+	if !ok {
+		total = &domain.CostTotal{Currency: cost.Currency}
+		totals[cost.Currency] = total
+	}
+	total.Estimated += cost.Estimated
+	total.Actual += cost.Actual
+	total.Tasks++
+}
+
+// totals ordered by currency, rounded to cents
+func sortedCosts(totals map[string]*domain.CostTotal) []domain.CostTotal {
+	costs := []domain.CostTotal{}
+	for _, total := range totals {
+		total.Estimated = domain.RoundAmount(total.Estimated)
+		total.Actual = domain.RoundAmount(total.Actual)
+		costs = append(costs, *total)
+	}
+	sort.Slice(costs, func(i, j int) bool { return costs[i].Currency < costs[j].Currency })
+	return costs
+}
+
+// label names are used in comma separated filters
+func validateLabelName(name string) error {
+	if name == "" {
+		return domain.NewValidationError("name", "cannot be empty")
+	}
+	if strings.Contains(name, ",") {
+		return domain.NewValidationError("name", "cannot contain commas")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"testing";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+)
+
+func TestBudgetReportCountsVisibleTasksOnly(t *testing.T) {
+
+	ctx := context.Background()
+	taskRepo := repositories.NewMemoryTaskRepository()
+	labelRepo := repositories.NewMemoryLabelRepository()
+	projectRepo := repositories.NewMemoryProjectRepository()
+	labels := NewLabelUseCase(labelRepo, taskRepo, projectRepo, nil, discardLogger{})
+
+	// alice is a member of acme's project only
+	acme := &domain.Project{Name: "Acme", Members: []domain.ProjectMember{{UserID: "alice", Role: domain.ProjectRoleViewer}}}
+	globex := &domain.Project{Name: "Globex"}
+	for _, project := range []*domain.Project{acme, globex} {
+		if err := projectRepo.CreateProject(ctx, project); err != nil {
+			t.Fatalf("CreateProject(%q): %v", project.Name, err)
+		}
+	}
+	for _, name := range []string{"acme", "globex"} {
+		if err := labelRepo.CreateLabel(ctx, &domain.Label{Name: name, Budget: &domain.Budget{Amount: 500, Currency: "EUR"}}); err != nil {
+			t.Fatalf("CreateLabel(%q): %v", name, err)
+		}
+	}
+	tasks := []*domain.Task{
+		{Title: "Design", Tags: []string{"acme"}, ProjectID: &acme.ID, Cost: &domain.TaskCost{Actual: 100, Currency: "EUR"}},
+		{Title: "Kickoff", Tags: []string{"acme"}, Cost: &domain.TaskCost{Actual: 10, Currency: "EUR"}},
+		{Title: "Audit", Tags: []string{"globex"}, ProjectID: &globex.ID, Cost: &domain.TaskCost{Actual: 50, Currency: "EUR"}},
+	}
+	for _, task := range tasks {
+		task.Status, task.Priority = "pending", "medium"
+		if _, err := taskRepo.CreateTask(ctx, task); err != nil {
+			t.Fatalf("CreateTask(%q): %v", task.Title, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		actor       domain.Actor
+		wantActual  float64
+		wantTasks   int
+		wantLabels  int
+	}{
+		{"project member", domain.Actor{ID: "alice", Role: domain.RoleUser}, 110, 2, 2},       // globex keeps its budget, without costs
+		{"admin", domain.Actor{ID: "root", Role: domain.RoleAdmin}, 160, 3, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := labels.GetBudgetReport(domain.ContextWithActor(ctx, test.actor))
+			if err != nil {
+				t.Fatalf("GetBudgetReport: %v", err)
+			}
+			if len(report.Totals) != 1 || report.Totals[0].Actual != test.wantActual || report.Totals[0].Tasks != test.wantTasks {
+				t.Errorf("totals = %+v, want %v EUR over %d tasks", report.Totals, test.wantActual, test.wantTasks)
+			}
+			if len(report.Labels) != test.wantLabels {
+				t.Fatalf("%d labels, want %d", len(report.Labels), test.wantLabels)
+			}
+			for _, label := range report.Labels {
+				if label.Label == "globex" && test.actor.Role != domain.RoleAdmin && len(label.Costs) != 0 {
+					t.Errorf("globex costs = %+v, want none for a non-member", label.Costs)
+				}
+			}
+		})
+	}
+}
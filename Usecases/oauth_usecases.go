@@ -0,0 +1,322 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"crypto/rand";
+	"crypto/sha256";
+	"crypto/subtle";
+	"encoding/base64";
+	"encoding/hex";
+	"net/url";
+	"regexp";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+const (
+	authorizationCodeTTL = 10 * time.Minute      // how long an authorization code can be exchanged
+	oauthAccessTokenTTL  = time.Hour             // lifetime of tokens issued to third-party clients
+)
+
+// pkce S256 challenges are 32 byte hashes in unpadded base64url, verifiers 43 to 128 unreserved characters (rfc 7636)
+var (
+	codeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+	codeVerifierPattern  = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+)
+
+// oauth consent (what the user is asked to approve)
+type OAuthConsent struct {
+	Client       *domain.OAuthClient    `json:"client"`
+	RedirectURI  string                 `json:"redirect_uri"`
+	Scopes       []string               `json:"scopes"`
+	State        string                 `json:"state,omitempty"`
+}
+
+// oauth token issued to a client
+type OAuthToken struct {
+	AccessToken  string     `json:"access_token"`
+	TokenType    string     `json:"token_type"`
+	ExpiresIn    int64      `json:"expires_in"`
+	Scope        string     `json:"scope"`
+}
+
+// oauth usecase
+type OAuthUseCase interface {
+	RegisterClient(ctx context.Context, ownerID string, name string, redirectURIs []string, scopes []string) (*domain.OAuthClient, string, error)      // register third-party client, returns plain secret once
+	Authorize(ctx context.Context, req *domain.OAuthAuthorizeRequest) (*OAuthConsent, error)                                                        // validate authorize request and describe consent
+	Approve(ctx context.Context, userID string, req *domain.OAuthAuthorizeRequest) (string, error)                                                  // record consent and return redirect uri carrying the code
+	ExchangeCode(ctx context.Context, req *domain.OAuthTokenRequest) (*OAuthToken, error)                                                           // exchange authorization code for access token
+}
+
+type oauthUseCase struct {
+	oauthRepo    domain.OAuthRepository
+	userRepo     domain.UserRepository
+	jwtService   domain.JWTService
+	pwdService   domain.PasswordService
+	auditSink    domain.AuditSink
+}
+
+// creates new OAuthUseCase instance
+func NewOAuthUseCase(oauthRepo domain.OAuthRepository, userRepo domain.UserRepository, jwtServ domain.JWTService, pwdServ domain.PasswordService, auditSink domain.AuditSink) OAuthUseCase {
+	return &oauthUseCase{oauthRepo: oauthRepo, userRepo: userRepo, jwtService: jwtServ, pwdService: pwdServ, auditSink: auditSink}
+}
+
+// register a third-party client
+func (oauthUsc *oauthUseCase) RegisterClient(ctx context.Context, ownerID string, name string, redirectURIs []string, scopes []string) (*domain.OAuthClient, string, error) {
+
+	// validate input
+	if name == "" {
+		return nil, "", domain.NewValidationError("name", "cannot be empty")
+	}
+	if len(redirectURIs) == 0 {
+		return nil, "", domain.NewValidationError("redirect_uris", "at least one redirect uri is required")
+	}
+	for _, redirectURI := range redirectURIs {
+		if !validRedirectURI(redirectURI) {
+			return nil, "", domain.NewValidationError("redirect_uris", "must be absolute https uris without a fragment")
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !domain.ValidScopes[scope] {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+
+	ownerObjID, err := primitive.ObjectIDFromHex(ownerID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, "", domain.ErrInvalidUserID
+	}
+
+	// generate client credentials
+	clientID, err := generateRandomToken(16)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := generateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	hashed, err := oauthUsc.pwdService.HashPassword(secret)        // secret is stored hashed like passwords
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &domain.OAuthClient{
+		ClientID:     clientID,
+		ClientSecret: hashed,
+		Name:         name,
+		RedirectURIs: redirectURIs,
+		Scopes:       scopes,
+		OwnerID:      ownerObjID,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := oauthUsc.oauthRepo.CreateClient(ctx, client); err != nil {
+		return nil, "", err
+	}
+
+	oauthUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditOAuthClientCreated,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  client.ClientID,
+		Details:   map[string]string{"name": client.Name, "scopes": strings.Join(client.Scopes, " ")},
+	})
+
+	return client, secret, nil
+}
+
+// validate authorize request and return what the user must consent to
+func (oauthUsc *oauthUseCase) Authorize(ctx context.Context, req *domain.OAuthAuthorizeRequest) (*OAuthConsent, error) {
+
+	// only the authorization code flow is supported
+	if req.ResponseType != "code" {
+		return nil, domain.ErrUnsupportedGrant
+	}
+
+	client, err := oauthUsc.oauthRepo.GetClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	// redirect uri must exactly match a registered one
+	if !containsString(client.RedirectURIs, req.RedirectURI) {
+		return nil, domain.ErrInvalidRedirectURI
+	}
+
+	// requested scopes must be known and allowed for the client
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		return nil, domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !domain.ValidScopes[scope] || !containsString(client.Scopes, scope) {
+			return nil, domain.ErrInvalidScope
+		}
+	}
+
+	// pkce is optional, only S256 is accepted (plain would send the verifier through the browser)
+	if req.CodeChallenge != "" || req.CodeChallengeMethod != "" {
+		if req.CodeChallengeMethod != "S256" || !codeChallengePattern.MatchString(req.CodeChallenge) {
+			return nil, domain.ErrInvalidCodeChallenge
+		}
+	}
+
+	return &OAuthConsent{Client: client, RedirectURI: req.RedirectURI, Scopes: scopes, State: req.State}, nil
+}
+
+// user approved the consent, issue authorization code
+func (oauthUsc *oauthUseCase) Approve(ctx context.Context, userID string, req *domain.OAuthAuthorizeRequest) (string, error) {
+
+	// re-validate the request, consent may have been tampered with
+	consent, err := oauthUsc.Authorize(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return "", domain.ErrInvalidUserID
+	}
+
+	code, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	// only a hash of the code is stored, like reset tokens
+	authCode := &domain.OAuthAuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      consent.Client.ClientID,
+		UserID:        userObjID,
+		RedirectURI:   consent.RedirectURI,
+		Scopes:        consent.Scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(authorizationCodeTTL),
+	}
+	if err := oauthUsc.oauthRepo.SaveAuthorizationCode(ctx, authCode); err != nil {
+		return "", err
+	}
+
+	oauthUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditOAuthConsentGranted,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  consent.Client.ClientID,
+		Details:   map[string]string{"scopes": strings.Join(consent.Scopes, " ")},
+	})
+
+	// build redirect uri carrying code and state
+	redirect, err := url.Parse(consent.RedirectURI)
+	if err != nil {
+		return "", domain.ErrInvalidRedirectURI
+	}
+	query := redirect.Query()
+	query.Set("code", code)
+	if consent.State != "" {
+		query.Set("state", consent.State)
+	}
+	redirect.RawQuery = query.Encode()
+
+	return redirect.String(), nil
+}
+
+// exchange authorization code for access token
+func (oauthUsc *oauthUseCase) ExchangeCode(ctx context.Context, req *domain.OAuthTokenRequest) (*OAuthToken, error) {
+
+	if req.GrantType != "authorization_code" {
+		return nil, domain.ErrUnsupportedGrant
+	}
+
+	// authenticate client
+	client, err := oauthUsc.oauthRepo.GetClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		if err == domain.ErrOAuthClientNotFound {
+			return nil, domain.ErrInvalidClient
+		}
+		return nil, err
+	}
+	if !oauthUsc.pwdService.CheckPassword(client.ClientSecret, req.ClientSecret) {
+		return nil, domain.ErrInvalidClient
+	}
+
+	// code is consumed even if the checks below fail, so it can't be replayed
+	authCode, err := oauthUsc.oauthRepo.ConsumeAuthorizationCode(ctx, hashToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if authCode.ClientID != client.ClientID || authCode.RedirectURI != req.RedirectURI || time.Now().After(authCode.ExpiresAt) {
+		return nil, domain.ErrInvalidGrant
+	}
+	// with pkce, only whoever started the authorization knows the verifier
+	if !verifyCodeChallenge(authCode.CodeChallenge, req.CodeVerifier) {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	// token carries the user's current identity
+	user, err := oauthUsc.userRepo.GetUserById(ctx, authCode.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, err
+	}
+	if user.IsDeactivated() {
+		return nil, domain.ErrInvalidGrant        // deactivated after approving
+	}
+
+	token, err := oauthUsc.jwtService.GenerateScopedToken(user.ID.Hex(), user.Username, user.Role, user.TenantID, client.ClientID, authCode.Scopes, oauthAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(oauthAccessTokenTTL.Seconds()),
+		Scope:       strings.Join(authCode.Scopes, " "),
+	}, nil
+}
+
+// generate random hex token of n bytes
+func generateRandomToken(n int) (string, error) {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// redirect uris are absolute https uris without a fragment (rfc 6749 section 3.1.2)
+func validRedirectURI(raw string) bool {
+	redirect, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return redirect.Scheme == "https" && redirect.Host != "" && redirect.Fragment == "" && !strings.Contains(raw, "#")
+}
+
+// check a pkce verifier against the S256 challenge of the code (no challenge takes no verifier)
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if challenge == "" {
+		return verifier == ""
+	}
+	if !codeVerifierPattern.MatchString(verifier) {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(challenge)) == 1
+}
+
+// check if slice contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
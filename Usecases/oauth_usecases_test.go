@@ -0,0 +1,60 @@
+package usecases
+
+// imports
+import (
+	"testing";
+)
+
+func TestValidRedirectURI(t *testing.T) {
+
+	tests := []struct {
+		uri   string
+		want  bool
+	}{
+		{"https://app.example.com/callback", true},
+		{"https://app.example.com:8443/callback?source=tasks", true},
+		{"http://app.example.com/callback", false},
+		{"https://app.example.com/callback#token", false},
+		{"https://app.example.com/callback#", false},
+		{"/callback", false},
+		{"https:///callback", false},
+		{"javascript:alert(1)", false},
+		{"com.example.app:/callback", false},
+	}
+
+	for _, test := range tests {
+		if got := validRedirectURI(test.uri); got != test.want {
+			t.Errorf("validRedirectURI(%q) = %v, want %v", test.uri, got, test.want)
+		}
+	}
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+
+	// example of RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	tests := []struct {
+		name       string
+		challenge  string
+		verifier   string
+		want       bool
+	}{
+		{"matching verifier", challenge, verifier, true},
+		{"wrong verifier", challenge, "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXx", false},
+		{"missing verifier", challenge, "", false},
+		{"plain challenge", verifier, verifier, false},
+		{"verifier too short", challenge, "dBjftJeZ4CVP", false},
+		{"no challenge, no verifier", "", "", true},
+		{"no challenge, verifier sent", "", verifier, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(test.challenge, test.verifier); got != test.want {
+				t.Errorf("verifyCodeChallenge(%q, %q) = %v, want %v", test.challenge, test.verifier, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strings";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// organization usecase (tenants with their own users, tasks and projects)
+type OrganizationUseCase interface {
+	CreateOrganization(ctx context.Context, req domain.CreateOrganizationRequest) (*domain.Organization, error)          // create an organization and move its first owner into it (only admin can do this)
+	GetOrganization(ctx context.Context, orgID string) (*domain.Organization, error)                                    // organization of the caller (admins see any)
+	ListMembers(ctx context.Context, orgID string, page int, limit int) (*domain.UserPage, error)                       // page of members (only members can do this)
+	CreateInvite(ctx context.Context, orgID string, email string) (*domain.OrganizationInvite, string, error)           // single-use invite, returns plain token once (only owners can do this)
+	AcceptInvite(ctx context.Context, token string) (*domain.Organization, error)                                       // move the caller into the organization of the invite
+}
+
+type organizationUseCase struct {
+	orgRepo       domain.OrganizationRepository
+	userRepo      domain.UserRepository
+	sessions      domain.SessionStarter
+	auditSink     domain.AuditSink
+	auditLogRepo  domain.AuditLogRepository
+	logger        domain.Logger
+	inviteTTL     time.Duration      // how long an invite stays valid
+}
+
+// creates new OrganizationUseCase instance
+func NewOrganizationUseCase(orgRepo domain.OrganizationRepository, userRepo domain.UserRepository, sessions domain.SessionStarter, auditSink domain.AuditSink, auditLogRepo domain.AuditLogRepository, logger domain.Logger, inviteTTL time.Duration) OrganizationUseCase {
+	return &organizationUseCase{orgRepo: orgRepo, userRepo: userRepo, sessions: sessions, auditSink: auditSink, auditLogRepo: auditLogRepo, logger: logger, inviteTTL: inviteTTL}
+}
+
+// create an organization and move its first owner into it
+func (orgUsc *organizationUseCase) CreateOrganization(ctx context.Context, req domain.CreateOrganizationRequest) (*domain.Organization, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", "cannot be empty")
+	}
+	owner, err := orgUsc.joinableUser(ctx, req.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &domain.Organization{Name: name, Owners: []string{owner.ID.Hex()}, CreatedBy: actor.ID, CreatedAt: time.Now().UTC()}
+	if err := orgUsc.orgRepo.CreateOrganization(ctx, org); err != nil {
+		return nil, err
+	}
+	if err := orgUsc.moveUser(ctx, owner, org.ID.Hex()); err != nil {
+		return nil, err
+	}
+
+	orgUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditOrgCreated,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   actor.ID,
+		Actor:     actor.Username,
+		TargetID:  org.ID.Hex(),
+		Details:   map[string]string{"owner_id": owner.ID.Hex()},
+	})
+	recordAuditLog(ctx, orgUsc.auditLogRepo, orgUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionCreate,
+		EntityType:  domain.AuditEntityOrganization,
+		EntityID:    org.ID.Hex(),
+		After:       auditSnapshot(org),
+	})
+
+	return org, nil
+}
+
+// organization of the caller (admins of the default workspace see any)
+func (orgUsc *organizationUseCase) GetOrganization(ctx context.Context, orgID string) (*domain.Organization, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if actor.TenantID != orgID && !(actor.Role == domain.RoleAdmin && actor.TenantID == "") {
+		return nil, domain.ErrOrganizationNotFound        // other organizations are not revealed
+	}
+
+	return orgUsc.orgRepo.GetOrganizationByID(ctx, orgID)
+}
+
+// page of members (the user repository only lists the caller's tenant)
+func (orgUsc *organizationUseCase) ListMembers(ctx context.Context, orgID string, page int, limit int) (*domain.UserPage, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if actor.TenantID != orgID {
+		return nil, domain.ErrOrganizationNotFound
+	}
+
+	// default the page
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = domain.DefaultUserPageSize
+	}
+	if limit > domain.MaxUserPageSize {
+		limit = domain.MaxUserPageSize
+	}
+
+	users, total, err := orgUsc.userRepo.ListUsers(ctx, domain.UserQuery{Page: page, Limit: limit, TenantID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.UserPage{Users: make([]domain.UserSummary, 0, len(users)), Total: total, Page: page, Limit: limit}
+	for i := range users {
+		result.Users = append(result.Users, users[i].Summary())
+	}
+	return result, nil
+}
+
+// create single-use invite (only owners can do this)
+func (orgUsc *organizationUseCase) CreateInvite(ctx context.Context, orgID string, email string) (*domain.OrganizationInvite, string, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, "", domain.ErrUnauthorized
+	}
+	if actor.TenantID != orgID {
+		return nil, "", domain.ErrOrganizationNotFound
+	}
+	org, err := orgUsc.orgRepo.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !org.IsOwner(actor.ID) {
+		return nil, "", domain.ErrNotOrganizationOwner
+	}
+
+	// generate token, only its hash is stored
+	plain, err := generateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	invite := &domain.OrganizationInvite{
+		OrgID:     orgID,
+		TokenHash: hashToken(plain),
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		CreatedBy: actor.ID,
+		ExpiresAt: now.Add(orgUsc.inviteTTL),
+		CreatedAt: now,
+	}
+	if err := orgUsc.orgRepo.CreateInvite(ctx, invite); err != nil {
+		return nil, "", err
+	}
+
+	orgUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditOrgInvited,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   actor.ID,
+		Actor:     actor.Username,
+		TargetID:  orgID,
+		Details:   map[string]string{"invite_id": invite.ID.Hex(), "email": invite.Email},
+	})
+
+	return invite, plain, nil
+}
+
+// move the caller into the organization of the invite (the caller has to log in again)
+func (orgUsc *organizationUseCase) AcceptInvite(ctx context.Context, token string) (*domain.Organization, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if token == "" {
+		return nil, domain.ErrInvalidOrgInvite
+	}
+
+	// check the caller before the invite is used up
+	user, err := orgUsc.joinableUser(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := orgUsc.orgRepo.ConsumeInvite(ctx, hashToken(token), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// invites naming an address are only valid for it
+	if invite.Email != "" && !strings.EqualFold(invite.Email, user.Email) {
+		orgUsc.restoreInvite(ctx, invite)
+		return nil, domain.ErrInvalidOrgInvite
+	}
+	org, err := orgUsc.orgRepo.GetOrganizationByID(ctx, invite.OrgID)
+	if err != nil {
+		return nil, err        // organization gone, the invite is worthless
+	}
+	if err := orgUsc.moveUser(ctx, user, invite.OrgID); err != nil {
+		orgUsc.restoreInvite(ctx, invite)
+		return nil, err
+	}
+
+	orgUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditOrgJoined,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   actor.ID,
+		Actor:     actor.Username,
+		TargetID:  invite.OrgID,
+		Details:   map[string]string{"invite_id": invite.ID.Hex(), "invited_by": invite.CreatedBy},
+	})
+
+	return org, nil
+}
+
+// user of the default workspace who may be moved into an organization
+func (orgUsc *organizationUseCase) joinableUser(ctx context.Context, userID string) (*domain.User, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	user, err := orgUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TenantID != "" {
+		return nil, domain.ErrAlreadyInOrganization
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil, domain.ErrAdminCannotJoin        // instance admins manage every organization from the default workspace
+	}
+
+	return user, nil
+}
+
+// set the user's tenant and end their sessions (login tokens carry the tenant)
+func (orgUsc *organizationUseCase) moveUser(ctx context.Context, user *domain.User, orgID string) error {
+
+	if err := orgUsc.userRepo.SetTenant(ctx, user.ID, orgID); err != nil {
+		return err
+	}
+	if _, err := orgUsc.sessions.RevokeUserSessions(ctx, user.ID.Hex(), domain.SessionRevokedAccount); err != nil {
+		return err
+	}
+
+	recordAuditLog(ctx, orgUsc.auditLogRepo, orgUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    user.ID.Hex(),
+		Before:      map[string]interface{}{"tenant_id": user.TenantID},
+		After:       map[string]interface{}{"tenant_id": orgID},
+	})
+
+	return nil
+}
+
+// put back an invite that could not be used, so it can be used again
+func (orgUsc *organizationUseCase) restoreInvite(ctx context.Context, invite *domain.OrganizationInvite) {
+	orgUsc.orgRepo.CreateInvite(context.WithoutCancel(ctx), invite)        // best effort, the owner can send a new invite
+}
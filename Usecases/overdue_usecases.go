@@ -0,0 +1,37 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// overdue usecase
+type OverdueUseCase interface {
+	RefreshOverdueFlags(ctx context.Context) error       // bring is_overdue in line with due dates and status
+}
+
+type overdueUseCase struct {
+	taskRepo  domain.TaskRepository
+	logger    domain.Logger
+}
+
+// creates new OverdueUseCase instance
+func NewOverdueUseCase(taskRepo domain.TaskRepository, logger domain.Logger) OverdueUseCase {
+	return &overdueUseCase{taskRepo: taskRepo, logger: logger}
+}
+
+// recompute overdue flags as of now
+func (overdueUsc *overdueUseCase) RefreshOverdueFlags(ctx context.Context) error {
+
+	updated, err := overdueUsc.taskRepo.UpdateOverdueFlags(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		overdueUsc.logger.Info(ctx, "overdue flags updated", "tasks", updated)
+	}
+
+	return nil
+}
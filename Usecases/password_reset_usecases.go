@@ -0,0 +1,139 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// password reset usecase
+type PasswordResetUseCase interface {
+	RequestReset(ctx context.Context, email string) error                      // email a reset token (silently does nothing for unknown addresses)
+	ResetPassword(ctx context.Context, token string, password string) error    // set new password with a valid token, invalidating all reset tokens and login sessions of the user
+}
+
+type passwordResetUseCase struct {
+	userRepo    domain.UserRepository
+	resetRepo   domain.PasswordResetRepository
+	pwdService  domain.PasswordService
+	sessions    domain.SessionStarter          // sessions end when the password is reset
+	emailServ   domain.EmailService
+	auditSink   domain.AuditSink
+	logger      domain.Logger
+	tokenTTL    time.Duration      // how long a reset token stays valid
+	resetURL    string             // link sent in the email, the token is appended
+}
+
+// creates new PasswordResetUseCase instance
+func NewPasswordResetUseCase(userRepo domain.UserRepository, resetRepo domain.PasswordResetRepository, pwdServ domain.PasswordService, sessions domain.SessionStarter, emailServ domain.EmailService, auditSink domain.AuditSink, logger domain.Logger, tokenTTL time.Duration, resetURL string) PasswordResetUseCase {
+	return &passwordResetUseCase{userRepo: userRepo, resetRepo: resetRepo, pwdService: pwdServ, sessions: sessions, emailServ: emailServ, auditSink: auditSink, logger: logger, tokenTTL: tokenTTL, resetURL: resetURL}
+}
+
+// generate reset token and email it to the account owner
+func (resetUsc *passwordResetUseCase) RequestReset(ctx context.Context, email string) error {
+
+	// validate input
+	if email == "" {
+		return errors.New("email cannot be empty")
+	}
+
+	// unknown addresses get the same answer so accounts can't be discovered
+	user, err := resetUsc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// only the newest token is valid
+	if err := resetUsc.resetRepo.DeleteUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// generate token, only its hash is stored
+	plain, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	token := &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(plain),
+		ExpiresAt: now.Add(resetUsc.tokenTTL),
+		CreatedAt: now,
+	}
+	if err := resetUsc.resetRepo.CreateToken(ctx, token); err != nil {
+		return err
+	}
+
+	body := "Hi " + user.Username + ",\n\n" +
+		"Use the link below to choose a new password. It expires at " + token.ExpiresAt.Format(time.RFC1123) + ".\n\n" +
+		resetUsc.resetURL + plain + "\n\n" +
+		"If you didn't ask for a password reset you can ignore this email."
+	if err := resetUsc.emailServ.SendEmail(ctx, user.Email, "Reset your password", body); err != nil {
+		resetUsc.logger.Error(ctx, "password reset email failed", "user_id", user.ID.Hex(), "error", err)
+		return err
+	}
+
+	resetUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditPasswordResetRequested,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  user.ID.Hex(),
+	})
+
+	return nil
+}
+
+// set new password using a reset token
+func (resetUsc *passwordResetUseCase) ResetPassword(ctx context.Context, token string, password string) error {
+
+	// validate input
+	if token == "" {
+		return domain.ErrInvalidResetToken
+	}
+	if len(password) < 8 {
+		return domain.NewValidationError("password", "must be at least 8 characters")
+	}
+
+	// tokens are single use, taking it out first stops a second request with the same token
+	// (expired tokens may still exist until the ttl index removes them)
+	resetToken, err := resetUsc.resetRepo.ConsumeToken(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if time.Now().After(resetToken.ExpiresAt) {
+		return domain.ErrInvalidResetToken
+	}
+
+	// hash password securely 
+	hashed, err := resetUsc.pwdService.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := resetUsc.userRepo.UpdatePassword(ctx, resetToken.UserID, hashed); err != nil {
+		return err
+	}
+
+	// other tokens stop working, and proving ownership lifts a login lockout
+	if err := resetUsc.resetRepo.DeleteUserTokens(ctx, resetToken.UserID); err != nil {
+		return err
+	}
+	if err := resetUsc.userRepo.ResetFailedLogins(ctx, resetToken.UserID); err != nil {
+		return err
+	}
+	// whoever knew the old password is logged out
+	if _, err := resetUsc.sessions.RevokeUserSessions(ctx, resetToken.UserID.Hex(), domain.SessionRevokedPassword); err != nil {
+		return err
+	}
+
+	resetUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditPasswordReset,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   resetToken.UserID.Hex(),
+	})
+
+	return nil
+}
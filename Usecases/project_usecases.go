@@ -0,0 +1,444 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// project usecase
+type ProjectUseCase interface {
+	CreateProject(ctx context.Context, project *domain.Project) (*domain.Project, error)                              // create project owned by the caller
+	GetProjects(ctx context.Context) ([]domain.Project, error)                                                        // projects the caller is a member of (all for admins)
+	GetProject(ctx context.Context, projectID string) (*domain.Project, error)                                       // get project the caller is a member of
+	UpdateProject(ctx context.Context, projectID string, project *domain.Project) (*domain.Project, error)          // update name and description (owners)
+	DeleteProject(ctx context.Context, projectID string) error                                                        // delete a project without tasks (owners)
+	SetMember(ctx context.Context, projectID string, member domain.ProjectMember) (*domain.Project, error)           // add a member or change their role (owners)
+	RemoveMember(ctx context.Context, projectID string, userID string) (*domain.Project, error)                      // remove a member (owners, or members leaving)
+	CloneProject(ctx context.Context, projectID string, req domain.ProjectCloneRequest) (*domain.ProjectClone, error)       // copy a project (members and open tasks on request) owned by the caller
+}
+
+type projectUseCase struct {
+	projectRepo   domain.ProjectRepository
+	taskRepo      domain.TaskRepository
+	userRepo      domain.UserRepository
+	auditLogRepo  domain.AuditLogRepository
+	unitOfWork    domain.UnitOfWork
+	logger        domain.Logger
+	handlers      []domain.TaskEventHandler       // same handlers as task commands, so cloned tasks are published like created tasks
+}
+
+// creates new ProjectUseCase instance
+func NewProjectUseCase(projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, userRepo domain.UserRepository, auditLogRepo domain.AuditLogRepository, unitOfWork domain.UnitOfWork, logger domain.Logger, handlers ...domain.TaskEventHandler) ProjectUseCase {
+	return &projectUseCase{projectRepo: projectRepo, taskRepo: taskRepo, userRepo: userRepo, auditLogRepo: auditLogRepo, unitOfWork: unitOfWork, logger: logger, handlers: handlers}
+}
+
+// stops a task stream at the first task
+var errStopStream = errors.New("stop stream")
+
+// create a project, the caller becomes its owner
+func (projectUsc *projectUseCase) CreateProject(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return nil, domain.NewValidationError("name", "cannot be empty")
+	}
+	project.Members = []domain.ProjectMember{{UserID: actor.ID, Role: domain.ProjectRoleOwner}}
+	project.CreatedAt = time.Now().UTC()
+
+	if err := projectUsc.projectRepo.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, projectUsc.auditLogRepo, projectUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionCreate,
+		EntityType:  domain.AuditEntityProject,
+		EntityID:    project.ID.Hex(),
+		After:       auditSnapshot(project),
+	})
+
+	return project, nil
+}
+
+// projects the caller is a member of (admins see all)
+func (projectUsc *projectUseCase) GetProjects(ctx context.Context) ([]domain.Project, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	if actor.ManagesAllProjects() {
+		return projectUsc.projectRepo.GetProjects(ctx, "")
+	}
+	if actor.ID == "" {
+		return []domain.Project{}, nil
+	}
+	return projectUsc.projectRepo.GetProjects(ctx, actor.ID)
+}
+
+// get a project, hidden from non-members
+func (projectUsc *projectUseCase) GetProject(ctx context.Context, projectID string) (*domain.Project, error) {
+	project, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, domain.ProjectRoleViewer)
+	if err == domain.ErrProjectAccessDenied {
+		return nil, domain.ErrProjectNotFound
+	}
+	return project, err
+}
+
+// update name and description
+func (projectUsc *projectUseCase) UpdateProject(ctx context.Context, projectID string, project *domain.Project) (*domain.Project, error) {
+
+	// stop if nothing valid to update
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" && project.Description == "" {
+		return nil, domain.NewValidationError("body", "no valid fields provided for update")
+	}
+
+	existing, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, domain.ProjectRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := projectUsc.projectRepo.UpdateProject(ctx, projectID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUsc.recordChange(ctx, existing, updated)
+	return updated, nil
+}
+
+// delete a project once its tasks are gone
+func (projectUsc *projectUseCase) DeleteProject(ctx context.Context, projectID string) error {
+
+	existing, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, domain.ProjectRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	// tasks would lose their members' access, they are moved or deleted first
+	query := domain.TaskQuery{ProjectID: &existing.ID}
+	err = projectUsc.taskRepo.StreamTasks(ctx, query, func(task *domain.Task) error {
+		return errStopStream
+	})
+	if err == errStopStream {
+		return domain.ErrProjectHasTasks
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := projectUsc.projectRepo.DeleteProject(ctx, projectID); err != nil {
+		return err
+	}
+
+	recordAuditLog(ctx, projectUsc.auditLogRepo, projectUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionDelete,
+		EntityType:  domain.AuditEntityProject,
+		EntityID:    projectID,
+		Before:      auditSnapshot(existing),
+	})
+
+	return nil
+}
+
+// add a member or change their role
+func (projectUsc *projectUseCase) SetMember(ctx context.Context, projectID string, member domain.ProjectMember) (*domain.Project, error) {
+
+	existing, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, domain.ProjectRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// members must be existing users
+	userID, err := primitive.ObjectIDFromHex(member.UserID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	if _, err := projectUsc.userRepo.GetUserById(ctx, userID); err != nil {
+		return nil, err
+	}
+	if member.Role != domain.ProjectRoleOwner && lastOwner(existing, member.UserID) {
+		return nil, domain.ErrLastProjectOwner
+	}
+
+	updated, err := projectUsc.projectRepo.SetMember(ctx, projectID, member)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUsc.recordChange(ctx, existing, updated)
+	return updated, nil
+}
+
+// remove a member (members may always leave)
+func (projectUsc *projectUseCase) RemoveMember(ctx context.Context, projectID string, userID string) (*domain.Project, error) {
+
+	need := domain.ProjectRoleOwner
+	if actor, _ := domain.ActorFromContext(ctx); actor.ID == userID {
+		need = domain.ProjectRoleViewer
+	}
+	existing, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, need)
+	if err != nil {
+		return nil, err
+	}
+	if existing.RoleOf(userID) == "" {
+		return nil, domain.ErrProjectMemberNotFound
+	}
+	if lastOwner(existing, userID) {
+		return nil, domain.ErrLastProjectOwner
+	}
+
+	updated, err := projectUsc.projectRepo.RemoveMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUsc.recordChange(ctx, existing, updated)
+	return updated, nil
+}
+
+// copy a project into a new one owned by the caller, open tasks start over as pending
+func (projectUsc *projectUseCase) CloneProject(ctx context.Context, projectID string, req domain.ProjectCloneRequest) (*domain.ProjectClone, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	source, err := projectWithRole(ctx, projectUsc.projectRepo, projectID, domain.ProjectRoleViewer)
+	if err == domain.ErrProjectAccessDenied {
+		return nil, domain.ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &domain.Project{
+		ID:           primitive.NewObjectID(),
+		Name:         strings.TrimSpace(req.Name),
+		Description:  source.Description,
+		Members:      []domain.ProjectMember{{UserID: actor.ID, Role: domain.ProjectRoleOwner}},
+		CreatedAt:    time.Now().UTC(),
+	}
+	if clone.Name == "" {
+		clone.Name = source.Name + " (copy)"
+		if len(clone.Name) > 100 {
+			clone.Name = source.Name
+		}
+	}
+	if req.CopyMembers {
+		for _, member := range source.Members {
+			if member.UserID != actor.ID {
+				clone.Members = append(clone.Members, member)
+			}
+		}
+	}
+
+	tasks := []*domain.Task{}
+	if req.IncludeTasks {
+		if tasks, err = projectUsc.openTaskCopies(ctx, source, clone, req.ShiftDays); err != nil {
+			return nil, err
+		}
+	}
+
+	// the project and its tasks appear together or not at all
+	err = projectUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := projectUsc.projectRepo.CreateProject(ctx, clone); err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		return projectUsc.taskRepo.CreateTasks(ctx, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, projectUsc.auditLogRepo, projectUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionCreate,
+		EntityType:  domain.AuditEntityProject,
+		EntityID:    clone.ID.Hex(),
+		After:       auditSnapshot(clone),
+	})
+	for _, task := range tasks {
+		for _, handler := range projectUsc.handlers {
+			handler.HandleTaskEvent(ctx, domain.TaskEvent{
+				Type:     domain.TaskEventCreated,
+				TaskID:   task.ID.Hex(),
+				After:    task,
+				Details:  map[string]string{"cloned_from_project": source.ID.Hex()},
+			})
+		}
+	}
+
+	return &domain.ProjectClone{Project: clone, TasksCopied: len(tasks)}, nil
+}
+
+// copies of the open tasks of a project for its clone (subtasks stay under their copied parent)
+func (projectUsc *projectUseCase) openTaskCopies(ctx context.Context, source *domain.Project, clone *domain.Project, shiftDays int) ([]*domain.Task, error) {
+
+	open := []domain.Task{}
+	err := projectUsc.taskRepo.StreamTasks(ctx, domain.TaskQuery{ProjectID: &source.ID}, func(task *domain.Task) error {
+		if task.Status == "completed" {
+			return nil
+		}
+		if len(open) == domain.MaxClonedTasks {
+			return domain.ErrTooManyTasksToClone
+		}
+		open = append(open, *task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// new ids first, so parents can be pointed at their copies
+	copiedIDs := map[primitive.ObjectID]primitive.ObjectID{}
+	for _, task := range open {
+		copiedIDs[task.ID] = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	copies := []*domain.Task{}
+	for _, task := range open {
+		copied := &domain.Task{
+			ID:            copiedIDs[task.ID],
+			Title:         task.Title,
+			Description:   task.Description,
+			DueDate:       task.DueDate.AddDate(0, 0, shiftDays),
+			Status:        "pending",
+			Priority:      task.Priority,
+			PriorityRank:  task.PriorityRank,
+			Estimate:      task.Estimate,
+			Tags:          task.Tags,
+			ProjectID:     &clone.ID,
+			Language:      task.Language,
+		}
+		copied.IsOverdue = copied.DueDate.Before(now)
+		// subtasks of completed parents become top-level tasks
+		if task.ParentID != nil {
+			if parentID, ok := copiedIDs[*task.ParentID]; ok {
+				copied.ParentID = &parentID
+			}
+		}
+		if task.Reminder != nil {
+			reminder := *task.Reminder
+			reminder.SentAt = nil
+			copied.Reminder = &reminder
+		}
+		if task.Cost != nil && task.Cost.Estimated > 0 {
+			copied.Cost = &domain.TaskCost{Estimated: task.Cost.Estimated, Currency: task.Cost.Currency}       // the copy has its own actual cost
+		}
+		// a recurring task starts its own series
+		if task.Recurrence != nil {
+			rule := *task.Recurrence
+			rule.SeriesID = copied.ID
+			rule.AdvancedAt = nil
+			copied.Recurrence = &rule
+		}
+		copies = append(copies, copied)
+	}
+
+	return copies, nil
+}
+
+// audit log entry of a changed project
+func (projectUsc *projectUseCase) recordChange(ctx context.Context, before *domain.Project, after *domain.Project) {
+	recordAuditLog(ctx, projectUsc.auditLogRepo, projectUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityProject,
+		EntityID:    after.ID.Hex(),
+		Before:      auditSnapshot(before),
+		After:       auditSnapshot(after),
+	})
+}
+
+// check if the user is the only owner of the project
+func lastOwner(project *domain.Project, userID string) bool {
+	if project.RoleOf(userID) != domain.ProjectRoleOwner {
+		return false
+	}
+	for _, member := range project.Members {
+		if member.Role == domain.ProjectRoleOwner && member.UserID != userID {
+			return false
+		}
+	}
+	return true
+}
+
+// get a project and check the caller's role in it
+// admins and background jobs (no actor on the context) act on every project
+func projectWithRole(ctx context.Context, repo domain.ProjectRepository, projectID string, need string) (*domain.Project, error) {
+
+	project, err := repo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok || actor.ManagesAllProjects() {
+		return project, nil
+	}
+	if !domain.ProjectRoleAtLeast(project.RoleOf(actor.ID), need) {
+		return nil, domain.ErrProjectAccessDenied
+	}
+	return project, nil
+}
+
+// check the caller may change the tasks of a project (nothing to check for tasks without one)
+func checkProjectTasks(ctx context.Context, repo domain.ProjectRepository, projectID *primitive.ObjectID) error {
+	if projectID == nil {
+		return nil
+	}
+	_, err := projectWithRole(ctx, repo, projectID.Hex(), domain.ProjectRoleEditor)
+	return err
+}
+
+// check the caller may see a task, tasks of other projects are reported as not found
+func checkTaskVisible(ctx context.Context, repo domain.ProjectRepository, task *domain.Task) error {
+	if task.ProjectID == nil {
+		return nil
+	}
+	_, err := projectWithRole(ctx, repo, task.ProjectID.Hex(), domain.ProjectRoleViewer)
+	if err == domain.ErrProjectAccessDenied || err == domain.ErrProjectNotFound {
+		return domain.ErrTaskNotFound
+	}
+	return err
+}
+
+// limit a task query to the caller's projects (and tasks without a project)
+func scopeTaskQuery(ctx context.Context, repo domain.ProjectRepository, query *domain.TaskQuery) error {
+
+	if query.ProjectID != nil {
+		_, err := projectWithRole(ctx, repo, query.ProjectID.Hex(), domain.ProjectRoleViewer)
+		return err
+	}
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok || actor.ManagesAllProjects() {
+		return nil
+	}
+	projects, err := repo.GetProjects(ctx, actor.ID)
+	if err != nil {
+		return err
+	}
+	visibility := &domain.ProjectVisibility{Projects: []primitive.ObjectID{}}
+	for _, project := range projects {
+		visibility.Projects = append(visibility.Projects, project.ID)
+	}
+	query.Visibility = visibility
+	return nil
+}
+
+// check if two tasks are in the same project (or both in none)
+func sameProject(a *primitive.ObjectID, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
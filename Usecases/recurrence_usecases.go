@@ -0,0 +1,209 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// recurrence usecase (series of repeating tasks, the newest occurrence carries the active rule)
+type RecurrenceUseCase interface {
+	SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error)      // start or replace the recurrence of a task
+	PauseRecurrence(ctx context.Context, taskID string) (*domain.Task, error)                            // stop creating occurrences until resumed
+	ResumeRecurrence(ctx context.Context, taskID string) (*domain.Task, error)                           // create occurrences again
+	EndRecurrence(ctx context.Context, taskID string) (*domain.Task, error)                              // end the series, the task stays
+	CreateDueOccurrences(ctx context.Context) error                                                      // create next occurrences of completed or past due tasks
+	CreateNextOccurrence(ctx context.Context, task *domain.Task) error                                   // create the occurrence following a task
+}
+
+type recurrenceUseCase struct {
+	taskRepo  domain.TaskRepository
+	logger    domain.Logger
+	handlers  []domain.TaskEventHandler       // same handlers as task commands, so occurrences are published like created tasks
+}
+
+// creates new RecurrenceUseCase instance
+func NewRecurrenceUseCase(taskRepo domain.TaskRepository, logger domain.Logger, handlers ...domain.TaskEventHandler) RecurrenceUseCase {
+	return &recurrenceUseCase{taskRepo: taskRepo, logger: logger, handlers: handlers}
+}
+
+// start or replace the recurrence of a task (an existing series keeps its id)
+func (recurrenceUsc *recurrenceUseCase) SetRecurrence(ctx context.Context, taskID string, rule *domain.Recurrence) (*domain.Task, error) {
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	task, err := recurrenceUsc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	rule.SeriesID = task.ID
+	rule.AdvancedAt = nil
+	if task.Recurrence != nil {
+		if task.Recurrence.AdvancedAt != nil {
+			return nil, domain.ErrRecurrenceNotFound       // the series continues on a newer occurrence
+		}
+		rule.SeriesID = task.Recurrence.SeriesID
+	}
+
+	return recurrenceUsc.taskRepo.SetRecurrence(ctx, taskID, rule)
+}
+
+func (recurrenceUsc *recurrenceUseCase) PauseRecurrence(ctx context.Context, taskID string) (*domain.Task, error) {
+	return recurrenceUsc.change(ctx, taskID, func(rule *domain.Recurrence) *domain.Recurrence {
+		rule.Paused = true
+		return rule
+	})
+}
+
+func (recurrenceUsc *recurrenceUseCase) ResumeRecurrence(ctx context.Context, taskID string) (*domain.Task, error) {
+	return recurrenceUsc.change(ctx, taskID, func(rule *domain.Recurrence) *domain.Recurrence {
+		rule.Paused = false
+		return rule
+	})
+}
+
+func (recurrenceUsc *recurrenceUseCase) EndRecurrence(ctx context.Context, taskID string) (*domain.Task, error) {
+	return recurrenceUsc.change(ctx, taskID, func(rule *domain.Recurrence) *domain.Recurrence {
+		return nil
+	})
+}
+
+// change the active rule of a task
+func (recurrenceUsc *recurrenceUseCase) change(ctx context.Context, taskID string, change func(rule *domain.Recurrence) *domain.Recurrence) (*domain.Task, error) {
+
+	// validate id field 
+	if taskID == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+
+	task, err := recurrenceUsc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Recurrence == nil || task.Recurrence.AdvancedAt != nil {
+		return nil, domain.ErrRecurrenceNotFound
+	}
+
+	return recurrenceUsc.taskRepo.SetRecurrence(ctx, taskID, change(task.Recurrence))
+}
+
+// scheduled job (catches series whose due date passed and completions missed by the event handler)
+func (recurrenceUsc *recurrenceUseCase) CreateDueOccurrences(ctx context.Context) error {
+
+	tasks, err := recurrenceUsc.taskRepo.GetRecurringTasksDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if err := recurrenceUsc.CreateNextOccurrence(ctx, &tasks[i]); err != nil {
+			recurrenceUsc.logger.Warn(ctx, "next occurrence not created", "task_id", tasks[i].ID.Hex(), "error", err)
+		}
+	}
+
+	return nil
+}
+
+// create the occurrence following a task, due at the first time of the series after now
+// missed occurrences are skipped instead of being created overdue
+func (recurrenceUsc *recurrenceUseCase) CreateNextOccurrence(ctx context.Context, task *domain.Task) error {
+
+	rule := task.Recurrence
+	if rule == nil || rule.Paused || rule.AdvancedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	next, ok := rule.NextOccurrence(task.DueDate, now)
+
+	// only one run moves the series on
+	advanced, err := recurrenceUsc.taskRepo.AdvanceRecurrence(ctx, task.ID, now)
+	if err != nil || !advanced {
+		return err
+	}
+	if !ok {
+		return nil        // series ended
+	}
+
+	occurrence := nextOccurrence(task, next)
+	created, err := recurrenceUsc.taskRepo.CreateTask(ctx, occurrence)
+	if err != nil {
+		// put the series back so the next run tries again
+		if _, restoreErr := recurrenceUsc.taskRepo.SetRecurrence(ctx, task.ID.Hex(), rule); restoreErr != nil {
+			recurrenceUsc.logger.Error(ctx, "recurrence not restored after failed occurrence", "task_id", task.ID.Hex(), "error", restoreErr)
+		}
+		return err
+	}
+
+	for _, handler := range recurrenceUsc.handlers {
+		handler.HandleTaskEvent(ctx, domain.TaskEvent{
+			Type:     domain.TaskEventCreated,
+			TaskID:   created.ID.Hex(),
+			After:    created,
+			Details:  map[string]string{"series_id": created.Recurrence.SeriesID.Hex(), "previous_id": task.ID.Hex()},
+		})
+	}
+
+	return nil
+}
+
+// copy of a task due at the next time of its series
+func nextOccurrence(task *domain.Task, due time.Time) *domain.Task {
+
+	rule := *task.Recurrence
+	rule.AdvancedAt = nil
+
+	occurrence := &domain.Task{
+		Title:         task.Title,
+		Description:   task.Description,
+		DueDate:       due,
+		Status:        "pending",
+		Priority:      task.Priority,
+		PriorityRank:  task.PriorityRank,
+		Estimate:      task.Estimate,
+		ParentID:      task.ParentID,
+		Tags:          task.Tags,
+		Recurrence:    &rule,
+		TenantID:      task.TenantID,
+	}
+	if task.Reminder != nil {
+		reminder := *task.Reminder
+		reminder.SentAt = nil
+		occurrence.Reminder = &reminder
+	}
+	if task.Cost != nil && task.Cost.Estimated > 0 {
+		occurrence.Cost = &domain.TaskCost{Estimated: task.Cost.Estimated, Currency: task.Cost.Currency}       // each occurrence has its own actual cost
+	}
+
+	return occurrence
+}
+
+// creates the next occurrence as soon as a recurring task is completed
+type recurrenceTaskEventHandler struct {
+	recurrenceUsc  RecurrenceUseCase
+	logger         domain.Logger
+}
+
+// creates task event handler for recurring tasks
+func NewRecurrenceTaskEventHandler(recurrenceUsc RecurrenceUseCase, logger domain.Logger) domain.TaskEventHandler {
+	return &recurrenceTaskEventHandler{recurrenceUsc: recurrenceUsc, logger: logger}
+}
+
+func (handler *recurrenceTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	if event.Type != domain.TaskEventUpdated || event.After == nil || event.After.Status != "completed" {
+		return
+	}
+	if event.Before != nil && event.Before.Status == "completed" {
+		return
+	}
+
+	// the scheduled job retries when this fails
+	if err := handler.recurrenceUsc.CreateNextOccurrence(ctx, event.After); err != nil {
+		handler.logger.Warn(ctx, "next occurrence not created", "task_id", event.TaskID, "error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"fmt";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// due soon notification text per task language (title, then due date and status for the message)
+var dueSoonTemplates = map[string]struct{ subject, message string }{
+	"en": {"Task %q is due soon", "Task %q is due at %s (status: %s)."},
+	"es": {"La tarea %q vence pronto", "La tarea %q vence el %s (estado: %s)."},
+	"fr": {"La tâche %q arrive à échéance", "La tâche %q est due le %s (statut : %s)."},
+	"de": {"Aufgabe %q ist bald fällig", "Aufgabe %q ist fällig am %s (Status: %s)."},
+	"it": {"L'attività %q è in scadenza", "L'attività %q scade il %s (stato: %s)."},
+	"pt": {"A tarefa %q vence em breve", "A tarefa %q vence em %s (estado: %s)."},
+	"nl": {"Taak %q verloopt binnenkort", "Taak %q verloopt op %s (status: %s)."},
+}
+
+// reminder usecase
+type ReminderUseCase interface {
+	SendDueReminders(ctx context.Context) error       // notify about tasks whose reminder time has come
+}
+
+type reminderUseCase struct {
+	taskRepo       domain.TaskRepository
+	projectRepo    domain.ProjectRepository      // members of a task's project are reminded too
+	userRepo       domain.UserRepository
+	notifier       domain.Notifier
+	defaultWindow  time.Duration      // reminder lead time for tasks without their own
+	maxWindow      time.Duration      // longest lead time a task can ask for
+	logger         domain.Logger
+}
+
+// creates new ReminderUseCase instance
+func NewReminderUseCase(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, userRepo domain.UserRepository, notifier domain.Notifier, defaultWindow time.Duration, logger domain.Logger) ReminderUseCase {
+	return &reminderUseCase{taskRepo: taskRepo, projectRepo: projectRepo, userRepo: userRepo, notifier: notifier, defaultWindow: defaultWindow, maxWindow: 7 * 24 * time.Hour, logger: logger}
+}
+
+// send reminders that are due now
+func (reminderUsc *reminderUseCase) SendDueReminders(ctx context.Context) error {
+
+	now := time.Now()
+	lookahead := reminderUsc.maxWindow
+	if reminderUsc.defaultWindow > lookahead {
+		lookahead = reminderUsc.defaultWindow
+	}
+
+	// candidates are tasks due within the longest possible window
+	tasks, err := reminderUsc.taskRepo.GetTasksDueForReminder(ctx, now.Add(lookahead))
+	if err != nil {
+		return err
+	}
+
+	recipients := map[primitive.ObjectID][]string{}        // project members who want reminders, looked up once per run
+	for _, task := range tasks {
+
+		// each task decides how early it wants to be reminded
+		window := reminderUsc.defaultWindow
+		if task.Reminder.MinutesBefore > 0 {
+			window = time.Duration(task.Reminder.MinutesBefore) * time.Minute
+		}
+		if task.DueDate.Sub(now) > window {
+			continue
+		}
+
+		// written in the task's language, english when it has none
+		template, ok := dueSoonTemplates[task.Language]
+		if !ok {
+			template = dueSoonTemplates[domain.DefaultTaskLanguage]
+		}
+		notification := domain.Notification{
+			Type:     domain.NotificationTaskDueSoon,
+			TaskID:   task.ID,
+			Subject:  fmt.Sprintf(template.subject, task.Title),
+			Message:  fmt.Sprintf(template.message, task.Title, task.DueDate.Format(time.RFC1123), task.Status),
+		}
+
+		// a failed delivery is retried on the next run
+		if err := reminderUsc.notifier.Notify(ctx, notification); err != nil {
+			reminderUsc.logger.Warn(ctx, "reminder delivery failed", "task_id", task.ID.Hex(), "error", err)
+			continue
+		}
+
+		// project members who asked for reminders by email, a failed email is not retried
+		if task.ProjectID != nil {
+			if _, ok := recipients[*task.ProjectID]; !ok {
+				recipients[*task.ProjectID] = reminderUsc.projectRecipients(ctx, task.ProjectID.Hex(), notification.Type)
+			}
+			for _, email := range recipients[*task.ProjectID] {
+				personal := notification
+				personal.Recipient = email
+				if err := reminderUsc.notifier.Notify(ctx, personal); err != nil {
+					reminderUsc.logger.Warn(ctx, "reminder email failed", "task_id", task.ID.Hex(), "error", err)
+				}
+			}
+		}
+
+		if err := reminderUsc.taskRepo.MarkReminderSent(ctx, task.ID, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// email addresses of active project members whose preferences ask for a notification type
+func (reminderUsc *reminderUseCase) projectRecipients(ctx context.Context, projectID string, notificationType string) []string {
+
+	project, err := reminderUsc.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		if err != domain.ErrProjectNotFound {
+			reminderUsc.logger.Warn(ctx, "reminder recipients not loaded", "project_id", projectID, "error", err)
+		}
+		return nil
+	}
+
+	var emails []string
+	for _, member := range project.Members {
+		userID, err := primitive.ObjectIDFromHex(member.UserID)
+		if err != nil {
+			continue
+		}
+		user, err := reminderUsc.userRepo.GetUserById(ctx, userID)
+		if err != nil {
+			continue        // deleted accounts stay members until removed
+		}
+		if user.Email != "" && user.DeactivatedAt == nil && user.Notifications.Wants(notificationType) {
+			emails = append(emails, user.Email)
+		}
+	}
+
+	return emails
+}
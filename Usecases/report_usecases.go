@@ -0,0 +1,109 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"fmt";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// report usecase (admins see their whole workspace, everyone else their own tasks and work)
+type ReportUseCase interface {
+	Summary(ctx context.Context) (*domain.TaskSummaryReport, error)                                         // task counts by status and priority, overdue and completed this week
+	Productivity(ctx context.Context, query domain.ProductivityQuery) (*domain.ProductivityReport, error)    // tasks created and completed and time logged per day
+}
+
+type reportUseCase struct {
+	reportRepo   domain.ReportRepository
+	projectRepo  domain.ProjectRepository        // personal summaries only count tasks of the caller's projects
+}
+
+// creates new ReportUseCase instance
+func NewReportUseCase(reportRepo domain.ReportRepository, projectRepo domain.ProjectRepository) ReportUseCase {
+	return &reportUseCase{reportRepo: reportRepo, projectRepo: projectRepo}
+}
+
+// counts of the tasks the caller sees
+func (reportUsc *reportUseCase) Summary(ctx context.Context) (*domain.TaskSummaryReport, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// same tasks as the caller's task list
+	var query domain.TaskQuery
+	if err := scopeTaskQuery(ctx, reportUsc.projectRepo, &query); err != nil {
+		return nil, err
+	}
+	scope := domain.ReportScope{Visibility: query.Visibility}
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		scope.TenantID = &tenant
+	}
+
+	now := time.Now().UTC()
+	report, err := reportUsc.reportRepo.TaskSummary(ctx, scope, weekStart(now))
+	if err != nil {
+		return nil, err
+	}
+	report.Scope = domain.ReportScopePersonal
+	if actor.ManagesAllProjects() {
+		report.Scope = domain.ReportScopeOrganization
+	}
+	report.GeneratedAt = now
+
+	return report, nil
+}
+
+// work done in a range, by everyone for admins and by the caller otherwise
+func (reportUsc *reportUseCase) Productivity(ctx context.Context, query domain.ProductivityQuery) (*domain.ProductivityReport, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	to := time.Now().UTC()
+	if query.To != nil {
+		to = query.To.UTC()
+	}
+	from := to.AddDate(0, 0, -30)
+	if query.From != nil {
+		from = query.From.UTC()
+	}
+	if !from.Before(to) {
+		return nil, domain.NewValidationError("from", "must be before to")
+	}
+	if to.Sub(from) > domain.MaxReportRangeDays*24*time.Hour {
+		return nil, domain.NewValidationError("from", fmt.Sprintf("range can't be longer than %d days", domain.MaxReportRangeDays))
+	}
+
+	scope := domain.ReportScope{UserID: actor.ID}
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		scope.TenantID = &tenant
+	}
+	if actor.ManagesAllProjects() {
+		scope.UserID = query.UserID        // the whole workspace unless a user was asked for
+	} else if query.UserID != "" && query.UserID != actor.ID {
+		return nil, domain.ErrReportForbidden
+	}
+
+	report, err := reportUsc.reportRepo.Productivity(ctx, scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report.Scope = domain.ReportScopeOrganization
+	if scope.UserID != "" {
+		report.Scope = domain.ReportScopePersonal
+		report.UserID = scope.UserID
+	}
+
+	return report, nil
+}
+
+// monday 00:00 UTC of the week a time falls in
+func weekStart(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,204 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"fmt";
+	"strconv";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// session usecase (login sessions and the workspace limit on concurrent sessions)
+type SessionUseCase interface {
+	StartSession(ctx context.Context, user *domain.User) (*domain.Session, error)                                     // record a login, revoke the oldest sessions beyond the limit
+	ValidateSession(ctx context.Context, sessionID string) error                                                      // return ErrSessionRevoked unless the session is active
+	RevokeUserSessions(ctx context.Context, userID string, reason string) (int, error)                                // end every active session of a user
+	RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) (int, error)          // end every active session of a user except one
+	GetSecuritySettings(ctx context.Context) (*domain.SecuritySettings, error)                                        // saved settings or the environment defaults
+	UpdateSecuritySettings(ctx context.Context, security *domain.SecuritySettings) (*domain.SecuritySettings, error)  // save settings, applies to the next logins
+}
+
+type sessionUseCase struct {
+	sessionRepo   domain.SessionRepository
+	settingsRepo  domain.SettingsRepository
+	emailServ     domain.EmailService
+	auditSink     domain.AuditSink
+	logger        domain.Logger
+	maxSessions   int        // limit used until one is saved in the settings (0 for no limit)
+	readOnly      bool       // instance can't store sessions, logins get tokens without one
+}
+
+// creates new SessionUseCase instance
+func NewSessionUseCase(sessionRepo domain.SessionRepository, settingsRepo domain.SettingsRepository, emailServ domain.EmailService, auditSink domain.AuditSink, logger domain.Logger, maxSessions int, readOnly bool) SessionUseCase {
+	return &sessionUseCase{sessionRepo: sessionRepo, settingsRepo: settingsRepo, emailServ: emailServ, auditSink: auditSink, logger: logger, maxSessions: maxSessions, readOnly: readOnly}
+}
+
+// record a login, revoke the oldest sessions beyond the limit
+func (sessionUsc *sessionUseCase) StartSession(ctx context.Context, user *domain.User) (*domain.Session, error) {
+
+	now := time.Now().UTC()
+	session := &domain.Session{UserID: user.ID.Hex(), ClientIP: domain.ClientIPFromContext(ctx), CreatedAt: now, ExpiresAt: now.Add(domain.SessionTTL)}
+	if sessionUsc.readOnly {
+		return session, nil        // not stored, the token carries no session (and the limit isn't enforced)
+	}
+	if err := sessionUsc.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	settings, err := sessionUsc.GetSecuritySettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if settings.MaxSessions == 0 {
+		return session, nil
+	}
+
+	// oldest first, the new session is the last one
+	active, err := sessionUsc.sessionRepo.GetActiveSessions(ctx, session.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) <= settings.MaxSessions {
+		return session, nil
+	}
+	revoked := []primitive.ObjectID{}
+	for _, old := range active[:len(active)-settings.MaxSessions] {
+		if old.ID != session.ID {
+			revoked = append(revoked, old.ID)
+		}
+	}
+	if err := sessionUsc.sessionRepo.RevokeSessions(ctx, revoked, domain.SessionRevokedLimit, now); err != nil {
+		return nil, err
+	}
+
+	for _, sessionID := range revoked {
+		sessionUsc.auditSink.Emit(ctx, domain.AuditEvent{
+			Type:      domain.AuditSessionRevoked,
+			Outcome:   domain.AuditOutcomeSuccess,
+			ActorID:   user.ID.Hex(),
+			Actor:     user.Username,
+			TargetID:  sessionID.Hex(),
+			Details:   map[string]string{"reason": domain.SessionRevokedLimit, "max_sessions": strconv.Itoa(settings.MaxSessions)},
+		})
+	}
+	sessionUsc.notifyRevoked(ctx, user, len(revoked), settings.MaxSessions)
+
+	return session, nil
+}
+
+// return ErrSessionRevoked unless the session is active
+func (sessionUsc *sessionUseCase) ValidateSession(ctx context.Context, sessionID string) error {
+
+	session, err := sessionUsc.sessionRepo.GetSession(ctx, sessionID)
+	if err == domain.ErrSessionNotFound || err == domain.ErrInvalidSessionID {
+		return domain.ErrSessionRevoked        // expired sessions are removed by the database
+	}
+	if err != nil {
+		return err
+	}
+	if !session.IsActive(time.Now()) {
+		return domain.ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// end every active session of a user (their login tokens stop working)
+func (sessionUsc *sessionUseCase) RevokeUserSessions(ctx context.Context, userID string, reason string) (int, error) {
+	return sessionUsc.RevokeOtherSessions(ctx, userID, "", reason)
+}
+
+// end every active session of a user except the one a request came with
+func (sessionUsc *sessionUseCase) RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) (int, error) {
+
+	now := time.Now().UTC()
+	active, err := sessionUsc.sessionRepo.GetActiveSessions(ctx, userID, now)
+	if err != nil || len(active) == 0 {
+		return 0, err
+	}
+	revoked := make([]primitive.ObjectID, 0, len(active))
+	for _, session := range active {
+		if session.ID.Hex() != keepSessionID {
+			revoked = append(revoked, session.ID)
+		}
+	}
+	if len(revoked) == 0 {
+		return 0, nil
+	}
+	if err := sessionUsc.sessionRepo.RevokeSessions(ctx, revoked, reason, now); err != nil {
+		return 0, err
+	}
+
+	for _, sessionID := range revoked {
+		sessionUsc.auditSink.Emit(ctx, domain.AuditEvent{
+			Type:      domain.AuditSessionRevoked,
+			Outcome:   domain.AuditOutcomeSuccess,
+			TargetID:  sessionID.Hex(),
+			Details:   map[string]string{"reason": reason, "user_id": userID},
+		})
+	}
+
+	return len(revoked), nil
+}
+
+// saved settings or the environment defaults
+func (sessionUsc *sessionUseCase) GetSecuritySettings(ctx context.Context) (*domain.SecuritySettings, error) {
+
+	settings, err := sessionUsc.settingsRepo.GetSettings(ctx)
+	if err != nil && err != domain.ErrSettingsNotFound {
+		return nil, err
+	}
+	if settings != nil && settings.Security != nil {
+		return settings.Security, nil
+	}
+
+	return &domain.SecuritySettings{MaxSessions: sessionUsc.maxSessions}, nil
+}
+
+// save settings, applies to the next logins
+func (sessionUsc *sessionUseCase) UpdateSecuritySettings(ctx context.Context, security *domain.SecuritySettings) (*domain.SecuritySettings, error) {
+
+	if err := sessionUsc.settingsRepo.SaveSecuritySettings(ctx, security, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event := domain.AuditEvent{
+		Type:     domain.AuditSecurityUpdated,
+		Outcome:  domain.AuditOutcomeSuccess,
+		Details:  map[string]string{"max_sessions": strconv.Itoa(security.MaxSessions)},
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID, event.Actor = actor.ID, actor.Username
+	}
+	sessionUsc.auditSink.Emit(ctx, event)
+
+	return security, nil
+}
+
+// tell the user older sessions were ended (sent in the background, login doesn't wait for the email)
+func (sessionUsc *sessionUseCase) notifyRevoked(ctx context.Context, user *domain.User, revoked int, maxSessions int) {
+
+	if user.Email == "" {
+		sessionUsc.logger.Info(ctx, "older sessions revoked, user has no email", "user_id", user.ID.Hex(), "revoked", revoked)
+		return
+	}
+
+	body := fmt.Sprintf("A new sign-in to your account from %s ended %d older session(s), since this workspace allows %d at a time.\n\n"+
+		"If this wasn't you, change your password.", clientOrUnknown(domain.ClientIPFromContext(ctx)), revoked, maxSessions)
+	ctx = context.WithoutCancel(ctx)        // outlives the request
+	go func() {
+		if err := sessionUsc.emailServ.SendEmail(ctx, user.Email, "You were signed out on another device", body); err != nil {
+			sessionUsc.logger.Warn(ctx, "session limit notice not sent", "user_id", user.ID.Hex(), "error", err)
+		}
+	}()
+}
+
+// client address for messages
+func clientOrUnknown(ip string) string {
+	if ip == "" {
+		return "an unknown address"
+	}
+	return ip
+}
@@ -0,0 +1,222 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"crypto/subtle";
+	"fmt";
+	"sync";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// sample project created for the first admin (tasks are due relative to setup)
+var sampleProjectTasks = []struct {
+	title        string
+	description  string
+	priority     string
+	dueIn        time.Duration
+}{
+	{"Invite your team", "Send admin invites or create organizations, then add teammates to this project.", "high", 2 * 24 * time.Hour},
+	{"Create your own project", "Projects group tasks and decide who sees them. Delete this one once you don't need it.", "medium", 7 * 24 * time.Hour},
+	{"Read the API documentation", "docs/api_documentation.md lists every endpoint.", "low", 14 * 24 * time.Hour},
+}
+
+// setup usecase (creates the first admin of a fresh database with the default workspace and a sample project)
+type SetupUseCase interface {
+	Bootstrap(ctx context.Context, admin *domain.User) (string, error)                  // on a fresh database create the given admin, or return a one-time setup token when none is given
+	CompleteSetup(ctx context.Context, token string, admin *domain.User, settings *domain.InstanceSettings) error          // validate and save settings, then create the first admin with the setup token
+}
+
+type setupUseCase struct {
+	userRepo    domain.UserRepository
+	projectRepo domain.ProjectRepository
+	taskRepo    domain.TaskRepository
+	pwdService  domain.PasswordService
+	auditSink   domain.AuditSink
+	settingsRepo  domain.SettingsRepository
+	emailFactory  domain.EmailServiceFactory
+	logger      domain.Logger
+	mutex       sync.Mutex
+	tokenHash   string          // hash of the pending setup token (empty when none)
+}
+
+// creates new SetupUseCase instance
+func NewSetupUseCase(userRepo domain.UserRepository, projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, pwdServ domain.PasswordService, auditSink domain.AuditSink, settingsRepo domain.SettingsRepository, emailFactory domain.EmailServiceFactory, logger domain.Logger) SetupUseCase {
+	return &setupUseCase{userRepo: userRepo, projectRepo: projectRepo, taskRepo: taskRepo, pwdService: pwdServ, auditSink: auditSink, settingsRepo: settingsRepo, emailFactory: emailFactory, logger: logger}
+}
+
+// prepare a fresh database at startup
+func (setupUsc *setupUseCase) Bootstrap(ctx context.Context, admin *domain.User) (string, error) {
+
+	setupUsc.mutex.Lock()
+	defer setupUsc.mutex.Unlock()
+
+	count, err := setupUsc.userRepo.GetUserCount(ctx)
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", nil        // already set up
+	}
+
+	// admin credentials provided through configuration (another instance may have won the claim)
+	if admin != nil {
+		err := setupUsc.claimed(ctx, func(claimedAt time.Time) error {
+			settings := &domain.InstanceSettings{ID: domain.InstanceSettingsID, WorkspaceName: domain.DefaultWorkspaceName, SetupCompletedAt: &claimedAt, UpdatedAt: time.Now()}
+			if err := setupUsc.settingsRepo.SaveSettings(ctx, settings); err != nil {
+				return err
+			}
+			return setupUsc.createAdmin(ctx, admin, settings, "config")
+		})
+		if err == domain.ErrSetupCompleted {
+			return "", nil
+		}
+		return "", err
+	}
+
+	// otherwise the operator finishes setup over the api, token lives as long as the process
+	token, err := generateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	setupUsc.tokenHash = hashToken(token)
+
+	return token, nil
+}
+
+// validate and save settings, then create the first admin with the setup token
+func (setupUsc *setupUseCase) CompleteSetup(ctx context.Context, token string, admin *domain.User, settings *domain.InstanceSettings) error {
+
+	setupUsc.mutex.Lock()
+	defer setupUsc.mutex.Unlock()
+
+	count, err := setupUsc.userRepo.GetUserCount(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrSetupCompleted
+	}
+	if setupUsc.tokenHash == "" || subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(setupUsc.tokenHash)) != 1 {
+		return domain.ErrInvalidSetupToken
+	}
+
+	err = setupUsc.claimed(ctx, func(claimedAt time.Time) error {
+
+		// smtp settings must work before they are saved
+		if settings.SMTP != nil {
+			if admin.Email == "" {
+				return domain.NewValidationError("email", "required to test smtp settings")
+			}
+			body := "Your Task Manager installation can send email."
+			if err := setupUsc.emailFactory(*settings.SMTP).SendEmail(ctx, admin.Email, "Task Manager test email", body); err != nil {
+				return fmt.Errorf("%w: %v", domain.ErrSMTPTestFailed, err)
+			}
+		}
+
+		// saving settings also proves the database accepts writes
+		if settings.WorkspaceName == "" {
+			settings.WorkspaceName = domain.DefaultWorkspaceName
+		}
+		settings.SetupCompletedAt = &claimedAt
+		settings.UpdatedAt = time.Now()
+		if err := setupUsc.settingsRepo.SaveSettings(ctx, settings); err != nil {
+			return err
+		}
+
+		return setupUsc.createAdmin(ctx, admin, settings, "setup_token")
+	})
+	if err != nil {
+		return err
+	}
+	setupUsc.tokenHash = ""        // one-time token
+
+	return nil
+}
+
+// claim setup in the settings document so only one request or instance creates the first admin,
+// the claim is released when run fails so setup can be retried
+func (setupUsc *setupUseCase) claimed(ctx context.Context, run func(claimedAt time.Time) error) error {
+
+	claimedAt := time.Now()
+	if err := setupUsc.settingsRepo.ClaimSetup(ctx, claimedAt); err != nil {
+		return err
+	}
+
+	if err := run(claimedAt); err != nil {
+		setupUsc.settingsRepo.ReleaseSetup(context.WithoutCancel(ctx))        // best effort, the admin cli can still create an admin
+		return err
+	}
+
+	return nil
+}
+
+// store the first admin in the default workspace with a sample project and audit how it was created
+func (setupUsc *setupUseCase) createAdmin(ctx context.Context, admin *domain.User, settings *domain.InstanceSettings, method string) error {
+
+	// validate input
+	if admin.Username == "" {
+		return domain.NewValidationError("username", "cannot be empty")
+	}
+	if len(admin.Password) < 8 {
+		return domain.NewValidationError("password", "must be at least 8 characters")
+	}
+
+	// hash password securely 
+	hashed, err := setupUsc.pwdService.HashPassword(admin.Password)
+	if err != nil {
+		return err
+	}
+	admin.Password = hashed
+	admin.Role = domain.RoleAdmin
+
+	if err := setupUsc.userRepo.CreateUser(ctx, admin); err != nil {
+		return err
+	}
+
+	details := map[string]string{"role": domain.RoleAdmin, "method": method, "workspace_name": settings.WorkspaceName}
+	project, err := setupUsc.createSampleProject(ctx, admin)
+	if err != nil {
+		setupUsc.logger.Warn(ctx, "sample project not created", "error", err)        // the admin can start without it
+	} else {
+		details["sample_project_id"] = project.ID.Hex()
+	}
+
+	setupUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditSetupCompleted,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   admin.ID.Hex(),
+		Actor:     admin.Username,
+		Details:   details,
+	})
+
+	return nil
+}
+
+// project owned by the first admin with a few tasks to start from
+func (setupUsc *setupUseCase) createSampleProject(ctx context.Context, admin *domain.User) (*domain.Project, error) {
+
+	now := time.Now().UTC()
+	project := &domain.Project{
+		Name:         domain.SampleProjectName,
+		Description:  "A few tasks to get started.",
+		Members:      []domain.ProjectMember{{UserID: admin.ID.Hex(), Role: domain.ProjectRoleOwner}},
+		CreatedAt:    now,
+	}
+	if err := setupUsc.projectRepo.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(sampleProjectTasks))
+	for _, sample := range sampleProjectTasks {
+		task := &domain.Task{Title: sample.title, Description: sample.description, Status: domain.TaskStatusPending, Priority: sample.priority, Language: "en",
+			DueDate: now.Add(sample.dueIn).Truncate(time.Hour), ProjectID: &project.ID, StatusEnteredAt: map[string]time.Time{domain.TaskStatusPending: now}}
+		if err := task.ApplyPriority(); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return project, setupUsc.taskRepo.CreateTasks(ctx, tasks)
+}
@@ -0,0 +1,218 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+const tagJobProgressEvery = 50        // tasks retagged between progress saves
+
+// tag job usecase (rename and merge tags on all tasks in the background)
+type TagJobUseCase interface {
+	RenameTag(ctx context.Context, req domain.RenameTagRequest) (*domain.TagJob, error)        // rename a label and start retagging its tasks
+	MergeTags(ctx context.Context, req domain.MergeTagsRequest) (*domain.TagJob, error)        // start moving tasks from one label to another, source label is deleted at the end
+	GetJob(ctx context.Context, jobID string) (*domain.TagJob, error)                          // get job with its progress
+}
+
+type tagJobUseCase struct {
+	jobRepo       domain.TagJobRepository
+	labelRepo     domain.LabelRepository
+	taskRepo      domain.TaskRepository
+	auditLogRepo  domain.AuditLogRepository
+	logger        domain.Logger
+}
+
+// creates new TagJobUseCase instance
+func NewTagJobUseCase(jobRepo domain.TagJobRepository, labelRepo domain.LabelRepository, taskRepo domain.TaskRepository, auditLogRepo domain.AuditLogRepository, logger domain.Logger) TagJobUseCase {
+	return &tagJobUseCase{jobRepo: jobRepo, labelRepo: labelRepo, taskRepo: taskRepo, auditLogRepo: auditLogRepo, logger: logger}
+}
+
+// rename a label and start retagging its tasks
+func (tagJobUsc *tagJobUseCase) RenameTag(ctx context.Context, req domain.RenameTagRequest) (*domain.TagJob, error) {
+
+	// validate tag names
+	from, to := strings.TrimSpace(req.From), strings.TrimSpace(req.To)
+	if err := validateLabelName(to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, domain.ErrSameTag
+	}
+
+	labels, err := tagJobUsc.labelsByName(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	existing, ok := labels[from]
+	if !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+	if _, taken := labels[to]; taken {
+		return nil, domain.ErrLabelExists
+	}
+
+	// new tasks can use the new name right away, old tags are moved by the job
+	updated, err := tagJobUsc.labelRepo.UpdateLabel(ctx, existing.ID.Hex(), &domain.Label{Name: to})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, tagJobUsc.auditLogRepo, tagJobUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityLabel,
+		EntityID:    existing.ID.Hex(),
+		Before:      auditSnapshot(existing),
+		After:       auditSnapshot(updated),
+	})
+
+	return tagJobUsc.start(ctx, domain.TagJobRename, from, to, nil)
+}
+
+// start moving tasks from one label to another
+func (tagJobUsc *tagJobUseCase) MergeTags(ctx context.Context, req domain.MergeTagsRequest) (*domain.TagJob, error) {
+
+	source, target := strings.TrimSpace(req.Source), strings.TrimSpace(req.Target)
+	if source == target {
+		return nil, domain.ErrSameTag
+	}
+
+	labels, err := tagJobUsc.labelsByName(ctx, source, target)
+	if err != nil {
+		return nil, err
+	}
+	sourceLabel, ok := labels[source]
+	if !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+	if _, ok := labels[target]; !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+
+	// source label stays until its tasks are moved (tasks may only carry existing labels)
+	finish := func(ctx context.Context) error {
+		if err := tagJobUsc.labelRepo.DeleteLabel(ctx, sourceLabel.ID.Hex()); err != nil {
+			return err
+		}
+		recordAuditLog(ctx, tagJobUsc.auditLogRepo, tagJobUsc.logger, domain.AuditLogEntry{
+			Action:      domain.AuditActionDelete,
+			EntityType:  domain.AuditEntityLabel,
+			EntityID:    sourceLabel.ID.Hex(),
+			Before:      auditSnapshot(sourceLabel),
+			Details:     map[string]string{"merged_into": target},
+		})
+		return nil
+	}
+
+	return tagJobUsc.start(ctx, domain.TagJobMerge, source, target, finish)
+}
+
+// get job with its progress
+func (tagJobUsc *tagJobUseCase) GetJob(ctx context.Context, jobID string) (*domain.TagJob, error) {
+	return tagJobUsc.jobRepo.GetJob(ctx, jobID)
+}
+
+// labels with the given names keyed by name
+func (tagJobUsc *tagJobUseCase) labelsByName(ctx context.Context, names ...string) (map[string]*domain.Label, error) {
+
+	labels, err := tagJobUsc.labelRepo.GetLabelsByName(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*domain.Label, len(labels))
+	for i := range labels {
+		byName[labels[i].Name] = &labels[i]
+	}
+
+	return byName, nil
+}
+
+// store job and retag tasks in the background (finish runs once every task is moved)
+func (tagJobUsc *tagJobUseCase) start(ctx context.Context, kind string, from string, to string, finish func(ctx context.Context) error) (*domain.TagJob, error) {
+
+	tasks, err := tagJobUsc.taskRepo.GetAllTasks(ctx, domain.TaskQuery{Labels: []string{from}})
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.TagJob{Kind: kind, From: from, To: to, Status: domain.TagJobRunning, Total: len(tasks), StartedAt: time.Now().UTC()}
+	actor, hasActor := domain.ActorFromContext(ctx)
+	if hasActor {
+		job.ActorID = actor.ID
+	}
+	if err := tagJobUsc.jobRepo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	started := *job
+
+	// outlives the request, keeps the actor for task history and audit log
+	jobCtx := context.Background()
+	if hasActor {
+		jobCtx = domain.ContextWithActor(jobCtx, actor)
+	}
+	go tagJobUsc.run(jobCtx, job, tasks, finish)
+
+	return &started, nil
+}
+
+// retag every task and record the outcome
+func (tagJobUsc *tagJobUseCase) run(ctx context.Context, job *domain.TagJob, tasks []domain.Task, finish func(ctx context.Context) error) {
+
+	err := func() error {
+		if err := tagJobUsc.retag(ctx, job, tasks); err != nil {
+			return err
+		}
+
+		// tasks tagged while the job ran (the old name stays valid for merges)
+		leftovers, err := tagJobUsc.taskRepo.GetAllTasks(ctx, domain.TaskQuery{Labels: []string{job.From}})
+		if err != nil {
+			return err
+		}
+		job.Total += len(leftovers)
+		if err := tagJobUsc.retag(ctx, job, leftovers); err != nil {
+			return err
+		}
+
+		if finish != nil {
+			return finish(ctx)
+		}
+		return nil
+	}()
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	job.Status = domain.TagJobCompleted
+	if err != nil {
+		job.Status = domain.TagJobFailed
+		job.Error = err.Error()
+		tagJobUsc.logger.Error(ctx, "tag job failed", "job_id", job.ID.Hex(), "kind", job.Kind, "from", job.From, "to", job.To, "error", err)
+	}
+	if err := tagJobUsc.jobRepo.UpdateJob(ctx, job); err != nil {
+		tagJobUsc.logger.Error(ctx, "tag job result not saved", "job_id", job.ID.Hex(), "error", err)
+	}
+}
+
+// move tasks from the old tag to the new one (add before remove so no task loses the tag entirely)
+func (tagJobUsc *tagJobUseCase) retag(ctx context.Context, job *domain.TagJob, tasks []domain.Task) error {
+
+	for _, task := range tasks {
+		if _, err := tagJobUsc.taskRepo.AddTag(ctx, task.ID.Hex(), job.To); err != nil && err != domain.ErrTaskNotFound {
+			return err
+		}
+		if _, err := tagJobUsc.taskRepo.RemoveTag(ctx, task.ID.Hex(), job.From); err != nil && err != domain.ErrTaskNotFound {
+			return err
+		}
+
+		job.Processed++
+		if job.Processed%tagJobProgressEvery == 0 {
+			if err := tagJobUsc.jobRepo.UpdateJob(ctx, job); err != nil {
+				tagJobUsc.logger.Warn(ctx, "tag job progress not saved", "job_id", job.ID.Hex(), "error", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// completed tasks archived per batch (each batch is one unit of work)
+const taskArchiveBatchSize = 200
+
+// task archive usecase (completed tasks left unchanged for the retention are moved to the archive)
+type TaskArchiveUseCase interface {
+	ArchiveCompletedTasks(ctx context.Context) error                                                        // move completed tasks past the retention (scheduled job)
+	GetArchivedTasks(ctx context.Context, before time.Time, limit int64) ([]domain.ArchivedTask, error)     // archived tasks the caller may see, newest first
+	UnarchiveTask(ctx context.Context, taskID string) (*domain.Task, error)                                 // move a task back to the task list
+}
+
+type taskArchiveUseCase struct {
+	archiveRepo  domain.TaskArchiveRepository
+	projectRepo  domain.ProjectRepository        // archived tasks of a project are only shown to its members
+	unitOfWork   domain.UnitOfWork
+	logger       domain.Logger
+	handlers     []domain.TaskEventHandler       // same handlers as task commands (search index, webhooks, audit log)
+	retention    time.Duration                   // completed tasks unchanged this long are archived (0 never archives)
+}
+
+// creates new TaskArchiveUseCase instance
+func NewTaskArchiveUseCase(archiveRepo domain.TaskArchiveRepository, projectRepo domain.ProjectRepository, unitOfWork domain.UnitOfWork, logger domain.Logger, retentionDays int, handlers ...domain.TaskEventHandler) TaskArchiveUseCase {
+	return &taskArchiveUseCase{archiveRepo: archiveRepo, projectRepo: projectRepo, unitOfWork: unitOfWork, logger: logger, handlers: handlers,
+		retention: time.Duration(retentionDays) * 24 * time.Hour}
+}
+
+// archive completed tasks in batches until none is left
+func (archiveUsc *taskArchiveUseCase) ArchiveCompletedTasks(ctx context.Context) error {
+
+	if archiveUsc.retention <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	archived := 0
+	for {
+		var moved []domain.Task
+		err := archiveUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			tasks, err := archiveUsc.archiveRepo.FindArchivable(ctx, now.Add(-archiveUsc.retention), taskArchiveBatchSize)
+			if err != nil {
+				return err
+			}
+			moved, err = archiveUsc.archiveRepo.Archive(ctx, tasks, now)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range moved {
+			archiveUsc.publish(ctx, domain.TaskEvent{Type: domain.TaskEventArchived, TaskID: moved[i].ID.Hex(), Before: &moved[i]})
+		}
+		archived += len(moved)
+		if len(moved) < taskArchiveBatchSize {
+			break        // tasks changed while being archived are left for the next run
+		}
+	}
+
+	if archived > 0 {
+		archiveUsc.logger.Info(ctx, "completed tasks archived", "tasks", archived)
+	}
+	return nil
+}
+
+// archived tasks of the caller's organization and projects
+func (archiveUsc *taskArchiveUseCase) GetArchivedTasks(ctx context.Context, before time.Time, limit int64) ([]domain.ArchivedTask, error) {
+
+	if limit <= 0 {
+		limit = domain.DefaultArchiveLimit
+	}
+	if limit > domain.MaxArchiveLimit {
+		limit = domain.MaxArchiveLimit
+	}
+	var scope domain.TaskQuery
+	if err := scopeTaskQuery(ctx, archiveUsc.projectRepo, &scope); err != nil {
+		return nil, err
+	}
+	query := domain.ArchiveQuery{Visibility: scope.Visibility, Before: before, Limit: limit}
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		query.TenantID = &tenant
+	}
+
+	return archiveUsc.archiveRepo.GetArchivedTasks(ctx, query)
+}
+
+// restore an archived task the caller may see
+func (archiveUsc *taskArchiveUseCase) UnarchiveTask(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	archived, err := archiveUsc.archiveRepo.GetArchivedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, archived.TenantID) {
+		return nil, domain.ErrArchivedTaskNotFound
+	}
+	if err := checkTaskVisible(ctx, archiveUsc.projectRepo, &archived.Task); err != nil {
+		if err == domain.ErrTaskNotFound {
+			return nil, domain.ErrArchivedTaskNotFound
+		}
+		return nil, err
+	}
+
+	var task *domain.Task
+	err = archiveUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		task, err = archiveUsc.archiveRepo.Restore(ctx, taskID, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archiveUsc.publish(ctx, domain.TaskEvent{Type: domain.TaskEventUnarchived, TaskID: taskID, After: task})
+	return task, nil
+}
+
+// pass an archive change to the task event handlers
+func (archiveUsc *taskArchiveUseCase) publish(ctx context.Context, event domain.TaskEvent) {
+	for _, handler := range archiveUsc.handlers {
+		handler.HandleTaskEvent(ctx, event)
+	}
+}
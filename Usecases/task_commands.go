@@ -0,0 +1,489 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"fmt";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// task command usecase (state changes, validated and published as events)
+type TaskCommandUseCase interface {
+	CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, bool, error)               // create new task with validation, false when a task with its client id already exists (returned as is)
+	ImportTasks(ctx context.Context, rows []domain.TaskImportRow) (*domain.TaskImportReport, error)      // validate rows like new tasks and create the valid ones at once
+	DeleteTask(ctx context.Context, taskID string, cascade bool) error                 	     // delete existing task (and its subtasks when cascading) or return error if not found
+	UpdateTask(ctx context.Context, taskID string, update *domain.TaskUpdate) (*domain.Task, []domain.TaskFieldChange, error)      // update the set fields of an existing task and return the changed fields or error if not found
+	PatchTask(ctx context.Context, taskID string, input *domain.UpdateTaskInput) (*domain.Task, []domain.TaskFieldChange, error)      // change only the fields set on the input, same rules as UpdateTask
+}
+
+type taskCommandUseCase struct {
+	taskRepo    domain.TaskRepository
+	labelRepo    domain.LabelRepository
+	projectRepo  domain.ProjectRepository        // tasks of a project are changed by its editors and owners
+	lockRepo     domain.TaskLockRepository       // updates are rejected while another user edits the task
+	dependencyRepo domain.TaskDependencyRepository       // tasks are only completed once their blockers are (nil allows completing blocked tasks)
+	extensions   domain.ExtensionHooks
+	detector     domain.LanguageDetector         // tags tasks with the language of their text
+	workflow     domain.TaskWorkflow             // status changes updates may make
+	trashRepo    domain.TaskTrashRepository      // deleted tasks are kept until purged (nil deletes them for good)
+	unitOfWork   domain.UnitOfWork
+	handlers     []domain.TaskEventHandler
+}
+
+// creates new TaskCommandUseCase instance
+func NewTaskCommandUseCase(repo domain.TaskRepository, labelRepo domain.LabelRepository, projectRepo domain.ProjectRepository, lockRepo domain.TaskLockRepository, dependencyRepo domain.TaskDependencyRepository, extensions domain.ExtensionHooks, detector domain.LanguageDetector, workflow domain.TaskWorkflow, trashRepo domain.TaskTrashRepository, unitOfWork domain.UnitOfWork, handlers ...domain.TaskEventHandler) TaskCommandUseCase {
+	return &taskCommandUseCase{taskRepo: repo, labelRepo: labelRepo, projectRepo: projectRepo, lockRepo: lockRepo, dependencyRepo: dependencyRepo, extensions: extensions, detector: detector, workflow: workflow, trashRepo: trashRepo, unitOfWork: unitOfWork, handlers: handlers}
+}
+
+// create a task
+func (taskCmd *taskCommandUseCase) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, bool, error) {
+
+	// offline clients resend tasks until they see them created, a retry gets the first task back
+	task.ClientID = strings.ToLower(task.ClientID)
+	if task.ClientID != "" {
+		existing, err := taskCmd.taskRepo.GetTaskByClientID(ctx, task.ClientID)
+		if err == nil {
+			return existing, false, nil
+		}
+		if err != domain.ErrTaskNotFound {
+			return nil, false, err
+		}
+	}
+
+	if err := taskCmd.prepareTask(ctx, task); err != nil {
+		return nil, false, err
+	}
+
+	created, err := taskCmd.taskRepo.CreateTask(ctx, task)
+	if err == domain.ErrTaskClientIDExists {
+		existing, err := taskCmd.taskRepo.GetTaskByClientID(ctx, task.ClientID)       // a concurrent retry won
+		return existing, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	taskCmd.publish(ctx, domain.TaskEvent{
+		Type:    domain.TaskEventCreated,
+		TaskID:  created.ID.Hex(),
+		After:   created,
+	})
+
+	return created, true, nil
+}
+
+// validate rows like new tasks and create the valid ones in one repository call
+func (taskCmd *taskCommandUseCase) ImportTasks(ctx context.Context, rows []domain.TaskImportRow) (*domain.TaskImportReport, error) {
+
+	if len(rows) > domain.MaxImportRows {
+		return nil, domain.ErrTooManyImportRows
+	}
+
+	report := &domain.TaskImportReport{Rows: make([]domain.TaskImportResult, len(rows))}
+	valid := []*domain.Task{}
+	validIndex := []int{}
+	for i, row := range rows {
+		report.Rows[i].Row = row.Row
+		if row.Error == "" {
+			if err := taskCmd.prepareTask(ctx, row.Task); err != nil {
+				row.Error = err.Error()
+			}
+		}
+		if row.Error != "" {
+			report.Rows[i].Error = row.Error
+			report.Failed++
+			continue
+		}
+		valid = append(valid, row.Task)
+		validIndex = append(validIndex, i)
+	}
+
+	if err := taskCmd.taskRepo.CreateTasks(ctx, valid); err != nil {
+		return nil, err
+	}
+
+	for i, task := range valid {
+		report.Rows[validIndex[i]].TaskID = task.ID.Hex()
+		taskCmd.publish(ctx, domain.TaskEvent{
+			Type:    domain.TaskEventCreated,
+			TaskID:  task.ID.Hex(),
+			After:   task,
+		})
+	}
+	report.Created = len(valid)
+
+	return report, nil
+}
+
+// apply extensions and defaults to a new task and validate it
+func (taskCmd *taskCommandUseCase) prepareTask(ctx context.Context, task *domain.Task) error {
+
+	// extensions may adjust or reject the task, their changes are validated below
+	if err := taskCmd.extensions.PreTaskCreate(ctx, task); err != nil {
+		return err
+	}
+
+	// validate task fields before creation, every broken rule is reported
+	invalid := &domain.ValidationError{}
+	if task.Title == "" {
+		invalid.Add("title", "cannot be empty")
+	}
+	if task.Description == "" {
+		invalid.Add("description", "cannot be empty")
+	}
+	if task.DueDate.IsZero() {
+		invalid.Add("due_date", "cannot be empty")
+	} else if time.Until(task.DueDate) < 0 {
+		invalid.Add("due_date", "must be in the future")       // validate due date is in the future
+	}
+	if task.Status == "" {
+		task.Status = domain.TaskStatusPending      // default status
+	}
+	// validate status is one of allowed values
+	if !domain.IsTaskStatus(task.Status) {
+		invalid.Add("status", "must be one of "+strings.Join(domain.TaskStatuses, ", "))
+	}
+	task.StatusEnteredAt = map[string]time.Time{task.Status: time.Now().UTC()}
+	if task.Priority == "" {
+		task.Priority = domain.DefaultTaskPriority      // default priority
+	}
+	// validate priority is one of allowed values
+	if err := task.ApplyPriority(); err != nil {
+		invalid.Add("priority", "must be one of low, medium, high, urgent")
+	}
+	// validate language, detect it when not given
+	if task.Language != "" && !domain.IsTaskLanguage(task.Language) {
+		invalid.Add("language", "must be one of "+strings.Join(domain.TaskLanguages, ", "))
+	}
+	// validate recurrence rule
+	if task.Recurrence != nil {
+		if err := task.Recurrence.Validate(); err != nil {
+			invalid.Add("recurrence", err.Error())
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		return err
+	}
+	if task.Language == "" {
+		task.Language = taskCmd.detector.Detect(task.Title + "\n" + task.Description)
+	}
+	// validate parent exists when creating a subtask
+	if task.ParentID != nil {
+		if err := taskCmd.checkParentExists(ctx, task.ParentID.Hex()); err != nil {
+			return err
+		}
+	}
+	// validate attached labels exist
+	tags, err := taskCmd.checkLabels(ctx, task.Tags)
+	if err != nil {
+		return err
+	}
+	task.Tags = tags
+	// validate the caller may add tasks to the project
+	if err := checkProjectTasks(ctx, taskCmd.projectRepo, task.ProjectID); err != nil {
+		return err
+	}
+	// a new recurring task starts its own series
+	if task.Recurrence != nil {
+		if task.ID.IsZero() {
+			task.ID = primitive.NewObjectID()
+		}
+		task.Recurrence.SeriesID = task.ID
+		task.Recurrence.AdvancedAt = nil
+	}
+
+	return nil
+}
+// remove task by its id
+func (taskCmd *taskCommandUseCase) DeleteTask(ctx context.Context, id string, cascade bool) error {
+	
+	// validate id field 
+	if id == "" {
+		return errors.New("task ID cannot be empty")
+	}
+	// verify task exists first
+	existing, err := taskCmd.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrTaskNotFound {
+			return domain.ErrTaskNotFound
+		}
+		return err
+	}
+	if err := taskCmd.checkTaskEditable(ctx, existing); err != nil {
+		return err
+	}
+
+	// block deletion of tasks with subtasks unless cascading
+	descendants, err := taskCmd.taskRepo.GetDescendantIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(descendants) > 0 && !cascade {
+		return domain.ErrTaskHasSubtasks
+	}
+
+	// subtasks and task go together, or none of them
+	err = taskCmd.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := taskCmd.moveToTrash(ctx, existing, descendants); err != nil {
+			return err
+		}
+		if len(descendants) > 0 {
+			if err := taskCmd.taskRepo.DeleteTasks(ctx, descendants); err != nil {
+				return err
+			}
+		}
+		return taskCmd.taskRepo.DeleteTask(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	event := domain.TaskEvent{
+		Type:    domain.TaskEventDeleted,
+		TaskID:  id,
+		Before:  existing,
+	}
+	if len(descendants) > 0 {
+		event.Details = map[string]string{"cascaded_subtasks": strconv.Itoa(len(descendants))}
+	}
+	if taskCmd.trashRepo != nil {
+		if event.Details == nil {
+			event.Details = map[string]string{}
+		}
+		event.Details["trashed"] = "true"        // kept in the trash until purged
+	}
+	taskCmd.publish(ctx, event)
+
+	return nil
+}
+
+// keep copies of a task and its subtasks in the trash before they are deleted
+func (taskCmd *taskCommandUseCase) moveToTrash(ctx context.Context, task *domain.Task, descendants []primitive.ObjectID) error {
+
+	if taskCmd.trashRepo == nil {
+		return nil
+	}
+
+	tasks := []domain.Task{*task}
+	if len(descendants) > 0 {
+		subtasks, err := taskCmd.taskRepo.GetAllTasks(ctx, domain.TaskQuery{IDs: descendants})
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, subtasks...)
+	}
+
+	return trashTasks(ctx, taskCmd.trashRepo, tasks)
+}
+
+// update task by its id
+func (taskCmd *taskCommandUseCase) UpdateTask(ctx context.Context, id string, update *domain.TaskUpdate) (*domain.Task, []domain.TaskFieldChange, error) {
+	
+	// validate id field 
+	if id == "" {
+		return nil, nil, errors.New("task ID cannot be empty")
+	}
+	// stop if nothing valid to update
+	if update.Empty() {
+		return nil, nil, domain.NewValidationError("body", "no valid fields provided for update")
+	}
+	invalid := &domain.ValidationError{}
+	update.StatusEnteredAt = nil        // set below when the status changes
+	// validate title if provided
+	if update.Title != nil && *update.Title == "" {
+		invalid.Add("title", "can't be removed")
+	}
+	// validate status if provided
+	if update.Status != nil && !domain.IsTaskStatus(*update.Status) {
+		invalid.Add("status", "must be one of "+strings.Join(domain.TaskStatuses, ", "))
+	}
+	// validate priority if provided and set its sortable rank
+	if update.Priority != nil {
+		rank, ok := domain.TaskPriorities[*update.Priority]
+		if !ok {
+			invalid.Add("priority", "must be one of low, medium, high, urgent")
+		}
+		update.PriorityRank = rank
+	}
+	// validate language if provided
+	if update.Language != nil && !domain.IsTaskLanguage(*update.Language) {
+		invalid.Add("language", "must be one of "+strings.Join(domain.TaskLanguages, ", "))
+	}
+	// validate due date if provided
+	if update.DueDate != nil && time.Until(*update.DueDate) < 0 {
+		invalid.Add("due_date", "must be in the future")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, nil, err
+	}
+	// validate new parent doesn't create a cycle
+	if update.ParentID != nil && !update.ParentID.IsZero() {
+		if err := taskCmd.checkParent(ctx, id, update.ParentID.Hex()); err != nil {
+			return nil, nil, err
+		}
+	}
+	// validate replacement labels exist
+	if update.Tags != nil {
+		tags, err := taskCmd.checkLabels(ctx, *update.Tags)
+		if err != nil {
+			return nil, nil, err
+		}
+		update.Tags = &tags
+	}
+
+	// keep the current state for event handlers
+	existing, err := taskCmd.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the caller edited an older version (checked again when stored)
+	if update.ExpectedUpdatedAt != nil && !existing.UpdatedAt.Equal(*update.ExpectedUpdatedAt) {
+		return nil, nil, domain.ErrTaskModified
+	}
+	// moving a task needs edit access to both projects (taking it out of its project only to the current one)
+	if err := taskCmd.checkTaskEditable(ctx, existing); err != nil {
+		return nil, nil, err
+	}
+	if update.ProjectID != nil && !update.ProjectID.IsZero() {
+		if err := checkProjectTasks(ctx, taskCmd.projectRepo, update.ProjectID); err != nil {
+			return nil, nil, err
+		}
+	}
+	// someone else has the task open for editing
+	if err := checkTaskLock(ctx, taskCmd.lockRepo, id); err != nil {
+		return nil, nil, err
+	}
+	// status changes follow the workflow, the time a status is entered is kept
+	if update.Status != nil && *update.Status != existing.Status {
+		if !taskCmd.workflow.Allows(existing.Status, *update.Status) {
+			return nil, nil, fmt.Errorf("%w: %s -> %s, allowed from %s: %s", domain.ErrInvalidTransition, existing.Status, *update.Status, existing.Status, allowedStatuses(taskCmd.workflow.Next(existing.Status)))
+		}
+		update.StatusEnteredAt = map[string]time.Time{*update.Status: time.Now().UTC()}
+	}
+	// blocked tasks wait for their blockers to be completed
+	if taskCmd.dependencyRepo != nil && update.Status != nil && *update.Status == domain.TaskStatusCompleted && existing.Status != domain.TaskStatusCompleted {
+		if err := checkBlockersResolved(ctx, taskCmd.dependencyRepo, taskCmd.taskRepo, id); err != nil {
+			return nil, nil, err
+		}
+	}
+	// changed text may be in another language (an unclear text keeps the current one)
+	if update.Language == nil && (update.Title != nil || update.Description != nil) {
+		title, description := existing.Title, existing.Description
+		if update.Title != nil {
+			title = *update.Title
+		}
+		if update.Description != nil {
+			description = *update.Description
+		}
+		if language := taskCmd.detector.Detect(title + "\n" + description); language != existing.Language {
+			update.Language = &language
+		}
+	}
+
+	updated, err := taskCmd.taskRepo.UpdateTask(ctx, id, update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	taskCmd.publish(ctx, domain.TaskEvent{
+		Type:    domain.TaskEventUpdated,
+		TaskID:  id,
+		Before:  existing,
+		After:   updated,
+	})
+
+	return updated, domain.DiffTasks(existing, updated), nil      // changed fields, old -> new
+}
+// partial update of a task, only the fields set on the input change
+func (taskCmd *taskCommandUseCase) PatchTask(ctx context.Context, id string, input *domain.UpdateTaskInput) (*domain.Task, []domain.TaskFieldChange, error) {
+
+	update, err := input.ToTaskUpdate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return taskCmd.UpdateTask(ctx, id, update)
+}
+// verify the caller may change a task (tasks of projects they are not a member of are not found)
+func (taskCmd *taskCommandUseCase) checkTaskEditable(ctx context.Context, task *domain.Task) error {
+	if err := checkTaskVisible(ctx, taskCmd.projectRepo, task); err != nil {
+		return err
+	}
+	return checkProjectTasks(ctx, taskCmd.projectRepo, task.ProjectID)
+}
+// verify parent task exists
+func (taskCmd *taskCommandUseCase) checkParentExists(ctx context.Context, parentID string) error {
+	_, err := taskCmd.taskRepo.GetTaskByID(ctx, parentID)
+	if err == domain.ErrTaskNotFound {
+		return domain.ErrParentNotFound
+	}
+	return err
+}
+// verify parent exists and isn't the task itself or one of its descendants
+func (taskCmd *taskCommandUseCase) checkParent(ctx context.Context, taskID string, parentID string) error {
+	
+	if taskID == parentID {
+		return domain.ErrTaskCycle
+	}
+	if err := taskCmd.checkParentExists(ctx, parentID); err != nil {
+		return err
+	}
+
+	descendants, err := taskCmd.taskRepo.GetDescendantIDs(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant.Hex() == parentID {
+			return domain.ErrTaskCycle
+		}
+	}
+
+	return nil
+}
+// verify every tag names an existing label, returns the tags without duplicates
+func (taskCmd *taskCommandUseCase) checkLabels(ctx context.Context, tags []string) ([]string, error) {
+	
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+
+	labels, err := taskCmd.labelRepo.GetLabelsByName(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) != len(unique) {
+		return nil, domain.ErrLabelNotFound
+	}
+
+	return unique, nil
+}
+
+// hand a stored change to every event handler in order
+func (taskCmd *taskCommandUseCase) publish(ctx context.Context, event domain.TaskEvent) {
+	for _, handler := range taskCmd.handlers {
+		handler.HandleTaskEvent(ctx, event)
+	}
+}
+
+// statuses listed in an invalid transition error
+func allowedStatuses(statuses []string) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	return strings.Join(statuses, ", ")
+}
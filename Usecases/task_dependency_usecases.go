@@ -0,0 +1,185 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"fmt";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// task dependency usecase ("blocked by" links between tasks the caller can see, never circular)
+type TaskDependencyUseCase interface {
+	AddDependency(ctx context.Context, taskID string, blockerID string) (*domain.TaskDependency, error)      // mark a task as blocked by another one
+	RemoveDependency(ctx context.Context, taskID string, blockerID string) error                             // unlink two tasks
+	GetDependencies(ctx context.Context, taskID string) (*domain.TaskDependencies, error)                    // blockers of a task and the tasks it blocks
+}
+
+type taskDependencyUseCase struct {
+	dependencyRepo  domain.TaskDependencyRepository
+	taskQuery       TaskQueryUseCase        // only tasks the caller can see are linked and listed
+}
+
+// creates new TaskDependencyUseCase instance
+func NewTaskDependencyUseCase(dependencyRepo domain.TaskDependencyRepository, taskQuery TaskQueryUseCase) TaskDependencyUseCase {
+	return &taskDependencyUseCase{dependencyRepo: dependencyRepo, taskQuery: taskQuery}
+}
+
+// link a task to a blocker, refusing links that close a cycle
+func (dependencyUsc *taskDependencyUseCase) AddDependency(ctx context.Context, taskID string, blockerID string) (*domain.TaskDependency, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	task, err := dependencyUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if blockerID == taskID {
+		return nil, domain.ErrDependencyCycle
+	}
+	blocker, err := dependencyUsc.taskQuery.GetTaskByID(ctx, blockerID)
+	if err == domain.ErrTaskNotFound || err == domain.ErrInvalidTaskID {
+		return nil, domain.NewValidationError("blocker_id", "must be the id of a task")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blocker.TenantID != task.TenantID {
+		return nil, domain.NewValidationError("blocker_id", "must be a task of the same organization")
+	}
+	if err := dependencyUsc.checkCycle(ctx, taskID, blockerID); err != nil {
+		return nil, err
+	}
+
+	dependency := &domain.TaskDependency{TaskID: taskID, BlockerID: blockerID, TenantID: task.TenantID, CreatedBy: actor.ID, CreatedAt: time.Now().UTC()}
+	if err := dependencyUsc.dependencyRepo.AddDependency(ctx, dependency); err != nil {
+		return nil, err
+	}
+
+	return dependency, nil
+}
+
+// a link closes a cycle when the blocker already waits for the task, directly or through other tasks
+func (dependencyUsc *taskDependencyUseCase) checkCycle(ctx context.Context, taskID string, blockerID string) error {
+
+	seen := map[string]bool{blockerID: true}
+	waiting := []string{blockerID}
+	for len(waiting) > 0 {
+		dependencies, err := dependencyUsc.dependencyRepo.GetBlockers(ctx, waiting)
+		if err != nil {
+			return err
+		}
+		waiting = nil
+		for _, dependency := range dependencies {
+			if dependency.BlockerID == taskID {
+				return domain.ErrDependencyCycle
+			}
+			if !seen[dependency.BlockerID] {
+				seen[dependency.BlockerID] = true
+				waiting = append(waiting, dependency.BlockerID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// unlink a task from a blocker
+func (dependencyUsc *taskDependencyUseCase) RemoveDependency(ctx context.Context, taskID string, blockerID string) error {
+
+	if _, err := dependencyUsc.taskQuery.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+
+	return dependencyUsc.dependencyRepo.RemoveDependency(ctx, taskID, blockerID)
+}
+
+// blockers of a task and the tasks waiting for it (tasks the caller can't see are left out)
+func (dependencyUsc *taskDependencyUseCase) GetDependencies(ctx context.Context, taskID string) (*domain.TaskDependencies, error) {
+
+	task, err := dependencyUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	blockers, err := dependencyUsc.dependencyRepo.GetBlockers(ctx, []string{taskID})
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := dependencyUsc.dependencyRepo.GetBlocked(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.TaskDependencies{TaskID: taskID, BlockedBy: []domain.DependencyTask{}, Blocks: []domain.DependencyTask{}}
+	for _, dependency := range blockers {
+		blocker, err := dependencyUsc.taskQuery.GetTaskByID(ctx, dependency.BlockerID)
+		if err == domain.ErrTaskNotFound {
+			continue        // hidden, archived or in the trash
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolved := blocker.Status == domain.TaskStatusCompleted
+		result.Blocked = result.Blocked || !resolved
+		result.BlockedBy = append(result.BlockedBy, domain.DependencyTask{TaskID: dependency.BlockerID, Title: blocker.Title, Status: blocker.Status, Resolved: resolved})
+	}
+	for _, dependency := range blocked {
+		waiting, err := dependencyUsc.taskQuery.GetTaskByID(ctx, dependency.TaskID)
+		if err == domain.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Blocks = append(result.Blocks, domain.DependencyTask{TaskID: dependency.TaskID, Title: waiting.Title, Status: waiting.Status, Resolved: task.Status == domain.TaskStatusCompleted})
+	}
+
+	return result, nil
+}
+
+// verify every blocker of a task is completed (blockers gone from the task list count as done)
+func checkBlockersResolved(ctx context.Context, dependencyRepo domain.TaskDependencyRepository, taskReader domain.TaskReader, taskID string) error {
+
+	dependencies, err := dependencyRepo.GetBlockers(ctx, []string{taskID})
+	if err != nil {
+		return err
+	}
+	for _, dependency := range dependencies {
+		blocker, err := taskReader.GetTaskByID(ctx, dependency.BlockerID)
+		if err == domain.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if blocker.Status != domain.TaskStatusCompleted {
+			return fmt.Errorf("%w (%s)", domain.ErrTaskBlocked, blocker.Title)
+		}
+	}
+
+	return nil
+}
+
+// drops the dependencies of deleted tasks (tasks in the trash keep theirs until purged)
+type taskDependencyTaskEventHandler struct {
+	dependencyRepo  domain.TaskDependencyRepository
+	logger          domain.Logger
+}
+
+// creates task event handler removing dependencies of deleted tasks
+func NewTaskDependencyTaskEventHandler(dependencyRepo domain.TaskDependencyRepository, logger domain.Logger) domain.TaskEventHandler {
+	return &taskDependencyTaskEventHandler{dependencyRepo: dependencyRepo, logger: logger}
+}
+
+func (handler *taskDependencyTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	trashed := event.Type == domain.TaskEventDeleted && event.Details["trashed"] == "true"
+	if event.Type != domain.TaskEventPurged && (event.Type != domain.TaskEventDeleted || trashed) {
+		return
+	}
+	if err := handler.dependencyRepo.DeleteTaskDependencies(ctx, event.TaskID); err != nil {
+		handler.logger.Error(ctx, "failed to delete dependencies of task", "task_id", event.TaskID, "error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// audit log action recorded for each task event
+var taskEventAuditActions = map[string]string{
+	domain.TaskEventCreated:  domain.AuditActionCreate,
+	domain.TaskEventUpdated:  domain.AuditActionUpdate,
+	domain.TaskEventDeleted:  domain.AuditActionDelete,
+	domain.TaskEventArchived:  domain.AuditActionArchive,
+	domain.TaskEventUnarchived:  domain.AuditActionUnarchive,
+	domain.TaskEventRestored:  domain.AuditActionRestore,
+	domain.TaskEventPurged:  domain.AuditActionPurge,
+}
+
+// records task events in the audit log
+type auditLogTaskEventHandler struct {
+	auditLogRepo  domain.AuditLogRepository
+	logger        domain.Logger
+}
+
+// creates task event handler writing to the audit log
+func NewAuditLogTaskEventHandler(auditLogRepo domain.AuditLogRepository, logger domain.Logger) domain.TaskEventHandler {
+	return &auditLogTaskEventHandler{auditLogRepo: auditLogRepo, logger: logger}
+}
+
+func (handler *auditLogTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	action, ok := taskEventAuditActions[event.Type]
+	if !ok {
+		return
+	}
+
+	recordAuditLog(ctx, handler.auditLogRepo, handler.logger, domain.AuditLogEntry{
+		Action:      action,
+		EntityType:  domain.AuditEntityTask,
+		EntityID:    event.TaskID,
+		Before:      auditSnapshot(event.Before),
+		After:       auditSnapshot(event.After),
+		Details:     event.Details,
+	})
+}
+
+// passes created tasks to post_create extensions
+type extensionTaskEventHandler struct {
+	extensions  domain.ExtensionHooks
+}
+
+// creates task event handler calling extension hooks
+func NewExtensionTaskEventHandler(extensions domain.ExtensionHooks) domain.TaskEventHandler {
+	return &extensionTaskEventHandler{extensions: extensions}
+}
+
+func (handler *extensionTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+	if event.Type == domain.TaskEventCreated {
+		handler.extensions.PostTaskCreate(ctx, event.After)
+	}
+}
+
+// keeps the search index in step with stored changes
+type searchIndexTaskEventHandler struct {
+	search  domain.SearchService
+	logger  domain.Logger
+}
+
+// creates task event handler updating the search index
+func NewSearchIndexTaskEventHandler(search domain.SearchService, logger domain.Logger) domain.TaskEventHandler {
+	return &searchIndexTaskEventHandler{search: search, logger: logger}
+}
+
+func (handler *searchIndexTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	var err error
+	switch {
+	case event.Type == domain.TaskEventDeleted, event.Type == domain.TaskEventArchived:
+		err = handler.search.RemoveTask(ctx, event.TaskID)
+	case event.After != nil:
+		err = handler.search.IndexTask(ctx, event.After)
+	}
+	if err != nil {
+		handler.logger.Error(ctx, "failed to update search index", "task_id", event.TaskID, "error", err)        // search shows the old version until the next change
+	}
+}
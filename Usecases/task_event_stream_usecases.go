@@ -0,0 +1,73 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// task event stream usecase (replays logged task events for server-sent event clients)
+type TaskEventStreamUseCase interface {
+	GetEventsAfter(ctx context.Context, lastEventID string) ([]domain.TaskEventLogEntry, error)       // events the caller may see after the given one, oldest first
+}
+
+type taskEventStreamUseCase struct {
+	eventLogRepo  domain.TaskEventLogRepository
+	projectRepo   domain.ProjectRepository        // events of a project's tasks are only shown to its members
+}
+
+// creates new TaskEventStreamUseCase instance
+func NewTaskEventStreamUseCase(eventLogRepo domain.TaskEventLogRepository, projectRepo domain.ProjectRepository) TaskEventStreamUseCase {
+	return &taskEventStreamUseCase{eventLogRepo: eventLogRepo, projectRepo: projectRepo}
+}
+
+// read the next events of the caller's organization and projects
+func (streamUsc *taskEventStreamUseCase) GetEventsAfter(ctx context.Context, lastEventID string) ([]domain.TaskEventLogEntry, error) {
+
+	after, err := primitive.ObjectIDFromHex(lastEventID)
+	if err != nil {
+		return nil, domain.ErrInvalidEventID
+	}
+
+	// membership is checked on every read, so leaving a project stops its events
+	scope := domain.TaskQuery{}
+	if err := scopeTaskQuery(ctx, streamUsc.projectRepo, &scope); err != nil {
+		return nil, err
+	}
+	query := domain.TaskEventLogQuery{After: after, Visibility: scope.Visibility, Limit: domain.TaskEventLogBatch}
+	if tenantID, scoped := domain.TenantFromContext(ctx); scoped {
+		query.TenantID = &tenantID
+	}
+
+	return streamUsc.eventLogRepo.GetEvents(ctx, query)
+}
+
+type taskEventLogTaskEventHandler struct {
+	eventLogRepo  domain.TaskEventLogRepository
+	retention     time.Duration        // how long events can be replayed
+	logger        domain.Logger
+}
+
+// creates task event handler appending every task change to the event log
+func NewTaskEventLogTaskEventHandler(eventLogRepo domain.TaskEventLogRepository, retention time.Duration, logger domain.Logger) domain.TaskEventHandler {
+	return &taskEventLogTaskEventHandler{eventLogRepo: eventLogRepo, retention: retention, logger: logger}
+}
+
+func (handler *taskEventLogTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	// deleted and archived tasks are only known from their state before the change
+	task := event.After
+	if task == nil {
+		task = event.Before
+	}
+	now := time.Now().UTC()
+	entry := &domain.TaskEventLogEntry{Type: event.Type, TaskID: event.TaskID, Task: event.After, CreatedAt: now, ExpiresAt: now.Add(handler.retention)}
+	if task != nil {
+		entry.ProjectID, entry.TenantID = task.ProjectID, task.TenantID
+	}
+	if err := handler.eventLogRepo.AppendEvent(ctx, entry); err != nil {
+		handler.logger.Error(ctx, "failed to log task event", "event", event.Type, "task_id", event.TaskID, "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// task history usecase (field changes are always recorded, snapshots only when tasks are event sourced)
+type TaskHistoryUseCase interface {
+	GetTaskAsOf(ctx context.Context, taskID string, at time.Time) (*domain.Task, error)       // task state at a point in time or error if it didn't exist then
+	GetTaskHistory(ctx context.Context, taskID string) ([]domain.TaskHistoryEvent, error)     // every stored snapshot of a task, oldest first
+	GetTaskChanges(ctx context.Context, taskID string) ([]domain.TaskChange, error)           // every field change of a task, oldest first
+}
+
+type taskHistoryUseCase struct {
+	historyRepo  domain.TaskHistoryRepository
+	changeRepo   domain.TaskChangeRepository
+	taskQuery    TaskQueryUseCase                  // live tasks the caller may see
+	trashRepo    domain.TaskTrashRepository        // last state of deleted tasks
+	archiveRepo  domain.TaskArchiveRepository      // last state of archived tasks
+	projectRepo  domain.ProjectRepository          // history of a project's tasks is only shown to its members
+	enabled      bool
+}
+
+// creates new TaskHistoryUseCase instance
+func NewTaskHistoryUseCase(historyRepo domain.TaskHistoryRepository, changeRepo domain.TaskChangeRepository, taskQuery TaskQueryUseCase, trashRepo domain.TaskTrashRepository, archiveRepo domain.TaskArchiveRepository, projectRepo domain.ProjectRepository, enabled bool) TaskHistoryUseCase {
+	return &taskHistoryUseCase{historyRepo: historyRepo, changeRepo: changeRepo, taskQuery: taskQuery, trashRepo: trashRepo, archiveRepo: archiveRepo, projectRepo: projectRepo, enabled: enabled}
+}
+
+// rebuild task as it was at a point in time
+func (historyUsc *taskHistoryUseCase) GetTaskAsOf(ctx context.Context, taskID string, at time.Time) (*domain.Task, error) {
+
+	if !historyUsc.enabled {
+		return nil, domain.ErrTaskHistoryDisabled
+	}
+	// validate id field 
+	if taskID == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+	if err := historyUsc.checkReadable(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	// events hold full snapshots, so the last one before the time is the state
+	event, err := historyUsc.historyRepo.GetEventAsOf(ctx, taskID, at)
+	if err != nil {
+		return nil, err
+	}
+	if event.Task == nil {
+		return nil, domain.ErrTaskNotFound       // deleted by then
+	}
+
+	return event.Task, nil
+}
+
+// list stored snapshots of a task
+func (historyUsc *taskHistoryUseCase) GetTaskHistory(ctx context.Context, taskID string) ([]domain.TaskHistoryEvent, error) {
+
+	if !historyUsc.enabled {
+		return nil, domain.ErrTaskHistoryDisabled
+	}
+	// validate id field 
+	if taskID == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+	if err := historyUsc.checkReadable(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	events, err := historyUsc.historyRepo.GetEvents(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return events, nil
+}
+
+// list field changes of a task
+func (historyUsc *taskHistoryUseCase) GetTaskChanges(ctx context.Context, taskID string) ([]domain.TaskChange, error) {
+
+	// validate id field 
+	if taskID == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+	if err := historyUsc.checkReadable(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	changes, err := historyUsc.changeRepo.GetChanges(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return changes, nil
+}
+
+// history is shown to callers who may see the task, or its last known state once it's gone
+func (historyUsc *taskHistoryUseCase) checkReadable(ctx context.Context, taskID string) error {
+
+	_, err := historyUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != domain.ErrTaskNotFound {
+		return err
+	}
+
+	// missing or hidden, a hidden task's last state is hidden as well
+	task, err := historyUsc.lastKnownState(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil || !domain.TenantVisible(ctx, task.TenantID) {
+		return domain.ErrTaskNotFound
+	}
+	return checkTaskVisible(ctx, historyUsc.projectRepo, task)
+}
+
+// task as it was deleted or archived, or its last snapshot (nil when nothing was kept)
+func (historyUsc *taskHistoryUseCase) lastKnownState(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	if historyUsc.trashRepo != nil {
+		deleted, err := historyUsc.trashRepo.GetDeletedTask(ctx, taskID)
+		if err == nil {
+			return &deleted.Task, nil
+		}
+		if err != domain.ErrDeletedTaskNotFound {
+			return nil, err
+		}
+	}
+	if historyUsc.archiveRepo != nil {
+		archived, err := historyUsc.archiveRepo.GetArchivedTask(ctx, taskID)
+		if err == nil {
+			return &archived.Task, nil
+		}
+		if err != domain.ErrArchivedTaskNotFound {
+			return nil, err
+		}
+	}
+	if !historyUsc.enabled {
+		return nil, nil
+	}
+
+	events, err := historyUsc.historyRepo.GetEvents(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Task != nil {
+			return events[i].Task, nil
+		}
+	}
+	return nil, nil
+}
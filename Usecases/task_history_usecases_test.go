@@ -0,0 +1,91 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"testing";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+)
+
+// field changes kept in memory
+type memoryTaskChangeRepository struct {
+	changes  []domain.TaskChange
+}
+
+func (changeRepo *memoryTaskChangeRepository) AppendChanges(ctx context.Context, changes []domain.TaskChange) error {
+	changeRepo.changes = append(changeRepo.changes, changes...)
+	return nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) GetChanges(ctx context.Context, taskID string) ([]domain.TaskChange, error) {
+	changes := []domain.TaskChange{}
+	for _, change := range changeRepo.changes {
+		if change.TaskID.Hex() == taskID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) GetCompletions(ctx context.Context, actorID string, limit int64) ([]domain.TaskCompletion, error) {
+	return nil, nil
+}
+
+func (changeRepo *memoryTaskChangeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+func TestTaskChangesOfOtherTenantsAreHidden(t *testing.T) {
+
+	ctx := context.Background()
+	taskRepo := repositories.NewTenantTaskRepository(repositories.NewMemoryTaskRepository())
+	trashRepo := repositories.NewMemoryTaskTrashRepository()
+	changeRepo := &memoryTaskChangeRepository{}
+	history := NewTaskHistoryUseCase(nil, changeRepo, NewTaskQueryUseCase(taskRepo, nil, nil), trashRepo, nil, nil, false)
+
+	// one live and one deleted task of organization acme
+	live, err := taskRepo.CreateTask(ctx, &domain.Task{Title: "Plan release", Status: "pending", Priority: "medium", TenantID: "acme"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	deleted, err := taskRepo.CreateTask(ctx, &domain.Task{Title: "Write notes", Status: "pending", Priority: "medium", TenantID: "acme"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := trashTasks(ctx, trashRepo, []domain.Task{*deleted}); err != nil {
+		t.Fatalf("trashTasks: %v", err)
+	}
+	if err := taskRepo.DeleteTask(ctx, deleted.ID.Hex()); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+	for _, task := range []*domain.Task{live, deleted} {
+		changeRepo.AppendChanges(ctx, []domain.TaskChange{{TaskID: task.ID, Action: domain.TaskEventCreated, Field: "title", NewValue: task.Title, Timestamp: time.Now().UTC()}})
+	}
+
+	tests := []struct {
+		name    string
+		tenant  string
+		task    *domain.Task
+		want    error
+	}{
+		{"live task of own organization", "acme", live, nil},
+		{"deleted task of own organization", "acme", deleted, nil},
+		{"live task of other organization", "globex", live, domain.ErrTaskNotFound},
+		{"deleted task of other organization", "globex", deleted, domain.ErrTaskNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), domain.Actor{ID: "user", Role: domain.RoleUser, TenantID: test.tenant})
+			changes, err := history.GetTaskChanges(ctx, test.task.ID.Hex())
+			if err != test.want {
+				t.Fatalf("GetTaskChanges error = %v, want %v", err, test.want)
+			}
+			if err == nil && len(changes) != 1 {
+				t.Errorf("GetTaskChanges returned %d changes, want 1", len(changes))
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"fmt";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// task lock usecase (advisory edit locks, held until released or no heartbeat arrives within the ttl)
+type TaskLockUseCase interface {
+	AcquireLock(ctx context.Context, taskID string) (*domain.TaskLock, error)        // lock a task for the caller, returns the holder's lock with ErrTaskLocked when someone else edits it
+	Heartbeat(ctx context.Context, taskID string) (*domain.TaskLock, error)          // keep the caller's lock fresh
+	ReleaseLock(ctx context.Context, taskID string) error                            // drop the caller's lock (admins may drop any)
+	GetLock(ctx context.Context, taskID string) (*domain.TaskLock, error)            // who is editing a task
+}
+
+type taskLockUseCase struct {
+	lockRepo   domain.TaskLockRepository
+	taskQuery  TaskQueryUseCase        // locks are only shown and taken on tasks the caller can see
+	ttl        time.Duration
+}
+
+// creates new TaskLockUseCase instance
+func NewTaskLockUseCase(lockRepo domain.TaskLockRepository, taskQuery TaskQueryUseCase, ttl time.Duration) TaskLockUseCase {
+	return &taskLockUseCase{lockRepo: lockRepo, taskQuery: taskQuery, ttl: ttl}
+}
+
+// lock a task for the caller
+func (lockUsc *taskLockUseCase) AcquireLock(ctx context.Context, taskID string) (*domain.TaskLock, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if _, err := lockUsc.taskQuery.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	lock := &domain.TaskLock{TaskID: taskID, UserID: actor.ID, Username: actor.Username, AcquiredAt: now, ExpiresAt: now.Add(lockUsc.ttl)}
+	return lockUsc.lockRepo.Acquire(ctx, lock)
+}
+
+// extend the caller's lock by the ttl
+func (lockUsc *taskLockUseCase) Heartbeat(ctx context.Context, taskID string) (*domain.TaskLock, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return lockUsc.lockRepo.Heartbeat(ctx, taskID, actor.ID, time.Now().UTC().Add(lockUsc.ttl))
+}
+
+// release the caller's lock, admins can break the lock of someone who left
+func (lockUsc *taskLockUseCase) ReleaseLock(ctx context.Context, taskID string) error {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	holder := actor.ID
+	if domain.HasPermission(domain.PermissionsForRole(actor.Role), domain.PermissionUserManage) {
+		holder = ""
+	}
+
+	return lockUsc.lockRepo.Release(ctx, taskID, holder)
+}
+
+// fresh lock of a task
+func (lockUsc *taskLockUseCase) GetLock(ctx context.Context, taskID string) (*domain.TaskLock, error) {
+
+	if _, err := lockUsc.taskQuery.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return lockUsc.lockRepo.GetLock(ctx, taskID)
+}
+
+// reject changes while another user holds a fresh lock (background jobs without an actor aren't blocked)
+func checkTaskLock(ctx context.Context, lockRepo domain.TaskLockRepository, taskID string) error {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	lock, err := lockRepo.GetLock(ctx, taskID)
+	if err == domain.ErrTaskLockNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lock.UserID != actor.ID {
+		return fmt.Errorf("%w (%s)", domain.ErrTaskLocked, lock.Username)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"fmt";
+	"strings";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// task query usecase (reads only, served from the task read model)
+type TaskQueryUseCase interface {
+	GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error)    	     // get all tasks in the system matching query
+	GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) 			     // get specific task by id or return error if not found
+	GetTasksByIDs(ctx context.Context, taskIDs []string) (*domain.TaskBatch, error)              // get many tasks by id in one read, reporting the ids not found
+	GetSubtasks(ctx context.Context, taskID string) ([]domain.Task, error)                       // get direct subtasks of a task
+	SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchResult, error)      // full-text search, most relevant tasks first
+}
+
+type taskQueryUseCase struct {
+	taskReader   domain.TaskReader
+	projectRepo  domain.ProjectRepository        // tasks of a project are only shown to its members
+	search       domain.SearchService            // full-text index of tasks
+}
+
+// creates new TaskQueryUseCase instance
+func NewTaskQueryUseCase(reader domain.TaskReader, projectRepo domain.ProjectRepository, search domain.SearchService) TaskQueryUseCase {
+	return &taskQueryUseCase{taskReader: reader, projectRepo: projectRepo, search: search}
+}
+
+// get all tasks 
+func (taskQry *taskQueryUseCase) GetAllTasks(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
+	
+	// validate sort fields
+	for _, sortField := range query.Sort {
+		if _, ok := domain.TaskSortFields[sortField.Field]; !ok {
+			return nil, domain.ErrInvalidSortField
+		}
+	}
+
+	// only tasks of the caller's projects
+	if err := scopeTaskQuery(ctx, taskQry.projectRepo, &query); err != nil {
+		return nil, err
+	}
+
+	tasks, err := taskQry.taskReader.GetAllTasks(ctx, query)
+	if err == domain.ErrPartialResult {
+		setChecklistProgress(tasks)
+		return tasks, err        // caller decides what to do with the tasks read in time
+	}
+	if err != nil {
+		return nil, err
+	}
+	setChecklistProgress(tasks)
+	// return empty slice 
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+// find task by its id
+func (taskQry *taskQueryUseCase) GetTaskByID(ctx context.Context, id string) (*domain.Task, error) {
+	
+	// validate id field 
+	if id == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+
+	task, err := taskQry.taskReader.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := checkTaskVisible(ctx, taskQry.projectRepo, task); err != nil {
+		return nil, err
+	}
+	task.Progress = checklistProgress(task.Checklist)
+
+	return task, nil
+}
+// find many tasks by id with a single read, ids that can't be returned are reported instead of failing the batch
+func (taskQry *taskQueryUseCase) GetTasksByIDs(ctx context.Context, ids []string) (*domain.TaskBatch, error) {
+
+	if len(ids) == 0 {
+		return nil, domain.NewValidationError("ids", "cannot be empty")
+	}
+	if len(ids) > domain.MaxTaskBatchSize {
+		return nil, domain.NewValidationError("ids", fmt.Sprintf("at most %d ids", domain.MaxTaskBatchSize))
+	}
+
+	// drop duplicates, invalid ids can't match a task
+	batch := &domain.TaskBatch{Tasks: []domain.Task{}, NotFound: []string{}}
+	requested := make([]string, 0, len(ids))
+	seen := map[string]bool{}
+	query := domain.TaskQuery{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			batch.NotFound = append(batch.NotFound, id)
+			continue
+		}
+		requested = append(requested, id)
+		query.IDs = append(query.IDs, objID)
+	}
+	if len(query.IDs) == 0 {
+		return batch, nil
+	}
+
+	// only tasks of the caller's projects
+	if err := scopeTaskQuery(ctx, taskQry.projectRepo, &query); err != nil {
+		return nil, err
+	}
+
+	tasks, err := taskQry.taskReader.GetAllTasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	setChecklistProgress(tasks)
+	found := make(map[string]domain.Task, len(tasks))
+	for _, task := range tasks {
+		found[task.ID.Hex()] = task
+	}
+
+	// requested order, missing and hidden tasks look the same to the caller
+	for _, id := range requested {
+		task, ok := found[id]
+		if !ok {
+			batch.NotFound = append(batch.NotFound, id)
+			continue
+		}
+		batch.Tasks = append(batch.Tasks, task)
+	}
+
+	return batch, nil
+}
+// get direct subtasks of a task
+func (taskQry *taskQueryUseCase) GetSubtasks(ctx context.Context, id string) ([]domain.Task, error) {
+	
+	// verify task exists first
+	parent, err := taskQry.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks, err := taskQry.taskReader.GetSubtasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// subtasks moved to another project are left out for non-members
+	visible := make([]domain.Task, 0, len(subtasks))
+	for i := range subtasks {
+		if !sameProject(subtasks[i].ProjectID, parent.ProjectID) && checkTaskVisible(ctx, taskQry.projectRepo, &subtasks[i]) != nil {
+			continue
+		}
+		visible = append(visible, subtasks[i])
+	}
+	setChecklistProgress(visible)
+	return visible, nil
+}
+
+// full-text search ranked by relevance, with the matched words highlighted
+func (taskQry *taskQueryUseCase) SearchTasks(ctx context.Context, query domain.TaskSearchQuery) ([]domain.TaskSearchResult, error) {
+
+	// validate words and language, default the page size
+	invalid := &domain.ValidationError{}
+	if query.Text = strings.TrimSpace(query.Text); query.Text == "" {
+		invalid.Add("q", "cannot be empty")
+	}
+	if query.Language != "" && !domain.IsTaskLanguage(query.Language) {
+		invalid.Add("language", "must be one of "+strings.Join(domain.TaskLanguages, ", "))
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+	if query.Limit < 1 {
+		query.Limit = domain.DefaultTaskSearchLimit
+	}
+	if query.Limit > domain.MaxTaskSearchLimit {
+		query.Limit = domain.MaxTaskSearchLimit
+	}
+
+	// only tasks of the caller's projects
+	scope := domain.TaskQuery{}
+	if err := scopeTaskQuery(ctx, taskQry.projectRepo, &scope); err != nil {
+		return nil, err
+	}
+	query.Visibility = scope.Visibility
+
+	matches, err := taskQry.search.SearchTasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// current state of each match, tasks deleted since they were indexed are left out
+	results := make([]domain.TaskSearchResult, 0, len(matches))
+	for _, match := range matches {
+		task, err := taskQry.taskReader.GetTaskByID(ctx, match.TaskID)
+		if err == domain.ErrTaskNotFound || err == domain.ErrInvalidTaskID {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, domain.TaskSearchResult{Task: *task, Score: match.Score, Highlights: match.Highlights})
+	}
+
+	return results, nil
+}
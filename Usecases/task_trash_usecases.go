@@ -0,0 +1,231 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// deleted tasks purged per batch
+const taskTrashPurgeBatchSize = 500
+
+// task trash usecase (deleted tasks stay in the trash until they are restored or purged)
+type TaskTrashUseCase interface {
+	GetDeletedTasks(ctx context.Context, before time.Time, limit int64) ([]domain.DeletedTask, error)     // deleted tasks the caller may see, newest first
+	RestoreTask(ctx context.Context, taskID string) (*domain.Task, error)                                 // move a task and the subtasks deleted with it back to the task list
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error)                               // remove tasks deleted before a time for good, returns tasks purged
+}
+
+type taskTrashUseCase struct {
+	trashRepo    domain.TaskTrashRepository
+	taskRepo     domain.TaskRepository           // restored tasks go back through it (history, cache and tenant checks)
+	projectRepo  domain.ProjectRepository        // deleted tasks of a project are only shown to its members
+	unitOfWork   domain.UnitOfWork
+	logger       domain.Logger
+	handlers     []domain.TaskEventHandler       // same handlers as task commands (search index, webhooks, audit log)
+}
+
+// creates new TaskTrashUseCase instance
+func NewTaskTrashUseCase(trashRepo domain.TaskTrashRepository, taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, unitOfWork domain.UnitOfWork, logger domain.Logger, handlers ...domain.TaskEventHandler) TaskTrashUseCase {
+	return &taskTrashUseCase{trashRepo: trashRepo, taskRepo: taskRepo, projectRepo: projectRepo, unitOfWork: unitOfWork, logger: logger, handlers: handlers}
+}
+
+// deleted tasks of the caller's organization and projects
+func (trashUsc *taskTrashUseCase) GetDeletedTasks(ctx context.Context, before time.Time, limit int64) ([]domain.DeletedTask, error) {
+
+	if limit <= 0 {
+		limit = domain.DefaultTrashLimit
+	}
+	if limit > domain.MaxTrashLimit {
+		limit = domain.MaxTrashLimit
+	}
+	var scope domain.TaskQuery
+	if err := scopeTaskQuery(ctx, trashUsc.projectRepo, &scope); err != nil {
+		return nil, err
+	}
+	query := domain.TrashQuery{Visibility: scope.Visibility, Before: before, Limit: limit}
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		query.TenantID = &tenant
+	}
+
+	return trashUsc.trashRepo.GetDeletedTasks(ctx, query)
+}
+
+// restore a deleted task the caller may see
+func (trashUsc *taskTrashUseCase) RestoreTask(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	deleted, err := trashUsc.trashRepo.GetDeletedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TenantVisible(ctx, deleted.TenantID) {
+		return nil, domain.ErrDeletedTaskNotFound
+	}
+	if err := trashUsc.checkRestorable(ctx, &deleted.Task); err != nil {
+		return nil, err
+	}
+
+	var tasks []domain.Task
+	err = trashUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if tasks, err = trashUsc.trashRepo.Restore(ctx, taskID); err != nil {
+			return err
+		}
+		if err := trashUsc.detachOrphans(ctx, tasks); err != nil {
+			return err
+		}
+		restored := make([]*domain.Task, len(tasks))
+		for i := range tasks {
+			restored[i] = &tasks[i]
+		}
+		return trashUsc.taskRepo.RestoreTasks(ctx, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		trashUsc.publish(ctx, domain.TaskEvent{Type: domain.TaskEventRestored, TaskID: tasks[i].ID.Hex(), After: &tasks[i]})
+	}
+	return &tasks[0], nil
+}
+
+// project tasks need a caller who sees the project, tasks of a deleted project need one who manages all projects
+func (trashUsc *taskTrashUseCase) checkRestorable(ctx context.Context, task *domain.Task) error {
+
+	if task.ProjectID == nil {
+		return nil
+	}
+	_, err := projectWithRole(ctx, trashUsc.projectRepo, task.ProjectID.Hex(), domain.ProjectRoleViewer)
+	if err == domain.ErrProjectNotFound {
+		if actor, ok := domain.ActorFromContext(ctx); !ok || actor.ManagesAllProjects() {
+			return nil        // comes back without a project
+		}
+	}
+	if err == domain.ErrProjectAccessDenied || err == domain.ErrProjectNotFound {
+		return domain.ErrDeletedTaskNotFound
+	}
+	return err
+}
+
+// make restored tasks whose parent is gone top-level (parents restored together stay), and drop projects deleted since
+func (trashUsc *taskTrashUseCase) detachOrphans(ctx context.Context, tasks []domain.Task) error {
+
+	projects := map[primitive.ObjectID]bool{}
+	for i := range tasks {
+		projectID := tasks[i].ProjectID
+		if projectID == nil {
+			continue
+		}
+		if _, checked := projects[*projectID]; !checked {
+			_, err := trashUsc.projectRepo.GetProjectByID(ctx, projectID.Hex())
+			if err != nil && err != domain.ErrProjectNotFound {
+				return err
+			}
+			projects[*projectID] = err == nil
+		}
+		if !projects[*projectID] {
+			tasks[i].ProjectID = nil
+		}
+	}
+
+	restoring := map[primitive.ObjectID]bool{}
+	for _, task := range tasks {
+		restoring[task.ID] = true
+	}
+	parents := []primitive.ObjectID{}
+	for _, task := range tasks {
+		if task.ParentID != nil && !restoring[*task.ParentID] {
+			parents = append(parents, *task.ParentID)
+		}
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+
+	live, err := trashUsc.taskRepo.GetAllTasks(ctx, domain.TaskQuery{IDs: parents})
+	if err != nil {
+		return err
+	}
+	for _, parent := range live {
+		restoring[parent.ID] = true
+	}
+	for i := range tasks {
+		if tasks[i].ParentID != nil && !restoring[*tasks[i].ParentID] {
+			tasks[i].ParentID = nil
+		}
+	}
+	return nil
+}
+
+// purge the trash of the caller's organization in batches until nothing older is left
+func (trashUsc *taskTrashUseCase) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+
+	var tenantID *string
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		tenantID = &tenant
+	}
+
+	var purged int64
+	for {
+		ids, err := trashUsc.trashRepo.Purge(ctx, deletedBefore, tenantID, taskTrashPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, id := range ids {
+			trashUsc.publish(ctx, domain.TaskEvent{Type: domain.TaskEventPurged, TaskID: id.Hex()})        // worklogs of the task go with it
+		}
+		purged += int64(len(ids))
+		if len(ids) < taskTrashPurgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		trashUsc.logger.Info(ctx, "deleted tasks purged", "tasks", purged)
+	}
+	return purged, nil
+}
+
+// keep copies of tasks about to be deleted together in the trash
+// subtasks are restored with the topmost of their ancestors deleted with them
+func trashTasks(ctx context.Context, trashRepo domain.TaskTrashRepository, tasks []domain.Task) error {
+
+	parents := map[primitive.ObjectID]*primitive.ObjectID{}
+	for _, task := range tasks {
+		parents[task.ID] = task.ParentID
+	}
+
+	now := time.Now().UTC()
+	actor, _ := domain.ActorFromContext(ctx)        // background deletes have no actor
+	deleted := make([]domain.DeletedTask, len(tasks))
+	for i, task := range tasks {
+		deleted[i] = domain.DeletedTask{Task: task, DeletedAt: now, DeletedBy: actor.ID}
+		root := task.ID
+		for hops := 0; hops < len(tasks); hops++ {        // bounded in case of a parent cycle
+			parent := parents[root]
+			if parent == nil {
+				break
+			}
+			if _, together := parents[*parent]; !together {
+				break
+			}
+			root = *parent
+		}
+		if root != task.ID {
+			deletedWith := root
+			deleted[i].DeletedWith = &deletedWith
+		}
+	}
+
+	return trashRepo.Add(ctx, deleted)
+}
+
+// pass a trash change to the task event handlers
+func (trashUsc *taskTrashUseCase) publish(ctx context.Context, event domain.TaskEvent) {
+	for _, handler := range trashUsc.handlers {
+		handler.HandleTaskEvent(ctx, event)
+	}
+}
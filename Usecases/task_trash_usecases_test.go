@@ -0,0 +1,248 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"testing";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+)
+
+// logger that drops everything
+type discardLogger struct{}
+
+func (discardLogger) Debug(ctx context.Context, msg string, args ...interface{}) {}
+func (discardLogger) Info(ctx context.Context, msg string, args ...interface{})  {}
+func (discardLogger) Warn(ctx context.Context, msg string, args ...interface{})  {}
+func (discardLogger) Error(ctx context.Context, msg string, args ...interface{}) {}
+
+// task event handler that keeps the events it gets
+type recordingTaskEventHandler struct {
+	events  []domain.TaskEvent
+}
+
+func (handler *recordingTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+	handler.events = append(handler.events, event)
+}
+
+// task commands and trash over the in-memory repositories (no projects, labels or hooks)
+type trashFixture struct {
+	taskRepo   domain.TaskRepository
+	trashRepo  domain.TaskTrashRepository
+	commands   TaskCommandUseCase
+	trash      TaskTrashUseCase
+	events     *recordingTaskEventHandler
+}
+
+func newTrashFixture() *trashFixture {
+	fixture := &trashFixture{
+		taskRepo:   repositories.NewMemoryTaskRepository(),
+		trashRepo:  repositories.NewMemoryTaskTrashRepository(),
+		events:     &recordingTaskEventHandler{},
+	}
+	unitOfWork := repositories.NewDirectUnitOfWork()
+	fixture.commands = NewTaskCommandUseCase(fixture.taskRepo, nil, nil, nil, nil, nil, nil, domain.TaskWorkflow{}, fixture.trashRepo, unitOfWork, fixture.events)
+	fixture.trash = NewTaskTrashUseCase(fixture.trashRepo, fixture.taskRepo, nil, unitOfWork, discardLogger{}, fixture.events)
+	return fixture
+}
+
+// store a task directly, parent may be nil
+func (fixture *trashFixture) createTask(t *testing.T, title string, parent *domain.Task) *domain.Task {
+	t.Helper()
+	task := &domain.Task{Title: title, Status: "pending", Priority: "medium"}
+	if parent != nil {
+		task.ParentID = &parent.ID
+	}
+	created, err := fixture.taskRepo.CreateTask(context.Background(), task)
+	if err != nil {
+		t.Fatalf("CreateTask(%q): %v", title, err)
+	}
+	return created
+}
+
+// events of one type in the order they were published
+func (fixture *trashFixture) eventsOf(eventType string) []domain.TaskEvent {
+	events := []domain.TaskEvent{}
+	for _, event := range fixture.events.events {
+		if event.Type == eventType {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestDeleteTaskMovesSubtasksToTrash(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTrashFixture()
+	parent := fixture.createTask(t, "Plan release", nil)
+	child := fixture.createTask(t, "Write notes", parent)
+	grandchild := fixture.createTask(t, "Collect changes", child)
+
+	if err := fixture.commands.DeleteTask(ctx, parent.ID.Hex(), false); err != domain.ErrTaskHasSubtasks {
+		t.Fatalf("DeleteTask without cascade error = %v, want %v", err, domain.ErrTaskHasSubtasks)
+	}
+	if err := fixture.commands.DeleteTask(ctx, parent.ID.Hex(), true); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	live, err := fixture.taskRepo.GetAllTasks(ctx, domain.TaskQuery{})
+	if err != nil {
+		t.Fatalf("GetAllTasks: %v", err)
+	}
+	if len(live) != 0 {
+		t.Errorf("%d tasks left after cascading delete, want 0", len(live))
+	}
+
+	// subtasks are restored with the task they were deleted with
+	wantDeletedWith := map[primitive.ObjectID]*primitive.ObjectID{parent.ID: nil, child.ID: &parent.ID, grandchild.ID: &parent.ID}
+	for id, want := range wantDeletedWith {
+		deleted, err := fixture.trashRepo.GetDeletedTask(ctx, id.Hex())
+		if err != nil {
+			t.Fatalf("GetDeletedTask(%s): %v", id.Hex(), err)
+		}
+		switch {
+		case want == nil && deleted.DeletedWith != nil:
+			t.Errorf("task %q deleted with %s, want none", deleted.Title, deleted.DeletedWith.Hex())
+		case want != nil && (deleted.DeletedWith == nil || *deleted.DeletedWith != *want):
+			t.Errorf("task %q deleted with %v, want %s", deleted.Title, deleted.DeletedWith, want.Hex())
+		}
+	}
+
+	deletedEvents := fixture.eventsOf(domain.TaskEventDeleted)
+	if len(deletedEvents) != 1 {
+		t.Fatalf("%d %s events, want 1", len(deletedEvents), domain.TaskEventDeleted)
+	}
+	if event := deletedEvents[0]; event.TaskID != parent.ID.Hex() || event.Details["trashed"] != "true" || event.Details["cascaded_subtasks"] != "2" {
+		t.Errorf("deleted event = %+v, want task %s trashed with 2 cascaded subtasks", event, parent.ID.Hex())
+	}
+}
+
+func TestRestoreTaskBringsBackSubtasks(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTrashFixture()
+	parent := fixture.createTask(t, "Plan release", nil)
+	child := fixture.createTask(t, "Write notes", parent)
+	if err := fixture.commands.DeleteTask(ctx, parent.ID.Hex(), true); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	restored, err := fixture.trash.RestoreTask(ctx, parent.ID.Hex())
+	if err != nil {
+		t.Fatalf("RestoreTask: %v", err)
+	}
+	if restored.ID != parent.ID {
+		t.Errorf("RestoreTask returned task %s, want %s", restored.ID.Hex(), parent.ID.Hex())
+	}
+
+	for _, original := range []*domain.Task{parent, child} {
+		task, err := fixture.taskRepo.GetTaskByID(ctx, original.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetTaskByID(%q) after restore: %v", original.Title, err)
+		}
+		if !task.CreatedAt.Equal(original.CreatedAt) {
+			t.Errorf("task %q created_at = %v, want %v", task.Title, task.CreatedAt, original.CreatedAt)
+		}
+	}
+	subtask, _ := fixture.taskRepo.GetTaskByID(ctx, child.ID.Hex())
+	if subtask.ParentID == nil || *subtask.ParentID != parent.ID {
+		t.Errorf("restored subtask parent = %v, want %s", subtask.ParentID, parent.ID.Hex())
+	}
+
+	trashed, err := fixture.trash.GetDeletedTasks(ctx, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("GetDeletedTasks: %v", err)
+	}
+	if len(trashed) != 0 {
+		t.Errorf("%d tasks left in the trash, want 0", len(trashed))
+	}
+	if restoredEvents := fixture.eventsOf(domain.TaskEventRestored); len(restoredEvents) != 2 {
+		t.Errorf("%d %s events, want 2", len(restoredEvents), domain.TaskEventRestored)
+	}
+}
+
+func TestRestoreSubtaskWithoutParentMakesItTopLevel(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTrashFixture()
+	parent := fixture.createTask(t, "Plan release", nil)
+	child := fixture.createTask(t, "Write notes", parent)
+	if err := fixture.commands.DeleteTask(ctx, parent.ID.Hex(), true); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	restored, err := fixture.trash.RestoreTask(ctx, child.ID.Hex())
+	if err != nil {
+		t.Fatalf("RestoreTask: %v", err)
+	}
+	if restored.ParentID != nil {
+		t.Errorf("restored subtask parent = %s, want none while the parent is in the trash", restored.ParentID.Hex())
+	}
+	if _, err := fixture.taskRepo.GetTaskByID(ctx, parent.ID.Hex()); err != domain.ErrTaskNotFound {
+		t.Errorf("GetTaskByID(parent) error = %v, want %v", err, domain.ErrTaskNotFound)
+	}
+	if _, err := fixture.trashRepo.GetDeletedTask(ctx, parent.ID.Hex()); err != nil {
+		t.Errorf("parent left the trash with its subtask: %v", err)
+	}
+}
+
+func TestRestoreTaskNotInTrash(t *testing.T) {
+
+	fixture := newTrashFixture()
+	task := fixture.createTask(t, "Plan release", nil)
+
+	tests := []struct {
+		name    string
+		taskID  string
+		want    error
+	}{
+		{"live task", task.ID.Hex(), domain.ErrDeletedTaskNotFound},
+		{"unknown task", primitive.NewObjectID().Hex(), domain.ErrDeletedTaskNotFound},
+		{"invalid id", "not-an-id", domain.ErrInvalidTaskID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := fixture.trash.RestoreTask(context.Background(), test.taskID); err != test.want {
+				t.Errorf("RestoreTask(%s) error = %v, want %v", test.taskID, err, test.want)
+			}
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTrashFixture()
+	parent := fixture.createTask(t, "Plan release", nil)
+	fixture.createTask(t, "Write notes", parent)
+	if err := fixture.commands.DeleteTask(ctx, parent.ID.Hex(), true); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	// tasks deleted after the cutoff stay
+	purged, err := fixture.trash.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if purged != 0 {
+		t.Errorf("PurgeTrash before the deletion purged %d tasks, want 0", purged)
+	}
+
+	purged, err = fixture.trash.PurgeTrash(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("PurgeTrash purged %d tasks, want 2", purged)
+	}
+	if _, err := fixture.trash.RestoreTask(ctx, parent.ID.Hex()); err != domain.ErrDeletedTaskNotFound {
+		t.Errorf("RestoreTask after purge error = %v, want %v", err, domain.ErrDeletedTaskNotFound)
+	}
+	if purgedEvents := fixture.eventsOf(domain.TaskEventPurged); len(purgedEvents) != 2 {
+		t.Errorf("%d %s events, want 2", len(purgedEvents), domain.TaskEventPurged)
+	}
+}
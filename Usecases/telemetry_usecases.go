@@ -0,0 +1,61 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// telemetry usecase
+type TelemetryUseCase interface {
+	BuildReport(ctx context.Context) (*domain.TelemetryReport, error)       // report exactly as it would be sent
+	SendReport(ctx context.Context) error                                   // send report (no-op unless opted in)
+}
+
+type telemetryUseCase struct {
+	usageRepo   domain.UsageStatsRepository
+	sender      domain.TelemetrySender
+	enabled     bool                  // operator opted in
+	instanceID  string
+	version     string
+	features    map[string]bool
+}
+
+// creates new TelemetryUseCase instance
+func NewTelemetryUseCase(usageRepo domain.UsageStatsRepository, sender domain.TelemetrySender, enabled bool, instanceID string, version string, features map[string]bool) TelemetryUseCase {
+	return &telemetryUseCase{usageRepo: usageRepo, sender: sender, enabled: enabled, instanceID: instanceID, version: version, features: features}
+}
+
+// build anonymized usage report
+func (telemetryUsc *telemetryUseCase) BuildReport(ctx context.Context) (*domain.TelemetryReport, error) {
+
+	counts, err := telemetryUsc.usageRepo.GetUsageCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TelemetryReport{
+		InstanceID:   telemetryUsc.instanceID,
+		Version:      telemetryUsc.version,
+		GeneratedAt:  time.Now().UTC(),
+		Counts:       *counts,
+		Features:     telemetryUsc.features,
+	}, nil
+}
+
+// send usage report to the collector
+func (telemetryUsc *telemetryUseCase) SendReport(ctx context.Context) error {
+
+	// nothing leaves the instance without opt-in
+	if !telemetryUsc.enabled {
+		return nil
+	}
+
+	report, err := telemetryUsc.BuildReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	return telemetryUsc.sender.Send(ctx, report)
+}
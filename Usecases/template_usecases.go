@@ -0,0 +1,246 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// template usecase (users keep templates of tasks they create often, only the owner sees a template)
+type TemplateUseCase interface {
+	CreateTemplate(ctx context.Context, template *domain.TaskTemplate) (*domain.TaskTemplate, error)                          // create template owned by the caller
+	SaveTaskAsTemplate(ctx context.Context, taskID string) (*domain.TaskTemplate, error)                                      // create template from a task the caller can see
+	GetTemplates(ctx context.Context) ([]domain.TaskTemplate, error)                                                          // get the caller's templates
+	GetTemplateByID(ctx context.Context, templateID string) (*domain.TaskTemplate, error)                                     // get own template or return error if not found
+	UpdateTemplate(ctx context.Context, templateID string, template *domain.TaskTemplate) (*domain.TaskTemplate, error)      // update own template
+	DeleteTemplate(ctx context.Context, templateID string) error                                                              // delete own template
+	CreateTaskFromTemplate(ctx context.Context, templateID string, req *domain.TaskFromTemplateRequest) (*domain.Task, error) // create task with the template's fields
+}
+
+type templateUseCase struct {
+	templateRepo  domain.TemplateRepository
+	labelRepo     domain.LabelRepository
+	taskCommands  TaskCommandUseCase        // tasks from templates are validated like any new task
+	taskQuery     TaskQueryUseCase          // only tasks the caller can see are saved as templates
+}
+
+// creates new TemplateUseCase instance
+func NewTemplateUseCase(templateRepo domain.TemplateRepository, labelRepo domain.LabelRepository, taskCommands TaskCommandUseCase, taskQuery TaskQueryUseCase) TemplateUseCase {
+	return &templateUseCase{templateRepo: templateRepo, labelRepo: labelRepo, taskCommands: taskCommands, taskQuery: taskQuery}
+}
+
+// create a template for the caller
+func (templateUsc *templateUseCase) CreateTemplate(ctx context.Context, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	template.Title = strings.TrimSpace(template.Title)
+	if template.Title == "" {
+		return nil, domain.NewValidationError("title", "cannot be empty")
+	}
+	if err := templateUsc.checkTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	template.OwnerID = actor.ID
+	template.TenantID = actor.TenantID
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	if err := templateUsc.templateRepo.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// create a template with the title, description, checklist, labels and priority of a task
+func (templateUsc *templateUseCase) SaveTaskAsTemplate(ctx context.Context, taskID string) (*domain.TaskTemplate, error) {
+
+	task, err := templateUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &domain.TaskTemplate{
+		Title:       task.Title,
+		Description: task.Description,
+		Tags:        task.Tags,
+		Priority:    task.Priority,
+	}
+	for _, item := range task.Checklist {
+		template.Checklist = append(template.Checklist, item.Text)
+	}
+
+	return templateUsc.CreateTemplate(ctx, template)
+}
+
+// get the caller's templates
+func (templateUsc *templateUseCase) GetTemplates(ctx context.Context) ([]domain.TaskTemplate, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return templateUsc.templateRepo.GetTemplates(ctx, actor.ID)
+}
+
+// find own template by its id
+func (templateUsc *templateUseCase) GetTemplateByID(ctx context.Context, templateID string) (*domain.TaskTemplate, error) {
+	return templateUsc.ownTemplate(ctx, templateID)
+}
+
+// update own template by its id
+func (templateUsc *templateUseCase) UpdateTemplate(ctx context.Context, templateID string, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+
+	// stop if nothing valid to update
+	template.Title = strings.TrimSpace(template.Title)
+	if template.Title == "" && template.Description == "" && template.Priority == "" && template.Checklist == nil && template.Tags == nil {
+		return nil, domain.NewValidationError("body", "no valid fields provided for update")
+	}
+	if err := templateUsc.checkTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if _, err := templateUsc.ownTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+
+	template.UpdatedAt = time.Now().UTC()
+	return templateUsc.templateRepo.UpdateTemplate(ctx, templateID, template)
+}
+
+// delete own template by its id
+func (templateUsc *templateUseCase) DeleteTemplate(ctx context.Context, templateID string) error {
+
+	if _, err := templateUsc.ownTemplate(ctx, templateID); err != nil {
+		return err
+	}
+
+	return templateUsc.templateRepo.DeleteTemplate(ctx, templateID)
+}
+
+// create a task from own template, fields of the request win over the template's
+func (templateUsc *templateUseCase) CreateTaskFromTemplate(ctx context.Context, templateID string, req *domain.TaskFromTemplateRequest) (*domain.Task, error) {
+
+	template, err := templateUsc.ownTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &domain.Task{
+		Title:       template.Title,
+		Description: template.Description,
+		DueDate:     req.DueDate,
+		Priority:    template.Priority,
+		ProjectID:   req.ProjectID,
+	}
+	if req.Title != "" {
+		task.Title = req.Title
+	}
+	if req.Description != "" {
+		task.Description = req.Description
+	}
+	if req.Priority != "" {
+		task.Priority = req.Priority
+	}
+	for _, text := range template.Checklist {
+		task.Checklist = append(task.Checklist, domain.ChecklistItem{Text: text})
+	}
+
+	// labels deleted since the template was saved are left out
+	if len(template.Tags) > 0 {
+		labels, err := templateUsc.labelRepo.GetLabelsByName(ctx, template.Tags)
+		if err != nil {
+			return nil, err
+		}
+		existing := map[string]bool{}
+		for _, label := range labels {
+			existing[label.Name] = true
+		}
+		for _, tag := range template.Tags {
+			if existing[tag] {
+				task.Tags = append(task.Tags, tag)
+			}
+		}
+	}
+
+	created, _, err := templateUsc.taskCommands.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// template of the caller (templates of other users are not found)
+func (templateUsc *templateUseCase) ownTemplate(ctx context.Context, templateID string) (*domain.TaskTemplate, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	template, err := templateUsc.templateRepo.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.OwnerID != actor.ID {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return template, nil
+}
+
+// validate the given template fields, every broken rule is reported
+func (templateUsc *templateUseCase) checkTemplate(ctx context.Context, template *domain.TaskTemplate) error {
+
+	invalid := &domain.ValidationError{}
+	if template.Priority != "" {
+		if _, ok := domain.TaskPriorities[template.Priority]; !ok {
+			invalid.Add("priority", "must be one of low, medium, high, urgent")
+		}
+	}
+	if len(template.Checklist) > domain.MaxChecklistItems {
+		invalid.Add("checklist", "cannot have more than "+strconv.Itoa(domain.MaxChecklistItems)+" items")
+	}
+	for i, text := range template.Checklist {
+		template.Checklist[i] = strings.TrimSpace(text)
+		if template.Checklist[i] == "" {
+			invalid.Add("checklist["+strconv.Itoa(i)+"]", "cannot be empty")
+		}
+	}
+	if err := invalid.Err(); err != nil {
+		return err
+	}
+
+	// validate labels exist, duplicates are dropped
+	if len(template.Tags) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(template.Tags))
+	for _, tag := range template.Tags {
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+	labels, err := templateUsc.labelRepo.GetLabelsByName(ctx, unique)
+	if err != nil {
+		return err
+	}
+	if len(labels) != len(unique) {
+		return domain.ErrLabelNotFound
+	}
+	template.Tags = unique
+
+	return nil
+}
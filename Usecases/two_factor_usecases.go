@@ -0,0 +1,232 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// generate a secret for the authenticator app, it only protects logins once a code from it is confirmed
+func (userUsc *userUseCase) SetupTwoFactor(ctx context.Context, userID string) (*domain.TwoFactorSetup, error) {
+
+	user, err := userUsc.twoFactorUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.IsEnabled() {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	// a repeated setup replaces the secret that was never confirmed
+	secret, err := userUsc.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := userUsc.userRepo.SetTwoFactor(ctx, user.ID, &domain.TwoFactorSettings{PendingSecret: secret}); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetup{Secret: secret, ProvisioningURI: userUsc.totp.ProvisioningURI(secret, user.Username)}, nil
+}
+
+// enable two-factor logins with a code of the secret from setup
+func (userUsc *userUseCase) EnableTwoFactor(ctx context.Context, userID string, code string) (*domain.TwoFactorBackupCodes, error) {
+
+	user, err := userUsc.twoFactorUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.IsEnabled() {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+	if user.TwoFactor == nil || user.TwoFactor.PendingSecret == "" {
+		return nil, domain.ErrTwoFactorNotSetUp
+	}
+
+	// a working code proves the app has the secret
+	step, ok := userUsc.totp.Validate(user.TwoFactor.PendingSecret, normalizeTwoFactorCode(code), time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	// backup codes are shown once, only their hashes are stored
+	backup := &domain.TwoFactorBackupCodes{BackupCodes: make([]string, 0, domain.TwoFactorBackupCodeCount)}
+	hashes := make([]string, 0, domain.TwoFactorBackupCodeCount)
+	for i := 0; i < domain.TwoFactorBackupCodeCount; i++ {
+		plain, err := generateRandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		backup.BackupCodes = append(backup.BackupCodes, plain)
+		hashes = append(hashes, hashToken(plain))
+	}
+
+	now := time.Now().UTC()
+	settings := &domain.TwoFactorSettings{Secret: user.TwoFactor.PendingSecret, EnabledAt: &now, BackupCodes: hashes, LastUsedStep: step}
+	if err := userUsc.userRepo.SetTwoFactor(ctx, user.ID, settings); err != nil {
+		return nil, err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditTwoFactorEnabled,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  userID,
+		Actor:    user.Username,
+	})
+
+	return backup, nil
+}
+
+// turn off two-factor logins, a code proves the caller still has the app or a backup code
+func (userUsc *userUseCase) DisableTwoFactor(ctx context.Context, userID string, code string) error {
+
+	user, err := userUsc.twoFactorUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactor.IsEnabled() {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	if _, err := userUsc.useTwoFactorCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := userUsc.userRepo.SetTwoFactor(ctx, user.ID, nil); err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditTwoFactorDisabled,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  userID,
+		Actor:    user.Username,
+	})
+
+	return nil
+}
+
+// finish a password login with an app or backup code
+func (userUsc *userUseCase) CompleteTwoFactorLogin(ctx context.Context, challengeToken string, code string) (string, *domain.User, error) {
+
+	// validate input
+	if challengeToken == "" {
+		return "", nil, domain.ErrInvalidTwoFactorChallenge
+	}
+
+	// expired challenges may still exist until the ttl index removes them
+	challenge, err := userUsc.challengeRepo.GetChallengeByHash(ctx, hashToken(challengeToken))
+	if err != nil {
+		return "", nil, err
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		return "", nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	user, err := userUsc.userRepo.GetUserById(ctx, challenge.UserID)
+	if err == domain.ErrUserNotFound {
+		return "", nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the account may have changed since the password was checked
+	if user.IsDeactivated() {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "account deactivated")
+		return "", nil, domain.ErrAccountDeactivated
+	}
+	if user.IsLocked(time.Now()) {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "account locked")
+		return "", nil, domain.ErrAccountLocked
+	}
+	if !user.TwoFactor.IsEnabled() {
+		return "", nil, domain.ErrInvalidTwoFactorChallenge
+	}
+
+	// a challenge takes a few wrong codes, then the password has to be entered again
+	// (new challenges don't start over, wrong codes count toward the account lockout like wrong passwords)
+	method, err := userUsc.useTwoFactorCode(ctx, user, code)
+	if err == domain.ErrInvalidTwoFactorCode {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "wrong two-factor code")
+		attempts, incErr := userUsc.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+		if incErr != nil {
+			return "", nil, incErr
+		}
+		lockErr := userUsc.recordFailedLogin(ctx, user)
+		if attempts >= domain.MaxTwoFactorAttempts || lockErr == domain.ErrAccountLocked {
+			if delErr := userUsc.challengeRepo.DeleteChallenge(ctx, challenge.ID); delErr != nil {
+				return "", nil, delErr
+			}
+		}
+		if lockErr != domain.ErrInvalidCredentials {
+			return "", nil, lockErr
+		}
+		return "", nil, err
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// challenges are single use, and both steps passing starts counting failures again
+	if err := userUsc.challengeRepo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return "", nil, err
+	}
+	if err := userUsc.clearFailedLogins(ctx, user); err != nil {
+		return "", nil, err
+	}
+
+	return userUsc.startSession(ctx, user, map[string]string{"two_factor": method})
+}
+
+// store a challenge for the second login step and hand its token to the client
+func (userUsc *userUseCase) twoFactorChallenge(ctx context.Context, user *domain.User) error {
+
+	// generate token, only its hash is stored
+	plain, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	challenge := &domain.TwoFactorChallenge{
+		UserID:    user.ID,
+		TokenHash: hashToken(plain),
+		ExpiresAt: now.Add(userUsc.challengeTTL),
+		CreatedAt: now,
+	}
+	if err := userUsc.challengeRepo.CreateChallenge(ctx, challenge); err != nil {
+		return err
+	}
+
+	return &domain.TwoFactorRequiredError{ChallengeToken: plain, ExpiresAt: challenge.ExpiresAt}
+}
+
+// accept an app code (once) or use up a backup code, returns which one it was
+func (userUsc *userUseCase) useTwoFactorCode(ctx context.Context, user *domain.User, code string) (string, error) {
+
+	code = normalizeTwoFactorCode(code)
+	if step, ok := userUsc.totp.Validate(user.TwoFactor.Secret, code, time.Now()); ok {
+		return "totp", userUsc.userRepo.UseTwoFactorStep(ctx, user.ID, step)
+	}
+	if err := userUsc.userRepo.UseBackupCode(ctx, user.ID, hashToken(code)); err != nil {
+		return "", err
+	}
+	return "backup_code", nil
+}
+
+// load the caller's account for two-factor changes
+func (userUsc *userUseCase) twoFactorUser(ctx context.Context, userID string) (*domain.User, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	return userUsc.userRepo.GetUserById(ctx, objID)
+}
+
+// codes may be typed with spaces or dashes and in any case
+func normalizeTwoFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
@@ -0,0 +1,203 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"errors";
+	"testing";
+	"time";
+	"github.com/dgrijalva/jwt-go";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+)
+
+// passwords stored as they are
+type plainPasswordService struct{}
+
+func (plainPasswordService) HashPassword(password string) (string, error) { return password, nil }
+func (plainPasswordService) CheckPassword(hashed, plain string) bool    { return hashed == plain }
+func (plainPasswordService) NeedsRehash(hashed string) bool             { return false }
+
+// the only valid app code is "123456"
+type fixedTOTPService struct{}
+
+func (fixedTOTPService) GenerateSecret() (string, error)                      { return "SECRET", nil }
+func (fixedTOTPService) ProvisioningURI(secret string, account string) string { return "" }
+func (fixedTOTPService) Validate(secret string, code string, at time.Time) (int64, bool) {
+	return at.Unix() / 30, code == "123456"
+}
+
+// tokens name the user they were issued for
+type stubJWTService struct{}
+
+func (stubJWTService) GenerateToken(userID, username, role, tenantID, sessionID string) (string, error) {
+	return "token-" + userID, nil
+}
+func (stubJWTService) GenerateScopedToken(userID, username, role, tenantID, clientID string, scopes []string, ttl time.Duration) (string, error) {
+	return "token-" + userID, nil
+}
+func (stubJWTService) ValidateToken(tokenStr string) (*jwt.Token, error) { return nil, errors.New("not supported") }
+func (stubJWTService) JWKS() domain.JSONWebKeySet                       { return domain.JSONWebKeySet{} }
+
+// sessions that are never stored
+type stubSessionStarter struct{}
+
+func (stubSessionStarter) StartSession(ctx context.Context, user *domain.User) (*domain.Session, error) {
+	return &domain.Session{}, nil
+}
+func (stubSessionStarter) RevokeUserSessions(ctx context.Context, userID string, reason string) (int, error) {
+	return 0, nil
+}
+func (stubSessionStarter) RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) (int, error) {
+	return 0, nil
+}
+
+// audit sink, event publisher and extension hooks that do nothing
+type discardSinks struct{}
+
+func (discardSinks) Emit(ctx context.Context, event domain.AuditEvent)       {}
+func (discardSinks) Publish(ctx context.Context, event domain.DomainEvent)   {}
+func (discardSinks) PreTaskCreate(ctx context.Context, task *domain.Task) error { return nil }
+func (discardSinks) PostTaskCreate(ctx context.Context, task *domain.Task)   {}
+func (discardSinks) PreLogin(ctx context.Context, username string) error     { return nil }
+func (discardSinks) DecorateResponse(ctx context.Context, method string, path string, status int) map[string]string {
+	return nil
+}
+
+// login challenges kept in memory
+type memoryChallengeRepository struct {
+	challenges  map[string]*domain.TwoFactorChallenge
+}
+
+func (challengeRepo *memoryChallengeRepository) CreateChallenge(ctx context.Context, challenge *domain.TwoFactorChallenge) error {
+	challenge.ID = primitive.NewObjectID()
+	challengeRepo.challenges[challenge.TokenHash] = challenge
+	return nil
+}
+
+func (challengeRepo *memoryChallengeRepository) GetChallengeByHash(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error) {
+	challenge, ok := challengeRepo.challenges[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	return challenge, nil
+}
+
+func (challengeRepo *memoryChallengeRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) (int, error) {
+	for _, challenge := range challengeRepo.challenges {
+		if challenge.ID == id {
+			challenge.Attempts++
+			return challenge.Attempts, nil
+		}
+	}
+	return 0, domain.ErrInvalidTwoFactorChallenge
+}
+
+func (challengeRepo *memoryChallengeRepository) DeleteChallenge(ctx context.Context, id primitive.ObjectID) error {
+	for hash, challenge := range challengeRepo.challenges {
+		if challenge.ID == id {
+			delete(challengeRepo.challenges, hash)
+		}
+	}
+	return nil
+}
+
+func (challengeRepo *memoryChallengeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// user usecase over the in-memory user repository with one two-factor account
+type twoFactorFixture struct {
+	userRepo  domain.UserRepository
+	users     UserUseCase
+	user      *domain.User
+}
+
+func newTwoFactorFixture(t *testing.T, maxFailedLogins int) *twoFactorFixture {
+	t.Helper()
+	fixture := &twoFactorFixture{userRepo: repositories.NewMemoryUserRepository()}
+	fixture.users = NewUserUseCase(fixture.userRepo, nil, nil, nil, stubJWTService{}, stubSessionStarter{}, nil, fixedTOTPService{}, &memoryChallengeRepository{challenges: map[string]*domain.TwoFactorChallenge{}}, plainPasswordService{}, discardSinks{}, nil, discardSinks{}, discardSinks{}, repositories.NewDirectUnitOfWork(), discardLogger{}, maxFailedLogins, time.Hour, false, time.Minute)
+
+	now := time.Now().UTC()
+	fixture.user = &domain.User{Username: "alice", Password: "Secret123!", Role: domain.RoleUser, TwoFactor: &domain.TwoFactorSettings{Secret: "SECRET", EnabledAt: &now}}
+	if err := fixture.userRepo.CreateUser(context.Background(), fixture.user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return fixture
+}
+
+// log in with the right password and return the challenge token
+func (fixture *twoFactorFixture) challenge(t *testing.T) string {
+	t.Helper()
+	_, _, err := fixture.users.Login(context.Background(), &domain.Credentials{Username: "alice", Password: "Secret123!"})
+	var required *domain.TwoFactorRequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("Login error = %v, want a two-factor challenge", err)
+	}
+	return required.ChallengeToken
+}
+
+// failed logins counted for the account so far
+func (fixture *twoFactorFixture) failedLogins(t *testing.T) int {
+	t.Helper()
+	user, err := fixture.userRepo.GetUserById(context.Background(), fixture.user.ID)
+	if err != nil {
+		t.Fatalf("GetUserById: %v", err)
+	}
+	return user.FailedLogins
+}
+
+func TestWrongTwoFactorCodesLockTheAccount(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTwoFactorFixture(t, 3)
+
+	// new challenges don't start the count over
+	for i := 1; i < 3; i++ {
+		token := fixture.challenge(t)
+		if _, _, err := fixture.users.CompleteTwoFactorLogin(ctx, token, "000000"); err != domain.ErrInvalidTwoFactorCode {
+			t.Fatalf("wrong code %d error = %v, want %v", i, err, domain.ErrInvalidTwoFactorCode)
+		}
+		if got := fixture.failedLogins(t); got != i {
+			t.Fatalf("failed logins after wrong code %d = %d, want %d", i, got, i)
+		}
+	}
+
+	token := fixture.challenge(t)
+	if _, _, err := fixture.users.CompleteTwoFactorLogin(ctx, token, "000000"); err != domain.ErrAccountLocked {
+		t.Fatalf("wrong code at the limit error = %v, want %v", err, domain.ErrAccountLocked)
+	}
+
+	// the lock holds for the right password and code as well
+	if _, _, err := fixture.users.Login(ctx, &domain.Credentials{Username: "alice", Password: "Secret123!"}); err != domain.ErrAccountLocked {
+		t.Errorf("Login while locked error = %v, want %v", err, domain.ErrAccountLocked)
+	}
+	if _, _, err := fixture.users.CompleteTwoFactorLogin(ctx, token, "123456"); err != domain.ErrInvalidTwoFactorChallenge {
+		t.Errorf("challenge reused after the lock error = %v, want %v", err, domain.ErrInvalidTwoFactorChallenge)
+	}
+}
+
+func TestFailedLoginsResetOnlyAfterTheSecondFactor(t *testing.T) {
+
+	ctx := context.Background()
+	fixture := newTwoFactorFixture(t, 5)
+
+	token := fixture.challenge(t)
+	if _, _, err := fixture.users.CompleteTwoFactorLogin(ctx, token, "000000"); err != domain.ErrInvalidTwoFactorCode {
+		t.Fatalf("wrong code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+
+	// the right password alone keeps the count
+	token = fixture.challenge(t)
+	if got := fixture.failedLogins(t); got != 1 {
+		t.Fatalf("failed logins after the password step = %d, want 1", got)
+	}
+
+	if _, _, err := fixture.users.CompleteTwoFactorLogin(ctx, token, "123456"); err != nil {
+		t.Fatalf("CompleteTwoFactorLogin: %v", err)
+	}
+	if got := fixture.failedLogins(t); got != 0 {
+		t.Errorf("failed logins after the code step = %d, want 0", got)
+	}
+}
@@ -2,101 +2,254 @@ package usecases
 
 // imports
 import (
+	"context";
 	"errors";
+	"slices";
+	"strconv";
+	"strings";
+	"time";
 	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
 	"go.mongodb.org/mongo-driver/bson/primitive";
 )
 
 // user usecase
 type UserUseCase interface {
-	Register(user *domain.User) error
-	Login(credentials *domain.Credentials) (string, *domain.User, error)
-	PromoteToAdmin(userID string) error
+	Register(ctx context.Context, user *domain.User) error
+	Login(ctx context.Context, credentials *domain.Credentials) (string, *domain.User, error)
+	ExternalLogin(ctx context.Context, identity *domain.ExternalIdentity) (string, *domain.User, error)      // sign in with a verified google/github account, linking or creating the user on first use
+	PromoteToAdmin(ctx context.Context, userID string) error
+	UnlockUser(ctx context.Context, userID string) error
+	GetProfile(ctx context.Context, userID string) (*domain.User, error)
+	CurrentRole(ctx context.Context, userID string) (string, error)                                      // role requests of the user are authorized with (error while deactivated)
+	UpdateProfile(ctx context.Context, userID string, profile *domain.UpdateProfileRequest) (*domain.User, error)
+	ChangePassword(ctx context.Context, userID string, currentPassword string, newPassword string) error
+	ListUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error)                     // page of users for admins
+	GetUser(ctx context.Context, userID string) (*domain.UserDetail, error)                             // account and project memberships of a user
+	DeactivateUser(ctx context.Context, userID string) error                                            // block logins and end the user's sessions
+	ReactivateUser(ctx context.Context, userID string) error                                            // allow logins again
+	DemoteAdmin(ctx context.Context, userID string) error                                               // turn an admin back into a user
+	DeleteUser(ctx context.Context, userID string, reassignTo string) (*domain.UserDeletion, error)     // delete a user, projects they own alone are handed to reassignTo or deleted with their tasks
+	SetupTwoFactor(ctx context.Context, userID string) (*domain.TwoFactorSetup, error)                  // new authenticator app secret, enabled once a code from it is confirmed
+	EnableTwoFactor(ctx context.Context, userID string, code string) (*domain.TwoFactorBackupCodes, error)       // confirm a code of the new secret, returns the backup codes
+	DisableTwoFactor(ctx context.Context, userID string, code string) error                             // turn off the second login step with an app or backup code
+	CompleteTwoFactorLogin(ctx context.Context, challengeToken string, code string) (string, *domain.User, error)      // second login step, issues the token like Login
 }
 
 type userUseCase struct {
 	userRepo     domain.UserRepository
+	projectRepo  domain.ProjectRepository
+	taskRepo     domain.TaskRepository
+	trashRepo    domain.TaskTrashRepository        // tasks of projects deleted with their owner are kept until purged
 	jwtService  domain.JWTService
+	sessions     domain.SessionStarter
+	verifier     domain.EmailVerifier             // emails verification links to new accounts
+	totp         domain.TOTPService               // authenticator app codes of two-factor logins
+	challengeRepo domain.TwoFactorChallengeRepository       // logins waiting for their second step
 	pwdService   domain.PasswordService
+	auditSink    domain.AuditSink
+	auditLogRepo domain.AuditLogRepository
+	events       domain.EventPublisher
+	extensions   domain.ExtensionHooks
+	unitOfWork   domain.UnitOfWork
+	logger       domain.Logger
+	maxFailedLogins  int              // failed logins before the account is locked (0 disables lockout)
+	lockoutDuration  time.Duration    // how long a locked account stays locked
+	requireVerifiedEmail bool         // refuse logins of accounts whose registration email isn't verified yet
+	challengeTTL     time.Duration    // how long a password login waits for its two-factor code
+	handlers     []domain.TaskEventHandler       // same handlers as task commands, so tasks of deleted projects are published like deleted tasks
 }
 
 // creates new UserUseCase instance
-func NewUserUseCase(userRepo domain.UserRepository, jwtServ domain.JWTService, pwdServ domain.PasswordService,) UserUseCase {
-	return &userUseCase{ userRepo:userRepo, jwtService:jwtServ, pwdService:pwdServ}
+func NewUserUseCase(userRepo domain.UserRepository, projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, trashRepo domain.TaskTrashRepository, jwtServ domain.JWTService, sessions domain.SessionStarter, verifier domain.EmailVerifier, totp domain.TOTPService, challengeRepo domain.TwoFactorChallengeRepository, pwdServ domain.PasswordService, auditSink domain.AuditSink, auditLogRepo domain.AuditLogRepository, events domain.EventPublisher, extensions domain.ExtensionHooks, unitOfWork domain.UnitOfWork, logger domain.Logger, maxFailedLogins int, lockoutDuration time.Duration, requireVerifiedEmail bool, challengeTTL time.Duration, handlers ...domain.TaskEventHandler) UserUseCase {
+	return &userUseCase{ userRepo:userRepo, projectRepo:projectRepo, taskRepo:taskRepo, trashRepo:trashRepo, jwtService:jwtServ, sessions:sessions, verifier:verifier, totp:totp, challengeRepo:challengeRepo, pwdService:pwdServ, auditSink:auditSink, auditLogRepo:auditLogRepo, events:events, extensions:extensions, unitOfWork:unitOfWork, logger:logger, maxFailedLogins:maxFailedLogins, lockoutDuration:lockoutDuration, requireVerifiedEmail:requireVerifiedEmail, challengeTTL:challengeTTL, handlers:handlers}
 }
 
 // register user
-func (userUsc *userUseCase) Register(user *domain.User) error {
+func (userUsc *userUseCase) Register(ctx context.Context, user *domain.User) error {
 	
 	// validate input
+	invalid := &domain.ValidationError{}
 	if user.Username == "" {
-		return errors.New("username cannot be empty")
+		invalid.Add("username", "cannot be empty")
 	}
 	if user.Password == "" {
-		return errors.New("password cannot be empty")
+		invalid.Add("password", "cannot be empty")
+	} else if len(user.Password) < 8 {
+		invalid.Add("password", "must be at least 8 characters")
 	}
-	if len(user.Password) < 8 {
-		return errors.New("password must be at least 8 characters")
+	if err := invalid.Err(); err != nil {
+		return err
+	}
+	// the first account is the admin created through setup
+	count, err := userUsc.userRepo.GetUserCount(ctx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrSetupRequired
 	}
-	// check if user already exists
-	existing, err := userUsc.userRepo.GetByUsername(user.Username)
+	// check if user already exists (a retry of the same registration succeeds again)
+	existing, err := userUsc.userRepo.GetByUsername(ctx, user.Username)
 	if err != nil && err != domain.ErrUserNotFound {
 		return err
 	}
 	if existing != nil {
-		return domain.ErrUserExists
+		return userUsc.repeatedRegistration(existing, user)
+	}
+	if user.Email != "" {
+		if _, err := userUsc.userRepo.GetByEmail(ctx, user.Email); err == nil {
+			return domain.ErrUserExists
+		} else if err != domain.ErrUserNotFound {
+			return err
+		}
 	}
 
 	// hash password securely 
+	password := user.Password
 	hashed, err := userUsc.pwdService.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
 	user.Password = hashed       // set user password to hashed password
 
-	// set default role
-	user.Role = "user"
+	// set default role (admins come from setup or promotion)
+	user.Role = domain.RoleUser
+	user.EmailVerificationPending = user.Email != ""        // confirmed through the emailed link
 
-	// first user becomes admin
-	count, err := userUsc.userRepo.GetUserCount()
+	err = userUsc.userRepo.CreateUser(ctx, user)
+	if err == domain.ErrUserExists {
+		// a concurrent request won the unique index, succeed if it was the same registration
+		existing, getErr := userUsc.userRepo.GetByUsername(ctx, user.Username)
+		if getErr != nil {
+			return err
+		}
+		user.Password = password
+		return userUsc.repeatedRegistration(existing, user)
+	}
 	if err != nil {
 		return err
 	}
-	if count == 0 {
-		user.Role = "admin"
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditUserRegistered,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  user.ID.Hex(),
+		Actor:    user.Username,
+		Details:  map[string]string{"role": user.Role},
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserRegistered, domain.AuditEntityUser, user.ID.Hex(), userSnapshot(*profileOf(user)))
+
+	// the account exists either way, a failed email can be sent again through the resend endpoint
+	if user.EmailVerificationPending {
+		if err := userUsc.verifier.SendVerification(ctx, user); err != nil {
+			userUsc.logger.Warn(ctx, "verification email not sent after registration", "user_id", user.ID.Hex(), "error", err)
+		}
 	}
 
-	return userUsc.userRepo.CreateUser(user)
+	return nil
+}
+
+// registration retried with the same username, email and password succeeds without creating anything
+func (userUsc *userUseCase) repeatedRegistration(existing *domain.User, user *domain.User) error {
+	if user.Email == "" || existing.Email != user.Email || !userUsc.pwdService.CheckPassword(existing.Password, user.Password) {
+		return domain.ErrUserExists
+	}
+	*user = *profileOf(existing)
+	return nil
 }
 
 // authenticate user
-func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *domain.User, error) {
+func (userUsc *userUseCase) Login(ctx context.Context, credentials *domain.Credentials) (string, *domain.User, error) {
 	
 	// validate input
 	if credentials.Username == "" || credentials.Password == "" {
 		return "", nil, errors.New("username and password are required")
 	}
+	// extensions may block logins (ip allow lists, maintenance windows, ...)
+	if err := userUsc.extensions.PreLogin(ctx, credentials.Username); err != nil {
+		userUsc.auditLoginFailure(ctx, credentials.Username, "", "rejected by extension")
+		return "", nil, err
+	}
 
 	// get user from repository
-	user, err := userUsc.userRepo.GetByUsername(credentials.Username)
+	user, err := userUsc.userRepo.GetByUsername(ctx, credentials.Username)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
+			userUsc.auditLoginFailure(ctx, credentials.Username, "", "unknown user")
 			return "", nil, domain.ErrInvalidCredentials
 		}
 		return "", nil, err
 	}
 
+	// locked accounts are refused before the password is checked
+	if user.IsLocked(time.Now()) {
+		userUsc.auditLoginFailure(ctx, credentials.Username, user.ID.Hex(), "account locked")
+		return "", nil, domain.ErrAccountLocked
+	}
+
 	// verify password
 	if !userUsc.pwdService.CheckPassword(user.Password, credentials.Password) {
-		return "", nil, domain.ErrInvalidCredentials
+		userUsc.auditLoginFailure(ctx, credentials.Username, user.ID.Hex(), "wrong password")
+		return "", nil, userUsc.recordFailedLogin(ctx, user)
+	}
+
+	// deactivated accounts are refused once the password is verified (wrong guesses learn nothing)
+	if user.IsDeactivated() {
+		userUsc.auditLoginFailure(ctx, credentials.Username, user.ID.Hex(), "account deactivated")
+		return "", nil, domain.ErrAccountDeactivated
+	}
+	if userUsc.requireVerifiedEmail && user.EmailVerificationPending {
+		userUsc.auditLoginFailure(ctx, credentials.Username, user.ID.Hex(), "email not verified")
+		return "", nil, domain.ErrEmailNotVerified
+	}
+
+	// upgrade hashes made with older hashing settings while the plain password is at hand
+	if userUsc.pwdService.NeedsRehash(user.Password) {
+		userUsc.rehashPassword(ctx, user.ID, credentials.Password)
 	}
 
-	// generate jwt token
-	token, err := userUsc.jwtService.GenerateToken(user.ID.Hex(), user.Username, user.Role)
+	// the password is right, accounts with two-factor authentication still need a code
+	// (failures keep counting until it is entered, wrong codes count toward the lockout too)
+	if user.TwoFactor.IsEnabled() {
+		return "", nil, userUsc.twoFactorChallenge(ctx, user)
+	}
+
+	// successful login starts counting failures again
+	if err := userUsc.clearFailedLogins(ctx, user); err != nil {
+		return "", nil, err
+	}
+
+	return userUsc.startSession(ctx, user, nil)
+}
+
+// start a session and issue its token (details are added to the audit event)
+func (userUsc *userUseCase) startSession(ctx context.Context, user *domain.User, details map[string]string) (string, *domain.User, error) {
+
+	// every login is a session, older ones may be revoked to stay within the workspace limit
+	session, err := userUsc.sessions.StartSession(ctx, user)
 	if err != nil {
 		return "", nil, err
 	}
 
+	// generate jwt token (read-only instances don't store sessions)
+	sessionID := ""
+	if !session.ID.IsZero() {
+		sessionID = session.ID.Hex()
+	}
+	token, err := userUsc.jwtService.GenerateToken(user.ID.Hex(), user.Username, user.Role, user.TenantID, sessionID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditLoginSucceeded,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  user.ID.Hex(),
+		Actor:    user.Username,
+		Details:  details,
+	})
+
 	// return token and user (without sensitive data)
 	returnUser := &domain.User{
 		ID:       user.ID,
@@ -107,8 +260,169 @@ func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *dom
 	return token, returnUser, nil
 }
 
+// sign in with an identity the provider verified
+func (userUsc *userUseCase) ExternalLogin(ctx context.Context, identity *domain.ExternalIdentity) (string, *domain.User, error) {
+
+	user, err := userUsc.userRepo.GetByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == domain.ErrUserNotFound {
+		user, err = userUsc.linkOrCreateUser(ctx, identity)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// extensions may block logins (ip allow lists, maintenance windows, ...)
+	if err := userUsc.extensions.PreLogin(ctx, user.Username); err != nil {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "rejected by extension")
+		return "", nil, err
+	}
+	// a lockout holds back the password and two-factor steps, deactivation blocks every way in
+	if user.IsDeactivated() {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "account deactivated")
+		return "", nil, domain.ErrAccountDeactivated
+	}
+	if userUsc.requireVerifiedEmail && user.EmailVerificationPending {
+		userUsc.auditLoginFailure(ctx, user.Username, user.ID.Hex(), "email not verified")
+		return "", nil, domain.ErrEmailNotVerified
+	}
+	if user.TwoFactor.IsEnabled() {
+		return "", nil, userUsc.twoFactorChallenge(ctx, user)
+	}
+
+	return userUsc.startSession(ctx, user, map[string]string{"provider": identity.Provider})
+}
+
+// first sign-in with a provider account: link it to the user owning the verified email, or create a user
+func (userUsc *userUseCase) linkOrCreateUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+
+	link := domain.LinkedIdentity{Provider: identity.Provider, Subject: identity.Subject, LinkedAt: time.Now()}
+
+	// unverified addresses could belong to anyone, they neither link nor end up on the new account
+	email := ""
+	if identity.EmailVerified {
+		email = identity.Email
+	}
+	if email != "" {
+		existing, err := userUsc.userRepo.GetByEmail(ctx, email)
+		if err != nil && err != domain.ErrUserNotFound {
+			return nil, err
+		}
+		if existing != nil {
+			// the account owner must have proven the address too, or whoever registered it first gets the provider account
+			if existing.EmailVerifiedAt == nil || existing.EmailVerificationPending {
+				userUsc.auditLoginFailure(ctx, existing.Username, existing.ID.Hex(), "account email not verified, identity not linked")
+				return nil, domain.ErrIdentityLinkRequired
+			}
+			if err := userUsc.userRepo.LinkIdentity(ctx, existing.ID, link); err != nil {
+				return nil, err
+			}
+			existing.Identities = append(existing.Identities, link)
+			userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+				Type:     domain.AuditIdentityLinked,
+				Outcome:  domain.AuditOutcomeSuccess,
+				ActorID:  existing.ID.Hex(),
+				Actor:    existing.Username,
+				Details:  map[string]string{"provider": identity.Provider, "email": email},
+			})
+			return existing, nil
+		}
+	}
+
+	// the first account is the admin created through setup
+	count, err := userUsc.userRepo.GetUserCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, domain.ErrSetupRequired
+	}
+
+	username, err := userUsc.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	// random password nobody knows, a password can be set later through a reset
+	secret, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := userUsc.pwdService.HashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Username:    username,
+		Email:       email,
+		DisplayName: identity.Name,
+		Password:    hashed,
+		Role:        domain.RoleUser,
+		Identities:  []domain.LinkedIdentity{link},
+	}
+	if email != "" {
+		user.EmailVerifiedAt = &link.LinkedAt        // the provider verified it, so other providers may link to this account
+	}
+	if err := userUsc.userRepo.CreateUser(ctx, user); err != nil {
+		if err == domain.ErrUserExists {
+			// a concurrent callback of the same account created it first
+			if existing, getErr := userUsc.userRepo.GetByIdentity(ctx, identity.Provider, identity.Subject); getErr == nil {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditUserRegistered,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  user.ID.Hex(),
+		Actor:    user.Username,
+		Details:  map[string]string{"role": user.Role, "provider": identity.Provider},
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserRegistered, domain.AuditEntityUser, user.ID.Hex(), userSnapshot(*profileOf(user)))
+
+	return user, nil
+}
+
+// username for a new account from the provider login (letters and digits, numbered when taken)
+func (userUsc *userUseCase) availableUsername(ctx context.Context, identity *domain.ExternalIdentity) (string, error) {
+
+	base := []rune{}
+	for _, r := range identity.Username {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			base = append(base, r)
+		}
+	}
+	if len(base) > 28 {
+		base = base[:28]
+	}
+	if len(base) < 3 {
+		base = []rune(identity.Provider + "user")
+	}
+
+	candidate := string(base)
+	for suffix := 2; suffix <= 100; suffix++ {
+		_, err := userUsc.userRepo.GetByUsername(ctx, candidate)
+		if err == domain.ErrUserNotFound {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = string(base) + strconv.Itoa(suffix)
+	}
+
+	// crowded name, fall back to a random one
+	random, err := generateRandomToken(4)
+	if err != nil {
+		return "", err
+	}
+	return string(base) + random, nil
+}
+
 // promote a user to admin role (only admin can do this)
-func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
+func (userUsc *userUseCase) PromoteToAdmin(ctx context.Context, userID string) error {
 	
 	// validate input
 	if userID == "" {
@@ -121,7 +435,7 @@ func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
 	}
 
 	// check if user exists
-	_, err = userUsc.userRepo.GetUserById(objID)
+	existing, err := userUsc.userRepo.GetUserById(ctx, objID)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return domain.ErrUserNotFound
@@ -129,6 +443,648 @@ func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
 		return err
 	}
 
-	// update role
-	return userUsc.userRepo.UpdateRole(objID, "admin")
+	// snapshots never include the password hash
+	before := domain.User{ID: existing.ID, Username: existing.Username, Role: existing.Role}
+	after := before
+	after.Role = domain.RoleAdmin
+
+	// update role and write the audit log entry together
+	err = userUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := userUsc.userRepo.UpdateRole(ctx, objID, domain.RoleAdmin); err != nil {
+			return err
+		}
+		return writeAuditLog(ctx, userUsc.auditLogRepo, domain.AuditLogEntry{
+			Action:      domain.AuditActionPromote,
+			EntityType:  domain.AuditEntityUser,
+			EntityID:    userID,
+			Before:      userSnapshot(before),
+			After:       userSnapshot(after),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserPromoted,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  userID,
+		Details:   map[string]string{"role": domain.RoleAdmin},
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserPromoted, domain.AuditEntityUser, userID, userSnapshot(after))
+
+	return nil
+}
+
+// clear failed logins and lock of a user (only admin can do this)
+func (userUsc *userUseCase) UnlockUser(ctx context.Context, userID string) error {
+	
+	// validate input
+	if userID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+
+	// check if user exists
+	existing, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	err = userUsc.userRepo.ResetFailedLogins(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserUnlocked,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  userID,
+	})
+
+	before := domain.User{ID: existing.ID, Username: existing.Username, Role: existing.Role, LockedUntil: existing.LockedUntil}
+	after := before
+	after.LockedUntil = nil
+	recordAuditLog(ctx, userUsc.auditLogRepo, userUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUnlock,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    userID,
+		Before:      userSnapshot(before),
+		After:       userSnapshot(after),
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserUnlocked, domain.AuditEntityUser, userID, userSnapshot(after))
+
+	return nil
+}
+
+// get own profile (without credentials)
+func (userUsc *userUseCase) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	return profileOf(user), nil
+}
+
+// current role of a user (tokens carry the role at login)
+func (userUsc *userUseCase) CurrentRole(ctx context.Context, userID string) (string, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return "", domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return "", err
+	}
+	// tokens without a session (oauth access tokens) would otherwise outlive the deactivation
+	if user.IsDeactivated() {
+		return "", domain.ErrAccountDeactivated
+	}
+
+	return user.Role, nil
+}
+
+// update own email, display name and notification preferences
+func (userUsc *userUseCase) UpdateProfile(ctx context.Context, userID string, profile *domain.UpdateProfileRequest) (*domain.User, error) {
+
+	// stop if nothing valid to update
+	if profile.Email == "" && profile.DisplayName == "" && profile.Notifications == nil {
+		return nil, domain.NewValidationError("body", "no valid fields provided for update")
+	}
+	if profile.Notifications != nil {
+		for _, muted := range profile.Notifications.Muted {
+			if !slices.Contains(domain.NotificationTypes, muted) {
+				return nil, domain.NewValidationError("notifications.muted", "must be one of: "+strings.Join(domain.NotificationTypes, ", "))
+			}
+		}
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	// keep the current state for the audit log
+	existing, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := userUsc.userRepo.UpdateProfile(ctx, objID, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, userUsc.auditLogRepo, userUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    userID,
+		Before:      userSnapshot(*profileOf(existing)),
+		After:       userSnapshot(*profileOf(updated)),
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserUpdated, domain.AuditEntityUser, userID, userSnapshot(*profileOf(updated)))
+
+	return profileOf(updated), nil
+}
+
+// change own password after verifying the current one
+func (userUsc *userUseCase) ChangePassword(ctx context.Context, userID string, currentPassword string, newPassword string) error {
+
+	// validate input
+	if len(newPassword) < 8 {
+		return domain.NewValidationError("new_password", "must be at least 8 characters")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	// verify current password
+	if !userUsc.pwdService.CheckPassword(user.Password, currentPassword) {
+		userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+			Type:     domain.AuditPasswordChanged,
+			Outcome:  domain.AuditOutcomeFailure,
+			ActorID:  userID,
+			Actor:    user.Username,
+			Details:  map[string]string{"reason": "wrong current password"},
+		})
+		return domain.ErrWrongPassword
+	}
+
+	// hash password securely 
+	hashed, err := userUsc.pwdService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := userUsc.userRepo.UpdatePassword(ctx, objID, hashed); err != nil {
+		return err
+	}
+	// other logins end, the one changing the password stays
+	actor, _ := domain.ActorFromContext(ctx)
+	if _, err := userUsc.sessions.RevokeOtherSessions(ctx, userID, actor.SessionID, domain.SessionRevokedPassword); err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditPasswordChanged,
+		Outcome:  domain.AuditOutcomeSuccess,
+		ActorID:  userID,
+		Actor:    user.Username,
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserPasswordChanged, domain.AuditEntityUser, userID, nil)
+
+	return nil
+}
+
+// replace password hash, the login already succeeded so a failure is only logged
+func (userUsc *userUseCase) rehashPassword(ctx context.Context, userID primitive.ObjectID, password string) {
+
+	hashed, err := userUsc.pwdService.HashPassword(password)
+	if err == nil {
+		err = userUsc.userRepo.UpdatePassword(ctx, userID, hashed)
+	}
+	if err != nil {
+		userUsc.logger.Warn(ctx, "password rehash failed", "user_id", userID.Hex(), "error", err)
+	}
+}
+
+// page of users matching query (only admin can do this)
+func (userUsc *userUseCase) ListUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+
+	// validate filters and default the page
+	if query.Role != "" && query.Role != domain.RoleUser && query.Role != domain.RoleAdmin {
+		return nil, domain.ErrInvalidUserQuery
+	}
+	if query.Status != "" && query.Status != domain.UserStatusActive && query.Status != domain.UserStatusDeactivated {
+		return nil, domain.ErrInvalidUserQuery
+	}
+	if _, ok := domain.UserSortFields[query.Sort.Field]; query.Sort.Field != "" && !ok {
+		return nil, domain.ErrInvalidUserQuery
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = domain.DefaultUserPageSize
+	}
+	if query.Limit > domain.MaxUserPageSize {
+		query.Limit = domain.MaxUserPageSize
+	}
+
+	users, total, err := userUsc.userRepo.ListUsers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.UserPage{Users: make([]domain.UserSummary, 0, len(users)), Total: total, Page: query.Page, Limit: query.Limit}
+	for i := range users {
+		page.Users = append(page.Users, users[i].Summary())
+	}
+	return page, nil
+}
+
+// account and project memberships of a user (only admin can do this)
+func (userUsc *userUseCase) GetUser(ctx context.Context, userID string) (*domain.UserDetail, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := userUsc.projectRepo.GetProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.UserDetail{UserSummary: user.Summary(), Projects: []domain.ProjectMembership{}}
+	for i := range projects {
+		detail.Projects = append(detail.Projects, domain.ProjectMembership{ProjectID: projects[i].ID, Name: projects[i].Name, Role: projects[i].RoleOf(userID)})
+	}
+	return detail, nil
+}
+
+// block logins and end the user's sessions (only admin can do this)
+func (userUsc *userUseCase) DeactivateUser(ctx context.Context, userID string) error {
+
+	user, err := userUsc.managedUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsDeactivated() {
+		return nil        // already deactivated
+	}
+	if err := userUsc.checkNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if err := userUsc.userRepo.SetDeactivated(ctx, user.ID, &now); err != nil {
+		return err
+	}
+	// login tokens carry no account state, their sessions end instead
+	if _, err := userUsc.sessions.RevokeUserSessions(ctx, userID, domain.SessionRevokedAccount); err != nil {
+		return err
+	}
+
+	before := domain.User{ID: user.ID, Username: user.Username, Role: user.Role}
+	after := before
+	after.DeactivatedAt = &now
+	userUsc.recordAccountChange(ctx, domain.AuditActionDeactivate, domain.AuditUserDeactivated, domain.EventUserDeactivated, before, after)
+
+	return nil
+}
+
+// allow logins again (only admin can do this)
+func (userUsc *userUseCase) ReactivateUser(ctx context.Context, userID string) error {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if !user.IsDeactivated() {
+		return nil        // already active
+	}
+	if err := userUsc.userRepo.SetDeactivated(ctx, objID, nil); err != nil {
+		return err
+	}
+
+	before := domain.User{ID: user.ID, Username: user.Username, Role: user.Role, DeactivatedAt: user.DeactivatedAt}
+	after := before
+	after.DeactivatedAt = nil
+	userUsc.recordAccountChange(ctx, domain.AuditActionReactivate, domain.AuditUserReactivated, domain.EventUserReactivated, before, after)
+
+	return nil
+}
+
+// turn an admin back into a user (only admin can do this)
+func (userUsc *userUseCase) DemoteAdmin(ctx context.Context, userID string) error {
+
+	user, err := userUsc.managedUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != domain.RoleAdmin {
+		return domain.ErrUserNotAdmin
+	}
+	if err := userUsc.checkNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	before := domain.User{ID: user.ID, Username: user.Username, Role: user.Role}
+	after := before
+	after.Role = domain.RoleUser
+
+	// update role and write the audit log entry together
+	err = userUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := userUsc.userRepo.UpdateRole(ctx, user.ID, domain.RoleUser); err != nil {
+			return err
+		}
+		return writeAuditLog(ctx, userUsc.auditLogRepo, domain.AuditLogEntry{
+			Action:      domain.AuditActionDemote,
+			EntityType:  domain.AuditEntityUser,
+			EntityID:    userID,
+			Before:      userSnapshot(before),
+			After:       userSnapshot(after),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	// the demoted admin signs in again with the new role
+	if _, err := userUsc.sessions.RevokeUserSessions(ctx, userID, domain.SessionRevokedAccount); err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserDemoted,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  userID,
+		Details:   map[string]string{"role": domain.RoleUser},
+	})
+	publishEvent(ctx, userUsc.events, domain.EventUserDemoted, domain.AuditEntityUser, userID, userSnapshot(after))
+
+	return nil
+}
+
+// delete a user (only admin can do this)
+// projects the user owns alone go to reassignTo, or are deleted with their tasks when it is empty
+func (userUsc *userUseCase) DeleteUser(ctx context.Context, userID string, reassignTo string) (*domain.UserDeletion, error) {
+
+	user, err := userUsc.managedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := userUsc.checkNotLastAdmin(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// the new owner must be another active account
+	if reassignTo != "" {
+		newOwnerID, err := primitive.ObjectIDFromHex(reassignTo)
+		if err != nil || reassignTo == userID {
+			return nil, errors.New("reassign_to must be the id of another user")
+		}
+		newOwner, err := userUsc.userRepo.GetUserById(ctx, newOwnerID)
+		if err == domain.ErrUserNotFound {
+			return nil, errors.New("user to reassign projects to not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if newOwner.IsDeactivated() {
+			return nil, errors.New("cannot reassign projects to a deactivated user")
+		}
+	}
+
+	projects, err := userUsc.projectRepo.GetProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// sessions end first, a failed deletion only means logging in again
+	report := &domain.UserDeletion{}
+	report.SessionsRevoked, err = userUsc.sessions.RevokeUserSessions(ctx, userID, domain.SessionRevokedAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// projects, account and audit log entry go together, or none of them
+	var removed []domain.Task
+	err = userUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		*report = domain.UserDeletion{SessionsRevoked: report.SessionsRevoked}        // transactions may run again
+		removed = nil
+		for i := range projects {
+			project := &projects[i]
+			if lastOwner(project, userID) && reassignTo == "" {
+				tasks, err := userUsc.deleteProjectWithTasks(ctx, project)
+				if err != nil {
+					return err
+				}
+				report.ProjectsDeleted++
+				report.TasksDeleted += len(tasks)
+				removed = append(removed, tasks...)
+				continue
+			}
+			if lastOwner(project, userID) {
+				if _, err := userUsc.projectRepo.SetMember(ctx, project.ID.Hex(), domain.ProjectMember{UserID: reassignTo, Role: domain.ProjectRoleOwner}); err != nil {
+					return err
+				}
+				report.ProjectsReassigned++
+			}
+			if _, err := userUsc.projectRepo.RemoveMember(ctx, project.ID.Hex(), userID); err != nil {
+				return err
+			}
+		}
+		if err := userUsc.userRepo.DeleteUser(ctx, user.ID); err != nil {
+			return err
+		}
+		return writeAuditLog(ctx, userUsc.auditLogRepo, domain.AuditLogEntry{
+			Action:      domain.AuditActionDelete,
+			EntityType:  domain.AuditEntityUser,
+			EntityID:    userID,
+			Before:      userSnapshot(domain.User{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role}),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserDeleted,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  userID,
+		Details:   map[string]string{"projects_deleted": strconv.Itoa(report.ProjectsDeleted), "projects_reassigned": strconv.Itoa(report.ProjectsReassigned), "tasks_deleted": strconv.Itoa(report.TasksDeleted)},
+	})
+	publishEvent(ctx, userUsc.events, domain.EventUserDeleted, domain.AuditEntityUser, userID, nil)
+	for i := range removed {
+		event := domain.TaskEvent{Type: domain.TaskEventDeleted, TaskID: removed[i].ID.Hex(), Before: &removed[i], Details: map[string]string{"trashed": "true"}}
+		for _, handler := range userUsc.handlers {
+			handler.HandleTaskEvent(ctx, event)
+		}
+	}
+
+	return report, nil
+}
+
+// delete a project and move its tasks to the trash, returns the tasks deleted
+func (userUsc *userUseCase) deleteProjectWithTasks(ctx context.Context, project *domain.Project) ([]domain.Task, error) {
+
+	tasks := []domain.Task{}
+	taskIDs := []primitive.ObjectID{}
+	err := userUsc.taskRepo.StreamTasks(ctx, domain.TaskQuery{ProjectID: &project.ID}, func(task *domain.Task) error {
+		tasks = append(tasks, *task)
+		taskIDs = append(taskIDs, task.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(taskIDs) > 0 {
+		if err := trashTasks(ctx, userUsc.trashRepo, tasks); err != nil {
+			return nil, err
+		}
+		if err := userUsc.taskRepo.DeleteTasks(ctx, taskIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, userUsc.projectRepo.DeleteProject(ctx, project.ID.Hex())
+}
+
+// get a user an admin may demote, deactivate or delete (not their own account)
+func (userUsc *userUseCase) managedUser(ctx context.Context, userID string) (*domain.User, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok && actor.ID == userID {
+		return nil, domain.ErrManageSelf
+	}
+
+	return userUsc.userRepo.GetUserById(ctx, objID)
+}
+
+// keep at least one active admin
+func (userUsc *userUseCase) checkNotLastAdmin(ctx context.Context, user *domain.User) error {
+
+	if user.Role != domain.RoleAdmin || user.IsDeactivated() {
+		return nil
+	}
+	_, admins, err := userUsc.userRepo.ListUsers(ctx, domain.UserQuery{Role: domain.RoleAdmin, Status: domain.UserStatusActive, Page: 1, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+// audit log entry, audit event and domain event of an account change
+func (userUsc *userUseCase) recordAccountChange(ctx context.Context, action string, auditType string, eventType string, before domain.User, after domain.User) {
+
+	userID := after.ID.Hex()
+	recordAuditLog(ctx, userUsc.auditLogRepo, userUsc.logger, domain.AuditLogEntry{
+		Action:      action,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    userID,
+		Before:      userSnapshot(before),
+		After:       userSnapshot(after),
+	})
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      auditType,
+		Outcome:   domain.AuditOutcomeSuccess,
+		TargetID:  userID,
+	})
+	publishEvent(ctx, userUsc.events, eventType, domain.AuditEntityUser, userID, userSnapshot(after))
+}
+
+// user as shown to its owner (without credentials and lockout counters)
+func profileOf(user *domain.User) *domain.User {
+	return &domain.User{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		EmailVerificationPending: user.EmailVerificationPending,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		DisplayName: user.DisplayName,
+		Role:        user.Role,
+		Identities:  user.Identities,
+		TwoFactorEnabled: user.TwoFactor.IsEnabled(),
+		Notifications: user.Notifications,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+// start counting failed logins again after a successful one
+func (userUsc *userUseCase) clearFailedLogins(ctx context.Context, user *domain.User) error {
+	if user.FailedLogins == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	return userUsc.userRepo.ResetFailedLogins(ctx, user.ID)
+}
+
+// count a wrong password or two-factor code and lock the account once the limit is reached
+func (userUsc *userUseCase) recordFailedLogin(ctx context.Context, user *domain.User) error {
+	
+	if userUsc.maxFailedLogins <= 0 {
+		return domain.ErrInvalidCredentials
+	}
+
+	failures, err := userUsc.userRepo.IncrementFailedLogins(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if failures < userUsc.maxFailedLogins {
+		return domain.ErrInvalidCredentials
+	}
+
+	until := time.Now().Add(userUsc.lockoutDuration).UTC()
+	if err := userUsc.userRepo.LockUser(ctx, user.ID, until); err != nil {
+		return err
+	}
+
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:      domain.AuditUserLocked,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   user.ID.Hex(),
+		Actor:     user.Username,
+		Details:   map[string]string{"failed_logins": strconv.Itoa(failures), "locked_until": until.Format(time.RFC3339)},
+	})
+
+	publishEvent(ctx, userUsc.events, domain.EventUserLocked, domain.AuditEntityUser, user.ID.Hex(), map[string]interface{}{"locked_until": until})
+
+	return domain.ErrAccountLocked
+}
+
+// audit snapshot of a user without credentials
+func userSnapshot(user domain.User) map[string]interface{} {
+	snapshot := auditSnapshot(user)
+	delete(snapshot, "password")
+	return snapshot
+}
+
+// record failed login attempt
+func (userUsc *userUseCase) auditLoginFailure(ctx context.Context, username string, userID string, reason string) {
+	userUsc.auditSink.Emit(ctx, domain.AuditEvent{
+		Type:     domain.AuditLoginFailed,
+		Outcome:  domain.AuditOutcomeFailure,
+		ActorID:  userID,
+		Actor:    username,
+		Details:  map[string]string{"reason": reason},
+	})
 }
\ No newline at end of file
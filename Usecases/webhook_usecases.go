@@ -0,0 +1,400 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"encoding/json";
+	"net/url";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+)
+
+// wait before the second attempt of a delivery, doubled for each further one
+const webhookRetryDelay = 2 * time.Second
+
+// webhook usecase (webhooks receiving domain events of the workspace or one organization, with a delivery log)
+type WebhookUseCase interface {
+	CreateWebhook(ctx context.Context, input *domain.WebhookInput) (*domain.Webhook, error)                              // add webhook, the response carries its signing secret
+	GetWebhooks(ctx context.Context) ([]domain.Webhook, error)                                                          // all webhooks without secrets
+	GetWebhook(ctx context.Context, webhookID string) (*domain.Webhook, error)                                          // webhook without secret
+	UpdateWebhook(ctx context.Context, webhookID string, input *domain.WebhookInput) (*domain.Webhook, error)           // change url, events or enabled state
+	DeleteWebhook(ctx context.Context, webhookID string) error                                                          // delete webhook and its delivery log
+	GetDeliveries(ctx context.Context, webhookID string, before time.Time, limit int64) ([]domain.WebhookDelivery, error)       // delivery log newest first
+	SendTestEvent(ctx context.Context, webhookID string) (*domain.WebhookDelivery, error)                              // deliver a webhook.test event right away
+	DisableFailingWebhooks(ctx context.Context) error                                                                   // disable webhooks failing for too many days (scheduled)
+	HandleDomainEvent(ctx context.Context, event domain.DomainEvent)                                                    // deliver event to subscribed webhooks in the background
+}
+
+type webhookUseCase struct {
+	webhookRepo       domain.WebhookRepository
+	deliveryRepo      domain.WebhookDeliveryRepository
+	orgRepo           domain.OrganizationRepository         // organizations webhooks are limited to
+	sender            domain.WebhookSender
+	auditLogRepo      domain.AuditLogRepository
+	logger            domain.Logger
+	maxAttempts       int
+	disableAfterDays  int
+	retention         time.Duration
+}
+
+// creates new WebhookUseCase instance
+func NewWebhookUseCase(webhookRepo domain.WebhookRepository, deliveryRepo domain.WebhookDeliveryRepository, orgRepo domain.OrganizationRepository, sender domain.WebhookSender, auditLogRepo domain.AuditLogRepository,
+	logger domain.Logger, maxAttempts int, disableAfterDays int, retention time.Duration) WebhookUseCase {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &webhookUseCase{webhookRepo: webhookRepo, deliveryRepo: deliveryRepo, orgRepo: orgRepo, sender: sender, auditLogRepo: auditLogRepo, logger: logger,
+		maxAttempts: maxAttempts, disableAfterDays: disableAfterDays, retention: retention}
+}
+
+// add an enabled webhook with a fresh signing secret
+func (webhookUsc *webhookUseCase) CreateWebhook(ctx context.Context, input *domain.WebhookInput) (*domain.Webhook, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if !validWebhookURL(input.URL) {
+		return nil, domain.ErrInvalidWebhook
+	}
+	tenantID := ""
+	if input.TenantID != nil {
+		if err := webhookUsc.checkTenant(ctx, *input.TenantID); err != nil {
+			return nil, err
+		}
+		tenantID = *input.TenantID
+	}
+	secret, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &domain.Webhook{
+		URL:        input.URL,
+		Events:     webhookEvents(input.Events),
+		TenantID:   tenantID,
+		Secret:     secret,
+		Enabled:    input.Enabled == nil || *input.Enabled,
+		CreatedBy:  actor.ID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if !webhook.Enabled {
+		webhook.DisabledAt = &webhook.CreatedAt
+		webhook.DisabledReason = "disabled by " + actor.Username
+	}
+	if err := webhookUsc.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	recordAuditLog(ctx, webhookUsc.auditLogRepo, webhookUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionCreate,
+		EntityType:  domain.AuditEntityWebhook,
+		EntityID:    webhook.ID.Hex(),
+		After:       auditSnapshot(withoutSecret(*webhook)),
+	})
+
+	return webhook, nil
+}
+
+// all webhooks, secrets are only shown on creation
+func (webhookUsc *webhookUseCase) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+
+	webhooks, err := webhookUsc.webhookRepo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// webhook by id without its secret
+func (webhookUsc *webhookUseCase) GetWebhook(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+
+	webhook, err := webhookUsc.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// change url, events or enabled state, enabling starts over without a failing streak
+func (webhookUsc *webhookUseCase) UpdateWebhook(ctx context.Context, webhookID string, input *domain.WebhookInput) (*domain.Webhook, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	existing, err := webhookUsc.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := *existing
+	if input.URL != "" {
+		if !validWebhookURL(input.URL) {
+			return nil, domain.ErrInvalidWebhook
+		}
+		changed.URL = input.URL
+	}
+	if input.Events != nil {
+		changed.Events = webhookEvents(input.Events)
+	}
+	if input.TenantID != nil {
+		if err := webhookUsc.checkTenant(ctx, *input.TenantID); err != nil {
+			return nil, err
+		}
+		changed.TenantID = *input.TenantID
+	}
+	if input.Enabled != nil && *input.Enabled != existing.Enabled {
+		changed.Enabled = *input.Enabled
+		if changed.Enabled {
+			changed.DisabledAt, changed.DisabledReason, changed.FailingSince = nil, "", nil
+		} else {
+			now := time.Now().UTC()
+			changed.DisabledAt, changed.DisabledReason = &now, "disabled by "+actor.Username
+		}
+	}
+
+	updated, err := webhookUsc.webhookRepo.UpdateWebhook(ctx, webhookID, &changed)
+	if err != nil {
+		return nil, err
+	}
+	updated.Secret = ""
+
+	recordAuditLog(ctx, webhookUsc.auditLogRepo, webhookUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionUpdate,
+		EntityType:  domain.AuditEntityWebhook,
+		EntityID:    webhookID,
+		Before:      auditSnapshot(withoutSecret(*existing)),
+		After:       auditSnapshot(updated),
+	})
+
+	return updated, nil
+}
+
+// delete webhook, its delivery log goes with it
+func (webhookUsc *webhookUseCase) DeleteWebhook(ctx context.Context, webhookID string) error {
+
+	existing, err := webhookUsc.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+	if err := webhookUsc.webhookRepo.DeleteWebhook(ctx, webhookID); err != nil {
+		return err
+	}
+	if err := webhookUsc.deliveryRepo.DeleteDeliveries(ctx, webhookID); err != nil {
+		webhookUsc.logger.Warn(ctx, "webhook deliveries not deleted, they expire on their own", "webhook_id", webhookID, "error", err)
+	}
+
+	recordAuditLog(ctx, webhookUsc.auditLogRepo, webhookUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionDelete,
+		EntityType:  domain.AuditEntityWebhook,
+		EntityID:    webhookID,
+		Before:      auditSnapshot(withoutSecret(*existing)),
+	})
+
+	return nil
+}
+
+// delivery log of a webhook newest first
+func (webhookUsc *webhookUseCase) GetDeliveries(ctx context.Context, webhookID string, before time.Time, limit int64) ([]domain.WebhookDelivery, error) {
+
+	if _, err := webhookUsc.webhookRepo.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = domain.DefaultWebhookDeliveryLimit
+	}
+	if limit > domain.MaxWebhookDeliveryLimit {
+		limit = domain.MaxWebhookDeliveryLimit
+	}
+
+	return webhookUsc.deliveryRepo.GetDeliveries(ctx, webhookID, before, limit)
+}
+
+// deliver a test event once and return the logged delivery (disabled webhooks too, so fixes can be checked before enabling)
+func (webhookUsc *webhookUseCase) SendTestEvent(ctx context.Context, webhookID string) (*domain.WebhookDelivery, error) {
+
+	webhook, err := webhookUsc.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := domain.DomainEvent{
+		ID:          primitive.NewObjectID().Hex(),
+		Type:        domain.EventWebhookTest,
+		EntityType:  domain.AuditEntityWebhook,
+		EntityID:    webhookID,
+		Data:        map[string]string{"message": "test event sent from the task manager"},
+		OccurredAt:  time.Now().UTC(),
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID = actor.ID
+	}
+
+	return webhookUsc.deliver(ctx, webhook, event, true, 1)
+}
+
+// disable enabled webhooks without a successful delivery for the configured number of days since their first failure
+func (webhookUsc *webhookUseCase) DisableFailingWebhooks(ctx context.Context) error {
+
+	if webhookUsc.disableAfterDays <= 0 {
+		return nil
+	}
+	webhooks, err := webhookUsc.webhookRepo.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, -webhookUsc.disableAfterDays)
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || webhook.FailingSince == nil || webhook.FailingSince.After(cutoff) {
+			continue
+		}
+
+		changed := webhook
+		changed.Enabled = false
+		changed.DisabledAt = &now
+		changed.DisabledReason = "deliveries failing since " + webhook.FailingSince.UTC().Format("2006-01-02")
+		updated, err := webhookUsc.webhookRepo.UpdateWebhook(ctx, webhook.ID.Hex(), &changed)
+		if err == domain.ErrWebhookNotFound {
+			continue        // deleted meanwhile
+		}
+		if err != nil {
+			return err
+		}
+
+		webhookUsc.logger.Warn(ctx, "webhook disabled after failing deliveries", "webhook_id", webhook.ID.Hex(), "url", webhook.URL, "failing_since", webhook.FailingSince)
+		recordAuditLog(ctx, webhookUsc.auditLogRepo, webhookUsc.logger, domain.AuditLogEntry{
+			Action:      domain.AuditActionUpdate,
+			EntityType:  domain.AuditEntityWebhook,
+			EntityID:    webhook.ID.Hex(),
+			Before:      auditSnapshot(withoutSecret(webhook)),
+			After:       auditSnapshot(withoutSecret(*updated)),
+			Details:     map[string]string{"method": "auto_disable"},
+		})
+	}
+
+	return nil
+}
+
+// deliver an event to every enabled webhook subscribed to it, without holding up the publisher
+func (webhookUsc *webhookUseCase) HandleDomainEvent(ctx context.Context, event domain.DomainEvent) {
+
+	ctx = context.WithoutCancel(ctx)        // deliveries outlive the request that caused the event
+	go func() {
+		webhooks, err := webhookUsc.webhookRepo.GetWebhooks(ctx)
+		if err != nil {
+			webhookUsc.logger.Error(ctx, "webhooks not loaded, event not delivered", "event", event.Type, "event_id", event.ID, "error", err)
+			return
+		}
+		for i := range webhooks {
+			if !webhooks[i].Enabled || !webhooks[i].Accepts(event) {
+				continue
+			}
+			delivery, err := webhookUsc.deliver(ctx, &webhooks[i], event, false, webhookUsc.maxAttempts)
+			if err != nil {
+				webhookUsc.logger.Error(ctx, "webhook delivery not logged", "webhook_id", webhooks[i].ID.Hex(), "event_id", event.ID, "error", err)
+			}
+			if delivery == nil {
+				continue
+			}
+			if err := webhookUsc.webhookRepo.RecordOutcome(ctx, delivery.WebhookID, delivery.Succeeded, delivery.CreatedAt); err != nil {
+				webhookUsc.logger.Error(ctx, "webhook failing state not updated", "webhook_id", delivery.WebhookID, "error", err)
+			}
+			if !delivery.Succeeded {
+				webhookUsc.logger.Warn(ctx, "webhook delivery failed", "webhook_id", delivery.WebhookID, "event", event.Type, "event_id", event.ID, "attempts", len(delivery.Attempts))
+			}
+		}
+	}()
+}
+
+// post the event until an attempt succeeds or the attempts run out, then log the delivery
+func (webhookUsc *webhookUseCase) deliver(ctx context.Context, webhook *domain.Webhook, event domain.DomainEvent, test bool, attempts int) (*domain.WebhookDelivery, error) {
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	delivery := &domain.WebhookDelivery{
+		WebhookID:  webhook.ID.Hex(),
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Test:       test,
+		Payload:    string(payload),
+		Attempts:   []domain.WebhookAttempt{},
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(webhookUsc.retention),
+	}
+
+	delay := webhookRetryDelay
+	for attempt := 1; attempt <= attempts; attempt++ {
+		start := time.Now()
+		status, err := webhookUsc.sender.Send(ctx, webhook.URL, webhook.Secret, payload)
+		result := domain.WebhookAttempt{At: start.UTC(), StatusCode: status, LatencyMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		delivery.Attempts = append(delivery.Attempts, result)
+		if err == nil {
+			delivery.Succeeded = true
+			break
+		}
+		if attempt < attempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	if err := webhookUsc.deliveryRepo.RecordDelivery(ctx, delivery); err != nil {
+		return delivery, err
+	}
+
+	return delivery, nil
+}
+
+// check that a webhook is limited to an existing organization ("" for every tenant)
+func (webhookUsc *webhookUseCase) checkTenant(ctx context.Context, tenantID string) error {
+	if tenantID == "" {
+		return nil
+	}
+	if _, err := webhookUsc.orgRepo.GetOrganizationByID(ctx, tenantID); err != nil {
+		if err == domain.ErrOrganizationNotFound || err == domain.ErrInvalidOrganizationID {
+			return domain.NewValidationError("tenant_id", "must be the id of an organization")
+		}
+		return err
+	}
+	return nil
+}
+
+// check that a webhook url is absolute http or https
+func validWebhookURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// event types without duplicates (nil becomes empty, which means all events)
+func webhookEvents(events []string) []string {
+	unique := []string{}
+	for _, event := range events {
+		if event != "" && !containsString(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// webhook as shown in the audit log
+func withoutSecret(webhook domain.Webhook) domain.Webhook {
+	webhook.Secret = ""
+	return webhook
+}
@@ -0,0 +1,171 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"sort";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// worklog usecase (time tracking with timers and manual entries, one running timer per user)
+type WorklogUseCase interface {
+	StartTimer(ctx context.Context, taskID string) (*domain.Worklog, error)                                 // start the caller's timer, returns the running timer with ErrTimerRunning when one runs already
+	StopTimer(ctx context.Context, taskID string) (*domain.Worklog, error)                                  // stop the caller's timer on a task
+	AddWorklog(ctx context.Context, taskID string, input *domain.WorklogInput) (*domain.Worklog, error)     // record time spent afterwards
+	GetWorklogs(ctx context.Context, taskID string) (*domain.TaskWorklogs, error)                           // worklogs of a task with totals
+	DeleteWorklog(ctx context.Context, taskID string, worklogID string) error                               // delete own entry (admins may delete any)
+}
+
+type worklogUseCase struct {
+	worklogRepo  domain.WorklogRepository
+	taskQuery    TaskQueryUseCase        // time is only tracked on tasks the caller can see
+}
+
+// creates new WorklogUseCase instance
+func NewWorklogUseCase(worklogRepo domain.WorklogRepository, taskQuery TaskQueryUseCase) WorklogUseCase {
+	return &worklogUseCase{worklogRepo: worklogRepo, taskQuery: taskQuery}
+}
+
+// start a timer on a task for the caller
+func (worklogUsc *worklogUseCase) StartTimer(ctx context.Context, taskID string) (*domain.Worklog, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	task, err := worklogUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	worklog := &domain.Worklog{TaskID: taskID, UserID: actor.ID, Username: actor.Username, TenantID: task.TenantID, Source: domain.WorklogSourceTimer, StartedAt: now, CreatedAt: now}
+	err = worklogUsc.worklogRepo.StartTimer(ctx, worklog)
+	if err == domain.ErrTimerRunning {
+		running, getErr := worklogUsc.worklogRepo.GetRunningTimer(ctx, actor.ID)
+		if getErr != nil {
+			return nil, err        // stopped in between, the client may retry
+		}
+		return running, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+// stop the caller's timer on a task
+func (worklogUsc *worklogUseCase) StopTimer(ctx context.Context, taskID string) (*domain.Worklog, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return worklogUsc.worklogRepo.StopTimer(ctx, actor.ID, taskID, time.Now().UTC())
+}
+
+// record time the caller spent on a task
+func (worklogUsc *worklogUseCase) AddWorklog(ctx context.Context, taskID string, input *domain.WorklogInput) (*domain.Worklog, error) {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	task, err := worklogUsc.taskQuery.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	spent := time.Duration(input.Minutes) * time.Minute
+	startedAt := now.Add(-spent)
+	if input.StartedAt != nil {
+		startedAt = input.StartedAt.UTC()
+	}
+	endedAt := startedAt.Add(spent)
+	if endedAt.After(now) {
+		return nil, domain.NewValidationError("started_at", "work can't end in the future")
+	}
+
+	worklog := &domain.Worklog{TaskID: taskID, UserID: actor.ID, Username: actor.Username, TenantID: task.TenantID, Source: domain.WorklogSourceManual,
+		StartedAt: startedAt, EndedAt: &endedAt, Seconds: int64(spent / time.Second), Note: input.Note, CreatedAt: now}
+	if err := worklogUsc.worklogRepo.AddWorklog(ctx, worklog); err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+// worklogs of a task with the time spent in total and per user
+func (worklogUsc *worklogUseCase) GetWorklogs(ctx context.Context, taskID string) (*domain.TaskWorklogs, error) {
+
+	if _, err := worklogUsc.taskQuery.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	worklogs, err := worklogUsc.worklogRepo.GetWorklogs(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.TaskWorklogs{TaskID: taskID, Users: []domain.WorklogUserTotal{}, Worklogs: worklogs}
+	perUser := map[string]int{}        // index in result.Users
+	for _, worklog := range worklogs {
+		if worklog.Running {
+			continue        // counted once stopped
+		}
+		result.TotalSeconds += worklog.Seconds
+		index, seen := perUser[worklog.UserID]
+		if !seen {
+			index = len(result.Users)
+			perUser[worklog.UserID] = index
+			result.Users = append(result.Users, domain.WorklogUserTotal{UserID: worklog.UserID, Username: worklog.Username})
+		}
+		result.Users[index].Seconds += worklog.Seconds
+	}
+	sort.SliceStable(result.Users, func(i, j int) bool { return result.Users[i].Seconds > result.Users[j].Seconds })
+
+	return result, nil
+}
+
+// delete the caller's entry, admins can correct anyone's
+func (worklogUsc *worklogUseCase) DeleteWorklog(ctx context.Context, taskID string, worklogID string) error {
+
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	if _, err := worklogUsc.taskQuery.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+	owner := actor.ID
+	if domain.HasPermission(domain.PermissionsForRole(actor.Role), domain.PermissionUserManage) {
+		owner = ""
+	}
+
+	return worklogUsc.worklogRepo.DeleteWorklog(ctx, taskID, worklogID, owner)
+}
+
+// drops the worklogs of deleted tasks (tasks in the trash keep theirs until purged)
+type worklogTaskEventHandler struct {
+	worklogRepo  domain.WorklogRepository
+	logger       domain.Logger
+}
+
+// creates task event handler removing worklogs of deleted tasks
+func NewWorklogTaskEventHandler(worklogRepo domain.WorklogRepository, logger domain.Logger) domain.TaskEventHandler {
+	return &worklogTaskEventHandler{worklogRepo: worklogRepo, logger: logger}
+}
+
+func (handler *worklogTaskEventHandler) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+
+	trashed := event.Type == domain.TaskEventDeleted && event.Details["trashed"] == "true"
+	if event.Type != domain.TaskEventPurged && (event.Type != domain.TaskEventDeleted || trashed) {
+		return
+	}
+	if err := handler.worklogRepo.DeleteTaskWorklogs(ctx, event.TaskID); err != nil {
+		handler.logger.Error(ctx, "failed to delete worklogs of task", "task_id", event.TaskID, "error", err)
+	}
+}
@@ -0,0 +1,382 @@
+package usecases
+
+// imports
+import (
+	"context";
+	"strconv";
+	"time";
+	"go.mongodb.org/mongo-driver/bson/primitive";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+)
+
+// tasks inserted per batch on import
+const workspaceImportBatch = 1000
+
+// workspace migration usecase (move a whole workspace between instances or organizations)
+type WorkspaceUseCase interface {
+	ExportWorkspace(ctx context.Context, opts domain.WorkspaceExportOptions) (*domain.WorkspaceBundle, error)                                     // users (optional), labels, projects and tasks of a workspace
+	ImportWorkspace(ctx context.Context, bundle *domain.WorkspaceBundle, opts domain.WorkspaceImportOptions) (*domain.WorkspaceImportReport, error)  // recreate a bundle under new ids, references follow
+}
+
+type workspaceUseCase struct {
+	taskRepo      domain.TaskRepository
+	userRepo      domain.UserRepository
+	labelRepo     domain.LabelRepository
+	projectRepo   domain.ProjectRepository
+	auditLogRepo  domain.AuditLogRepository
+	unitOfWork    domain.UnitOfWork
+	logger        domain.Logger
+	handlers      []domain.TaskEventHandler       // imported tasks are published like created tasks
+}
+
+// creates new WorkspaceUseCase instance
+func NewWorkspaceUseCase(taskRepo domain.TaskRepository, userRepo domain.UserRepository, labelRepo domain.LabelRepository, projectRepo domain.ProjectRepository, auditLogRepo domain.AuditLogRepository, unitOfWork domain.UnitOfWork, logger domain.Logger, handlers ...domain.TaskEventHandler) WorkspaceUseCase {
+	return &workspaceUseCase{taskRepo: taskRepo, userRepo: userRepo, labelRepo: labelRepo, projectRepo: projectRepo, auditLogRepo: auditLogRepo, unitOfWork: unitOfWork, logger: logger, handlers: handlers}
+}
+
+// users (optional), labels, projects and tasks of a workspace
+func (workspaceUsc *workspaceUseCase) ExportWorkspace(ctx context.Context, opts domain.WorkspaceExportOptions) (*domain.WorkspaceBundle, error) {
+
+	tenant := workspaceTenant(ctx, opts.TenantID)
+	bundle := &domain.WorkspaceBundle{Version: domain.WorkspaceBundleVersion, ExportedAt: time.Now().UTC(), Labels: []domain.Label{}, Projects: []domain.Project{}, Tasks: []domain.Task{}}
+
+	if opts.IncludeUsers {
+		for page := 1; ; page++ {
+			users, _, err := workspaceUsc.userRepo.ListUsers(ctx, domain.UserQuery{Page: page, Limit: domain.MaxUserPageSize, TenantID: &tenant})
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				bundle.Users = append(bundle.Users, domain.WorkspaceUser{
+					ID:            user.ID,
+					Username:      user.Username,
+					Email:         user.Email,
+					DisplayName:   user.DisplayName,
+					PasswordHash:  user.Password,
+					Role:          user.Role,
+					DeactivatedAt: user.DeactivatedAt,
+					Identities:    user.Identities,
+				})
+			}
+			if len(users) < domain.MaxUserPageSize {
+				break
+			}
+		}
+	}
+
+	// labels are instance-wide, all of them go along
+	labels, err := workspaceUsc.labelRepo.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Labels = append(bundle.Labels, labels...)
+
+	projects, err := workspaceUsc.projectRepo.GetProjects(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if project.TenantID == tenant {
+			bundle.Projects = append(bundle.Projects, project)
+		}
+	}
+
+	err = workspaceUsc.taskRepo.StreamTasks(ctx, domain.TaskQuery{TenantID: &tenant}, func(task *domain.Task) error {
+		bundle.Tasks = append(bundle.Tasks, *task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// recreate a bundle under new ids (nothing is written when a reference can't be resolved)
+func (workspaceUsc *workspaceUseCase) ImportWorkspace(ctx context.Context, bundle *domain.WorkspaceBundle, opts domain.WorkspaceImportOptions) (*domain.WorkspaceImportReport, error) {
+
+	if bundle.Version < 1 || bundle.Version > domain.WorkspaceBundleVersion {
+		return nil, domain.ErrUnsupportedBundle
+	}
+	tenant := workspaceTenant(ctx, opts.TenantID)
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		opts.OwnerID = actor.ID
+	}
+	report := &domain.WorkspaceImportReport{}
+
+	// accounts first, members point at them
+	userIDs := map[string]string{}
+	newUsers := []*domain.User{}
+	if opts.IncludeUsers {
+		for _, bundled := range bundle.Users {
+			existing, err := workspaceUsc.existingAccount(ctx, bundled, tenant)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				userIDs[bundled.ID.Hex()] = existing.ID.Hex()
+				report.UsersMatched++
+				continue
+			}
+			user, err := workspaceUsc.importedUser(ctx, bundled, tenant)
+			if err != nil {
+				return nil, err
+			}
+			userIDs[bundled.ID.Hex()] = user.ID.Hex()
+			newUsers = append(newUsers, user)
+		}
+	}
+
+	// labels with a name not taken yet
+	names := make([]string, 0, len(bundle.Labels))
+	for _, label := range bundle.Labels {
+		names = append(names, label.Name)
+	}
+	existingLabels, err := workspaceUsc.labelRepo.GetLabelsByName(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	taken := map[string]bool{}
+	for _, label := range existingLabels {
+		taken[label.Name] = true
+	}
+	newLabels := []*domain.Label{}
+	for _, label := range bundle.Labels {
+		if taken[label.Name] {
+			report.LabelsMatched++
+			continue
+		}
+		taken[label.Name] = true
+		label.ID = primitive.NewObjectID()
+		newLabels = append(newLabels, &label)
+	}
+
+	// projects under new ids, members remapped
+	projectIDs := map[primitive.ObjectID]primitive.ObjectID{}
+	newProjects := []*domain.Project{}
+	for _, bundled := range bundle.Projects {
+		project := &domain.Project{ID: primitive.NewObjectID(), Name: bundled.Name, Description: bundled.Description, Members: []domain.ProjectMember{}, CreatedAt: bundled.CreatedAt, TenantID: tenant}
+		for _, member := range bundled.Members {
+			userID, err := workspaceUsc.resolveUser(ctx, userIDs, member.UserID, tenant)
+			if err != nil {
+				return nil, err
+			}
+			if userID == "" || project.RoleOf(userID) != "" {
+				report.MembersDropped++
+				continue
+			}
+			project.Members = append(project.Members, domain.ProjectMember{UserID: userID, Role: member.Role})
+		}
+		if !hasOwner(project) {
+			if opts.OwnerID == "" {
+				return nil, domain.ErrWorkspaceOwnerNeeded
+			}
+			project.Members = append(withoutMember(project.Members, opts.OwnerID), domain.ProjectMember{UserID: opts.OwnerID, Role: domain.ProjectRoleOwner})
+		}
+		projectIDs[bundled.ID] = project.ID
+		newProjects = append(newProjects, project)
+	}
+
+	tasks, err := importedTasks(bundle.Tasks, projectIDs, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	// the workspace appears as a whole or not at all (where the server supports transactions)
+	err = workspaceUsc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		for _, user := range newUsers {
+			if err := workspaceUsc.userRepo.CreateUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		for _, label := range newLabels {
+			if err := workspaceUsc.labelRepo.CreateLabel(ctx, label); err != nil {
+				return err
+			}
+		}
+		for _, project := range newProjects {
+			if err := workspaceUsc.projectRepo.CreateProject(ctx, project); err != nil {
+				return err
+			}
+		}
+		for start := 0; start < len(tasks); start += workspaceImportBatch {
+			end := min(start+workspaceImportBatch, len(tasks))
+			if err := workspaceUsc.taskRepo.CreateTasks(ctx, tasks[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.UsersCreated, report.LabelsCreated, report.ProjectsCreated, report.TasksCreated = len(newUsers), len(newLabels), len(newProjects), len(tasks)
+
+	recordAuditLog(ctx, workspaceUsc.auditLogRepo, workspaceUsc.logger, domain.AuditLogEntry{
+		Action:      domain.AuditActionImport,
+		EntityType:  domain.AuditEntityWorkspace,
+		EntityID:    tenant,
+		After:       auditSnapshot(report),
+		Details:     map[string]string{"exported_at": bundle.ExportedAt.Format(time.RFC3339), "include_users": strconv.FormatBool(opts.IncludeUsers)},
+	})
+	for _, task := range tasks {
+		for _, handler := range workspaceUsc.handlers {
+			handler.HandleTaskEvent(ctx, domain.TaskEvent{
+				Type:     domain.TaskEventCreated,
+				TaskID:   task.ID.Hex(),
+				After:    task,
+				Details:  map[string]string{"imported": "workspace"},
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// account of the target workspace with the bundled username or email (nil when neither is taken)
+func (workspaceUsc *workspaceUseCase) existingAccount(ctx context.Context, bundled domain.WorkspaceUser, tenant string) (*domain.User, error) {
+
+	existing, err := workspaceUsc.userRepo.GetByUsername(ctx, bundled.Username)
+	if err == domain.ErrUserNotFound && bundled.Email != "" {
+		existing, err = workspaceUsc.userRepo.GetByEmail(ctx, bundled.Email)
+	}
+	if err == domain.ErrUserNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existing.TenantID != tenant {
+		return nil, domain.ErrWorkspaceUserConflict        // names are unique across organizations
+	}
+
+	return existing, nil
+}
+
+// new account for a bundled user (keeps the password hash, drops sign-ins linked elsewhere)
+func (workspaceUsc *workspaceUseCase) importedUser(ctx context.Context, bundled domain.WorkspaceUser, tenant string) (*domain.User, error) {
+
+	user := &domain.User{
+		ID:            primitive.NewObjectID(),
+		Username:      bundled.Username,
+		Email:         bundled.Email,
+		DisplayName:   bundled.DisplayName,
+		Password:      bundled.PasswordHash,
+		Role:          bundled.Role,
+		DeactivatedAt: bundled.DeactivatedAt,
+		TenantID:      tenant,
+	}
+	if user.Role != domain.RoleAdmin || tenant != "" {
+		user.Role = domain.RoleUser        // admins stay in the default workspace
+	}
+	for _, identity := range bundled.Identities {
+		if _, err := workspaceUsc.userRepo.GetByIdentity(ctx, identity.Provider, identity.Subject); err == domain.ErrUserNotFound {
+			user.Identities = append(user.Identities, identity)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// id of a referenced user on this instance (imported accounts first, then existing ones, empty when unknown)
+func (workspaceUsc *workspaceUseCase) resolveUser(ctx context.Context, userIDs map[string]string, sourceID string, tenant string) (string, error) {
+
+	if userID, ok := userIDs[sourceID]; ok {
+		return userID, nil
+	}
+	objID, err := primitive.ObjectIDFromHex(sourceID)
+	if err != nil {
+		return "", nil
+	}
+	user, err := workspaceUsc.userRepo.GetUserById(ctx, objID)
+	if err == domain.ErrUserNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if user.TenantID != tenant {
+		return "", nil
+	}
+
+	return sourceID, nil
+}
+
+// copies of bundled tasks under new ids, parents, projects and recurrence series point at the copies
+func importedTasks(bundled []domain.Task, projectIDs map[primitive.ObjectID]primitive.ObjectID, tenant string) ([]*domain.Task, error) {
+
+	// new ids first, so references can be pointed at their copies
+	taskIDs := map[primitive.ObjectID]primitive.ObjectID{}
+	for _, task := range bundled {
+		taskIDs[task.ID] = primitive.NewObjectID()
+	}
+
+	tasks := make([]*domain.Task, 0, len(bundled))
+	for _, source := range bundled {
+		task := source
+		task.ID = taskIDs[source.ID]
+		task.ClientID = ""        // offline client ids are unique per instance
+		task.TenantID = tenant
+		task.ParentID, task.ProjectID = nil, nil
+		if source.ParentID != nil {
+			if parentID, ok := taskIDs[*source.ParentID]; ok {
+				task.ParentID = &parentID
+			}
+		}
+		if source.ProjectID != nil {
+			if projectID, ok := projectIDs[*source.ProjectID]; ok {
+				task.ProjectID = &projectID
+			}
+		}
+		if source.Recurrence != nil {
+			rule := *source.Recurrence
+			if seriesID, ok := taskIDs[rule.SeriesID]; ok {
+				rule.SeriesID = seriesID
+			} else if !rule.SeriesID.IsZero() {
+				rule.SeriesID = task.ID        // first task of the series wasn't exported, this one starts it
+			}
+			task.Recurrence = &rule
+		}
+		if source.Reminder != nil {
+			reminder := *source.Reminder
+			task.Reminder = &reminder
+		}
+		if err := task.ApplyPriority(); err != nil {        // rank isn't part of the json bundle
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, nil
+}
+
+// tenant a migration works on (requests always work on their own)
+func workspaceTenant(ctx context.Context, tenantID string) string {
+	if tenant, scoped := domain.TenantFromContext(ctx); scoped {
+		return tenant
+	}
+	return tenantID
+}
+
+// check if a project has an owner
+func hasOwner(project *domain.Project) bool {
+	for _, member := range project.Members {
+		if member.Role == domain.ProjectRoleOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// members without a user
+func withoutMember(members []domain.ProjectMember, userID string) []domain.ProjectMember {
+	kept := []domain.ProjectMember{}
+	for _, member := range members {
+		if member.UserID != userID {
+			kept = append(kept, member)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,314 @@
+package main
+
+// imports
+import (
+	"bufio";
+	"context";
+	"encoding/json";
+	"flag";
+	"fmt";
+	"os";
+	"strings";
+	"time";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Domain";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Infrastructure";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Migrations";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Repositories";
+	"github.com/natnael-eyuel-dev/Task-Management-Clean-Architecture/Usecases";
+	"go.mongodb.org/mongo-driver/mongo";
+	"go.mongodb.org/mongo-driver/mongo/options";
+)
+
+const usage = `usage: admin <command> [flags]
+
+commands:
+  create-admin    -username NAME [-email ADDRESS] [-password PASSWORD]   create an admin account
+  reset-password  -username NAME [-password PASSWORD]                    set a password and lift any login lockout
+  revoke-tokens   -username NAME                                         revoke personal access tokens and reset links
+  reindex                                                                create missing database indexes and fill the search index
+  migrate                                                                create missing database indexes and apply pending migrations
+  check-integrity [-repair] [-checks NAME,...]                           report broken references and drifted fields (and repair them)
+  list-tasks      [-status S] [-due-before DATE] [-updated-before DATE] [-tenant ORG_ID]          list matching tasks
+  purge-tasks     [-status S] [-due-before DATE] [-updated-before DATE] [-tenant ORG_ID] [-yes]   delete matching tasks with their subtasks
+  export-workspace -file PATH [-tenant ORG_ID] [-users]                  write labels, projects, tasks (and accounts) to a bundle
+  import-workspace -file PATH [-tenant ORG_ID] [-users] [-owner NAME]    recreate a bundle under new ids
+
+passwords are read from standard input when -password is not given
+dates are YYYY-MM-DD or RFC3339, purge-tasks only counts the tasks until -yes is given
+`
+
+// entry point of the admin command line (same configuration as the server)
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	username := flags.String("username", "", "account username")
+	email := flags.String("email", "", "account email address")
+	password := flags.String("password", "", "account password (read from standard input when empty)")
+	file := flags.String("file", "", "workspace bundle path")
+	tenant := flags.String("tenant", "", "organization id (default workspace when empty)")
+	withUsers := flags.Bool("users", false, "export or import accounts too")
+	owner := flags.String("owner", "", "username owning imported projects left without an owner")
+	repair := flags.Bool("repair", false, "repair integrity problems instead of only reporting them")
+	checks := flags.String("checks", "", "comma separated integrity checks (all when empty)")
+	status := flags.String("status", "", "only tasks in this status")
+	dueBefore := flags.String("due-before", "", "only tasks due before this date")
+	updatedBefore := flags.String("updated-before", "", "only tasks last changed before this date")
+	confirmed := flags.Bool("yes", false, "really delete the tasks purge-tasks found")
+	flags.Parse(args)
+
+	config := infrastructure.LoadConfig()        // load configuration from .env or environment
+	logger := infrastructure.NewLogger(config)   // audit events go to the same sinks as the server
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)       // long enough for migrations and workspace moves
+	defer cancel()
+
+	// connect
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoURI))
+	if err != nil {
+		fail(err)
+	}
+	defer client.Disconnect(ctx)       // disconnect
+
+	db := client.Database("taskmanager")
+	taskRepo := repositories.NewTaskRepository(db.Collection("tasks"))
+	taskHistoryRepo := repositories.NewTaskHistoryRepository(db.Collection("task_events"))
+	taskChangeRepo := repositories.NewTaskChangeRepository(db.Collection("task_history"))
+	userRepo := repositories.NewUserRepository(db.Collection("users"))
+	tokenRepo := repositories.NewPersonalAccessTokenRepository(db.Collection("personal_access_tokens"))
+	labelRepo := repositories.NewLabelRepository(db.Collection("labels"))
+	resetRepo := repositories.NewPasswordResetRepository(db.Collection("password_reset_tokens"))
+	adminInviteRepo := repositories.NewAdminInviteRepository(db.Collection("admin_invites"))
+	projectRepo := repositories.NewProjectRepository(db.Collection("projects"))
+	auditLogRepo := repositories.NewAuditLogRepository(db.Collection("audit_log"))
+	trashRepo := repositories.NewTaskTrashRepository(db.Collection("deleted_tasks"))
+	indexes := []migrations.IndexEnsurer{taskRepo.EnsureIndexes, taskHistoryRepo.EnsureIndexes, taskChangeRepo.EnsureIndexes, userRepo.EnsureIndexes, labelRepo.EnsureIndexes, resetRepo.EnsureIndexes, adminInviteRepo.EnsureIndexes, trashRepo.EnsureIndexes}
+
+	searchService := repositories.NewMongoSearchService(db.Collection("tasks"))
+	if config.SearchBackend == domain.SearchBackendElasticsearch {
+		searchService = infrastructure.NewElasticsearchSearchService(config.ElasticsearchURL, config.ElasticsearchIndex)
+	}
+
+	// webhooks and broker events go out in the background, the command would exit before they are sent
+	taskEventHandlers := []domain.TaskEventHandler{usecases.NewAuditLogTaskEventHandler(auditLogRepo, logger),
+		usecases.NewTaskEventLogTaskEventHandler(repositories.NewTaskEventLogRepository(db.Collection("task_event_log")), config.TaskEventRetention, logger),
+		usecases.NewSearchIndexTaskEventHandler(searchService, logger)}       // consumers of purged tasks
+	adminUC := usecases.NewAdminUseCase(userRepo, taskRepo, trashRepo, tokenRepo, resetRepo,
+		infrastructure.NewPasswordService(config), infrastructure.NewAuditSink(config, logger), searchService, taskEventHandlers...)
+	unitOfWork := repositories.NewDirectUnitOfWork()
+	if supported, err := repositories.TransactionsSupported(ctx, client); err == nil && supported {
+		unitOfWork = repositories.NewMongoUnitOfWork(client)        // imports appear as a whole
+	}
+	workspaceUC := usecases.NewWorkspaceUseCase(taskRepo, userRepo, labelRepo, projectRepo, auditLogRepo, unitOfWork, logger)
+	integrityUC := usecases.NewIntegrityUseCase(repositories.NewIntegrityRepository(db.Collection("tasks"), db.Collection("projects"), db.Collection("labels"), db.Collection("organizations"),
+		db.Collection("users"), db.Collection("personal_access_tokens"), db.Collection("sessions"), db.Collection("my_day")), auditLogRepo, logger, false)
+
+	switch command {
+	case "create-admin":
+		requireUsername(*username)
+		admin := domain.User{Username: *username, Email: *email, Password: readPassword(*password)}
+		if err := adminUC.CreateAdmin(ctx, &admin); err != nil {
+			fail(err)
+		}
+		fmt.Printf("created admin %s (%s)\n", admin.Username, admin.ID.Hex())
+
+	case "reset-password":
+		requireUsername(*username)
+		if err := adminUC.ResetPassword(ctx, *username, readPassword(*password)); err != nil {
+			fail(err)
+		}
+		fmt.Printf("password of %s reset\n", *username)
+
+	case "revoke-tokens":
+		requireUsername(*username)
+		revoked, err := adminUC.RevokeTokens(ctx, *username)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("revoked %d personal access tokens of %s\n", revoked, *username)
+
+	case "reindex":
+		if _, err := migrations.Run(ctx, nil, indexes, nil, logger); err != nil {
+			fail(err)
+		}
+		fmt.Println("indexes up to date")
+		if config.SearchBackend == domain.SearchBackendElasticsearch {        // the mongodb text index follows every write
+			indexed, err := adminUC.IndexTasks(ctx)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("%d tasks in the search index\n", indexed)
+		}
+
+	case "migrate":
+		applied, err := migrations.Run(ctx, repositories.NewMigrationRepository(db.Collection("schema_migrations")), indexes, migrations.Versioned(taskRepo, userRepo), logger)
+		for _, migration := range applied {
+			fmt.Printf("applied migration %d (%s), %d documents changed\n", migration.Version, migration.Name, migration.Documents)
+		}
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("database up to date, %d migrations applied\n", len(applied))
+
+	case "check-integrity":
+		dryRun := !*repair
+		request := domain.IntegrityRequest{DryRun: &dryRun}
+		for _, check := range strings.Split(*checks, ",") {
+			if check = strings.TrimSpace(check); check != "" {
+				request.Checks = append(request.Checks, check)
+			}
+		}
+		report, err := integrityUC.CheckIntegrity(ctx, request)
+		if err != nil {
+			fail(err)
+		}
+		for _, result := range report.Checks {
+			fmt.Printf("%-28s found %d, repaired %d\n", result.Check, result.Found, result.Repaired)
+			for _, issue := range result.Issues {
+				fmt.Printf("  %s %s: %s\n", issue.Collection, issue.DocumentID, issue.Reference)
+			}
+		}
+		if report.DryRun && report.Found > 0 {
+			fmt.Println("dry run, run again with -repair to fix what can be repaired")
+		}
+
+	case "list-tasks":
+		tasks, err := adminUC.FindTasks(ctx, taskFilter(flags, *status, *dueBefore, *updatedBefore, *tenant))
+		if err != nil {
+			fail(err)
+		}
+		for _, task := range tasks {
+			fmt.Printf("%s  %-11s  %s  %s\n", task.ID.Hex(), task.Status, task.DueDate.Format("2006-01-02"), task.Title)
+		}
+		fmt.Printf("%d tasks\n", len(tasks))
+
+	case "purge-tasks":
+		filter := taskFilter(flags, *status, *dueBefore, *updatedBefore, *tenant)
+		if !filter.Narrowed() {
+			fail(domain.ErrTaskPurgeUnfiltered)
+		}
+		if !*confirmed {
+			tasks, err := adminUC.FindTasks(ctx, filter)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("%d tasks match (their subtasks are deleted too), run again with -yes to move them to the trash\n", len(tasks))
+			return
+		}
+		purged, err := adminUC.PurgeTasks(ctx, filter)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("moved %d tasks to the trash\n", purged)
+
+	case "export-workspace":
+		requireFile(*file)
+		bundle, err := workspaceUC.ExportWorkspace(ctx, domain.WorkspaceExportOptions{TenantID: *tenant, IncludeUsers: *withUsers})
+		if err != nil {
+			fail(err)
+		}
+		data, err := json.Marshal(bundle)
+		if err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(*file, data, 0600); err != nil {        // may hold password hashes
+			fail(err)
+		}
+		fmt.Printf("exported %d users, %d labels, %d projects and %d tasks to %s\n", len(bundle.Users), len(bundle.Labels), len(bundle.Projects), len(bundle.Tasks), *file)
+
+	case "import-workspace":
+		requireFile(*file)
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			fail(err)
+		}
+		var bundle domain.WorkspaceBundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			fail(fmt.Errorf("invalid workspace bundle: %w", err))
+		}
+		opts := domain.WorkspaceImportOptions{TenantID: *tenant, IncludeUsers: *withUsers}
+		if *owner != "" {
+			user, err := userRepo.GetByUsername(ctx, *owner)
+			if err != nil {
+				fail(err)
+			}
+			if user.TenantID != *tenant {
+				fail(domain.ErrWorkspaceUserConflict)
+			}
+			opts.OwnerID = user.ID.Hex()
+		}
+		report, err := workspaceUC.ImportWorkspace(ctx, &bundle, opts)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("created %d users (%d existed), %d labels (%d existed), %d projects and %d tasks, dropped %d unknown project members\n",
+			report.UsersCreated, report.UsersMatched, report.LabelsCreated, report.LabelsMatched, report.ProjectsCreated, report.TasksCreated, report.MembersDropped)
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// exit when a command needs a username and none was given
+func requireUsername(username string) {
+	if username == "" {
+		fail(fmt.Errorf("-username is required"))
+	}
+}
+
+// exit when a workspace command has no bundle path
+func requireFile(file string) {
+	if file == "" {
+		fail(fmt.Errorf("-file is required"))
+	}
+}
+
+// password from the flag, otherwise the first line of standard input (keeps it out of shell history)
+func readPassword(password string) string {
+	if password != "" {
+		return password
+	}
+	fmt.Fprint(os.Stderr, "password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fail(fmt.Errorf("no password given"))
+	}
+	return strings.TrimRight(line, "\r\n")
+}
+
+// task filter from the command line flags (-tenant limits the organization only when given)
+func taskFilter(flags *flag.FlagSet, status string, dueBefore string, updatedBefore string, tenant string) domain.TaskPurgeFilter {
+	filter := domain.TaskPurgeFilter{Status: status, DueBefore: parseDate("due-before", dueBefore), UpdatedBefore: parseDate("updated-before", updatedBefore)}
+	flags.Visit(func(set *flag.Flag) {
+		if set.Name == "tenant" {
+			filter.TenantID = &tenant
+		}
+	})
+	return filter
+}
+
+// date flag value (nil when empty), a day means its start in utc
+func parseDate(name string, raw string) *time.Time {
+	if raw == "" {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return &parsed
+		}
+	}
+	fail(fmt.Errorf("-%s must be YYYY-MM-DD or RFC3339", name))
+	return nil
+}
+
+// print error and exit
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}